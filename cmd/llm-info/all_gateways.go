@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	errhandler "github.com/armaniacs/llm-info/internal/error"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/numfmt"
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/internal/ui"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// allGatewaysReport は--all-gatewaysのJSON出力
+// 取得できたゲートウェイと失敗したゲートウェイを分けて出力し、どのゲートウェイが失敗したかを判別できるようにする
+type allGatewaysReport struct {
+	Gateways []gatewayModels     `json:"gateways"`
+	Errors   []gatewayFetchError `json:"errors"`
+}
+
+// gatewayModels は1つのゲートウェイから取得したモデル一覧
+type gatewayModels struct {
+	Gateway    string        `json:"gateway"`
+	URL        string        `json:"url"`
+	ModelCount int           `json:"model_count"`
+	Models     []model.Model `json:"models"`
+}
+
+// gatewayFetchError は1つのゲートウェイの取得失敗の詳細
+type gatewayFetchError struct {
+	Gateway string `json:"gateway"`
+	URL     string `json:"url,omitempty"`
+	Code    string `json:"code"`            // connection_refused、authentication_failedなどのエラーコード
+	Message string `json:"message"`         // エラーの説明
+	Cause   string `json:"cause,omitempty"` // 元のエラー
+}
+
+// gatewayFetch は1つのゲートウェイの取得結果
+type gatewayFetch struct {
+	resolved *internalConfig.ResolvedConfig
+	models   []model.Model
+	latency  time.Duration
+	err      *errhandler.AppError
+}
+
+// listAllGateways は設定ファイルのすべてのゲートウェイからモデル一覧を並行して取得して表示する
+// 一部のゲートウェイが失敗しても残りの結果を表示し、すべて失敗した場合のみエラーを返す
+// 失敗したゲートウェイのエラーはerrorHandlerで同じ内容ごとにまとめて表示する
+func listAllGateways(configManager *internalConfig.Manager, cliArgs *internalConfig.CLIArgs, strictParse, pinnedOnly bool, errorHandler *errhandler.Handler) error {
+	names := configManager.ListGateways()
+	if len(names) == 0 {
+		return errhandler.CreateUserError("invalid_argument", "--all-gateways", fmt.Errorf("no gateways configured; add one with llm-info init"))
+	}
+
+	// ゲートウェイごとに設定を解決する（フィルタやソートなどの指定は共通）
+	fetches := make([]gatewayFetch, len(names))
+	for i, name := range names {
+		args := *cliArgs
+		args.Gateway = name
+		resolved, err := configManager.ResolveConfig(&args)
+		if err != nil {
+			resolved = &internalConfig.ResolvedConfig{Gateway: &config.GatewayConfig{Name: name}}
+			fetches[i].err = errhandler.CreateConfigError("missing_required_field", name, err)
+		}
+		fetches[i].resolved = resolved
+	}
+
+	// 出力形式・フィルタ・ソートは全ゲートウェイで共通のため、設定を解決できた最初のゲートウェイのものを使う
+	base := &internalConfig.ResolvedConfig{OutputFormat: cliArgs.OutputFormat, NumberFormat: cliArgs.NumberFormat, Filter: cliArgs.Filter, SortBy: cliArgs.SortBy}
+	for _, f := range fetches {
+		if f.err == nil {
+			base = f.resolved
+			break
+		}
+	}
+
+	format, _ := internalConfig.SplitOutputFormat(base.OutputFormat)
+	if format != "table" && format != "json" {
+		return errhandler.CreateUserError("invalid_argument", "--format", fmt.Errorf("--all-gateways supports table and json output, got %s", format))
+	}
+	displayFormat, err := numfmt.FromEnv(base.NumberFormat)
+	if err != nil {
+		return errhandler.CreateUserError("invalid_argument", "--number-format", err)
+	}
+	if pinnedOnly {
+		base.Filter = withPinnedFilter(base.Filter)
+	}
+	var filterCriteria *ui.FilterCriteria
+	if filter := base.Filter; filter != "" {
+		filterCriteria, err = ui.ParseFilterString(filter)
+		if err != nil {
+			return errhandler.CreateUserError("invalid_filter_syntax", filter, err)
+		}
+	}
+	var sortCriteria *ui.SortCriteria
+	if sortBy := base.SortBy; sortBy != "" {
+		sortCriteria, err = ui.ParseSortString(sortBy)
+		if err != nil {
+			return errhandler.CreateUserError("invalid_sort_field", sortBy, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := range fetches {
+		if fetches[i].err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(f *gatewayFetch) {
+			defer wg.Done()
+			f.models, f.latency, f.err = fetchGatewayCatalog(configManager, f.resolved, strictParse)
+			if f.err != nil {
+				return
+			}
+			if filterCriteria != nil {
+				f.models = ui.Filter(f.models, filterCriteria)
+			}
+			if sortCriteria != nil {
+				ui.Sort(f.models, sortCriteria)
+			}
+			runFetchHook(f.resolved, f.models)
+		}(&fetches[i])
+	}
+	wg.Wait()
+
+	statsRecorder := newStatsRecorder(configManager)
+	statsRecorder.RecordCommand("list")
+
+	report := allGatewaysReport{Gateways: []gatewayModels{}, Errors: []gatewayFetchError{}}
+	for _, f := range fetches {
+		gw := f.resolved.Gateway
+		if f.err != nil {
+			if f.latency > 0 {
+				statsRecorder.RecordFetch(statsGatewayName(f.resolved), f.latency, f.err)
+			}
+			report.Errors = append(report.Errors, newGatewayFetchError(gw.Name, gw.URL, f.err))
+			continue
+		}
+		models := f.models
+		if models == nil {
+			models = []model.Model{}
+		}
+		statsRecorder.RecordFetch(statsGatewayName(f.resolved), f.latency, nil)
+		report.Gateways = append(report.Gateways, gatewayModels{Gateway: gw.Name, URL: gw.URL, ModelCount: len(models), Models: models})
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return errhandler.CreateSystemError("unexpected_error", "JSON rendering", err)
+		}
+	} else {
+		printed := 0
+		for _, f := range fetches {
+			if f.err != nil {
+				continue
+			}
+			if printed > 0 {
+				fmt.Println()
+			}
+			printed++
+			fmt.Printf("=== %s (%s): %d models ===\n", f.resolved.Gateway.Name, f.resolved.Gateway.URL, len(f.models))
+			renderOptions := &ui.RenderOptions{
+				Filter:       f.resolved.Filter,
+				Sort:         f.resolved.SortBy,
+				Columns:      f.resolved.Columns,
+				NumberFormat: displayFormat,
+				TableStyle:   base.TableStyle,
+			}
+			if len(f.models) > 0 {
+				if err := ui.RenderTableWithOptions(f.models, renderOptions); err != nil {
+					return errhandler.CreateSystemError("unexpected_error", "table rendering", err)
+				}
+			}
+		}
+	}
+
+	// 同じエラーはまとめて1回だけ表示する
+	aggregator := errhandler.NewAggregator()
+	for _, f := range fetches {
+		if f.err != nil {
+			aggregator.Add(f.resolved.Gateway.Name, f.err)
+		}
+	}
+	errorHandler.HandleAggregate(aggregator)
+	if len(report.Gateways) == 0 {
+		return fmt.Errorf("could not fetch models from any of %d gateway(s)", len(names))
+	}
+	if len(report.Errors) > 0 {
+		ui.Warnf("⚠️  Fetched %d of %d gateway(s); %d failed.", len(report.Gateways), len(names), len(report.Errors))
+	}
+	return nil
+}
+
+// fetchGatewayCatalog は1つのゲートウェイのモデル一覧を取得し、一覧表示と同じ変換を行う
+func fetchGatewayCatalog(configManager *internalConfig.Manager, resolved *internalConfig.ResolvedConfig, strictParse bool) ([]model.Model, time.Duration, *errhandler.AppError) {
+	if err := validateURL(resolved.Gateway.URL); err != nil {
+		return nil, 0, errhandler.CreateUserError("invalid_argument", resolved.Gateway.URL, err)
+	}
+
+	cfg := internalConfig.New(resolved.Gateway.URL, resolved.Gateway.APIKey, resolved.Gateway.Timeout)
+	cfg.Timeouts = resolved.Gateway.Timeouts
+	cfg.Proxy = resolved.Gateway.Proxy
+	client := api.NewClient(cfg)
+
+	start := time.Now()
+	response, err := client.FetchModelsWithFallback()
+	latency := time.Since(start)
+	if err != nil {
+		return nil, latency, errhandler.WrapErrorWithDetection(err, resolved.Gateway.URL)
+	}
+	saveCatalogCache(configManager, resolved, response.Models, false)
+
+	if strictParse {
+		if appErr := strictParseError(response.Models, resolved.Gateway.URL); appErr != nil {
+			return nil, latency, appErr
+		}
+	}
+
+	models, err := dedupeModels(model.FromAPIResponse(response.Models), resolved)
+	if err != nil {
+		var appErr *errhandler.AppError
+		if !errhandler.AsAppError(err, &appErr) {
+			appErr = errhandler.CreateConfigError("invalid_config_format", "normalization", err)
+		}
+		return nil, latency, appErr
+	}
+	model.TagWildcards(models)
+	model.ApplyPins(models, configManager.GetPinnedModels())
+	applyNotes(configManager, models)
+	if resolved.Gateway.IsLiteLLM() {
+		fetchLiteLLMStatus(client, models, false)
+		if resolved.Columns == "" {
+			resolved.Columns = ui.LiteLLMColumns
+		}
+	}
+	if usesMeasurements(resolved) {
+		applyMeasurements(configManager, resolved, models)
+	}
+	return models, latency, nil
+}
+
+// newGatewayFetchError は取得失敗をJSON出力用の形式に変換する（APIキーは伏せる）
+func newGatewayFetchError(gateway, url string, err *errhandler.AppError) gatewayFetchError {
+	e := gatewayFetchError{
+		Gateway: gateway,
+		URL:     url,
+		Code:    err.Code,
+		Message: redact.String(err.Message),
+	}
+	if err.OriginalErr != nil {
+		e.Cause = redact.String(err.OriginalErr.Error())
+	}
+	return e
+}
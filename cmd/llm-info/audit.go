@@ -0,0 +1,432 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/policy"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["audit"] = auditCommand
+}
+
+// auditReport はaudit duplicatesのJSON出力
+type auditReport struct {
+	Gateways    []string               `json:"gateways"`
+	Conflicting int                    `json:"conflicting"`
+	Shared      int                    `json:"shared"` // 複数のゲートウェイに存在するモデルIDの数
+	Duplicates  []model.DuplicateModel `json:"duplicates"`
+}
+
+// policyReport はaudit policyのJSON出力
+type policyReport struct {
+	Policy     string                   `json:"policy"`
+	Checked    int                      `json:"checked"` // 検査したモデルの数
+	Compliant  bool                     `json:"compliant"`
+	Gateways   []string                 `json:"gateways"`
+	Violations []policyGatewayViolation `json:"violations"`
+}
+
+// policyGatewayViolation はゲートウェイ名付きのポリシー違反
+type policyGatewayViolation struct {
+	Gateway string `json:"gateway"`
+	policy.Violation
+}
+
+// auditCommand はゲートウェイの設定を横断して点検する
+func auditCommand(args []string) error {
+	if len(args) > 0 && args[0] == "duplicates" {
+		return auditDuplicatesCommand(args[1:])
+	}
+	if len(args) > 0 && args[0] == "policy" {
+		return auditPolicyCommand(args[1:])
+	}
+	showAuditHelp()
+	if len(args) > 0 && args[0] != "--help" && args[0] != "-help" && args[0] != "-h" {
+		return fmt.Errorf("unknown audit check: %s", args[0])
+	}
+	return nil
+}
+
+// auditDuplicatesCommand は複数のゲートウェイに存在し、属性が食い違うモデルIDを報告する
+func auditDuplicatesCommand(args []string) error {
+	auditCmd := flag.NewFlagSet("audit duplicates", flag.ExitOnError)
+	gateways := auditCmd.String("gateways", "", "Comma-separated gateway names to audit (default: all configured gateways)")
+	offline := auditCmd.Bool("offline", false, "Audit the cached model catalogs instead of fetching them")
+	normalize := auditCmd.Bool("normalize", false, "Match model IDs after normalization (provider prefixes, date suffixes, aliases)")
+	showAll := auditCmd.Bool("all", false, "Also list model IDs that are shared without conflicts")
+	failOnConflict := auditCmd.Bool("fail-on-conflict", false, "Exit with an error if any model ID has conflicting metadata")
+	outputFormat := auditCmd.String("format", "table", "Output format (table, json)")
+	timeout := auditCmd.Duration("timeout", 30*time.Second, "Request timeout for each gateway")
+	configFile := auditCmd.String("config", "", "Path to config file")
+	progressOpts := addProgressFlags(auditCmd)
+	showHelp := auditCmd.Bool("help", false, "Show help for audit duplicates command")
+
+	auditCmd.Parse(args)
+
+	if *showHelp {
+		showAuditHelp()
+		return nil
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+
+	var normalizer *model.Normalizer
+	if *normalize {
+		var normalization *config.NormalizationConfig
+		if cfg := configManager.GetNewConfig(); cfg != nil {
+			normalization = &cfg.Normalization
+		}
+		var err error
+		normalizer, err = model.NewNormalizer(normalization)
+		if err != nil {
+			return err
+		}
+	}
+
+	names := splitGatewayNames(*gateways)
+	var catalogs []model.GatewayCatalog
+	var err error
+	if *offline {
+		catalogs, err = cachedGatewayCatalogs(configManager, names)
+	} else {
+		catalogs, err = fetchGatewayCatalogs(configManager, names, *timeout, progressOpts)
+	}
+	if err != nil {
+		return err
+	}
+	if len(catalogs) < 2 {
+		return fmt.Errorf("need at least two gateways to audit, got %d", len(catalogs))
+	}
+
+	report := auditReport{Duplicates: []model.DuplicateModel{}}
+	for _, catalog := range catalogs {
+		report.Gateways = append(report.Gateways, catalog.Gateway)
+	}
+	for _, duplicate := range model.FindDuplicates(catalogs, normalizer) {
+		report.Shared++
+		if duplicate.HasConflicts() {
+			report.Conflicting++
+		} else if !*showAll {
+			continue
+		}
+		report.Duplicates = append(report.Duplicates, duplicate)
+	}
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		printAuditReport(report)
+	}
+
+	if *failOnConflict && report.Conflicting > 0 {
+		return fmt.Errorf("%d model ID(s) have conflicting metadata across gateways", report.Conflicting)
+	}
+	return nil
+}
+
+// auditPolicyCommand はゲートウェイのモデルを許可・拒否リストとコスト上限のポリシーで検査する
+// 違反があればエラーを返し、CIのゲートとして使えるようにする
+func auditPolicyCommand(args []string) error {
+	auditCmd := flag.NewFlagSet("audit policy", flag.ExitOnError)
+	policyFile := auditCmd.String("policy", "", "Path to the policy file (YAML)")
+	gateways := auditCmd.String("gateways", "", "Comma-separated gateway names to audit (default: all configured gateways)")
+	offline := auditCmd.Bool("offline", false, "Audit the cached model catalogs instead of fetching them")
+	outputFormat := auditCmd.String("format", "table", "Output format (table, json)")
+	timeout := auditCmd.Duration("timeout", 30*time.Second, "Request timeout for each gateway")
+	configFile := auditCmd.String("config", "", "Path to config file")
+	progressOpts := addProgressFlags(auditCmd)
+	showHelp := auditCmd.Bool("help", false, "Show help for audit policy command")
+
+	auditCmd.Parse(args)
+
+	if *showHelp {
+		showAuditHelp()
+		return nil
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+	if *policyFile == "" {
+		return fmt.Errorf("--policy is required")
+	}
+	p, err := policy.Load(*policyFile)
+	if err != nil {
+		return err
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+
+	names := splitGatewayNames(*gateways)
+	var catalogs []model.GatewayCatalog
+	if *offline {
+		catalogs, err = cachedGatewayCatalogs(configManager, names)
+	} else {
+		catalogs, err = fetchGatewayCatalogs(configManager, names, *timeout, progressOpts)
+	}
+	if err != nil {
+		return err
+	}
+	if len(catalogs) == 0 {
+		return fmt.Errorf("no gateway could be audited")
+	}
+
+	report := policyReport{Policy: *policyFile, Violations: []policyGatewayViolation{}}
+	for _, catalog := range catalogs {
+		report.Gateways = append(report.Gateways, catalog.Gateway)
+		report.Checked += len(catalog.Models)
+		for _, violation := range p.Check(catalog.Models) {
+			report.Violations = append(report.Violations, policyGatewayViolation{Gateway: catalog.Gateway, Violation: violation})
+		}
+	}
+	report.Compliant = len(report.Violations) == 0
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		printPolicyReport(report)
+	}
+
+	if !report.Compliant {
+		return fmt.Errorf("%d policy violation(s) found", len(report.Violations))
+	}
+	return nil
+}
+
+// printPolicyReport はポリシー違反をゲートウェイごとに表示する
+func printPolicyReport(report policyReport) {
+	fmt.Printf("Audited %d models on %d gateway(s) against %s\n\n", report.Checked, len(report.Gateways), report.Policy)
+
+	gateway := ""
+	for _, violation := range report.Violations {
+		if violation.Gateway != gateway {
+			gateway = violation.Gateway
+			fmt.Printf("%s:\n", gateway)
+		}
+		fmt.Printf("  ❌ %s [%s] %s\n", violation.Model, violation.Rule, violation.Detail)
+	}
+	if len(report.Violations) > 0 {
+		fmt.Println()
+	}
+
+	if report.Compliant {
+		fmt.Println("✅ All models comply with the policy.")
+		return
+	}
+	models := make(map[string]bool)
+	for _, violation := range report.Violations {
+		models[violation.Gateway+"\x00"+violation.Model] = true
+	}
+	fmt.Printf("⚠️  %d violation(s) on %d model(s). Remove the models from the gateway or update the policy.\n", len(report.Violations), len(models))
+}
+
+// fetchGatewayCatalogs は指定したゲートウェイ（なければ設定済みの全ゲートウェイ）からモデル一覧を取得する
+// 取得できなかったゲートウェイは警告を出して除外する
+func fetchGatewayCatalogs(configManager *internalConfig.Manager, names []string, timeout time.Duration, progressOpts *progressFlags) ([]model.GatewayCatalog, error) {
+	var gateways []*config.GatewayConfig
+	if len(names) == 0 {
+		var err error
+		gateways, err = doctorGateways(configManager, "", "", "", timeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range names {
+		gw, err := configManager.GetGatewayConfig(name)
+		if err != nil {
+			return nil, err
+		}
+		gateways = append(gateways, gw)
+	}
+	for _, gw := range gateways {
+		if gw.Timeout == 0 {
+			gw.Timeout = timeout
+		}
+	}
+
+	progress := progressOpts.newProgress()
+	defer progress.Finish()
+
+	var catalogs []model.GatewayCatalog
+	for i, gw := range gateways {
+		progress.StartItem(gw.Name, i+1, len(gateways))
+		snapshot, err := exportSnapshot(gw)
+		if err != nil {
+			progress.Finish()
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", snapshot.Gateway, err)
+			progress.FinishItem("skipped")
+			continue
+		}
+		catalogs = append(catalogs, model.GatewayCatalog{Gateway: snapshot.Gateway, Models: snapshot.Models})
+		progress.FinishItem(fmt.Sprintf("%d models", len(snapshot.Models)))
+	}
+	return catalogs, nil
+}
+
+// cachedGatewayCatalogs はキャッシュ済みのモデル一覧を返す（namesが空ならすべて）
+func cachedGatewayCatalogs(configManager *internalConfig.Manager, names []string) ([]model.GatewayCatalog, error) {
+	catalog, err := catalogCache(configManager)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := catalog.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var catalogs []model.GatewayCatalog
+	for _, entry := range entries {
+		if len(names) > 0 && !wanted[entry.Gateway] {
+			continue
+		}
+		catalogs = append(catalogs, model.GatewayCatalog{Gateway: catalogName(entry), Models: model.FromAPIResponse(entry.Models)})
+	}
+	return catalogs, nil
+}
+
+// printAuditReport は対処すべき順に重複モデルの不一致を表示する
+func printAuditReport(report auditReport) {
+	fmt.Printf("Audited %d gateways: %s\n\n", len(report.Gateways), strings.Join(report.Gateways, ", "))
+
+	icons := map[string]string{
+		model.SeverityHigh:   "🔴",
+		model.SeverityMedium: "🟠",
+		model.SeverityLow:    "🟡",
+	}
+	n := 0
+	for _, duplicate := range report.Duplicates {
+		if !duplicate.HasConflicts() {
+			continue
+		}
+		n++
+		fmt.Printf("%d. %s %s [%s] on %s\n", n, icons[duplicate.Severity], duplicate.ID, duplicate.Severity, strings.Join(duplicate.Gateways, ", "))
+		for _, conflict := range duplicate.Conflicts {
+			fmt.Printf("   %s: %s\n", conflict.Field, model.FormatConflictValues(conflict))
+			fmt.Printf("     → %s\n", conflict.Remediation)
+		}
+		fmt.Println()
+	}
+
+	var consistent []string
+	for _, duplicate := range report.Duplicates {
+		if !duplicate.HasConflicts() {
+			consistent = append(consistent, duplicate.ID)
+		}
+	}
+	if len(consistent) > 0 {
+		fmt.Printf("Consistent across gateways: %s\n\n", strings.Join(consistent, ", "))
+	}
+
+	if report.Conflicting == 0 {
+		fmt.Printf("✅ No conflicts: %d model ID(s) are shared and their metadata agrees.\n", report.Shared)
+		return
+	}
+	fmt.Printf("⚠️  %d of %d shared model ID(s) have conflicting metadata. Fix them in the order above; token limit conflicts cause request failures depending on routing.\n",
+		report.Conflicting, report.Shared)
+}
+
+// showAuditHelp はauditコマンドのヘルプを表示する
+func showAuditHelp() {
+	fmt.Println(`llm-info audit - Audit model metadata across gateways
+
+USAGE:
+    llm-info audit duplicates [flags]
+    llm-info audit policy --policy FILE [flags]
+
+CHECKS:
+    duplicates           Model IDs served by several gateways with different
+                         max_tokens, prices or mode
+    policy               Models that violate an allow/deny list of providers,
+                         models and regions or a cost ceiling
+
+FLAGS:
+    --policy string      Policy file (YAML, audit policy only)
+    --gateways string    Comma-separated gateway names to audit
+                         (default: all configured gateways)
+    --offline            Audit the cached model catalogs instead of fetching them
+    --normalize          Match model IDs after normalization (provider prefixes,
+                         date suffixes, normalization.aliases)
+    --all                Also list model IDs that are shared without conflicts
+    --fail-on-conflict   Exit with an error if any model ID has conflicting metadata
+                         (audit duplicates only; audit policy always fails on
+                         violations)
+    --format string      Output format: table, json (default: table)
+    --timeout duration   Request timeout for each gateway (default: 30s)
+    --config string      Path to config file
+    --quiet              Do not show progress
+    --help               Show help for audit command
+
+EXAMPLES:
+    # Compare every configured gateway
+    llm-info audit duplicates
+
+    # Two gateways, matching openai/gpt-4o with gpt-4o-2024-08-06
+    llm-info audit duplicates --gateways production,staging --normalize
+
+    # Fail a CI job when the catalogs disagree
+    llm-info audit duplicates --offline --fail-on-conflict --format json
+
+    # Gate a CI job on the model policy
+    llm-info audit policy --policy policy.yaml --gateways production
+
+DESCRIPTION:
+    Fetches the model list of each gateway and reports model IDs that exist in
+    more than one of them with different values. Findings are ordered by what
+    to fix first:
+
+      high     max_tokens, max_input_tokens or max_output_tokens differ; a
+               request that fits one gateway fails when routed to another
+      medium   input_cost or output_cost differ; cost estimates and
+               cost-based routing depend on which gateway serves the request
+      low      mode differs
+
+    Within a severity, larger differences come first. A gateway that does
+    not report a value is not counted as a conflict. Each finding names the
+    gateways involved and a suggested remediation. Gateways that cannot be
+    reached are skipped with a warning.
+
+POLICY FILE:
+    providers:
+      allow: [openai, anthropic, bedrock]
+    models:
+      deny: ["*-preview", "*realtime*"]
+    regions:
+      allow: [us-*, eu-*]
+      allow_unknown: true
+    cost:
+      max_input_cost_per_token: 0.00001
+      max_output_cost_per_token: 0.00003
+
+    Patterns are case-insensitive globs (* matches any text including "/",
+    ? matches one character). A deny match wins over allow. When an allow
+    list is set, a model whose value is unknown violates it unless
+    allow_unknown is true. The provider comes from the model metadata or the
+    prefix of the model ID; the region from region, aws_region_name or
+    vertex_location. Models without a price are not checked against cost
+    ceilings. Unknown keys in the policy file are rejected. audit policy
+    exits non-zero when any model violates the policy.`)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// partialFailureError はすべての結果を出力したうえで、一部のモデルが失敗したことを表す
+// 終了コードは1にするが、成功したモデルの結果を失わないよう--outputのファイルには書き出す
+type partialFailureError struct {
+	err error
+}
+
+func (e *partialFailureError) Error() string {
+	return e.err.Error()
+}
+
+func (e *partialFailureError) Unwrap() error {
+	return e.err
+}
+
+// batchFailureError は--continue-on-errorで記録した失敗をまとめたエラーを返す
+// 一部のモデルが失敗した実行をCIで検出できるよう、結果を出力した後に終了コード1で終了させる
+func batchFailureError(failed []string, total int) error {
+	return &partialFailureError{err: fmt.Errorf("%d of %d model(s) failed: %s", len(failed), total, strings.Join(failed, ", "))}
+}
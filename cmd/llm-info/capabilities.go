@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/storage"
+)
+
+// saveCapabilities は前回の互換性結果を読み込み、updateで一部を更新して保存する
+// probe-roles、probe-params、probe-overhead、probe-caching、probe-continuationの結果を1つのcapabilities結果にまとめるために使う
+func saveCapabilities(configManager *internalConfig.Manager, resolved *internalConfig.ResolvedConfig, model string, update func(*probe.Capabilities)) (string, error) {
+	probeConfig := configManager.GetProbeConfig()
+	resultStorage, err := storage.NewResultStorageWithOptions(probeConfig.Result.Dir, probeConfig.Result.StorageOptions())
+	if err != nil {
+		return "", fmt.Errorf("failed to create result storage: %w", err)
+	}
+
+	provider := detectProvider(configManager, resolved, model, nil)
+	capabilities := probe.NewCapabilities(model, resolved.Gateway.Name)
+	if saved, err := resultStorage.LoadCapabilitiesResult(provider, model); err == nil {
+		if previous, err := probe.DecodeCapabilities(saved); err == nil {
+			capabilities.Roles = previous.Roles
+			capabilities.RolesProbedAt = previous.RolesProbedAt
+			capabilities.Parameters = previous.Parameters
+			capabilities.ParametersProbedAt = previous.ParametersProbedAt
+			capabilities.PromptOverhead = previous.PromptOverhead
+			capabilities.PromptCaching = previous.PromptCaching
+			capabilities.Continuation = previous.Continuation
+		}
+	}
+
+	update(capabilities)
+
+	if err := resultStorage.SaveCapabilitiesResult(provider, model, capabilities); err != nil {
+		return "", fmt.Errorf("failed to save result: %w", err)
+	}
+	return resultStorage.BaseDir(), nil
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/readonly"
+	"github.com/armaniacs/llm-info/internal/ui"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["chat"] = chatCommand
+}
+
+// chatCommand はゲートウェイのモデルにメッセージを送り、応答を表示する
+// メッセージを省略した場合は標準入力から読み込み、端末では対話的にやり取りする
+func chatCommand(args []string) error {
+	chatCmd := flag.NewFlagSet("chat", flag.ExitOnError)
+	model := chatCmd.String("model", "", "Target model ID (default: default_model of the gateway)")
+	baseURL := chatCmd.String("url", "", "Base URL of the LLM gateway")
+	apiKey := chatCmd.String("api-key", "", "API key for authentication")
+	gateway := chatCmd.String("gateway", "", "Gateway name to use from config")
+	timeout := chatCmd.Duration("timeout", 30*time.Second, "Request timeout, overrides timeouts.probe (default: 30s)")
+	maxTokens := chatCmd.Int("max-tokens", 1024, "Maximum tokens in each reply")
+	system := chatCmd.String("system", "", "System prompt")
+	showUsage := chatCmd.Bool("usage", false, "Show token usage of each reply")
+	configFile := chatCmd.String("config", "", "Path to config file")
+	showHelp := chatCmd.Bool("help", false, "Show help for chat command")
+
+	chatCmd.Parse(args)
+
+	if *showHelp {
+		showChatHelp()
+		return nil
+	}
+	if *maxTokens <= 0 {
+		return fmt.Errorf("--max-tokens must be positive: %d", *maxTokens)
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	if err := readonly.Check("chat"); err != nil {
+		return err
+	}
+	resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+		URL:          *baseURL,
+		APIKey:       *apiKey,
+		Timeout:      *timeout,
+		Gateway:      *gateway,
+		OutputFormat: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	// --model省略時はゲートウェイのdefault_modelを使う
+	*model = modelOrDefault(*model, resolved)
+	if *model == "" {
+		fmt.Fprintf(os.Stderr, "Error: --model is required (or set default_model for the gateway in the config file)\n\n")
+		showChatHelp()
+		os.Exit(1)
+	}
+
+	session := &chatSession{
+		client:        api.NewProbeClient(newProbeClientConfig(resolved, chatCmd)),
+		configManager: configManager,
+		resolved:      resolved,
+		flags:         chatCmd,
+		model:         *model,
+		maxTokens:     *maxTokens,
+		showUsage:     *showUsage,
+	}
+	if *system != "" {
+		session.messages = append(session.messages, api.Message{Role: "system", Content: *system})
+	}
+
+	// 引数のメッセージを1回だけ送る
+	if chatCmd.NArg() > 0 {
+		return session.send(strings.Join(chatCmd.Args(), " "))
+	}
+
+	// パイプやファイルからの入力は全体を1つのメッセージとして送る
+	if !isTerminal(os.Stdin) {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read message from stdin: %w", err)
+		}
+		message := strings.TrimSpace(string(data))
+		if message == "" {
+			return fmt.Errorf("no message given (pass it as an argument or on stdin)")
+		}
+		return session.send(message)
+	}
+
+	return session.interact(os.Stdin)
+}
+
+// chatSession は会話の履歴を保持してメッセージを送る
+type chatSession struct {
+	client        *api.ProbeClient
+	configManager *internalConfig.Manager
+	resolved      *internalConfig.ResolvedConfig // 会話に使っているゲートウェイ（フェイルオーバー後は応答したゲートウェイ）
+	flags         *flag.FlagSet
+	model         string
+	maxTokens     int
+	showUsage     bool
+	messages      []api.Message
+}
+
+// send はメッセージを送り、応答を表示して履歴に加える
+// ゲートウェイが停止している場合はfailoverのゲートウェイに送り、以降の会話もそのゲートウェイで続ける
+func (s *chatSession) send(content string) error {
+	messages := append(s.messages, api.Message{Role: "user", Content: content})
+
+	var resp *api.ProbeResponse
+	served, failover, err := runWithFailover(s.configManager, s.resolved.Gateway, func(gw *config.GatewayConfig) error {
+		client := s.client
+		if gw != s.resolved.Gateway {
+			client = s.clientFor(gw)
+		}
+		r, err := client.ProbeMessages(s.model, messages, s.maxTokens)
+		if err == nil {
+			resp = r
+			s.client = client
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to chat with %s: %w", s.model, err)
+	}
+	if failover != nil {
+		ui.DisplayFailover(failover)
+		s.resolved.Gateway = served
+	}
+	if len(resp.Choices) == 0 {
+		return fmt.Errorf("no reply from %s", s.model)
+	}
+
+	reply := resp.Choices[0].Message
+	fmt.Println(reply.Content)
+	if resp.Choices[0].FinishReason == "length" {
+		fmt.Fprintf(os.Stderr, "(reply truncated at --max-tokens %d)\n", s.maxTokens)
+	}
+	if s.showUsage && resp.Usage != nil {
+		fmt.Fprintf(os.Stderr, "(tokens: %d prompt, %d completion)\n", resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+	}
+
+	s.messages = append(messages, api.Message{Role: "assistant", Content: reply.Content})
+	return nil
+}
+
+// clientFor はフェイルオーバー先のゲートウェイに送るクライアントを作成する（--timeoutなどの指定は引き継ぐ）
+func (s *chatSession) clientFor(gw *config.GatewayConfig) *api.ProbeClient {
+	resolved := *s.resolved
+	resolved.Gateway = gw
+	return api.NewProbeClient(newProbeClientConfig(&resolved, s.flags))
+}
+
+// interact は端末から1行ずつメッセージを読み込んで会話する
+// 空行は無視し、EOFか/exitで終了する。失敗したメッセージは履歴に残さない
+func (s *chatSession) interact(in io.Reader) error {
+	fmt.Fprintf(os.Stderr, "Chatting with %s (Ctrl-D or /exit to quit)\n", s.model)
+
+	reader := bufio.NewReader(in)
+	for {
+		fmt.Fprint(os.Stderr, "> ")
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read message: %w", err)
+		}
+
+		message := strings.TrimSpace(line)
+		if message == "/exit" {
+			return nil
+		}
+		if message != "" {
+			if sendErr := s.send(message); sendErr != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", sendErr)
+			}
+		}
+
+		if err == io.EOF {
+			fmt.Fprintln(os.Stderr)
+			return nil
+		}
+	}
+}
+
+// showChatHelp はchatコマンドのヘルプを表示する
+func showChatHelp() {
+	fmt.Println(`llm-info chat - Send messages to a model through the gateway
+
+USAGE:
+    llm-info chat [flags] [MESSAGE...]
+
+With MESSAGE, sends it once and prints the reply. Without MESSAGE, reads
+the message from stdin, or starts an interactive conversation when stdin
+is a terminal (Ctrl-D or /exit to quit).
+
+FLAGS:
+    --gateway string             Gateway name to use from config
+    --model string               Target model ID (default: default_model of the gateway)
+    --max-tokens int             Maximum tokens in each reply (default: 1024)
+    --system string              System prompt
+    --usage                      Show token usage of each reply on stderr
+    --url string                 Base URL of the LLM gateway
+    --api-key string             API key for authentication
+    --timeout duration           Request timeout (default: timeouts.probe, then 30s)
+    --config string              Path to config file
+    --help                       Show help for chat command
+
+EXAMPLES:
+    # Ask the default_model of a gateway
+    llm-info chat --gateway production "What is your knowledge cutoff?"
+
+    # Interactive conversation
+    llm-info chat --gateway production
+
+    # Pipe a prompt to a specific model
+    echo "Summarize RFC 2119 in one line" | llm-info chat --model gpt-4o-mini`)
+}
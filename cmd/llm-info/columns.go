@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/armaniacs/llm-info/internal/cache"
+	"github.com/armaniacs/llm-info/internal/export"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/ui"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["columns"] = columnsCommand
+}
+
+// exportColumnNames は表示カラムに対応するexportの列名
+// 探索結果のカラムはexportに含まれないため対応がない
+var exportColumnNames = map[string]string{
+	"name":       "model",
+	"max_tokens": "max_tokens",
+	"mode":       "mode",
+	"input_cost": "input_cost",
+	"variants":   "variants",
+	"health":     export.MetaColumnName(model.MetaDeploymentHealth),
+	"group":      export.MetaColumnName(model.MetaGroupProviders),
+	"meta.<key>": "meta_<key>",
+}
+
+// columnJSON はcolumnsコマンドのJSON出力の1件
+type columnJSON struct {
+	ui.ColumnInfo
+	Export   string   `json:"export,omitempty"`
+	Gateways []string `json:"gateways,omitempty"` // meta.<key>の値を返したゲートウェイ
+}
+
+// columnsCommand は--columnsで指定できるカラムと値の取得元を一覧表示する
+func columnsCommand(args []string) error {
+	columnsCmd := flag.NewFlagSet("columns", flag.ExitOnError)
+	gateway := columnsCmd.String("gateway", "", "Only list metadata columns of this gateway's cached catalog")
+	outputFormat := columnsCmd.String("format", "table", "Output format (table, json)")
+	cacheDir := columnsCmd.String("cache-dir", "", "Directory where model catalogs are cached")
+	configFile := columnsCmd.String("config", "", "Path to config file")
+	showHelp := columnsCmd.Bool("help", false, "Show help for columns command")
+
+	columnsCmd.Parse(args)
+
+	if *showHelp {
+		showColumnsHelp()
+		return nil
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	var catalog *cache.CatalogCache
+	if *cacheDir != "" {
+		catalog = cache.NewCatalogCache(*cacheDir)
+	} else {
+		var err error
+		catalog, err = catalogCache(loadProbeConfigManager(*configFile))
+		if err != nil {
+			return err
+		}
+	}
+
+	// meta.<key>のカラムはキャッシュ済みのモデル一覧から集める（APIは呼ばない）
+	entries, err := catalog.LoadAll()
+	if err != nil {
+		return err
+	}
+	metaGateways := make(map[string][]string)
+	catalogs := 0
+	for _, entry := range entries {
+		if *gateway != "" && entry.Gateway != *gateway {
+			continue
+		}
+		catalogs++
+		for _, name := range ui.MetaColumns(model.FromAPIResponse(entry.Models)) {
+			metaGateways[name] = append(metaGateways[name], catalogName(entry))
+		}
+	}
+	metaNames := make([]string, 0, len(metaGateways))
+	for name := range metaGateways {
+		metaNames = append(metaNames, name)
+	}
+	sort.Strings(metaNames)
+
+	if *outputFormat == "json" {
+		var output []columnJSON
+		for _, column := range ui.AvailableColumns() {
+			output = append(output, columnJSON{ColumnInfo: column, Export: exportColumnNames[column.Name]})
+		}
+		for _, name := range metaNames {
+			key := strings.TrimPrefix(name, "meta.")
+			output = append(output, columnJSON{
+				ColumnInfo: ui.ColumnInfo{Name: name, Header: strings.ToUpper(key), Description: "Metadata field", Source: "gateway metadata"},
+				Export:     export.MetaColumnName(key),
+				Gateways:   metaGateways[name],
+			})
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COLUMN\tSOURCE\tEXPORT\tDESCRIPTION")
+	for _, column := range ui.AvailableColumns() {
+		exportName := exportColumnNames[column.Name]
+		if exportName == "" {
+			exportName = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", column.Name, column.Source, exportName, column.Description)
+	}
+	w.Flush()
+
+	fmt.Println()
+	switch {
+	case catalogs == 0:
+		fmt.Println("No cached model catalogs; run llm-info against a gateway to list its meta.<key> columns.")
+	case len(metaNames) == 0:
+		fmt.Printf("No metadata fields in %d cached catalog(s).\n", catalogs)
+	default:
+		fmt.Printf("Metadata columns in %d cached catalog(s):\n", catalogs)
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "COLUMN\tEXPORT\tGATEWAYS")
+		for _, name := range metaNames {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", name, export.MetaColumnName(strings.TrimPrefix(name, "meta.")), strings.Join(metaGateways[name], ", "))
+		}
+		w.Flush()
+	}
+
+	fmt.Println("\nUse --columns all to show every column that has values.")
+	return nil
+}
+
+// showColumnsHelp はcolumnsコマンドのヘルプを表示する
+func showColumnsHelp() {
+	fmt.Println(`llm-info columns - List the columns you can select with --columns
+
+USAGE:
+    llm-info columns [flags]
+
+FLAGS:
+    --gateway string      Only list metadata columns of this gateway's cached catalog
+    --format string       Output format: table, json (default: table)
+    --cache-dir string    Directory where model catalogs are cached (default: storage.cache_dir)
+    --config string       Path to config file
+    --help                Show help for columns command
+
+EXAMPLES:
+    # Every column and where its values come from
+    llm-info columns
+
+    # Metadata fields returned by one gateway
+    llm-info columns --gateway production
+
+    # Show everything in the listing
+    llm-info --columns all
+
+DESCRIPTION:
+    Lists the built-in columns of the model listing with the endpoint or
+    feature that populates them: /models and LiteLLM's /model/info, --dedupe,
+    /health and /model_group/info for type: litellm gateways, and saved probe
+    results for the measured_* columns. The EXPORT column is the name of the
+    same field in llm-info export output ("-" when it is not exported).
+
+    Gateways can return arbitrary metadata, selectable as meta.<key>. Those
+    keys are read from the model catalogs cached by earlier listings, so no
+    network requests are made; nested fields use dots, e.g.
+    meta.model_info.max_input_tokens.
+
+    --columns all shows the built-in columns that have a value for at least
+    one model, followed by every meta.<key> column, and llm-info export
+    writes all columns unless --columns selects some of them.`)
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/ghactions"
+	"github.com/armaniacs/llm-info/internal/notify"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/readonly"
+	"github.com/armaniacs/llm-info/internal/ui"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["probe-compare"] = probeCompareCommand
+}
+
+// probeCompareCommand は同一モデルを複数ゲートウェイで探索して比較する
+func probeCompareCommand(args []string) error {
+	compareCmd := flag.NewFlagSet("probe-compare", flag.ExitOnError)
+	model := compareCmd.String("model", "", "Target model ID (required)")
+	gateways := compareCmd.String("gateways", "", "Comma-separated gateway names to compare (required, at least 2)")
+	timeout := compareCmd.Duration("timeout", 0, "Request timeout override for all gateways")
+	configFile := compareCmd.String("config", "", "Path to config file")
+	contextOnly := compareCmd.Bool("context-only", false, "Compare only context window")
+	outputOnly := compareCmd.Bool("output-only", false, "Compare only max output tokens")
+	outputFormat := compareCmd.String("format", "table", "Output format (table, json)")
+	dryRun := compareCmd.Bool("dry-run", false, "Show execution plan without making actual API calls")
+	yes := addYesFlag(compareCmd)
+	noNotify := compareCmd.Bool("no-notify", false, "Disable completion notification")
+	githubSummary := compareCmd.Bool("github-summary", false, "Write a Markdown summary to $GITHUB_STEP_SUMMARY and emit annotations")
+	lockOpts := addLockFlags(compareCmd)
+	progressOpts := addProgressFlags(compareCmd)
+	showHelp := compareCmd.Bool("help", false, "Show help for probe-compare command")
+
+	compareCmd.Parse(args)
+
+	if *showHelp {
+		showProbeCompareHelp()
+		return nil
+	}
+
+	// 必須引数のチェック
+	gatewayNames := splitGatewayNames(*gateways)
+	if *model == "" || len(gatewayNames) < 2 {
+		fmt.Fprintf(os.Stderr, "Error: --model and at least two --gateways are required\n\n")
+		showProbeCompareHelp()
+		os.Exit(1)
+	}
+
+	if *contextOnly && *outputOnly {
+		fmt.Fprintf(os.Stderr, "Error: --context-only and --output-only cannot be used together\n\n")
+		showProbeCompareHelp()
+		os.Exit(1)
+	}
+
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	if err := readonly.Check("probe-compare"); err != nil {
+		return err
+	}
+
+	// 各ゲートウェイの設定を先に解決して、設定ミスを探索前に検出する
+	resolvedConfigs := make([]*internalConfig.ResolvedConfig, 0, len(gatewayNames))
+	for _, name := range gatewayNames {
+		resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+			Gateway:      name,
+			OutputFormat: "json",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to resolve gateway %q: %w", name, err)
+		}
+		if *timeout > 0 {
+			resolved.Gateway.Timeout = *timeout
+			resolved.Gateway.Timeouts.Probe = *timeout
+		}
+		resolvedConfigs = append(resolvedConfigs, resolved)
+	}
+
+	// 各ゲートウェイでの探索を模擬実行して呼び出し回数・トークン数・コストを見積もる（probeGatewayと同じくsearch戦略）
+	estimateCalls := func(assumptions dryRunAssumptions) ([]*probe.CallEstimate, error) {
+		var estimates []*probe.CallEstimate
+		if !*outputOnly {
+			strategy, err := buildStrategy(probe.StrategySearch, 0, "", false)
+			if err != nil {
+				return nil, err
+			}
+			estimate, err := estimateContextCalls(strategy, false, assumptions.contextWindow)
+			if err != nil {
+				return nil, err
+			}
+			estimates = append(estimates, estimate)
+		}
+		if !*contextOnly {
+			estimate, err := probe.EstimateMaxOutputCalls(assumptions.maxOutput)
+			if err != nil {
+				return nil, err
+			}
+			estimates = append(estimates, estimate)
+		}
+		return estimates, nil
+	}
+
+	if *dryRun {
+		showCompareExecutionPlan(*model, resolvedConfigs, *contextOnly, *outputOnly)
+		for _, resolved := range resolvedConfigs {
+			assumptions := resolveDryRunAssumptions(configManager, resolved, *model, 0)
+			estimates, err := estimateCalls(assumptions)
+			if err != nil {
+				return fmt.Errorf("failed to estimate probe calls: %w", err)
+			}
+			fmt.Printf("\nEstimate for %s:\n", resolved.Gateway.Name)
+			printDryRunEstimate(resolved, *model, assumptions, estimates)
+		}
+		dryRunComplete()
+		return nil
+	}
+
+	// 全ゲートウェイの見積もりコストの合計がspend_confirm_thresholdを超える場合は実行前に確認する
+	if err := confirmCompareSpend(configManager, resolvedConfigs, *model, *yes, estimateCalls); err != nil {
+		return err
+	}
+
+	progress := progressOpts.newProgress()
+	defer progress.Finish()
+
+	var comparisons []probe.GatewayComparison
+	for i, resolved := range resolvedConfigs {
+		progress.StartItem(resolved.Gateway.Name, i+1, len(resolvedConfigs))
+
+		report, err := probeGatewayLocked(configManager, *model, resolved, *contextOnly, *outputOnly, lockOpts, progress)
+		comparison := probe.NewGatewayComparison(resolved.Gateway.Name, ui.MaskURL(resolved.Gateway.URL), report)
+		if err != nil {
+			comparison.Error = err.Error()
+		}
+		if comparison.Error != "" {
+			progress.Finish()
+			fmt.Fprintf(os.Stderr, "Warning: probing via %s failed: %s\n", resolved.Gateway.Name, comparison.Error)
+			progress.FinishItem("failed")
+		} else {
+			progress.FinishItem("ok")
+		}
+		comparisons = append(comparisons, comparison)
+	}
+	progress.Finish()
+
+	report := probe.NewComparisonReport(*model, comparisons)
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		formatter := ui.NewTableFormatter()
+		fmt.Print(formatter.FormatComparison(report))
+	}
+
+	// GitHub Actions向けサマリー
+	if *githubSummary {
+		writeGitHubSummary(ghactions.ComparisonSummary(report))
+	}
+
+	// 完了通知（通知設定は最初のゲートウェイの解決結果を使用）
+	if !*noNotify {
+		sendComparisonNotification(notify.NewNotifier(resolvedConfigs[0].Notifications), report)
+	}
+
+	return nil
+}
+
+// probeGatewayLocked はゲートウェイのロックを取得してからprobeGatewayを実行する
+func probeGatewayLocked(configManager *internalConfig.Manager, model string, resolved *internalConfig.ResolvedConfig, contextOnly, outputOnly bool, flags *lockFlags, progress *ui.Progress) (*probe.Report, error) {
+	gatewayLock, err := acquireGatewayLock(configManager, resolved, "probe-compare", flags)
+	if err != nil {
+		return nil, err
+	}
+	defer gatewayLock.Release()
+	return probeGateway(model, resolved, contextOnly, outputOnly, progress)
+}
+
+// probeGateway は1つのゲートウェイでモデルを探索してレポートを返す
+// progressがnilでなければ試行ごとの進捗を表示する
+func probeGateway(model string, resolved *internalConfig.ResolvedConfig, contextOnly, outputOnly bool, progress *ui.Progress) (*probe.Report, error) {
+	client := api.NewProbeClient(&config.AppConfig{
+		BaseURL:       resolved.Gateway.URL,
+		APIKey:        resolved.Gateway.APIKey,
+		Timeout:       resolved.Gateway.ProbeTimeout(resolved.Gateway.Timeout),
+		Timeouts:      resolved.Gateway.Timeouts.Connection(),
+		Proxy:         resolved.Gateway.Proxy,
+		ProbeEndpoint: resolved.Gateway.ProbeEndpoint,
+	})
+
+	var contextResult *probe.ContextWindowResult
+	var outputResult *probe.MaxOutputResult
+	var errs []string
+
+	if !outputOnly {
+		prober := probe.NewContextWindowProbe(client)
+		if progress != nil {
+			progress.SetPhase("context window")
+			prober.SetVerboseLogger(progress)
+		}
+		result, err := prober.Probe(model, false)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("context window: %v", err))
+		} else {
+			contextResult = result
+		}
+	}
+
+	if !contextOnly {
+		prober := probe.NewMaxOutputTokensProbe(client)
+		if progress != nil {
+			progress.SetPhase("max output")
+			prober.SetVerboseLogger(progress)
+		}
+		result, err := prober.ProbeOutputTokens(model, false)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("max output: %v", err))
+		} else {
+			outputResult = result
+		}
+	}
+
+	report := buildProbeReport(model, resolved, contextResult, outputResult)
+	if len(errs) > 0 {
+		return report, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return report, nil
+}
+
+// sendComparisonNotification はゲートウェイ比較の完了を通知する
+func sendComparisonNotification(notifier *notify.Notifier, report *probe.ComparisonReport) {
+	if !notifier.Enabled(notify.EventProbeCompleted) {
+		return
+	}
+
+	var summary []string
+	for _, gw := range report.Gateways {
+		if gw.Error != "" {
+			summary = append(summary, fmt.Sprintf("%s: failed (%s)", gw.Gateway, gw.Error))
+			continue
+		}
+		summary = append(summary, fmt.Sprintf("%s: context window %d, max output %d, avg latency %dms",
+			gw.Gateway, gw.ContextWindow, gw.MaxOutput, gw.AvgLatencyMs))
+	}
+	for _, diff := range report.Differences {
+		summary = append(summary, fmt.Sprintf("difference in %s: %s=%d, %s=%d",
+			diff.Field, diff.Lowest, diff.Min, diff.Highest, diff.Max))
+	}
+
+	title := "Gateway comparison completed: no differences"
+	if report.HasDifferences() {
+		title = fmt.Sprintf("Gateway comparison completed: %d difference(s)", len(report.Differences))
+	}
+
+	payload := &notify.Payload{
+		Event:   notify.EventProbeCompleted,
+		Title:   title,
+		Summary: summary,
+		Model:   report.Model,
+		Details: report,
+	}
+	if err := notifier.Send(payload); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to send notification: %v\n", err)
+	}
+}
+
+// splitGatewayNames はカンマ区切りのゲートウェイ名を重複なしで分割する
+func splitGatewayNames(value string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// showCompareExecutionPlan は比較探索の実行計画を表示する
+func showCompareExecutionPlan(model string, resolvedConfigs []*internalConfig.ResolvedConfig, contextOnly, outputOnly bool) {
+	fmt.Printf("Gateway Comparison Execution Plan:\n")
+	fmt.Printf("  Model: %s\n", model)
+
+	mode := "Context Window and Max Output Tokens"
+	if contextOnly {
+		mode = "Context Window Only"
+	} else if outputOnly {
+		mode = "Max Output Tokens Only"
+	}
+	fmt.Printf("  Probe Mode: %s\n", mode)
+
+	fmt.Printf("\nGateways (probed sequentially):\n")
+	for i, resolved := range resolvedConfigs {
+		fmt.Printf("  %d. %s\n", i+1, resolved.Gateway.Name)
+		fmt.Printf("     URL: %s\n", ui.MaskURL(resolved.Gateway.URL))
+		fmt.Printf("     API Key: %s\n", maskAPIKey(resolved.Gateway.APIKey))
+		fmt.Printf("     Timeout: %s\n", resolved.Gateway.ProbeTimeout(resolved.Gateway.Timeout))
+	}
+
+	fmt.Printf("\nCompared Values:\n")
+	fmt.Printf("  - Enforced context window\n")
+	fmt.Printf("  - Enforced max output tokens\n")
+	fmt.Printf("  - Average request latency\n")
+}
+
+// showProbeCompareHelp はprobe-compareコマンドのヘルプを表示する
+func showProbeCompareHelp() {
+	fmt.Println(`llm-info probe-compare - Compare probed model constraints across gateways
+
+USAGE:
+    llm-info probe-compare --model <MODEL_ID> --gateways <NAME1,NAME2,...> [flags]
+
+FLAGS:
+    --model string       Target model ID (required)
+    --gateways string    Comma-separated gateway names from config (required, at least 2)
+    --timeout duration   Request timeout override for all gateways
+    --context-only       Compare only context window
+    --output-only        Compare only max output tokens
+    --format string      Output format (table, json) (default: table)
+    --dry-run            Show execution plan and estimated cost without making actual API calls
+    --yes                Run without asking when the estimated cost exceeds spend_confirm_threshold
+    --no-notify          Disable completion notification
+    --github-summary     Write Markdown summary to $GITHUB_STEP_SUMMARY
+    --wait duration      Wait for another probe of each gateway to finish (default: fail immediately)
+    --force              Take over gateway locks held by other probes
+    --quiet              Do not show progress
+    --plain              Show progress as log lines instead of a progress bar
+    --config string      Path to config file
+    --help               Show help for probe-compare command
+
+EXAMPLES:
+    # Compare two production gateways
+    llm-info probe-compare --model gpt-4o --gateways prodA,prodB
+
+    # Compare only the enforced context window
+    llm-info probe-compare --model gpt-4o --gateways prodA,prodB,staging --context-only
+
+    # JSON output for downstream tooling
+    llm-info probe-compare --model gpt-4o --gateways prodA,prodB --format json
+
+DESCRIPTION:
+    Probes the same model through each gateway in turn and reports differences
+    in the enforced context window, max output tokens and average latency.
+    Gateways often clamp limits below the provider's published values.`)
+}
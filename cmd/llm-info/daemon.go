@@ -0,0 +1,350 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/notify"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/readonly"
+	"github.com/armaniacs/llm-info/internal/schedule"
+	"github.com/armaniacs/llm-info/internal/storage"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["daemon"] = daemonCommand
+}
+
+// scheduledJob はスケジュール付きの探索ジョブ
+type scheduledJob struct {
+	job      config.ProbeJob
+	schedule *schedule.Schedule
+	next     time.Time
+}
+
+// daemonCommand は設定された探索ジョブをcronスケジュールで実行し続ける
+func daemonCommand(args []string) error {
+	daemonCmd := flag.NewFlagSet("daemon", flag.ExitOnError)
+	scheduleExpr := daemonCmd.String("schedule", "", "Cron expression for all jobs without their own schedule (e.g. \"0 3 * * *\")")
+	configFile := daemonCmd.String("config", "", "Path to config file")
+	resultDir := daemonCmd.String("result-dir", "", "Directory to persist probe results")
+	retention := daemonCmd.Duration("retention", 0, "Remove saved results and logs older than this duration")
+	once := daemonCmd.Bool("once", false, "Run all jobs once and exit")
+	runOnStart := daemonCmd.Bool("run-on-start", false, "Run all jobs immediately before waiting for the schedule")
+	noNotify := daemonCmd.Bool("no-notify", false, "Disable notifications")
+	lockWait := daemonCmd.Duration("wait", 10*time.Minute, "Wait up to this long for another probe of the same gateway before skipping the job")
+	showHelp := daemonCmd.Bool("help", false, "Show help for daemon command")
+
+	daemonCmd.Parse(args)
+
+	if *showHelp {
+		showDaemonHelp()
+		return nil
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	if err := readonly.Check("daemon"); err != nil {
+		return err
+	}
+	probeConfig := configManager.GetProbeConfig()
+
+	// 設定ファイルのdaemonセクションをCLI引数で上書き
+	var daemonConfig config.DaemonConfig
+	if newConfig := configManager.GetNewConfig(); newConfig != nil {
+		daemonConfig = newConfig.Daemon
+	}
+	if *scheduleExpr != "" {
+		daemonConfig.Schedule = *scheduleExpr
+	}
+	if *resultDir != "" {
+		daemonConfig.ResultDir = *resultDir
+	}
+	if *retention > 0 {
+		daemonConfig.Retention = *retention
+	}
+	if daemonConfig.ResultDir == "" {
+		daemonConfig.ResultDir = probeConfig.Result.Dir
+	}
+
+	// 保持ポリシー（daemon.retentionは最大保持期間を上書き）
+	policy, _, err := loadRetentionPolicy(configManager)
+	if err != nil {
+		return fmt.Errorf("invalid storage retention settings: %w", err)
+	}
+	if daemonConfig.Retention > 0 {
+		policy.MaxAge = daemonConfig.Retention
+	}
+
+	if len(daemonConfig.Jobs) == 0 {
+		return fmt.Errorf("no probe jobs configured (add daemon.jobs to the config file)")
+	}
+
+	jobs, err := buildScheduledJobs(daemonConfig, *once)
+	if err != nil {
+		return err
+	}
+
+	resultStorage, err := storage.NewResultStorageWithOptions(daemonConfig.ResultDir, probeConfig.Result.StorageOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create result storage: %w", err)
+	}
+
+	runner := &daemonRunner{
+		configManager: configManager,
+		storage:       resultStorage,
+		config:        daemonConfig,
+		policy:        policy,
+		logDir:        probeConfig.Log.Dir,
+		notify:        !*noNotify,
+		lock:          &lockFlags{wait: lockWait, force: new(bool)},
+	}
+
+	// 起動時にも整理する
+	runner.prune()
+
+	if *once {
+		for _, job := range jobs {
+			runner.run(job.job)
+		}
+		runner.prune()
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	daemonLogf("Daemon started with %d job(s); results are saved to %s", len(jobs), daemonConfig.ResultDir)
+
+	if *runOnStart {
+		for _, job := range jobs {
+			runner.run(job.job)
+		}
+		runner.prune()
+	}
+
+	now := time.Now()
+	for _, job := range jobs {
+		job.next = job.schedule.Next(now)
+	}
+
+	for {
+		next := earliestJob(jobs)
+		if next == nil {
+			return fmt.Errorf("no upcoming runs found for the configured schedules")
+		}
+		daemonLogf("Next run: %s via %s at %s", next.job.Model, gatewayLabel(next.job.Gateway), next.next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next.next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			daemonLogf("Daemon stopped")
+			return nil
+		case <-timer.C:
+		}
+
+		// 同時刻に予定されているジョブをまとめて実行
+		now := time.Now()
+		for _, job := range jobs {
+			if !job.next.IsZero() && !job.next.After(now) {
+				runner.run(job.job)
+				job.next = job.schedule.Next(time.Now())
+			}
+		}
+		runner.prune()
+	}
+}
+
+// buildScheduledJobs はジョブごとのcron式を解析する
+func buildScheduledJobs(daemonConfig config.DaemonConfig, once bool) ([]*scheduledJob, error) {
+	var jobs []*scheduledJob
+	for i, job := range daemonConfig.Jobs {
+		expr := job.Schedule
+		if expr == "" {
+			expr = daemonConfig.Schedule
+		}
+
+		scheduled := &scheduledJob{job: job}
+		if expr == "" {
+			if !once {
+				return nil, fmt.Errorf("job %d (%s): no schedule configured (use --schedule or daemon.schedule)", i+1, job.Model)
+			}
+		} else {
+			parsed, err := schedule.Parse(expr)
+			if err != nil {
+				return nil, fmt.Errorf("job %d (%s): %w", i+1, job.Model, err)
+			}
+			scheduled.schedule = parsed
+		}
+		jobs = append(jobs, scheduled)
+	}
+	return jobs, nil
+}
+
+// earliestJob は次に実行予定のジョブを返す
+func earliestJob(jobs []*scheduledJob) *scheduledJob {
+	var earliest *scheduledJob
+	for _, job := range jobs {
+		if job.next.IsZero() {
+			continue
+		}
+		if earliest == nil || job.next.Before(earliest.next) {
+			earliest = job
+		}
+	}
+	return earliest
+}
+
+// daemonRunner はジョブの実行と結果の保存・整理を行う
+type daemonRunner struct {
+	configManager *internalConfig.Manager
+	storage       storage.ResultStorage
+	config        config.DaemonConfig
+	policy        storage.RetentionPolicy
+	logDir        string
+	notify        bool
+	lock          *lockFlags
+}
+
+// run は1つの探索ジョブを実行して結果を保存する
+func (r *daemonRunner) run(job config.ProbeJob) {
+	daemonLogf("Running %s probe for %s via %s", probeLabel(job.Probe), job.Model, gatewayLabel(job.Gateway))
+
+	resolved, err := r.configManager.ResolveConfig(&internalConfig.CLIArgs{
+		Gateway:      job.Gateway,
+		OutputFormat: "json",
+	})
+	if err != nil {
+		daemonLogf("Error: failed to resolve gateway for %s: %v", job.Model, err)
+		return
+	}
+
+	// 手動の探索と重なった場合は待ち、それでも解放されなければ今回は見送る
+	gatewayLock, err := acquireGatewayLock(r.configManager, resolved, "daemon", r.lock)
+	if err != nil {
+		daemonLogf("Warning: skipping %s: %v", job.Model, err)
+		return
+	}
+	defer gatewayLock.Release()
+
+	start := time.Now()
+	report, err := probeGateway(job.Model, resolved, job.Probe == "context", job.Probe == "max_output", nil)
+	if err != nil {
+		daemonLogf("Warning: %s: %v", job.Model, err)
+	}
+
+	provider := detectProvider(r.configManager, resolved, job.Model, nil)
+	if result := report.Find(probe.ProbeTypeContextWindow); result != nil {
+		if err := r.storage.SaveContextResult(provider, job.Model, result); err != nil {
+			daemonLogf("Warning: failed to save context result: %v", err)
+		}
+	}
+	if result := report.Find(probe.ProbeTypeMaxOutput); result != nil {
+		if err := r.storage.SaveMaxOutputResult(provider, job.Model, result); err != nil {
+			daemonLogf("Warning: failed to save max output result: %v", err)
+		}
+	}
+
+	daemonLogf("Finished %s in %s (%d trials, %s)", job.Model, time.Since(start).Round(time.Second), report.TotalTrials, report.SpendSummary())
+
+	if r.notify {
+		sendProbeNotification(notify.NewNotifier(resolved.Notifications), report)
+	}
+	runProbeHook(resolved, report)
+}
+
+// prune は保持ポリシーに従って結果ファイルとログを削除する
+func (r *daemonRunner) prune() {
+	if r.policy.IsZero() {
+		return
+	}
+
+	for _, result := range pruneDirs([]string{r.config.ResultDir, r.logDir}, r.policy, false) {
+		if len(result.Removed) > 0 {
+			daemonLogf("Pruned %d file(s) (%s) from %s", len(result.Removed), storage.FormatSize(result.FreedBytes), result.Dir)
+		}
+	}
+}
+
+// daemonLogf はタイムスタンプ付きのログを標準エラー出力に書き出す
+func daemonLogf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "[%s] %s\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, args...))
+}
+
+// gatewayLabel はログ表示用のゲートウェイ名を返す
+func gatewayLabel(gateway string) string {
+	if gateway == "" {
+		return "default gateway"
+	}
+	return gateway
+}
+
+// probeLabel はログ表示用の探索種別を返す
+func probeLabel(probeType string) string {
+	switch probeType {
+	case "context":
+		return "context window"
+	case "max_output":
+		return "max output"
+	default:
+		return "full"
+	}
+}
+
+// showDaemonHelp はdaemonコマンドのヘルプを表示する
+func showDaemonHelp() {
+	fmt.Println(`llm-info daemon - Run configured probe jobs on a cron schedule
+
+USAGE:
+    llm-info daemon [flags]
+
+FLAGS:
+    --schedule string     Cron expression for jobs without their own schedule
+                          (minute hour day-of-month month day-of-week, or @daily/@hourly/...)
+    --result-dir string   Directory to persist probe results (default: ~/.local/state/llm-info/estimates)
+    --retention duration  Remove saved results and logs older than this duration
+                          (overrides storage.retention.max_age)
+    --once                Run all jobs once and exit
+    --run-on-start        Run all jobs immediately before waiting for the schedule
+    --no-notify           Disable notifications
+    --wait duration       Wait for another probe of the same gateway before skipping
+                          the job (default: 10m)
+    --config string       Path to config file
+    --help                Show help for daemon command
+
+CONFIG:
+    daemon:
+      schedule: "0 3 * * *"
+      retention: "720h"
+      jobs:
+        - model: "gpt-4o"
+          gateway: "production"
+          probe: "all"          # all, context, max_output
+        - model: "gpt-4o-mini"
+          schedule: "@hourly"   # per-job override
+
+EXAMPLES:
+    # Run configured jobs every night at 03:00
+    llm-info daemon --schedule "0 3 * * *"
+
+    # Run every job once (e.g. from an external scheduler)
+    llm-info daemon --once
+
+    # Keep only the last 30 days of results and logs
+    llm-info daemon --schedule "@daily" --retention 720h
+
+DESCRIPTION:
+    Runs until interrupted (Ctrl+C or SIGTERM). Each run saves results using the
+    same schema as --save-result and sends a probe_completed notification when
+    notifications are configured. Jobs take the same per-gateway lock as the
+    probe commands, so a run that overlaps a manual probe waits for it and is
+    skipped if the gateway is still busy after --wait.`)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/armaniacs/llm-info/internal/demo"
+	"github.com/armaniacs/llm-info/internal/readonly"
+	"github.com/armaniacs/llm-info/internal/ui"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["demo"] = demoCommand
+}
+
+// demoFlags はdemoコマンド自身のフラグ（それ以外の引数はデモ環境で実行するllm-infoにそのまま渡す）
+var demoFlags = map[string]bool{"serve": true, "help": true, "h": true}
+
+// demoCommand は組み込みの偽ゲートウェイとサンプルの探索結果を用意し、その環境でllm-infoを実行する
+// 利用者の設定ファイルや保存済みの結果には触れず、終了時にデモ環境を削除する
+func demoCommand(args []string) error {
+	demoCmd := flag.NewFlagSet("demo", flag.ExitOnError)
+	serve := demoCmd.Bool("serve", false, "Keep the demo gateway running and print the environment to use it from another shell")
+	showHelp := demoCmd.Bool("help", false, "Show help for demo command")
+
+	// 先頭にあるdemoのフラグだけを解析する（llm-info demo --format json の--formatはデモ環境に渡す）
+	own := 0
+	for own < len(args) && strings.HasPrefix(args[own], "-") && demoFlags[strings.TrimLeft(args[own], "-")] {
+		own++
+	}
+	demoCmd.Parse(args[:own])
+	rest := args[own:]
+	if len(rest) > 0 && rest[0] == "--" {
+		rest = rest[1:]
+	}
+
+	if *showHelp {
+		showDemoHelp()
+		return nil
+	}
+	if len(rest) > 0 && rest[0] == "demo" {
+		return fmt.Errorf("demo cannot run itself")
+	}
+	if *serve && len(rest) > 0 {
+		return fmt.Errorf("--serve cannot be used with a command")
+	}
+
+	gateway := demo.NewGateway()
+	gatewayURL, stop, err := gateway.Start()
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	root, err := os.MkdirTemp("", "llm-info-demo-")
+	if err != nil {
+		return fmt.Errorf("failed to create demo directory: %w", err)
+	}
+	defer os.RemoveAll(root)
+
+	env, err := demo.Prepare(root, gatewayURL)
+	if err != nil {
+		return err
+	}
+
+	// Ctrl+Cはデモ環境のllm-infoに任せ、終了後にデモ環境を削除する
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	if *serve {
+		fmt.Printf("Demo gateway listening on %s\n\n", gatewayURL)
+		fmt.Println("Run llm-info against it from another shell:")
+		fmt.Println()
+		for _, variable := range env.Env() {
+			fmt.Printf("  export %s\n", variable)
+		}
+		fmt.Println("  llm-info")
+		fmt.Println()
+		fmt.Println("Press Ctrl+C to stop. The demo directory is removed on exit.")
+		<-ctx.Done()
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate llm-info executable: %w", err)
+	}
+	ui.Infof("Using the built-in demo gateway (%d sample models, no credentials needed)", len(demo.Models()))
+
+	// --read-onlyは引数から取り除かれているため、デモ環境のllm-infoにも指定し直す
+	if readonly.Enabled() {
+		rest = append([]string{"--read-only"}, rest...)
+	}
+	cmd := exec.Command(executable, rest...)
+	cmd.Env = append(demoEnviron(os.Environ()), env.Env()...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// 終了コードはそのまま返す（デモ環境は先に削除する）
+		stop()
+		os.RemoveAll(root)
+		exit(exitErr.ExitCode())
+	}
+	return err
+}
+
+// demoEnviron はデモ環境の設定を上書きしてしまうLLM_INFO_*の環境変数を取り除く
+// 表示や統計に関する変数（LLM_INFO_DEBUG、LLM_INFO_VERBOSE、LLM_INFO_STATS）は残す
+func demoEnviron(environ []string) []string {
+	keep := map[string]bool{"LLM_INFO_DEBUG": true, "LLM_INFO_VERBOSE": true, "LLM_INFO_STATS": true}
+	filtered := make([]string, 0, len(environ))
+	for _, variable := range environ {
+		name, _, _ := strings.Cut(variable, "=")
+		if strings.HasPrefix(name, "LLM_INFO_") && !keep[name] {
+			continue
+		}
+		filtered = append(filtered, variable)
+	}
+	return filtered
+}
+
+// showDemoHelp はdemoコマンドのヘルプを表示する
+func showDemoHelp() {
+	fmt.Println(`llm-info demo - Try llm-info against a built-in fake gateway
+
+USAGE:
+    llm-info demo [--serve] [command] [flags]
+
+FLAGS:
+    --serve   Keep the demo gateway running and print the environment
+              variables to use it from another shell
+    --help    Show help for demo command
+
+DESCRIPTION:
+    Starts an in-memory gateway on 127.0.0.1 with realistic sample models
+    (OpenAI, Anthropic, Vertex AI and vLLM style) and runs llm-info against
+    it. No API key or network access is needed.
+
+    The demo uses a temporary config, cache and state directory that is
+    seeded with sample probe results and removed on exit, so your own
+    config file and saved results are never touched.
+
+    Everything after demo is passed to llm-info, so any command works:
+    model listing, probes, results, verify, spend and so on.
+
+    The gateway enforces each model's limits and rejects oversized requests
+    with the same error messages as the real providers. llama-3.1-70b-instruct
+    advertises 131072 tokens but only accepts 32768, like a vLLM deployment
+    with a reduced max_model_len.
+
+EXAMPLES:
+    llm-info demo
+    llm-info demo --format json
+    llm-info demo probe-context --model gpt-4o
+    llm-info demo results list
+    llm-info demo verify --model llama-3.1-70b-instruct
+    llm-info demo --serve`)
+}
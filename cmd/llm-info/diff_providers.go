@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/model"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["diff-providers"] = diffProvidersCommand
+}
+
+// discrepancyReport はdiff-providersのJSON出力
+type discrepancyReport struct {
+	Gateway       string              `json:"gateway"`
+	Compared      int                 `json:"compared"` // 公称値と探索結果の両方があるモデルの数
+	MinPercent    float64             `json:"min_percent"`
+	MinTokens     int                 `json:"min_tokens"`
+	Discrepancies []model.Discrepancy `json:"discrepancies"`
+}
+
+// diffProvidersCommand はカタログの公称値と保存済みの探索結果の食い違いを報告する
+func diffProvidersCommand(args []string) error {
+	diffCmd := flag.NewFlagSet("diff-providers", flag.ExitOnError)
+	baseURL := diffCmd.String("url", "", "Base URL of the LLM gateway")
+	apiKey := diffCmd.String("api-key", "", "API key for authentication")
+	gateway := diffCmd.String("gateway", "", "Gateway name to use from config")
+	timeout := diffCmd.Duration("timeout", 30*time.Second, "Request timeout")
+	configFile := diffCmd.String("config", "", "Path to config file")
+	offline := diffCmd.Bool("offline", false, "Compare against the cached model catalog instead of fetching it")
+	minPercent := diffCmd.Float64("min-percent", 1, "Ignore differences smaller than this percentage of the advertised value")
+	minTokens := diffCmd.Int("min-tokens", 0, "Ignore differences smaller than this number of tokens")
+	failOnDiscrepancy := diffCmd.Bool("fail-on-discrepancy", false, "Exit with an error if any discrepancy is reported")
+	outputFormat := diffCmd.String("format", "table", "Output format (table, json)")
+	showHelp := diffCmd.Bool("help", false, "Show help for diff-providers command")
+
+	diffCmd.Parse(args)
+
+	if *showHelp {
+		showDiffProvidersHelp()
+		return nil
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+	if *minPercent < 0 || *minTokens < 0 {
+		return fmt.Errorf("--min-percent and --min-tokens must not be negative")
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+		URL:          *baseURL,
+		APIKey:       *apiKey,
+		Timeout:      *timeout,
+		Gateway:      *gateway,
+		OutputFormat: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	var models []model.Model
+	if *offline {
+		entry, err := loadCatalogCache(configManager, resolved)
+		if err != nil {
+			return err
+		}
+		models = model.FromAPIResponse(entry.Models)
+	} else {
+		fmt.Fprintf(os.Stderr, "Fetching advertised limits from %s...\n", resolved.Gateway.URL)
+		models, err = fetchAdvertisedCatalog(resolved)
+		if err != nil {
+			return err
+		}
+	}
+	applyMeasurements(configManager, resolved, models)
+
+	report := discrepancyReport{
+		Gateway:       resolved.Gateway.Name,
+		MinPercent:    *minPercent,
+		MinTokens:     *minTokens,
+		Discrepancies: []model.Discrepancy{},
+	}
+	for _, m := range models {
+		if _, ok := m.MeasuredAt(); ok {
+			report.Compared++
+		}
+	}
+	thresholds := model.DiscrepancyThresholds{MinPercent: *minPercent, MinTokens: *minTokens}
+	if discrepancies := model.FindDiscrepancies(models, thresholds); discrepancies != nil {
+		report.Discrepancies = discrepancies
+	}
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		printDiscrepancyReport(report)
+	}
+
+	if *failOnDiscrepancy && len(report.Discrepancies) > 0 {
+		return fmt.Errorf("%d discrepancy(ies) between advertised and measured limits", len(report.Discrepancies))
+	}
+	return nil
+}
+
+// printDiscrepancyReport は対処すべき順に公称値と実測値の食い違いを表示する
+func printDiscrepancyReport(report discrepancyReport) {
+	fmt.Printf("Compared %d measured model(s) on %s (ignoring differences below %g%% or %d tokens)\n\n",
+		report.Compared, report.Gateway, report.MinPercent, report.MinTokens)
+
+	if report.Compared == 0 {
+		fmt.Println("No stored probe results for models in this catalog. Run probe-context or probe-max-output first.")
+		return
+	}
+	if len(report.Discrepancies) == 0 {
+		fmt.Println("✅ No discrepancies: measured limits match the catalog.")
+		return
+	}
+
+	icons := map[string]string{
+		model.SeverityHigh:   "🔴",
+		model.SeverityMedium: "🟠",
+		model.SeverityLow:    "🟡",
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tMODEL\tFIELD\tCLAIMED\tMEASURED\tDIFF\tMEASURED AT")
+	for _, d := range report.Discrepancies {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%+d (%+.1f%%)\t%s\n",
+			icons[d.Severity], d.Model, d.Field, d.Claimed, d.Measured, d.Diff, d.DiffPercent,
+			d.MeasuredAt.Local().Format("2006-01-02"))
+	}
+	w.Flush()
+	fmt.Println()
+
+	overstated := 0
+	for _, d := range report.Discrepancies {
+		if d.Diff < 0 {
+			overstated++
+		}
+	}
+	fmt.Printf("⚠️  %d discrepancy(ies), %d where the catalog overstates the limit. Requests sized to an overstated limit fail; update the catalog or re-probe to confirm.\n",
+		len(report.Discrepancies), overstated)
+}
+
+// showDiffProvidersHelp はdiff-providersコマンドのヘルプを表示する
+func showDiffProvidersHelp() {
+	fmt.Println(`llm-info diff-providers - Report catalog limits that differ from probe results
+
+USAGE:
+    llm-info diff-providers [flags]
+
+FLAGS:
+    --gateway string          Gateway name to use from config
+    --url string              Base URL of the LLM gateway
+    --api-key string          API key for authentication
+    --offline                 Compare against the cached model catalog instead of
+                              fetching it
+    --min-percent float       Ignore differences smaller than this percentage of
+                              the advertised value (default: 1)
+    --min-tokens int          Ignore differences smaller than this number of
+                              tokens (default: 0)
+    --fail-on-discrepancy     Exit with an error if any discrepancy is reported
+    --format string           Output format: table, json (default: table)
+    --timeout duration        Request timeout (default: 30s)
+    --config string           Path to config file
+    --help                    Show help for diff-providers command
+
+EXAMPLES:
+    # Every measured model on the production gateway
+    llm-info diff-providers --gateway production
+
+    # Only differences of at least 5% and 1000 tokens, from the cached catalog
+    llm-info diff-providers --gateway production --offline --min-percent 5 --min-tokens 1000
+
+    # Fail a CI job when the catalog disagrees with the probes
+    llm-info diff-providers --gateway production --fail-on-discrepancy --format json
+
+DESCRIPTION:
+    Compares the catalog's max_tokens and max_output_tokens with the latest
+    stored probe-context and probe-max-output results for each model, and
+    reports the ones that differ. No requests are sent to the models; only
+    the model list is fetched. Findings are ordered by what to fix first:
+
+      high     measured limit is at least 5% below the advertised one;
+               requests sized to the catalog fail
+      medium   measured limit is below the advertised one by less than 5%,
+               or at least 5% above it
+      low      measured limit is slightly above the advertised one
+
+    Within a severity, larger differences come first. A difference is
+    ignored when it is below either --min-percent or --min-tokens. Models
+    without an advertised value or a stored probe result are not compared.`)
+}
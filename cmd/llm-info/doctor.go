@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/cache"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/doctor"
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/internal/ui"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["doctor"] = doctorCommand
+}
+
+// doctorSection は診断結果の見出しごとのまとまり
+type doctorSection struct {
+	Title  string          `json:"title"`
+	Checks []doctor.Result `json:"checks"`
+}
+
+// doctorReport はdoctorコマンドのJSON出力
+type doctorReport struct {
+	Sections []doctorSection       `json:"sections"`
+	Summary  map[doctor.Status]int `json:"summary"`
+}
+
+// doctorCommand は設定・ネットワーク・ゲートウェイ・保存先を診断する
+func doctorCommand(args []string) error {
+	doctorCmd := flag.NewFlagSet("doctor", flag.ExitOnError)
+	gatewayName := doctorCmd.String("gateway", "", "Only check this gateway from the config file")
+	url := doctorCmd.String("url", "", "Check this gateway URL instead of the configured gateways")
+	apiKey := doctorCmd.String("api-key", "", "API key to use with --url (default: LLM_INFO_API_KEY)")
+	timeout := doctorCmd.Duration("timeout", 10*time.Second, "Timeout for each network check with --url")
+	outputFormat := doctorCmd.String("format", "table", "Output format (table, json)")
+	configFile := doctorCmd.String("config", "", "Path to config file")
+	showHelp := doctorCmd.Bool("help", false, "Show help for doctor command")
+
+	doctorCmd.Parse(args)
+
+	if *showHelp {
+		showDoctorHelp()
+		return nil
+	}
+
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	configPath := *configFile
+	if configPath == "" {
+		configPath = internalConfig.GetDefaultConfigPath()
+	}
+	// 読み込みエラーは設定ファイルのチェックで報告する
+	configManager := internalConfig.NewManager(configPath)
+	configManager.Load()
+
+	d := doctor.New()
+	sections := []doctorSection{{
+		Title:  "Configuration",
+		Checks: []doctor.Result{d.CheckConfig(configPath), d.CheckEnv()},
+	}}
+
+	gateways, err := doctorGateways(configManager, *gatewayName, *url, *apiKey, *timeout)
+	if err != nil {
+		return err
+	}
+	for _, gw := range gateways {
+		sections = append(sections, doctorSection{
+			Title:  fmt.Sprintf("Gateway %s (%s)", gw.Name, ui.MaskURL(gw.URL)),
+			Checks: d.CheckGateway(gw),
+		})
+	}
+
+	probeConfig := configManager.GetProbeConfig()
+	cacheDir := configManager.GetStorageConfig().CacheDir
+	if cacheDir == "" {
+		cacheDir = cache.GetDefaultCacheDir()
+	}
+	sections = append(sections, doctorSection{
+		Title: "Storage",
+		Checks: []doctor.Result{
+			d.CheckWritable("cache dir", cacheDir),
+			d.CheckWritable("result dir", probeConfig.Result.Dir),
+			d.CheckWritable("log dir", probeConfig.Log.Dir),
+		},
+	})
+
+	summary := make(map[doctor.Status]int)
+	for _, section := range sections {
+		for _, check := range section.Checks {
+			summary[check.Status]++
+		}
+	}
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(doctorReport{Sections: sections, Summary: summary}); err != nil {
+			return err
+		}
+	} else {
+		printDoctorReport(sections, summary, len(gateways) == 0)
+	}
+
+	if summary[doctor.StatusFail] > 0 {
+		return fmt.Errorf("%d check(s) failed", summary[doctor.StatusFail])
+	}
+	return nil
+}
+
+// doctorGateways は診断するゲートウェイを返す
+// --urlが指定されていなければ設定済みの全ゲートウェイ、なければLLM_INFO_URLを対象にする
+func doctorGateways(configManager *internalConfig.Manager, name, url, apiKey string, timeout time.Duration) ([]*config.GatewayConfig, error) {
+	if url != "" {
+		if apiKey == "" {
+			apiKey = os.Getenv("LLM_INFO_API_KEY")
+		}
+		redact.Register(apiKey)
+		return []*config.GatewayConfig{{Name: "--url", URL: url, APIKey: apiKey, Timeout: timeout}}, nil
+	}
+
+	if name != "" {
+		gw, err := configManager.GetGatewayConfig(name)
+		if err != nil {
+			return nil, err
+		}
+		return []*config.GatewayConfig{gw}, nil
+	}
+
+	var gateways []*config.GatewayConfig
+	for _, gatewayName := range configManager.ListGateways() {
+		if gw, err := configManager.GetGatewayConfig(gatewayName); err == nil {
+			gateways = append(gateways, gw)
+		}
+	}
+	if len(gateways) == 0 {
+		if envURL := os.Getenv("LLM_INFO_URL"); envURL != "" {
+			gateways = append(gateways, &config.GatewayConfig{
+				Name:    "LLM_INFO_URL",
+				URL:     envURL,
+				APIKey:  os.Getenv("LLM_INFO_API_KEY"),
+				Timeout: timeout,
+			})
+		}
+	}
+	return gateways, nil
+}
+
+// printDoctorReport は診断結果を見出しごとに表示する
+func printDoctorReport(sections []doctorSection, summary map[doctor.Status]int, noGateways bool) {
+	icons := map[doctor.Status]string{
+		doctor.StatusPass: "✅",
+		doctor.StatusWarn: "⚠️ ",
+		doctor.StatusFail: "❌",
+		doctor.StatusSkip: "⏭️ ",
+	}
+
+	for i, section := range sections {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(section.Title)
+		for _, check := range section.Checks {
+			fmt.Printf("  %s %-16s %s\n", icons[check.Status], check.Name, check.Detail)
+			for _, solution := range check.Solutions {
+				fmt.Printf("       💡 %s\n", solution)
+			}
+		}
+		if i == 0 && noGateways {
+			fmt.Println("\nNo gateways to check; add one with llm-info init or pass --url")
+		}
+	}
+
+	fmt.Printf("\n%d passed, %d warning(s), %d failed, %d skipped\n",
+		summary[doctor.StatusPass], summary[doctor.StatusWarn], summary[doctor.StatusFail], summary[doctor.StatusSkip])
+}
+
+// showDoctorHelp はdoctorコマンドのヘルプを表示する
+func showDoctorHelp() {
+	fmt.Println(`llm-info doctor - Diagnose configuration, connectivity and storage
+
+USAGE:
+    llm-info doctor [flags]
+
+FLAGS:
+    --gateway string    Only check this gateway from the config file
+    --url string        Check this gateway URL instead of the configured gateways
+    --api-key string    API key to use with --url (default: LLM_INFO_API_KEY)
+    --timeout duration  Timeout for each network check with --url (default: 10s)
+    --format string     Output format: table, json (default: table)
+    --config string     Path to config file
+    --help              Show help for doctor command
+
+CHECKS:
+    Configuration   the config file parses and validates; LLM_INFO_* values are valid
+    Gateway         DNS resolution, TLS handshake (https only), API key accepted,
+                    models endpoint (/v1/models, falling back to /model/info)
+    Storage         the cache, result and log directories are writable
+
+EXAMPLES:
+    # Check everything that is configured
+    llm-info doctor
+
+    # Check a single gateway
+    llm-info doctor --gateway production
+
+    # Check a gateway before adding it to the config file
+    llm-info doctor --url https://litellm.example.com --api-key sk-...
+
+DESCRIPTION:
+    Each failed check is printed with remediation hints. Checks that depend on
+    a failed step (for example auth after a failed TLS handshake) are skipped.
+    The command exits with status 1 when any check fails.`)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/cost"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/ui"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// 見積もりで想定する上限（公称値がわからない場合）
+const (
+	defaultAssumedContextWindow = 128000
+	defaultAssumedMaxOutput     = 16384
+)
+
+// dryRunAssumptions は見積もりで想定する上限と料金
+type dryRunAssumptions struct {
+	contextWindow       int
+	contextWindowSource string // --claimed-limit, catalog, default
+	maxOutput           int
+	maxOutputSource     string // catalog, default
+	catalogPricing      *config.Pricing
+}
+
+// resolveDryRunAssumptions は見積もりで想定する上限と料金を決める
+// APIは呼ばず、--claimed-limit、キャッシュ済みのモデル一覧（--offline用）、デフォルト値の順に使う
+func resolveDryRunAssumptions(configManager *internalConfig.Manager, resolved *internalConfig.ResolvedConfig, modelID string, claimedLimit int) dryRunAssumptions {
+	assumptions := dryRunAssumptions{
+		contextWindow:       defaultAssumedContextWindow,
+		contextWindowSource: "default",
+		maxOutput:           defaultAssumedMaxOutput,
+		maxOutputSource:     "default",
+	}
+
+	if catalog, err := catalogCache(configManager); err == nil {
+		if entry, err := catalog.Load(resolved.Gateway.URL); err == nil {
+			models := model.FromAPIResponse(entry.Models)
+			claims := advertisedClaims(models, modelID)
+			if claims.ContextWindow > 0 {
+				assumptions.contextWindow, assumptions.contextWindowSource = claims.ContextWindow, "catalog"
+			}
+			if claims.MaxOutput > 0 {
+				assumptions.maxOutput, assumptions.maxOutputSource = claims.MaxOutput, "catalog"
+			}
+			assumptions.catalogPricing = catalogPricing(models, modelID)
+		}
+	}
+
+	if claimedLimit > 0 {
+		assumptions.contextWindow, assumptions.contextWindowSource = claimedLimit, "--claimed-limit"
+	}
+	return assumptions
+}
+
+// catalogPricing はモデル一覧のトークンあたりの料金を1Kトークンあたりの料金に換算する
+// 出力の料金がない場合は入力の料金を使う。入力の料金もなければnilを返す
+func catalogPricing(catalog []model.Model, modelID string) *config.Pricing {
+	for _, m := range catalog {
+		if m.Name != modelID {
+			continue
+		}
+		if m.InputCost <= 0 {
+			return nil
+		}
+		pricing := &config.Pricing{
+			InputPricePer1K:  m.InputCost * 1000,
+			OutputPricePer1K: m.InputCost * 1000,
+		}
+		for _, key := range []string{"output_cost", "output_cost_per_token"} {
+			if value, ok := m.MetaValue(key); ok {
+				if parsed, err := strconv.ParseFloat(model.FormatMetaValue(value), 64); err == nil && parsed > 0 {
+					pricing.OutputPricePer1K = parsed * 1000
+					break
+				}
+			}
+		}
+		return pricing
+	}
+	return nil
+}
+
+// estimateDryRunCost は模擬実行した探索の呼び出しからコストを見積もり、使った計算機と料金の出典を返す
+// 料金はcost.pricing、モデル一覧、デフォルトの順に使う
+func estimateDryRunCost(resolved *internalConfig.ResolvedConfig, modelID string, assumptions dryRunAssumptions, estimates []*probe.CallEstimate) (*cost.UsageSummary, *cost.Calculator, string) {
+	calculator := cost.NewCalculator(resolved.Cost, modelID)
+	pricingSource := "cost.pricing"
+	if _, known := calculator.PricingFor(modelID); !known {
+		pricingSource = "default rate"
+		if assumptions.catalogPricing != nil {
+			calculator.SetFallbackPricing(modelID, *assumptions.catalogPricing)
+			pricingSource = "catalog"
+		}
+	}
+
+	probeTrials := make(map[string][]cost.TrialUsage)
+	for _, estimate := range estimates {
+		key := estimateUsageKey(estimate.Probe)
+		for _, call := range estimate.Calls {
+			usage := cost.TrialUsage{}
+			if call.Accepted {
+				usage = cost.TrialUsage{PromptTokens: call.InputTokens, CompletionTokens: call.MaxTokens}
+			}
+			probeTrials[key] = append(probeTrials[key], usage)
+		}
+	}
+	return cost.EstimateFromTrials(calculator, modelID, probeTrials), calculator, pricingSource
+}
+
+// printDryRunEstimate は模擬実行した探索の呼び出し回数・トークン数・コストの概算を表示する
+func printDryRunEstimate(resolved *internalConfig.ResolvedConfig, modelID string, assumptions dryRunAssumptions, estimates []*probe.CallEstimate) {
+	summary, calculator, pricingSource := estimateDryRunCost(resolved, modelID, assumptions, estimates)
+	fmt.Print(ui.FormatDryRunCostEstimate(summary, calculator))
+
+	pricing, _ := calculator.PricingFor(modelID)
+	fmt.Printf("Assumptions:\n")
+	for _, estimate := range estimates {
+		switch estimate.Probe {
+		case probe.ProbeTypeContextWindow:
+			fmt.Printf("  Context window : %s tokens (%s)\n", calculator.FormatTokenCount(estimate.AssumedLimit), assumptions.contextWindowSource)
+		case probe.ProbeTypeMaxOutput:
+			fmt.Printf("  Max output     : %s tokens (%s)\n", calculator.FormatTokenCount(estimate.AssumedLimit), assumptions.maxOutputSource)
+		case probe.ProbeTypeMaxInput:
+			fmt.Printf("  Combined limit : %s tokens (%s)\n", calculator.FormatTokenCount(estimate.AssumedLimit), assumptions.contextWindowSource)
+		}
+	}
+	fmt.Printf("  Pricing        : $%g input / $%g output per 1K tokens (%s)\n", pricing.InputPricePer1K, pricing.OutputPricePer1K, pricingSource)
+	fmt.Printf("  Notes          : limits not stated in errors; accepted calls bill up to max_tokens; rejected calls are free\n")
+}
+
+// estimateUsageKey はプローブの種類をUsageSummary.BreakdownByProbeのキーに変換する
+func estimateUsageKey(probeType string) string {
+	if probeType == probe.ProbeTypeContextWindow {
+		return "context"
+	}
+	return probeType
+}
+
+// dryRunComplete はDry-runの最後に表示するメッセージを表示する
+func dryRunComplete() {
+	fmt.Printf("\nDry run complete. Use --dry-run=false to execute actual API calls.\n")
+}
+
+// estimateContextCalls はコンテキストウィンドウ探索の呼び出しを見積もる
+// --test-all-positionsの場合はneedle位置ごとの探索を見積もる
+func estimateContextCalls(strategy probe.Strategy, testAllPositions bool, assumedLimit int) (*probe.CallEstimate, error) {
+	if testAllPositions {
+		return probe.EstimateAllPositionsCalls(assumedLimit), nil
+	}
+	return probe.EstimateContextCalls(strategy, assumedLimit)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["env"] = envCommand
+}
+
+// envCommand はサポートしている環境変数と現在の値・検証結果を表示する
+func envCommand(args []string) error {
+	envCmd := flag.NewFlagSet("env", flag.ExitOnError)
+	strictEnv := envCmd.Bool("strict-env", false, "Fail if unknown LLM_INFO_* variables are set")
+	setOnly := envCmd.Bool("set", false, "Show only variables that are set")
+	outputFormat := envCmd.String("format", "table", "Output format (table, json)")
+	showHelp := envCmd.Bool("help", false, "Show help for env command")
+
+	envCmd.Parse(args)
+
+	if *showHelp {
+		showEnvHelp()
+		return nil
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	var statuses []internalConfig.EnvVarStatus
+	invalid, unknown := 0, 0
+	for _, status := range internalConfig.CheckEnvVars(os.Environ()) {
+		if !status.Known {
+			unknown++
+		} else if status.Error != "" {
+			invalid++
+		}
+		if *setOnly && !status.Set {
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(statuses); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		printEnvStatuses(statuses)
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d environment variable(s) have invalid values", invalid)
+	}
+	if *strictEnv && unknown > 0 {
+		return internalConfig.CheckUnknownEnvVars(os.Environ())
+	}
+	return nil
+}
+
+// printEnvStatuses は環境変数の一覧を表示する
+func printEnvStatuses(statuses []internalConfig.EnvVarStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VARIABLE\tVALUE\tSTATUS\tDESCRIPTION")
+	for _, status := range statuses {
+		value := status.Value
+		if !status.Set {
+			value = "-"
+		} else if value == "" {
+			value = `""`
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", status.Name, value, envStatusLabel(status), status.Description)
+	}
+	w.Flush()
+
+	for _, status := range statuses {
+		switch {
+		case !status.Known && status.Suggestion != "":
+			fmt.Printf("\n⚠️  %s is not a supported variable and is ignored. Did you mean %s?", status.Name, status.Suggestion)
+		case !status.Known:
+			fmt.Printf("\n⚠️  %s is not a supported variable and is ignored.", status.Name)
+		case status.Error != "":
+			fmt.Printf("\n❌ %s: %s", status.Name, status.Error)
+		}
+	}
+	fmt.Println()
+}
+
+// envStatusLabel は環境変数の状態を短く表す
+func envStatusLabel(status internalConfig.EnvVarStatus) string {
+	switch {
+	case !status.Known:
+		return "unknown"
+	case status.Error != "":
+		return "invalid"
+	case status.Set:
+		return "ok"
+	default:
+		return "not set"
+	}
+}
+
+// showEnvHelp はenvコマンドのヘルプを表示する
+func showEnvHelp() {
+	fmt.Println(`llm-info env - List supported environment variables and check their values
+
+USAGE:
+    llm-info env [flags]
+
+FLAGS:
+    --strict-env         Fail if unknown LLM_INFO_* variables are set
+    --set                Show only variables that are set
+    --format string      Output format: table, json (default: table)
+    --help               Show help for env command
+
+EXAMPLES:
+    # Every supported variable with its current value
+    llm-info env
+
+    # Catch typos such as LLM_INFO_TIMOUT in CI
+    llm-info env --set --strict-env
+
+DESCRIPTION:
+    Lists every supported LLM_INFO_* variable with its description, its
+    current value and whether the value is valid. API keys, admin keys and
+    webhook URLs are shown as "(set, hidden)". Variables that start with
+    LLM_INFO_ but are not supported are listed as unknown, with the closest
+    supported name when the difference looks like a typo; their values are
+    hidden too, since a misspelled name may hold a key.
+
+    The command exits non-zero when a variable has an invalid value. Unknown
+    variables are ignored by llm-info, so they only fail the command with
+    --strict-env. The model listing accepts --strict-env as well.`)
+}
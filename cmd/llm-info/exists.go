@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/numfmt"
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["exists"] = existsCommand
+}
+
+// existsResult はexistsコマンドのJSON出力のモデル1件分
+type existsResult struct {
+	Model     string `json:"model"`
+	Exists    bool   `json:"exists"`
+	MaxTokens int    `json:"max_tokens,omitempty"`
+	OK        bool   `json:"ok"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// existsReport はexistsコマンドのJSON出力
+type existsReport struct {
+	Gateway    string         `json:"gateway"`
+	URL        string         `json:"url"`
+	MinContext int            `json:"min_context,omitempty"`
+	OK         bool           `json:"ok"`
+	Models     []existsResult `json:"models"`
+}
+
+// existsCommand はモデルがゲートウェイの一覧にあるかを確かめ、終了コードで返す（デプロイ前の確認用）
+// すべてのモデルが条件を満たせば0、満たさないモデルがあれば1、一覧を取得できなければexit_codesのerror（既定: 2）で終了する
+func existsCommand(args []string) error {
+	// 「exists MODEL --gateway prod」のようにモデルIDを先に書けるよう、フラグより前の引数をモデルIDとして取り出す
+	var modelIDs []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		modelIDs = append(modelIDs, args[0])
+		args = args[1:]
+	}
+
+	existsCmd := flag.NewFlagSet("exists", flag.ExitOnError)
+	gateway := existsCmd.String("gateway", "", "Gateway name to use from config")
+	baseURL := existsCmd.String("url", "", "Base URL of the LLM gateway")
+	apiKey := existsCmd.String("api-key", "", "API key for authentication")
+	timeout := existsCmd.Duration("timeout", 10*time.Second, "Request timeout (default: 10s)")
+	minContextFlag := existsCmd.String("min-context", "", "Also require the advertised context window (max_tokens) to be at least this many tokens (e.g. 100000, 128k)")
+	outputFormat := existsCmd.String("format", "table", "Output format (table, json)")
+	configFile := existsCmd.String("config", "", "Path to config file")
+	showHelp := existsCmd.Bool("help", false, "Show help for exists command")
+
+	existsCmd.Parse(args)
+
+	if *showHelp {
+		showExistsHelp()
+		return nil
+	}
+
+	modelIDs = append(modelIDs, existsCmd.Args()...)
+	if len(modelIDs) == 0 {
+		return fmt.Errorf("model ID is required (e.g. llm-info exists gpt-4o --gateway production)")
+	}
+	// フィルタのトークン数と同じく k・m・b の単位を使える
+	minContext := 0
+	if *minContextFlag != "" {
+		value, err := numfmt.ParseTokens(*minContextFlag)
+		if err != nil {
+			return fmt.Errorf("--min-context: %w", err)
+		}
+		minContext = value
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+		URL:          *baseURL,
+		APIKey:       *apiKey,
+		Timeout:      *timeout,
+		Gateway:      *gateway,
+		OutputFormat: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	// 一覧を取得できない場合は「存在しない」（1）と区別できる終了コードにする
+	catalog, err := fetchAdvertisedCatalog(resolved)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", redact.Error(err))
+		exit(exitCodes.Resolve(config.ExitCodeError))
+	}
+
+	report := checkModelsExist(catalog, modelIDs, minContext)
+	report.Gateway = resolved.Gateway.Name
+	report.URL = redact.String(resolved.Gateway.URL)
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		printExistsReport(report)
+	}
+
+	if !report.OK {
+		exit(1)
+	}
+	return nil
+}
+
+// checkModelsExist はモデルごとに一覧にあるか、--min-contextを満たすかを調べる
+func checkModelsExist(catalog []model.Model, modelIDs []string, minContext int) *existsReport {
+	byName := make(map[string]model.Model, len(catalog))
+	for _, m := range catalog {
+		byName[m.Name] = m
+	}
+
+	report := &existsReport{MinContext: minContext, OK: true, Models: []existsResult{}}
+	for _, id := range modelIDs {
+		result := existsResult{Model: id}
+		m, found := byName[id]
+		switch {
+		case !found:
+			result.Reason = "not listed by the gateway"
+			// "openai/*"のようなワイルドカードのエントリ経由で呼べる可能性がある場合は知らせる
+			for _, candidate := range catalog {
+				if model.IsWildcard(candidate.Name) && model.MatchWildcard(candidate.Name, id) {
+					result.Reason = fmt.Sprintf("not listed by the gateway (it may still be routed through %s)", candidate.Name)
+					break
+				}
+			}
+		case minContext > 0 && m.MaxTokens == 0:
+			result.Exists = true
+			result.Reason = "listed, but the gateway does not advertise its context window"
+		case m.MaxTokens < minContext:
+			result.Exists = true
+			result.MaxTokens = m.MaxTokens
+			result.Reason = fmt.Sprintf("listed, but its context window (%d) is below --min-context %d", m.MaxTokens, minContext)
+		default:
+			result.Exists = true
+			result.MaxTokens = m.MaxTokens
+			result.OK = true
+		}
+		if !result.OK {
+			report.OK = false
+		}
+		report.Models = append(report.Models, result)
+	}
+	return report
+}
+
+// printExistsReport はモデルごとの確認結果を1行ずつ表示する
+func printExistsReport(report *existsReport) {
+	for _, result := range report.Models {
+		if result.OK {
+			if result.MaxTokens > 0 {
+				fmt.Printf("✅ %s is served by %s (max_tokens: %d)\n", result.Model, report.Gateway, result.MaxTokens)
+			} else {
+				fmt.Printf("✅ %s is served by %s\n", result.Model, report.Gateway)
+			}
+			continue
+		}
+		fmt.Printf("❌ %s: %s\n", result.Model, result.Reason)
+	}
+}
+
+// showExistsHelp はexistsコマンドのヘルプを表示する
+func showExistsHelp() {
+	fmt.Println(`llm-info exists - Check that models are currently served by a gateway
+
+USAGE:
+    llm-info exists MODEL [MODEL...] [flags]
+
+Exits with 0 when every model is listed by the gateway (and meets --min-context),
+1 when any model is missing or too small, and 2 (exit_codes.error) when the model
+list could not be fetched.
+
+FLAGS:
+    --gateway string      Gateway name to use from config
+    --url string          Base URL of the LLM gateway
+    --api-key string      API key for authentication
+    --timeout duration    Request timeout (default: 10s)
+    --min-context string  Also require the advertised context window (max_tokens) to be at least this many tokens (e.g. 100000, 128k)
+    --format string       Output format (table, json) (default: table)
+    --config string       Path to config file
+    --help                Show help for exists command
+
+EXAMPLES:
+    # Deployment preflight
+    llm-info exists gpt-4o --gateway production
+
+    # Require a 100k context window
+    llm-info exists gpt-4o --gateway production --min-context 100k
+
+    # Check several models at once
+    llm-info exists gpt-4o claude-sonnet-4 --gateway production --format json`)
+}
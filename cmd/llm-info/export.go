@@ -0,0 +1,253 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	errhandler "github.com/armaniacs/llm-info/internal/error"
+	"github.com/armaniacs/llm-info/internal/export"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/numfmt"
+	"github.com/armaniacs/llm-info/internal/ui"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["export"] = exportCommand
+}
+
+// exportCommand はモデル一覧をCSVまたはParquetで書き出す
+func exportCommand(args []string) error {
+	exportCmd := flag.NewFlagSet("export", flag.ExitOnError)
+	outputFormat := exportCmd.String("format", "csv", "Export format (csv, parquet)")
+	outFile := exportCmd.String("out", "", "Output file (default: stdout for csv)")
+	columns := exportCmd.String("columns", ui.AllColumns, "Columns to export, comma-separated (default: all)")
+	numberFormat := exportCmd.String("number-format", numfmt.StyleRaw, "Number format of max_tokens and input_cost in CSV (raw, thousands, si)")
+	allGateways := exportCmd.Bool("all-gateways", false, "Export every gateway in the config file into one file")
+	gatewayName := exportCmd.String("gateway", "", "Gateway name to use from config")
+	baseURL := exportCmd.String("url", "", "Base URL of the LLM gateway")
+	apiKey := exportCmd.String("api-key", "", "API key for authentication")
+	timeout := exportCmd.Duration("timeout", 30*time.Second, "Request timeout")
+	configFile := exportCmd.String("config", "", "Path to config file")
+	verbose := exportCmd.Bool("verbose", false, "Show the underlying error for every skipped gateway")
+	progressOpts := addProgressFlags(exportCmd)
+	showHelp := exportCmd.Bool("help", false, "Show help for export command")
+
+	exportCmd.Parse(args)
+
+	if *showHelp {
+		showExportHelp()
+		return nil
+	}
+
+	if *outputFormat != "csv" && *outputFormat != "parquet" {
+		return fmt.Errorf("invalid format: %s (valid: csv, parquet)", *outputFormat)
+	}
+	if *outputFormat == "parquet" && (*outFile == "" || *outFile == "-") && stdoutCapture == nil {
+		return fmt.Errorf("--out is required for parquet export")
+	}
+	displayFormat, err := numfmt.FromEnv(*numberFormat)
+	if err != nil {
+		return err
+	}
+	if *outputFormat == "parquet" && displayFormat.Humanized() {
+		return fmt.Errorf("--number-format only applies to csv export")
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+
+	var gateways []*config.GatewayConfig
+	if *allGateways {
+		var err error
+		gateways, err = doctorGateways(configManager, "", "", "", *timeout)
+		if err != nil {
+			return err
+		}
+	} else {
+		resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+			URL:          *baseURL,
+			APIKey:       *apiKey,
+			Timeout:      *timeout,
+			Gateway:      *gatewayName,
+			OutputFormat: "json",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to resolve config: %w", err)
+		}
+		gateways = []*config.GatewayConfig{resolved.Gateway}
+	}
+	if len(gateways) == 0 {
+		return fmt.Errorf("no gateways to export; add one with llm-info init or pass --url")
+	}
+
+	progress := progressOpts.newProgress()
+	defer progress.Finish()
+
+	var snapshots []export.Snapshot
+	skipped := errhandler.NewAggregator()
+	for i, gw := range gateways {
+		progress.StartItem(gw.Name, i+1, len(gateways))
+		snapshot, err := exportSnapshot(gw)
+		if err != nil {
+			if !*allGateways {
+				return err
+			}
+			skipped.Add(snapshot.Gateway, err)
+			progress.FinishItem("skipped")
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+		progress.FinishItem(fmt.Sprintf("%d models", len(snapshot.Models)))
+	}
+	progress.Finish()
+	// 読み飛ばしたゲートウェイのエラーは同じ内容ごとにまとめて表示する
+	errhandler.NewHandler(*verbose).HandleAggregate(skipped)
+	if len(snapshots) == 0 {
+		return fmt.Errorf("could not fetch models from any gateway")
+	}
+
+	table, err := export.SelectColumns(export.FormatNumbers(export.BuildTable(snapshots), displayFormat), exportColumns(*columns))
+	if err != nil {
+		return fmt.Errorf("invalid --columns: %w (run llm-info columns to list them)", err)
+	}
+
+	// ファイルへは一時ファイルに書き込んでから置き換え、途中で失敗しても既存のファイルを壊さない
+	var out io.Writer = os.Stdout
+	var file *atomicFile
+	if *outFile != "" && *outFile != "-" {
+		file, err = createAtomicFile(*outFile)
+		if err != nil {
+			return err
+		}
+		out = file
+	}
+
+	if *outputFormat == "parquet" {
+		err = export.WriteParquet(out, table)
+	} else {
+		err = export.WriteCSV(out, table)
+	}
+	if err != nil {
+		if file != nil {
+			file.Abort()
+		}
+		return fmt.Errorf("failed to write %s: %w", *outputFormat, err)
+	}
+
+	if file != nil {
+		if err := file.Commit(); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d models (%d columns) from %d gateway(s) to %s\n",
+			len(table.Rows), len(table.Columns), len(snapshots), *outFile)
+	}
+	return nil
+}
+
+// exportColumns は--columnsの値を列名のリストにする。allの場合はnil（すべての列）を返す
+func exportColumns(columns string) []string {
+	if columns == "" || columns == ui.AllColumns {
+		return nil
+	}
+	var names []string
+	for _, name := range strings.Split(columns, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// exportSnapshot はゲートウェイからモデル一覧を取得し、取得時刻とともに返す
+// LiteLLMゲートウェイではデプロイメントの状態とモデルグループも付加する
+func exportSnapshot(gw *config.GatewayConfig) (export.Snapshot, error) {
+	snapshot := export.Snapshot{Gateway: gw.Name, FetchedAt: time.Now()}
+	if snapshot.Gateway == "" {
+		snapshot.Gateway = gw.URL
+	}
+
+	cfg := internalConfig.New(gw.URL, gw.APIKey, gw.Timeout)
+	cfg.Timeouts = gw.Timeouts
+	cfg.Proxy = gw.Proxy
+	client := api.NewClient(cfg)
+
+	response, err := client.FetchModelsWithFallback()
+	if err != nil {
+		return snapshot, errhandler.WrapErrorWithDetection(err, gw.URL)
+	}
+	snapshot.Models = model.FromAPIResponse(response.Models)
+	if gw.IsLiteLLM() {
+		fetchLiteLLMStatus(client, snapshot.Models, false)
+	}
+	return snapshot, nil
+}
+
+// showExportHelp はexportコマンドのヘルプを表示する
+func showExportHelp() {
+	fmt.Println(`llm-info export - Export the model catalog to CSV or Parquet
+
+USAGE:
+    llm-info export [flags]
+
+FLAGS:
+    --format string      Export format (csv, parquet) (default: csv)
+    --out string         Output file (default: stdout for csv; required for parquet)
+    --columns string     Columns to export, comma-separated (default: all)
+    --number-format string
+                         Number format of max_tokens and input_cost in CSV:
+                         raw, thousands, si (default: raw)
+    --all-gateways       Export every gateway in the config file into one file
+    --gateway string     Gateway name to use from config
+    --url string         Base URL of the LLM gateway
+    --api-key string     API key for authentication
+    --timeout duration   Request timeout (default: 30s)
+    --verbose            Show the underlying error for every skipped gateway
+    --quiet              Do not show progress
+    --plain              Show progress as log lines instead of a progress bar
+    --config string      Path to config file
+    --help               Show help for export command
+
+EXAMPLES:
+    # Daily snapshot of every gateway for the data warehouse
+    llm-info export --all-gateways --format parquet --out models-2026-10-16.parquet
+
+    # Load it into BigQuery
+    bq load --source_format=PARQUET analytics.llm_models models-2026-10-16.parquet
+
+    # CSV of a single gateway on stdout
+    llm-info export --gateway production > models.csv
+
+    # Only some columns
+    llm-info export --columns model,max_tokens,meta_litellm_provider
+
+DESCRIPTION:
+    Writes one row per model with the gateway name, the snapshot time (UTC),
+    name, max_tokens, mode, input_cost and variants, followed by one column
+    per metadata key. Nested metadata such as LiteLLM's model_info is
+    flattened into columns like meta_model_info_max_input_tokens, and column
+    names only use lowercase letters, digits and underscores so warehouses
+    accept them as-is. For type: litellm gateways the deployment health and
+    model group providers are included as well. --columns keeps only the
+    listed columns in the given order; llm-info columns shows the export
+    name of each column.
+
+    Parquet files are uncompressed with a single row group. snapshot_at is a
+    TIMESTAMP_MILLIS column, numeric metadata is DOUBLE, booleans are BOOLEAN
+    and everything else is a UTF-8 string; missing values are null. In CSV
+    missing values are empty and timestamps use RFC 3339.
+
+    CSV keeps raw numbers by default so loads stay stable, regardless of
+    global.number_format. --number-format thousands or si writes max_tokens
+    as 128,000 or 128K and input_cost per 1M tokens ($2.50), using the
+    separators of the LC_ALL, LC_NUMERIC or LANG locale.
+
+    With --all-gateways, gateways that cannot be reached are skipped with a
+    warning; the command fails only when no gateway could be read.`)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	errhandler "github.com/armaniacs/llm-info/internal/error"
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/internal/ui"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// isGatewayDown はエラーがゲートウェイの停止（接続できない、タイムアウト、5xx、過負荷）によるものかを返す
+// 認証エラーや404などは別のゲートウェイでは解決しない設定の問題のため、フェイルオーバーしない
+func isGatewayDown(err error) bool {
+	errType, code := errhandler.DetectErrorType(err)
+	return errType == errhandler.ErrorTypeNetwork || code == "server_error" || code == "overloaded"
+}
+
+// failoverGateways はゲートウェイのfailoverに指定したゲートウェイの設定を順に返す
+// --urlや環境変数でURLを上書きした場合は設定ファイルのゲートウェイに接続していないため、フェイルオーバーしない
+func failoverGateways(configManager *internalConfig.Manager, primary *config.GatewayConfig) []*config.GatewayConfig {
+	if len(primary.Failover) == 0 {
+		return nil
+	}
+	configured, err := configManager.GetGatewayConfig(primary.Name)
+	if err != nil || configured.URL != primary.URL {
+		return nil
+	}
+
+	var gateways []*config.GatewayConfig
+	for _, name := range primary.Failover {
+		gw, err := configManager.GetGatewayConfig(name)
+		if err != nil {
+			ui.Warnf("Warning: skipping failover gateway %s: %v", name, err)
+			continue
+		}
+		gateways = append(gateways, gw)
+	}
+	return gateways
+}
+
+// runWithFailover はfnを設定したゲートウェイで実行し、ゲートウェイが停止している場合はfailoverのゲートウェイで順に再実行する
+// 成功したゲートウェイと、別のゲートウェイが応答した場合はその情報を返す
+// どのゲートウェイでも失敗した場合は、設定したゲートウェイのエラーを返す
+func runWithFailover(configManager *internalConfig.Manager, primary *config.GatewayConfig, fn func(*config.GatewayConfig) error) (*config.GatewayConfig, *ui.FailoverInfo, error) {
+	err := fn(primary)
+	if err == nil || !isGatewayDown(err) {
+		return primary, nil, err
+	}
+
+	for _, gw := range failoverGateways(configManager, primary) {
+		ui.Debugf("Gateway %s is unavailable, trying failover gateway %s...", primary.Name, gw.Name)
+		if failoverErr := fn(gw); failoverErr != nil {
+			ui.Warnf("Warning: failover gateway %s also failed: %v", gw.Name, redact.String(failoverErr.Error()))
+			continue
+		}
+		return gw, &ui.FailoverInfo{
+			Primary:  primary.Name,
+			ServedBy: gw.Name,
+			URL:      gw.URL,
+			Reason:   redact.String(err.Error()),
+		}, nil
+	}
+	return primary, nil, err
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/lock"
+	"github.com/armaniacs/llm-info/internal/storage"
+)
+
+// lockFlags は探索コマンド共通のロック関連フラグ
+type lockFlags struct {
+	wait  *time.Duration
+	force *bool
+}
+
+// addLockFlags は--waitと--forceを登録する
+func addLockFlags(fs *flag.FlagSet) *lockFlags {
+	return &lockFlags{
+		wait:  fs.Duration("wait", 0, "Wait up to this long for another probe of the same gateway to finish"),
+		force: fs.Bool("force", false, "Take over the lock held by another probe of the same gateway"),
+	}
+}
+
+// newGatewayLocker はstorage設定に従ってロックの置き場所を決める
+// storage.remote.lockが有効なら他のマシンとも共有のロックを使う
+func newGatewayLocker(configManager *internalConfig.Manager) *lock.Locker {
+	storageConfig := configManager.GetStorageConfig()
+	dir := storageConfig.LockDir
+	if dir == "" {
+		dir = lock.GetDefaultLockDir()
+	}
+	stores := []lock.Store{lock.NewDirStore(dir)}
+	if remote := storageConfig.Remote; remote.Enabled() && remote.Lock {
+		s3 := storage.NewS3Store(internalConfig.RemoteS3Config(remote))
+		stores = append(stores, lock.NewRemoteStore(s3, remote.Prefix, storage.ErrObjectNotFound))
+	}
+	return lock.NewLocker(stores...)
+}
+
+// acquireGatewayLock は探索の間ゲートウェイをロックする
+// 他の探索が実行中なら--waitの間待ち、それでも解放されなければエラーを返す
+func acquireGatewayLock(configManager *internalConfig.Manager, resolved *internalConfig.ResolvedConfig, command string, flags *lockFlags) (*lock.Lock, error) {
+	opts := lock.Options{
+		Wait:    *flags.wait,
+		Force:   *flags.force,
+		Command: command,
+		OnWait: func(holder lock.Info) {
+			fmt.Fprintf(os.Stderr, "Waiting for %s (pid %d) to finish probing %s...\n", holder.Owner, holder.PID, resolved.Gateway.URL)
+		},
+	}
+	return newGatewayLocker(configManager).Acquire(resolved.Gateway.URL, opts)
+}
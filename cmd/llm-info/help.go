@@ -31,18 +31,35 @@ func (hp *HelpProvider) ShowGeneralHelp() {
 	fmt.Fprintln(w, "  --url string\tゲートウェイのURL")
 	fmt.Fprintln(w, "  --api-key string\tAPIキー")
 	fmt.Fprintln(w, "  --gateway string\t使用するゲートウェイ名")
+	fmt.Fprintln(w, "  --all-gateways\t設定ファイルのすべてのゲートウェイから並行して取得（JSONでは失敗したゲートウェイをerrorsに出力）")
 	fmt.Fprintln(w, "  --timeout duration\tリクエストタイムアウト (デフォルト: 10s)")
-	fmt.Fprintln(w, "  --format string\t出力形式 (table|json) (デフォルト: table)")
+	fmt.Fprintln(w, "  --format string\t出力形式 (table|json|設定ファイルのformattersに登録した名前)。table=grid|plain|compactで罫線を選択。--watchではjson-patchも指定可 (デフォルト: table)")
 	fmt.Fprintln(w, "  --filter string\tフィルタ条件")
 	fmt.Fprintln(w, "  --sort string\tソート条件")
-	fmt.Fprintln(w, "  --columns string\t表示するカラム (カンマ区切り)")
+	fmt.Fprintln(w, "  --columns string\t表示するカラム (カンマ区切り、allですべて、一覧は llm-info columns)")
+	fmt.Fprintln(w, "  --pinned\tllm-info pinでピン留めしたモデルのみ表示")
 	fmt.Fprintln(w, "  --config string\t設定ファイルパス")
 	fmt.Fprintln(w, "  --verbose\t詳細なログを表示")
+	fmt.Fprintln(w, "  --quiet\t警告以外のメッセージ（エンドポイント表示やヒント）を表示しない")
+	fmt.Fprintln(w, "  --no-hints\tヒントと初回起動時の案内を表示しない（スクリプト向け）")
+	fmt.Fprintln(w, "  --output\t標準出力の代わりにファイルに書き出す（全コマンド共通。成功した場合のみ置き換える）")
+	fmt.Fprintln(w, "  --read-only\t探索やchatなど補完を生成するリクエストを送らない（全コマンド共通。一覧取得と保存済みの結果のみ）")
 	fmt.Fprintln(w, "  --help\tヘルプを表示")
-	fmt.Fprintln(w, "  --version\tバージョンを表示")
+	fmt.Fprintln(w, "  --version\tバージョンとビルド情報（コミット、ビルド日時、Goのバージョン）を表示")
+	fmt.Fprintln(w, "  --check-update\t--versionに加えて最新のGitHubリリースと比較")
 	fmt.Fprintln(w, "  --init-config\t設定ファイルのテンプレートを作成")
 	fmt.Fprintln(w, "  --check-config\t設定ファイルを検証")
 	fmt.Fprintln(w, "  --list-gateways\t登録済みゲートウェイを一覧表示")
+	fmt.Fprintln(w, "  --prune\t保持ポリシーに従って保存済みの結果とログを削除")
+	fmt.Fprintln(w, "  --watch\tモデル一覧を定期取得して変更を表示・通知")
+	fmt.Fprintln(w, "  --watch-interval duration\t--watchの取得間隔 (デフォルト: 5m)")
+	fmt.Fprintln(w, "  --offline\tネットワークに接続せず、キャッシュ済みのモデル一覧と探索結果を表示")
+	fmt.Fprintln(w, "  --number-format string\tトークン数とコストの表記 (raw|thousands|si) (デフォルト: raw)")
+	fmt.Fprintln(w, "  --dedupe\t正規化後のIDが同じモデルをまとめ、元のIDをvariantsとして表示")
+	fmt.Fprintln(w, "  --strict-parse\tレスポンスに欠落・不正なフィールドがあるモデルを注記せず、エラーにする")
+	fmt.Fprintln(w, "  --raw[=pretty|both]\t受信したままのレスポンスを一覧の代わりに出力 (both: 一覧の後に出力)")
+	fmt.Fprintln(w, "  --strict-env\t未知のLLM_INFO_*環境変数が設定されていればエラーにする")
+	fmt.Fprintln(w, "  --github-summary\tGitHub Actionsのステップサマリーとアノテーションを出力")
 	w.Flush()
 
 	fmt.Print(`
@@ -59,6 +76,17 @@ func (hp *HelpProvider) ShowGeneralHelp() {
   # JSON出力
   llm-info --format json
   
+  # モデル一覧の変更を監視
+  llm-info --gateway production --watch --watch-interval 10m
+  llm-info --gateway production --offline
+  llm-info --dedupe
+
+  # すべてのゲートウェイを並行して取得
+  llm-info --all-gateways --format json
+
+  # 組み込みのデモゲートウェイで試す（APIキー不要）
+  llm-info demo
+  
 詳細なヘルプ:
   llm-info --help filter    # フィルタ構文のヘルプ
   llm-info --help sort      # ソートオプションのヘルプ
@@ -79,23 +107,50 @@ func (hp *HelpProvider) ShowFilterHelp() {
 使用可能な条件:
   name:パターン          モデル名でフィルタ（部分一致）
   exclude:パターン       モデル名で除外（部分一致）
-  tokens>数値           最大トークン数が指定値より大きい
-  tokens<数値           最大トークン数が指定値より小さい
+  name=名前             モデル名の完全一致（大文字小文字を区別しない）
+  name==名前            モデル名の完全一致（大文字小文字も区別する）
+  name!=名前            モデル名の完全一致で除外（大文字小文字を区別しない）
+  tokens>数値           最大トークン数が指定値以上（>=も可、128k・1mのように指定可）
+  tokens<数値           最大トークン数が指定値以下（<=も可）
   cost>数値             入力コストが指定値より大きい
   cost<数値             入力コストが指定値より小さい
   mode:値               モードでフィルタ（chat/completion）
+  meta.キー:値          メタデータの値が一致（例: meta.supports_vision:true）
+  meta.キー>数値        メタデータの数値が指定値より大きい（>=で指定値以上）
+  meta.キー<数値        メタデータの数値が指定値より小さい（<=で指定値以下）
+  measured_context>数値     探索したコンテキストウィンドウが指定値より大きい（<、>=、<=も可）
+  measured_max_output>数値  探索した最大出力トークン数が指定値より大きい（<、>=、<=も可）
+  measured_at<期間          指定した期間より前に探索したモデル（例: 30d, 2w, 12h）
+  measured_at>期間          指定した期間内に探索したモデル
+  incomplete                max_tokensか入力コストがないモデル
+  pinned                    llm-info pinでピン留めしたモデル（--pinnedと同じ）
+  tag:タグ                  llm-info note tagでタグを付けたモデル（大文字小文字を区別しない）
+  @名前                     設定ファイルのfiltersに登録した名前付きフィルタ
 
 使用例:
   llm-info --filter "gpt"                           # GPTモデルのみ
   llm-info --filter "name:gpt,tokens>1000"          # GPTでトークン数>1000
+  llm-info --filter "name=gpt-4"                    # gpt-4のみ（gpt-4oなどは含まない）
+  llm-info --filter "name:gpt-4o,name!=gpt-4o-mini" # gpt-4o系からgpt-4o-miniを除く
   llm-info --filter "exclude:beta,cost<0.01"        # ベータ版除外でコスト<0.01
   llm-info --filter "mode:chat,tokens>4000"         # チャットモードでトークン数>4000
+  llm-info --filter "tokens>=1m"                    # 100万トークン以上
+  llm-info --filter "meta.supports_vision:true"     # 画像入力に対応したモデルのみ
+  llm-info --filter "measured_at<30d"               # 30日以上探索していないモデル
+  llm-info --filter "incomplete"                    # メタデータが欠けたモデルのみ
+  llm-info --filter "@cheap-chat,tokens>100000"     # 名前付きフィルタに条件を追加
 
 ヒント:
   - 条件はカンマ(,)で区切って複数指定できます
-  - 条件はAND条件で結合されます
+  - 条件はAND条件で結合されます（name=とname==を複数指定した場合はいずれかに一致）
   - 大文字小文字は区別されません
   - ワイルドカード(*)は使用できません
+  - トークン数にはk（1,000）、m（1,000,000）、b（1,000,000,000）を付けられます（128kは128000）
+  - meta.のキーはゲートウェイが返した任意のフィールドを指定できます
+    （ドット区切りでネストした値も参照可能、--columns "meta.キー" で列としても表示できます）
+  - measured_*は保存済みの探索結果を使い、探索結果がないモデルは一致しません
+  - @名前は設定ファイルの filters: { cheap-chat: "mode:chat,cost<0.000005" } を展開します
+    （名前付きフィルタの中でも@名前を使えます）
 `)
 	fmt.Println()
 }
@@ -107,22 +162,30 @@ func (hp *HelpProvider) ShowSortHelp() {
 基本構文:
   --sort "フィールド"        # 昇順
   --sort "-フィールド"       # 降順
+  --sort "フィールド1,-フィールド2"  # 値が同じモデルを次のフィールドで並べる
 
 使用可能なフィールド:
   name, model              モデル名
   tokens, max_tokens       最大トークン数
   cost, input_cost         入力コスト
   mode                     モード
+  measured_context         探索したコンテキストウィンドウ
+  measured_max_output      探索した最大出力トークン数
+  measured_at, probed_at   探索結果の保存時刻
+  latency_p50              探索の試行のレイテンシの中央値（探索結果がないモデルは最後）
+  pinned                   ピン留め（昇順でピン留めしたモデルが先頭）
 
 使用例:
   llm-info --sort "name"           # 名前の昇順
   llm-info --sort "-tokens"        # トークン数の降順
   llm-info --sort "cost"           # コストの昇順
+  llm-info --sort "pinned,-tokens" # ピン留めしたモデルを先頭に、トークン数の降順
+  llm-info --sort "latency_p50"    # 実測のレイテンシが小さい順
 
 ヒント:
   - マイナス(-)を付けると降順になります
   - デフォルトは昇順です
-  - 複数のフィールドでのソートはできません
+  - カンマ区切りで複数のフィールドを指定できます
 `)
 	fmt.Println()
 }
@@ -140,6 +203,14 @@ func (hp *HelpProvider) ShowConfigHelp() {
       url: "https://api.example.com"
       api_key: "your-api-key"
       timeout: "10s"
+      default_model: "gpt-4o-mini"  # probe/chatで--model省略時に使うモデル
+      max_body_size: "10MB"  # 探索リクエストのボディの上限
+      probe_endpoint: "chat"  # 探索に使うエンドポイント（chat, completions, responses, auto）
+      proxy: "socks5h://localhost:1080"  # このゲートウェイへの接続に使うプロキシ
+      max_concurrency: 4  # 同時に送るリクエストの上限
+      rate_limit: "60/m"  # リクエストのレートの上限（/s、/m、/h）
+      max_retries: 2  # 429を返されたリクエストを再送する回数（0〜10）
+      failover: ["development"]  # 停止しているときに代わりに使うゲートウェイ
     - name: "development"
       url: "https://dev-api.example.com"
       api_key: "dev-api-key"
@@ -159,9 +230,23 @@ func (hp *HelpProvider) ShowConfigHelp() {
   LLM_INFO_DEBUG         デバッグモードを有効にする
 
 コマンド:
+  llm-info init              # 対話形式でゲートウェイを設定（接続確認付き）
   llm-info --init-config     # 設定ファイルのテンプレートを作成
   llm-info --check-config    # 設定ファイルを検証
+  llm-info doctor            # 設定・接続・保存先を診断
+  llm-info env               # 環境変数の一覧と現在の値・検証結果を表示
+  llm-info ping --all-gateways  # ゲートウェイごとの接続遅延を比較
+  llm-info exists gpt-4o --gateway production  # モデルが提供されているかを終了コードで返す
+  llm-info preflight --manifest models-required.yaml  # アプリケーションが必要とするモデルと性能を一括検査
+  llm-info spend             # LiteLLMキーの残り予算と利用額を表示
+  llm-info export            # モデル一覧をCSV/Parquetで書き出す
+  llm-info audit duplicates  # ゲートウェイ間で食い違うモデル定義を報告
+  llm-info audit policy      # ポリシーに違反するモデルを報告（CI向け）
+  llm-info diff-providers    # カタログの公称値と探索結果の食い違いを報告
+  llm-info inventory --sign key.pem  # 署名付きのモデル一覧を監査証跡として書き出す
+  llm-info chat --gateway production  # ゲートウェイのdefault_modelと対話する
   llm-info --list-gateways   # 登録済みゲートウェイを一覧表示
+  llm-info --prune           # 保持ポリシーに従って古い結果とログを削除
 
 優先順位:
   1. コマンドライン引数
@@ -258,9 +343,14 @@ func (hp *HelpProvider) ShowErrorsHelp() {
 
   2. APIエラー
      - 認証失敗 (401)
+     - 支払いが必要 (402)
      - 認可失敗 (403)
      - エンドポイント不在 (404)
+     - リクエストが大きすぎる (413)
      - レート制限 (429)
+     - 利用枠の超過 (insufficient_quota)
+     - 法的な理由による利用制限 (451)
+     - プロバイダーの過負荷 (529, overloaded_error)
      - サーバーエラー (5xx)
 
   3. 設定エラー
@@ -294,6 +384,9 @@ func (hp *HelpProvider) ShowErrorsHelp() {
   
   # バージョン情報を表示
   llm-info --version
+
+  # 新しいリリースがあるか確認
+  llm-info --version --check-update
 `)
 	fmt.Println()
 }
@@ -319,14 +412,27 @@ func (hp *HelpProvider) ShowTopicHelp(topic string) {
 	}
 }
 
-// ShowVersion はバージョン情報を表示する
-func (hp *HelpProvider) ShowVersion() {
+// ShowVersion はバージョン情報とビルド情報を表示する
+func (hp *HelpProvider) ShowVersion(meta buildMetadata) {
 	fmt.Printf("llm-info version %s\n", hp.version)
+	fmt.Printf("  commit:     %s\n", valueOrUnknown(meta.Commit))
+	fmt.Printf("  built:      %s\n", valueOrUnknown(meta.BuildDate))
+	fmt.Printf("  go version: %s\n", meta.GoVersion)
+	fmt.Printf("  platform:   %s\n", meta.Platform)
+	fmt.Println()
 	fmt.Println("Copyright (c) 2024 llm-info contributors")
 	fmt.Println("License: MIT")
 	fmt.Println("Repository: https://github.com/armaniacs/llm-info")
 }
 
+// valueOrUnknown は空の値をunknownとして表示する
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
 // ShowConfigTemplate は設定ファイルのテンプレートを表示する
 func (hp *HelpProvider) ShowConfigTemplate() {
 	fmt.Print(`# llm-info 設定ファイルテンプレート
@@ -339,6 +445,22 @@ gateways:
     url: "https://api.example.com"
     api_key: "your-production-api-key"
     timeout: "10s"
+    # 段階ごとのタイムアウト（任意、未設定の項目はglobal.timeoutsを使用）
+    # timeouts:
+    #   dial: "5s"              # TCP接続の確立
+    #   tls_handshake: "5s"     # TLSハンドシェイク
+    #   response_header: "10s"  # レスポンスヘッダーの受信（モデル一覧の取得のみ）
+    #   total: "10s"            # リクエスト全体（timeoutと同じ）
+    #   probe: "3m"             # 探索リクエスト全体（probeコマンドのデフォルト30sを上書き）
+    # ゲートウェイの種類（任意）。litellmを指定すると/healthと/model_group/infoも取得し、
+    # デプロイメントの状態（health列）とグループのプロバイダー（group列）を表示
+    # type: "litellm"
+    # このゲートウェイへのリクエストの上限（任意、すべてのコマンドで共通）
+    # max_concurrency: 4  # 同時に送るリクエスト数
+    # rate_limit: "60/m"  # リクエストのレート（/s、/m、/h）
+    # max_retries: 2      # 429を返されたリクエストを待ってから再送する回数（0〜10）
+    # 停止しているとき（接続できない、5xx、過負荷）に順に代わりに使うゲートウェイ（任意、一覧取得とchat）
+    # failover: ["development"]
     description: "本番環境ゲートウェイ"
   
   # 開発環境ゲートウェイ
@@ -369,12 +491,96 @@ global:
   # デフォルトのソートフィールド
   sort_by: "name"
   
+  # トークン数とコストの表記 (raw|thousands|si)
+  number_format: "raw"
+
+  # テーブルの罫線 (default|grid|plain|compact)
+  table_style: "default"
+
+  # 探索やchatなど補完を生成するリクエストを送らない (true|false)
+  read_only: false
+
+  # 見積もりコスト（USD）がこれを超える探索は実行前に確認する（0は確認しない、--yesで省略）
+  spend_confirm_threshold: 0
+  
   # デフォルトで表示するカラム
   columns: "name,tokens,cost,mode"
   
   # 詳細ログを有効にする (true|false)
   verbose: false
 
+# 通知設定（任意）
+# notifications:
+#   webhook_url: "https://hooks.slack.com/services/XXX/YYY/ZZZ"
+#   format: "slack"  # json|slack
+#   events: ["catalog_changed", "probe_completed"]  # 省略時はすべて
+#   timeout: "10s"
+
+# 外部フォーマッター（任意）: --format <名前> で出力内容のJSONを標準入力で渡して整形
+# formatters:
+#   corp-report:
+#     command: "./corp-report --style weekly"
+#     timeout: "30s"
+
+# フック設定（任意）: 結果のJSONを標準入力で渡して外部コマンドを実行
+# hooks:
+#   post_fetch: "./notify.sh"  # モデル一覧の取得後
+#   post_probe: "jq -c . >> ~/probe-results.jsonl"  # 探索の完了後
+#   timeout: "30s"
+
+# モデルIDの正規化設定（任意）
+# normalization:
+#   dedupe: false            # trueで常に--dedupeを有効にする
+#   disable_defaults: false  # 組み込みルール（プロバイダー接頭辞・日付サフィックスの除去）を無効化
+#   rules:
+#     - pattern: "^azure-"
+#       replace: ""
+#   aliases:
+#     chatgpt-4o: "gpt-4o"
+
+# 結果とログの保存設定（任意）
+# storage:
+#   result_dir: "~/.local/state/llm-info/estimates"
+#   log_dir: "~/.local/state/llm-info/logs"
+#   log_format: "json"  # jsonlで1試行1行のフラットなJSON（ログ収集向け）
+#   cache_dir: "~/.cache/llm-info"  # --offline用のキャッシュ
+#   lock_dir: "~/.local/state/llm-info/locks"   # 探索中のゲートウェイのロック
+#   notes_file: "~/.config/llm-info/notes.json"  # llm-info noteのメモとタグ
+#   compress: false  # 探索結果をgzip圧縮して保存
+#   retention:
+#     max_files: 500
+#     max_age: "720h"
+#     max_total_size: "100MB"
+#   remote:  # チームで探索結果を共有するS3互換の保存先
+#     bucket: "team-llm-info"
+#     prefix: "estimates"
+#     region: "ap-northeast-1"
+#     # endpoint: "https://storage.googleapis.com"  # GCSやMinIOの場合
+#     # 認証情報は AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY から読み込む
+#     # lock: true  # 他のマシンからの同時探索も防ぐ
+
+# ローカルの利用統計（任意・オプトイン、ネットワーク送信なし）
+# stats:
+#   enabled: false
+#   file: "~/.local/state/llm-info/stats.json"  # llm-info stats で表示
+
+# 名前付きフィルタ（--filter @cheap-chat のように使う、他の条件と組み合わせ可）
+# filters:
+#   chat: "mode:chat"
+#   cheap-chat: "@chat,cost<0.000005"
+
+# ピン留めしたモデル（llm-info pin add/remove で編集）
+# pinned:
+#   - "gpt-4o"
+
+# HashiCorp VaultからAPIキーを読み込む（ゲートウェイに api_key_vault: "secret/data/llm/prod#key" を指定）
+# vault:
+#   address: "https://vault.example.com"  # 省略時は VAULT_ADDR
+#   auth: "token"  # token（VAULT_TOKEN）または approle（role_id と VAULT_SECRET_ID）
+
+# 組織の共有設定（ローカルの設定の下に重ねる）
+# config_url: "https://internal.example.com/llm-info/org.yaml"
+
 # 環境変数の設定例:
 # export LLM_INFO_URL="https://api.example.com"
 # export LLM_INFO_API_KEY="your-api-key"
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	errhandler "github.com/armaniacs/llm-info/internal/error"
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["init"] = initCommand
+}
+
+// 認証方式
+const (
+	authStoredKey = "1" // APIキーを設定ファイルに保存する
+	authEnvKey    = "2" // APIキーはLLM_INFO_API_KEYから読み込む
+	authNone      = "3" // 認証なし
+)
+
+// initCommand は対話形式でゲートウェイを設定し、設定ファイルに書き込む
+func initCommand(args []string) error {
+	initCmd := flag.NewFlagSet("init", flag.ExitOnError)
+	configFile := initCmd.String("config", "", "Path to config file")
+	noVerify := initCmd.Bool("no-verify", false, "Skip the test fetch before saving")
+	showHelp := initCmd.Bool("help", false, "Show help for init command")
+
+	initCmd.Parse(args)
+
+	if *showHelp {
+		showInitHelp()
+		return nil
+	}
+
+	configPath := *configFile
+	if configPath == "" {
+		configPath = internalConfig.GetDefaultConfigPath()
+	}
+
+	w := newWizard(os.Stdin, os.Stdout)
+	fmt.Printf("llm-info setup — answers are saved to %s\n\n", configPath)
+
+	existing := existingGatewayNames(configPath)
+
+	gw := config.Gateway{}
+	gw.Name = w.ask("Gateway name", "default")
+	for {
+		gw.URL = w.ask("Gateway URL (e.g. https://litellm.example.com)", "")
+		if w.err != nil {
+			return w.err
+		}
+		if err := validateURL(gw.URL); err != nil {
+			fmt.Printf("  %v\n", err)
+			continue
+		}
+		break
+	}
+
+	fmt.Println("Authentication:")
+	fmt.Println("  1) API key saved in the config file")
+	fmt.Println("  2) API key read from LLM_INFO_API_KEY at run time")
+	fmt.Println("  3) No authentication")
+	auth := w.choose("Choose", []string{authStoredKey, authEnvKey, authNone}, authStoredKey)
+	if w.err != nil {
+		return w.err
+	}
+
+	apiKey := ""
+	switch auth {
+	case authStoredKey:
+		apiKey = w.askSecret("API key")
+		redact.Register(apiKey)
+		if apiKey != "" {
+			fmt.Printf("  Using API key %s\n", maskAPIKey(apiKey))
+		}
+	case authEnvKey:
+		apiKey = os.Getenv("LLM_INFO_API_KEY")
+		if apiKey == "" {
+			fmt.Println("  LLM_INFO_API_KEY is not set; the test fetch will run without a key")
+		}
+	}
+	if auth == authStoredKey {
+		gw.APIKey = apiKey
+	}
+
+	for {
+		value := w.ask("Request timeout", "10s")
+		if w.err != nil {
+			return w.err
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			fmt.Printf("  invalid duration: %s\n", value)
+			continue
+		}
+		gw.Timeout = timeout
+		break
+	}
+
+	if !*noVerify {
+		if !verifyGateway(gw, apiKey) && !w.confirm("Save this gateway anyway?", false) {
+			fmt.Println("Nothing was saved.")
+			return nil
+		}
+	}
+
+	if contains(existing, gw.Name) && !w.confirm(fmt.Sprintf("Gateway %q already exists. Replace it?", gw.Name), false) {
+		fmt.Println("Nothing was saved.")
+		return nil
+	}
+	setDefault := len(existing) == 0 || w.confirm("Make it the default gateway?", true)
+
+	if err := internalConfig.SaveGatewayToFile(gw, configPath, setDefault); err != nil {
+		return errhandler.CreateConfigError("invalid_config_format", configPath, err)
+	}
+
+	fmt.Printf("\n✅ Saved gateway %q to %s\n", gw.Name, configPath)
+	if setDefault {
+		fmt.Println("Run `llm-info` to list its models.")
+	} else {
+		fmt.Printf("Run `llm-info --gateway %s` to list its models.\n", gw.Name)
+	}
+	return nil
+}
+
+// verifyGateway はモデル一覧を取得して設定が正しいか確認する
+func verifyGateway(gw config.Gateway, apiKey string) bool {
+	fmt.Printf("\nTesting %s ...\n", gw.URL)
+
+	cfg := internalConfig.New(gw.URL, apiKey, gw.Timeout)
+	response, err := api.NewClient(cfg).FetchModelsWithFallback()
+	if err != nil {
+		appErr := errhandler.WrapErrorWithDetection(err, gw.URL)
+		fmt.Printf("❌ Test fetch failed: %s\n", appErr.Message)
+		for _, solution := range appErr.Solutions {
+			fmt.Printf("   💡 %s\n", solution)
+		}
+		return false
+	}
+
+	fmt.Printf("✅ Found %d models\n", len(response.Models))
+	return true
+}
+
+// existingGatewayNames は設定ファイルに登録済みのゲートウェイ名を返す
+func existingGatewayNames(configPath string) []string {
+	if _, err := os.Stat(configPath); err != nil {
+		return nil
+	}
+	cfg, err := internalConfig.LoadConfigFromFile(configPath)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, gw := range cfg.Gateways {
+		names = append(names, gw.Name)
+	}
+	return names
+}
+
+// contains はスライスに値が含まれるかを返す
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// wizard は対話形式の入力を扱う
+type wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+	err error // 入力が途中で終了した場合のエラー
+}
+
+// newWizard は新しいwizardを作成する
+func newWizard(in io.Reader, out io.Writer) *wizard {
+	return &wizard{in: bufio.NewReader(in), out: out}
+}
+
+// readLine は1行を読み込む
+// 入力が終了した場合はerrを設定し、以降の質問を打ち切れるようにする
+func (w *wizard) readLine() string {
+	line, err := w.in.ReadString('\n')
+	if err != nil && line == "" {
+		w.err = fmt.Errorf("setup aborted: no more input")
+	}
+	return strings.TrimSpace(line)
+}
+
+// ask は値を尋ね、空の入力にはデフォルト値を返す
+func (w *wizard) ask(label, defaultValue string) string {
+	if defaultValue != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", label, defaultValue)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", label)
+	}
+	if value := w.readLine(); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// choose は選択肢のいずれかが入力されるまで尋ねる
+func (w *wizard) choose(label string, options []string, defaultValue string) string {
+	for {
+		value := w.ask(label, defaultValue)
+		if contains(options, value) || w.err != nil {
+			return value
+		}
+		fmt.Fprintf(w.out, "  please enter one of %s\n", strings.Join(options, ", "))
+	}
+}
+
+// confirm はy/nで確認する
+func (w *wizard) confirm(label string, defaultYes bool) bool {
+	hint := "y/N"
+	if defaultYes {
+		hint = "Y/n"
+	}
+	fmt.Fprintf(w.out, "%s [%s]: ", label, hint)
+	switch strings.ToLower(w.readLine()) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	default:
+		return defaultYes
+	}
+}
+
+// askSecret は入力をエコーせずに値を尋ねる
+// 端末でない場合（パイプ入力など）はそのまま読み込む
+func (w *wizard) askSecret(label string) string {
+	fmt.Fprintf(w.out, "%s (input hidden): ", label)
+	if !isTerminal(os.Stdin) || setEcho(false) != nil {
+		return w.readLine()
+	}
+	defer func() {
+		setEcho(true)
+		fmt.Fprintln(w.out)
+	}()
+	return w.readLine()
+}
+
+// isTerminal はファイルが端末かどうかを返す
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// setEcho は端末の入力エコーを切り替える
+func setEcho(enabled bool) error {
+	arg := "-echo"
+	if enabled {
+		arg = "echo"
+	}
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+// showInitHelp はinitコマンドのヘルプを表示する
+func showInitHelp() {
+	fmt.Println(`llm-info init - Interactively add a gateway to the config file
+
+USAGE:
+    llm-info init [flags]
+
+FLAGS:
+    --config string   Path to config file (default: ~/.config/llm-info/llm-info.yaml)
+    --no-verify       Skip the test fetch before saving
+    --help            Show help for init command
+
+DESCRIPTION:
+    Prompts for the gateway name, URL, authentication and timeout, then
+    fetches the model list to check that the settings work before writing
+    them. The API key is typed without echo and can either be stored in the
+    config file or read from LLM_INFO_API_KEY at run time.
+
+    The gateway is added to the existing config file (a gateway with the same
+    name is replaced after confirmation); other settings and comments are kept.
+    New config files are created with mode 0600 because they may hold API keys.
+
+    Use llm-info --init-config instead to write a commented template to edit
+    by hand.`)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"crypto"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	errhandler "github.com/armaniacs/llm-info/internal/error"
+	"github.com/armaniacs/llm-info/internal/inventory"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["inventory"] = inventoryCommand
+}
+
+// inventoryCommand はゲートウェイとモデルの一覧を監査証跡用のJSONとして書き出す
+func inventoryCommand(args []string) error {
+	if len(args) > 0 && args[0] == "verify" {
+		return inventoryVerifyCommand(args[1:])
+	}
+
+	inventoryCmd := flag.NewFlagSet("inventory", flag.ExitOnError)
+	signKey := inventoryCmd.String("sign", "", "Sign the inventory with this PEM private key (Ed25519, ECDSA or RSA)")
+	outFile := inventoryCmd.String("out", "", "Output file (default: stdout)")
+	gateways := inventoryCmd.String("gateways", "", "Comma-separated gateway names to include (default: all configured gateways)")
+	timeout := inventoryCmd.Duration("timeout", 30*time.Second, "Request timeout for each gateway")
+	configFile := inventoryCmd.String("config", "", "Path to config file")
+	verbose := inventoryCmd.Bool("verbose", false, "Show the underlying error for every failed gateway")
+	progressOpts := addProgressFlags(inventoryCmd)
+	showHelp := inventoryCmd.Bool("help", false, "Show help for inventory command")
+
+	inventoryCmd.Parse(args)
+
+	if *showHelp {
+		showInventoryHelp()
+		return nil
+	}
+
+	// 取得に時間をかける前に鍵を読み込んでおく
+	var signer crypto.Signer
+	if *signKey != "" {
+		var err error
+		signer, err = inventory.LoadPrivateKey(*signKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	targets, err := inventoryGateways(configManager, splitGatewayNames(*gateways), *timeout)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no gateways to inventory; add one with llm-info init")
+	}
+
+	progress := progressOpts.newProgress()
+	defer progress.Finish()
+
+	inv := inventory.New("llm-info "+version, time.Now())
+	failures := errhandler.NewAggregator()
+	for i, gw := range targets {
+		progress.StartItem(gw.Name, i+1, len(targets))
+		cfg := internalConfig.New(gw.URL, gw.APIKey, gw.Timeout)
+		cfg.Timeouts = gw.Timeouts
+		cfg.Proxy = gw.Proxy
+		fetchedAt := time.Now()
+		response, err := api.NewClient(cfg).FetchModelsWithFallback()
+		if err != nil {
+			// 監査証跡として失敗も記録する
+			err = errhandler.WrapErrorWithDetection(err, gw.URL)
+			failures.Add(gw.Name, err)
+			progress.FinishItem("failed")
+		} else {
+			progress.FinishItem(fmt.Sprintf("%d models", len(response.Models)))
+		}
+		inv.AddGateway(gw.Name, gw.URL, fetchedAt, response, err)
+	}
+	progress.Finish()
+	// 同じエラーはまとめて1回だけ表示する
+	errhandler.NewHandler(*verbose).HandleAggregate(failures)
+	if failures.Len() == len(targets) {
+		return fmt.Errorf("could not fetch models from any gateway")
+	}
+
+	if signer != nil {
+		if err := inv.Sign(signer); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(inv, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	data = append(data, '\n')
+	if *outFile == "" || *outFile == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := writeFileAtomic(*outFile, data); err != nil {
+		return err
+	}
+
+	signed := "unsigned"
+	if inv.Signature != nil {
+		signed = "signed with key " + inv.Signature.KeyID
+	}
+	fmt.Fprintf(os.Stderr, "Wrote inventory of %d models from %d gateway(s) to %s (%s)\n",
+		inv.ModelCount(), len(inv.Gateways), *outFile, signed)
+	return nil
+}
+
+// inventoryVerifyCommand はインベントリの署名を検証する
+func inventoryVerifyCommand(args []string) error {
+	verifyCmd := flag.NewFlagSet("inventory verify", flag.ExitOnError)
+	keyFile := verifyCmd.String("key", "", "Trusted PEM public key (default: the key embedded in the inventory)")
+	showHelp := verifyCmd.Bool("help", false, "Show help for inventory command")
+
+	verifyCmd.Parse(args)
+
+	if *showHelp {
+		showInventoryHelp()
+		return nil
+	}
+	if verifyCmd.NArg() != 1 {
+		return fmt.Errorf("usage: llm-info inventory verify [--key public.pem] FILE")
+	}
+	path := verifyCmd.Arg(0)
+
+	var trusted crypto.PublicKey
+	if *keyFile != "" {
+		var err error
+		trusted, err = inventory.LoadPublicKey(*keyFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read inventory: %w", err)
+	}
+	var inv inventory.Inventory
+	if err := json.Unmarshal(data, &inv); err != nil {
+		return fmt.Errorf("failed to parse inventory %s: %w", path, err)
+	}
+	if err := inv.Verify(trusted); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	fmt.Printf("✅ %s: valid %s signature by key %s (generated %s, %d models on %d gateway(s))\n",
+		path, inv.Signature.Algorithm, inv.Signature.KeyID, inv.GeneratedAt.Format(time.RFC3339), inv.ModelCount(), len(inv.Gateways))
+	if trusted == nil {
+		fmt.Println("   Checked against the embedded public key only; pass --key to confirm who signed it.")
+	}
+	return nil
+}
+
+// inventoryGateways は指定したゲートウェイ（なければ設定済みの全ゲートウェイ）を返す
+func inventoryGateways(configManager *internalConfig.Manager, names []string, timeout time.Duration) ([]*config.GatewayConfig, error) {
+	var gateways []*config.GatewayConfig
+	if len(names) == 0 {
+		var err error
+		gateways, err = doctorGateways(configManager, "", "", "", timeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range names {
+		gw, err := configManager.GetGatewayConfig(name)
+		if err != nil {
+			return nil, err
+		}
+		gateways = append(gateways, gw)
+	}
+	for _, gw := range gateways {
+		if gw.Timeout == 0 {
+			gw.Timeout = timeout
+		}
+	}
+	return gateways, nil
+}
+
+// showInventoryHelp はinventoryコマンドのヘルプを表示する
+func showInventoryHelp() {
+	fmt.Println(`llm-info inventory - Write a timestamped inventory of gateways and models
+
+USAGE:
+    llm-info inventory [flags]
+    llm-info inventory verify [--key public.pem] FILE
+
+FLAGS:
+    --sign string        Sign the inventory with this PEM private key
+                         (Ed25519, ECDSA or RSA; PKCS#8, SEC 1 or PKCS#1)
+    --out string         Output file (default: stdout)
+    --gateways string    Comma-separated gateway names to include
+                         (default: all configured gateways)
+    --timeout duration   Request timeout for each gateway (default: 30s)
+    --config string      Path to config file
+    --verbose            Show the underlying error for every failed gateway
+                         (identical errors are otherwise shown once with a count)
+    --quiet              Do not show progress
+    --help               Show help for inventory command
+
+VERIFY FLAGS:
+    --key string         Trusted PEM public key (or the private key); without
+                         it the key embedded in the inventory is used
+
+EXAMPLES:
+    # Signed inventory for the compliance archive
+    openssl genpkey -algorithm ed25519 -out inventory-key.pem
+    llm-info inventory --sign inventory-key.pem --out inventory-2026-10-16.json
+
+    # Check that an archived inventory was not modified
+    openssl pkey -in inventory-key.pem -pubout -out inventory-key.pub.pem
+    llm-info inventory verify --key inventory-key.pub.pem inventory-2026-10-16.json
+
+DESCRIPTION:
+    Fetches the model list of each gateway and writes one JSON document with
+    the generation time (UTC), the llm-info version and, per gateway, the
+    fetch time, the SHA-256 and size of every raw response the list was built
+    from, and each model with its provider, mode, max_tokens, input_cost and
+    a SHA-256 of the fields the gateway returned for it. Gateways that cannot
+    be reached are recorded with their error; the command fails only when no
+    gateway could be read. API keys are never written to the inventory.
+
+    With --sign, the signature covers the whole document except the
+    signature block, which holds the algorithm, the public key and a key ID.
+    inventory verify exits non-zero if any field was changed after signing or
+    if the inventory was signed by a key other than --key.`)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/latency"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/storage"
+	"github.com/armaniacs/llm-info/internal/ui"
+)
+
+// measurementFields は探索結果を参照する--columns、--filter、--sortのフィールド名に含まれる文字列
+var measurementFields = []string{"measured_", "latency_p50", "probed_at"}
+
+// usesMeasurements は--columns、--filter、--sortで探索結果（measured_*、latency_p50、probed_at）を参照しているかを返す
+// --columns allの場合も探索結果を表示する
+func usesMeasurements(resolvedConfig *internalConfig.ResolvedConfig) bool {
+	if resolvedConfig.Columns == ui.AllColumns {
+		return true
+	}
+	for _, option := range []string{resolvedConfig.Columns, resolvedConfig.Filter, resolvedConfig.SortBy} {
+		for _, field := range measurementFields {
+			if strings.Contains(option, field) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyMeasurements は保存済みの探索結果をモデルのメタデータに付加する
+// 探索結果はモデルのプロバイダー名とモデルIDで探し、--dedupeでまとめた元のIDも対象にする
+func applyMeasurements(configManager *internalConfig.Manager, resolvedConfig *internalConfig.ResolvedConfig, models []model.Model) {
+	dir, index, ok := openResultIndex(configManager)
+	if !ok {
+		return
+	}
+
+	detector := providerDetector(configManager)
+	measurements := make(map[string]model.Measurement)
+	for _, m := range models {
+		provider := modelProvider(detector, resolvedConfig, m)
+		names := append([]string{m.Name}, m.Variants...)
+		var measurement model.Measurement
+		var latencies []time.Duration
+		for _, resultType := range []string{storage.ResultTypeContextWindow, storage.ResultTypeMaxOutput} {
+			saved, savedAt := latestSavedResult(index, dir, provider, names, resultType)
+			if saved == nil {
+				continue
+			}
+			if savedAt.After(measurement.MeasuredAt) {
+				measurement.MeasuredAt = savedAt
+			}
+			// 失敗した探索でも、試行のレイテンシはゲートウェイの応答の速さとして使える
+			latencies = append(latencies, saved.TrialLatencies(resultType)...)
+			value, ok := saved.Value(resultType)
+			if !ok {
+				continue
+			}
+			if resultType == storage.ResultTypeContextWindow {
+				measurement.ContextWindow = value
+			} else {
+				measurement.MaxOutput = value
+			}
+		}
+		measurement.LatencyP50 = latency.Summarize(latencies).Median
+		if !measurement.MeasuredAt.IsZero() {
+			measurements[m.Name] = measurement
+		}
+	}
+	model.ApplyMeasurements(models, measurements)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/notes"
+	"github.com/armaniacs/llm-info/internal/storage"
+	"github.com/armaniacs/llm-info/internal/ui"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["note"] = noteCommand
+}
+
+// noteActions はnoteコマンドの操作
+var noteActions = map[string]bool{"add": true, "remove": true, "tag": true, "untag": true, "list": true}
+
+// noteCommand はモデルにメモとタグを付け、ローカルのファイルに保存する
+func noteCommand(args []string) error {
+	action := "list"
+	if len(args) > 0 && noteActions[args[0]] {
+		action, args = args[0], args[1:]
+	}
+
+	noteCmd := flag.NewFlagSet("note "+action, flag.ExitOnError)
+	outputFormat := noteCmd.String("format", "table", "Output format for list (table, json)")
+	configFile := noteCmd.String("config", "", "Path to config file")
+	showHelp := noteCmd.Bool("help", false, "Show help for note command")
+
+	noteCmd.Parse(args)
+
+	if *showHelp {
+		showNoteHelp()
+		return nil
+	}
+
+	path := notesFilePath(loadProbeConfigManager(*configFile))
+	stored, err := notes.Load(path)
+	if err != nil {
+		return err
+	}
+
+	modelID := noteCmd.Arg(0)
+	rest := noteCmd.Args()
+	if len(rest) > 0 {
+		rest = rest[1:]
+	}
+
+	var message string
+	switch action {
+	case "list":
+		if *outputFormat != "table" && *outputFormat != "json" {
+			return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+		}
+		if noteCmd.NArg() > 1 {
+			return fmt.Errorf("note list takes at most one model ID")
+		}
+		return printNotes(stored, modelID, *outputFormat)
+	case "add":
+		if modelID == "" || len(rest) == 0 {
+			return fmt.Errorf("usage: llm-info note add MODEL TEXT")
+		}
+		if err := stored.AddNote(modelID, strings.Join(rest, " ")); err != nil {
+			return err
+		}
+		message = fmt.Sprintf("Added note #%d to %s", len(stored.Models[modelID].Notes), modelID)
+	case "remove":
+		if modelID == "" || len(rest) > 1 {
+			return fmt.Errorf("usage: llm-info note remove MODEL [NUMBER]")
+		}
+		index := 0
+		if len(rest) == 1 {
+			index, err = strconv.Atoi(rest[0])
+			if err != nil || index < 1 {
+				return fmt.Errorf("invalid note number: %s (see llm-info note list %s)", rest[0], modelID)
+			}
+		}
+		if err := stored.RemoveNote(modelID, index); err != nil {
+			return err
+		}
+		message = fmt.Sprintf("Removed notes from %s", modelID)
+		if index > 0 {
+			message = fmt.Sprintf("Removed note #%d from %s", index, modelID)
+		}
+	case "tag":
+		if modelID == "" || len(rest) == 0 {
+			return fmt.Errorf("usage: llm-info note tag MODEL TAG...")
+		}
+		if err := stored.AddTags(modelID, rest...); err != nil {
+			return err
+		}
+		message = fmt.Sprintf("Tagged %s: %s", modelID, strings.Join(stored.Models[modelID].Tags, ", "))
+	case "untag":
+		if modelID == "" || len(rest) == 0 {
+			return fmt.Errorf("usage: llm-info note untag MODEL TAG...")
+		}
+		stored.RemoveTags(modelID, rest...)
+		message = fmt.Sprintf("Removed tags from %s", modelID)
+	}
+
+	if err := stored.Save(path); err != nil {
+		return err
+	}
+	fmt.Println(message)
+	return nil
+}
+
+// notesFilePath はメモファイルのパスを返す（storage.notes_file、未設定ならデフォルト）
+func notesFilePath(configManager *internalConfig.Manager) string {
+	path := configManager.GetStorageConfig().NotesFile
+	if path == "" {
+		return notes.GetDefaultNotesFile()
+	}
+	if expanded, err := storage.ExpandPath(path); err == nil {
+		return expanded
+	}
+	return path
+}
+
+// applyNotes はllm-info noteで付けたメモとタグをモデルのメタデータに付加する
+// 読み込みに失敗しても一覧の表示は続ける
+func applyNotes(configManager *internalConfig.Manager, models []model.Model) {
+	stored, err := notes.Load(notesFilePath(configManager))
+	if err != nil {
+		ui.Warnf("Warning: %v", err)
+		return
+	}
+	model.ApplyAnnotations(models, stored.Annotations())
+}
+
+// printNotes はメモとタグを表示する。modelIDを指定した場合はそのモデルのみ表示する
+func printNotes(stored *notes.Notes, modelID, format string) error {
+	ids := stored.ModelIDs()
+	if modelID != "" {
+		ids = nil
+		if _, ok := stored.Models[modelID]; ok {
+			ids = []string{modelID}
+		}
+	}
+
+	if format == "json" {
+		selected := make(map[string]*notes.Entry, len(ids))
+		for _, id := range ids {
+			selected[id] = stored.Models[id]
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(selected); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return nil
+	}
+
+	if len(ids) == 0 {
+		fmt.Fprintln(os.Stderr, "No notes (add one with llm-info note add MODEL TEXT)")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tTAGS\t#\tNOTE\tADDED")
+	for _, id := range ids {
+		entry := stored.Models[id]
+		tags := strings.Join(entry.Tags, ", ")
+		if tags == "" {
+			tags = "-"
+		}
+		if len(entry.Notes) == 0 {
+			fmt.Fprintf(w, "%s\t%s\t-\t-\t-\n", id, tags)
+			continue
+		}
+		for i, note := range entry.Notes {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", id, tags, i+1, note.Text, note.CreatedAt.Local().Format("2006-01-02"))
+			id, tags = "", ""
+		}
+	}
+	return w.Flush()
+}
+
+// showNoteHelp はnoteコマンドのヘルプを表示する
+func showNoteHelp() {
+	fmt.Println(`llm-info note - Attach notes and tags to models
+
+USAGE:
+    llm-info note add [flags] MODEL TEXT
+    llm-info note remove [flags] MODEL [NUMBER]
+    llm-info note tag [flags] MODEL TAG...
+    llm-info note untag [flags] MODEL TAG...
+    llm-info note [list] [flags] [MODEL]
+
+FLAGS:
+    --format string   Output format for list: table, json (default: table)
+    --config string   Path to config file
+    --help            Show help for note command
+
+EXAMPLES:
+    # Record a decision next to the model
+    llm-info note add gpt-4o "approved for PII workloads"
+    llm-info note tag gpt-4o approved
+
+    # Show notes and tags in the model list
+    llm-info --columns name,max_tokens,tags,notes
+
+    # List only approved models
+    llm-info --filter tag:approved
+
+    # Remove the second note, or all notes of a model
+    llm-info note remove gpt-4o 2
+    llm-info note remove gpt-4o
+
+DESCRIPTION:
+    Notes and tags are stored locally in storage.notes_file
+    (default: ~/.config/llm-info/notes.json), keyed by model ID.
+    Models collapsed with --dedupe show the notes and tags of all their
+    original IDs. Tags are matched case-insensitively and cannot contain
+    commas or spaces.`)
+}
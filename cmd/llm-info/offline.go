@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/internal/cache"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	errhandler "github.com/armaniacs/llm-info/internal/error"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/storage"
+	"github.com/armaniacs/llm-info/internal/ui"
+)
+
+// catalogCache は設定されたディレクトリのカタログキャッシュを返す
+func catalogCache(configManager *internalConfig.Manager) (*cache.CatalogCache, error) {
+	dir := configManager.GetStorageConfig().CacheDir
+	if dir == "" {
+		dir = cache.GetDefaultCacheDir()
+	}
+	dir, err := storage.ExpandPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	return cache.NewCatalogCache(dir), nil
+}
+
+// saveCatalogCache はオンラインで取得したモデル一覧を--offline用に保存する
+func saveCatalogCache(configManager *internalConfig.Manager, resolvedConfig *internalConfig.ResolvedConfig, models []api.ModelInfo, verbose bool) {
+	catalog, err := catalogCache(configManager)
+	if err == nil {
+		err = catalog.Save(resolvedConfig.Gateway.Name, resolvedConfig.Gateway.URL, models)
+	}
+	if err != nil && verbose {
+		ui.Warnf("Warning: failed to cache model catalog: %v", err)
+	}
+}
+
+// loadCatalogCache はキャッシュ済みのモデル一覧を読み込み、データの鮮度を表示する
+func loadCatalogCache(configManager *internalConfig.Manager, resolvedConfig *internalConfig.ResolvedConfig) (*cache.CatalogEntry, error) {
+	catalog, err := catalogCache(configManager)
+	if err != nil {
+		return nil, errhandler.CreateSystemError("unexpected_error", "catalog cache", err)
+	}
+
+	entry, err := catalog.Load(resolvedConfig.Gateway.URL)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errhandler.CreateUserError("offline_cache_not_found", "--offline", err)
+		}
+		return nil, errhandler.CreateSystemError("unexpected_error", "catalog cache", err)
+	}
+
+	// JSON出力を壊さないよう、鮮度の表示は標準エラー出力に出す
+	ui.Infof("📦 Offline mode: showing cached catalog fetched %s (%s)\n\n",
+		cache.FormatAge(entry.Age()), entry.FetchedAt.Local().Format("2006-01-02 15:04:05"))
+
+	return entry, nil
+}
+
+// findLatestEntry はいずれかのモデルIDで保存された最新の探索結果のインデックスを返す
+// providerが空の場合はプロバイダーを問わない
+func findLatestEntry(index *storage.Index, provider string, modelNames []string, resultType string) *storage.IndexEntry {
+	var latest *storage.IndexEntry
+	for _, name := range modelNames {
+		entries := index.Find(storage.IndexQuery{Provider: provider, Model: name, Type: resultType, Limit: 1})
+		if len(entries) > 0 && (latest == nil || entries[0].SavedAt.After(latest.SavedAt)) {
+			latest = &entries[0]
+		}
+	}
+	return latest
+}
+
+// printCachedProbeResults は表示中のモデルについて保存済みの探索結果を表示する
+func printCachedProbeResults(configManager *internalConfig.Manager, resolvedConfig *internalConfig.ResolvedConfig, models []model.Model) {
+	dir, index, ok := openResultIndex(configManager)
+	if !ok {
+		return
+	}
+
+	detector := providerDetector(configManager)
+
+	var rows []string
+	for _, m := range models {
+		provider := modelProvider(detector, resolvedConfig, m)
+		// --dedupeでまとめたモデルは元のIDで保存された結果も対象にする
+		names := append([]string{m.Name}, m.Variants...)
+		contextValue, contextAt := latestResultValue(index, dir, provider, names, storage.ResultTypeContextWindow)
+		outputValue, outputAt := latestResultValue(index, dir, provider, names, storage.ResultTypeMaxOutput)
+		if contextAt.IsZero() && outputAt.IsZero() {
+			continue
+		}
+
+		probedAt := contextAt
+		if outputAt.After(probedAt) {
+			probedAt = outputAt
+		}
+		rows = append(rows, fmt.Sprintf("%s\t%s\t%s\t%s", m.Name, contextValue, outputValue, cache.FormatAge(time.Since(probedAt))))
+	}
+
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Println("\nSaved probe results:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tCONTEXT WINDOW\tMAX OUTPUT\tPROBED")
+	for _, row := range rows {
+		fmt.Fprintln(w, row)
+	}
+	w.Flush()
+}
+
+// openResultIndex は探索結果のディレクトリとインデックスを開く
+// まだ探索結果が保存されていない場合はfalseを返す
+func openResultIndex(configManager *internalConfig.Manager) (string, *storage.Index, bool) {
+	dir, err := storage.ExpandPath(configManager.GetProbeConfig().Result.Dir)
+	if err != nil {
+		return "", nil, false
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return "", nil, false
+	}
+	index, err := storage.OpenIndex(dir)
+	if err != nil {
+		ui.Warnf("Warning: failed to open result index: %v", err)
+		return "", nil, false
+	}
+	return dir, index, true
+}
+
+// latestResultValue はいずれかのモデルIDで保存された最新の探索結果の値と保存時刻を返す
+func latestResultValue(index *storage.Index, dir, provider string, modelNames []string, resultType string) (string, time.Time) {
+	value, ok, savedAt := latestResult(index, dir, provider, modelNames, resultType)
+	switch {
+	case savedAt.IsZero():
+		return "-", savedAt
+	case !ok:
+		return "failed", savedAt
+	}
+	return fmt.Sprintf("%d", value), savedAt
+}
+
+// latestResult はいずれかのモデルIDで保存された最新の探索結果を読み込む
+// プロバイダー名で見つからない場合は、探索時にレスポンスヘッダーから判定した名前や
+// 以前の判定で保存された結果も対象にするため、プロバイダーを問わずに探す
+// 結果がない場合は保存時刻がゼロ値、探索に失敗した結果の場合はokがfalseになる
+func latestResult(index *storage.Index, dir, provider string, modelNames []string, resultType string) (int, bool, time.Time) {
+	saved, savedAt := latestSavedResult(index, dir, provider, modelNames, resultType)
+	if saved == nil {
+		return 0, false, time.Time{}
+	}
+	value, ok := saved.Value(resultType)
+	return value, ok, savedAt
+}
+
+// latestSavedResult はlatestResultと同じ方法で探した最新の探索結果と保存時刻を返す（結果がない場合はnil）
+func latestSavedResult(index *storage.Index, dir, provider string, modelNames []string, resultType string) (*storage.SavedResult, time.Time) {
+	latest := findLatestEntry(index, provider, modelNames, resultType)
+	if latest == nil && provider != "" {
+		latest = findLatestEntry(index, "", modelNames, resultType)
+	}
+	if latest == nil {
+		return nil, time.Time{}
+	}
+
+	saved, err := storage.ReadIndexedResult(dir, *latest)
+	if err != nil {
+		return nil, time.Time{}
+	}
+	return saved, latest.SavedAt
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/ui"
+)
+
+// needsOnboarding は接続先が何も設定されていない（設定ファイル、--url、LLM_INFO_URL、
+// 組織の共有設定のいずれもない）場合にtrueを返す
+func needsOnboarding(configManager *internalConfig.Manager, configPath, url string) bool {
+	if url != "" || os.Getenv("LLM_INFO_URL") != "" || configManager.OrgConfigURL() != "" {
+		return false
+	}
+	_, err := os.Stat(configPath)
+	return os.IsNotExist(err)
+}
+
+// printOnboarding は初回起動時の始め方を標準エラー出力に表示する
+// --no-hintsと--quietでは1行のエラーのみ表示する
+func printOnboarding(configPath string) {
+	if !ui.HintsEnabled() {
+		fmt.Fprintf(os.Stderr, "Error: no gateway configured (no config file at %s, no --url or LLM_INFO_URL)\n", configPath)
+		return
+	}
+	fmt.Fprintf(os.Stderr, `No gateway is configured yet (no config file at %s).
+
+Get started in one of these ways:
+
+  1. Set up a gateway interactively (writes the config file):
+       llm-info init
+
+  2. Use environment variables:
+       export LLM_INFO_URL=https://litellm.example.com
+       export LLM_INFO_API_KEY=your-api-key
+       llm-info
+
+  3. Pass the gateway on the command line:
+       llm-info --url https://litellm.example.com --api-key your-api-key
+
+See llm-info --help-topic config for the config file format.
+Use --no-hints to print only a one-line error (for scripts).
+`, configPath)
+}
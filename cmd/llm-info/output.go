@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// streamingCommands は出力が終わらないため--outputを使えないサブコマンド
+var streamingCommands = map[string]bool{
+	"chat":   true,
+	"daemon": true,
+}
+
+// capturedOutput は--outputで標準出力を差し替えているときの状態
+type capturedOutput struct {
+	path    string
+	stdout  *os.File
+	writer  *os.File
+	done    chan struct{}
+	buf     bytes.Buffer
+	partial bool // 結果を出力し終えてから失敗した（終了コードが0でなくても書き出す）
+}
+
+// stdoutCapture は--output指定時の標準出力の差し替え（未指定ならnil）
+var stdoutCapture *capturedOutput
+
+// extractOutputFlag は引数から--outputとその値を取り除く
+// --outputは全コマンド共通のため、各コマンドのフラグ解析より前に取り出す
+func extractOutputFlag(args []string) (string, []string, error) {
+	var path string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "output" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("--output requires a file path")
+			}
+			i++
+			value = args[i]
+		}
+		if value == "" {
+			return "", nil, fmt.Errorf("--output requires a file path")
+		}
+		path = value
+	}
+	return path, rest, nil
+}
+
+// captureStdout は標準出力をパイプに差し替え、書き込まれた内容をメモリに溜める
+// 終了コード0で終了したときだけfinishOutputでファイルに書き出すため、途中で失敗しても既存のファイルは壊れない
+func captureStdout(path string) error {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to capture output: %w", err)
+	}
+	c := &capturedOutput{path: path, stdout: os.Stdout, writer: w, done: make(chan struct{})}
+	go func() {
+		io.Copy(&c.buf, r)
+		r.Close()
+		close(c.done)
+	}()
+	os.Stdout = w
+	stdoutCapture = c
+	return nil
+}
+
+// keepPartialOutput は結果を出力し終えた後の失敗（--continue-on-errorでの一部のモデルの失敗）として、
+// 終了コードが0でなくても--outputのファイルに書き出すようにする
+func keepPartialOutput() {
+	if stdoutCapture != nil {
+		stdoutCapture.partial = true
+	}
+}
+
+// finishOutput は標準出力を元に戻し、終了コードが0の場合（またはkeepPartialOutputを呼んだ場合）だけ
+// 溜めた内容を--outputのファイルに書き出す
+// 書き出しに失敗した場合は1を返す
+func finishOutput(code int) int {
+	c := stdoutCapture
+	if c == nil {
+		return code
+	}
+	stdoutCapture = nil
+	os.Stdout = c.stdout
+	c.writer.Close()
+	<-c.done
+
+	if code != 0 && !c.partial {
+		fmt.Fprintf(os.Stderr, "%s was not written because the command failed\n", c.path)
+		return code
+	}
+	if err := writeFileAtomic(c.path, c.buf.Bytes()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if code != 0 {
+		fmt.Fprintf(os.Stderr, "%s was written with the results of the models that succeeded\n", c.path)
+	}
+	return code
+}
+
+// exitCodes は設定ファイルのexit_codes（設定ファイルを読み込んだときに設定する）
+var exitCodes config.ExitCodes
+
+// exit は--outputの書き出しを済ませてから終了する
+// 終了コードにはexit_codesのmax（上限）を適用する
+func exit(code int) {
+	os.Exit(exitCodes.Limit(finishOutput(code)))
+}
+
+// atomicFile は出力先と同じディレクトリの一時ファイルに書き込み、Commitで出力先と置き換える
+type atomicFile struct {
+	*os.File
+	path string
+}
+
+// createAtomicFile は親ディレクトリを作成し、pathを置き換えるための一時ファイルを作成する
+func createAtomicFile(path string) (*atomicFile, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	return &atomicFile{File: file, path: path}, nil
+}
+
+// Commit は一時ファイルを閉じて出力先に置き換える
+func (f *atomicFile) Commit() error {
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to write %s: %w", f.path, err)
+	}
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to write %s: %w", f.path, err)
+	}
+	if err := os.Rename(f.Name(), f.path); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to write %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// Abort は一時ファイルを削除し、出力先を変更しない
+func (f *atomicFile) Abort() {
+	f.Close()
+	os.Remove(f.Name())
+}
+
+// writeFileAtomic はdataを一時ファイルに書き込んでからpathに置き換える
+func writeFileAtomic(path string, data []byte) error {
+	file, err := createAtomicFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Abort()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Commit()
+}
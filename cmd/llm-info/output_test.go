@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFinishOutput_ContinueOnError(t *testing.T) {
+	dir := t.TempDir()
+
+	// 一部のモデルだけが失敗した場合は、終了コード1でも成功した結果を書き出す
+	partialPath := filepath.Join(dir, "partial.json")
+	if err := captureStdout(partialPath); err != nil {
+		t.Fatal(err)
+	}
+	fmt.Print(`[{"model":"gpt-4o"},{"model":"broken","error":"connection refused"}]`)
+	err := batchFailureError([]string{"broken"}, 2)
+	var partial *partialFailureError
+	if !errors.As(err, &partial) {
+		t.Fatalf("batchFailureError() = %T, want *partialFailureError", err)
+	}
+	keepPartialOutput()
+	if code := finishOutput(1); code != 1 {
+		t.Errorf("finishOutput() = %d, want 1", code)
+	}
+	data, readErr := os.ReadFile(partialPath)
+	if readErr != nil {
+		t.Fatalf("partial results were not written: %v", readErr)
+	}
+	if want := `[{"model":"gpt-4o"},{"model":"broken","error":"connection refused"}]`; string(data) != want {
+		t.Errorf("written = %s, want %s", data, want)
+	}
+
+	// それ以外の失敗では書き出さず、既存のファイルを残す
+	failedPath := filepath.Join(dir, "failed.json")
+	if err := os.WriteFile(failedPath, []byte("previous"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := captureStdout(failedPath); err != nil {
+		t.Fatal(err)
+	}
+	fmt.Print("incomplete")
+	if code := finishOutput(1); code != 1 {
+		t.Errorf("finishOutput() = %d, want 1", code)
+	}
+	if data, _ := os.ReadFile(failedPath); string(data) != "previous" {
+		t.Errorf("file = %q, want the previous content to be kept", data)
+	}
+}
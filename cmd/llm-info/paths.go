@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	errhandler "github.com/armaniacs/llm-info/internal/error"
+	"github.com/armaniacs/llm-info/internal/lock"
+	"github.com/armaniacs/llm-info/internal/paths"
+	"github.com/armaniacs/llm-info/internal/storage"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["paths"] = pathsCommand
+}
+
+// pathLocation はllm-infoが読み書きする場所とその決め方
+type pathLocation struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Source string `json:"source"` // 設定ファイルのキー、--config、default
+}
+
+// pathsReport はllm-info pathsの出力
+type pathsReport struct {
+	Directories []paths.Dir    `json:"directories"`
+	Locations   []pathLocation `json:"locations"`
+}
+
+// pathsCommand は設定・キャッシュ・状態のディレクトリと、各ファイルの解決済みの場所を表示する
+func pathsCommand(args []string) error {
+	pathsCmd := flag.NewFlagSet("paths", flag.ExitOnError)
+	configFile := pathsCmd.String("config", "", "Path to config file")
+	outputFormat := pathsCmd.String("format", "table", "Output format (table, json)")
+	showHelp := pathsCmd.Bool("help", false, "Show help for paths command")
+
+	pathsCmd.Parse(args)
+
+	if *showHelp {
+		showPathsHelp()
+		return nil
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	storageConfig := configManager.GetStorageConfig()
+	probeConfig := configManager.GetProbeConfig()
+
+	configPath, configSource := *configFile, "--config"
+	if configPath == "" {
+		configPath, configSource = internalConfig.GetDefaultConfigPath(), "default"
+	}
+
+	report := pathsReport{
+		Directories: paths.Dirs(),
+		Locations: []pathLocation{
+			{"config file", configPath, configSource},
+			{"results", probeConfig.Result.Dir, sourceOf(storageConfig.ResultDir, "storage.result_dir")},
+			{"logs", probeConfig.Log.Dir, sourceOf(storageConfig.LogDir, "storage.log_dir")},
+			{"cache", orDefault(storageConfig.CacheDir, paths.CacheDir()), sourceOf(storageConfig.CacheDir, "storage.cache_dir")},
+			{"locks", orDefault(storageConfig.LockDir, lock.GetDefaultLockDir()), sourceOf(storageConfig.LockDir, "storage.lock_dir")},
+			{"notes", notesFilePath(configManager), sourceOf(storageConfig.NotesFile, "storage.notes_file")},
+			{"stats", newStatsRecorder(configManager).Path(), sourceOf(configManager.GetStatsConfig().File, "stats.file")},
+			{"crash reports", errhandler.GetDefaultCrashDir(), "default"},
+		},
+	}
+	for i := range report.Locations {
+		if expanded, err := storage.ExpandPath(report.Locations[i].Path); err == nil {
+			report.Locations[i].Path = expanded
+		}
+	}
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DIRECTORY\tPATH\tSOURCE")
+	for _, dir := range report.Directories {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", dir.Kind, dir.Path, dir.Source)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "LOCATION\tPATH\tSOURCE")
+	for _, location := range report.Locations {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", location.Name, location.Path, location.Source)
+	}
+	return w.Flush()
+}
+
+// sourceOf は設定ファイルで指定された場合はそのキーを、そうでなければdefaultを返す
+func sourceOf(value, key string) string {
+	if value != "" {
+		return key
+	}
+	return "default"
+}
+
+// orDefault は値が空ならデフォルト値を返す
+func orDefault(value, defaultValue string) string {
+	if value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// showPathsHelp はpathsコマンドのヘルプを表示する
+func showPathsHelp() {
+	fmt.Println(`llm-info paths - Show where llm-info reads and writes its files
+
+USAGE:
+    llm-info paths [flags]
+
+FLAGS:
+    --config string   Path to config file
+    --format string   Output format: table, json (default: table)
+    --help            Show help for paths command
+
+DESCRIPTION:
+    Files are split into three directories:
+
+      config  Config file and notes
+              $XDG_CONFIG_HOME/llm-info, %AppData%\llm-info on Windows
+              (default: ~/.config/llm-info)
+      cache   Cached model catalogs and shared org config
+              $XDG_CACHE_HOME/llm-info, %LocalAppData%\llm-info\cache on Windows
+              (default: ~/.cache/llm-info)
+      state   Probe results, logs, locks, usage stats and crash reports
+              $XDG_STATE_HOME/llm-info, %LocalAppData%\llm-info\state on Windows
+              (default: ~/.local/state/llm-info)
+
+    LLM_INFO_CONFIG_DIR, LLM_INFO_CACHE_DIR and LLM_INFO_STATE_DIR override
+    each directory. Keys in the storage section of the config file override
+    individual locations.
+
+    Earlier versions kept everything in ~/.config/llm-info. Files that exist
+    only there are still used (SOURCE: legacy for the cache directory), so
+    saved results are not lost; move them to the new location to switch.
+
+EXAMPLES:
+    llm-info paths
+    llm-info paths --format json`)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["pin"] = pinCommand
+}
+
+// pinCommand はよく使うモデルを設定ファイルのpinnedにピン留めする
+func pinCommand(args []string) error {
+	action := "list"
+	if len(args) > 0 && (args[0] == "add" || args[0] == "remove" || args[0] == "list") {
+		action, args = args[0], args[1:]
+	}
+
+	pinCmd := flag.NewFlagSet("pin "+action, flag.ExitOnError)
+	configFile := pinCmd.String("config", "", "Path to config file")
+	showHelp := pinCmd.Bool("help", false, "Show help for pin command")
+
+	pinCmd.Parse(args)
+
+	if *showHelp {
+		showPinHelp()
+		return nil
+	}
+
+	configPath := *configFile
+	if configPath == "" {
+		configPath = internalConfig.GetDefaultConfigPath()
+	}
+	pinned := loadProbeConfigManager(configPath).GetPinnedModels()
+
+	if action == "list" {
+		if pinCmd.NArg() > 0 {
+			return fmt.Errorf("unknown pin action: %s (valid: add, remove, list)", pinCmd.Arg(0))
+		}
+		for _, id := range pinned {
+			fmt.Println(id)
+		}
+		if len(pinned) == 0 {
+			fmt.Fprintln(os.Stderr, "No pinned models (add one with llm-info pin add MODEL)")
+		}
+		return nil
+	}
+
+	if pinCmd.NArg() == 0 {
+		return fmt.Errorf("pin %s requires at least one model ID", action)
+	}
+	if _, err := os.Stat(configPath); err != nil {
+		return fmt.Errorf("config file %s not found; create it with llm-info init before pinning models", configPath)
+	}
+
+	// 読み込みから書き込みまで設定ファイルのロックを保持し、同時に実行されたpinの変更も反映する
+	var messages []string
+	err := internalConfig.UpdatePinnedInFile(configPath, func(current []string) []string {
+		updated := append([]string(nil), current...)
+		messages = nil
+		for _, id := range pinCmd.Args() {
+			index := indexOf(updated, id)
+			switch {
+			case action == "add" && index < 0:
+				updated = append(updated, id)
+				messages = append(messages, "Pinned "+id)
+			case action == "add":
+				messages = append(messages, id+" is already pinned")
+			case index >= 0:
+				updated = append(updated[:index], updated[index+1:]...)
+				messages = append(messages, "Unpinned "+id)
+			default:
+				messages = append(messages, id+" is not pinned")
+			}
+		}
+		return updated
+	})
+	if err != nil {
+		return err
+	}
+	for _, message := range messages {
+		fmt.Println(message)
+	}
+	return nil
+}
+
+// withPinnedFilter はフィルタ条件にピン留めしたモデルのみの条件（pinned）を加える
+func withPinnedFilter(filter string) string {
+	if filter == "" {
+		return "pinned"
+	}
+	return filter + ",pinned"
+}
+
+// indexOf はスライス内の値の位置を返す（ない場合は-1）
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// showPinHelp はpinコマンドのヘルプを表示する
+func showPinHelp() {
+	fmt.Println(`llm-info pin - Pin frequently used models
+
+USAGE:
+    llm-info pin add [flags] MODEL...
+    llm-info pin remove [flags] MODEL...
+    llm-info pin [list] [flags]
+
+FLAGS:
+    --config string   Path to config file
+    --help            Show help for pin command
+
+EXAMPLES:
+    # Pin a model
+    llm-info pin add gpt-4o
+
+    # List pinned models first, then by max tokens
+    llm-info --sort pinned,-max_tokens
+
+    # List only pinned models
+    llm-info --pinned
+
+DESCRIPTION:
+    Pinned model IDs are stored in the pinned list of the config file.
+    Models collapsed with --dedupe are pinned when any of their original
+    IDs is pinned. Use the pinned column, the pinned filter or the pinned
+    sort field to surface them in the model list.`)
+}
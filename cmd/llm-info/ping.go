@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/latency"
+	"github.com/armaniacs/llm-info/internal/ui"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["ping"] = pingCommand
+}
+
+// pingCommand はモデルを呼び出さずにゲートウェイまでの接続遅延を計測する
+func pingCommand(args []string) error {
+	pingCmd := flag.NewFlagSet("ping", flag.ExitOnError)
+	allGateways := pingCmd.Bool("all-gateways", false, "Measure every gateway in the config file")
+	gatewayName := pingCmd.String("gateway", "", "Gateway name to use from config")
+	url := pingCmd.String("url", "", "Measure this gateway URL instead of the configured gateways")
+	count := pingCmd.Int("count", 5, "Number of connections per gateway")
+	interval := pingCmd.Duration("interval", 200*time.Millisecond, "Wait between connections")
+	timeout := pingCmd.Duration("timeout", 10*time.Second, "Timeout for each connection")
+	outputFormat := pingCmd.String("format", "table", "Output format (table, json)")
+	configFile := pingCmd.String("config", "", "Path to config file")
+	showHelp := pingCmd.Bool("help", false, "Show help for ping command")
+
+	pingCmd.Parse(args)
+
+	if *showHelp {
+		showPingHelp()
+		return nil
+	}
+
+	if *count < 1 {
+		return fmt.Errorf("--count must be at least 1")
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+
+	var gateways []*config.GatewayConfig
+	if *allGateways || *url != "" || *gatewayName != "" {
+		var err error
+		gateways, err = doctorGateways(configManager, *gatewayName, *url, "", *timeout)
+		if err != nil {
+			return err
+		}
+	} else {
+		resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{OutputFormat: "json"})
+		if err != nil {
+			return fmt.Errorf("failed to resolve config: %w", err)
+		}
+		gateways = []*config.GatewayConfig{{Name: resolved.Gateway.Name, URL: resolved.Gateway.URL}}
+	}
+	if len(gateways) == 0 {
+		return fmt.Errorf("no gateways to measure; add one with llm-info init or pass --url")
+	}
+
+	pinger := latency.NewPinger(*count, *interval, *timeout)
+	var results []*latency.Result
+	for _, gw := range gateways {
+		if *outputFormat == "table" {
+			fmt.Fprintf(os.Stderr, "Measuring %s (%d connections)...\n", gw.Name, *count)
+		}
+		results = append(results, pinger.Ping(gw.Name, gw.URL))
+	}
+	latency.SortByFirstByte(results)
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+
+	printPingResults(results)
+	for _, result := range results {
+		if result.Reachable() {
+			return nil
+		}
+	}
+	return fmt.Errorf("no gateway was reachable")
+}
+
+// printPingResults はゲートウェイごとの遅延を近い順に表示する
+func printPingResults(results []*latency.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "GATEWAY\tURL\tDNS\tCONNECT\tTLS\tFIRST BYTE\tMIN/MAX\tLOSS")
+	for _, result := range results {
+		loss := fmt.Sprintf("%d/%d", result.Failed, result.Sent)
+		if !result.Reachable() {
+			fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t-\t-\t%s\n", result.Gateway, ui.MaskURL(result.URL), loss)
+			continue
+		}
+		tlsTime := "-"
+		if result.TLS.Median > 0 {
+			tlsTime = formatLatency(result.TLS.Median)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s/%s\t%s\n",
+			result.Gateway, ui.MaskURL(result.URL),
+			formatLatency(result.DNS.Median), formatLatency(result.Connect.Median), tlsTime,
+			formatLatency(result.FirstByte.Median),
+			formatLatency(result.FirstByte.Min), formatLatency(result.FirstByte.Max),
+			loss)
+	}
+	w.Flush()
+
+	for _, result := range results {
+		if result.Failed > 0 {
+			fmt.Printf("\n%s: %s\n", result.Gateway, result.LastError())
+		}
+	}
+	if len(results) > 1 && results[0].Reachable() {
+		fmt.Printf("\nNearest gateway: %s (median first byte %s)\n", results[0].Gateway, formatLatency(results[0].FirstByte.Median))
+	}
+}
+
+// formatLatency はミリ秒単位で小数1桁まで表示する
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d.Microseconds())/1000)
+}
+
+// showPingHelp はpingコマンドのヘルプを表示する
+func showPingHelp() {
+	fmt.Println(`llm-info ping - Measure network latency to gateways without calling a model
+
+USAGE:
+    llm-info ping [flags]
+
+FLAGS:
+    --all-gateways       Measure every gateway in the config file
+    --gateway string     Gateway name to use from config
+    --url string         Measure this gateway URL instead of the configured gateways
+    --count int          Number of connections per gateway (default: 5)
+    --interval duration  Wait between connections (default: 200ms)
+    --timeout duration   Timeout for each connection (default: 10s)
+    --format string      Output format (table, json) (default: table)
+    --config string      Path to config file
+    --help               Show help for ping command
+
+EXAMPLES:
+    # Compare every configured gateway
+    llm-info ping --all-gateways --count 5
+
+    # Measure the default gateway
+    llm-info ping
+
+    # Measure a gateway before adding it to the config file
+    llm-info ping --url https://litellm-eu.example.com
+
+DESCRIPTION:
+    Sends a GET request to the gateway base URL over a new connection each
+    time and reports the median DNS lookup, TCP connect, TLS handshake and
+    time to first byte, plus the min/max first byte. The HTTP status is
+    ignored and no API key is sent, so no model is called and nothing is
+    billed. Gateways are listed nearest first; use the result to pick the
+    endpoint for latency-sensitive workloads. LOSS counts failed connections.
+    The command exits with status 1 when no gateway is reachable.`)
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/manifest"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["preflight"] = preflightCommand
+}
+
+// preflightReport はpreflightコマンドのJSON出力
+type preflightReport struct {
+	Manifest string            `json:"manifest"`
+	Gateway  string            `json:"gateway"`
+	URL      string            `json:"url"`
+	Offline  bool              `json:"offline,omitempty"`
+	Pass     bool              `json:"pass"`
+	Models   []manifest.Result `json:"models"`
+}
+
+// preflightCommand はアプリケーションのモデルマニフェストをゲートウェイのモデル一覧と保存済みの探索結果で検査する
+// すべてのモデルが条件を満たせば0、満たさないモデルがあれば1、一覧を取得できなければexit_codesのerror（既定: 2）で終了する
+func preflightCommand(args []string) error {
+	preflightCmd := flag.NewFlagSet("preflight", flag.ExitOnError)
+	manifestFile := preflightCmd.String("manifest", "", "Path to the model manifest (YAML) (required)")
+	gateway := preflightCmd.String("gateway", "", "Gateway name to use from config, overrides the manifest's gateway")
+	baseURL := preflightCmd.String("url", "", "Base URL of the LLM gateway")
+	apiKey := preflightCmd.String("api-key", "", "API key for authentication")
+	timeout := preflightCmd.Duration("timeout", 10*time.Second, "Request timeout (default: 10s)")
+	offline := preflightCmd.Bool("offline", false, "Check the cached model catalog instead of fetching it")
+	outputFormat := preflightCmd.String("format", "table", "Output format (table, json)")
+	configFile := preflightCmd.String("config", "", "Path to config file")
+	showHelp := preflightCmd.Bool("help", false, "Show help for preflight command")
+
+	preflightCmd.Parse(args)
+
+	if *showHelp {
+		showPreflightHelp()
+		return nil
+	}
+	if *manifestFile == "" {
+		return fmt.Errorf("--manifest is required")
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+	m, err := manifest.Load(*manifestFile)
+	if err != nil {
+		return err
+	}
+
+	gatewayName := *gateway
+	if gatewayName == "" && *baseURL == "" {
+		gatewayName = m.Gateway
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+		URL:          *baseURL,
+		APIKey:       *apiKey,
+		Timeout:      *timeout,
+		Gateway:      gatewayName,
+		OutputFormat: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	// 一覧を取得できない場合は「条件を満たさない」（1）と区別できる終了コードにする
+	var catalog []model.Model
+	if *offline {
+		entry, err := loadCatalogCache(configManager, resolved)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", redact.Error(err))
+			exit(exitCodes.Resolve(config.ExitCodeError))
+		}
+		catalog = model.FromAPIResponse(entry.Models)
+	} else {
+		catalog, err = fetchAdvertisedCatalog(resolved)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", redact.Error(err))
+			exit(exitCodes.Resolve(config.ExitCodeError))
+		}
+	}
+
+	// 探索結果がある値は公表値より優先する
+	applyMeasurements(configManager, resolved, catalog)
+
+	results := m.Check(catalog)
+	report := preflightReport{
+		Manifest: *manifestFile,
+		Gateway:  resolved.Gateway.Name,
+		URL:      redact.String(resolved.Gateway.URL),
+		Offline:  *offline,
+		Pass:     manifest.Passed(results),
+		Models:   results,
+	}
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		printPreflightReport(report)
+	}
+
+	if !report.Pass {
+		exit(1)
+	}
+	return nil
+}
+
+// printPreflightReport はモデルごと・検査項目ごとのpass/failのマトリクスと、満たさなかった理由を表示する
+func printPreflightReport(report preflightReport) {
+	fmt.Printf("Checked %d models in %s against %s\n\n", len(report.Models), report.Manifest, report.Gateway)
+
+	headers := map[string]string{
+		manifest.CheckListed:     "LISTED",
+		manifest.CheckMode:       "MODE",
+		manifest.CheckContext:    "CONTEXT",
+		manifest.CheckOutput:     "OUTPUT",
+		manifest.CheckInputCost:  "INPUT COST",
+		manifest.CheckOutputCost: "OUTPUT COST",
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, "MODEL")
+	for _, check := range manifest.Checks {
+		fmt.Fprintf(w, "\t%s", headers[check])
+	}
+	fmt.Fprintln(w, "\tRESULT")
+	for _, result := range report.Models {
+		fmt.Fprint(w, result.Model)
+		for _, check := range manifest.Checks {
+			fmt.Fprintf(w, "\t%s", preflightCell(result, check))
+		}
+		if result.Pass {
+			fmt.Fprintln(w, "\tpass")
+		} else {
+			fmt.Fprintln(w, "\tFAIL")
+		}
+	}
+	w.Flush()
+
+	failed := 0
+	for _, result := range report.Models {
+		if result.Pass {
+			continue
+		}
+		if failed == 0 {
+			fmt.Println()
+		}
+		failed++
+		for _, c := range result.Checks {
+			if !c.Pass {
+				fmt.Printf("❌ %s: %s\n", result.Model, c.Detail)
+			}
+		}
+	}
+
+	fmt.Println()
+	if failed == 0 {
+		fmt.Printf("✅ All %d models meet the manifest.\n", len(report.Models))
+		return
+	}
+	fmt.Printf("⚠️  %d of %d models do not meet the manifest.\n", failed, len(report.Models))
+}
+
+// preflightCell はマトリクスのセル（条件を指定していない項目は"-"、探索結果による値には"*"）を返す
+func preflightCell(result manifest.Result, check string) string {
+	c, ok := result.Find(check)
+	if !ok {
+		return "-"
+	}
+	status := "pass"
+	if !c.Pass {
+		status = "FAIL"
+	}
+	if c.Value == "" || check == manifest.CheckListed {
+		return status
+	}
+	value := c.Value
+	if c.Source == manifest.SourceMeasured {
+		value += "*"
+	}
+	return fmt.Sprintf("%s (%s)", status, value)
+}
+
+// showPreflightHelp はpreflightコマンドのヘルプを表示する
+func showPreflightHelp() {
+	fmt.Println(`llm-info preflight - Check an application's model manifest against a gateway
+
+USAGE:
+    llm-info preflight --manifest FILE [flags]
+
+Checks that every model in the manifest is listed by the gateway and meets its
+minimum context, output, mode and cost requirements. Saved probe results take
+precedence over the values the gateway advertises (marked with * in the matrix).
+Exits with 0 when every model passes, 1 when any model fails, and 2
+(exit_codes.error) when the model list could not be fetched.
+
+FLAGS:
+    --manifest string     Path to the model manifest (YAML) (required)
+    --gateway string      Gateway name to use from config, overrides the manifest's gateway
+    --url string          Base URL of the LLM gateway
+    --api-key string      API key for authentication
+    --timeout duration    Request timeout (default: 10s)
+    --offline             Check the cached model catalog instead of fetching it
+    --format string       Output format (table, json) (default: table)
+    --config string       Path to config file
+    --help                Show help for preflight command
+
+MANIFEST:
+    gateway: production          # optional, overridden by --gateway
+    models:
+      - id: gpt-4o
+        mode: chat
+        min_context: 100000
+        min_output: 16000
+        max_input_cost_per_token: 0.000005
+        max_output_cost_per_token: 0.00002
+      - id: claude-sonnet-4
+        min_context: 150000
+        require_measured: true   # judge min_context/min_output by probe results only
+
+    min_context and min_output accept k, m and b suffixes (e.g. 128k).
+    Unknown keys are rejected so that a misspelled requirement is not ignored.
+
+EXAMPLES:
+    # Gate a deployment in CI
+    llm-info preflight --manifest models-required.yaml
+
+    # Check against another gateway
+    llm-info preflight --manifest models-required.yaml --gateway staging --format json`)
+}
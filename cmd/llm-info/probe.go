@@ -5,14 +5,20 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/armaniacs/llm-info/internal/api"
 	internalConfig "github.com/armaniacs/llm-info/internal/config"
 	"github.com/armaniacs/llm-info/internal/cost"
+	"github.com/armaniacs/llm-info/internal/ghactions"
+	"github.com/armaniacs/llm-info/internal/hooks"
 	"github.com/armaniacs/llm-info/internal/logging"
+	"github.com/armaniacs/llm-info/internal/notify"
 	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/readonly"
 	"github.com/armaniacs/llm-info/internal/storage"
 	"github.com/armaniacs/llm-info/internal/ui"
 	"github.com/armaniacs/llm-info/pkg/config"
@@ -29,25 +35,37 @@ func init() {
 func probeCommand(args []string) error {
 	// probeコマンド用のフラグを定義
 	probeCmd := flag.NewFlagSet("probe", flag.ExitOnError)
-	model := probeCmd.String("model", "", "Target model ID (required)")
+	model := probeCmd.String("model", "", "Target model ID, or a wildcard group such as openai/* (default: default_model of the gateway)")
+	representative := probeCmd.String("representative", "", "Model ID to probe for a wildcard --model (default: chosen from the model list)")
 	baseURL := probeCmd.String("url", "", "Base URL of the LLM gateway")
 	apiKey := probeCmd.String("api-key", "", "API key for authentication")
 	gateway := probeCmd.String("gateway", "", "Gateway name to use from config")
-	timeout := probeCmd.Duration("timeout", 30*time.Second, "Request timeout (default: 30s)")
-	dryRun := probeCmd.Bool("dry-run", false, "Show execution plan without making actual API calls")
+	timeout := probeCmd.Duration("timeout", 30*time.Second, "Request timeout, overrides timeouts.probe (default: 30s)")
+	dryRun := probeCmd.Bool("dry-run", false, "Show execution plan and estimated API calls, tokens and cost without making API calls")
 	verbose := probeCmd.Bool("verbose", false, "Show verbose logs")
 	configFile := probeCmd.String("config", "", "Path to config file")
 	logDir := probeCmd.String("log-dir", "", "Directory to save probe logs")
 	saveResult := probeCmd.Bool("save-result", false, "Save probe results to file")
 	noLog := probeCmd.Bool("no-log", false, "Disable logging")
+	logFormat := probeCmd.String("log-format", "", "Probe log format (json, jsonl) (default: storage.log_format, then json)")
 	contextOnly := probeCmd.Bool("context-only", false, "Probe only context window")
 	outputOnly := probeCmd.Bool("output-only", false, "Probe only max output tokens")
-	outputFormat := probeCmd.String("format", "table", "Output format (table, json)")
+	outputFormat := probeCmd.String("format", "table", "Output format (table, json, or a formatter name from the config)")
 	needlePosition := probeCmd.String("needle-position", "end", "Needle position (end, middle, 80pct)")
 	needleKeyword := probeCmd.String("needle-keyword", "", "Custom needle keyword (default: ラッキーカラーは青色です)")
 	needleAnswer := probeCmd.String("needle-answer", "", "Expected answer for needle (default: 青色)")
 	testAllPositions := probeCmd.Bool("test-all-positions", false, "Test all needle positions (will triple the cost)")
+	strategyName := probeCmd.String("strategy", probe.StrategySearch, "Context window strategy ("+strings.Join(probe.StrategyNames(), ", ")+")")
+	claimedLimit := probeCmd.Int("claimed-limit", 0, "Claimed context window to start from (strategy bisect-claimed)")
+	candidates := probeCmd.String("candidates", "", "Comma-separated context window sizes to verify (strategy fixed-list)")
 	showCost := probeCmd.Bool("show-cost", false, "Show API usage cost summary")
+	noNotify := probeCmd.Bool("no-notify", false, "Disable completion notification")
+	githubSummary := probeCmd.Bool("github-summary", false, "Write a Markdown summary to $GITHUB_STEP_SUMMARY and emit annotations")
+	historyOut := probeCmd.String("history-out", "", "Write each trial (index, tokens, success, latency, error) as CSV to this file")
+	endpoint := addEndpointFlag(probeCmd)
+	yes := addYesFlag(probeCmd)
+	lockOpts := addLockFlags(probeCmd)
+	progressOpts := addProgressFlags(probeCmd)
 	showHelp := probeCmd.Bool("help", false, "Show help for probe command")
 
 	// フラグを解析
@@ -59,9 +77,13 @@ func probeCommand(args []string) error {
 		return nil
 	}
 
-	// 必須引数のチェック
-	if *model == "" {
-		fmt.Fprintf(os.Stderr, "Error: --model is required\n\n")
+	if err := logging.ValidateFormat(*logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		showProbeHelp()
+		os.Exit(1)
+	}
+	if err := validateProbeEndpoint(*endpoint); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
 		showProbeHelp()
 		os.Exit(1)
 	}
@@ -72,6 +94,12 @@ func probeCommand(args []string) error {
 		showProbeHelp()
 		os.Exit(1)
 	}
+	strategy, err := buildStrategy(*strategyName, *claimedLimit, *candidates, *testAllPositions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		showProbeHelp()
+		os.Exit(1)
+	}
 
 	// 設定マネージャーの準備
 	configPath := *configFile
@@ -89,6 +117,11 @@ func probeCommand(args []string) error {
 		}
 	}
 
+	// 読み取り専用モード（--read-only、global.read_only）では探索を拒否する
+	if err := readonly.Check("probe"); err != nil {
+		return err
+	}
+
 	// コマンドライン引数の構造体を作成
 	cliArgs := &internalConfig.CLIArgs{
 		URL:          *baseURL,
@@ -104,31 +137,77 @@ func probeCommand(args []string) error {
 		return fmt.Errorf("failed to resolve config: %w", err)
 	}
 
+	// 必須引数のチェック（--model省略時はゲートウェイのdefault_modelを使う）
+	*model = modelOrDefault(*model, resolved)
+	if *model == "" {
+		fmt.Fprintf(os.Stderr, "Error: --model is required (or set default_model for the gateway in the config file)\n\n")
+		showProbeHelp()
+		os.Exit(1)
+	}
+
+	// ワイルドカードのグループは代表のモデルで探索する
+	*model, err = resolveWildcardTarget(configManager, resolved, *model, *representative)
+	if err != nil {
+		return err
+	}
+
+	// 探索を模擬実行して呼び出し回数・トークン数・コストを見積もる
+	estimateCalls := func(assumptions dryRunAssumptions) ([]*probe.CallEstimate, error) {
+		var estimates []*probe.CallEstimate
+		if !*outputOnly {
+			estimate, err := estimateContextCalls(strategy, *contextOnly && *testAllPositions, assumptions.contextWindow)
+			if err != nil {
+				return nil, err
+			}
+			estimates = append(estimates, estimate)
+		}
+		if !*contextOnly {
+			estimate, err := probe.EstimateMaxOutputCalls(assumptions.maxOutput)
+			if err != nil {
+				return nil, err
+			}
+			estimates = append(estimates, estimate)
+		}
+		return estimates, nil
+	}
+
 	// Dry-runモードの場合は実行計画を表示
 	if *dryRun {
-		showIntegratedExecutionPlan(*model, resolved, *contextOnly, *outputOnly)
+		showIntegratedExecutionPlan(*model, resolved, *contextOnly, *outputOnly, strategy)
 
-		// コスト概算表示
-		if *showCost && resolved.Cost != nil && resolved.Cost.Enabled {
-			calculator := cost.NewCalculator(resolved.Cost, *model)
-			summary := cost.EstimateUsage(calculator, *model, *contextOnly, *outputOnly)
-			fmt.Print(ui.FormatDryRunCostEstimate(summary, calculator))
+		assumptions := resolveDryRunAssumptions(configManager, resolved, *model, *claimedLimit)
+		estimates, err := estimateCalls(assumptions)
+		if err != nil {
+			return fmt.Errorf("failed to estimate probe calls: %w", err)
 		}
+		printDryRunEstimate(resolved, *model, assumptions, estimates)
+		dryRunComplete()
 
 		return nil
 	}
 
-	// APIクライアントを作成
-	cfg := &config.AppConfig{
-		BaseURL: resolved.Gateway.URL,
-		APIKey:  resolved.Gateway.APIKey,
-		Timeout: resolved.Gateway.Timeout,
+	// 見積もりコストがspend_confirm_thresholdを超える場合は実行前に確認する
+	if err := confirmSpend(configManager, resolved, "probe", *model, *claimedLimit, *yes, estimateCalls); err != nil {
+		return err
 	}
 
+	// 保持ポリシーに従って古いログと結果を整理
+	autoPrune(configManager)
+
+	// 同じゲートウェイへの同時探索を防ぐ
+	gatewayLock, err := acquireGatewayLock(configManager, resolved, "probe", lockOpts)
+	if err != nil {
+		return err
+	}
+	defer gatewayLock.Release()
+
+	// APIクライアントを作成
+	cfg := newProbeClientConfig(resolved, probeCmd)
+
 	client := api.NewProbeClient(cfg)
 
-	// デフォルトのログ設定を取得
-	probeConfig := internalConfig.GetDefaultProbeConfig()
+	// ログ・結果保存の設定を取得（storageセクションを反映）
+	probeConfig := configManager.GetProbeConfig()
 
 	// ログ設定の準備
 	var logger logging.ProbeLogger
@@ -137,6 +216,9 @@ func probeCommand(args []string) error {
 		if *logDir != "" {
 			probeConfig.Log.Dir = *logDir
 		}
+		if *logFormat != "" {
+			probeConfig.Log.Format = *logFormat
+		}
 
 		// ログ作成
 		logger, err = logging.NewProbeLogger(probeConfig.Log.ConvertToProbeLogConfig())
@@ -151,7 +233,7 @@ func probeCommand(args []string) error {
 	// 結果保存の準備
 	var resultStorage storage.ResultStorage
 	if *saveResult {
-		resultStorage, err = storage.NewResultStorage(probeConfig.Result.Dir)
+		resultStorage, err = storage.NewResultStorageWithOptions(probeConfig.Result.Dir, probeConfig.Result.StorageOptions())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to create result storage: %v\n", err)
 			resultStorage = nil
@@ -164,6 +246,14 @@ func probeCommand(args []string) error {
 	var totalDuration time.Duration
 	var totalTrials int
 
+	// 進捗表示（--verboseでは詳細ログを表示する）
+	var progress *ui.Progress
+	if !*verbose {
+		progress = progressOpts.newProgress()
+		progress.StartItem(*model, 1, 1)
+		defer progress.Finish()
+	}
+
 	// 実行モードに応じて探索を実行
 	if *contextOnly {
 		// Context Windowのみ測定
@@ -177,7 +267,8 @@ func probeCommand(args []string) error {
 			prober.SetVerboseLogger(verboseFormatter)
 			defer verboseFormatter.Finish()
 		} else {
-			fmt.Printf("Probing context window for model %s...\n", *model)
+			progress.SetPhase("context window")
+			prober.SetVerboseLogger(progress)
 		}
 
 		// needle位置を設定
@@ -192,9 +283,12 @@ func probeCommand(args []string) error {
 		if *testAllPositions {
 			contextResult, err = prober.ProbeAllNeedlePositions(*model, *needleKeyword, *needleAnswer, *verbose)
 		} else {
-			contextResult, err = prober.ProbeWithNeedle(*model, position, *needleKeyword, *needleAnswer, *verbose)
+			contextResult, err = prober.ProbeWithStrategy(*model, strategy, position, *needleKeyword, *needleAnswer)
 		}
 		if err != nil {
+			if *githubSummary {
+				annotateGitHubFailure(probe.ProbeTypeContextWindow, *model, err)
+			}
 			return fmt.Errorf("failed to probe context window: %w", err)
 		}
 		totalDuration = time.Since(start)
@@ -203,13 +297,20 @@ func probeCommand(args []string) error {
 	} else if *outputOnly {
 		// Max Output Tokensのみ測定
 		if *verbose {
-			fmt.Printf("Probing max output tokens for model %s...\n", *model)
+			fmt.Fprintf(os.Stderr, "Probing max output tokens for model %s...\n", *model)
 		}
 
 		start := time.Now()
 		prober := probe.NewMaxOutputTokensProbe(client)
+		if !*verbose {
+			progress.SetPhase("max output")
+			prober.SetVerboseLogger(progress)
+		}
 		outputResult, err = prober.ProbeOutputTokens(*model, *verbose)
 		if err != nil {
+			if *githubSummary {
+				annotateGitHubFailure(probe.ProbeTypeMaxOutput, *model, err)
+			}
 			return fmt.Errorf("failed to probe max output tokens: %w", err)
 		}
 		totalDuration = time.Since(start)
@@ -218,15 +319,31 @@ func probeCommand(args []string) error {
 	} else {
 		// 両方を測定（デフォルト）
 		if *verbose {
-			fmt.Printf("Probing both context window and max output tokens for model %s...\n", *model)
+			fmt.Fprintf(os.Stderr, "Probing both context window and max output tokens for model %s...\n", *model)
 		}
 
 		// 1. Context Window測定（時間がかかる方を先に）
 		start := time.Now()
 		prober := probe.NewContextWindowProbe(client)
-		contextResult, err = prober.Probe(*model, *verbose)
+		if !*verbose {
+			progress.SetPhase("context window")
+			prober.SetVerboseLogger(progress)
+		}
+		if strategy.Name() == probe.StrategySearch {
+			contextResult, err = prober.Probe(*model, *verbose)
+		} else {
+			contextResult, err = prober.ProbeWithStrategy(*model, strategy, probe.End, "", "")
+		}
 		if err != nil {
+			progress.Finish()
 			fmt.Fprintf(os.Stderr, "Warning: failed to probe context window: %v\n", err)
+			if *githubSummary {
+				fmt.Fprintln(os.Stderr, ghactions.Annotation{
+					Level:   ghactions.LevelError,
+					Title:   "Probe failed: " + probe.ProbeTypeContextWindow,
+					Message: fmt.Sprintf("%s: %v", *model, err),
+				})
+			}
 			contextResult = nil
 		}
 		contextDuration := time.Since(start)
@@ -234,9 +351,21 @@ func probeCommand(args []string) error {
 		// 2. Max Output Tokens測定
 		start = time.Now()
 		maxProber := probe.NewMaxOutputTokensProbe(client)
+		if !*verbose {
+			progress.SetPhase("max output")
+			maxProber.SetVerboseLogger(progress)
+		}
 		outputResult, err = maxProber.ProbeOutputTokens(*model, *verbose)
 		if err != nil {
+			progress.Finish()
 			fmt.Fprintf(os.Stderr, "Warning: failed to probe max output tokens: %v\n", err)
+			if *githubSummary {
+				fmt.Fprintln(os.Stderr, ghactions.Annotation{
+					Level:   ghactions.LevelError,
+					Title:   "Probe failed: " + probe.ProbeTypeMaxOutput,
+					Message: fmt.Sprintf("%s: %v", *model, err),
+				})
+			}
 			outputResult = nil
 		}
 		outputDuration := time.Since(start)
@@ -250,37 +379,31 @@ func probeCommand(args []string) error {
 		}
 	}
 
-	// コスト集計
-	var costSummary *cost.UsageSummary
-	if *showCost && resolved.Cost != nil && resolved.Cost.Enabled {
-		calculator := cost.NewCalculator(resolved.Cost, *model)
+	progress.Finish()
 
-		// TrialHistoryからTrialUsageに変換
-		var contextTrials []cost.TrialUsage
-		var outputTrials []cost.TrialUsage
+	if *verbose {
+		reportConnStats(client)
+	}
 
+	// 試行履歴をCSVで書き出す
+	if *historyOut != "" {
+		var histories []probe.TrialHistory
 		if contextResult != nil {
-			for _, trial := range contextResult.TrialHistory {
-				if trial.Usage != nil {
-					contextTrials = append(contextTrials, cost.TrialUsage{
-						PromptTokens:     trial.Usage.PromptTokens,
-						CompletionTokens: trial.Usage.CompletionTokens,
-					})
-				}
-			}
+			histories = append(histories, probe.TrialHistory{Probe: probe.ProbeTypeContextWindow, Trials: contextResult.TrialHistory})
 		}
-
 		if outputResult != nil {
-			for _, trial := range outputResult.TrialHistory {
-				if trial.Usage != nil {
-					outputTrials = append(outputTrials, cost.TrialUsage{
-						PromptTokens:     trial.Usage.PromptTokens,
-						CompletionTokens: trial.Usage.CompletionTokens,
-					})
-				}
-			}
+			histories = append(histories, probe.TrialHistory{Probe: probe.ProbeTypeMaxOutput, Trials: outputResult.TrialHistory})
+		}
+		if err := writeTrialHistory(*historyOut, histories...); err != nil {
+			return err
 		}
+	}
 
+	// コスト集計
+	var costSummary *cost.UsageSummary
+	if *showCost && resolved.Cost != nil && resolved.Cost.Enabled {
+		calculator := cost.NewCalculator(resolved.Cost, *model)
+		contextTrials, outputTrials := probeTrialUsage(contextResult, outputResult)
 		costSummary = cost.AggregateFromTrials(calculator, *model, contextTrials, outputTrials)
 
 		// 警告表示
@@ -291,35 +414,34 @@ func probeCommand(args []string) error {
 	}
 
 	// 統合結果を表示
-	if *outputFormat == "json" {
-		// JSON形式で出力
-		result := map[string]interface{}{
-			"model":             *model,
-			"context_window":    contextResult,
-			"max_output_tokens": outputResult,
-			"summary": map[string]interface{}{
-				"total_trials":     totalTrials,
-				"total_duration":   totalDuration.Seconds(),
-				"success":          (contextResult != nil && contextResult.Success) && (outputResult != nil && outputResult.Success),
-			},
-			"timestamp": time.Now().Format(time.RFC3339),
-		}
-
-		// コスト情報をJSONに追加
-		if costSummary != nil {
-			result = ui.AddCostToJSON(result, costSummary)
+	report := buildProbeReport(*model, resolved, contextResult, outputResult)
+	report.Cost = costSummary
+	if formatter := pluginFormatter(resolved, *outputFormat); formatter != nil {
+		// 設定ファイルのformattersに登録された外部フォーマッターで整形
+		if err := formatter.Render(os.Stdout, ui.PluginKindProbeReport, resolved.Gateway.Name, report); err != nil {
+			return err
 		}
-
-		encoder := json.NewEncoder(os.Stdout)
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(result); err != nil {
-			return fmt.Errorf("failed to encode JSON: %w", err)
+	} else if *outputFormat == "json" {
+		// JSON形式で出力（スキーマv2）
+		if err := writeProbeReportJSON(report); err != nil {
+			return err
 		}
 	} else {
 		// テーブル形式で出力
 		formatter := ui.NewTableFormatter()
 		output := formatter.FormatIntegratedResult(*model, contextResult, outputResult, totalDuration, totalTrials)
 		fmt.Println(output)
+		printRunSpend(report)
+
+		// verbose時は信頼度の根拠も表示
+		if *verbose {
+			if contextResult != nil {
+				fmt.Println("\nContext Window " + strings.TrimPrefix(formatter.FormatConfidenceEvidence(contextResult.Confidence), "\n"))
+			}
+			if outputResult != nil {
+				fmt.Println("\nMax Output Tokens " + strings.TrimPrefix(formatter.FormatConfidenceEvidence(outputResult.Confidence), "\n"))
+			}
+		}
 
 		// コスト情報をテーブル形式で追加表示
 		if costSummary != nil {
@@ -333,21 +455,14 @@ func probeCommand(args []string) error {
 		if contextResult != nil {
 			// Context Windowの試行履歴をログ
 			for i, trial := range contextResult.TrialHistory {
-				logEntry := logging.TrialLogEntry{
-					Index:        i,
-					TokenCount:   trial.TokenCount,
-					Success:      trial.Success,
-					Message:      trial.Message,
-					Timestamp:    time.Now(),
-					Duration:     contextResult.Duration / time.Duration(len(contextResult.TrialHistory)),
-				}
+				logEntry := newTrialLogEntry(i, trial)
 				if err := logger.LogTrial(*model, resolved.Gateway.Name, "context", logEntry); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to log context trial: %v\n", err)
 				}
 			}
 
 			// Context Windowの最終結果をログ
-			if err := logger.LogResult(*model, resolved.Gateway.Name, "context", contextResult); err != nil {
+			if err := logger.LogResult(*model, resolved.Gateway.Name, "context", report.Find(probe.ProbeTypeContextWindow)); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to log context result: %v\n", err)
 			}
 		}
@@ -355,21 +470,14 @@ func probeCommand(args []string) error {
 		if outputResult != nil {
 			// Max Outputの試行履歴をログ
 			for i, trial := range outputResult.TrialHistory {
-				logEntry := logging.TrialLogEntry{
-					Index:        i,
-					TokenCount:   trial.TokenCount,
-					Success:      trial.Success,
-					Message:      trial.Message,
-					Timestamp:    time.Now(),
-					Duration:     outputResult.Duration / time.Duration(len(outputResult.TrialHistory)),
-				}
+				logEntry := newTrialLogEntry(i, trial)
 				if err := logger.LogTrial(*model, resolved.Gateway.Name, "max_output", logEntry); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to log max output trial: %v\n", err)
 				}
 			}
 
 			// Max Outputの最終結果をログ
-			if err := logger.LogResult(*model, resolved.Gateway.Name, "max_output", outputResult); err != nil {
+			if err := logger.LogResult(*model, resolved.Gateway.Name, "max_output", report.Find(probe.ProbeTypeMaxOutput)); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to log max output result: %v\n", err)
 			}
 		}
@@ -377,10 +485,10 @@ func probeCommand(args []string) error {
 
 	// 結果保存
 	if resultStorage != nil {
-		provider := extractProviderName(resolved.Gateway.URL)
+		provider := detectProvider(configManager, resolved, *model, client.ResponseHeader())
 
 		if contextResult != nil {
-			if err := resultStorage.SaveContextResult(provider, *model, contextResult); err != nil {
+			if err := resultStorage.SaveContextResult(provider, *model, report.Find(probe.ProbeTypeContextWindow)); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to save context result: %v\n", err)
 			} else if *verbose {
 				fmt.Printf("Context result saved to: %s\n", probeConfig.Result.Dir)
@@ -388,7 +496,7 @@ func probeCommand(args []string) error {
 		}
 
 		if outputResult != nil {
-			if err := resultStorage.SaveMaxOutputResult(provider, *model, outputResult); err != nil {
+			if err := resultStorage.SaveMaxOutputResult(provider, *model, report.Find(probe.ProbeTypeMaxOutput)); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to save max output result: %v\n", err)
 			} else if *verbose {
 				fmt.Printf("Max output result saved to: %s\n", probeConfig.Result.Dir)
@@ -396,6 +504,22 @@ func probeCommand(args []string) error {
 		}
 	}
 
+	// GitHub Actions向けサマリー
+	if *githubSummary {
+		writeGitHubSummary(ghactions.ProbeReportSummary(report))
+	}
+
+	// 利用統計（オプトイン）
+	recordProbeSpend(configManager, resolved, *model, contextResult, outputResult)
+
+	// 完了通知
+	if !*noNotify {
+		sendProbeNotification(notify.NewNotifier(resolved.Notifications), report)
+	}
+
+	// post_probeフック
+	runProbeHook(resolved, report)
+
 	return nil
 }
 
@@ -407,18 +531,30 @@ func probeContextCommand(args []string) error {
 	baseURL := probeCmd.String("url", "", "Base URL of the LLM gateway")
 	apiKey := probeCmd.String("api-key", "", "API key for authentication")
 	gateway := probeCmd.String("gateway", "", "Gateway name to use from config")
-	timeout := probeCmd.Duration("timeout", 30*time.Second, "Request timeout (default: 30s)")
-	dryRun := probeCmd.Bool("dry-run", false, "Show execution plan without making actual API calls")
+	timeout := probeCmd.Duration("timeout", 30*time.Second, "Request timeout, overrides timeouts.probe (default: 30s)")
+	dryRun := probeCmd.Bool("dry-run", false, "Show execution plan and estimated API calls, tokens and cost without making API calls")
 	verbose := probeCmd.Bool("verbose", false, "Show verbose logs")
 	configFile := probeCmd.String("config", "", "Path to config file")
 	logDir := probeCmd.String("log-dir", "", "Directory to save probe logs")
 	saveResult := probeCmd.Bool("save-result", false, "Save probe results to file")
 	noLog := probeCmd.Bool("no-log", false, "Disable logging")
-	outputFormat := probeCmd.String("format", "table", "Output format (table, json)")
+	logFormat := probeCmd.String("log-format", "", "Probe log format (json, jsonl) (default: storage.log_format, then json)")
+	outputFormat := probeCmd.String("format", "table", "Output format (table, json, or a formatter name from the config)")
 	needlePosition := probeCmd.String("needle-position", "end", "Needle position (end, middle, 80pct)")
 	needleKeyword := probeCmd.String("needle-keyword", "", "Custom needle keyword (default: ラッキーカラーは青色です)")
 	needleAnswer := probeCmd.String("needle-answer", "", "Expected answer for needle (default: 青色)")
 	testAllPositions := probeCmd.Bool("test-all-positions", false, "Test all needle positions (will triple the cost)")
+	strategyName := probeCmd.String("strategy", probe.StrategySearch, "Context window strategy ("+strings.Join(probe.StrategyNames(), ", ")+")")
+	claimedLimit := probeCmd.Int("claimed-limit", 0, "Claimed context window to start from (strategy bisect-claimed)")
+	candidates := probeCmd.String("candidates", "", "Comma-separated context window sizes to verify (strategy fixed-list)")
+	multiTurn := probeCmd.Bool("multi-turn", false, "Grow the context as a conversation history (assistant replies included) and save the limit separately")
+	turnTokens := probeCmd.Int("turn-tokens", probe.DefaultTurnTokens, "Tokens per user/assistant turn with --multi-turn")
+	githubSummary := probeCmd.Bool("github-summary", false, "Write a Markdown summary to $GITHUB_STEP_SUMMARY and emit annotations")
+	historyOut := probeCmd.String("history-out", "", "Write each trial (index, tokens, success, latency, error) as CSV to this file")
+	endpoint := addEndpointFlag(probeCmd)
+	yes := addYesFlag(probeCmd)
+	lockOpts := addLockFlags(probeCmd)
+	progressOpts := addProgressFlags(probeCmd)
 	showHelp := probeCmd.Bool("help", false, "Show help for probe-context command")
 
 	// フラグを解析
@@ -436,6 +572,16 @@ func probeContextCommand(args []string) error {
 		showProbeContextHelp()
 		os.Exit(1)
 	}
+	if err := logging.ValidateFormat(*logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		showProbeContextHelp()
+		os.Exit(1)
+	}
+	if err := validateProbeEndpoint(*endpoint); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		showProbeContextHelp()
+		os.Exit(1)
+	}
 
 	// needle positionの検証
 	validPositions := map[string]bool{"end": true, "middle": true, "80pct": true}
@@ -445,9 +591,35 @@ func probeContextCommand(args []string) error {
 		os.Exit(1)
 	}
 
+	strategy, err := buildStrategy(*strategyName, *claimedLimit, *candidates, *testAllPositions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		showProbeContextHelp()
+		os.Exit(1)
+	}
+
+	if *multiTurn {
+		if *testAllPositions {
+			fmt.Fprintf(os.Stderr, "Error: --multi-turn cannot be used with --test-all-positions\n\n")
+			showProbeContextHelp()
+			os.Exit(1)
+		}
+		if *turnTokens <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --turn-tokens must be greater than 0: %d\n\n", *turnTokens)
+			showProbeContextHelp()
+			os.Exit(1)
+		}
+	}
+
+	// 複数ターンの探索は単発の探索と別の種類として記録する
+	probeType, logType, progressLabel := probe.ProbeTypeContextWindow, "context", "context window"
+	if *multiTurn {
+		probeType, logType, progressLabel = probe.ProbeTypeMultiTurn, probe.ProbeTypeMultiTurn, "multi-turn context"
+	}
+
 	// test-all-positions の警告
 	if *testAllPositions {
-		fmt.Println("⚠️  Testing all needle positions will triple the API call cost")
+		fmt.Fprintln(os.Stderr, "⚠️  Testing all needle positions will triple the API call cost")
 	}
 
 	// 設定マネージャーの準備
@@ -466,6 +638,10 @@ func probeContextCommand(args []string) error {
 		}
 	}
 
+	if err := readonly.Check("probe-context"); err != nil {
+		return err
+	}
+
 	// コマンドライン引数の構造体を作成
 	cliArgs := &internalConfig.CLIArgs{
 		URL:          *baseURL,
@@ -481,19 +657,50 @@ func probeContextCommand(args []string) error {
 		return fmt.Errorf("failed to resolve config: %w", err)
 	}
 
+	// 探索を模擬実行して呼び出し回数・トークン数・コストを見積もる
+	estimateCalls := func(assumptions dryRunAssumptions) ([]*probe.CallEstimate, error) {
+		estimate, err := estimateContextCalls(strategy, *testAllPositions, assumptions.contextWindow)
+		if err != nil {
+			return nil, err
+		}
+		return []*probe.CallEstimate{estimate}, nil
+	}
+
 	// Dry-runモードの場合は実行計画を表示
 	if *dryRun {
-		showContextExecutionPlan(*model, resolved)
+		showContextExecutionPlan(*model, resolved, strategy)
+		if *multiTurn {
+			fmt.Printf("  - Multi-turn conversation: %d tokens per user/assistant turn\n", *turnTokens)
+		}
+
+		assumptions := resolveDryRunAssumptions(configManager, resolved, *model, *claimedLimit)
+		estimates, err := estimateCalls(assumptions)
+		if err != nil {
+			return fmt.Errorf("failed to estimate probe calls: %w", err)
+		}
+		printDryRunEstimate(resolved, *model, assumptions, estimates)
+		dryRunComplete()
 		return nil
 	}
 
-	// APIクライアントを作成
-	cfg := &config.AppConfig{
-		BaseURL: resolved.Gateway.URL,
-		APIKey:  resolved.Gateway.APIKey,
-		Timeout: resolved.Gateway.Timeout,
+	// 見積もりコストがspend_confirm_thresholdを超える場合は実行前に確認する
+	if err := confirmSpend(configManager, resolved, "probe-context", *model, *claimedLimit, *yes, estimateCalls); err != nil {
+		return err
 	}
 
+	// 保持ポリシーに従って古いログと結果を整理
+	autoPrune(configManager)
+
+	// 同じゲートウェイへの同時探索を防ぐ
+	gatewayLock, err := acquireGatewayLock(configManager, resolved, "probe-context", lockOpts)
+	if err != nil {
+		return err
+	}
+	defer gatewayLock.Release()
+
+	// APIクライアントを作成
+	cfg := newProbeClientConfig(resolved, probeCmd)
+
 	client := api.NewProbeClient(cfg)
 
 	// Context Window Proberを作成
@@ -501,10 +708,15 @@ func probeContextCommand(args []string) error {
 
 	// Verbose formatter for real-time output
 	var verboseFormatter *ui.VerboseFormatter
+	var progress *ui.Progress
 	if *verbose {
 		verboseFormatter = ui.NewVerboseFormatter()
 		prober.SetVerboseLogger(verboseFormatter)
 		defer verboseFormatter.Finish()
+	} else {
+		progress = progressOpts.startProgress(*model, progressLabel)
+		prober.SetVerboseLogger(progress)
+		defer progress.Finish()
 	}
 
 	var result *probe.ContextWindowResult
@@ -518,38 +730,53 @@ func probeContextCommand(args []string) error {
 		position = probe.Percent80
 	}
 
-	if *testAllPositions {
+	if *multiTurn {
+		// 会話履歴を積み上げてテスト
+		result, err = prober.ProbeMultiTurn(*model, strategy, *turnTokens)
+	} else if *testAllPositions {
 		// 全ての位置をテスト
 		result, err = prober.ProbeAllNeedlePositions(*model, *needleKeyword, *needleAnswer, *verbose)
 	} else {
-		// 単一の位置をテスト
-		result, err = prober.ProbeWithNeedle(*model, position, *needleKeyword, *needleAnswer, *verbose)
+		// 単一の位置を指定した戦略でテスト
+		result, err = prober.ProbeWithStrategy(*model, strategy, position, *needleKeyword, *needleAnswer)
 	}
+	progress.Finish()
 
 	if err != nil {
+		if *githubSummary {
+			annotateGitHubFailure(probeType, *model, err)
+		}
 		return fmt.Errorf("failed to probe context window: %w", err)
 	}
 
+	// 比較のため、保存済みの単発の探索結果を読み込む
+	if *multiTurn {
+		if dir, index, ok := openResultIndex(configManager); ok {
+			provider := detectProvider(configManager, resolved, *model, client.ResponseHeader())
+			if value, ok, _ := latestResult(index, dir, provider, []string{*model}, storage.ResultTypeContextWindow); ok {
+				result.SingleShotLimit = value
+			}
+		}
+	}
+
 	// 結果を表示
-	if *outputFormat == "json" {
-		// JSON形式で出力
-		jsonResult := map[string]interface{}{
-			"model":           *model,
-			"type":            "context_window",
-			"result":          result,
-			"timestamp":       time.Now().Format(time.RFC3339),
-		}
-		
-		encoder := json.NewEncoder(os.Stdout)
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(jsonResult); err != nil {
-			return fmt.Errorf("failed to encode JSON: %w", err)
+	report := buildProbeReport(*model, resolved, result, nil)
+	if formatter := pluginFormatter(resolved, *outputFormat); formatter != nil {
+		// 設定ファイルのformattersに登録された外部フォーマッターで整形
+		if err := formatter.Render(os.Stdout, ui.PluginKindProbeReport, resolved.Gateway.Name, report); err != nil {
+			return err
+		}
+	} else if *outputFormat == "json" {
+		// JSON形式で出力（スキーマv2）
+		if err := writeProbeReportJSON(report); err != nil {
+			return err
 		}
 	} else {
 		// テーブル形式で出力
 		formatter := ui.NewTableFormatter()
 		output := formatter.FormatContextWindowResult(result)
 		fmt.Println(output)
+		printRunSpend(report)
 	}
 
 	// verbose時は履歴も表示
@@ -558,11 +785,23 @@ func probeContextCommand(args []string) error {
 			formatter := ui.NewTableFormatter()
 			history := formatter.FormatVerboseHistory(result.TrialHistory)
 			fmt.Println(history)
+			fmt.Println(formatter.FormatConfidenceEvidence(result.Confidence))
 		}
 	}
 
-	// デフォルトのログ設定を取得
-	probeConfig := internalConfig.GetDefaultProbeConfig()
+	if *verbose {
+		reportConnStats(client)
+	}
+
+	// 試行履歴をCSVで書き出す
+	if *historyOut != "" {
+		if err := writeTrialHistory(*historyOut, probe.TrialHistory{Probe: probeType, Trials: result.TrialHistory}); err != nil {
+			return err
+		}
+	}
+
+	// ログ・結果保存の設定を取得（storageセクションを反映）
+	probeConfig := configManager.GetProbeConfig()
 
 	// ログ保存処理
 	if !*noLog {
@@ -570,6 +809,9 @@ func probeContextCommand(args []string) error {
 		if *logDir != "" {
 			probeConfig.Log.Dir = *logDir
 		}
+		if *logFormat != "" {
+			probeConfig.Log.Format = *logFormat
+		}
 
 		// ログ作成
 		logger, err := logging.NewProbeLogger(probeConfig.Log.ConvertToProbeLogConfig())
@@ -580,21 +822,14 @@ func probeContextCommand(args []string) error {
 
 			// 試行履歴をログ
 			for i, trial := range result.TrialHistory {
-				logEntry := logging.TrialLogEntry{
-					Index:        i,
-					TokenCount:   trial.TokenCount,
-					Success:      trial.Success,
-					Message:      trial.Message,
-					Timestamp:    time.Now(), // 実際は各試行のタイムスタンプを使用すべき
-					Duration:     result.Duration / time.Duration(len(result.TrialHistory)),
-				}
-				if err := logger.LogTrial(*model, resolved.Gateway.Name, "context", logEntry); err != nil {
+				logEntry := newTrialLogEntry(i, trial)
+				if err := logger.LogTrial(*model, resolved.Gateway.Name, logType, logEntry); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to log trial: %v\n", err)
 				}
 			}
 
 			// 最終結果をログ
-			if err := logger.LogResult(*model, resolved.Gateway.Name, "context", result); err != nil {
+			if err := logger.LogResult(*model, resolved.Gateway.Name, logType, report.Find(probeType)); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to log result: %v\n", err)
 			}
 		}
@@ -602,13 +837,17 @@ func probeContextCommand(args []string) error {
 
 	// 結果保存処理
 	if *saveResult {
-		resultStorage, err := storage.NewResultStorage(probeConfig.Result.Dir)
+		resultStorage, err := storage.NewResultStorageWithOptions(probeConfig.Result.Dir, probeConfig.Result.StorageOptions())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to create result storage: %v\n", err)
 		} else {
-			// Provider名を取得（gateway名から推測）
-			provider := extractProviderName(resolved.Gateway.URL)
-			if err := resultStorage.SaveContextResult(provider, *model, result); err != nil {
+			// Provider名を判定（モデルのメタデータ、設定、レスポンスヘッダー、URLから）
+			provider := detectProvider(configManager, resolved, *model, client.ResponseHeader())
+			save := resultStorage.SaveContextResult
+			if *multiTurn {
+				save = resultStorage.SaveMultiTurnResult
+			}
+			if err := save(provider, *model, report.Find(probeType)); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to save result: %v\n", err)
 			} else if *verbose {
 				fmt.Printf("Result saved to: %s\n", probeConfig.Result.Dir)
@@ -616,6 +855,17 @@ func probeContextCommand(args []string) error {
 		}
 	}
 
+	// 利用統計（オプトイン）
+	recordProbeSpend(configManager, resolved, *model, result, nil)
+
+	// GitHub Actions向けサマリー
+	if *githubSummary {
+		writeGitHubSummary(ghactions.ProbeReportSummary(report))
+	}
+
+	// post_probeフック
+	runProbeHook(resolved, report)
+
 	return nil
 }
 
@@ -627,14 +877,21 @@ func probeMaxOutputCommand(args []string) error {
 	baseURL := probeCmd.String("url", "", "Base URL of the LLM gateway")
 	apiKey := probeCmd.String("api-key", "", "API key for authentication")
 	gateway := probeCmd.String("gateway", "", "Gateway name to use from config")
-	timeout := probeCmd.Duration("timeout", 30*time.Second, "Request timeout (default: 30s)")
-	dryRun := probeCmd.Bool("dry-run", false, "Show execution plan without making actual API calls")
+	timeout := probeCmd.Duration("timeout", 30*time.Second, "Request timeout, overrides timeouts.probe (default: 30s)")
+	dryRun := probeCmd.Bool("dry-run", false, "Show execution plan and estimated API calls, tokens and cost without making API calls")
 	verbose := probeCmd.Bool("verbose", false, "Show verbose logs")
 	configFile := probeCmd.String("config", "", "Path to config file")
 	logDir := probeCmd.String("log-dir", "", "Directory to save probe logs")
 	saveResult := probeCmd.Bool("save-result", false, "Save probe results to file")
 	noLog := probeCmd.Bool("no-log", false, "Disable logging")
-	outputFormat := probeCmd.String("format", "table", "Output format (table, json)")
+	logFormat := probeCmd.String("log-format", "", "Probe log format (json, jsonl) (default: storage.log_format, then json)")
+	outputFormat := probeCmd.String("format", "table", "Output format (table, json, or a formatter name from the config)")
+	githubSummary := probeCmd.Bool("github-summary", false, "Write a Markdown summary to $GITHUB_STEP_SUMMARY and emit annotations")
+	historyOut := probeCmd.String("history-out", "", "Write each trial (index, tokens, success, latency, error) as CSV to this file")
+	endpoint := addEndpointFlag(probeCmd)
+	yes := addYesFlag(probeCmd)
+	lockOpts := addLockFlags(probeCmd)
+	progressOpts := addProgressFlags(probeCmd)
 	showHelp := probeCmd.Bool("help", false, "Show help for probe-max-output command")
 
 	// フラグを解析
@@ -652,6 +909,16 @@ func probeMaxOutputCommand(args []string) error {
 		showProbeMaxOutputHelp()
 		os.Exit(1)
 	}
+	if err := logging.ValidateFormat(*logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		showProbeMaxOutputHelp()
+		os.Exit(1)
+	}
+	if err := validateProbeEndpoint(*endpoint); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		showProbeMaxOutputHelp()
+		os.Exit(1)
+	}
 
 	// 設定マネージャーの準備
 	configPath := *configFile
@@ -669,6 +936,10 @@ func probeMaxOutputCommand(args []string) error {
 		}
 	}
 
+	if err := readonly.Check("probe-max-output"); err != nil {
+		return err
+	}
+
 	// コマンドライン引数の構造体を作成
 	cliArgs := &internalConfig.CLIArgs{
 		URL:          *baseURL,
@@ -684,18 +955,46 @@ func probeMaxOutputCommand(args []string) error {
 		return fmt.Errorf("failed to resolve config: %w", err)
 	}
 
+	// 探索を模擬実行して呼び出し回数・トークン数・コストを見積もる
+	estimateCalls := func(assumptions dryRunAssumptions) ([]*probe.CallEstimate, error) {
+		estimate, err := probe.EstimateMaxOutputCalls(assumptions.maxOutput)
+		if err != nil {
+			return nil, err
+		}
+		return []*probe.CallEstimate{estimate}, nil
+	}
+
 	// Dry-runモードの場合は実行計画を表示
 	if *dryRun {
 		showMaxOutputExecutionPlan(*model, resolved)
+
+		assumptions := resolveDryRunAssumptions(configManager, resolved, *model, 0)
+		estimates, err := estimateCalls(assumptions)
+		if err != nil {
+			return fmt.Errorf("failed to estimate probe calls: %w", err)
+		}
+		printDryRunEstimate(resolved, *model, assumptions, estimates)
+		dryRunComplete()
 		return nil
 	}
 
-	// APIクライアントを作成
-	cfg := &config.AppConfig{
-		BaseURL: resolved.Gateway.URL,
-		APIKey:  resolved.Gateway.APIKey,
-		Timeout: resolved.Gateway.Timeout,
+	// 見積もりコストがspend_confirm_thresholdを超える場合は実行前に確認する
+	if err := confirmSpend(configManager, resolved, "probe-max-output", *model, 0, *yes, estimateCalls); err != nil {
+		return err
+	}
+
+	// 保持ポリシーに従って古いログと結果を整理
+	autoPrune(configManager)
+
+	// 同じゲートウェイへの同時探索を防ぐ
+	gatewayLock, err := acquireGatewayLock(configManager, resolved, "probe-max-output", lockOpts)
+	if err != nil {
+		return err
 	}
+	defer gatewayLock.Release()
+
+	// APIクライアントを作成
+	cfg := newProbeClientConfig(resolved, probeCmd)
 
 	client := api.NewProbeClient(cfg)
 
@@ -704,39 +1003,44 @@ func probeMaxOutputCommand(args []string) error {
 
 	// Verbose formatter for real-time output
 	var verboseFormatter *ui.VerboseFormatter
+	var progress *ui.Progress
 	if *verbose {
 		verboseFormatter = ui.NewVerboseFormatter()
 		prober.SetVerboseLogger(verboseFormatter)
 		defer verboseFormatter.Finish()
 	} else {
-		fmt.Printf("Probing max output tokens for model %s...\n", *model)
+		progress = progressOpts.startProgress(*model, "max output")
+		prober.SetVerboseLogger(progress)
+		defer progress.Finish()
 	}
 
 	result, err := prober.ProbeOutputTokens(*model, *verbose)
+	progress.Finish()
 	if err != nil {
+		if *githubSummary {
+			annotateGitHubFailure(probe.ProbeTypeMaxOutput, *model, err)
+		}
 		return fmt.Errorf("failed to probe max output tokens: %w", err)
 	}
 
 	// 結果を表示
-	if *outputFormat == "json" {
-		// JSON形式で出力
-		jsonResult := map[string]interface{}{
-			"model":     *model,
-			"type":      "max_output",
-			"result":    result,
-			"timestamp": time.Now().Format(time.RFC3339),
-		}
-		
-		encoder := json.NewEncoder(os.Stdout)
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(jsonResult); err != nil {
-			return fmt.Errorf("failed to encode JSON: %w", err)
+	report := buildProbeReport(*model, resolved, nil, result)
+	if formatter := pluginFormatter(resolved, *outputFormat); formatter != nil {
+		// 設定ファイルのformattersに登録された外部フォーマッターで整形
+		if err := formatter.Render(os.Stdout, ui.PluginKindProbeReport, resolved.Gateway.Name, report); err != nil {
+			return err
+		}
+	} else if *outputFormat == "json" {
+		// JSON形式で出力（スキーマv2）
+		if err := writeProbeReportJSON(report); err != nil {
+			return err
 		}
 	} else {
 		// テーブル形式で出力
 		formatter := ui.NewTableFormatter()
 		output := formatter.FormatMaxOutputResult(result)
 		fmt.Println(output)
+		printRunSpend(report)
 	}
 
 	// verbose時は履歴も表示
@@ -745,11 +1049,23 @@ func probeMaxOutputCommand(args []string) error {
 			formatter := ui.NewTableFormatter()
 			history := formatter.FormatVerboseHistory(result.TrialHistory)
 			fmt.Println(history)
+			fmt.Println(formatter.FormatConfidenceEvidence(result.Confidence))
+		}
+	}
+
+	if *verbose {
+		reportConnStats(client)
+	}
+
+	// 試行履歴をCSVで書き出す
+	if *historyOut != "" {
+		if err := writeTrialHistory(*historyOut, probe.TrialHistory{Probe: probe.ProbeTypeMaxOutput, Trials: result.TrialHistory}); err != nil {
+			return err
 		}
 	}
 
-	// デフォルトのログ設定を取得
-	probeConfig := internalConfig.GetDefaultProbeConfig()
+	// ログ・結果保存の設定を取得（storageセクションを反映）
+	probeConfig := configManager.GetProbeConfig()
 
 	// ログ保存処理
 	if !*noLog {
@@ -757,6 +1073,9 @@ func probeMaxOutputCommand(args []string) error {
 		if *logDir != "" {
 			probeConfig.Log.Dir = *logDir
 		}
+		if *logFormat != "" {
+			probeConfig.Log.Format = *logFormat
+		}
 
 		// ログ作成
 		logger, err := logging.NewProbeLogger(probeConfig.Log.ConvertToProbeLogConfig())
@@ -767,21 +1086,14 @@ func probeMaxOutputCommand(args []string) error {
 
 			// 試行履歴をログ
 			for i, trial := range result.TrialHistory {
-				logEntry := logging.TrialLogEntry{
-					Index:        i,
-					TokenCount:   trial.TokenCount,
-					Success:      trial.Success,
-					Message:      trial.Message,
-					Timestamp:    time.Now(),
-					Duration:     result.Duration / time.Duration(len(result.TrialHistory)),
-				}
+				logEntry := newTrialLogEntry(i, trial)
 				if err := logger.LogTrial(*model, resolved.Gateway.Name, "max_output", logEntry); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to log trial: %v\n", err)
 				}
 			}
 
 			// 最終結果をログ
-			if err := logger.LogResult(*model, resolved.Gateway.Name, "max_output", result); err != nil {
+			if err := logger.LogResult(*model, resolved.Gateway.Name, "max_output", report.Find(probe.ProbeTypeMaxOutput)); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to log result: %v\n", err)
 			}
 		}
@@ -789,13 +1101,13 @@ func probeMaxOutputCommand(args []string) error {
 
 	// 結果保存処理
 	if *saveResult {
-		resultStorage, err := storage.NewResultStorage(probeConfig.Result.Dir)
+		resultStorage, err := storage.NewResultStorageWithOptions(probeConfig.Result.Dir, probeConfig.Result.StorageOptions())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to create result storage: %v\n", err)
 		} else {
-			// Provider名を取得（gateway名から推測）
-			provider := extractProviderName(resolved.Gateway.URL)
-			if err := resultStorage.SaveMaxOutputResult(provider, *model, result); err != nil {
+			// Provider名を判定（モデルのメタデータ、設定、レスポンスヘッダー、URLから）
+			provider := detectProvider(configManager, resolved, *model, client.ResponseHeader())
+			if err := resultStorage.SaveMaxOutputResult(provider, *model, report.Find(probe.ProbeTypeMaxOutput)); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to save result: %v\n", err)
 			} else if *verbose {
 				fmt.Printf("Result saved to: %s\n", probeConfig.Result.Dir)
@@ -803,6 +1115,17 @@ func probeMaxOutputCommand(args []string) error {
 		}
 	}
 
+	// 利用統計（オプトイン）
+	recordProbeSpend(configManager, resolved, *model, nil, result)
+
+	// GitHub Actions向けサマリー
+	if *githubSummary {
+		writeGitHubSummary(ghactions.ProbeReportSummary(report))
+	}
+
+	// post_probeフック
+	runProbeHook(resolved, report)
+
 	return nil
 }
 
@@ -812,21 +1135,44 @@ func showProbeHelp() {
 
 USAGE:
     llm-info probe --model <MODEL_ID> [flags]
+    llm-info probe --gateway <NAME> [flags]
 
 FLAGS:
-    --model string              Target model ID (required)
+    --model string              Target model ID, or a wildcard group such as openai/*
+                                (default: default_model of the gateway)
+    --representative string     Model ID to probe for a wildcard --model
+                                (default: chosen from the model list)
     --url string                 Base URL of the LLM gateway
     --api-key string             API key for authentication
     --gateway string             Gateway name to use from config
-    --timeout duration           Request timeout (default: 30s)
-    --dry-run                   Show execution plan without making actual API calls
+    --timeout duration           Request timeout (default: timeouts.probe, then 30s)
+    --dry-run                   Show execution plan and estimated API calls, tokens and cost
+    --yes                       Don't ask for confirmation when the estimated cost exceeds
+                                spend_confirm_threshold
     --verbose                   Show verbose logs
     --log-dir string            Directory to save probe logs
     --save-result               Save probe results to file
     --no-log                   Disable logging
+    --log-format string        Log format: json or jsonl (one flat record per trial) (default: storage.log_format, then json)
     --context-only              Probe only context window
+    --strategy string           Context window strategy (default: search)
+                                search, error-first, bisect-claimed, fixed-list
+    --claimed-limit int         Claimed context window (strategy bisect-claimed)
+    --candidates string         Comma-separated sizes to verify (strategy fixed-list)
     --output-only               Probe only max output tokens
-    --format string             Output format (table, json) (default: table)
+    --format string             Output format (table, json, or a formatter name from the config) (default: table)
+    --no-notify                 Disable completion notification
+    --github-summary            Write Markdown summary to $GITHUB_STEP_SUMMARY
+    --history-out string        Write each trial as CSV (probe, index, tokens, success, latency, error)
+    --endpoint string           Probe endpoint: chat, completions, responses, auto
+                                (default: probe_endpoint of the gateway, then chat)
+                                completions uses /v1/completions for legacy models;
+                                responses uses the Responses API (/v1/responses);
+                                auto switches to completions when the model is not a chat model
+    --wait duration              Wait for another probe of the same gateway to finish (default: fail immediately)
+    --force                      Take over the gateway lock held by another probe
+    --quiet                      Do not show progress
+    --plain                      Show progress as log lines instead of a progress bar
     --config string              Path to config file
     --help                      Show help for probe command
 
@@ -837,6 +1183,9 @@ EXAMPLES:
     # With custom gateway
     llm-info probe --model gpt-4o-mini --gateway production
 
+    # Probe the default_model of a gateway
+    llm-info probe --gateway production
+
     # Dry run to see execution plan
     llm-info probe --model gpt-4o-mini --dry-run
 
@@ -846,12 +1195,30 @@ EXAMPLES:
     # Probe only context window
     llm-info probe --model gpt-4o-mini --context-only
 
+    # Probe a wildcard routing entry through one of its members
+    llm-info probe --model "openai/*" --representative openai/gpt-4o-mini
+
+    # Read the context window from validation errors before searching
+    llm-info probe --model gpt-4o-mini --context-only --strategy error-first
+
     # Probe only max output tokens
     llm-info probe --model gpt-4o-mini --output-only
 
     # Save results and logs
     llm-info probe --model gpt-4o-mini --save-result --log-dir ./logs
 
+    # Structured logs for a log shipper (one JSON record per trial)
+    llm-info probe --model gpt-4o-mini --log-format jsonl
+
+    # Trial history as CSV for plotting the convergence
+    llm-info probe --model gpt-4o-mini --history-out history.csv
+
+    # Legacy completions-only model
+    llm-info probe --model gpt-3.5-turbo-instruct --endpoint completions
+
+    # Probe through the Responses API
+    llm-info probe --model gpt-4o-mini --endpoint responses
+
     # JSON output
     llm-info probe --model gpt-4o-mini --format json
 
@@ -860,7 +1227,7 @@ EXAMPLES:
 }
 
 // showIntegratedExecutionPlan は統合探索の実行計画を表示する
-func showIntegratedExecutionPlan(model string, config *internalConfig.ResolvedConfig, contextOnly, outputOnly bool) {
+func showIntegratedExecutionPlan(model string, config *internalConfig.ResolvedConfig, contextOnly, outputOnly bool, strategy probe.Strategy) {
 	fmt.Printf("Model Constraints Probe Execution Plan:\n")
 	fmt.Printf("  Model: %s\n", model)
 	fmt.Printf("  URL: %s\n", config.Gateway.URL)
@@ -870,9 +1237,7 @@ func showIntegratedExecutionPlan(model string, config *internalConfig.ResolvedCo
 	if contextOnly {
 		fmt.Printf("\nProbe Mode: Context Window Only\n")
 		fmt.Printf("\nProbe Phases:\n")
-		fmt.Printf("  1. Exponential Search: Find upper bound by doubling token count\n")
-		fmt.Printf("  2. Binary Search: Refine boundary within ±1024 tokens\n")
-		fmt.Printf("  3. Error Analysis: Extract token limits from error messages\n")
+		printStrategyPlan(strategy)
 		fmt.Printf("\nAPI Calls:\n")
 		fmt.Printf("  POST %s/v1/chat/completions\n", config.Gateway.URL)
 		fmt.Printf("  - Test data generation with Japanese text\n")
@@ -899,9 +1264,7 @@ func showIntegratedExecutionPlan(model string, config *internalConfig.ResolvedCo
 		fmt.Printf("  1. Context Window Probing (time-intensive phase first)\n")
 		fmt.Printf("  2. Max Output Tokens Probing\n")
 		fmt.Printf("\nContext Window Probe Phases:\n")
-		fmt.Printf("  1. Exponential Search: Find upper bound by doubling token count\n")
-		fmt.Printf("  2. Binary Search: Refine boundary within ±1024 tokens\n")
-		fmt.Printf("  3. Error Analysis: Extract token limits from error messages\n")
+		printStrategyPlan(strategy)
 		fmt.Printf("\nMax Output Tokens Probe Phases:\n")
 		fmt.Printf("  1. Exponential Search: Find upper bound by doubling token count (256→512→1024...)\n")
 		fmt.Printf("  2. Binary Search: Refine boundary within detection range\n")
@@ -913,7 +1276,6 @@ func showIntegratedExecutionPlan(model string, config *internalConfig.ResolvedCo
 		fmt.Printf("  - Rate limiting: 1s (context) / 0.5s (output) between calls\n")
 	}
 
-	fmt.Printf("\nDry run complete. Use --dry-run=false to execute actual API calls.\n")
 }
 
 // displayProbeResult は探索結果を表示する
@@ -939,6 +1301,46 @@ func displayProbeResult(model string, response *api.ProbeResponse, verbose bool)
 	}
 }
 
+// buildStrategy は--strategyと関連オプションからcontext windowの探索戦略を作成する
+func buildStrategy(name string, claimedLimit int, candidates string, testAllPositions bool) (probe.Strategy, error) {
+	opts := probe.StrategyOptions{ClaimedLimit: claimedLimit}
+	for _, field := range strings.Split(candidates, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		value, err := strconv.Atoi(field)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid value in --candidates: %s", field)
+		}
+		opts.Candidates = append(opts.Candidates, value)
+	}
+
+	strategy, err := probe.NewStrategy(name, opts)
+	if err != nil {
+		return nil, err
+	}
+	if testAllPositions && strategy.Name() != probe.StrategySearch {
+		return nil, fmt.Errorf("--strategy %s cannot be used with --test-all-positions", strategy.Name())
+	}
+	return strategy, nil
+}
+
+// printStrategyPlan は探索戦略の手順を実行計画として表示する
+func printStrategyPlan(strategy probe.Strategy) {
+	if strategy.Name() != probe.StrategySearch {
+		fmt.Printf("  Strategy: %s\n", strategy.Name())
+	}
+	step := 0
+	for _, line := range strategy.Plan() {
+		if strings.HasPrefix(line, "  ") {
+			fmt.Printf("   %s\n", line)
+			continue
+		}
+		step++
+		fmt.Printf("  %d. %s\n", step, line)
+	}
+}
+
 // showProbeContextHelp はprobe-contextコマンドのヘルプを表示する
 func showProbeContextHelp() {
 	fmt.Println(`llm-info probe-context - Probe context window constraints via actual API behavior
@@ -951,17 +1353,34 @@ FLAGS:
     --url string         Base URL of the LLM gateway
     --api-key string     API key for authentication
     --gateway string     Gateway name to use from config
-    --timeout duration   Request timeout (default: 30s)
-    --dry-run           Show execution plan without making actual API calls
+    --timeout duration   Request timeout (default: timeouts.probe, then 30s)
+    --dry-run           Show execution plan and estimated API calls, tokens and cost
+    --yes               Don't ask for confirmation when the estimated cost exceeds
+                        spend_confirm_threshold
     --verbose           Show verbose logs
     --log-dir string     Directory to save probe logs
     --save-result       Save probe results to file
     --no-log           Disable logging
-    --format string     Output format (table, json) (default: table)
+    --log-format string  Log format: json or jsonl (one flat record per trial) (default: json)
+    --format string     Output format (table, json, or a formatter name from the config) (default: table)
+    --github-summary    Write Markdown summary to $GITHUB_STEP_SUMMARY
+    --history-out string Write each trial as CSV (probe, index, tokens, success, latency, error)
+    --endpoint string    Probe endpoint: chat, completions, responses, auto (default: probe_endpoint of the gateway, then chat)
     --needle-position string Needle position (end, middle, 80pct)
     --needle-keyword string Custom needle keyword (default: ラッキーカラーは青色です)
     --needle-answer string  Expected answer for needle (default: 青色)
     --test-all-positions  Test all needle positions (will triple the cost)
+    --strategy string   Probe strategy (default: search)
+                        search, error-first, bisect-claimed, fixed-list
+    --claimed-limit int Claimed context window (strategy bisect-claimed)
+    --candidates string Comma-separated sizes to verify (strategy fixed-list)
+    --multi-turn        Grow the context as a conversation history instead of one message;
+                        the limit is saved as multi_turn_context, apart from the single-shot limit
+    --turn-tokens int   Tokens per user/assistant turn with --multi-turn (default: 4096)
+    --wait duration      Wait for another probe of the same gateway to finish (default: fail immediately)
+    --force              Take over the gateway lock held by another probe
+    --quiet              Do not show progress
+    --plain              Show progress as log lines instead of a progress bar
     --config string      Path to config file
     --help              Show help for probe-context command
 
@@ -988,26 +1407,41 @@ EXAMPLES:
     llm-info probe-context --model gpt-4o-mini --needle-keyword "東京タワーは333メートルです" --needle-answer "333メートル"
 
     # Test all positions
-    llm-info probe-context --model gpt-4o-mini --test-all-positions --verbose`)
+    llm-info probe-context --model gpt-4o-mini --test-all-positions --verbose
+
+    # Read the limit from validation errors (1-2 calls), search only if that fails
+    llm-info probe-context --model gpt-4o-mini --strategy error-first
+
+    # Confirm the documented limit with a few calls
+    llm-info probe-context --model gpt-4o-mini --strategy bisect-claimed --claimed-limit 128000
+
+    # Check which of a fixed list of sizes is accepted, largest first
+    llm-info probe-context --model gpt-4o-mini --strategy fixed-list --candidates 32768,128000,200000
+
+    # Measure the practical limit of a conversation history (assistant replies included)
+    llm-info probe-context --model gpt-4o-mini --multi-turn --save-result
+
+STRATEGIES:
+    search          Exponential search, then binary search around the boundary
+    error-first     Read the limit from validation errors, search if none is found
+    bisect-claimed  Try the claimed limit, then bisect below or above it
+    fixed-list      Try common window sizes largest first, stop at the first accepted`)
 }
 
 // showContextExecutionPlan はContext Window探索の実行計画を表示する
-func showContextExecutionPlan(model string, config *internalConfig.ResolvedConfig) {
+func showContextExecutionPlan(model string, config *internalConfig.ResolvedConfig, strategy probe.Strategy) {
 	fmt.Printf("Context Window Probe Execution Plan:\n")
 	fmt.Printf("  Model: %s\n", model)
 	fmt.Printf("  URL: %s\n", config.Gateway.URL)
 	fmt.Printf("  API Key: %s\n", maskAPIKey(config.Gateway.APIKey))
 	fmt.Printf("  Timeout: %s\n", config.Gateway.Timeout)
 	fmt.Printf("\nProbe Phases:\n")
-	fmt.Printf("  1. Exponential Search: Find upper bound by doubling token count\n")
-	fmt.Printf("  2. Binary Search: Refine boundary within ±1024 tokens\n")
-	fmt.Printf("  3. Error Analysis: Extract token limits from error messages\n")
+	printStrategyPlan(strategy)
 	fmt.Printf("\nAPI Calls:\n")
 	fmt.Printf("  POST %s/v1/chat/completions\n", config.Gateway.URL)
 	fmt.Printf("  - Test data generation with Japanese text\n")
 	fmt.Printf("  - Needle-in-haystack methodology\n")
 	fmt.Printf("  - Rate limited: 1 second between calls\n")
-	fmt.Printf("\nDry run complete. Use --dry-run=false to execute actual API calls.\n")
 }
 
 // showProbeMaxOutputHelp はprobe-max-outputコマンドのヘルプを表示する
@@ -1022,13 +1456,23 @@ func showProbeMaxOutputHelp() {
 	fmt.Println("    --url string         Base URL of the LLM gateway")
 	fmt.Println("    --api-key string     API key for authentication")
 	fmt.Println("    --gateway string     Gateway name to use from config")
-	fmt.Println("    --timeout duration   Request timeout (default: 30s)")
-	fmt.Println("    --dry-run           Show execution plan without making actual API calls")
+	fmt.Println("    --timeout duration   Request timeout (default: timeouts.probe, then 30s)")
+	fmt.Println("    --dry-run           Show execution plan and estimated API calls, tokens and cost")
+	fmt.Println("    --yes               Don't ask for confirmation when the estimated cost exceeds")
+	fmt.Println("                        spend_confirm_threshold")
 	fmt.Println("    --verbose           Show verbose logs")
 	fmt.Println("    --log-dir string     Directory to save probe logs")
 	fmt.Println("    --save-result       Save probe results to file")
 	fmt.Println("    --no-log           Disable logging")
-	fmt.Println("    --format string     Output format (table, json) (default: table)")
+	fmt.Println("    --log-format string  Log format: json or jsonl (one flat record per trial) (default: json)")
+	fmt.Println("    --format string     Output format (table, json, or a formatter name from the config) (default: table)")
+	fmt.Println("    --github-summary    Write Markdown summary to $GITHUB_STEP_SUMMARY")
+	fmt.Println("    --history-out string Write each trial as CSV (probe, index, tokens, success, latency, error)")
+	fmt.Println("    --endpoint string    Probe endpoint: chat, completions, responses, auto (default: probe_endpoint of the gateway, then chat)")
+	fmt.Println("    --wait duration      Wait for another probe of the same gateway to finish (default: fail immediately)")
+	fmt.Println("    --force              Take over the gateway lock held by another probe")
+	fmt.Println("    --quiet              Do not show progress")
+	fmt.Println("    --plain              Show progress as log lines instead of a progress bar")
 	fmt.Println("    --config string      Path to config file")
 	fmt.Println("    --help              Show help for probe-max-output command")
 	fmt.Println("")
@@ -1074,7 +1518,6 @@ func showMaxOutputExecutionPlan(model string, config *internalConfig.ResolvedCon
 	fmt.Printf("\nDetection Methods:\n")
 	fmt.Printf("  - Validation Error: Extract from error messages (e.g., 'max_output_tokens must be <= 16384')\n")
 	fmt.Printf("  - Incomplete Status: Check response.finish_reason='length'\n")
-	fmt.Printf("\nDry run complete. Use --dry-run=false to execute actual API calls.\n")
 }
 
 // maskAPIKey はAPIキーをマスキングする
@@ -1088,20 +1531,254 @@ func maskAPIKey(key string) string {
 	return key[:4] + strings.Repeat("*", len(key)-8) + key[len(key)-4:]
 }
 
-// extractProviderName はURLからプロバイダ名を抽出する
-func extractProviderName(url string) string {
-	if strings.Contains(url, "openai") {
-		return "openai"
+// newTrialLogEntry は試行情報からログエントリを作成する
+func newTrialLogEntry(index int, trial probe.TrialInfo) logging.TrialLogEntry {
+	entry := logging.TrialLogEntry{
+		Index:      index,
+		TokenCount: trial.TokenCount,
+		Success:    trial.Success,
+		Message:    trial.Message,
+		Timestamp:  trial.StartedAt,
+		Duration:   trial.EndedAt.Sub(trial.StartedAt),
+		StartedAt:  trial.StartedAt,
+		EndedAt:    trial.EndedAt,
+		Latency:    trial.Latency,
+	}
+	if !trial.Success {
+		entry.ErrorMessage = trial.Message
+	}
+	if trial.Usage != nil {
+		entry.PromptTokens = trial.Usage.PromptTokens
+		entry.CompletionTokens = trial.Usage.CompletionTokens
+		entry.TotalTokens = trial.Usage.TotalTokens
+	}
+	return entry
+}
+
+// buildProbeReport は探索結果からスキーマv2のレポートを作成する
+func buildProbeReport(model string, resolved *internalConfig.ResolvedConfig, contextResult *probe.ContextWindowResult, outputResult *probe.MaxOutputResult) *probe.Report {
+	calculator := cost.NewCalculator(resolved.Cost, model)
+
+	var results []*probe.Result
+	if contextResult != nil {
+		result := contextResult.ToResult(resolved.Gateway.Name)
+		result.Model = model
+		result.ApplyCost(calculator)
+		results = append(results, result)
+	}
+	if outputResult != nil {
+		result := outputResult.ToResult(resolved.Gateway.Name)
+		result.Model = model
+		result.ApplyCost(calculator)
+		results = append(results, result)
+	}
+
+	return probe.NewReport(model, resolved.Gateway.Name, results...)
+}
+
+// printRunSpend は実行全体で消費したトークン数とコストを表示する
+func printRunSpend(report *probe.Report) {
+	fmt.Printf("\nSpend: %s\n", report.SpendSummary())
+}
+
+// writeProbeReportJSON はレポートをJSON形式で標準出力に書き出す
+func writeProbeReportJSON(report *probe.Report) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return nil
+}
+
+// loadProbeConfigManager は設定マネージャーを作成し設定ファイルを読み込む
+func loadProbeConfigManager(configFile string) *internalConfig.Manager {
+	configPath := configFile
+	if configPath == "" {
+		configPath = internalConfig.GetDefaultConfigPath()
+	}
+	configManager := internalConfig.NewManager(configPath)
+
+	if err := configManager.Load(); err != nil {
+		// 設定ファイルが存在しない場合は警告を表示しない
+		if !strings.Contains(err.Error(), "no such file or directory") &&
+			!strings.Contains(err.Error(), "config file not found") {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load config file: %v\n", err)
+		}
+	}
+	exitCodes = configManager.GetExitCodes()
+
+	return configManager
+}
+
+// sendProbeNotification は探索完了を通知する
+func sendProbeNotification(notifier *notify.Notifier, report *probe.Report) {
+	if !notifier.Enabled(notify.EventProbeCompleted) {
+		return
+	}
+
+	status := "completed"
+	if !report.Success {
+		status = "finished with errors"
 	}
-	if strings.Contains(url, "anthropic") {
-		return "anthropic"
+
+	var summary []string
+	for _, result := range report.Results {
+		if !result.Success {
+			summary = append(summary, fmt.Sprintf("%s: failed (%s)", result.Type, result.ErrorMessage))
+			continue
+		}
+		summary = append(summary, fmt.Sprintf("%s: %d tokens (confidence %.2f %s, %d trials)",
+			result.Type, result.Value, result.Confidence, result.Level, result.TrialCount))
+	}
+	summary = append(summary, fmt.Sprintf("total: %d trials in %s, %s",
+		report.TotalTrials, time.Duration(report.TotalDurationMs)*time.Millisecond, report.SpendSummary()))
+
+	payload := &notify.Payload{
+		Event:   notify.EventProbeCompleted,
+		Title:   fmt.Sprintf("Probe %s", status),
+		Summary: summary,
+		Gateway: report.Gateway,
+		Model:   report.Model,
+		Details: report,
+	}
+	if err := notifier.Send(payload); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to send notification: %v\n", err)
+	}
+}
+
+// runProbeHook は探索結果のレポートをpost_probeフックに渡す
+func runProbeHook(resolved *internalConfig.ResolvedConfig, report *probe.Report) {
+	info := hooks.Info{Gateway: report.Gateway, Model: report.Model}
+	if err := hooks.NewRunner(resolved.Hooks).Run(hooks.EventPostProbe, info, report); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 	}
-	if strings.Contains(url, "google") {
-		return "google"
+}
+
+// writeGitHubSummary はステップサマリーを書き込み、アノテーションを出力する
+func writeGitHubSummary(summary *ghactions.Summary) {
+	if err := summary.Write(os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 	}
-	if strings.Contains(url, "azure") {
-		return "azure"
+}
+
+// annotateGitHubFailure は探索失敗をエラーアノテーションとして出力する
+func annotateGitHubFailure(probeType, model string, err error) {
+	summary := ghactions.NewSummary(fmt.Sprintf("llm-info probe: `%s`", model))
+	summary.Line(fmt.Sprintf("❌ %s probe failed: %v\n", probeType, err))
+	summary.Error(fmt.Sprintf("Probe failed: %s", probeType), fmt.Sprintf("%s: %v", model, err))
+	writeGitHubSummary(summary)
+}
+
+// newProbeClientConfig は探索用のクライアント設定を作成する
+// --timeoutが明示されていない場合はゲートウェイのtimeouts.probeを優先する
+func newProbeClientConfig(resolved *internalConfig.ResolvedConfig, flags *flag.FlagSet) *config.AppConfig {
+	timeout := resolved.Gateway.Timeout
+	if !isFlagSet(flags, "timeout") {
+		timeout = resolved.Gateway.ProbeTimeout(timeout)
 	}
-	// デフォルトは "unknown"
-	return "unknown"
-}
\ No newline at end of file
+
+	// max_body_sizeは設定ファイルの読み込み時に検証済み
+	maxBodySize, _ := storage.ParseSize(resolved.Gateway.MaxBodySize)
+
+	cfg := &config.AppConfig{
+		BaseURL:     resolved.Gateway.URL,
+		APIKey:      resolved.Gateway.APIKey,
+		Timeout:     timeout,
+		Timeouts:    resolved.Gateway.Timeouts.Connection(),
+		Proxy:       resolved.Gateway.Proxy,
+		MaxBodySize: maxBodySize,
+	}
+	// エンドポイントはゲートウェイのprobe_endpointを使い、--endpointが明示された場合はそちらを優先する
+	cfg.ProbeEndpoint = resolved.Gateway.ProbeEndpoint
+	if isFlagSet(flags, "endpoint") {
+		cfg.ProbeEndpoint = flags.Lookup("endpoint").Value.String()
+	}
+	return cfg
+}
+
+// addEndpointFlag は探索リクエストを送るエンドポイントを指定する--endpointを定義する
+func addEndpointFlag(fs *flag.FlagSet) *string {
+	return fs.String("endpoint", "", "Probe endpoint ("+strings.Join(config.ValidProbeEndpoints, ", ")+") (default: probe_endpoint of the gateway, then chat)")
+}
+
+// validateProbeEndpoint は--endpointの値を検証する（空はゲートウェイの設定を使う）
+func validateProbeEndpoint(endpoint string) error {
+	if endpoint != "" && !slices.Contains(config.ValidProbeEndpoints, endpoint) {
+		return fmt.Errorf("invalid --endpoint: %s (valid: %s)", endpoint, strings.Join(config.ValidProbeEndpoints, ", "))
+	}
+	return nil
+}
+
+// modelOrDefault は指定されたモデルを返す
+// 未指定の場合は解決したゲートウェイのdefault_modelを返す
+func modelOrDefault(modelID string, resolved *internalConfig.ResolvedConfig) string {
+	if modelID != "" || resolved.Gateway == nil {
+		return modelID
+	}
+	return resolved.Gateway.DefaultModel
+}
+
+// writeTrialHistory は試行履歴をCSVファイルに書き出す
+func writeTrialHistory(path string, histories ...probe.TrialHistory) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create trial history file: %w", err)
+	}
+	if err := probe.WriteHistoryCSV(file, histories...); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write trial history file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Trial history saved to: %s\n", path)
+	return nil
+}
+
+// reportConnStats は探索中の接続の再利用状況と、レート制限で待った時間や429の回数を表示する
+// JSON出力を壊さないよう標準エラー出力に出す
+func reportConnStats(client *api.ProbeClient) {
+	fmt.Fprintf(os.Stderr, "Connections: %s\n", client.ConnStats())
+	fmt.Fprintf(os.Stderr, "Throttling: %s\n", client.Throttling())
+}
+
+// isFlagSet はフラグがコマンドラインで明示的に指定されたかを返す
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// probeTrialUsage は試行履歴からコスト集計用の使用量を取り出す
+func probeTrialUsage(contextResult *probe.ContextWindowResult, outputResult *probe.MaxOutputResult) ([]cost.TrialUsage, []cost.TrialUsage) {
+	var contextTrials []cost.TrialUsage
+	var outputTrials []cost.TrialUsage
+
+	if contextResult != nil {
+		contextTrials = trialUsage(contextResult.TrialHistory)
+	}
+	if outputResult != nil {
+		outputTrials = trialUsage(outputResult.TrialHistory)
+	}
+
+	return contextTrials, outputTrials
+}
+
+// trialUsage は試行履歴のうち使用量の分かる試行を取り出す
+func trialUsage(history []probe.TrialInfo) []cost.TrialUsage {
+	var trials []cost.TrialUsage
+	for _, trial := range history {
+		if trial.Usage != nil {
+			trials = append(trials, cost.TrialUsage{
+				PromptTokens:     trial.Usage.PromptTokens,
+				CompletionTokens: trial.Usage.CompletionTokens,
+			})
+		}
+	}
+	return trials
+}
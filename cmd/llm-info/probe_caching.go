@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/readonly"
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["probe-caching"] = probeCachingCommand
+}
+
+// probeCachingCommand はモデルごとにプロンプトキャッシュに対応しているか（キャッシュされたトークン数が報告されるか）を調べる
+func probeCachingCommand(args []string) error {
+	probeCmd := flag.NewFlagSet("probe-caching", flag.ExitOnError)
+	models := probeCmd.String("model", "", "Target model ID, or a comma-separated list of model IDs (required)")
+	baseURL := probeCmd.String("url", "", "Base URL of the LLM gateway")
+	apiKey := probeCmd.String("api-key", "", "API key for authentication")
+	gateway := probeCmd.String("gateway", "", "Gateway name to use from config")
+	timeout := probeCmd.Duration("timeout", 30*time.Second, "Request timeout, overrides timeouts.probe (default: 30s)")
+	configFile := probeCmd.String("config", "", "Path to config file")
+	saveResult := probeCmd.Bool("save-result", false, "Save the result as part of the capabilities result")
+	outputFormat := probeCmd.String("format", "table", "Output format (table, json)")
+	continueOnError := probeCmd.Bool("continue-on-error", false, "Record a model that fails to probe in the report and continue with the next model")
+	lockOpts := addLockFlags(probeCmd)
+	progressOpts := addProgressFlags(probeCmd)
+	showHelp := probeCmd.Bool("help", false, "Show help for probe-caching command")
+
+	probeCmd.Parse(args)
+
+	if *showHelp {
+		showProbeCachingHelp()
+		return nil
+	}
+
+	var modelIDs []string
+	for _, id := range strings.Split(*models, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			modelIDs = append(modelIDs, id)
+		}
+	}
+	if len(modelIDs) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: --model is required\n\n")
+		showProbeCachingHelp()
+		os.Exit(1)
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	if err := readonly.Check("probe-caching"); err != nil {
+		return err
+	}
+	resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+		URL:          *baseURL,
+		APIKey:       *apiKey,
+		Timeout:      *timeout,
+		Gateway:      *gateway,
+		OutputFormat: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	// 同じゲートウェイへの同時探索を防ぐ
+	gatewayLock, err := acquireGatewayLock(configManager, resolved, "probe-caching", lockOpts)
+	if err != nil {
+		return err
+	}
+	defer gatewayLock.Release()
+
+	client := api.NewProbeClient(newProbeClientConfig(resolved, probeCmd))
+	prober := probe.NewPromptCachingProbe(client)
+
+	progress := progressOpts.newProgress()
+	defer progress.Finish()
+
+	var results []*probe.PromptCachingResult
+	var failed []string
+	for i, model := range modelIDs {
+		progress.StartItem(model, i+1, len(modelIDs))
+		result, err := prober.Probe(model, resolved.Gateway.Name)
+		if err != nil {
+			if !*continueOnError {
+				return fmt.Errorf("failed to probe prompt caching for %s: %w", model, err)
+			}
+			// 失敗を記録して次のモデルに進む
+			progress.FinishItem("failed")
+			failed = append(failed, model)
+			results = append(results, &probe.PromptCachingResult{
+				Model:    model,
+				Gateway:  resolved.Gateway.Name,
+				Samples:  []probe.CachingSample{},
+				ProbedAt: time.Now(),
+				Error:    redact.String(err.Error()),
+			})
+			continue
+		}
+		results = append(results, result)
+		progress.FinishItem("ok")
+	}
+	progress.Finish()
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printPromptCaching(results)
+	}
+
+	if *saveResult {
+		for _, result := range results {
+			if result.Error != "" {
+				continue
+			}
+			dir, err := saveCapabilities(configManager, resolved, result.Model, func(c *probe.Capabilities) {
+				c.SetPromptCaching(result)
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Result for %s saved to: %s\n", result.Model, dir)
+		}
+	}
+
+	if len(failed) > 0 {
+		return batchFailureError(failed, len(modelIDs))
+	}
+	return nil
+}
+
+// printPromptCaching はモデルごとの2回目のリクエストのキャッシュされたトークン数とcache_controlの受け付けを表示する
+func printPromptCaching(results []*probe.PromptCachingResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tENDPOINT\tPROMPT\tCACHED\tCACHE_CONTROL\tCACHED (CACHE_CONTROL)\tCACHING\t")
+	var notReported, failures []string
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Fprintf(w, "%s\tFAILED\t-\t-\t-\t-\t-\t\n", result.Model)
+			failures = append(failures, fmt.Sprintf("  %s: %s", result.Model, result.Error))
+			continue
+		}
+		second := result.Samples[1]
+		controlCached := "-"
+		if result.CacheControl == probe.CacheControlAccepted && len(result.Samples) == 4 {
+			controlCached = fmt.Sprintf("%d", result.Samples[3].CachedTokens)
+		}
+		caching := "no"
+		if result.Supported {
+			caching = "yes"
+		}
+		if !result.ReportsCachedTokens {
+			caching = "unknown"
+			notReported = append(notReported, "  "+result.Model)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\t%s\t\n", result.Model, result.Endpoint,
+			second.PromptTokens, second.CachedTokens, result.CacheControl, controlCached, caching)
+	}
+	w.Flush()
+
+	if len(notReported) > 0 {
+		fmt.Println("\nThe gateway did not report cached tokens in usage, so cache hits cannot be detected:")
+		fmt.Println(strings.Join(notReported, "\n"))
+	}
+	if len(failures) > 0 {
+		fmt.Println("\nFailed:")
+		fmt.Println(strings.Join(failures, "\n"))
+	}
+}
+
+// showProbeCachingHelp はprobe-cachingコマンドのヘルプを表示する
+func showProbeCachingHelp() {
+	fmt.Println(`llm-info probe-caching - Detect prompt caching support
+
+USAGE:
+    llm-info probe-caching --model <MODEL_ID>[,<MODEL_ID>...] [flags]
+
+FLAGS:
+    --model string      Target model ID, or a comma-separated list (required)
+    --url string        Base URL of the LLM gateway
+    --api-key string    API key for authentication
+    --gateway string    Gateway name to use from config
+    --timeout duration  Request timeout (default: timeouts.probe, then 30s)
+    --save-result       Save the result as part of the capabilities result
+    --format string     Output format (table, json) (default: table)
+    --continue-on-error
+                        Record a model that fails to probe and continue with the next one
+    --wait duration     Wait for another probe of the same gateway to finish (default: fail immediately)
+    --force             Take over the gateway lock held by another probe
+    --quiet             Do not show progress
+    --plain             Show progress as log lines instead of a progress bar
+    --config string     Path to config file
+    --help              Show help for probe-caching command
+
+EXAMPLES:
+    # Check whether repeated prompts are billed at the cached rate
+    llm-info probe-caching --model gpt-4o-mini
+
+    # Compare several models behind the same gateway
+    llm-info probe-caching --model gpt-4o-mini,claude-3-5-sonnet --gateway production --format json
+
+DESCRIPTION:
+    Sends the same prompt with a prefix of about 1,500 tokens twice
+    (max_tokens=16) and checks whether the second response reports cached
+    tokens in usage.prompt_tokens_details.cached_tokens (OpenAI) or
+    usage.cache_read_input_tokens (Anthropic). On the chat endpoint it then
+    sends another prefix twice as a content part with
+    cache_control: {"type": "ephemeral"}, which Anthropic models need to
+    cache, and records whether the gateway accepts it.
+
+      PROMPT         usage.prompt_tokens of the second request
+      CACHED         cached tokens reported for the second request
+      CACHE_CONTROL  accepted, rejected, or skipped (not the chat endpoint)
+      CACHED (CACHE_CONTROL)
+                     cached tokens reported for the second cache_control request
+      CACHING        yes if either second request hit the cache; unknown if
+                     the gateway does not report cached tokens at all
+
+    Each run starts the prefix with a unique value, so earlier runs do not
+    produce cache hits. The probe sends about 6,000 prompt tokens per model.
+    Use the cached_input_cost column to see the price of cached input.`)
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/readonly"
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/internal/storage"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["probe-continuation"] = probeContinuationCommand
+}
+
+// probeContinuationCommand はmax_tokensで打ち切られた応答を続きの依頼でつなげられるかを調べ、実効的な最大出力を報告する
+func probeContinuationCommand(args []string) error {
+	probeCmd := flag.NewFlagSet("probe-continuation", flag.ExitOnError)
+	models := probeCmd.String("model", "", "Target model ID, or a comma-separated list of model IDs (required)")
+	baseURL := probeCmd.String("url", "", "Base URL of the LLM gateway")
+	apiKey := probeCmd.String("api-key", "", "API key for authentication")
+	gateway := probeCmd.String("gateway", "", "Gateway name to use from config")
+	timeout := probeCmd.Duration("timeout", 120*time.Second, "Request timeout, overrides timeouts.probe (default: 120s)")
+	configFile := probeCmd.String("config", "", "Path to config file")
+	maxTokens := probeCmd.Int("max-tokens", 0, "max_tokens for each request (default: the saved max output tokens, then 4096)")
+	maxContinuations := probeCmd.Int("max-continuations", probe.DefaultMaxContinuations, "Maximum number of continuation requests after the first response")
+	saveResult := probeCmd.Bool("save-result", false, "Save the result as part of the capabilities result")
+	outputFormat := probeCmd.String("format", "table", "Output format (table, json)")
+	continueOnError := probeCmd.Bool("continue-on-error", false, "Record a model that fails to probe in the report and continue with the next model")
+	lockOpts := addLockFlags(probeCmd)
+	progressOpts := addProgressFlags(probeCmd)
+	showHelp := probeCmd.Bool("help", false, "Show help for probe-continuation command")
+
+	probeCmd.Parse(args)
+
+	if *showHelp {
+		showProbeContinuationHelp()
+		return nil
+	}
+
+	var modelIDs []string
+	for _, id := range strings.Split(*models, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			modelIDs = append(modelIDs, id)
+		}
+	}
+	if len(modelIDs) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: --model is required\n\n")
+		showProbeContinuationHelp()
+		os.Exit(1)
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+	if *maxTokens < 0 {
+		return fmt.Errorf("--max-tokens must be a positive number")
+	}
+	if *maxContinuations <= 0 {
+		return fmt.Errorf("--max-continuations must be a positive number")
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	if err := readonly.Check("probe-continuation"); err != nil {
+		return err
+	}
+	resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+		URL:          *baseURL,
+		APIKey:       *apiKey,
+		Timeout:      *timeout,
+		Gateway:      *gateway,
+		OutputFormat: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	// 同じゲートウェイへの同時探索を防ぐ
+	gatewayLock, err := acquireGatewayLock(configManager, resolved, "probe-continuation", lockOpts)
+	if err != nil {
+		return err
+	}
+	defer gatewayLock.Release()
+
+	client := api.NewProbeClient(newProbeClientConfig(resolved, probeCmd))
+	prober := probe.NewContinuationProbe(client, *maxContinuations)
+
+	// 指定がなければ、保存済みのmax output tokensの探索結果に近い長さを依頼する
+	dir, index, hasIndex := openResultIndex(configManager)
+	requestTokens := func(model string) (int, string) {
+		if *maxTokens > 0 {
+			return *maxTokens, "flag"
+		}
+		if hasIndex {
+			provider := detectProvider(configManager, resolved, model, nil)
+			if value, ok, _ := latestResult(index, dir, provider, []string{model}, storage.ResultTypeMaxOutput); ok && value > 0 {
+				return value, "saved"
+			}
+		}
+		return probe.DefaultContinuationMaxTokens, "default"
+	}
+
+	progress := progressOpts.newProgress()
+	defer progress.Finish()
+
+	var results []*probe.ContinuationResult
+	var failed []string
+	for i, model := range modelIDs {
+		progress.StartItem(model, i+1, len(modelIDs))
+		tokens, source := requestTokens(model)
+		result, err := prober.Probe(model, resolved.Gateway.Name, tokens)
+		if err != nil {
+			if !*continueOnError {
+				return fmt.Errorf("failed to probe continuation for %s: %w", model, err)
+			}
+			// 失敗を記録して次のモデルに進む
+			progress.FinishItem("failed")
+			failed = append(failed, model)
+			results = append(results, &probe.ContinuationResult{
+				Model:           model,
+				Gateway:         resolved.Gateway.Name,
+				MaxTokens:       tokens,
+				MaxTokensSource: source,
+				Segments:        []probe.ContinuationSegment{},
+				ProbedAt:        time.Now(),
+				Error:           redact.String(err.Error()),
+			})
+			continue
+		}
+		result.MaxTokensSource = source
+		results = append(results, result)
+		progress.FinishItem("ok")
+	}
+	progress.Finish()
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printContinuation(results)
+	}
+
+	if *saveResult {
+		for _, result := range results {
+			if result.Error != "" {
+				continue
+			}
+			dir, err := saveCapabilities(configManager, resolved, result.Model, func(c *probe.Capabilities) {
+				c.SetContinuation(result)
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Result for %s saved to: %s\n", result.Model, dir)
+		}
+	}
+
+	if len(failed) > 0 {
+		return batchFailureError(failed, len(modelIDs))
+	}
+	return nil
+}
+
+// printContinuation はモデルごとの応答の連鎖と、続きの依頼でつなげられた出力の合計を表示する
+func printContinuation(results []*probe.ContinuationResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tENDPOINT\tMAX_TOKENS\tRESPONSES\tCONTINUATION\tEFFECTIVE OUTPUT\t")
+	var notes, failures []string
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Fprintf(w, "%s\tFAILED\t%d\t-\t-\t-\t\n", result.Model, result.MaxTokens)
+			failures = append(failures, fmt.Sprintf("  %s: %s", result.Model, result.Error))
+			continue
+		}
+		effective := fmt.Sprintf("%d", result.EffectiveOutputTokens)
+		switch result.Continuation {
+		case probe.ContinuationNotTruncated:
+			effective = "-"
+			notes = append(notes, fmt.Sprintf("  %s: the first response stopped before max_tokens (%d completion tokens)", result.Model, result.Segments[0].CompletionTokens))
+		case probe.ContinuationRejected:
+			notes = append(notes, fmt.Sprintf("  %s: the continuation request failed: %s", result.Model, result.ContinuationError))
+		case probe.ContinuationIncoherent:
+			segment := result.Segments[1]
+			notes = append(notes, fmt.Sprintf("  %s: counting stopped at %d but the continuation started at %d", result.Model, result.Segments[0].LastNumber, segment.FirstNumber))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d (%s)\t%d\t%s\t%s\t\n", result.Model, result.Endpoint,
+			result.MaxTokens, result.MaxTokensSource, len(result.Segments), result.Continuation, effective)
+	}
+	w.Flush()
+
+	if len(notes) > 0 {
+		fmt.Println()
+		fmt.Println(strings.Join(notes, "\n"))
+	}
+	if len(failures) > 0 {
+		fmt.Println("\nFailed:")
+		fmt.Println(strings.Join(failures, "\n"))
+	}
+}
+
+// showProbeContinuationHelp はprobe-continuationコマンドのヘルプを表示する
+func showProbeContinuationHelp() {
+	fmt.Println(`llm-info probe-continuation - Measure output reachable through continuation requests
+
+USAGE:
+    llm-info probe-continuation --model <MODEL_ID>[,<MODEL_ID>...] [flags]
+
+FLAGS:
+    --model string      Target model ID, or a comma-separated list (required)
+    --url string        Base URL of the LLM gateway
+    --api-key string    API key for authentication
+    --gateway string    Gateway name to use from config
+    --timeout duration  Request timeout (default: timeouts.probe, then 120s)
+    --max-tokens int    max_tokens for each request (default: the saved max output
+                        tokens from probe-max-output --save-result, then 4096)
+    --max-continuations int
+                        Maximum number of continuation requests (default: 3)
+    --save-result       Save the result as part of the capabilities result
+    --format string     Output format (table, json) (default: table)
+    --continue-on-error
+                        Record a model that fails to probe and continue with the next one
+    --wait duration     Wait for another probe of the same gateway to finish (default: fail immediately)
+    --force             Take over the gateway lock held by another probe
+    --quiet             Do not show progress
+    --plain             Show progress as log lines instead of a progress bar
+    --config string     Path to config file
+    --help              Show help for probe-continuation command
+
+EXAMPLES:
+    # Check how much output can be chained after probe-max-output --save-result
+    llm-info probe-continuation --model gpt-4o-mini
+
+    # Limit the cost with a smaller max_tokens and a single continuation
+    llm-info probe-continuation --model claude-3-5-sonnet --max-tokens 1024 --max-continuations 1
+
+DESCRIPTION:
+    Asks the model to count upward from 1, one number per line, with
+    max_tokens set near the measured maximum output. When the response is
+    cut off with finish_reason=length, the response is added to the
+    conversation and the model is asked to continue, up to
+    --max-continuations times. A continuation is coherent if it starts
+    right after the last number of the previous response.
+
+      RESPONSES         responses in the chain, including the first one
+      CONTINUATION      coherent, incoherent (the count restarted or jumped),
+                        rejected (the continuation request failed), or
+                        not_truncated (the first response stopped on its own)
+      EFFECTIVE OUTPUT  completion tokens of the first response and the
+                        coherent continuations that followed it
+
+    Each request can generate up to max_tokens completion tokens, so the
+    probe can generate (max-continuations + 1) x max_tokens output tokens
+    per model.`)
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/cost"
+	"github.com/armaniacs/llm-info/internal/ghactions"
+	"github.com/armaniacs/llm-info/internal/logging"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/readonly"
+	"github.com/armaniacs/llm-info/internal/ui"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["probe-max-input"] = probeMaxInputCommand
+}
+
+// probeMaxInputCommand は最大入力トークン数を入力+出力の合計上限と区別して探索する
+func probeMaxInputCommand(args []string) error {
+	probeCmd := flag.NewFlagSet("probe-max-input", flag.ExitOnError)
+	model := probeCmd.String("model", "", "Target model ID (required)")
+	baseURL := probeCmd.String("url", "", "Base URL of the LLM gateway")
+	apiKey := probeCmd.String("api-key", "", "API key for authentication")
+	gateway := probeCmd.String("gateway", "", "Gateway name to use from config")
+	timeout := probeCmd.Duration("timeout", 30*time.Second, "Request timeout, overrides timeouts.probe (default: 30s)")
+	outputReserve := probeCmd.Int("output-reserve", probe.DefaultOutputReserve, "max_tokens to reserve when probing the combined input+output limit")
+	dryRun := probeCmd.Bool("dry-run", false, "Show execution plan and estimated API calls, tokens and cost without making API calls")
+	verbose := probeCmd.Bool("verbose", false, "Show verbose logs")
+	configFile := probeCmd.String("config", "", "Path to config file")
+	logDir := probeCmd.String("log-dir", "", "Directory to save probe logs")
+	noLog := probeCmd.Bool("no-log", false, "Disable logging")
+	logFormat := probeCmd.String("log-format", "", "Probe log format (json, jsonl) (default: storage.log_format, then json)")
+	outputFormat := probeCmd.String("format", "table", "Output format (table, json, or a formatter name from the config)")
+	githubSummary := probeCmd.Bool("github-summary", false, "Write a Markdown summary to $GITHUB_STEP_SUMMARY and emit annotations")
+	historyOut := probeCmd.String("history-out", "", "Write each trial (index, tokens, success, latency, error) as CSV to this file")
+	endpoint := addEndpointFlag(probeCmd)
+	yes := addYesFlag(probeCmd)
+	lockOpts := addLockFlags(probeCmd)
+	progressOpts := addProgressFlags(probeCmd)
+	showHelp := probeCmd.Bool("help", false, "Show help for probe-max-input command")
+
+	probeCmd.Parse(args)
+
+	if *showHelp {
+		showProbeMaxInputHelp()
+		return nil
+	}
+
+	// 必須引数のチェック
+	if *model == "" {
+		fmt.Fprintf(os.Stderr, "Error: --model is required\n\n")
+		showProbeMaxInputHelp()
+		os.Exit(1)
+	}
+	if err := logging.ValidateFormat(*logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		showProbeMaxInputHelp()
+		os.Exit(1)
+	}
+	if err := validateProbeEndpoint(*endpoint); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		showProbeMaxInputHelp()
+		os.Exit(1)
+	}
+	if *outputReserve <= 1 {
+		return fmt.Errorf("--output-reserve must be greater than 1: %d", *outputReserve)
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	if err := readonly.Check("probe-max-input"); err != nil {
+		return err
+	}
+
+	cliArgs := &internalConfig.CLIArgs{
+		URL:          *baseURL,
+		APIKey:       *apiKey,
+		Timeout:      *timeout,
+		Gateway:      *gateway,
+		OutputFormat: "json", // probeではjson固定
+	}
+
+	resolved, err := configManager.ResolveConfig(cliArgs)
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	// 探索を模擬実行して呼び出し回数・トークン数・コストを見積もる
+	estimateCalls := func(assumptions dryRunAssumptions) ([]*probe.CallEstimate, error) {
+		estimate, err := probe.EstimateMaxInputCalls(assumptions.contextWindow, *outputReserve)
+		if err != nil {
+			return nil, err
+		}
+		return []*probe.CallEstimate{estimate}, nil
+	}
+
+	// Dry-runモードの場合は実行計画を表示
+	if *dryRun {
+		showMaxInputExecutionPlan(*model, resolved, *outputReserve)
+
+		assumptions := resolveDryRunAssumptions(configManager, resolved, *model, 0)
+		estimates, err := estimateCalls(assumptions)
+		if err != nil {
+			return fmt.Errorf("failed to estimate probe calls: %w", err)
+		}
+		printDryRunEstimate(resolved, *model, assumptions, estimates)
+		dryRunComplete()
+		return nil
+	}
+
+	// 見積もりコストがspend_confirm_thresholdを超える場合は実行前に確認する
+	if err := confirmSpend(configManager, resolved, "probe-max-input", *model, 0, *yes, estimateCalls); err != nil {
+		return err
+	}
+
+	// 保持ポリシーに従って古いログと結果を整理
+	autoPrune(configManager)
+
+	// 同じゲートウェイへの同時探索を防ぐ
+	gatewayLock, err := acquireGatewayLock(configManager, resolved, "probe-max-input", lockOpts)
+	if err != nil {
+		return err
+	}
+	defer gatewayLock.Release()
+
+	client := api.NewProbeClient(newProbeClientConfig(resolved, probeCmd))
+	prober := probe.NewMaxInputProbe(client)
+
+	var verboseFormatter *ui.VerboseFormatter
+	var progress *ui.Progress
+	if *verbose {
+		verboseFormatter = ui.NewVerboseFormatter()
+		prober.SetVerboseLogger(verboseFormatter)
+		defer verboseFormatter.Finish()
+	} else {
+		progress = progressOpts.startProgress(*model, "max input")
+		prober.SetVerboseLogger(progress)
+		defer progress.Finish()
+	}
+
+	result, err := prober.ProbeInputTokens(*model, *outputReserve)
+	progress.Finish()
+	if err != nil {
+		if *githubSummary {
+			annotateGitHubFailure(probe.ProbeTypeMaxInput, *model, err)
+		}
+		return fmt.Errorf("failed to probe max input tokens: %w", err)
+	}
+
+	// 結果を表示
+	calculator := cost.NewCalculator(resolved.Cost, *model)
+	maxInput := result.ToResult(resolved.Gateway.Name)
+	maxInput.Model = *model
+	maxInput.ApplyCost(calculator)
+	report := probe.NewReport(*model, resolved.Gateway.Name, maxInput)
+	if formatter := pluginFormatter(resolved, *outputFormat); formatter != nil {
+		// 設定ファイルのformattersに登録された外部フォーマッターで整形
+		if err := formatter.Render(os.Stdout, ui.PluginKindProbeReport, resolved.Gateway.Name, report); err != nil {
+			return err
+		}
+	} else if *outputFormat == "json" {
+		if err := writeProbeReportJSON(report); err != nil {
+			return err
+		}
+	} else {
+		formatter := ui.NewTableFormatter()
+		fmt.Println(formatter.FormatMaxInputResult(result))
+		printRunSpend(report)
+
+		// verbose時は履歴も表示
+		if *verbose && len(result.TrialHistory) > 0 {
+			fmt.Println(formatter.FormatVerboseHistory(result.TrialHistory))
+			fmt.Println(formatter.FormatConfidenceEvidence(result.Confidence))
+		}
+	}
+
+	if *verbose {
+		reportConnStats(client)
+	}
+
+	// 試行履歴をCSVで書き出す
+	if *historyOut != "" {
+		if err := writeTrialHistory(*historyOut, probe.TrialHistory{Probe: probe.ProbeTypeMaxInput, Trials: result.TrialHistory}); err != nil {
+			return err
+		}
+	}
+
+	// ログ保存処理
+	if !*noLog {
+		probeConfig := configManager.GetProbeConfig()
+		if *logDir != "" {
+			probeConfig.Log.Dir = *logDir
+		}
+		if *logFormat != "" {
+			probeConfig.Log.Format = *logFormat
+		}
+
+		logger, err := logging.NewProbeLogger(probeConfig.Log.ConvertToProbeLogConfig())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to create logger: %v\n", err)
+		} else {
+			defer logger.Close()
+
+			for i, trial := range result.TrialHistory {
+				if err := logger.LogTrial(*model, resolved.Gateway.Name, probe.ProbeTypeMaxInput, newTrialLogEntry(i, trial)); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to log trial: %v\n", err)
+				}
+			}
+			if err := logger.LogResult(*model, resolved.Gateway.Name, probe.ProbeTypeMaxInput, maxInput); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to log result: %v\n", err)
+			}
+		}
+	}
+
+	// 利用統計（オプトイン）
+	recordTrialSpend(configManager, resolved, *model, trialUsage(result.TrialHistory), nil)
+
+	// GitHub Actions向けサマリー
+	if *githubSummary {
+		writeGitHubSummary(ghactions.ProbeReportSummary(report))
+	}
+
+	// post_probeフック
+	runProbeHook(resolved, report)
+
+	return nil
+}
+
+// showProbeMaxInputHelp はprobe-max-inputコマンドのヘルプを表示する
+func showProbeMaxInputHelp() {
+	fmt.Println(`llm-info probe-max-input - Probe the max input tokens separately from the combined context limit
+
+USAGE:
+    llm-info probe-max-input --model <MODEL_ID> [flags]
+
+FLAGS:
+    --model string          Target model ID (required)
+    --url string            Base URL of the LLM gateway
+    --api-key string        API key for authentication
+    --gateway string        Gateway name to use from config
+    --timeout duration      Request timeout (default: timeouts.probe, then 30s)
+    --output-reserve int    max_tokens to reserve when probing the combined limit (default: 4096)
+    --dry-run               Show execution plan and estimated API calls, tokens and cost
+    --yes                   Don't ask for confirmation when the estimated cost exceeds
+                            spend_confirm_threshold
+    --verbose               Show verbose logs
+    --log-dir string        Directory to save probe logs
+    --no-log                Disable logging
+    --log-format string     Log format: json or jsonl (one flat record per trial) (default: json)
+    --format string         Output format (table, json, or a formatter name from the config) (default: table)
+    --github-summary        Write Markdown summary to $GITHUB_STEP_SUMMARY
+    --history-out string    Write each trial as CSV (probe, index, tokens, success, latency, error)
+    --endpoint string       Probe endpoint: chat, completions, responses, auto (default: probe_endpoint of the gateway, then chat)
+    --wait duration         Wait for another probe of the same gateway to finish (default: fail immediately)
+    --force                 Take over the gateway lock held by another probe
+    --quiet                 Do not show progress
+    --plain                 Show progress as log lines instead of a progress bar
+    --config string         Path to config file
+    --help                  Show help for probe-max-input command
+
+EXAMPLES:
+    # Basic usage
+    llm-info probe-max-input --model gpt-4o-mini
+
+    # Reserve more output tokens so the combined limit is reached
+    llm-info probe-max-input --model gpt-4o-mini --output-reserve 32768
+
+    # JSON output
+    llm-info probe-max-input --model gpt-4o-mini --format json
+
+DESCRIPTION:
+    Some gateways cap the prompt on its own in addition to the combined
+    input+output context limit. This command runs two searches:
+
+      1. Input limit: the largest prompt accepted with max_tokens=1, so the
+         output reservation does not count against the context.
+      2. Combined limit: the largest prompt accepted with max_tokens set to
+         --output-reserve, plus that reserve.
+
+    When the input limit is clearly below the combined limit, the gateway
+    enforces them independently. If the prompt is still capped by the input
+    limit in the second search, the combined limit is shown as a lower bound;
+    raise --output-reserve to measure it.`)
+}
+
+// showMaxInputExecutionPlan は最大入力トークン探索の実行計画を表示する
+func showMaxInputExecutionPlan(model string, config *internalConfig.ResolvedConfig, outputReserve int) {
+	fmt.Printf("Max Input Tokens Probe Execution Plan:\n")
+	fmt.Printf("  Model: %s\n", model)
+	fmt.Printf("  URL: %s\n", config.Gateway.URL)
+	fmt.Printf("  API Key: %s\n", maskAPIKey(config.Gateway.APIKey))
+	fmt.Printf("  Timeout: %s\n", config.Gateway.Timeout)
+	fmt.Printf("\nProbe Phases:\n")
+	fmt.Printf("  1. Input Limit: Exponential + binary search over prompt size with max_tokens=1\n")
+	fmt.Printf("  2. Combined Limit: Same search with max_tokens=%d, limit = prompt + reserve\n", outputReserve)
+	fmt.Printf("  3. Comparison: Report whether the input limit is enforced separately\n")
+	fmt.Printf("\nAPI Calls:\n")
+	fmt.Printf("  POST %s/v1/chat/completions\n", config.Gateway.URL)
+	fmt.Printf("  - Varying input length (4096→8192→16384...)\n")
+	fmt.Printf("  - Rate limited: 0.5s between binary search calls\n")
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/readonly"
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["probe-overhead"] = probeOverheadCommand
+}
+
+// probeOverheadCommand はゲートウェイが追加したプロンプト（システムプロンプトなど）のトークン数をモデルごとに測定する
+func probeOverheadCommand(args []string) error {
+	probeCmd := flag.NewFlagSet("probe-overhead", flag.ExitOnError)
+	models := probeCmd.String("model", "", "Target model ID, or a comma-separated list of model IDs (required)")
+	baseURL := probeCmd.String("url", "", "Base URL of the LLM gateway")
+	apiKey := probeCmd.String("api-key", "", "API key for authentication")
+	gateway := probeCmd.String("gateway", "", "Gateway name to use from config")
+	timeout := probeCmd.Duration("timeout", 30*time.Second, "Request timeout, overrides timeouts.probe (default: 30s)")
+	configFile := probeCmd.String("config", "", "Path to config file")
+	saveResult := probeCmd.Bool("save-result", false, "Save the result as part of the capabilities result")
+	outputFormat := probeCmd.String("format", "table", "Output format (table, json)")
+	continueOnError := probeCmd.Bool("continue-on-error", false, "Record a model that fails to probe in the report and continue with the next model")
+	lockOpts := addLockFlags(probeCmd)
+	progressOpts := addProgressFlags(probeCmd)
+	showHelp := probeCmd.Bool("help", false, "Show help for probe-overhead command")
+
+	probeCmd.Parse(args)
+
+	if *showHelp {
+		showProbeOverheadHelp()
+		return nil
+	}
+
+	var modelIDs []string
+	for _, id := range strings.Split(*models, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			modelIDs = append(modelIDs, id)
+		}
+	}
+	if len(modelIDs) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: --model is required\n\n")
+		showProbeOverheadHelp()
+		os.Exit(1)
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	if err := readonly.Check("probe-overhead"); err != nil {
+		return err
+	}
+	resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+		URL:          *baseURL,
+		APIKey:       *apiKey,
+		Timeout:      *timeout,
+		Gateway:      *gateway,
+		OutputFormat: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	// 同じゲートウェイへの同時探索を防ぐ
+	gatewayLock, err := acquireGatewayLock(configManager, resolved, "probe-overhead", lockOpts)
+	if err != nil {
+		return err
+	}
+	defer gatewayLock.Release()
+
+	client := api.NewProbeClient(newProbeClientConfig(resolved, probeCmd))
+	prober := probe.NewPromptOverheadProbe(client)
+
+	progress := progressOpts.newProgress()
+	defer progress.Finish()
+
+	var results []*probe.PromptOverheadResult
+	var failed []string
+	for i, model := range modelIDs {
+		progress.StartItem(model, i+1, len(modelIDs))
+		result, err := prober.Probe(model, resolved.Gateway.Name)
+		if err != nil {
+			if !*continueOnError {
+				return fmt.Errorf("failed to measure prompt overhead for %s: %w", model, err)
+			}
+			// 失敗を記録して次のモデルに進む
+			progress.FinishItem("failed")
+			failed = append(failed, model)
+			results = append(results, &probe.PromptOverheadResult{
+				Model:    model,
+				Gateway:  resolved.Gateway.Name,
+				Samples:  []probe.OverheadSample{},
+				ProbedAt: time.Now(),
+				Error:    redact.String(err.Error()),
+			})
+			continue
+		}
+		results = append(results, result)
+		progress.FinishItem("ok")
+	}
+	progress.Finish()
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printPromptOverhead(results)
+	}
+
+	if *saveResult {
+		for _, result := range results {
+			if result.Error != "" {
+				continue
+			}
+			dir, err := saveCapabilities(configManager, resolved, result.Model, func(c *probe.Capabilities) {
+				c.SetPromptOverhead(result)
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Result for %s saved to: %s\n", result.Model, dir)
+		}
+	}
+
+	if len(failed) > 0 {
+		return batchFailureError(failed, len(modelIDs))
+	}
+	return nil
+}
+
+// printPromptOverhead はモデルごとの見積もりと報告されたprompt_tokens、その差を表示する
+func printPromptOverhead(results []*probe.PromptOverheadResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tENDPOINT\tESTIMATED\tREPORTED\tOVERHEAD\tTOKENS/WORD\t")
+	var significant, failures []string
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Fprintf(w, "%s\tFAILED\t-\t-\t-\t-\t\n", result.Model)
+			failures = append(failures, fmt.Sprintf("  %s: %s", result.Model, result.Error))
+			continue
+		}
+		sample := result.Samples[0]
+		mark := ""
+		if result.Significant {
+			mark = "⚠️"
+			significant = append(significant, fmt.Sprintf("  %s: ~%d tokens are added to every request", result.Model, result.Overhead))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%+d\t%.2f\t%s\n", result.Model, result.Endpoint,
+			sample.EstimatedTokens, sample.PromptTokens, result.Overhead, result.TokensPerWord, mark)
+	}
+	w.Flush()
+
+	if len(significant) > 0 {
+		fmt.Printf("\nThe gateway appears to inject a system prompt or other text (≥%d tokens), which shrinks the usable context:\n", probe.SignificantOverhead)
+		fmt.Println(strings.Join(significant, "\n"))
+	}
+	if len(failures) > 0 {
+		fmt.Println("\nFailed:")
+		fmt.Println(strings.Join(failures, "\n"))
+	}
+}
+
+// showProbeOverheadHelp はprobe-overheadコマンドのヘルプを表示する
+func showProbeOverheadHelp() {
+	fmt.Println(`llm-info probe-overhead - Measure prompt tokens added by the gateway
+
+USAGE:
+    llm-info probe-overhead --model <MODEL_ID>[,<MODEL_ID>...] [flags]
+
+FLAGS:
+    --model string      Target model ID, or a comma-separated list (required)
+    --url string        Base URL of the LLM gateway
+    --api-key string    API key for authentication
+    --gateway string    Gateway name to use from config
+    --timeout duration  Request timeout (default: timeouts.probe, then 30s)
+    --save-result       Save the result as part of the capabilities result
+    --format string     Output format (table, json) (default: table)
+    --continue-on-error
+                        Record a model that fails to probe and continue with the next one
+    --wait duration     Wait for another probe of the same gateway to finish (default: fail immediately)
+    --force             Take over the gateway lock held by another probe
+    --quiet             Do not show progress
+    --plain             Show progress as log lines instead of a progress bar
+    --config string     Path to config file
+    --help              Show help for probe-overhead command
+
+EXAMPLES:
+    # Check whether the proxy injects a system prompt
+    llm-info probe-overhead --model gpt-4o-mini
+
+    # Compare several models behind the same gateway
+    llm-info probe-overhead --model gpt-4o-mini,claude-3-haiku --gateway production --format json
+
+DESCRIPTION:
+    Sends two small requests (max_tokens=16) whose prompts repeat a word that
+    is a single token in common tokenizers, 1 and 201 times, and compares the
+    tokens we sent (the words plus about 7 tokens of chat template) with the
+    usage.prompt_tokens the gateway reports.
+
+      ESTIMATED    tokens we think we sent with the 1-word prompt
+      REPORTED     usage.prompt_tokens for the same request
+      OVERHEAD     REPORTED - ESTIMATED: tokens added by the gateway or model
+      TOKENS/WORD  measured from the difference between the two requests;
+                   the estimate is corrected with it if it is not 1.00
+
+    A few tokens of overhead come from differences in chat templates. An
+    overhead of 16 tokens or more usually means that the gateway or proxy
+    injects a system prompt, which is sent with every request and reduces
+    the context window available to you by the same amount.`)
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/readonly"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["probe-params"] = probeParamsCommand
+}
+
+// probeParamsCommand はリクエストパラメータがゲートウェイで反映されるかを調べる
+func probeParamsCommand(args []string) error {
+	probeCmd := flag.NewFlagSet("probe-params", flag.ExitOnError)
+	model := probeCmd.String("model", "", "Target model ID (required)")
+	baseURL := probeCmd.String("url", "", "Base URL of the LLM gateway")
+	apiKey := probeCmd.String("api-key", "", "API key for authentication")
+	gateway := probeCmd.String("gateway", "", "Gateway name to use from config")
+	timeout := probeCmd.Duration("timeout", 30*time.Second, "Request timeout, overrides timeouts.probe (default: 30s)")
+	configFile := probeCmd.String("config", "", "Path to config file")
+	saveResult := probeCmd.Bool("save-result", false, "Save the result as part of the capabilities result")
+	outputFormat := probeCmd.String("format", "table", "Output format (table, json)")
+	require := probeCmd.String("require", "", "Comma-separated parameters that must be supported; exit with an error otherwise")
+	lockOpts := addLockFlags(probeCmd)
+	showHelp := probeCmd.Bool("help", false, "Show help for probe-params command")
+
+	probeCmd.Parse(args)
+
+	if *showHelp {
+		showProbeParamsHelp()
+		return nil
+	}
+
+	if *model == "" {
+		fmt.Fprintf(os.Stderr, "Error: --model is required\n\n")
+		showProbeParamsHelp()
+		os.Exit(1)
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	required, err := parseRequiredParams(*require)
+	if err != nil {
+		return err
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	if err := readonly.Check("probe-params"); err != nil {
+		return err
+	}
+	resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+		URL:          *baseURL,
+		APIKey:       *apiKey,
+		Timeout:      *timeout,
+		Gateway:      *gateway,
+		OutputFormat: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	// 同じゲートウェイへの同時探索を防ぐ
+	gatewayLock, err := acquireGatewayLock(configManager, resolved, "probe-params", lockOpts)
+	if err != nil {
+		return err
+	}
+	defer gatewayLock.Release()
+
+	client := api.NewProbeClient(newProbeClientConfig(resolved, probeCmd))
+	prober := probe.NewParamSupportProbe(client)
+
+	fmt.Fprintf(os.Stderr, "Probing request parameters for model %s...\n", *model)
+	probedAt := time.Now()
+	results, err := prober.Probe(*model)
+	if err != nil {
+		return fmt.Errorf("failed to probe parameters for %s: %w", *model, err)
+	}
+
+	capabilities := probe.NewCapabilities(*model, resolved.Gateway.Name)
+	capabilities.SetParameters(results, probedAt)
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(capabilities); err != nil {
+			return err
+		}
+	} else {
+		printParamSupport(results)
+	}
+
+	if *saveResult {
+		dir, err := saveCapabilities(configManager, resolved, *model, func(c *probe.Capabilities) {
+			c.SetParameters(results, probedAt)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "Result saved to: %s\n", dir)
+		}
+	}
+
+	// 必須パラメータの確認
+	var missing []string
+	for _, name := range required {
+		if status := capabilities.Parameter(name); status != probe.ParamSupported {
+			missing = append(missing, fmt.Sprintf("%s (%s)", name, status))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("required parameters are not supported: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// parseRequiredParams は--requireの値を解析し、探索対象外のパラメータがあればエラーを返す
+func parseRequiredParams(value string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		known := false
+		for _, c := range probe.ParamSupportCases {
+			if c.Name == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown parameter in --require: %s", name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// printParamSupport はパラメータごとの対応状況を表示する
+func printParamSupport(results []probe.ParamSupportResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PARAMETER\tSTATUS\tEVIDENCE")
+	for _, result := range results {
+		mark := "✓"
+		switch result.Status {
+		case probe.ParamIgnored:
+			mark = "⚠"
+		case probe.ParamRejected:
+			mark = "✗"
+		}
+		fmt.Fprintf(w, "%s\t%s %s\t%s\n", result.Parameter, mark, result.Status, result.Evidence)
+	}
+	w.Flush()
+
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Printf("\n%s: %s", result.Parameter, result.Error)
+		}
+	}
+	fmt.Println()
+}
+
+// showProbeParamsHelp はprobe-paramsコマンドのヘルプを表示する
+func showProbeParamsHelp() {
+	fmt.Println(`llm-info probe-params - Check which request parameters a gateway honors
+
+USAGE:
+    llm-info probe-params --model <MODEL_ID> [flags]
+
+FLAGS:
+    --model string      Target model ID (required)
+    --url string        Base URL of the LLM gateway
+    --api-key string    API key for authentication
+    --gateway string    Gateway name to use from config
+    --timeout duration  Request timeout (default: timeouts.probe, then 30s)
+    --save-result       Save the result as part of the capabilities result
+    --format string     Output format (table, json) (default: table)
+    --require string    Comma-separated parameters that must be supported
+    --wait duration     Wait for another probe of the same gateway to finish (default: fail immediately)
+    --force             Take over the gateway lock held by another probe
+    --config string     Path to config file
+    --help              Show help for probe-params command
+
+EXAMPLES:
+    llm-info probe-params --model gpt-4o-mini
+    llm-info probe-params --model gpt-4o-mini --gateway production --save-result
+
+    # Fail (exit 1) unless stop sequences and n>1 sampling work
+    llm-info probe-params --model gpt-4o-mini --require stop,n
+
+DESCRIPTION:
+    Tests temperature, top_p, frequency_penalty, presence_penalty, seed,
+    logprobs, stop and n, and classifies each one:
+
+      supported  the value is reflected in the response (logprobs, stop, n),
+                 or an out-of-range value is rejected, so the parameter is parsed
+      ignored    requests succeed but the parameter has no visible effect;
+                 even an out-of-range value is accepted, so it is likely dropped
+      rejected   the request fails even with a valid value
+
+    Each parameter costs one or two small requests (max_tokens=16). stop asks
+    the model to count from 1 to 10 with stop=["5"] (max_tokens=40) and checks
+    that the output ends before "5"; n requests n=2 and checks that two
+    choices are returned.
+
+    With --save-result the classification is stored in the capabilities
+    result for the model, next to the latest probe-roles result. Use
+    'llm-info results --type capabilities --latest' to read it back.`)
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/readonly"
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["probe-roles"] = probeRolesCommand
+}
+
+// probeRolesCommand はメッセージのロールやフィールドの互換性を調べ、モデルごとの対応表を表示する
+func probeRolesCommand(args []string) error {
+	probeCmd := flag.NewFlagSet("probe-roles", flag.ExitOnError)
+	models := probeCmd.String("model", "", "Target model ID, or a comma-separated list of model IDs (required)")
+	baseURL := probeCmd.String("url", "", "Base URL of the LLM gateway")
+	apiKey := probeCmd.String("api-key", "", "API key for authentication")
+	gateway := probeCmd.String("gateway", "", "Gateway name to use from config")
+	timeout := probeCmd.Duration("timeout", 30*time.Second, "Request timeout, overrides timeouts.probe (default: 30s)")
+	configFile := probeCmd.String("config", "", "Path to config file")
+	saveResult := probeCmd.Bool("save-result", false, "Save the result as part of the capabilities result")
+	outputFormat := probeCmd.String("format", "table", "Output format (table, json)")
+	continueOnError := probeCmd.Bool("continue-on-error", false, "Record a model that fails to probe in the report and continue with the next model")
+	lockOpts := addLockFlags(probeCmd)
+	progressOpts := addProgressFlags(probeCmd)
+	showHelp := probeCmd.Bool("help", false, "Show help for probe-roles command")
+
+	probeCmd.Parse(args)
+
+	if *showHelp {
+		showProbeRolesHelp()
+		return nil
+	}
+
+	var modelIDs []string
+	for _, id := range strings.Split(*models, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			modelIDs = append(modelIDs, id)
+		}
+	}
+	if len(modelIDs) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: --model is required\n\n")
+		showProbeRolesHelp()
+		os.Exit(1)
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	if err := readonly.Check("probe-roles"); err != nil {
+		return err
+	}
+	resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+		URL:          *baseURL,
+		APIKey:       *apiKey,
+		Timeout:      *timeout,
+		Gateway:      *gateway,
+		OutputFormat: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	// 同じゲートウェイへの同時探索を防ぐ
+	gatewayLock, err := acquireGatewayLock(configManager, resolved, "probe-roles", lockOpts)
+	if err != nil {
+		return err
+	}
+	defer gatewayLock.Release()
+
+	client := api.NewProbeClient(newProbeClientConfig(resolved, probeCmd))
+	prober := probe.NewRoleCompatProbe(client)
+
+	progress := progressOpts.newProgress()
+	defer progress.Finish()
+
+	var reports []*probe.RoleCompatReport
+	var failed []string
+	for i, model := range modelIDs {
+		progress.StartItem(model, i+1, len(modelIDs))
+		report, err := prober.Probe(model, resolved.Gateway.Name)
+		if err != nil {
+			if !*continueOnError {
+				return fmt.Errorf("failed to probe message roles for %s: %w", model, err)
+			}
+			// 失敗を記録して次のモデルに進む
+			progress.FinishItem("failed")
+			failed = append(failed, model)
+			reports = append(reports, &probe.RoleCompatReport{
+				Model:    model,
+				Gateway:  resolved.Gateway.Name,
+				Results:  []probe.RoleCompatResult{},
+				ProbedAt: time.Now(),
+				Error:    redact.String(err.Error()),
+			})
+			continue
+		}
+		reports = append(reports, report)
+		progress.FinishItem("ok")
+	}
+	progress.Finish()
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		printRoleCompatMatrix(reports)
+	}
+
+	if *saveResult {
+		for _, report := range reports {
+			if report.Error != "" {
+				continue
+			}
+			dir, err := saveCapabilities(configManager, resolved, report.Model, func(c *probe.Capabilities) {
+				c.SetRoles(report)
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Result for %s saved to: %s\n", report.Model, dir)
+		}
+	}
+
+	if len(failed) > 0 {
+		return batchFailureError(failed, len(modelIDs))
+	}
+	return nil
+}
+
+// printRoleCompatMatrix はケースごと・モデルごとの対応表と拒否理由を表示する
+func printRoleCompatMatrix(reports []*probe.RoleCompatReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	header := []string{"CASE"}
+	for _, report := range reports {
+		header = append(header, report.Model)
+	}
+	header = append(header, "DESCRIPTION")
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	for _, c := range probe.RoleCompatCases {
+		row := []string{c.Name}
+		for _, report := range reports {
+			accepted, ok := report.Accepted(c.Name)
+			switch {
+			case !ok:
+				row = append(row, "-")
+			case accepted && c.ExpectReject:
+				row = append(row, "✓ (not validated)")
+			case accepted:
+				row = append(row, "✓")
+			default:
+				row = append(row, "✗")
+			}
+		}
+		row = append(row, c.Description)
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+
+	// 拒否理由
+	var rejections []string
+	for _, report := range reports {
+		for _, result := range report.Results {
+			if !result.Accepted {
+				rejections = append(rejections, fmt.Sprintf("  %s / %s: %s", report.Model, result.Case, result.Error))
+			}
+		}
+	}
+	if len(rejections) > 0 {
+		fmt.Println("\nRejections:")
+		fmt.Println(strings.Join(rejections, "\n"))
+	}
+
+	// --continue-on-errorで記録した失敗
+	var failures []string
+	for _, report := range reports {
+		if report.Error != "" {
+			failures = append(failures, fmt.Sprintf("  %s: %s", report.Model, report.Error))
+		}
+	}
+	if len(failures) > 0 {
+		fmt.Println("\nFailed:")
+		fmt.Println(strings.Join(failures, "\n"))
+	}
+}
+
+// showProbeRolesHelp はprobe-rolesコマンドのヘルプを表示する
+func showProbeRolesHelp() {
+	fmt.Println(`llm-info probe-roles - Check which message roles and fields a model accepts
+
+USAGE:
+    llm-info probe-roles --model <MODEL_ID>[,<MODEL_ID>...] [flags]
+
+FLAGS:
+    --model string      Target model ID, or a comma-separated list (required)
+    --url string        Base URL of the LLM gateway
+    --api-key string    API key for authentication
+    --gateway string    Gateway name to use from config
+    --timeout duration  Request timeout (default: timeouts.probe, then 30s)
+    --save-result       Save the result as part of the capabilities result
+    --format string     Output format (table, json) (default: table)
+    --continue-on-error
+                        Record a model that fails to probe and continue with the next one
+    --wait duration     Wait for another probe of the same gateway to finish (default: fail immediately)
+    --force             Take over the gateway lock held by another probe
+    --quiet             Do not show progress
+    --plain             Show progress as log lines instead of a progress bar
+    --config string     Path to config file
+    --help              Show help for probe-roles command
+
+EXAMPLES:
+    # Compatibility matrix for one model
+    llm-info probe-roles --model gpt-4o-mini
+
+    # Compare several models behind the same gateway
+    llm-info probe-roles --model gpt-4o-mini,claude-3-haiku --gateway production
+
+DESCRIPTION:
+    Sends one small request (max_tokens=16) per case and records whether the
+    gateway accepts it:
+
+      user               single user message (baseline)
+      system             system message before user
+      developer          developer message before user
+      multiple_system    two system messages before user
+      system_after_user  system message after the first user message
+      name_field         user message with a name field
+      assistant_prefill  conversation ending with an assistant message
+      empty_user         user message with empty content
+      empty_system       system message with empty content
+
+    An empty user message should be rejected; "✓ (not validated)" means the
+    gateway passed it through. If the baseline request fails the probe stops,
+    since the problem is the connection or credentials rather than the roles.
+    With several models that failure stops the whole run unless
+    --continue-on-error is given; then the model is listed under "Failed"
+    (an "error" field in JSON) and the command exits with status 1.`)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/armaniacs/llm-info/internal/ui"
+)
+
+// progressFlags は時間のかかるコマンド共通の進捗表示フラグ
+type progressFlags struct {
+	quiet *bool
+	plain *bool
+}
+
+// addProgressFlags は--quietと--plainを登録する
+func addProgressFlags(fs *flag.FlagSet) *progressFlags {
+	return &progressFlags{
+		quiet: fs.Bool("quiet", false, "Do not show progress"),
+		plain: fs.Bool("plain", false, "Show progress as log lines instead of a progress bar"),
+	}
+}
+
+// newProgress は進捗表示を作成する。--quietの場合はnilを返す（何も表示しない）
+// 標準エラー出力が端末でない場合（CIやリダイレクト）は--plainと同じ行単位の表示にする
+func (f *progressFlags) newProgress() *ui.Progress {
+	if *f.quiet {
+		return nil
+	}
+	return ui.NewProgress(os.Stderr, *f.plain || !isTerminal(os.Stderr))
+}
+
+// startProgress は1つのモデルの探索の進捗表示を開始する
+func (f *progressFlags) startProgress(model, phase string) *ui.Progress {
+	progress := f.newProgress()
+	progress.StartItem(model, 1, 1)
+	progress.SetPhase(phase)
+	return progress
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/http"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/provider"
+)
+
+// providerDetector は設定ファイルのprovidersセクションを反映したプロバイダー名の判定を返す
+func providerDetector(configManager *internalConfig.Manager) *provider.Detector {
+	return provider.NewDetector(configManager.GetProvidersConfig())
+}
+
+// detectProvider は探索結果を保存するプロバイダー名を判定する
+// モデルのメタデータはキャッシュ済みのモデル一覧から探し、ネットワークには接続しない
+// headerには探索リクエストのレスポンスヘッダーを渡す（ない場合はnil）
+func detectProvider(configManager *internalConfig.Manager, resolved *internalConfig.ResolvedConfig, modelID string, header http.Header) string {
+	return providerDetector(configManager).Detect(provider.Evidence{
+		URL:      resolved.Gateway.URL,
+		Metadata: cachedModelMetadata(configManager, resolved, modelID),
+		Header:   header,
+	})
+}
+
+// cachedModelMetadata はキャッシュ済みのモデル一覧からモデルのメタデータを返す
+func cachedModelMetadata(configManager *internalConfig.Manager, resolved *internalConfig.ResolvedConfig, modelID string) map[string]interface{} {
+	catalog, err := catalogCache(configManager)
+	if err != nil {
+		return nil
+	}
+	entry, err := catalog.Load(resolved.Gateway.URL)
+	if err != nil {
+		return nil
+	}
+	for _, m := range model.FromAPIResponse(entry.Models) {
+		if m.Name == modelID {
+			return m.Metadata
+		}
+	}
+	return nil
+}
+
+// modelProvider はモデル一覧のモデルのプロバイダー名を判定する
+// 保存済みの探索結果を探すときに使う（レスポンスヘッダーは使えない）
+func modelProvider(detector *provider.Detector, resolved *internalConfig.ResolvedConfig, m model.Model) string {
+	return detector.Detect(provider.Evidence{URL: resolved.Gateway.URL, Metadata: m.Metadata})
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/storage"
+)
+
+// loadRetentionPolicy は設定ファイルのstorageセクションから保持ポリシーと対象ディレクトリを取得する
+func loadRetentionPolicy(configManager *internalConfig.Manager) (storage.RetentionPolicy, []string, error) {
+	probeConfig := configManager.GetProbeConfig()
+	dirs := []string{probeConfig.Result.Dir, probeConfig.Log.Dir}
+
+	policy, err := internalConfig.ToRetentionPolicy(configManager.GetStorageConfig().Retention)
+	if err != nil {
+		return storage.RetentionPolicy{}, dirs, err
+	}
+	return policy, dirs, nil
+}
+
+// pruneDirs は保持ポリシーに従って各ディレクトリのファイルを削除する
+func pruneDirs(dirs []string, policy storage.RetentionPolicy, dryRun bool) []*storage.PruneResult {
+	var results []*storage.PruneResult
+	for _, dir := range dirs {
+		expanded, err := storage.ExpandPath(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to prune %s: %v\n", dir, err)
+			continue
+		}
+		result, err := storage.Prune(expanded, policy, dryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to prune %s: %v\n", dir, err)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// autoPrune は起動時に保持ポリシーを適用する（ポリシー未設定の場合は何もしない）
+func autoPrune(configManager *internalConfig.Manager) {
+	policy, dirs, err := loadRetentionPolicy(configManager)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: invalid storage retention settings: %v\n", err)
+		return
+	}
+	if policy.IsZero() {
+		return
+	}
+	pruneDirs(dirs, policy, false)
+}
+
+// pruneStoredFiles は--pruneで保持ポリシーを適用し、結果を表示する
+func pruneStoredFiles(configFile string) error {
+	configManager := loadProbeConfigManager(configFile)
+
+	policy, dirs, err := loadRetentionPolicy(configManager)
+	if err != nil {
+		return err
+	}
+	if policy.IsZero() {
+		fmt.Println("⚠️  保持ポリシーが設定されていません")
+		fmt.Println("設定ファイルの storage.retention に max_files / max_age / max_total_size を設定してください。")
+		return nil
+	}
+
+	for _, result := range pruneDirs(dirs, policy, false) {
+		fmt.Printf("%s\n", result.Dir)
+		fmt.Printf("  削除: %d ファイル (%s)\n", len(result.Removed), storage.FormatSize(result.FreedBytes))
+		fmt.Printf("  残り: %d ファイル\n", result.Remaining)
+	}
+
+	return nil
+}
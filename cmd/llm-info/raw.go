@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/internal/ui"
+)
+
+// --rawの指定
+const (
+	rawOff    = ""
+	rawOnly   = "only"   // 正規化した一覧の代わりに受信したままのレスポンスを出力する
+	rawPretty = "pretty" // rawOnlyと同じだが、JSONを整形して出力する
+	rawBoth   = "both"   // 正規化した一覧の後に、整形したレスポンスを続けて出力する
+)
+
+// rawFlag は--raw（値なし）と--raw=pretty|bothの両方を受け付けるフラグ
+type rawFlag struct {
+	mode string
+}
+
+func (r *rawFlag) String() string {
+	if r == nil {
+		return ""
+	}
+	return r.mode
+}
+
+// Set は--rawの値を設定する（値なしの--rawはtrueとして渡される）
+func (r *rawFlag) Set(value string) error {
+	switch value {
+	case "true", rawOnly:
+		r.mode = rawOnly
+	case "false":
+		r.mode = rawOff
+	case rawPretty, rawBoth:
+		r.mode = value
+	default:
+		return fmt.Errorf("invalid --raw value: %s (valid: pretty, both)", value)
+	}
+	return nil
+}
+
+// IsBoolFlag は値なしの--rawを受け付けるために実装する
+func (r *rawFlag) IsBoolFlag() bool {
+	return true
+}
+
+// printRawResponses はゲートウェイから受信したままのレスポンスボディを出力する
+// rawOnlyではボディをそのまま出力し、取得元のエンドポイントは標準エラー出力に表示する（標準出力はjqなどにそのまま渡せる）
+// rawBothでは正規化した一覧と区別できるよう、エンドポイントの見出しも標準出力に出す
+// APIキーに見える値は、他の出力と同じく伏せる
+func printRawResponses(w io.Writer, responses []api.RawResponse, mode string) error {
+	for _, raw := range responses {
+		body := raw.Body
+		if mode != rawOnly {
+			var indented bytes.Buffer
+			if err := json.Indent(&indented, raw.Body, "", "  "); err == nil {
+				body = indented.Bytes()
+			}
+		}
+		body = []byte(redact.String(string(body)))
+
+		if mode == rawBoth {
+			fmt.Fprintf(w, "\n--- Raw response: GET %s ---\n", raw.Endpoint)
+		} else {
+			ui.Infof("# GET %s", raw.Endpoint)
+		}
+		if _, err := w.Write(body); err != nil {
+			return err
+		}
+		if !bytes.HasSuffix(body, []byte("\n")) {
+			fmt.Fprintln(w)
+		}
+	}
+	return nil
+}
+
+// printRawAfterList は--raw=bothの場合に、表示した一覧の後にレスポンスを出力する
+func printRawAfterList(mode string, responses []api.RawResponse) {
+	if mode != rawBoth {
+		return
+	}
+	if err := printRawResponses(os.Stdout, responses, mode); err != nil {
+		ui.Warnf("Warning: failed to print raw responses: %v", err)
+	}
+}
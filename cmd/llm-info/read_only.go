@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// extractReadOnlyFlag は引数から--read-onlyを取り除き、指定されたかを返す
+// --read-onlyは全コマンド共通のため、各コマンドのフラグ解析より前に取り出す（--read-only=falseも受け付ける）
+func extractReadOnlyFlag(args []string) (bool, []string, error) {
+	readOnly := false
+	rest := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "read-only" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			readOnly = true
+			continue
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, nil, fmt.Errorf("invalid --read-only value: %s", value)
+		}
+		readOnly = enabled
+	}
+	return readOnly, rest, nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/storage"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["results"] = resultsCommand
+}
+
+// resultsCommand は保存済みの探索結果をインデックスから検索する
+func resultsCommand(args []string) error {
+	if len(args) > 0 && args[0] == "import" {
+		return resultsImportCommand(args[1:])
+	}
+	if len(args) > 0 && args[0] == "sync" {
+		return resultsSyncCommand(args[1:])
+	}
+	if len(args) > 0 && args[0] == "trend" {
+		return resultsTrendCommand(args[1:])
+	}
+
+	resultsCmd := flag.NewFlagSet("results", flag.ExitOnError)
+	provider := resultsCmd.String("provider", "", "Filter by provider name")
+	model := resultsCmd.String("model", "", "Filter by model ID")
+	resultType := resultsCmd.String("type", "", "Filter by probe type (context, max_output, capabilities, multi_turn)")
+	since := resultsCmd.Duration("since", 0, "Only show results saved within this duration (e.g. 168h)")
+	limit := resultsCmd.Int("limit", 20, "Maximum number of results to show (0 for all)")
+	latest := resultsCmd.Bool("latest", false, "Print the full content of the newest matching result")
+	rebuildIndex := resultsCmd.Bool("rebuild-index", false, "Rebuild the index from the files on disk")
+	outputFormat := resultsCmd.String("format", "table", "Output format (table, json)")
+	resultDir := resultsCmd.String("result-dir", "", "Directory where probe results are saved")
+	configFile := resultsCmd.String("config", "", "Path to config file")
+	showHelp := resultsCmd.Bool("help", false, "Show help for results command")
+
+	resultsCmd.Parse(args)
+
+	if *showHelp {
+		showResultsHelp()
+		return nil
+	}
+
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	typeFilter, err := parseResultType(*resultType)
+	if err != nil {
+		return err
+	}
+
+	probeConfig := loadProbeConfigManager(*configFile).GetProbeConfig()
+	dir := *resultDir
+	if dir == "" {
+		dir = probeConfig.Result.Dir
+	}
+	dir, err = storage.ExpandPath(dir)
+	if err != nil {
+		return err
+	}
+
+	// 共有の保存先があれば、他のメンバーの結果をローカルのキャッシュに取得してから検索する
+	if probeConfig.Result.Remote.Enabled() && !*rebuildIndex {
+		pullSharedResults(dir, probeConfig.Result.StorageOptions())
+	}
+
+	var index *storage.Index
+	if *rebuildIndex {
+		index, err = storage.RebuildIndex(dir)
+	} else {
+		index, err = storage.OpenIndex(dir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open result index: %w", err)
+	}
+
+	query := storage.IndexQuery{
+		Provider: *provider,
+		Model:    *model,
+		Type:     typeFilter,
+		Limit:    *limit,
+	}
+	if *since > 0 {
+		query.Since = time.Now().Add(-*since)
+	}
+	if *latest {
+		query.Limit = 1
+	}
+
+	entries := index.Find(query)
+
+	if *latest {
+		if len(entries) == 0 {
+			return fmt.Errorf("no saved results match the given filters")
+		}
+		saved, err := storage.ReadIndexedResult(dir, entries[0])
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(saved)
+	}
+
+	if *outputFormat == "json" {
+		if entries == nil {
+			entries = []storage.IndexEntry{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("No saved results found in %s\n", dir)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SAVED AT\tPROVIDER\tMODEL\tTYPE\tSIZE\tPATH")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.SavedAt.Local().Format("2006-01-02 15:04:05"),
+			entry.Provider,
+			entry.Model,
+			entry.Type,
+			storage.FormatSize(entry.Size),
+			entry.Path)
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d result(s) shown (%d indexed in %s)\n", len(entries), len(index.Entries), dir)
+	return nil
+}
+
+// parseResultType は--typeの値を保存形式の探索種別に変換する
+func parseResultType(value string) (string, error) {
+	switch value {
+	case "":
+		return "", nil
+	case "context", storage.ResultTypeContextWindow:
+		return storage.ResultTypeContextWindow, nil
+	case storage.ResultTypeMaxOutput:
+		return storage.ResultTypeMaxOutput, nil
+	case storage.ResultTypeCapabilities:
+		return storage.ResultTypeCapabilities, nil
+	case "multi_turn", storage.ResultTypeMultiTurn:
+		return storage.ResultTypeMultiTurn, nil
+	default:
+		return "", fmt.Errorf("invalid type: %s (valid: context, max_output, capabilities, multi_turn)", value)
+	}
+}
+
+// showResultsHelp はresultsコマンドのヘルプを表示する
+func showResultsHelp() {
+	fmt.Println(`llm-info results - Search saved probe results
+
+USAGE:
+    llm-info results [flags]
+    llm-info results import [flags] <file|dir>...
+    llm-info results sync [flags]
+    llm-info results trend --model MODEL [flags]
+
+FLAGS:
+    --provider string     Filter by provider name
+    --model string        Filter by model ID
+    --type string         Filter by probe type (context, max_output, capabilities, multi_turn)
+    --since duration      Only show results saved within this duration (e.g. 168h)
+    --limit int           Maximum number of results to show, 0 for all (default: 20)
+    --latest              Print the full content of the newest matching result
+    --rebuild-index       Rebuild the index from the files on disk
+    --format string       Output format: table, json (default: table)
+    --result-dir string   Directory where probe results are saved (default: storage.result_dir)
+    --config string       Path to config file
+    --help                Show help for results command
+
+EXAMPLES:
+    # List the most recent saved results
+    llm-info results
+
+    # Show the last week of context window results for a model
+    llm-info results --model gpt-4o --type context --since 168h
+
+    # Print the newest max output result as JSON
+    llm-info results --model gpt-4o --type max_output --latest
+
+    # Import results produced by a CI runner
+    llm-info results import ci-results.json
+
+    # Context window of a model over time, with change points highlighted
+    llm-info results trend --model gpt-4o --metric context_window
+
+DESCRIPTION:
+    Results saved with --save-result or by the daemon are stored as
+    <provider>/<model>/<YYYY-MM-DD>/<time>-<type>.json (or .json.gz when
+    storage.compress is enabled) and tracked in index.json. The index is
+    rebuilt automatically when it is missing, e.g. for directories written by
+    earlier versions.
+
+    When storage.remote is configured, results shared by the team are
+    downloaded into the local directory before searching. See
+    'llm-info results sync --help' and 'llm-info results import --help' for
+    sharing results produced on other machines, and 'llm-info results trend
+    --help' for the history of a model's measured limits.`)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/armaniacs/llm-info/internal/storage"
+)
+
+// resultsImportCommand は他のマシンやCIで保存された探索結果を検証してローカルの保存先に取り込む
+func resultsImportCommand(args []string) error {
+	importCmd := flag.NewFlagSet("results import", flag.ExitOnError)
+	dryRun := importCmd.Bool("dry-run", false, "Validate the files without importing anything")
+	outputFormat := importCmd.String("format", "table", "Output format (table, json)")
+	resultDir := importCmd.String("result-dir", "", "Directory where probe results are saved")
+	configFile := importCmd.String("config", "", "Path to config file")
+	showHelp := importCmd.Bool("help", false, "Show help for results import")
+
+	importCmd.Parse(args)
+
+	if *showHelp {
+		showResultsImportHelp()
+		return nil
+	}
+
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+	if importCmd.NArg() == 0 {
+		return fmt.Errorf("no files to import; pass one or more result files or directories")
+	}
+
+	probeConfig := loadProbeConfigManager(*configFile).GetProbeConfig()
+	dir := *resultDir
+	if dir == "" {
+		dir = probeConfig.Result.Dir
+	}
+	resultStorage, err := storage.NewResultStorageWithOptions(dir, probeConfig.Result.StorageOptions())
+	if err != nil {
+		return fmt.Errorf("failed to open result storage: %w", err)
+	}
+
+	var candidates []storage.ImportCandidate
+	for _, path := range importCmd.Args() {
+		found, err := storage.ReadImportSource(path)
+		if err != nil {
+			return err
+		}
+		candidates = append(candidates, found...)
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no result files found in %v", importCmd.Args())
+	}
+
+	outcomes, err := resultStorage.Import(candidates, *dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to import results: %w", err)
+	}
+
+	var imported, duplicates, invalid int
+	for _, outcome := range outcomes {
+		switch {
+		case outcome.Error != "":
+			invalid++
+		case outcome.Duplicate:
+			duplicates++
+		default:
+			imported++
+		}
+	}
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(outcomes); err != nil {
+			return err
+		}
+	} else {
+		printImportOutcomes(outcomes)
+		verb := "Imported"
+		if *dryRun {
+			verb = "Would import"
+		}
+		fmt.Printf("\n%s %d result(s) into %s (%d already present, %d invalid)\n",
+			verb, imported, resultStorage.BaseDir(), duplicates, invalid)
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d result(s) failed validation", invalid)
+	}
+	return nil
+}
+
+// printImportOutcomes は取り込んだ結果を1件1行で表示する
+func printImportOutcomes(outcomes []storage.ImportOutcome) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tPROVIDER\tMODEL\tTYPE\tSAVED AT\tSOURCE")
+	for _, outcome := range outcomes {
+		status := "imported"
+		switch {
+		case outcome.Error != "":
+			fmt.Fprintf(w, "invalid\t-\t-\t-\t-\t%s: %s\n", outcome.Source, outcome.Error)
+			continue
+		case outcome.Duplicate:
+			status = "duplicate"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			status, outcome.Provider, outcome.Model, outcome.Type,
+			outcome.SavedAt.Local().Format("2006-01-02 15:04:05"), outcome.Source)
+	}
+	w.Flush()
+}
+
+// showResultsImportHelp はresults importのヘルプを表示する
+func showResultsImportHelp() {
+	fmt.Println(`llm-info results import - Import probe results produced on other machines
+
+USAGE:
+    llm-info results import [flags] <file|dir>...
+
+FLAGS:
+    --dry-run             Validate the files without importing anything
+    --format string       Output format: table, json (default: table)
+    --result-dir string   Directory where probe results are saved (default: storage.result_dir)
+    --config string       Path to config file
+    --help                Show help for results import
+
+EXAMPLES:
+    # Import a result file downloaded from a CI run
+    llm-info results import ci-results.json
+
+    # Import another machine's whole result directory
+    llm-info results import ./shared/estimates
+
+    # Check files before importing them
+    llm-info results import --dry-run ci-results.json
+
+DESCRIPTION:
+    Each file holds a saved result as written by --save-result or printed by
+    'llm-info results --latest', or a JSON array of them; .json.gz files are
+    decompressed. Directories are scanned recursively and their index.json
+    is ignored, so a result directory copied from another machine can be
+    imported as a whole.
+
+    Every result is validated before it is written: provider, model and
+    estimated_at must be present, estimated_at must not lie in the future,
+    and context_window, max_output and capabilities must be objects with a
+    non-negative value. Valid results are stored in the partition of their
+    original date and added to the index together with the file they came
+    from, so 'llm-info results' and the offline catalog pick them up.
+    Results that are already indexed are reported as duplicates and skipped,
+    which makes repeated imports safe. The command exits with status 1 when
+    any result failed validation; the valid ones are still imported.`)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/armaniacs/llm-info/internal/storage"
+)
+
+// resultsSyncCommand はローカルの探索結果と共有のリモート保存先を同期する
+func resultsSyncCommand(args []string) error {
+	syncCmd := flag.NewFlagSet("results sync", flag.ExitOnError)
+	pullOnly := syncCmd.Bool("pull-only", false, "Only download results shared by others")
+	pushOnly := syncCmd.Bool("push-only", false, "Only upload local results")
+	resultDir := syncCmd.String("result-dir", "", "Directory where probe results are saved")
+	configFile := syncCmd.String("config", "", "Path to config file")
+	showHelp := syncCmd.Bool("help", false, "Show help for results sync")
+
+	syncCmd.Parse(args)
+
+	if *showHelp {
+		showResultsSyncHelp()
+		return nil
+	}
+
+	if *pullOnly && *pushOnly {
+		return fmt.Errorf("--pull-only and --push-only cannot be used together")
+	}
+
+	probeConfig := loadProbeConfigManager(*configFile).GetProbeConfig()
+	if !probeConfig.Result.Remote.Enabled() {
+		return fmt.Errorf("no remote store configured; set storage.remote.bucket in the config file")
+	}
+	dir := *resultDir
+	if dir == "" {
+		dir = probeConfig.Result.Dir
+	}
+	resultStorage, err := storage.NewResultStorageWithOptions(dir, probeConfig.Result.StorageOptions())
+	if err != nil {
+		return fmt.Errorf("failed to open result storage: %w", err)
+	}
+
+	remote := probeConfig.Result.Remote
+	fmt.Printf("Remote: s3://%s/%s\n", remote.Bucket, remote.Prefix)
+	fmt.Printf("Local:  %s\n\n", resultStorage.BaseDir())
+
+	if !*pullOnly {
+		report, err := resultStorage.Push()
+		if err != nil {
+			return fmt.Errorf("failed to upload results: %w", err)
+		}
+		fmt.Printf("Uploaded %d local result(s)\n", report.Uploaded)
+	}
+	if !*pushOnly {
+		report, err := resultStorage.Pull("")
+		if err != nil {
+			return fmt.Errorf("failed to download results: %w", err)
+		}
+		fmt.Printf("Downloaded %d shared result(s)\n", report.Downloaded)
+	}
+	return nil
+}
+
+// pullSharedResults は共有の保存先から未取得の結果をダウンロードする
+// 保存先に接続できない場合は警告を表示し、ローカルのキャッシュだけを使う
+func pullSharedResults(dir string, opts storage.Options) {
+	resultStorage, err := storage.NewResultStorageWithOptions(dir, opts)
+	if err == nil {
+		_, err = resultStorage.Pull("")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch shared results, showing the local cache: %v\n", err)
+	}
+}
+
+// showResultsSyncHelp はresults syncのヘルプを表示する
+func showResultsSyncHelp() {
+	fmt.Println(`llm-info results sync - Synchronize probe results with the shared remote store
+
+USAGE:
+    llm-info results sync [flags]
+
+FLAGS:
+    --pull-only           Only download results shared by others
+    --push-only           Only upload local results
+    --result-dir string   Directory where probe results are saved (default: storage.result_dir)
+    --config string       Path to config file
+    --help                Show help for results sync
+
+EXAMPLES:
+    # Share the local history and fetch everyone else's
+    llm-info results sync
+
+    # Fetch the team's results on a new laptop
+    llm-info results sync --pull-only
+
+DESCRIPTION:
+    Requires storage.remote in the config file. The remote store is any
+    S3-compatible bucket (AWS S3, MinIO, or GCS through its XML API with
+    HMAC keys). Results saved with --save-result, by the daemon or with
+    'results import' are uploaded automatically, and 'llm-info results'
+    downloads new results before searching, so sync is only needed for
+    history saved before the remote was configured or to fill the local
+    cache ahead of working offline.
+
+    The local result directory acts as a cache: each result file is stored
+    once under <prefix>/<provider>/<model>/<YYYY-MM-DD>/ and never
+    rewritten, and every machine keeps its own index.json.`)
+}
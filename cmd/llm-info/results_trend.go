@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/storage"
+	"github.com/armaniacs/llm-info/internal/ui"
+)
+
+// trendReport はresults trendのJSON出力
+type trendReport struct {
+	Model   string               `json:"model"`
+	Metric  string               `json:"metric"`
+	Changes int                  `json:"changes"`
+	Points  []storage.TrendPoint `json:"points"`
+}
+
+// resultsTrendCommand は保存済みの探索結果からモデルの制約値の推移を表示する
+// プロバイダーが告知なく上限を変えたことに気付けるよう、値が変わった時点を強調する
+func resultsTrendCommand(args []string) error {
+	trendCmd := flag.NewFlagSet("results trend", flag.ExitOnError)
+	modelID := trendCmd.String("model", "", "Model ID (required)")
+	metric := trendCmd.String("metric", storage.ResultTypeContextWindow, "Measured value to show (context_window, max_output)")
+	provider := trendCmd.String("provider", "", "Filter by provider name")
+	since := trendCmd.Duration("since", 0, "Only use results saved within this duration (e.g. 2160h)")
+	limit := trendCmd.Int("limit", 0, "Maximum number of most recent results to use (0 for all)")
+	outputFormat := trendCmd.String("format", "table", "Output format (table, json)")
+	resultDir := trendCmd.String("result-dir", "", "Directory where probe results are saved")
+	configFile := trendCmd.String("config", "", "Path to config file")
+	showHelp := trendCmd.Bool("help", false, "Show help for results trend")
+
+	trendCmd.Parse(args)
+
+	if *showHelp {
+		showResultsTrendHelp()
+		return nil
+	}
+
+	if *modelID == "" {
+		return fmt.Errorf("--model is required")
+	}
+	resultType, err := parseResultType(*metric)
+	if err != nil || resultType == "" || resultType == storage.ResultTypeCapabilities {
+		return fmt.Errorf("invalid metric: %s (valid: context_window, max_output)", *metric)
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	probeConfig := loadProbeConfigManager(*configFile).GetProbeConfig()
+	dir := *resultDir
+	if dir == "" {
+		dir = probeConfig.Result.Dir
+	}
+	dir, err = storage.ExpandPath(dir)
+	if err != nil {
+		return err
+	}
+
+	if probeConfig.Result.Remote.Enabled() {
+		pullSharedResults(dir, probeConfig.Result.StorageOptions())
+	}
+
+	index, err := storage.OpenIndex(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open result index: %w", err)
+	}
+
+	query := storage.IndexQuery{
+		Provider: *provider,
+		Model:    *modelID,
+		Type:     resultType,
+		Limit:    *limit,
+	}
+	if *since > 0 {
+		query.Since = time.Now().Add(-*since)
+	}
+
+	report := trendReport{
+		Model:  *modelID,
+		Metric: resultType,
+		Points: storage.LoadTrend(dir, index, query),
+	}
+	for _, point := range report.Points {
+		if point.Change {
+			report.Changes++
+		}
+	}
+
+	if *outputFormat == "json" {
+		if report.Points == nil {
+			report.Points = []storage.TrendPoint{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	if len(report.Points) == 0 {
+		fmt.Printf("No saved %s results for %s in %s\n", resultType, *modelID, dir)
+		return nil
+	}
+	printTrendReport(report)
+	return nil
+}
+
+// printTrendReport は推移をスパークラインと表で表示する
+func printTrendReport(report trendReport) {
+	values := make([]int, len(report.Points))
+	ok := make([]bool, len(report.Points))
+	for i, point := range report.Points {
+		values[i], ok[i] = point.Value, point.Success
+	}
+	first, last := report.Points[0].SavedAt, report.Points[len(report.Points)-1].SavedAt
+	fmt.Printf("%s %s  %s  (%s → %s, %d result(s))\n\n", report.Model, report.Metric,
+		ui.Sparkline(values, ok), first.Local().Format("2006-01-02"), last.Local().Format("2006-01-02"), len(report.Points))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SAVED AT\tPROVIDER\tVALUE\tCHANGE")
+	for _, point := range report.Points {
+		value, change := "failed", ""
+		if point.Success {
+			value = fmt.Sprintf("%d", point.Value)
+		}
+		if point.Change {
+			change = fmt.Sprintf("⚠️  %d → %d (%+d)", point.Previous, point.Value, point.Value-point.Previous)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			point.SavedAt.Local().Format("2006-01-02 15:04:05"), point.Provider, value, change)
+	}
+	w.Flush()
+	fmt.Println()
+
+	if report.Changes == 0 {
+		fmt.Println("✅ No change in the measured value.")
+		return
+	}
+	fmt.Printf("⚠️  The measured value changed %d time(s). Re-run verify or update the catalog if the change is unexpected.\n", report.Changes)
+}
+
+// showResultsTrendHelp はresults trendのヘルプを表示する
+func showResultsTrendHelp() {
+	fmt.Println(`llm-info results trend - Show how a model's measured limit changed over time
+
+USAGE:
+    llm-info results trend --model MODEL [flags]
+
+FLAGS:
+    --model string        Model ID (required)
+    --metric string       Measured value to show: context_window (or context),
+                          max_output (default: context_window)
+    --provider string     Filter by provider name
+    --since duration      Only use results saved within this duration (e.g. 2160h)
+    --limit int           Maximum number of most recent results to use, 0 for all
+    --format string       Output format: table, json (default: table)
+    --result-dir string   Directory where probe results are saved (default: storage.result_dir)
+    --config string       Path to config file
+    --help                Show help for results trend
+
+EXAMPLES:
+    # Context window history of gpt-4o
+    llm-info results trend --model gpt-4o --metric context_window
+
+    # Max output over the last 90 days as JSON
+    llm-info results trend --model gpt-4o --metric max_output --since 2160h --format json
+
+DESCRIPTION:
+    Reads every saved result of the model, oldest first, and prints a
+    sparkline followed by one row per result. A row is marked as a change
+    when its value differs from the previous successful result, so a limit
+    that a provider changed silently stands out. Failed runs are shown as
+    "failed", left blank in the sparkline and never counted as a change.
+    Results from all providers are combined unless --provider is given.`)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/cache"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/search"
+	"github.com/armaniacs/llm-info/internal/ui"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["search"] = searchCommand
+}
+
+// searchResultJSON はsearchコマンドのJSON出力の1件
+type searchResultJSON struct {
+	Gateway     string    `json:"gateway"`
+	URL         string    `json:"url"`
+	Model       string    `json:"model"`
+	Provider    string    `json:"provider,omitempty"`
+	Description string    `json:"description,omitempty"`
+	MaxTokens   int       `json:"max_tokens"`
+	Mode        string    `json:"mode,omitempty"`
+	Score       int       `json:"score"`
+	MatchedOn   string    `json:"matched_on"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// searchCommand はキャッシュ済みの全ゲートウェイのモデル一覧をあいまい検索する
+func searchCommand(args []string) error {
+	searchCmd := flag.NewFlagSet("search", flag.ExitOnError)
+	gateway := searchCmd.String("gateway", "", "Only search the cached catalog of this gateway")
+	limit := searchCmd.Int("limit", 20, "Maximum number of matches to show (0 for all)")
+	outputFormat := searchCmd.String("format", "table", "Output format (table, json)")
+	cacheDir := searchCmd.String("cache-dir", "", "Directory where model catalogs are cached")
+	configFile := searchCmd.String("config", "", "Path to config file")
+	showHelp := searchCmd.Bool("help", false, "Show help for search command")
+
+	searchCmd.Parse(args)
+
+	if *showHelp {
+		showSearchHelp()
+		return nil
+	}
+
+	query := strings.Join(searchCmd.Args(), " ")
+	if strings.TrimSpace(query) == "" {
+		return fmt.Errorf("search query is required (e.g. llm-info search sonnet)")
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	var catalog *cache.CatalogCache
+	if *cacheDir != "" {
+		catalog = cache.NewCatalogCache(*cacheDir)
+	} else {
+		var err error
+		catalog, err = catalogCache(loadProbeConfigManager(*configFile))
+		if err != nil {
+			return err
+		}
+	}
+
+	entries, err := catalog.LoadAll()
+	if err != nil {
+		return err
+	}
+	if *gateway != "" {
+		var selected []*cache.CatalogEntry
+		for _, entry := range entries {
+			if entry.Gateway == *gateway {
+				selected = append(selected, entry)
+			}
+		}
+		entries = selected
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no cached model catalogs found; run llm-info against a gateway first to populate the cache")
+	}
+
+	// 検索結果から取得元のキャッシュを引けるよう、ゲートウェイ名にはURLを併記する
+	var docs []search.Document
+	sources := make(map[string]*cache.CatalogEntry)
+	for _, entry := range entries {
+		name := catalogName(entry)
+		sources[name] = entry
+		for _, m := range model.FromAPIResponse(entry.Models) {
+			docs = append(docs, search.Document{Gateway: name, Model: m})
+		}
+	}
+
+	results := search.Search(query, docs)
+	total := len(results)
+	if *limit > 0 && len(results) > *limit {
+		results = results[:*limit]
+	}
+
+	if *outputFormat == "json" {
+		output := make([]searchResultJSON, 0, len(results))
+		for _, r := range results {
+			source := sources[r.Gateway]
+			output = append(output, searchResultJSON{
+				Gateway:     source.Gateway,
+				URL:         ui.MaskURL(source.URL),
+				Model:       r.Model.Name,
+				Provider:    search.Provider(r.Model),
+				Description: search.Description(r.Model),
+				MaxTokens:   r.Model.MaxTokens,
+				Mode:        r.Model.Mode,
+				Score:       r.Score,
+				MatchedOn:   r.Field,
+				FetchedAt:   source.FetchedAt,
+			})
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	}
+
+	if total == 0 {
+		fmt.Printf("No models match %q in %d cached catalog(s)\n", query, len(entries))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tGATEWAY\tPROVIDER\tMAX TOKENS\tMATCHED ON\tCACHED")
+	for _, r := range results {
+		provider := search.Provider(r.Model)
+		if provider == "" {
+			provider = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n",
+			r.Model.Name,
+			r.Gateway,
+			provider,
+			r.Model.MaxTokens,
+			r.Field,
+			cache.FormatAge(sources[r.Gateway].Age()))
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d of %d match(es) shown across %d cached catalog(s)\n", len(results), total, len(entries))
+	return nil
+}
+
+// catalogName はキャッシュの表示名を返す
+// ゲートウェイ名のないキャッシュ（--urlで取得したもの）はURLで表示する
+func catalogName(entry *cache.CatalogEntry) string {
+	if entry.Gateway != "" && entry.Gateway != "default" {
+		return entry.Gateway
+	}
+	return ui.MaskURL(entry.URL)
+}
+
+// showSearchHelp はsearchコマンドのヘルプを表示する
+func showSearchHelp() {
+	fmt.Println(`llm-info search - Fuzzy search models across cached catalogs
+
+USAGE:
+    llm-info search [flags] <query>
+
+FLAGS:
+    --gateway string      Only search the cached catalog of this gateway
+    --limit int           Maximum number of matches to show, 0 for all (default: 20)
+    --format string       Output format: table, json (default: table)
+    --cache-dir string    Directory where model catalogs are cached (default: storage.cache_dir)
+    --config string       Path to config file
+    --help                Show help for search command
+
+EXAMPLES:
+    # Find Sonnet models on every gateway you have queried
+    llm-info search sonnet
+
+    # All words must match; letters may be abbreviated
+    llm-info search claude 35 sonnet
+
+    # Search one gateway and print JSON
+    llm-info search --gateway production --format json gpt-4o
+
+DESCRIPTION:
+    Every successful model listing is cached per gateway. search ranks the
+    cached models by how well each word of the query matches the model ID,
+    provider name or description: exact, prefix, word start, substring and
+    finally fuzzy (the letters appear in order). Model ID matches rank above
+    provider and description matches. No network requests are made, so run
+    llm-info against each gateway first to refresh its catalog.`)
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/internal/update"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["self-update"] = selfUpdateCommand
+}
+
+// selfUpdateCommand はGitHubリリースから新しいバイナリを取得し、実行中のバイナリを置き換える
+func selfUpdateCommand(args []string) error {
+	updateCmd := flag.NewFlagSet("self-update", flag.ExitOnError)
+	checkOnly := updateCmd.Bool("check-only", false, "Only report whether an update is available")
+	targetVersion := updateCmd.String("version", "", "Install this release tag instead of the latest release")
+	force := updateCmd.Bool("force", false, "Install even if the release is not newer than the running version")
+	apiURL := updateCmd.String("api-url", envOrDefault("LLM_INFO_UPDATE_API_URL", update.DefaultAPIURL), "GitHub API URL (for GitHub Enterprise or a mirror)")
+	repo := updateCmd.String("repo", update.DefaultRepo, "Repository to fetch releases from")
+	timeout := updateCmd.Duration("timeout", 60*time.Second, "Timeout for each download")
+	showHelp := updateCmd.Bool("help", false, "Show help for self-update command")
+
+	updateCmd.Parse(args)
+
+	if *showHelp {
+		showSelfUpdateHelp()
+		return nil
+	}
+
+	updater := update.New(*timeout)
+	updater.APIURL = *apiURL
+	updater.Repo = *repo
+	updater.Token = os.Getenv("GITHUB_TOKEN")
+	redact.Register(updater.Token)
+
+	var release *update.Release
+	var err error
+	if *targetVersion != "" {
+		release, err = updater.Tag(*targetVersion)
+	} else {
+		release, err = updater.Latest()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check for updates: %w", err)
+	}
+
+	cmp := update.CompareVersions(release.Version(), version)
+	if *checkOnly {
+		if cmp > 0 {
+			fmt.Printf("Update available: %s -> %s\n", version, release.Version())
+			if release.HTMLURL != "" {
+				fmt.Printf("Release notes: %s\n", release.HTMLURL)
+			}
+			fmt.Println("Run `llm-info self-update` to install it.")
+		} else {
+			fmt.Printf("llm-info %s is up to date (latest release: %s)\n", version, release.Version())
+		}
+		return nil
+	}
+
+	if cmp <= 0 && !*force {
+		if *targetVersion == "" {
+			fmt.Printf("llm-info %s is up to date (latest release: %s)\n", version, release.Version())
+			return nil
+		}
+		return fmt.Errorf("release %s is not newer than the running version %s; use --force to install it anyway", release.Version(), version)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	fmt.Printf("Downloading llm-info %s for %s/%s ...\n", release.Version(), runtime.GOOS, runtime.GOARCH)
+	data, err := updater.Download(release, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+	if updater.PublicKey != "" {
+		fmt.Println("✅ Checksum and signature verified")
+	} else {
+		fmt.Println("✅ Checksum verified")
+	}
+
+	if err := update.Replace(executable, data); err != nil {
+		return fmt.Errorf("%w (try running with permission to write %s)", err, filepath.Dir(executable))
+	}
+
+	fmt.Printf("✅ Updated %s: %s -> %s\n", executable, version, release.Version())
+	return nil
+}
+
+// envOrDefault は環境変数が設定されていればその値、なければデフォルト値を返す
+func envOrDefault(name, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// showSelfUpdateHelp はself-updateコマンドのヘルプを表示する
+func showSelfUpdateHelp() {
+	fmt.Println(`llm-info self-update - Replace this binary with the latest GitHub release
+
+USAGE:
+    llm-info self-update [flags]
+
+FLAGS:
+    --check-only        Only report whether an update is available
+    --version string    Install this release tag instead of the latest release
+    --force             Install even if the release is not newer than the running version
+    --api-url string    GitHub API URL (default: LLM_INFO_UPDATE_API_URL or https://api.github.com)
+    --repo string       Repository to fetch releases from (default: armaniacs/llm-info)
+    --timeout duration  Timeout for each download (default: 60s)
+    --help              Show help for self-update command
+
+EXAMPLES:
+    # See whether a newer release exists
+    llm-info self-update --check-only
+
+    # Install the latest release
+    llm-info self-update
+
+    # Pin a specific release (also used to downgrade with --force)
+    llm-info self-update --version v1.2.0 --force
+
+DESCRIPTION:
+    The binary for the current platform (llm-info_<os>_<arch>) is downloaded
+    from the release and checked against the SHA-256 in checksums.txt before
+    anything is written. Release builds with an embedded public key also verify
+    the Ed25519 signature in checksums.txt.sig. The new binary is written next
+    to the current one and renamed over it, so a failed update leaves the old
+    binary in place.
+
+    Set GITHUB_TOKEN to avoid GitHub API rate limits when many hosts update at
+    the same time.`)
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/cost"
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["spend"] = spendCommand
+}
+
+// spendReport はspendコマンドのJSON出力
+type spendReport struct {
+	Gateway   string            `json:"gateway"`
+	Key       string            `json:"key"` // マスク済み
+	KeyInfo   *api.KeyInfo      `json:"key_info,omitempty"`
+	Remaining *float64          `json:"remaining,omitempty"`
+	Days      int               `json:"days,omitempty"`
+	Spend     []cost.DailySpend `json:"recent_spend,omitempty"`
+	Total     float64           `json:"recent_total,omitempty"`
+	Errors    map[string]string `json:"errors,omitempty"`
+}
+
+// spendCommand はLiteLLMの仮想キーの予算と最近の利用額を表示する
+func spendCommand(args []string) error {
+	spendCmd := flag.NewFlagSet("spend", flag.ExitOnError)
+	baseURL := spendCmd.String("url", "", "Base URL of the LiteLLM gateway")
+	apiKey := spendCmd.String("api-key", "", "Virtual key to inspect")
+	gateway := spendCmd.String("gateway", "", "Gateway name to use from config")
+	adminKey := spendCmd.String("admin-key", "", "LiteLLM admin (master) key for /spend/logs (default: LLM_INFO_ADMIN_KEY)")
+	days := spendCmd.Int("days", 7, "Number of days of spend logs to show")
+	timeout := spendCmd.Duration("timeout", 30*time.Second, "Request timeout")
+	outputFormat := spendCmd.String("format", "table", "Output format (table, json)")
+	configFile := spendCmd.String("config", "", "Path to config file")
+	showHelp := spendCmd.Bool("help", false, "Show help for spend command")
+
+	spendCmd.Parse(args)
+
+	if *showHelp {
+		showSpendHelp()
+		return nil
+	}
+
+	if *days < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+		URL:          *baseURL,
+		APIKey:       *apiKey,
+		Timeout:      *timeout,
+		Gateway:      *gateway,
+		OutputFormat: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	key := resolved.Gateway.APIKey
+	if key == "" {
+		return fmt.Errorf("no API key for gateway %s; pass --api-key or set it in the config file", resolved.Gateway.URL)
+	}
+	if *adminKey == "" {
+		*adminKey = os.Getenv("LLM_INFO_ADMIN_KEY")
+	}
+	redact.Register(*adminKey)
+
+	report := &spendReport{Gateway: resolved.Gateway.Name, Key: maskAPIKey(key), Errors: map[string]string{}}
+	if report.Gateway == "" {
+		report.Gateway = resolved.Gateway.URL
+	}
+
+	// 管理者キーがあればそれで照会し、なければキー自身で自分の情報を照会する
+	var keyInfo *api.KeyInfoResponse
+	if *adminKey != "" {
+		adminCfg := internalConfig.New(resolved.Gateway.URL, *adminKey, resolved.Gateway.Timeout)
+		adminCfg.Timeouts = resolved.Gateway.Timeouts
+		adminCfg.Proxy = resolved.Gateway.Proxy
+		adminClient := api.NewClient(adminCfg)
+
+		keyInfo, err = adminClient.GetKeyInfo(key)
+		if err != nil {
+			report.Errors["key_info"] = err.Error()
+		}
+
+		end := time.Now()
+		start := end.AddDate(0, 0, -(*days - 1))
+		logs, err := adminClient.GetSpendLogs(key, start, end.AddDate(0, 0, 1))
+		if err != nil {
+			report.Errors["spend_logs"] = err.Error()
+		} else {
+			report.Days = *days
+			report.Spend = cost.SummarizeSpendLogs(logs)
+			report.Total = cost.TotalSpend(report.Spend)
+		}
+	} else {
+		cfg := internalConfig.New(resolved.Gateway.URL, key, resolved.Gateway.Timeout)
+		cfg.Timeouts = resolved.Gateway.Timeouts
+		cfg.Proxy = resolved.Gateway.Proxy
+		keyInfo, err = api.NewClient(cfg).GetKeyInfo("")
+		if err != nil {
+			report.Errors["key_info"] = err.Error()
+		}
+	}
+
+	if keyInfo != nil {
+		report.KeyInfo = &keyInfo.Info
+		if remaining, ok := keyInfo.Info.Remaining(); ok {
+			report.Remaining = &remaining
+		}
+	}
+	if len(report.Errors) == 0 {
+		report.Errors = nil
+	}
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printSpendReport(report, *adminKey != "")
+	}
+
+	if report.KeyInfo == nil && report.Spend == nil {
+		return fmt.Errorf("could not read key info or spend logs from %s", resolved.Gateway.URL)
+	}
+	return nil
+}
+
+// printSpendReport はキーの予算と日ごとの利用額を表示する
+func printSpendReport(report *spendReport, hasAdminKey bool) {
+	fmt.Printf("Gateway: %s\n", report.Gateway)
+	fmt.Printf("Key:     %s\n\n", report.Key)
+
+	if info := report.KeyInfo; info != nil {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		if info.KeyAlias != "" {
+			fmt.Fprintf(w, "Alias:\t%s\n", info.KeyAlias)
+		}
+		fmt.Fprintf(w, "Spend:\t$%.4f\n", info.Spend)
+		if info.MaxBudget != nil {
+			fmt.Fprintf(w, "Max Budget:\t$%.4f\n", *info.MaxBudget)
+			fmt.Fprintf(w, "Remaining:\t$%.4f (%.1f%% used)\n", *report.Remaining, budgetUsed(info))
+		} else {
+			fmt.Fprintf(w, "Max Budget:\tunlimited\n")
+		}
+		if info.BudgetDuration != "" {
+			fmt.Fprintf(w, "Budget Period:\t%s\n", info.BudgetDuration)
+		}
+		if info.BudgetResetAt != "" {
+			fmt.Fprintf(w, "Budget Resets:\t%s\n", info.BudgetResetAt)
+		}
+		if info.Expires != "" {
+			fmt.Fprintf(w, "Expires:\t%s\n", info.Expires)
+		}
+		w.Flush()
+	} else if msg := report.Errors["key_info"]; msg != "" {
+		fmt.Printf("⚠️  Failed to read key info: %s\n", msg)
+	}
+
+	if !hasAdminKey {
+		fmt.Println("\nRecent spend needs an admin key; pass --admin-key or set LLM_INFO_ADMIN_KEY")
+		return
+	}
+	if msg := report.Errors["spend_logs"]; msg != "" {
+		fmt.Printf("\n⚠️  Failed to read spend logs: %s\n", msg)
+		return
+	}
+
+	fmt.Printf("\nRecent spend (last %d days)\n", report.Days)
+	if len(report.Spend) == 0 {
+		fmt.Println("No spend recorded")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tMODEL\tREQUESTS\tTOKENS\tSPEND")
+	for _, entry := range report.Spend {
+		requests, tokens := "-", "-"
+		if entry.Requests > 0 {
+			requests = fmt.Sprintf("%d", entry.Requests)
+			tokens = fmt.Sprintf("%d", entry.TotalTokens)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t$%.4f\n", entry.Date, entry.Model, requests, tokens, entry.Spend)
+	}
+	fmt.Fprintf(w, "TOTAL\t\t\t\t$%.4f\n", report.Total)
+	w.Flush()
+}
+
+// budgetUsed は予算の消化率（%）を返す
+func budgetUsed(info *api.KeyInfo) float64 {
+	if info.MaxBudget == nil || *info.MaxBudget <= 0 {
+		return 0
+	}
+	return info.Spend / *info.MaxBudget * 100
+}
+
+// showSpendHelp はspendコマンドのヘルプを表示する
+func showSpendHelp() {
+	fmt.Println(`llm-info spend - Show the remaining budget and recent spend of a LiteLLM key
+
+USAGE:
+    llm-info spend [flags]
+
+FLAGS:
+    --gateway string     Gateway name to use from config
+    --url string         Base URL of the LiteLLM gateway
+    --api-key string     Virtual key to inspect (default: the gateway's key)
+    --admin-key string   LiteLLM admin (master) key for /spend/logs (default: LLM_INFO_ADMIN_KEY)
+    --days int           Number of days of spend logs to show (default: 7)
+    --timeout duration   Request timeout (default: 30s)
+    --format string      Output format (table, json) (default: table)
+    --config string      Path to config file
+    --help               Show help for spend command
+
+EXAMPLES:
+    # Remaining budget of the production key
+    llm-info spend --gateway production
+
+    # Include the last 30 days of spend per model
+    LLM_INFO_ADMIN_KEY=sk-master... llm-info spend --gateway production --days 30
+
+DESCRIPTION:
+    Reads the key's spend, max budget and reset date from LiteLLM's /key/info.
+    Without an admin key the key looks itself up, which LiteLLM allows for
+    any virtual key. With an admin key the command also reads /spend/logs
+    and sums the key's spend per day and model, so the budget consumed by
+    probe runs is visible. Both keys are masked in the output. The command
+    exits with status 1 when neither endpoint could be read.`)
+}
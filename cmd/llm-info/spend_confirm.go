@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/probe"
+)
+
+// callEstimator は想定する上限から探索の呼び出しを見積もる（--dry-runと実行前の確認で共有する）
+type callEstimator func(assumptions dryRunAssumptions) ([]*probe.CallEstimate, error)
+
+// addYesFlag は見積もりコストの確認を省略する--yesを定義する
+func addYesFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("yes", false, "Run without asking for confirmation when the estimated cost exceeds spend_confirm_threshold")
+}
+
+// confirmSpend は探索の見積もりコストがglobal.spend_confirm_thresholdを超える場合に、実行してよいかを確認する
+// 見積もりは--dry-runと同じ方法でAPIを呼ばずに計算する。--yesが指定された場合は確認しない
+// 標準入力が端末でない場合（CIなど）は確認できないため、--yesを求めるエラーにする
+func confirmSpend(configManager *internalConfig.Manager, resolved *internalConfig.ResolvedConfig, command, modelID string, claimedLimit int, yes bool, estimateCalls callEstimator) error {
+	threshold := resolved.SpendConfirmThreshold
+	if threshold <= 0 || yes {
+		return nil
+	}
+
+	totalCost, calls, err := estimateSpend(configManager, resolved, modelID, claimedLimit, estimateCalls)
+	if err != nil {
+		return err
+	}
+	return askSpend(command, modelID, totalCost, calls, threshold)
+}
+
+// confirmCompareSpend はprobe-compareの見積もりコスト（ゲートウェイごとの見積もりの合計）をconfirmSpendと同じく確認する
+// しきい値はglobalの設定のため、最初のゲートウェイの解決結果を使う
+func confirmCompareSpend(configManager *internalConfig.Manager, resolvedConfigs []*internalConfig.ResolvedConfig, modelID string, yes bool, estimateCalls callEstimator) error {
+	threshold := resolvedConfigs[0].SpendConfirmThreshold
+	if threshold <= 0 || yes {
+		return nil
+	}
+
+	totalCost, calls := 0.0, 0
+	for _, resolved := range resolvedConfigs {
+		gatewayCost, gatewayCalls, err := estimateSpend(configManager, resolved, modelID, 0, estimateCalls)
+		if err != nil {
+			return err
+		}
+		totalCost += gatewayCost
+		calls += gatewayCalls
+	}
+	target := fmt.Sprintf("%s on %d gateways", modelID, len(resolvedConfigs))
+	return askSpend("probe-compare", target, totalCost, calls, threshold)
+}
+
+// estimateSpend は1つのゲートウェイでの探索の見積もりコストとAPI呼び出し回数を返す
+func estimateSpend(configManager *internalConfig.Manager, resolved *internalConfig.ResolvedConfig, modelID string, claimedLimit int, estimateCalls callEstimator) (float64, int, error) {
+	assumptions := resolveDryRunAssumptions(configManager, resolved, modelID, claimedLimit)
+	estimates, err := estimateCalls(assumptions)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to estimate probe calls: %w", err)
+	}
+	summary, _, _ := estimateDryRunCost(resolved, modelID, assumptions, estimates)
+
+	calls := 0
+	for _, estimate := range estimates {
+		calls += len(estimate.Calls)
+	}
+	return summary.TotalCost, calls, nil
+}
+
+// askSpend は見積もりコストがしきい値を超える場合に、標準エラー出力で実行してよいかを確認する
+func askSpend(command, target string, totalCost float64, calls int, threshold float64) error {
+	if totalCost <= threshold {
+		return nil
+	}
+	if !isTerminal(os.Stdin) {
+		return fmt.Errorf("estimated cost of %s for %s ($%.4f, %d API calls) exceeds spend_confirm_threshold ($%g); pass --yes to run it, or --dry-run to see the estimate", command, target, totalCost, calls, threshold)
+	}
+
+	// 標準出力は結果の出力に使うため、確認は標準エラー出力に表示する
+	fmt.Fprintf(os.Stderr, "⚠️  Estimated cost of %s for %s: $%.4f (%d API calls), above spend_confirm_threshold ($%g)\n", command, target, totalCost, calls, threshold)
+	fmt.Fprintf(os.Stderr, "   Run with --dry-run to see the breakdown, or --yes to skip this prompt.\n")
+	fmt.Fprintf(os.Stderr, "Continue? [y/N]: ")
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return nil
+	}
+	return fmt.Errorf("%s canceled: estimated cost exceeds spend_confirm_threshold", command)
+}
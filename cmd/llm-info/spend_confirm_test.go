@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestConfirmCompareSpend(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager := internalConfig.NewManager("")
+
+	// CIと同じく標準入力が端末でない場合は、確認せずに--yesを求めるエラーになる
+	stdin, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	original := os.Stdin
+	os.Stdin = stdin
+	defer func() { os.Stdin = original }()
+
+	// 1ゲートウェイあたり10万トークンの入力を1回受け付ける探索とする
+	estimateCalls := func(assumptions dryRunAssumptions) ([]*probe.CallEstimate, error) {
+		return []*probe.CallEstimate{{
+			Probe: probe.ProbeTypeContextWindow,
+			Calls: []probe.PlannedCall{{InputTokens: 100000, MaxTokens: 1, Accepted: true}},
+		}}, nil
+	}
+	gateways := func(threshold float64, names ...string) []*internalConfig.ResolvedConfig {
+		var resolved []*internalConfig.ResolvedConfig
+		for _, name := range names {
+			resolved = append(resolved, &internalConfig.ResolvedConfig{
+				Gateway:               &config.GatewayConfig{Name: name},
+				SpendConfirmThreshold: threshold,
+			})
+		}
+		return resolved
+	}
+
+	single, calls, err := estimateSpend(configManager, gateways(0, "a")[0], "gpt-4o", 0, estimateCalls)
+	if err != nil || single <= 0 || calls != 1 {
+		t.Fatalf("estimateSpend() = %v, %d, %v", single, calls, err)
+	}
+
+	// 1ゲートウェイ分の見積もりはしきい値以下でも、3ゲートウェイの合計が超える場合は確認する
+	err = confirmCompareSpend(configManager, gateways(single*2, "a", "b", "c"), "gpt-4o", false, estimateCalls)
+	if err == nil || !strings.Contains(err.Error(), "gpt-4o on 3 gateways") || !strings.Contains(err.Error(), "3 API calls") {
+		t.Errorf("confirmCompareSpend() error = %v, want the 3-gateway total to exceed the threshold", err)
+	}
+
+	if err := confirmCompareSpend(configManager, gateways(single*2, "a", "b", "c"), "gpt-4o", true, estimateCalls); err != nil {
+		t.Errorf("confirmCompareSpend() with --yes error = %v, want nil", err)
+	}
+	if err := confirmCompareSpend(configManager, gateways(single*2, "a"), "gpt-4o", false, estimateCalls); err != nil {
+		t.Errorf("confirmCompareSpend() below the threshold error = %v, want nil", err)
+	}
+	if err := confirmCompareSpend(configManager, gateways(0, "a", "b", "c"), "gpt-4o", false, estimateCalls); err != nil {
+		t.Errorf("confirmCompareSpend() without a threshold error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/cost"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/stats"
+	"github.com/armaniacs/llm-info/internal/storage"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["stats"] = statsCommand
+}
+
+// newStatsRecorder は設定に従って利用統計のRecorderを作成する
+// stats.enabledよりLLM_INFO_STATS（true/false）を優先する
+func newStatsRecorder(configManager *internalConfig.Manager) *stats.Recorder {
+	statsConfig := configManager.GetStatsConfig()
+
+	enabled := statsConfig.Enabled
+	if value := os.Getenv("LLM_INFO_STATS"); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			enabled = parsed
+		}
+	}
+
+	path := statsConfig.File
+	if path != "" {
+		if expanded, err := storage.ExpandPath(path); err == nil {
+			path = expanded
+		}
+	}
+	return stats.NewRecorder(path, enabled)
+}
+
+// recordSubcommand はサブコマンドの実行回数を記録する
+// サブコマンドのフラグは各コマンドで解析するため、--configだけをここで読み取る
+func recordSubcommand(name string, args []string) {
+	if name == "stats" {
+		return
+	}
+	configPath := configFlagValue(args)
+	if configPath == "" {
+		configPath = internalConfig.GetDefaultConfigPath()
+	}
+	configManager := internalConfig.NewManager(configPath)
+	// 読み込みエラーは各サブコマンドで報告する
+	configManager.Load()
+	newStatsRecorder(configManager).RecordCommand(name)
+}
+
+// configFlagValue は引数から--configの値を取り出す
+func configFlagValue(args []string) string {
+	for i, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "config" {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// statsGatewayName は統計に記録するゲートウェイ名を返す
+// 名前のないゲートウェイ（--urlや環境変数で指定したもの）はURLを保存しないようホスト名で集計する
+func statsGatewayName(resolved *internalConfig.ResolvedConfig) string {
+	if resolved.Gateway.Name != "" && resolved.Gateway.Name != "default" {
+		return resolved.Gateway.Name
+	}
+	if parsed, err := url.Parse(resolved.Gateway.URL); err == nil && parsed.Hostname() != "" {
+		return parsed.Hostname()
+	}
+	return "unknown"
+}
+
+// recordProbeSpend はprobeで消費したトークンとコストを記録する
+// コストはcost.enabledで料金が設定されている場合のみ計算する
+func recordProbeSpend(configManager *internalConfig.Manager, resolved *internalConfig.ResolvedConfig, model string, contextResult *probe.ContextWindowResult, outputResult *probe.MaxOutputResult) {
+	contextTrials, outputTrials := probeTrialUsage(contextResult, outputResult)
+	recordTrialSpend(configManager, resolved, model, contextTrials, outputTrials)
+}
+
+// recordTrialSpend は試行ごとの使用量からprobeの消費量を記録する
+func recordTrialSpend(configManager *internalConfig.Manager, resolved *internalConfig.ResolvedConfig, model string, contextTrials, outputTrials []cost.TrialUsage) {
+	recorder := newStatsRecorder(configManager)
+	if !recorder.Enabled() {
+		return
+	}
+
+	var inputTokens, outputTokens int
+	for _, trial := range append(contextTrials, outputTrials...) {
+		inputTokens += trial.PromptTokens
+		outputTokens += trial.CompletionTokens
+	}
+
+	var spend float64
+	if resolved.Cost != nil && resolved.Cost.Enabled {
+		calculator := cost.NewCalculator(resolved.Cost, model)
+		spend = cost.AggregateFromTrials(calculator, model, contextTrials, outputTrials).TotalCost
+	}
+	recorder.RecordProbe(model, inputTokens, outputTokens, spend)
+}
+
+// statsCommand はローカルに集計した利用統計を表示する
+func statsCommand(args []string) error {
+	statsCmd := flag.NewFlagSet("stats", flag.ExitOnError)
+	outputFormat := statsCmd.String("format", "table", "Output format (table, json)")
+	reset := statsCmd.Bool("reset", false, "Delete the collected stats")
+	configFile := statsCmd.String("config", "", "Path to config file")
+	showHelp := statsCmd.Bool("help", false, "Show help for stats command")
+
+	statsCmd.Parse(args)
+
+	if *showHelp {
+		showStatsHelp()
+		return nil
+	}
+
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	recorder := newStatsRecorder(loadProbeConfigManager(*configFile))
+
+	if *reset {
+		if err := stats.Reset(recorder.Path()); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s\n", recorder.Path())
+		return nil
+	}
+
+	collected, err := stats.Load(recorder.Path())
+	if err != nil {
+		return err
+	}
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(collected)
+	}
+
+	if !recorder.Enabled() {
+		fmt.Println("Usage stats are disabled. Nothing is collected unless you opt in with")
+		fmt.Println("  stats:")
+		fmt.Println("    enabled: true")
+		fmt.Println("in the config file, or LLM_INFO_STATS=true.")
+		if len(collected.Commands) == 0 {
+			return nil
+		}
+		fmt.Println()
+	}
+
+	printStats(collected, recorder.Path())
+	return nil
+}
+
+// printStats は利用統計を表形式で表示する
+func printStats(collected *stats.Stats, path string) {
+	fmt.Printf("Usage stats in %s\n", path)
+	if len(collected.Commands) == 0 {
+		fmt.Println("\nNo usage recorded yet.")
+		return
+	}
+	fmt.Printf("Collected since %s\n", collected.Since.Local().Format("2006-01-02 15:04"))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "\nCOMMAND\tRUNS")
+	commands := make([]string, 0, len(collected.Commands))
+	for name := range collected.Commands {
+		commands = append(commands, name)
+	}
+	// 実行回数の多い順
+	sort.Slice(commands, func(i, j int) bool {
+		if collected.Commands[commands[i]] != collected.Commands[commands[j]] {
+			return collected.Commands[commands[i]] > collected.Commands[commands[j]]
+		}
+		return commands[i] < commands[j]
+	})
+	for _, name := range commands {
+		fmt.Fprintf(w, "%s\t%d\n", name, collected.Commands[name])
+	}
+
+	if len(collected.Gateways) > 0 {
+		fmt.Fprintln(w, "\nGATEWAY\tFETCHES\tFAILURES\tAVG LATENCY")
+		gateways := make([]string, 0, len(collected.Gateways))
+		for name := range collected.Gateways {
+			gateways = append(gateways, name)
+		}
+		sort.Strings(gateways)
+		for _, name := range gateways {
+			g := collected.Gateways[name]
+			latency := "-"
+			if g.Fetches > g.Failures {
+				latency = g.AverageLatency().Round(time.Millisecond).String()
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", name, g.Fetches, g.Failures, latency)
+		}
+	}
+
+	if len(collected.Probes) > 0 {
+		fmt.Fprintln(w, "\nPROBED MODEL\tRUNS\tINPUT TOKENS\tOUTPUT TOKENS\tCOST")
+		models := make([]string, 0, len(collected.Probes))
+		for name := range collected.Probes {
+			models = append(models, name)
+		}
+		sort.Strings(models)
+		for _, name := range models {
+			p := collected.Probes[name]
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t$%.4f\n", name, p.Runs, p.InputTokens, p.OutputTokens, p.Cost)
+		}
+		total := collected.TotalProbeSpend()
+		fmt.Fprintf(w, "TOTAL\t%d\t%d\t%d\t$%.4f\n", total.Runs, total.InputTokens, total.OutputTokens, total.Cost)
+	}
+
+	w.Flush()
+}
+
+// showStatsHelp はstatsコマンドのヘルプを表示する
+func showStatsHelp() {
+	fmt.Println(`llm-info stats - Show locally collected usage stats
+
+USAGE:
+    llm-info stats [flags]
+
+FLAGS:
+    --format string   Output format: table, json (default: table)
+    --reset           Delete the collected stats
+    --config string   Path to config file
+    --help            Show help for stats command
+
+ENABLING:
+    Stats are off by default. Opt in with the config file:
+
+        stats:
+          enabled: true
+          file: ~/.local/state/llm-info/stats.json   # optional
+
+    or set LLM_INFO_STATS=true (LLM_INFO_STATS=false turns it off again).
+
+DESCRIPTION:
+    When enabled, each run adds to a local JSON file: how often each command
+    was run, the number of model list fetches per gateway with their average
+    latency and failures, and the tokens and cost spent by probes per model.
+    Cost is only counted when cost.enabled is set with pricing.
+
+    Nothing is ever sent over the network. Gateway URLs, API keys and prompts
+    are not recorded; unnamed gateways are counted by host name.`)
+}
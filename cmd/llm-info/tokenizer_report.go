@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/readonly"
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["tokenizer-report"] = tokenizerReportCommand
+}
+
+// tokenizerReportCommand は英語、日本語、中国語、コードの固定のテキストを送り、モデルごとに文字あたりのトークン数を報告する
+func tokenizerReportCommand(args []string) error {
+	probeCmd := flag.NewFlagSet("tokenizer-report", flag.ExitOnError)
+	models := probeCmd.String("model", "", "Target model ID, or a comma-separated list of model IDs (required)")
+	baseURL := probeCmd.String("url", "", "Base URL of the LLM gateway")
+	apiKey := probeCmd.String("api-key", "", "API key for authentication")
+	gateway := probeCmd.String("gateway", "", "Gateway name to use from config")
+	timeout := probeCmd.Duration("timeout", 30*time.Second, "Request timeout, overrides timeouts.probe (default: 30s)")
+	configFile := probeCmd.String("config", "", "Path to config file")
+	outputFormat := probeCmd.String("format", "table", "Output format (table, json)")
+	continueOnError := probeCmd.Bool("continue-on-error", false, "Record a model that fails to probe in the report and continue with the next model")
+	lockOpts := addLockFlags(probeCmd)
+	progressOpts := addProgressFlags(probeCmd)
+	showHelp := probeCmd.Bool("help", false, "Show help for tokenizer-report command")
+
+	probeCmd.Parse(args)
+
+	if *showHelp {
+		showTokenizerReportHelp()
+		return nil
+	}
+
+	var modelIDs []string
+	for _, id := range strings.Split(*models, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			modelIDs = append(modelIDs, id)
+		}
+	}
+	if len(modelIDs) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: --model is required\n\n")
+		showTokenizerReportHelp()
+		os.Exit(1)
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	if err := readonly.Check("tokenizer-report"); err != nil {
+		return err
+	}
+	resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+		URL:          *baseURL,
+		APIKey:       *apiKey,
+		Timeout:      *timeout,
+		Gateway:      *gateway,
+		OutputFormat: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	// 同じゲートウェイへの同時探索を防ぐ
+	gatewayLock, err := acquireGatewayLock(configManager, resolved, "tokenizer-report", lockOpts)
+	if err != nil {
+		return err
+	}
+	defer gatewayLock.Release()
+
+	client := api.NewProbeClient(newProbeClientConfig(resolved, probeCmd))
+	prober := probe.NewTokenizerProbe(client)
+
+	progress := progressOpts.newProgress()
+	defer progress.Finish()
+
+	var results []*probe.TokenizerReport
+	var failed []string
+	for i, model := range modelIDs {
+		progress.StartItem(model, i+1, len(modelIDs))
+		result, err := prober.Probe(model, resolved.Gateway.Name)
+		if err != nil {
+			if !*continueOnError {
+				return fmt.Errorf("failed to measure tokenizer efficiency for %s: %w", model, err)
+			}
+			// 失敗を記録して次のモデルに進む
+			progress.FinishItem("failed")
+			failed = append(failed, model)
+			results = append(results, &probe.TokenizerReport{
+				Model:     model,
+				Gateway:   resolved.Gateway.Name,
+				Languages: []probe.TokenizerLanguageResult{},
+				ProbedAt:  time.Now(),
+				Error:     redact.String(err.Error()),
+			})
+			continue
+		}
+		results = append(results, result)
+		progress.FinishItem("ok")
+	}
+	progress.Finish()
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printTokenizerReport(results)
+	}
+
+	if len(failed) > 0 {
+		return batchFailureError(failed, len(modelIDs))
+	}
+	return nil
+}
+
+// printTokenizerReport はモデルと言語ごとのテキストのトークン数と文字あたりのトークン数を表示する
+func printTokenizerReport(results []*probe.TokenizerReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tLANGUAGE\tCHARS\tTOKENS\tTOKENS/CHAR\tCHARS/TOKEN\tVS EN\t")
+	var failures []string
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Fprintf(w, "%s\tFAILED\t-\t-\t-\t-\t-\t\n", result.Model)
+			failures = append(failures, fmt.Sprintf("  %s: %s", result.Model, result.Error))
+			continue
+		}
+		for _, language := range result.Languages {
+			charsPerToken, relative := "-", "-"
+			if language.Tokens > 0 {
+				charsPerToken = fmt.Sprintf("%.2f", float64(language.Characters)/float64(language.Tokens))
+			}
+			if language.RelativeToEN > 0 {
+				relative = fmt.Sprintf("x%.2f", language.RelativeToEN)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.3f\t%s\t%s\t\n", result.Model, language.Language,
+				language.Characters, language.Tokens, language.TokensPerChar, charsPerToken, relative)
+		}
+	}
+	w.Flush()
+
+	if len(failures) > 0 {
+		fmt.Println("\nFailed:")
+		fmt.Println(strings.Join(failures, "\n"))
+	}
+}
+
+// showTokenizerReportHelp はtokenizer-reportコマンドのヘルプを表示する
+func showTokenizerReportHelp() {
+	fmt.Println(`llm-info tokenizer-report - Compare tokens per character across languages
+
+USAGE:
+    llm-info tokenizer-report --model <MODEL_ID>[,<MODEL_ID>...] [flags]
+
+FLAGS:
+    --model string      Target model ID, or a comma-separated list (required)
+    --url string        Base URL of the LLM gateway
+    --api-key string    API key for authentication
+    --gateway string    Gateway name to use from config
+    --timeout duration  Request timeout (default: timeouts.probe, then 30s)
+    --format string     Output format (table, json) (default: table)
+    --continue-on-error
+                        Record a model that fails to probe and continue with the next one
+    --wait duration     Wait for another probe of the same gateway to finish (default: fail immediately)
+    --force             Take over the gateway lock held by another probe
+    --quiet             Do not show progress
+    --plain             Show progress as log lines instead of a progress bar
+    --config string     Path to config file
+    --help              Show help for tokenizer-report command
+
+EXAMPLES:
+    # How many tokens does Japanese text cost compared with English?
+    llm-info tokenizer-report --model gpt-4o-mini
+
+    # Compare the tokenizers of several models
+    llm-info tokenizer-report --model gpt-4o-mini,claude-3-haiku,qwen-max --format json
+
+DESCRIPTION:
+    Sends fixed sample texts of a few hundred characters in English (en),
+    Japanese (ja), Chinese (zh) and Go code (code), each as a single user
+    message with max_tokens=16, and reads usage.prompt_tokens. A one-token
+    prompt is sent first to measure the tokens that do not depend on the
+    text (chat template and any prompt the gateway injects), and they are
+    subtracted from each sample.
+
+      CHARS        Unicode characters in the sample text
+      TOKENS       tokens of the sample text
+      TOKENS/CHAR  TOKENS / CHARS; multiply by the length of your text to
+                   estimate its tokens
+      CHARS/TOKEN  CHARS / TOKENS
+      VS EN        TOKENS/CHAR relative to the English sample
+
+    The samples are the same for every model, so the ratios can be compared
+    between models. The probe sends five small requests per model.`)
+}
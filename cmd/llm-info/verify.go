@@ -0,0 +1,296 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	errhandler "github.com/armaniacs/llm-info/internal/error"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/readonly"
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+func init() {
+	// サブコマンド登録
+	subcommands["verify"] = verifyCommand
+}
+
+// verifyCommand はカタログが公表している制約値を公称値ちょうどと公称値+1の試行で検証する
+func verifyCommand(args []string) error {
+	verifyCmd := flag.NewFlagSet("verify", flag.ExitOnError)
+	models := verifyCmd.String("model", "", "Target model ID, or a comma-separated list of model IDs (required)")
+	baseURL := verifyCmd.String("url", "", "Base URL of the LLM gateway")
+	apiKey := verifyCmd.String("api-key", "", "API key for authentication")
+	gateway := verifyCmd.String("gateway", "", "Gateway name to use from config")
+	timeout := verifyCmd.Duration("timeout", 30*time.Second, "Request timeout, overrides timeouts.probe (default: 30s)")
+	configFile := verifyCmd.String("config", "", "Path to config file")
+	claimedContext := verifyCmd.Int("claimed-context", 0, "Context window to verify instead of the catalog's max_tokens")
+	claimedOutput := verifyCmd.Int("claimed-output", 0, "Max output tokens to verify instead of the catalog's max_output_tokens")
+	failOnMismatch := verifyCmd.Bool("fail-on-mismatch", false, "Exit with an error if any limit is OVERSTATED or UNDERSTATED")
+	outputFormat := verifyCmd.String("format", "table", "Output format (table, json)")
+	continueOnError := verifyCmd.Bool("continue-on-error", false, "Record a model that fails to verify in the report and continue with the next model")
+	lockOpts := addLockFlags(verifyCmd)
+	progressOpts := addProgressFlags(verifyCmd)
+	showHelp := verifyCmd.Bool("help", false, "Show help for verify command")
+
+	verifyCmd.Parse(args)
+
+	if *showHelp {
+		showVerifyHelp()
+		return nil
+	}
+
+	var modelIDs []string
+	for _, id := range strings.Split(*models, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			modelIDs = append(modelIDs, id)
+		}
+	}
+	if len(modelIDs) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: --model is required\n\n")
+		showVerifyHelp()
+		os.Exit(1)
+	}
+	if *outputFormat != "table" && *outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", *outputFormat)
+	}
+
+	configManager := loadProbeConfigManager(*configFile)
+	if err := readonly.Check("verify"); err != nil {
+		return err
+	}
+	resolved, err := configManager.ResolveConfig(&internalConfig.CLIArgs{
+		URL:          *baseURL,
+		APIKey:       *apiKey,
+		Timeout:      *timeout,
+		Gateway:      *gateway,
+		OutputFormat: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	// 同じゲートウェイへの同時探索を防ぐ
+	gatewayLock, err := acquireGatewayLock(configManager, resolved, "verify", lockOpts)
+	if err != nil {
+		return err
+	}
+	defer gatewayLock.Release()
+
+	// 両方の値が指定されていなければカタログから公称値を取得する
+	var catalog []model.Model
+	if *claimedContext == 0 || *claimedOutput == 0 {
+		fmt.Fprintf(os.Stderr, "Fetching advertised limits from %s...\n", resolved.Gateway.URL)
+		catalog, err = fetchAdvertisedCatalog(resolved)
+		if err != nil {
+			return err
+		}
+	}
+
+	client := api.NewProbeClient(newProbeClientConfig(resolved, verifyCmd))
+	verifier := probe.NewClaimVerifier(client)
+
+	progress := progressOpts.newProgress()
+	defer progress.Finish()
+
+	var reports []*probe.VerifyReport
+	var failed []string
+	for i, modelID := range modelIDs {
+		claims := advertisedClaims(catalog, modelID)
+		if *claimedContext > 0 {
+			claims.ContextWindow = *claimedContext
+		}
+		if *claimedOutput > 0 {
+			claims.MaxOutput = *claimedOutput
+		}
+		if claims.ContextWindow == 0 && claims.MaxOutput == 0 {
+			progress.Finish()
+			fmt.Fprintf(os.Stderr, "Warning: no advertised limits for %s, skipping\n", modelID)
+			continue
+		}
+
+		progress.StartItem(modelID, i+1, len(modelIDs))
+		report, err := verifier.Verify(modelID, resolved.Gateway.Name, claims)
+		if err != nil {
+			if !*continueOnError {
+				return fmt.Errorf("failed to verify limits for %s: %w", modelID, err)
+			}
+			// 失敗を記録して次のモデルに進む
+			progress.FinishItem("failed")
+			failed = append(failed, modelID)
+			reports = append(reports, &probe.VerifyReport{
+				Model:      modelID,
+				Gateway:    resolved.Gateway.Name,
+				Checks:     []probe.ClaimCheck{},
+				VerifiedAt: time.Now(),
+				Error:      redact.String(err.Error()),
+			})
+			continue
+		}
+		reports = append(reports, report)
+		if report.Mismatch() {
+			progress.FinishItem("mismatch")
+		} else {
+			progress.FinishItem("ok")
+		}
+
+		// 利用統計（オプトイン）
+		var contextTrials, outputTrials []probe.TrialInfo
+		if check, ok := report.Check(probe.ClaimContextWindow); ok {
+			contextTrials = check.Trials
+		}
+		if check, ok := report.Check(probe.ClaimMaxOutput); ok {
+			outputTrials = check.Trials
+		}
+		recordTrialSpend(configManager, resolved, modelID, trialUsage(contextTrials), trialUsage(outputTrials))
+	}
+
+	progress.Finish()
+
+	if *outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		printVerifyReports(reports)
+	}
+
+	if len(failed) > 0 {
+		return batchFailureError(failed, len(modelIDs))
+	}
+	if *failOnMismatch {
+		for _, report := range reports {
+			if report.Mismatch() {
+				return fmt.Errorf("advertised limits do not match the gateway behavior")
+			}
+		}
+	}
+	return nil
+}
+
+// fetchAdvertisedCatalog はゲートウェイのモデル一覧を取得する
+func fetchAdvertisedCatalog(resolved *internalConfig.ResolvedConfig) ([]model.Model, error) {
+	cfg := internalConfig.New(resolved.Gateway.URL, resolved.Gateway.APIKey, resolved.Gateway.Timeout)
+	cfg.Timeouts = resolved.Gateway.Timeouts
+	cfg.Proxy = resolved.Gateway.Proxy
+
+	response, err := api.NewClient(cfg).FetchModelsWithFallback()
+	if err != nil {
+		return nil, errhandler.WrapErrorWithDetection(err, resolved.Gateway.URL)
+	}
+	return model.FromAPIResponse(response.Models), nil
+}
+
+// advertisedClaims はカタログのmax_tokensとmax_output_tokensを公称値として返す
+func advertisedClaims(catalog []model.Model, modelID string) probe.Claims {
+	var claims probe.Claims
+	for _, m := range catalog {
+		if m.Name != modelID {
+			continue
+		}
+		claims.ContextWindow = m.MaxTokens
+		for _, key := range []string{"max_output_tokens", "max_output"} {
+			if value, ok := m.MetaValue(key); ok {
+				if parsed, err := strconv.Atoi(model.FormatMetaValue(value)); err == nil && parsed > 0 {
+					claims.MaxOutput = parsed
+					break
+				}
+			}
+		}
+		break
+	}
+	return claims
+}
+
+// printVerifyReports は制約値ごとの検証結果を表示する
+func printVerifyReports(reports []*probe.VerifyReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tLIMIT\tCLAIMED\tOBSERVED\tSTATUS\tDETAIL")
+	for _, report := range reports {
+		if report.Error != "" {
+			fmt.Fprintf(w, "%s\t-\t-\t-\tFAILED\t%s\n", report.Model, report.Error)
+			continue
+		}
+		for _, check := range report.Checks {
+			observed := "-"
+			if check.Observed > 0 {
+				observed = strconv.Itoa(check.Observed)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n",
+				report.Model, check.Limit, check.Claimed, observed, check.Status, check.Detail)
+		}
+	}
+	w.Flush()
+}
+
+// showVerifyHelp はverifyコマンドのヘルプを表示する
+func showVerifyHelp() {
+	fmt.Println(`llm-info verify - Confirm the limits advertised by the gateway catalog
+
+USAGE:
+    llm-info verify --model <MODEL_ID>[,<MODEL_ID>...] [flags]
+
+FLAGS:
+    --model string           Target model ID, or a comma-separated list (required)
+    --url string             Base URL of the LLM gateway
+    --api-key string         API key for authentication
+    --gateway string         Gateway name to use from config
+    --timeout duration       Request timeout (default: timeouts.probe, then 30s)
+    --claimed-context int    Context window to verify instead of the catalog's max_tokens
+    --claimed-output int     Max output tokens to verify instead of the catalog's max_output_tokens
+    --fail-on-mismatch       Exit with an error if any limit is OVERSTATED or UNDERSTATED
+    --format string          Output format (table, json) (default: table)
+    --continue-on-error      Record a model that fails to verify and continue with the next one
+    --wait duration          Wait for another probe of the same gateway to finish (default: fail immediately)
+    --force                  Take over the gateway lock held by another probe
+    --quiet                  Do not show progress
+    --plain                  Show progress as log lines instead of a progress bar
+    --config string          Path to config file
+    --help                   Show help for verify command
+
+EXAMPLES:
+    # Verify the catalog's limits for one model
+    llm-info verify --model gpt-4o-mini
+
+    # Routine audit of several models in CI
+    llm-info verify --model gpt-4o-mini,gpt-4o --gateway production --fail-on-mismatch
+
+    # Verify a long list without stopping at a model that fails
+    llm-info verify --model gpt-4o-mini,gpt-4o,o1-mini --continue-on-error --format json
+
+    # Verify a documented value that the catalog does not publish
+    llm-info verify --model gpt-4o-mini --claimed-context 128000
+
+DESCRIPTION:
+    Reads max_tokens (context window) and max_output_tokens from the model
+    catalog and sends two requests per limit: one exactly at the claim and one
+    just above it.
+
+      CONFIRMED     accepted at the claim, rejected above it
+      OVERSTATED    rejected at the claim (the real limit is lower)
+      UNDERSTATED   accepted above the claim (the real limit is higher,
+                    or the gateway does not enforce it)
+      INCONCLUSIVE  the prompt was counted as fewer tokens than intended
+
+    When an error message reports the limit, that value is compared with the
+    claim directly. The context window check sends a prompt of the claimed
+    size once, so it costs about one full context of input tokens; the max
+    output check asks for a short reply and costs almost nothing. Use
+    'llm-info probe' to discover limits that are not advertised.
+
+    By default the first model that cannot be verified (for example because
+    of a connection error) stops the run. With --continue-on-error the model
+    is shown as FAILED, or with an "error" field in JSON, the remaining models
+    are verified, and the command exits with status 1 naming the failures.`)
+}
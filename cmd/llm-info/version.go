@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/internal/update"
+)
+
+// バージョン情報（リリースビルドでは -ldflags "-X main.version=... -X main.commit=... -X main.buildDate=..." で埋め込む）
+var (
+	version   = "1.0.0"
+	commit    = ""
+	buildDate = ""
+)
+
+// buildMetadata はバイナリのビルド情報
+type buildMetadata struct {
+	Version   string
+	Commit    string // 未コミットの変更を含むビルドでは末尾に -dirty を付ける
+	BuildDate string
+	GoVersion string
+	Platform  string
+}
+
+// readBuildMetadata はldflagsで埋め込まれたビルド情報を返す
+// 埋め込まれていない項目は、go buildが記録したVCSの情報（debug.ReadBuildInfo）で補う
+func readBuildMetadata() buildMetadata {
+	meta := buildMetadata{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return meta
+	}
+	if info.GoVersion != "" {
+		meta.GoVersion = info.GoVersion
+	}
+	var revision, vcsTime string
+	modified := false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.time":
+			vcsTime = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if meta.Commit == "" && revision != "" {
+		meta.Commit = revision[:min(len(revision), 12)]
+		if modified {
+			meta.Commit += "-dirty"
+		}
+	}
+	if meta.BuildDate == "" {
+		meta.BuildDate = vcsTime
+	}
+	return meta
+}
+
+// String はバージョンとコミットを1行で返す（クラッシュレポートなどに使う）
+func (m buildMetadata) String() string {
+	if m.Commit == "" {
+		return m.Version
+	}
+	return fmt.Sprintf("%s (%s)", m.Version, m.Commit)
+}
+
+// checkForUpdate は最新のGitHubリリースと実行中のバージョンを比較して表示する
+func checkForUpdate(timeout time.Duration) error {
+	updater := update.New(timeout)
+	updater.APIURL = envOrDefault("LLM_INFO_UPDATE_API_URL", update.DefaultAPIURL)
+	updater.Token = os.Getenv("GITHUB_TOKEN")
+	redact.Register(updater.Token)
+
+	release, err := updater.Latest()
+	if err != nil {
+		return fmt.Errorf("failed to check for updates: %w", err)
+	}
+	if update.CompareVersions(release.Version(), version) > 0 {
+		fmt.Printf("Update available: %s -> %s\n", version, release.Version())
+		if release.HTMLURL != "" {
+			fmt.Printf("Release notes: %s\n", release.HTMLURL)
+		}
+		fmt.Println("Run `llm-info self-update` to install it.")
+		return nil
+	}
+	fmt.Printf("llm-info %s is up to date (latest release: %s)\n", version, release.Version())
+	return nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	errhandler "github.com/armaniacs/llm-info/internal/error"
+	"github.com/armaniacs/llm-info/internal/ghactions"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/notify"
+	"github.com/armaniacs/llm-info/internal/ui"
+)
+
+// catalogChangeEvent はwatchモードのJSON出力1件分
+type catalogChangeEvent struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Gateway   string            `json:"gateway,omitempty"`
+	Diff      model.CatalogDiff `json:"diff"`
+}
+
+// runWatch はモデル一覧を定期的に取得し、変更を検出したら表示・通知する
+func runWatch(client *api.Client, resolvedConfig *internalConfig.ResolvedConfig, renderOptions *ui.RenderOptions, interval time.Duration, notifier *notify.Notifier, githubSummary bool) error {
+	if interval <= 0 {
+		return errhandler.CreateUserError("invalid_argument", "--watch-interval", fmt.Errorf("watch interval must be positive"))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 初回取得結果を基準として表示
+	previous, err := fetchCatalog(client, resolvedConfig)
+	if err != nil {
+		return err
+	}
+	// json-patchでは空の一覧から初回の一覧を組み立てる操作を出力する
+	if resolvedConfig.OutputFormat == "json-patch" {
+		printCatalogPatch(nil, previous)
+	} else if err := renderModels(previous, resolvedConfig.OutputFormat, renderOptions); err != nil {
+		return err
+	}
+
+	ui.Infof("\n👀 Watching for catalog changes every %s (Ctrl+C to stop)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := fetchCatalog(client, resolvedConfig)
+		if err != nil {
+			// 一時的な取得失敗では監視を継続する
+			ui.Warnf("Warning: failed to fetch models: %v", err)
+			continue
+		}
+
+		diff := model.Diff(previous, current)
+		before := previous
+		previous = current
+		if diff.IsEmpty() {
+			continue
+		}
+
+		event := catalogChangeEvent{
+			Timestamp: time.Now(),
+			Gateway:   resolvedConfig.Gateway.Name,
+			Diff:      diff,
+		}
+		if resolvedConfig.OutputFormat == "json-patch" {
+			printCatalogPatch(before, current)
+		} else {
+			printCatalogChange(event, resolvedConfig.OutputFormat)
+		}
+
+		if githubSummary {
+			writeGitHubSummary(ghactions.CatalogDiffSummary(resolvedConfig.Gateway.Name, diff))
+		}
+
+		if notifier.Enabled(notify.EventCatalogChanged) {
+			payload := &notify.Payload{
+				Event:     notify.EventCatalogChanged,
+				Title:     fmt.Sprintf("Model catalog changed at %s", ui.MaskURL(resolvedConfig.Gateway.URL)),
+				Summary:   diff.Summary(),
+				Gateway:   resolvedConfig.Gateway.Name,
+				Details:   diff,
+				Timestamp: event.Timestamp,
+			}
+			if err := notifier.Send(payload); err != nil {
+				ui.Warnf("Warning: failed to send notification: %v", err)
+			}
+		}
+	}
+}
+
+// fetchCatalog はモデル一覧を取得してフィルタ・ソートを適用し、post_fetchフックに渡す
+func fetchCatalog(client *api.Client, resolvedConfig *internalConfig.ResolvedConfig) ([]model.Model, error) {
+	response, err := client.FetchModelsWithFallback()
+	if err != nil {
+		return nil, errhandler.WrapErrorWithDetection(err, resolvedConfig.Gateway.URL)
+	}
+
+	models, err := dedupeModels(model.FromAPIResponse(response.Models), resolvedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolvedConfig.Filter != "" {
+		filterCriteria, err := ui.ParseFilterString(resolvedConfig.Filter)
+		if err != nil {
+			return nil, errhandler.CreateUserError("invalid_filter_syntax", resolvedConfig.Filter, err)
+		}
+		models = ui.Filter(models, filterCriteria)
+	}
+
+	if resolvedConfig.SortBy != "" {
+		sortCriteria, err := ui.ParseSortString(resolvedConfig.SortBy)
+		if err != nil {
+			return nil, errhandler.CreateUserError("invalid_sort_field", resolvedConfig.SortBy, err)
+		}
+		ui.Sort(models, sortCriteria)
+	}
+
+	runFetchHook(resolvedConfig, models)
+	return models, nil
+}
+
+// renderModels は出力形式に応じてモデル一覧を表示する
+func renderModels(models []model.Model, outputFormat string, renderOptions *ui.RenderOptions) error {
+	switch outputFormat {
+	case "json":
+		if err := ui.RenderJSONWithOptions(models, renderOptions); err != nil {
+			return errhandler.CreateSystemError("unexpected_error", "JSON rendering", err)
+		}
+	default:
+		if err := ui.RenderTableWithOptions(models, renderOptions); err != nil {
+			return errhandler.CreateSystemError("unexpected_error", "table rendering", err)
+		}
+	}
+	return nil
+}
+
+// printCatalogChange は検出した変更を表示する
+func printCatalogChange(event catalogChangeEvent, outputFormat string) {
+	if outputFormat == "json" {
+		data, err := json.Marshal(event)
+		if err != nil {
+			ui.Warnf("Warning: failed to encode change event: %v", err)
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Printf("\n[%s] 🔔 Catalog changed:\n", event.Timestamp.Format("2006-01-02 15:04:05"))
+	for _, line := range event.Diff.Summary() {
+		fmt.Printf("  - %s\n", line)
+	}
+}
+
+// printCatalogPatch はbeforeからafterへの変更をJSON Patch（RFC 6902）として1行で出力する
+func printCatalogPatch(before, after []model.Model) {
+	ops, err := model.JSONPatch(before, after)
+	if err != nil {
+		ui.Warnf("Warning: failed to build JSON patch: %v", err)
+		return
+	}
+	data, err := json.Marshal(ops)
+	if err != nil {
+		ui.Warnf("Warning: failed to encode JSON patch: %v", err)
+		return
+	}
+	fmt.Println(string(data))
+}
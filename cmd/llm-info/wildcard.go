@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/model"
+)
+
+// resolveWildcardTarget は"openai/*"のようなワイルドカードのグループを探索するモデルIDに置き換える
+// representativeが指定されていればパターンに一致することを確かめて使い、なければ
+// キャッシュ済み（なければ取得した）モデル一覧から代表のモデルを選ぶ
+// ワイルドカードでないモデルIDはそのまま返す
+func resolveWildcardTarget(configManager *internalConfig.Manager, resolved *internalConfig.ResolvedConfig, target, representative string) (string, error) {
+	if !model.IsWildcard(target) {
+		if representative != "" {
+			return "", fmt.Errorf("--representative requires a wildcard --model such as openai/*")
+		}
+		return target, nil
+	}
+
+	if representative != "" {
+		if model.IsWildcard(representative) || !model.MatchWildcard(target, representative) {
+			return "", fmt.Errorf("--representative %s is not a model ID matching %s", representative, target)
+		}
+		fmt.Fprintf(os.Stderr, "Probing wildcard group %s with representative member %s\n", target, representative)
+		return representative, nil
+	}
+
+	models, err := wildcardCatalog(configManager, resolved)
+	if err != nil {
+		return "", fmt.Errorf("failed to list models to choose a representative for %s: %w (pass --representative)", target, err)
+	}
+	member, ok := model.Representative(target, models)
+	if !ok {
+		return "", fmt.Errorf("no listed model matches %s; pass --representative with a model ID the gateway routes through it", target)
+	}
+	fmt.Fprintf(os.Stderr, "Probing wildcard group %s with representative member %s (of %d listed; override with --representative)\n",
+		target, member, len(model.WildcardMembers(target, models)))
+	return member, nil
+}
+
+// wildcardCatalog はゲートウェイのモデル一覧を返す。キャッシュがあればネットワークに接続しない
+func wildcardCatalog(configManager *internalConfig.Manager, resolved *internalConfig.ResolvedConfig) ([]model.Model, error) {
+	if catalog, err := catalogCache(configManager); err == nil {
+		if entry, err := catalog.Load(resolved.Gateway.URL); err == nil {
+			return model.FromAPIResponse(entry.Models), nil
+		}
+	}
+
+	cfg := internalConfig.New(resolved.Gateway.URL, resolved.Gateway.APIKey, resolved.Gateway.Timeout)
+	cfg.Timeouts = resolved.Gateway.Timeouts
+	cfg.Proxy = resolved.Gateway.Proxy
+	response, err := api.NewClient(cfg).FetchModelsWithFallback()
+	if err != nil {
+		return nil, err
+	}
+	return model.FromAPIResponse(response.Models), nil
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/redact"
 )
 
 // Client はAPIクライアントです
@@ -24,9 +26,7 @@ func NewClient(cfg *config.Config) *Client {
 		baseURL: cfg.BaseURL,
 		apiKey:  cfg.APIKey,
 		timeout: cfg.Timeout,
-		client: &http.Client{
-			Timeout: cfg.Timeout,
-		},
+		client:  newHTTPClient(cfg.Timeout, cfg.Timeouts, cfg.Proxy),
 	}
 }
 
@@ -46,11 +46,11 @@ func (c *Client) GetModelInfo() (*ModelInfoResponse, error) {
 	req.Header.Set("Content-Type", "application/json")
 
 	// リクエスト送信
-	resp, err := c.client.Do(req)
+	resp, err := doRequest(c.client, req, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
-	defer resp.Body.Close()
+	defer drainAndClose(resp.Body)
 
 	// ステータスコードのチェック
 	if resp.StatusCode != http.StatusOK {
@@ -63,7 +63,7 @@ func (c *Client) GetModelInfo() (*ModelInfoResponse, error) {
 			errorMsg = getDefaultStatusMessage(resp.StatusCode)
 		}
 
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, errorMsg)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, redact.String(errorMsg))
 	}
 
 	// レスポンスボディを読み取り
@@ -85,8 +85,9 @@ func (c *Client) GetModelInfo() (*ModelInfoResponse, error) {
 		if len(preview) > 200 {
 			preview = preview[:200] + "..."
 		}
-		return nil, fmt.Errorf("failed to decode JSON response: %w. Response preview: %s", err, preview)
+		return nil, fmt.Errorf("failed to decode JSON response: %w. Response preview: %s", err, redact.String(preview))
 	}
+	response.Raw = []RawResponse{{Endpoint: "/model/info", Body: body}}
 
 	return &response, nil
 }
@@ -98,12 +99,18 @@ func getDefaultStatusMessage(statusCode int) string {
 		return "Bad Request - Invalid parameters or request format"
 	case 401:
 		return "Unauthorized - Invalid or missing API key"
+	case 402:
+		return "Payment Required - The account has no credit or the budget is exhausted"
 	case 403:
 		return "Forbidden - Insufficient permissions to access this resource"
 	case 404:
 		return "Not Found - The requested endpoint does not exist"
+	case 413:
+		return "Payload Too Large - The request body exceeds the size limit"
 	case 429:
 		return "Too Many Requests - Rate limit exceeded"
+	case 451:
+		return "Unavailable For Legal Reasons - Blocked in this region or for this account"
 	case 500:
 		return "Internal Server Error - The server encountered an unexpected error"
 	case 502:
@@ -112,6 +119,8 @@ func getDefaultStatusMessage(statusCode int) string {
 		return "Service Unavailable - The server is temporarily unavailable"
 	case 504:
 		return "Gateway Timeout - The server took too long to respond"
+	case 529:
+		return "Overloaded - The provider is temporarily overloaded"
 	default:
 		return fmt.Sprintf("HTTP Error %d", statusCode)
 	}
@@ -129,6 +138,7 @@ func (c *Client) FetchModelsWithFallback() (*ModelInfoResponse, error) {
 		litellmResp, litellmErr := c.GetModelInfo()
 		if litellmErr == nil {
 			// 詳細情報が取得できた場合はそちらを優先
+			litellmResp.Raw = append(baseModels.Raw, litellmResp.Raw...)
 			return litellmResp, nil
 		}
 
@@ -137,8 +147,8 @@ func (c *Client) FetchModelsWithFallback() (*ModelInfoResponse, error) {
 		return baseModels, nil
 	}
 
-	// 標準エンドポイント失敗時の警告
-	fmt.Printf("⚠️  OpenAI standard endpoint failed, falling back to LiteLLM endpoint: %v\n", standardErr)
+	// 標準エンドポイント失敗時の警告（表やJSONと混ざらないよう標準エラー出力に出す）
+	fmt.Fprintf(os.Stderr, "⚠️  OpenAI standard endpoint failed, falling back to LiteLLM endpoint: %v\n", standardErr)
 
 	// LiteLLMエンドポイントを試行
 	litellmResp, litellmErr := c.GetModelInfo()
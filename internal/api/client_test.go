@@ -3,10 +3,12 @@ package api
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/redact"
 )
 
 func TestClient_GetModelInfo(t *testing.T) {
@@ -188,6 +190,33 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestClient_GetModelInfo_RedactsEchoedKey(t *testing.T) {
+	redact.Reset()
+	defer redact.Reset()
+
+	apiKey := "gw-secret-123"
+	redact.Register(apiKey)
+
+	// 一部のゲートウェイは誤ったキーをエラーメッセージにそのまま含める
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": "Invalid API key: ` + r.Header.Get("Authorization") + `"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(config.New(server.URL, apiKey, 5*time.Second))
+	_, err := client.GetModelInfo()
+	if err == nil {
+		t.Fatal("GetModelInfo() expected error")
+	}
+	if strings.Contains(err.Error(), apiKey) {
+		t.Errorf("error contains the API key: %v", err)
+	}
+	if !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("error should keep the status code: %v", err)
+	}
+}
+
 func TestClient_FetchModelsWithFallback(t *testing.T) {
 	tests := []struct {
 		name               string
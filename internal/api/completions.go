@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// chatUnsupportedPatterns はモデルがチャット形式に対応していないことを表すエラーメッセージのパターン
+var chatUnsupportedPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)not a chat model`),
+	regexp.MustCompile(`(?i)not supported (in|on|by) the v1/chat/completions`),
+	regexp.MustCompile(`(?i)did you mean to use v1/completions`),
+	regexp.MustCompile(`(?i)does not support chat`),
+}
+
+// completionRequest は/v1/completionsのリクエスト
+type completionRequest struct {
+	Model       string  `json:"model"`
+	Prompt      string  `json:"prompt"`
+	MaxTokens   int     `json:"max_tokens"`
+	Temperature float64 `json:"temperature"`
+}
+
+// completionResponse は/v1/completionsのレスポンス
+type completionResponse struct {
+	ID      string             `json:"id"`
+	Object  string             `json:"object"`
+	Created int64              `json:"created"`
+	Model   string             `json:"model"`
+	Choices []completionChoice `json:"choices"`
+	Usage   *UsageInfo         `json:"usage"`
+	Error   *OpenAIError       `json:"error"`
+}
+
+// completionChoice は/v1/completionsの選択肢
+type completionChoice struct {
+	Index        int             `json:"index"`
+	Text         string          `json:"text"`
+	FinishReason string          `json:"finish_reason"`
+	Logprobs     json.RawMessage `json:"logprobs,omitempty"`
+}
+
+// toProbeResponse はチャット形式のレスポンスに変換する（生成された文章はassistantのメッセージとして扱う）
+func (r *completionResponse) toProbeResponse() *ProbeResponse {
+	resp := &ProbeResponse{
+		ID:      r.ID,
+		Object:  r.Object,
+		Created: r.Created,
+		Model:   r.Model,
+		Usage:   r.Usage,
+		Error:   r.Error,
+	}
+	for _, choice := range r.Choices {
+		resp.Choices = append(resp.Choices, ChatChoice{
+			Index:        choice.Index,
+			Message:      ChatMessage{Role: "assistant", Content: choice.Text},
+			FinishReason: choice.FinishReason,
+			Logprobs:     choice.Logprobs,
+		})
+	}
+	return resp
+}
+
+// Endpoint はモデルの探索に使うエンドポイント（chat, completions, responses）を返す
+// autoでまだ判定していない場合はchatを返す
+func (pc *ProbeClient) Endpoint(modelID string) string {
+	switch pc.config.ProbeEndpoint {
+	case config.ProbeEndpointCompletions, config.ProbeEndpointResponses:
+		return pc.config.ProbeEndpoint
+	}
+	if _, ok := pc.completionModels.Load(modelID); ok {
+		return config.ProbeEndpointCompletions
+	}
+	return config.ProbeEndpointChat
+}
+
+// EndpointPath はモデルの探索に使うエンドポイントのパスを返す
+func (pc *ProbeClient) EndpointPath(modelID string) string {
+	switch pc.Endpoint(modelID) {
+	case config.ProbeEndpointCompletions:
+		return "/v1/completions"
+	case config.ProbeEndpointResponses:
+		return "/v1/responses"
+	}
+	return "/v1/chat/completions"
+}
+
+// resolveEndpoint はautoの場合にモデルがチャット形式に対応しているかを判定する
+// 最初に小さなチャットリクエストを1回送り、対応していなければ以降は/v1/completionsを使う
+// 探索本体のプロンプトは送信しながら生成するため、拒否された後に送り直すことができない
+func (pc *ProbeClient) resolveEndpoint(modelID string) {
+	if pc.config.ProbeEndpoint != config.ProbeEndpointAuto {
+		return
+	}
+	if _, checked := pc.checkedModels.LoadOrStore(modelID, true); checked {
+		return
+	}
+	resp, err := pc.probeChat(modelID, []Message{{Role: "user", Content: "test"}}, 1, nil)
+	if err != nil && chatUnsupported(resp, err) {
+		pc.completionModels.Store(modelID, true)
+	}
+}
+
+// chatUnsupported はエラーがモデルのチャット形式への非対応によるものかを返す
+func chatUnsupported(resp *ProbeResponse, err error) bool {
+	message := err.Error()
+	if resp != nil && resp.Error != nil {
+		message = resp.Error.Message
+	}
+	for _, pattern := range chatUnsupportedPatterns {
+		if pattern.MatchString(message) {
+			return true
+		}
+	}
+	return false
+}
+
+// promptFromMessages はメッセージ列を/v1/completionsのpromptに変換する
+func promptFromMessages(messages []Message) string {
+	contents := make([]string, 0, len(messages))
+	for _, m := range messages {
+		contents = append(contents, m.Content)
+	}
+	return strings.Join(contents, "\n\n")
+}
+
+// probeCompletion は/v1/completionsにリクエストを送信し、チャット形式のレスポンスに変換して返す
+func (pc *ProbeClient) probeCompletion(modelID string, content io.Reader, maxTokens int, params map[string]any) (*ProbeResponse, error) {
+	var body io.Reader
+	if len(params) > 0 {
+		// パラメータを追加する場合は小さなプロンプトなのでメモリ上で組み立てる
+		prompt, err := io.ReadAll(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt: %w", err)
+		}
+		jsonBody, err := json.Marshal(completionRequest{Model: modelID, Prompt: string(prompt), MaxTokens: maxTokens})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		if jsonBody, err = mergeRequestParams(jsonBody, params); err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(jsonBody)
+	} else {
+		var err error
+		if body, err = newCompletionRequestBody(modelID, maxTokens, 0, content); err != nil {
+			return nil, err
+		}
+	}
+
+	var completionResp completionResponse
+	status, err := pc.postProbe("/v1/completions", body, &completionResp)
+	if err != nil {
+		return nil, err
+	}
+	return checkProbeStatus(status, completionResp.toProbeResponse())
+}
+
+// postProbe は探索リクエストをpathに送信し、レスポンスのJSONをoutに読み込んでステータスコードを返す
+func (pc *ProbeClient) postProbe(path string, body io.Reader, out any) (int, error) {
+	// HTTPリクエストを作成
+	httpReq, err := http.NewRequestWithContext(pc.stats.withTrace(context.Background()), "POST", pc.config.BaseURL+path, body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	setReplayableBody(httpReq, body)
+
+	// ヘッダーを設定
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", pc.config.APIKey))
+
+	// リクエストを送信
+	resp, err := pc.send(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer drainAndClose(resp.Body)
+	pc.stats.recordResponse(resp)
+	pc.recordHeader(resp)
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		return resp.StatusCode, fmt.Errorf("%w (status %d)", ErrRequestTooLarge, resp.StatusCode)
+	}
+
+	// レスポンスを読み込む
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// checkProbeStatus はステータスコードをチェックし、エラーの場合はエラー情報付きのレスポンスを返す
+func checkProbeStatus(status int, probeResp *ProbeResponse) (*ProbeResponse, error) {
+	if status != http.StatusOK {
+		if probeResp.Error != nil {
+			return probeResp, fmt.Errorf("API error (%s): %s", probeResp.Error.Type, redact.String(probeResp.Error.Message))
+		}
+		return nil, fmt.Errorf("unexpected status code: %d", status)
+	}
+	return probeResp, nil
+}
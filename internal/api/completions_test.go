@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// legacyServer はチャット形式に対応しない旧来のモデルを模したテストサーバー
+func legacyServer(t *testing.T, chatCalls *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/chat/completions":
+			chatCalls.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ProbeResponse{Error: &OpenAIError{
+				Type:    "invalid_request_error",
+				Message: "This is not a chat model and thus not supported in the v1/chat/completions endpoint. Did you mean to use v1/completions?",
+			}})
+		case "/v1/completions":
+			var req completionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+				return
+			}
+			json.NewEncoder(w).Encode(completionResponse{
+				Model:   req.Model,
+				Choices: []completionChoice{{Text: "echo: " + req.Prompt, FinishReason: "length"}},
+				Usage:   &UsageInfo{PromptTokens: len(req.Prompt), CompletionTokens: req.MaxTokens, TotalTokens: len(req.Prompt) + req.MaxTokens},
+			})
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newEndpointTestClient(server *httptest.Server, endpoint string) *ProbeClient {
+	return NewProbeClient(&config.AppConfig{BaseURL: server.URL, APIKey: "test", Timeout: 10 * time.Second, ProbeEndpoint: endpoint})
+}
+
+func TestProbeClient_CompletionsEndpoint(t *testing.T) {
+	var chatCalls atomic.Int32
+	client := newEndpointTestClient(legacyServer(t, &chatCalls), config.ProbeEndpointCompletions)
+
+	resp, err := client.ProbeModelWithMaxTokens("davinci-002", strings.NewReader("hello"), 8)
+	if err != nil {
+		t.Fatalf("ProbeModelWithMaxTokens() error = %v", err)
+	}
+	if len(resp.Choices) != 1 || resp.Choices[0].Message.Content != "echo: hello" || resp.Choices[0].FinishReason != "length" {
+		t.Errorf("Choices = %+v", resp.Choices)
+	}
+	if resp.Usage == nil || resp.Usage.CompletionTokens != 8 {
+		t.Errorf("Usage = %+v, want 8 completion tokens", resp.Usage)
+	}
+
+	// メッセージ列は1つのpromptにまとめる
+	resp, err = client.ProbeMessagesWithParams("davinci-002", []Message{{Role: "system", Content: "a"}, {Role: "user", Content: "b"}}, 4, map[string]any{"seed": 1})
+	if err != nil {
+		t.Fatalf("ProbeMessagesWithParams() error = %v", err)
+	}
+	if resp.Choices[0].Message.Content != "echo: a\n\nb" {
+		t.Errorf("Content = %q", resp.Choices[0].Message.Content)
+	}
+	if chatCalls.Load() != 0 {
+		t.Errorf("chat calls = %d, want 0", chatCalls.Load())
+	}
+	if got := client.EndpointPath("davinci-002"); got != "/v1/completions" {
+		t.Errorf("EndpointPath() = %q", got)
+	}
+}
+
+func TestProbeClient_AutoEndpoint(t *testing.T) {
+	var chatCalls atomic.Int32
+	client := newEndpointTestClient(legacyServer(t, &chatCalls), config.ProbeEndpointAuto)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.ProbeModelWithReader("davinci-002", strings.NewReader("hello")); err != nil {
+			t.Fatalf("ProbeModelWithReader() error = %v", err)
+		}
+	}
+	// チャット形式への対応はモデルごとに1回だけ確認する
+	if chatCalls.Load() != 1 {
+		t.Errorf("chat calls = %d, want 1", chatCalls.Load())
+	}
+	if got := client.EndpointPath("davinci-002"); got != "/v1/completions" {
+		t.Errorf("EndpointPath() = %q", got)
+	}
+
+	// chatのままではチャット形式のエラーがそのまま返る
+	chat := newEndpointTestClient(legacyServer(t, &chatCalls), config.ProbeEndpointChat)
+	if _, err := chat.ProbeModel("davinci-002"); err == nil || !strings.Contains(err.Error(), "not a chat model") {
+		t.Errorf("ProbeModel() error = %v, want chat model error", err)
+	}
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ConnStats はHTTP接続の再利用状況を集計する
+type ConnStats struct {
+	requests atomic.Int64
+	newConns atomic.Int64
+	reused   atomic.Int64
+	http2    atomic.Int64
+
+	// レート制限の集計（ゲートウェイが遅いのか、制限で待たされたのかを区別するため）
+	waits     atomic.Int64 // max_concurrency、rate_limitの空きを待ったリクエスト数
+	waitTime  atomic.Int64 // 空きを待った時間の合計（ナノ秒）
+	throttled atomic.Int64 // ゲートウェイが429を返したリクエスト数
+	retries   atomic.Int64 // 429を返されて再送したリクエスト数（max_retries）
+	backoff   atomic.Int64 // 再送までに待った時間の合計（ナノ秒）
+}
+
+// ConnStatsSnapshot はConnStatsのある時点の値
+type ConnStatsSnapshot struct {
+	Requests       int64 `json:"requests"`
+	NewConnections int64 `json:"new_connections"`
+	Reused         int64 `json:"reused"`
+	HTTP2          int64 `json:"http2"`
+}
+
+// Snapshot は現在の集計値を返す
+func (s *ConnStats) Snapshot() ConnStatsSnapshot {
+	return ConnStatsSnapshot{
+		Requests:       s.requests.Load(),
+		NewConnections: s.newConns.Load(),
+		Reused:         s.reused.Load(),
+		HTTP2:          s.http2.Load(),
+	}
+}
+
+// Throttling はレート制限で待った回数と時間、ゲートウェイが429を返した回数と再送の集計
+type Throttling struct {
+	Waits     int64         // max_concurrency、rate_limitの空きを待ったリクエスト数
+	Wait      time.Duration // 空きを待った時間の合計
+	Throttled int64         // ゲートウェイが429（Too Many Requests）を返したリクエスト数
+	Retries   int64         // 429を返されて再送したリクエスト数（max_retries）
+	Backoff   time.Duration // 再送までに待った時間の合計
+}
+
+// Throttling は現在のレート制限の集計値を返す
+func (s *ConnStats) Throttling() Throttling {
+	return Throttling{
+		Waits:     s.waits.Load(),
+		Wait:      time.Duration(s.waitTime.Load()),
+		Throttled: s.throttled.Load(),
+		Retries:   s.retries.Load(),
+		Backoff:   time.Duration(s.backoff.Load()),
+	}
+}
+
+// Sub はbeforeからの増分を返す（1回の試行や探索の間の集計に使う）
+func (t Throttling) Sub(before Throttling) Throttling {
+	return Throttling{
+		Waits:     t.Waits - before.Waits,
+		Wait:      t.Wait - before.Wait,
+		Throttled: t.Throttled - before.Throttled,
+		Retries:   t.Retries - before.Retries,
+		Backoff:   t.Backoff - before.Backoff,
+	}
+}
+
+// IsZero はレート制限で待たず、429も返されず、再送もしなかったかを返す
+func (t Throttling) IsZero() bool {
+	return t.Waits == 0 && t.Throttled == 0 && t.Retries == 0
+}
+
+// String は「3 requests waited 4.2s for max_concurrency/rate_limit, 1 throttled by the gateway (429), 1 retry after 2s of backoff」の形式で返す
+func (t Throttling) String() string {
+	if t.IsZero() {
+		return "none"
+	}
+	var parts []string
+	if t.Waits > 0 {
+		parts = append(parts, fmt.Sprintf("%d requests waited %s for max_concurrency/rate_limit", t.Waits, t.Wait.Round(100*time.Millisecond)))
+	}
+	if t.Throttled > 0 {
+		parts = append(parts, fmt.Sprintf("%d throttled by the gateway (429)", t.Throttled))
+	}
+	if t.Retries > 0 {
+		unit := "retries"
+		if t.Retries == 1 {
+			unit = "retry"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s after %s of backoff", t.Retries, unit, t.Backoff.Round(100*time.Millisecond)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ReuseRate は接続を再利用したリクエストの割合（0〜1）を返す
+func (s ConnStatsSnapshot) ReuseRate() float64 {
+	total := s.NewConnections + s.Reused
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Reused) / float64(total)
+}
+
+// String は「12 requests, 11 reused (91.7%), 1 new, 0 over HTTP/2」の形式で返す
+func (s ConnStatsSnapshot) String() string {
+	return fmt.Sprintf("%d requests, %d reused (%.1f%%), %d new, %d over HTTP/2",
+		s.Requests, s.Reused, s.ReuseRate()*100, s.NewConnections, s.HTTP2)
+}
+
+// withTrace はリクエストごとの接続取得を記録するContextを返す
+func (s *ConnStats) withTrace(ctx context.Context) context.Context {
+	s.requests.Add(1)
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				s.reused.Add(1)
+			} else {
+				s.newConns.Add(1)
+			}
+		},
+	})
+}
+
+// recordResponse はレスポンスのプロトコルと、ゲートウェイのレート制限による拒否を記録する
+func (s *ConnStats) recordResponse(resp *http.Response) {
+	if resp.ProtoMajor == 2 {
+		s.http2.Add(1)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		s.throttled.Add(1)
+	}
+}
+
+// recordWait はmax_concurrency、rate_limitの空きを待った時間を記録する（待たなかった場合は何もしない）
+func (s *ConnStats) recordWait(wait time.Duration) {
+	if s == nil || wait <= 0 {
+		return
+	}
+	s.waits.Add(1)
+	s.waitTime.Add(int64(wait))
+}
+
+// recordRetry は429を返されたリクエストの再送と、再送までに待った時間を記録する
+func (s *ConnStats) recordRetry(backoff time.Duration) {
+	if s == nil {
+		return
+	}
+	s.requests.Add(1)
+	s.retries.Add(1)
+	s.backoff.Add(int64(backoff))
+}
+
+// drainAndClose はレスポンスボディを読み切ってから閉じる
+// 読み残しがあると接続がkeep-aliveで再利用されないため
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(io.Discard, body)
+	body.Close()
+}
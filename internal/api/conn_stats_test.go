@@ -0,0 +1,167 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/ratelimit"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestProbeClient_ReusesConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"test","choices":[{"message":{"role":"assistant","content":"ok"}}]}` + "\n"))
+	}))
+	defer server.Close()
+
+	client := NewProbeClient(&config.AppConfig{
+		BaseURL: server.URL,
+		APIKey:  "test",
+		Timeout: 5 * time.Second,
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.ProbeModelWithContent("test-model", "hello"); err != nil {
+			t.Fatalf("ProbeModelWithContent() error = %v", err)
+		}
+	}
+
+	stats := client.ConnStats()
+	if stats.Requests != 3 {
+		t.Errorf("Requests = %d, want 3", stats.Requests)
+	}
+	if stats.NewConnections != 1 || stats.Reused != 2 {
+		t.Errorf("NewConnections = %d, Reused = %d, want 1 and 2", stats.NewConnections, stats.Reused)
+	}
+}
+
+func TestSharedTransport(t *testing.T) {
+	a := newHTTPClient(10*time.Second, config.Timeouts{Dial: 7 * time.Second}, "")
+	b := newHTTPClient(time.Minute, config.Timeouts{Dial: 7 * time.Second, Probe: time.Minute}, "")
+	c := newHTTPClient(10*time.Second, config.Timeouts{Dial: 8 * time.Second}, "")
+
+	// リクエスト全体のタイムアウトが違ってもTransportは共有する
+	if a.Transport != b.Transport {
+		t.Error("clients with the same connection timeouts should share a transport")
+	}
+	if a.Transport == c.Transport {
+		t.Error("clients with different connection timeouts should not share a transport")
+	}
+}
+
+func TestConnStatsSnapshot_String(t *testing.T) {
+	s := ConnStatsSnapshot{Requests: 4, NewConnections: 1, Reused: 3}
+	want := "4 requests, 3 reused (75.0%), 1 new, 0 over HTTP/2"
+	if got := s.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestProbeClient_Throttling(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"message":"Rate limit reached","type":"rate_limit_error"}}` + "\n"))
+			return
+		}
+		w.Write([]byte(`{"id":"test","choices":[{"message":{"role":"assistant","content":"ok"}}]}` + "\n"))
+	}))
+	defer server.Close()
+
+	ratelimit.Reset()
+	defer ratelimit.Reset()
+	ratelimit.Register(server.URL, ratelimit.Limit{Interval: 30 * time.Millisecond})
+
+	client := NewProbeClient(&config.AppConfig{
+		BaseURL: server.URL,
+		APIKey:  "test",
+		Timeout: 5 * time.Second,
+	})
+	before := client.Throttling()
+	if !before.IsZero() || before.String() != "none" {
+		t.Errorf("Throttling() before any request = %+v", before)
+	}
+
+	client.ProbeModelWithContent("test-model", "hello")
+	for i := 0; i < 2; i++ {
+		if _, err := client.ProbeModelWithContent("test-model", "hello"); err != nil {
+			t.Fatalf("ProbeModelWithContent() error = %v", err)
+		}
+	}
+
+	throttling := client.Throttling().Sub(before)
+	if throttling.Throttled != 1 {
+		t.Errorf("Throttled = %d, want 1", throttling.Throttled)
+	}
+	// 1回目はすぐに送り、2回目と3回目はrate_limitの間隔を待つ
+	if throttling.Waits != 2 || throttling.Wait < 40*time.Millisecond {
+		t.Errorf("Waits = %d, Wait = %v, want 2 and at least 40ms", throttling.Waits, throttling.Wait)
+	}
+	if got := throttling.String(); !strings.Contains(got, "2 requests waited") || !strings.Contains(got, "1 throttled by the gateway (429)") {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestProbeClient_RetriesThrottled(t *testing.T) {
+	var bodies []string
+	throttle := 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		if len(bodies) <= throttle {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"message":"Rate limit reached","type":"rate_limit_error"}}` + "\n"))
+			return
+		}
+		w.Write([]byte(`{"id":"test","choices":[{"message":{"role":"assistant","content":"ok"}}]}` + "\n"))
+	}))
+	defer server.Close()
+
+	ratelimit.Reset()
+	defer ratelimit.Reset()
+	ratelimit.Register(server.URL, ratelimit.Limit{MaxRetries: 2})
+
+	client := NewProbeClient(&config.AppConfig{
+		BaseURL: server.URL,
+		APIKey:  "test",
+		Timeout: 5 * time.Second,
+	})
+
+	// 2回429を返されても、max_retriesの回数まで同じボディを送り直して成功する
+	if _, err := client.ProbeModelWithContent("test-model", "hello"); err != nil {
+		t.Fatalf("ProbeModelWithContent() error = %v", err)
+	}
+	if len(bodies) != 3 || bodies[1] != bodies[0] || bodies[2] != bodies[0] {
+		t.Fatalf("request bodies = %q, want the same body sent 3 times", bodies)
+	}
+	throttling := client.Throttling()
+	if throttling.Throttled != 2 || throttling.Retries != 2 {
+		t.Errorf("Throttled = %d, Retries = %d, want 2 and 2", throttling.Throttled, throttling.Retries)
+	}
+	if got := throttling.String(); !strings.Contains(got, "2 throttled by the gateway (429), 2 retries after 0s of backoff") {
+		t.Errorf("String() = %q", got)
+	}
+	if stats := client.ConnStats(); stats.Requests != 3 {
+		t.Errorf("Requests = %d, want 3", stats.Requests)
+	}
+
+	// max_retriesを超えて429が続く場合は、最後の429をそのまま返す
+	bodies, throttle = nil, 5
+	before := client.Throttling()
+	if _, err := client.ProbeModelWithContent("test-model", "hello"); err == nil || !strings.Contains(err.Error(), "rate_limit_error") {
+		t.Errorf("ProbeModelWithContent() error = %v, want the rate limit error", err)
+	}
+	if got := client.Throttling().Sub(before); len(bodies) != 3 || got.Throttled != 3 || got.Retries != 2 {
+		t.Errorf("requests = %d, throttling = %+v, want 3 requests, 3 throttled and 2 retries", len(bodies), got)
+	}
+}
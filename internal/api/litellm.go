@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+// HealthResponse はLiteLLMの/healthのレスポンス
+// 各エンドポイントはプロキシ配下のデプロイメント（litellm_paramsの内容とエラー）を表す
+type HealthResponse struct {
+	HealthyEndpoints   []map[string]interface{} `json:"healthy_endpoints"`
+	UnhealthyEndpoints []map[string]interface{} `json:"unhealthy_endpoints"`
+	HealthyCount       int                      `json:"healthy_count"`
+	UnhealthyCount     int                      `json:"unhealthy_count"`
+}
+
+// ModelGroupInfoResponse はLiteLLMの/model_group/infoのレスポンス
+type ModelGroupInfoResponse struct {
+	Data []ModelGroupInfo `json:"data"`
+}
+
+// ModelGroupInfo はモデルグループ（同じmodel_nameでまとめられたデプロイメント）の情報
+type ModelGroupInfo struct {
+	ModelGroup string   `json:"model_group"`
+	Providers  []string `json:"providers"`
+	Mode       string   `json:"mode,omitempty"`
+}
+
+// GetHealth はLiteLLMの/healthからデプロイメントごとの状態を取得する
+// LiteLLMは各デプロイメントに実際にリクエストを送って確認するため、時間がかかることがある
+func (c *Client) GetHealth() (*HealthResponse, error) {
+	var response HealthResponse
+	if err := c.getJSON("/health", &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetModelGroupInfo はLiteLLMの/model_group/infoからモデルグループの構成を取得する
+func (c *Client) GetModelGroupInfo() (*ModelGroupInfoResponse, error) {
+	var response ModelGroupInfoResponse
+	if err := c.getJSON("/model_group/info", &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// getJSON はGETリクエストを送信し、レスポンスをoutにデコードする
+func (c *Client) getJSON(path string, out interface{}) error {
+	req, err := http.NewRequest("GET", c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	}
+
+	resp, err := doRequest(c.client, req, nil)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer drainAndClose(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		errorBody, _ := io.ReadAll(resp.Body)
+		errorMsg := string(errorBody)
+		if errorMsg == "" {
+			errorMsg = getDefaultStatusMessage(resp.StatusCode)
+		}
+		endpoint, _, _ := strings.Cut(path, "?")
+		return fmt.Errorf("%s failed with status %d: %s", endpoint, resp.StatusCode, redact.String(errorMsg))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		endpoint, _, _ := strings.Cut(path, "?")
+		return fmt.Errorf("failed to decode %s response: %w", endpoint, err)
+	}
+	return nil
+}
+
+// KeyInfoResponse はLiteLLMの/key/infoのレスポンス
+type KeyInfoResponse struct {
+	Key  string  `json:"key"`
+	Info KeyInfo `json:"info"`
+}
+
+// KeyInfo は仮想キーの予算と利用状況
+type KeyInfo struct {
+	KeyAlias       string   `json:"key_alias,omitempty"`
+	KeyName        string   `json:"key_name,omitempty"`
+	Spend          float64  `json:"spend"`
+	MaxBudget      *float64 `json:"max_budget"` // nilは予算の上限なし
+	BudgetDuration string   `json:"budget_duration,omitempty"`
+	BudgetResetAt  string   `json:"budget_reset_at,omitempty"`
+	Expires        string   `json:"expires,omitempty"`
+	Models         []string `json:"models,omitempty"`
+	TPMLimit       *int     `json:"tpm_limit,omitempty"`
+	RPMLimit       *int     `json:"rpm_limit,omitempty"`
+}
+
+// Remaining は予算の残額を返す（上限がなければfalse）
+func (k KeyInfo) Remaining() (float64, bool) {
+	if k.MaxBudget == nil {
+		return 0, false
+	}
+	return *k.MaxBudget - k.Spend, true
+}
+
+// SpendLog はLiteLLMの/spend/logsの1件
+// リクエストごとのログ（model, total_tokens）と日ごとの集計（models）のどちらの形式も受け付ける
+type SpendLog struct {
+	RequestID   string             `json:"request_id,omitempty"`
+	StartTime   string             `json:"startTime"`
+	Model       string             `json:"model,omitempty"`
+	Models      map[string]float64 `json:"models,omitempty"`
+	Spend       float64            `json:"spend"`
+	TotalTokens int                `json:"total_tokens,omitempty"`
+}
+
+// GetKeyInfo はLiteLLMの/key/infoから仮想キーの予算と利用額を取得する
+// keyが空の場合はリクエストに使ったキー自身の情報を返す
+func (c *Client) GetKeyInfo(key string) (*KeyInfoResponse, error) {
+	path := "/key/info"
+	if key != "" {
+		path += "?key=" + url.QueryEscape(key)
+	}
+	var response KeyInfoResponse
+	if err := c.getJSON(path, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetSpendLogs はLiteLLMの/spend/logsから期間内の利用ログを取得する
+// 管理者キー（master key）が必要
+func (c *Client) GetSpendLogs(key string, start, end time.Time) ([]SpendLog, error) {
+	query := url.Values{}
+	if key != "" {
+		query.Set("api_key", key)
+	}
+	query.Set("start_date", start.Format("2006-01-02"))
+	query.Set("end_date", end.Format("2006-01-02"))
+
+	var logs []SpendLog
+	if err := c.getJSON("/spend/logs?"+query.Encode(), &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/config"
+)
+
+func TestClient_LiteLLMEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/health":
+			w.Write([]byte(`{
+				"healthy_endpoints": [{"model": "openai/gpt-4o", "api_base": "https://api.openai.com"}],
+				"unhealthy_endpoints": [{"model": "azure/gpt-4o", "error": "timeout"}],
+				"healthy_count": 1,
+				"unhealthy_count": 1
+			}`))
+		case "/model_group/info":
+			w.Write([]byte(`{"data": [{"model_group": "gpt-4o", "providers": ["openai", "azure"], "mode": "chat"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(config.New(server.URL, "test-key", 5*time.Second))
+
+	health, err := client.GetHealth()
+	if err != nil {
+		t.Fatalf("GetHealth() error = %v", err)
+	}
+	if len(health.HealthyEndpoints) != 1 || len(health.UnhealthyEndpoints) != 1 {
+		t.Errorf("GetHealth() = %+v, want 1 healthy and 1 unhealthy endpoint", health)
+	}
+	if health.UnhealthyEndpoints[0]["error"] != "timeout" {
+		t.Errorf("unhealthy endpoint error = %v, want timeout", health.UnhealthyEndpoints[0]["error"])
+	}
+
+	groups, err := client.GetModelGroupInfo()
+	if err != nil {
+		t.Fatalf("GetModelGroupInfo() error = %v", err)
+	}
+	if len(groups.Data) != 1 || groups.Data[0].ModelGroup != "gpt-4o" || len(groups.Data[0].Providers) != 2 {
+		t.Errorf("GetModelGroupInfo() = %+v", groups)
+	}
+
+	_, err = NewClient(config.New(server.URL, "bad-key", 5*time.Second)).GetHealth()
+	if err == nil || !strings.Contains(err.Error(), "/health failed with status 401") {
+		t.Errorf("GetHealth() with bad key error = %v", err)
+	}
+}
+
+func TestClient_KeyInfoAndSpendLogs(t *testing.T) {
+	var query map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = map[string]string{}
+		for k := range r.URL.Query() {
+			query[k] = r.URL.Query().Get(k)
+		}
+		switch r.URL.Path {
+		case "/key/info":
+			w.Write([]byte(`{"key": "hashed", "info": {"key_alias": "probe-bot", "spend": 12.5, "max_budget": 50, "budget_reset_at": "2024-07-01T00:00:00Z"}}`))
+		case "/spend/logs":
+			w.Write([]byte(`[{"request_id": "r1", "startTime": "2024-06-01T10:00:00Z", "model": "gpt-4o", "spend": 0.02, "total_tokens": 1000}]`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(config.New(server.URL, "sk-admin", 5*time.Second))
+
+	info, err := client.GetKeyInfo("sk-virtual")
+	if err != nil {
+		t.Fatalf("GetKeyInfo() error = %v", err)
+	}
+	if query["key"] != "sk-virtual" {
+		t.Errorf("key query = %q, want sk-virtual", query["key"])
+	}
+	if remaining, ok := info.Info.Remaining(); !ok || remaining != 37.5 {
+		t.Errorf("Remaining() = %v, %v, want 37.5, true", remaining, ok)
+	}
+
+	if _, err := client.GetKeyInfo(""); err != nil {
+		t.Fatalf("GetKeyInfo(\"\") error = %v", err)
+	}
+	if _, ok := query["key"]; ok {
+		t.Error("key query should be omitted when looking up the calling key")
+	}
+
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	logs, err := client.GetSpendLogs("sk-virtual", start, start.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("GetSpendLogs() error = %v", err)
+	}
+	if query["api_key"] != "sk-virtual" || query["start_date"] != "2024-06-01" || query["end_date"] != "2024-06-08" {
+		t.Errorf("spend logs query = %v", query)
+	}
+	if len(logs) != 1 || logs[0].Model != "gpt-4o" || logs[0].TotalTokens != 1000 {
+		t.Errorf("GetSpendLogs() = %+v", logs)
+	}
+
+	unlimited := KeyInfo{Spend: 3}
+	if _, ok := unlimited.Remaining(); ok {
+		t.Error("Remaining() without max_budget should report no limit")
+	}
+}
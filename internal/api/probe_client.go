@@ -4,25 +4,42 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/armaniacs/llm-info/internal/readonly"
+	"github.com/armaniacs/llm-info/internal/redact"
 	"github.com/armaniacs/llm-info/pkg/config"
 )
 
+// ErrRequestTooLarge はリクエストボディのサイズ超過（413）で拒否されたことを表す
+// トークン数の検証より前にゲートウェイやプロキシが返すため、エラー本文はJSONとは限らない
+var ErrRequestTooLarge = errors.New("request body too large")
+
 // ProbeClient はモデル制約値を探索するためのクライアント
 type ProbeClient struct {
-	client  *http.Client
-	config  *config.AppConfig
+	client *http.Client
+	config *config.AppConfig
+	stats  *ConnStats
+	header atomic.Pointer[http.Header] // 最後に成功したレスポンスのヘッダー
+
+	// --endpoint autoで判定済みのモデルと、/v1/completionsを使うモデル
+	checkedModels    sync.Map
+	completionModels sync.Map
 }
 
 // NewProbeClient は新しいProbeClientを作成する
+// 同じタイムアウト設定のクライアント間で接続を共有し、試行ごとに再利用する
 func NewProbeClient(cfg *config.AppConfig) *ProbeClient {
 	return &ProbeClient{
-		client: &http.Client{
-			Timeout: cfg.Timeout,
-		},
+		client: newHTTPClient(cfg.Timeout, cfg.Timeouts, cfg.Proxy),
 		config: cfg,
+		stats:  &ConnStats{},
 	}
 }
 
@@ -31,6 +48,36 @@ func (pc *ProbeClient) GetConfig() *config.AppConfig {
 	return pc.config
 }
 
+// ConnStats はこのクライアントの接続再利用状況を返す
+func (pc *ProbeClient) ConnStats() ConnStatsSnapshot {
+	return pc.stats.Snapshot()
+}
+
+// Throttling はこのクライアントのリクエストがレート制限で待った回数と時間、429を返された回数、再送の回数と待った時間を返す
+func (pc *ProbeClient) Throttling() Throttling {
+	if pc == nil {
+		return Throttling{}
+	}
+	return pc.stats.Throttling()
+}
+
+// ResponseHeader は最後に成功したレスポンスのヘッダーを返す
+// まだ成功したレスポンスがない場合はnilを返す
+func (pc *ProbeClient) ResponseHeader() http.Header {
+	if header := pc.header.Load(); header != nil {
+		return *header
+	}
+	return nil
+}
+
+// recordHeader は成功したレスポンスのヘッダーを記録する
+func (pc *ProbeClient) recordHeader(resp *http.Response) {
+	if resp.StatusCode == http.StatusOK {
+		header := resp.Header.Clone()
+		pc.header.Store(&header)
+	}
+}
+
 // ProbeRequest はAPIリクエストの構造体
 type ProbeRequest struct {
 	Model       string `json:"model"`
@@ -43,6 +90,7 @@ type ProbeRequest struct {
 type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	Name    string `json:"name,omitempty"`
 }
 
 // ProbeResponse はAPIレスポンスの構造体
@@ -58,9 +106,10 @@ type ProbeResponse struct {
 
 // ChatChoice は選択肢の構造体
 type ChatChoice struct {
-	Index        int         `json:"index"`
-	Message      ChatMessage `json:"message"`
-	FinishReason string      `json:"finish_reason"`
+	Index        int             `json:"index"`
+	Message      ChatMessage     `json:"message"`
+	FinishReason string          `json:"finish_reason"`
+	Logprobs     json.RawMessage `json:"logprobs,omitempty"`
 }
 
 // ChatMessage はチャットメッセージの構造体
@@ -78,20 +127,77 @@ type OpenAIError struct {
 
 // UsageInfo は使用量情報の構造体
 type UsageInfo struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens        int                  `json:"prompt_tokens"`
+	CompletionTokens    int                  `json:"completion_tokens"`
+	TotalTokens         int                  `json:"total_tokens"`
+	PromptTokensDetails *PromptTokensDetails `json:"prompt_tokens_details,omitempty"`
+	// Anthropic形式のキャッシュの使用量（LiteLLMはそのまま返すことがある）
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+}
+
+// PromptTokensDetails はprompt_tokensの内訳
+type PromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// CachedTokens はキャッシュから読み込まれたプロンプトのトークン数を返す
+// OpenAI形式（prompt_tokens_details.cached_tokens）とAnthropic形式（cache_read_input_tokens）のどちらにも対応する
+func (u *UsageInfo) CachedTokens() int {
+	if u == nil {
+		return 0
+	}
+	if u.PromptTokensDetails != nil && u.PromptTokensDetails.CachedTokens > 0 {
+		return u.PromptTokensDetails.CachedTokens
+	}
+	return u.CacheReadInputTokens
+}
+
+// ReportsCaching はレスポンスがキャッシュの使用量のフィールドを含んでいたかを返す
+func (u *UsageInfo) ReportsCaching() bool {
+	return u != nil && (u.PromptTokensDetails != nil || u.CacheReadInputTokens > 0 || u.CacheCreationInputTokens > 0)
+}
+
+// send は探索リクエストを送信する
+// 探索のリクエストはすべて補完を生成するため、読み取り専用モードでは送信せずにエラーを返す
+func (pc *ProbeClient) send(req *http.Request) (*http.Response, error) {
+	if readonly.Enabled() {
+		return nil, fmt.Errorf("%w: refusing to send %s %s", readonly.ErrReadOnly, req.Method, req.URL.Path)
+	}
+	return doRequest(pc.client, req, pc.stats)
 }
 
 // ProbeModel はモデルの制約値を探索する
 func (pc *ProbeClient) ProbeModel(modelID string) (*ProbeResponse, error) {
+	return pc.ProbeMessages(modelID, []Message{{Role: "user", Content: "test"}}, 16)
+}
+
+// ProbeMessages は指定したメッセージ列でチャットリクエストを送信する
+// ロールやフィールドの互換性を調べるため、メッセージはそのまま送信する
+func (pc *ProbeClient) ProbeMessages(modelID string, messages []Message, maxTokens int) (*ProbeResponse, error) {
+	return pc.ProbeMessagesWithParams(modelID, messages, maxTokens, nil)
+}
+
+// ProbeMessagesWithParams は追加のパラメータ（top_p、seedなど）を付けてチャットリクエストを送信する
+// paramsの値はリクエストのフィールドを上書きする
+func (pc *ProbeClient) ProbeMessagesWithParams(modelID string, messages []Message, maxTokens int, params map[string]any) (*ProbeResponse, error) {
+	pc.resolveEndpoint(modelID)
+	switch pc.Endpoint(modelID) {
+	case config.ProbeEndpointCompletions:
+		return pc.probeCompletion(modelID, strings.NewReader(promptFromMessages(messages)), maxTokens, params)
+	case config.ProbeEndpointResponses:
+		return pc.probeResponsesMessages(modelID, messages, maxTokens, params)
+	}
+	return pc.probeChat(modelID, messages, maxTokens, params)
+}
+
+// probeChat は/v1/chat/completionsにリクエストを送信する
+func (pc *ProbeClient) probeChat(modelID string, messages []Message, maxTokens int, params map[string]any) (*ProbeResponse, error) {
 	// リクエストを作成
 	req := ProbeRequest{
-		Model: modelID,
-		Messages: []Message{
-			{Role: "user", Content: "test"},
-		},
-		MaxTokens:   16,
+		Model:       modelID,
+		Messages:    messages,
+		MaxTokens:   maxTokens,
 		Temperature: 0,
 	}
 
@@ -100,6 +206,11 @@ func (pc *ProbeClient) ProbeModel(modelID string) (*ProbeResponse, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
+	if len(params) > 0 {
+		if jsonBody, err = mergeRequestParams(jsonBody, params); err != nil {
+			return nil, err
+		}
+	}
 
 	// HTTPリクエストを作成（タイムアウト付き）
 	url := fmt.Sprintf("%s/v1/chat/completions", pc.config.BaseURL)
@@ -109,7 +220,7 @@ func (pc *ProbeClient) ProbeModel(modelID string) (*ProbeResponse, error) {
 	defer cancel()
 
 	httpReq, err := http.NewRequestWithContext(
-		ctx,  // タイムアウト付きContextを使用
+		pc.stats.withTrace(ctx), // タイムアウト付きContextを使用
 		"POST",
 		url,
 		bytes.NewBuffer(jsonBody),
@@ -123,11 +234,16 @@ func (pc *ProbeClient) ProbeModel(modelID string) (*ProbeResponse, error) {
 	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", pc.config.APIKey))
 
 	// リクエストを送信
-	resp, err := pc.client.Do(httpReq)
+	resp, err := pc.send(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
-	defer resp.Body.Close()
+	defer drainAndClose(resp.Body)
+	pc.stats.recordResponse(resp)
+	pc.recordHeader(resp)
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		return nil, fmt.Errorf("%w (status %d)", ErrRequestTooLarge, resp.StatusCode)
+	}
 
 	// レスポンスを読み込む
 	var probeResp ProbeResponse
@@ -138,7 +254,7 @@ func (pc *ProbeClient) ProbeModel(modelID string) (*ProbeResponse, error) {
 	// ステータスコードをチェック
 	if resp.StatusCode != http.StatusOK {
 		if probeResp.Error != nil {
-			return &probeResp, fmt.Errorf("API error (%s): %s", probeResp.Error.Type, probeResp.Error.Message)
+			return &probeResp, fmt.Errorf("API error (%s): %s", probeResp.Error.Type, redact.String(probeResp.Error.Message))
 		}
 		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
@@ -146,40 +262,71 @@ func (pc *ProbeClient) ProbeModel(modelID string) (*ProbeResponse, error) {
 	return &probeResp, nil
 }
 
+// mergeRequestParams はリクエストのJSONにパラメータを追加する
+func mergeRequestParams(body []byte, params map[string]any) ([]byte, error) {
+	fields := make(map[string]any)
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	for name, value := range params {
+		fields[name] = value
+	}
+	merged, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return merged, nil
+}
+
 // ProbeModelWithContent はカスタムコンテンツでモデルの制約値を探索する
 func (pc *ProbeClient) ProbeModelWithContent(modelID string, content string) (*ProbeResponse, error) {
-	// リクエストを作成
-	req := ProbeRequest{
-		Model: modelID,
-		Messages: []Message{
-			{Role: "user", Content: content},
-		},
-		MaxTokens:   16,
-		Temperature: 0,
+	return pc.ProbeModelWithReader(modelID, newReplayableString(content))
+}
+
+// ProbeModelWithReader はcontentから読み出した内容でモデルの制約値を探索する
+// リクエストボディは送信しながら生成するため、巨大なプロンプトでもメモリに展開しない
+func (pc *ProbeClient) ProbeModelWithReader(modelID string, content io.Reader) (*ProbeResponse, error) {
+	return pc.ProbeModelWithMaxTokens(modelID, content, 16)
+}
+
+// ProbeModelWithMaxTokens はmax_tokensを指定してcontentから読み出した内容でモデルの制約値を探索する
+// 入力上限だけを測る場合は出力の予約分が影響しないよう小さなmax_tokensを指定する
+func (pc *ProbeClient) ProbeModelWithMaxTokens(modelID string, content io.Reader, maxTokens int) (*ProbeResponse, error) {
+	pc.resolveEndpoint(modelID)
+	switch pc.Endpoint(modelID) {
+	case config.ProbeEndpointCompletions:
+		return pc.probeCompletion(modelID, content, maxTokens, nil)
+	case config.ProbeEndpointResponses:
+		return pc.probeResponses(modelID, content, maxTokens)
 	}
 
-	// JSONにエンコード
-	jsonBody, err := json.Marshal(req)
+	body, err := newChatRequestBody(modelID, maxTokens, 0, content)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, err
 	}
 
 	// HTTPリクエストを作成
-	httpReq, err := http.NewRequest("POST", pc.config.BaseURL+"/v1/chat/completions", bytes.NewBuffer(jsonBody))
+	httpReq, err := http.NewRequestWithContext(pc.stats.withTrace(context.Background()), "POST", pc.config.BaseURL+"/v1/chat/completions", body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	setReplayableBody(httpReq, body)
 
 	// ヘッダーを設定
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", pc.config.APIKey))
 
 	// リクエストを送信
-	resp, err := pc.client.Do(httpReq)
+	resp, err := pc.send(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
-	defer resp.Body.Close()
+	defer drainAndClose(resp.Body)
+	pc.stats.recordResponse(resp)
+	pc.recordHeader(resp)
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		return nil, fmt.Errorf("%w (status %d)", ErrRequestTooLarge, resp.StatusCode)
+	}
 
 	// レスポンスを読み込む
 	var probeResp ProbeResponse
@@ -190,10 +337,10 @@ func (pc *ProbeClient) ProbeModelWithContent(modelID string, content string) (*P
 	// ステータスコードをチェック
 	if resp.StatusCode != http.StatusOK {
 		if probeResp.Error != nil {
-			return &probeResp, fmt.Errorf("API error (%s): %s", probeResp.Error.Type, probeResp.Error.Message)
+			return &probeResp, fmt.Errorf("API error (%s): %s", probeResp.Error.Type, redact.String(probeResp.Error.Message))
 		}
 		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	return &probeResp, nil
-}
\ No newline at end of file
+}
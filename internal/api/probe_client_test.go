@@ -2,12 +2,14 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/armaniacs/llm-info/internal/readonly"
 	"github.com/armaniacs/llm-info/pkg/config"
 )
 
@@ -78,4 +80,84 @@ func TestProbeClient_NormalRequestStillWorks(t *testing.T) {
 	if result.Usage.PromptTokens != 10 {
 		t.Errorf("Expected PromptTokens=10, got %d", result.Usage.PromptTokens)
 	}
-}
\ No newline at end of file
+}
+func TestProbeClient_ResponseHeader(t *testing.T) {
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if fail {
+			w.Header().Set("Server", "gateway")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ProbeResponse{Error: &OpenAIError{Message: "bad", Type: "invalid_request_error"}})
+			return
+		}
+		w.Header().Set("Openai-Processing-Ms", "12")
+		json.NewEncoder(w).Encode(ProbeResponse{Choices: []ChatChoice{{FinishReason: "stop"}}})
+	}))
+	defer server.Close()
+
+	client := NewProbeClient(&config.AppConfig{BaseURL: server.URL, APIKey: "test", Timeout: 10 * time.Second})
+	if client.ResponseHeader() != nil {
+		t.Fatal("ResponseHeader() should be nil before any response")
+	}
+
+	if _, err := client.ProbeModel("test-model"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := client.ResponseHeader().Get("Openai-Processing-Ms"); got != "12" {
+		t.Errorf("Openai-Processing-Ms = %q, want 12", got)
+	}
+
+	// 失敗したレスポンスのヘッダーでは上書きしない
+	fail = true
+	if _, err := client.ProbeModel("test-model"); err == nil {
+		t.Fatal("Expected API error, got nil")
+	}
+	if got := client.ResponseHeader().Get("Server"); got != "" {
+		t.Errorf("Server = %q, want header of the last successful response", got)
+	}
+}
+
+func TestProbeClient_RequestTooLarge(t *testing.T) {
+	// プロキシはHTMLのエラーページを返すことがある
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		w.Write([]byte("<html><body>413 Request Entity Too Large</body></html>"))
+	}))
+	defer server.Close()
+
+	client := NewProbeClient(&config.AppConfig{BaseURL: server.URL, APIKey: "test", Timeout: 10 * time.Second})
+
+	if _, err := client.ProbeModelWithReader("test-model", strings.NewReader("large prompt")); !errors.Is(err, ErrRequestTooLarge) {
+		t.Errorf("ProbeModelWithReader() error = %v, want ErrRequestTooLarge", err)
+	}
+	if _, err := client.ProbeModel("test-model"); !errors.Is(err, ErrRequestTooLarge) {
+		t.Errorf("ProbeModel() error = %v, want ErrRequestTooLarge", err)
+	}
+}
+
+func TestProbeClient_ReadOnly(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	readonly.Enable()
+	defer readonly.Reset()
+
+	for _, endpoint := range []string{config.ProbeEndpointChat, config.ProbeEndpointCompletions, config.ProbeEndpointResponses} {
+		client := NewProbeClient(&config.AppConfig{BaseURL: server.URL, APIKey: "test", Timeout: 10 * time.Second, ProbeEndpoint: endpoint})
+		if _, err := client.ProbeModel("test-model"); !errors.Is(err, readonly.ErrReadOnly) {
+			t.Errorf("ProbeModel() via %s error = %v, want ErrReadOnly", endpoint, err)
+		}
+		if _, err := client.ProbeModelWithReader("test-model", strings.NewReader("prompt")); !errors.Is(err, readonly.ErrReadOnly) {
+			t.Errorf("ProbeModelWithReader() via %s error = %v, want ErrReadOnly", endpoint, err)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("gateway received %d requests in read-only mode, want 0", requests)
+	}
+}
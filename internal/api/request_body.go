@@ -0,0 +1,196 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// escapeChunkSize はコンテンツを読み出してJSONエスケープする単位
+const escapeChunkSize = 32 * 1024
+
+// escapeBuffers は試行間で再利用するエスケープ用バッファ
+// 巨大なプロンプトでも1リクエストあたりのメモリ使用量はこのバッファ分に収まる
+var escapeBuffers = sync.Pool{
+	New: func() any {
+		return &escapeBuffer{
+			in:  make([]byte, escapeChunkSize),
+			out: make([]byte, 0, escapeChunkSize*2),
+		}
+	},
+}
+
+type escapeBuffer struct {
+	in  []byte
+	out []byte
+}
+
+// newChatRequestBody はコンテンツを埋め込んだチャットリクエストのJSONをストリームで返す
+// コンテンツは送信しながら読み出すため、リクエスト全体を文字列として組み立てない
+func newChatRequestBody(modelID string, maxTokens int, temperature float64, content io.Reader) (io.Reader, error) {
+	model, err := json.Marshal(modelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	prefix := `{"model":` + string(model) + `,"messages":[{"role":"user","content":"`
+	suffix := fmt.Sprintf(`"}],"max_tokens":%d,"temperature":%s}`, maxTokens, formatTemperature(temperature))
+
+	return streamRequestBody(prefix, content, suffix), nil
+}
+
+// newCompletionRequestBody はコンテンツをpromptに埋め込んだ/v1/completionsのリクエストのJSONをストリームで返す
+func newCompletionRequestBody(modelID string, maxTokens int, temperature float64, content io.Reader) (io.Reader, error) {
+	model, err := json.Marshal(modelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	prefix := `{"model":` + string(model) + `,"prompt":"`
+	suffix := fmt.Sprintf(`","max_tokens":%d,"temperature":%s}`, maxTokens, formatTemperature(temperature))
+
+	return streamRequestBody(prefix, content, suffix), nil
+}
+
+// newResponsesRequestBody はコンテンツをinputに埋め込んだ/v1/responsesのリクエストのJSONをストリームで返す
+func newResponsesRequestBody(modelID string, maxOutputTokens int, temperature float64, content io.Reader) (io.Reader, error) {
+	model, err := json.Marshal(modelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	prefix := `{"model":` + string(model) + `,"input":"`
+	suffix := fmt.Sprintf(`","max_output_tokens":%d,"temperature":%s}`, maxOutputTokens, formatTemperature(temperature))
+
+	return streamRequestBody(prefix, content, suffix), nil
+}
+
+// streamRequestBody はprefixとsuffixの間にcontentをJSON文字列としてエスケープしながら埋め込む
+func streamRequestBody(prefix string, content io.Reader, suffix string) io.Reader {
+	return &streamBody{
+		Reader: io.MultiReader(
+			strings.NewReader(prefix),
+			&jsonEscapeReader{src: content},
+			strings.NewReader(suffix),
+		),
+		prefix:  prefix,
+		content: content,
+		suffix:  suffix,
+	}
+}
+
+// Replayer は先頭から読み直せるコンテンツ
+// 429を返されたリクエストを再送するときに、送信済みのボディと同じ内容を作り直すために使う
+type Replayer interface {
+	Replay() io.Reader
+}
+
+// streamBody は送信しながら生成するリクエストボディ
+type streamBody struct {
+	io.Reader
+	prefix  string
+	content io.Reader
+	suffix  string
+}
+
+// setReplayableBody はbodyを作り直せる場合にreq.GetBodyを設定する（429の再送に使う）
+// bytes.Readerなどはhttp.NewRequestが設定済みで、コンテンツがReplayerでないストリームは作り直せないため何もしない
+func setReplayableBody(req *http.Request, body io.Reader) {
+	stream, ok := body.(*streamBody)
+	if !ok {
+		return
+	}
+	replayer, ok := stream.content.(Replayer)
+	if !ok {
+		return
+	}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(streamRequestBody(stream.prefix, replayer.Replay(), stream.suffix)), nil
+	}
+}
+
+// replayableString は先頭から読み直せる文字列のコンテンツ
+type replayableString struct {
+	*strings.Reader
+	s string
+}
+
+func newReplayableString(s string) *replayableString {
+	return &replayableString{Reader: strings.NewReader(s), s: s}
+}
+
+// Replay はReplayerを実装する
+func (r *replayableString) Replay() io.Reader {
+	return newReplayableString(r.s)
+}
+
+// formatTemperature はtemperatureをJSONの数値として書式化する
+func formatTemperature(temperature float64) string {
+	data, _ := json.Marshal(temperature)
+	return string(data)
+}
+
+// jsonEscapeReader はsrcの内容をJSON文字列としてエスケープしながら読み出す
+// UTF-8のマルチバイト文字は0x80以上のバイトだけで構成されるため、
+// チャンクの境界で分割されてもそのまま出力してよい
+type jsonEscapeReader struct {
+	src     io.Reader
+	buf     *escapeBuffer
+	pending []byte
+	err     error
+}
+
+// Read はio.Readerを実装する
+func (r *jsonEscapeReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			r.release()
+			return 0, r.err
+		}
+		if r.buf == nil {
+			r.buf = escapeBuffers.Get().(*escapeBuffer)
+		}
+
+		n, err := r.src.Read(r.buf.in)
+		r.buf.out = appendJSONEscaped(r.buf.out[:0], r.buf.in[:n])
+		r.pending = r.buf.out
+		r.err = err
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// release はバッファをプールに戻す
+func (r *jsonEscapeReader) release() {
+	if r.buf != nil {
+		escapeBuffers.Put(r.buf)
+		r.buf = nil
+	}
+}
+
+// appendJSONEscaped はsrcをJSON文字列用にエスケープしてdstに追加する
+func appendJSONEscaped(dst, src []byte) []byte {
+	const hex = "0123456789abcdef"
+	for _, c := range src {
+		switch {
+		case c == '"' || c == '\\':
+			dst = append(dst, '\\', c)
+		case c == '\n':
+			dst = append(dst, '\\', 'n')
+		case c == '\r':
+			dst = append(dst, '\\', 'r')
+		case c == '\t':
+			dst = append(dst, '\\', 't')
+		case c < 0x20:
+			dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestNewChatRequestBody(t *testing.T) {
+	content := "以下の内容を記憶してください。\n\n「お可愛いものですね。 definitiveiyar rag a\"\t\\ \x01end"
+
+	// 1バイトずつ読み出して、マルチバイト文字がチャンク境界で分割されても壊れないことを確認
+	body, err := newChatRequestBody("gpt-4o", 16, 0, iotest.OneByteReader(strings.NewReader(content)))
+	if err != nil {
+		t.Fatalf("newChatRequestBody() error = %v", err)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	var req ProbeRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatalf("request body is not valid JSON: %v\n%s", err, data)
+	}
+	if req.Model != "gpt-4o" || req.MaxTokens != 16 || req.Temperature != 0 {
+		t.Errorf("request = %+v", req)
+	}
+	if len(req.Messages) != 1 || req.Messages[0].Role != "user" || req.Messages[0].Content != content {
+		t.Errorf("Messages = %+v, want content %q", req.Messages, content)
+	}
+}
+
+func BenchmarkNewChatRequestBody(b *testing.B) {
+	content := strings.Repeat("吾輩は猫である。名前はまだ無い。 ", 30000) // 約1.5MB
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(content)))
+	for i := 0; i < b.N; i++ {
+		body, err := newChatRequestBody("gpt-4o", 16, 0, strings.NewReader(content))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Copy(io.Discard, body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
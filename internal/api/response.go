@@ -1,14 +1,56 @@
 package api
 
+import "encoding/json"
+
 // ModelInfoResponse はAPIレスポンスの構造体です
 type ModelInfoResponse struct {
-	Models []ModelInfo `json:"models"`
+	Models []ModelInfo   `json:"models"`
+	Raw    []RawResponse `json:"-"` // 一覧の作成に使った受信したままのレスポンス
+}
+
+// RawResponse は取得元のエンドポイントと受信したままのレスポンスボディ
+type RawResponse struct {
+	Endpoint string
+	Body     []byte
 }
 
 // ModelInfo は個別のモデル情報です
 type ModelInfo struct {
-	ID        string  `json:"id"`
-	MaxTokens int     `json:"max_tokens"`
-	Mode      string  `json:"mode"`
-	InputCost float64 `json:"input_cost"`
+	ID        string                 `json:"id"`
+	MaxTokens int                    `json:"max_tokens"`
+	Mode      string                 `json:"mode"`
+	InputCost float64                `json:"input_cost"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"` // APIが返した生のフィールド
+	Issues    []FieldIssue           `json:"issues,omitempty"`   // 欠落・不正だったフィールド
+}
+
+// UnmarshalJSON はモデル情報を読み込み、APIが返したすべてのフィールドをMetadataに保持する
+// 型の誤ったフィールドがあってもモデル全体は読み込み、欠落・不正だったフィールドをIssuesに記録する
+// キャッシュなどで既にmetadataを持つ場合はそれをそのまま使う
+func (m *ModelInfo) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if _, cached := raw["metadata"].(map[string]interface{}); cached {
+		type plain ModelInfo
+		var p plain
+		if err := json.Unmarshal(data, &p); err != nil {
+			return err
+		}
+		*m = ModelInfo(p)
+		return nil
+	}
+
+	r := &fieldReader{raw: raw}
+	*m = ModelInfo{
+		ID:        r.string("id", true),
+		MaxTokens: r.int("max_tokens", true),
+		Mode:      r.string("mode", true),
+		InputCost: r.float("input_cost", true),
+		Metadata:  raw,
+		Issues:    r.issues,
+	}
+	return nil
 }
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestModelInfo_UnmarshalJSON(t *testing.T) {
+	data := `{"id":"gpt-4o","max_tokens":4096,"mode":"chat","input_cost":0.0025,"supports_vision":true,"litellm_provider":"openai"}`
+
+	var info ModelInfo
+	if err := json.Unmarshal([]byte(data), &info); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if info.ID != "gpt-4o" || info.MaxTokens != 4096 {
+		t.Errorf("ModelInfo = %+v", info)
+	}
+	if info.Metadata["supports_vision"] != true {
+		t.Errorf("Metadata[supports_vision] = %v, want true", info.Metadata["supports_vision"])
+	}
+	if info.Metadata["litellm_provider"] != "openai" {
+		t.Errorf("Metadata[litellm_provider] = %v, want openai", info.Metadata["litellm_provider"])
+	}
+}
+
+func TestModelInfo_MetadataRoundTrip(t *testing.T) {
+	original := ModelInfo{
+		ID:       "gpt-4o",
+		Metadata: map[string]interface{}{"supports_vision": true},
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	// キャッシュから読み戻したときにmetadataが二重にネストしないこと
+	var restored ModelInfo
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(restored.Metadata) != 1 || restored.Metadata["supports_vision"] != true {
+		t.Errorf("Metadata = %v, want %v", restored.Metadata, original.Metadata)
+	}
+}
+
+func TestModelInfo_UnmarshalJSONIssues(t *testing.T) {
+	data := `{"models":[
+		{"id":"gpt-4o","max_tokens":"128000","mode":"chat","input_cost":0.0025},
+		{"id":"no-limit","max_tokens":null,"mode":"chat","input_cost":-1},
+		{"id":"ok","max_tokens":8192,"mode":"chat","input_cost":0}
+	]}`
+
+	var response ModelInfoResponse
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		t.Fatalf("Unmarshal() error = %v, want lenient parsing", err)
+	}
+
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"gpt-4o", `max_tokens: malformed (expected non-negative integer, got string "128000")`},
+		{"no-limit", "max_tokens: missing; input_cost: malformed (expected non-negative number, got number -1)"},
+		{"ok", ""},
+	}
+	for i, tt := range tests {
+		info := response.Models[i]
+		if info.ID != tt.id {
+			t.Fatalf("Models[%d].ID = %s, want %s", i, info.ID, tt.id)
+		}
+		if got := FormatIssues(info.Issues); got != tt.want {
+			t.Errorf("%s issues = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+	if response.Models[0].Mode != "chat" || response.Models[0].Metadata["max_tokens"] != "128000" {
+		t.Errorf("valid fields and metadata should be kept: %+v", response.Models[0])
+	}
+
+	// キャッシュから読み戻しても問題の記録は残る
+	cached, err := json.Marshal(response.Models[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored ModelInfo
+	if err := json.Unmarshal(cached, &restored); err != nil {
+		t.Fatal(err)
+	}
+	if len(restored.Issues) != 1 || restored.Issues[0].Field != "max_tokens" {
+		t.Errorf("restored issues = %+v", restored.Issues)
+	}
+}
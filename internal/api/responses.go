@@ -0,0 +1,170 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+// responsesMinOutputTokens はResponses APIが受け付けるmax_output_tokensの最小値
+// 入力上限の探索ではmax_tokens=1を指定するため、この値に切り上げて送信する
+const responsesMinOutputTokens = 16
+
+// responsesRequest は/v1/responsesのリクエスト
+type responsesRequest struct {
+	Model           string           `json:"model"`
+	Input           []responsesInput `json:"input"`
+	MaxOutputTokens int              `json:"max_output_tokens"`
+	Temperature     float64          `json:"temperature"`
+}
+
+// responsesInput は/v1/responsesの入力メッセージ
+type responsesInput struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// responsesResponse は/v1/responsesのレスポンス
+type responsesResponse struct {
+	ID                string               `json:"id"`
+	Object            string               `json:"object"`
+	CreatedAt         int64                `json:"created_at"`
+	Model             string               `json:"model"`
+	Status            string               `json:"status"` // completed, incomplete, failed など
+	IncompleteDetails *responsesIncomplete `json:"incomplete_details"`
+	Output            []responsesOutput    `json:"output"`
+	Usage             *responsesUsage      `json:"usage"`
+	Error             *OpenAIError         `json:"error"`
+}
+
+// responsesIncomplete は生成が打ち切られた理由
+type responsesIncomplete struct {
+	Reason string `json:"reason"` // max_output_tokens, content_filter
+}
+
+// responsesOutput は/v1/responsesの出力項目
+type responsesOutput struct {
+	Type    string             `json:"type"` // message, reasoning など
+	Role    string             `json:"role"`
+	Content []responsesContent `json:"content"`
+}
+
+// responsesContent は出力メッセージの内容
+type responsesContent struct {
+	Type string `json:"type"` // output_text, refusal
+	Text string `json:"text"`
+}
+
+// responsesUsage は/v1/responsesの使用量（フィールド名がチャット形式と異なる）
+type responsesUsage struct {
+	InputTokens        int                  `json:"input_tokens"`
+	OutputTokens       int                  `json:"output_tokens"`
+	TotalTokens        int                  `json:"total_tokens"`
+	InputTokensDetails *PromptTokensDetails `json:"input_tokens_details,omitempty"`
+}
+
+// toProbeResponse はチャット形式のレスポンスに変換する
+// 結果を共通のスキーマで比較できるよう、max_output_tokensでの打ち切りはfinish_reason=lengthとして扱う
+func (r *responsesResponse) toProbeResponse() *ProbeResponse {
+	resp := &ProbeResponse{
+		ID:      r.ID,
+		Object:  r.Object,
+		Created: r.CreatedAt,
+		Model:   r.Model,
+		Error:   r.Error,
+	}
+	if r.Usage != nil {
+		resp.Usage = &UsageInfo{
+			PromptTokens:        r.Usage.InputTokens,
+			CompletionTokens:    r.Usage.OutputTokens,
+			TotalTokens:         r.Usage.TotalTokens,
+			PromptTokensDetails: r.Usage.InputTokensDetails,
+		}
+	}
+	// Responses APIのエラーはtypeを持たずcodeだけを返すことがある
+	if resp.Error != nil && resp.Error.Type == "" {
+		resp.Error.Type = resp.Error.Code
+	}
+	if r.Error != nil || r.Status == "failed" {
+		return resp
+	}
+
+	var text strings.Builder
+	for _, output := range r.Output {
+		if output.Type != "message" {
+			continue
+		}
+		for _, content := range output.Content {
+			text.WriteString(content.Text)
+		}
+	}
+	resp.Choices = []ChatChoice{{
+		Message:      ChatMessage{Role: "assistant", Content: text.String()},
+		FinishReason: r.finishReason(),
+	}}
+	return resp
+}
+
+// finishReason はstatusとincomplete_detailsをチャット形式のfinish_reasonに変換する
+func (r *responsesResponse) finishReason() string {
+	if r.Status != "incomplete" {
+		return "stop"
+	}
+	if r.IncompleteDetails == nil || r.IncompleteDetails.Reason == "max_output_tokens" {
+		return "length"
+	}
+	return r.IncompleteDetails.Reason
+}
+
+// probeResponses はcontentから読み出した内容を/v1/responsesに送信し、チャット形式のレスポンスに変換して返す
+func (pc *ProbeClient) probeResponses(modelID string, content io.Reader, maxTokens int) (*ProbeResponse, error) {
+	body, err := newResponsesRequestBody(modelID, max(maxTokens, responsesMinOutputTokens), 0, content)
+	if err != nil {
+		return nil, err
+	}
+	return pc.sendResponses(body)
+}
+
+// probeResponsesMessages は指定したメッセージ列を/v1/responsesに送信する
+func (pc *ProbeClient) probeResponsesMessages(modelID string, messages []Message, maxTokens int, params map[string]any) (*ProbeResponse, error) {
+	req := responsesRequest{
+		Model:           modelID,
+		MaxOutputTokens: max(maxTokens, responsesMinOutputTokens),
+	}
+	for _, m := range messages {
+		req.Input = append(req.Input, responsesInput{Role: m.Role, Content: m.Content})
+	}
+
+	jsonBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	if len(params) > 0 {
+		if jsonBody, err = mergeRequestParams(jsonBody, params); err != nil {
+			return nil, err
+		}
+	}
+	return pc.sendResponses(bytes.NewReader(jsonBody))
+}
+
+// sendResponses は/v1/responsesにリクエストを送信する
+// ステータスコードが200でも生成に失敗した場合（status=failed）はエラーを返す
+func (pc *ProbeClient) sendResponses(body io.Reader) (*ProbeResponse, error) {
+	var responsesResp responsesResponse
+	status, err := pc.postProbe("/v1/responses", body, &responsesResp)
+	if err != nil {
+		return nil, err
+	}
+	probeResp, err := checkProbeStatus(status, responsesResp.toProbeResponse())
+	if err != nil {
+		return probeResp, err
+	}
+	if responsesResp.Status == "failed" && probeResp.Error != nil {
+		return probeResp, fmt.Errorf("API error (%s): %s", probeResp.Error.Type, redact.String(probeResp.Error.Message))
+	}
+	return probeResp, nil
+}
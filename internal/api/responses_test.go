@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// responsesServer は/v1/responsesだけを提供するゲートウェイを模したテストサーバー
+// 入力の文字数を入力トークン数とし、max_output_tokensが100未満なら打ち切りとして返す
+func responsesServer(t *testing.T, requests *[]map[string]any) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/responses" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			return
+		}
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			return
+		}
+		*requests = append(*requests, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		var input string
+		switch v := req["input"].(type) {
+		case string:
+			input = v
+		case []any:
+			for _, item := range v {
+				input += item.(map[string]any)["content"].(string)
+			}
+		}
+		if strings.Contains(input, "fail") {
+			json.NewEncoder(w).Encode(responsesResponse{
+				Status: "failed",
+				Error:  &OpenAIError{Code: "server_error", Message: "The model failed to generate a response"},
+			})
+			return
+		}
+		if len(input) > 1000 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(responsesResponse{Error: &OpenAIError{
+				Code:    "context_length_exceeded",
+				Message: "Your input exceeds the context window of this model.",
+			}})
+			return
+		}
+
+		maxOutput := int(req["max_output_tokens"].(float64))
+		resp := responsesResponse{
+			Model:  req["model"].(string),
+			Status: "completed",
+			Output: []responsesOutput{
+				{Type: "reasoning"},
+				{Type: "message", Role: "assistant", Content: []responsesContent{{Type: "output_text", Text: "ok"}}},
+			},
+			Usage: &responsesUsage{InputTokens: len(input), OutputTokens: maxOutput, TotalTokens: len(input) + maxOutput},
+		}
+		if maxOutput < 100 {
+			resp.Status = "incomplete"
+			resp.IncompleteDetails = &responsesIncomplete{Reason: "max_output_tokens"}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProbeClient_ResponsesEndpoint(t *testing.T) {
+	var requests []map[string]any
+	client := newEndpointTestClient(responsesServer(t, &requests), config.ProbeEndpointResponses)
+
+	resp, err := client.ProbeModelWithMaxTokens("gpt-4o-mini", strings.NewReader("hello"), 1)
+	if err != nil {
+		t.Fatalf("ProbeModelWithMaxTokens() error = %v", err)
+	}
+	// usageとfinish_reasonはチャット形式と同じフィールドで扱えるようにする
+	if resp.Usage == nil || resp.Usage.PromptTokens != 5 || resp.Usage.CompletionTokens != responsesMinOutputTokens {
+		t.Errorf("Usage = %+v", resp.Usage)
+	}
+	if len(resp.Choices) != 1 || resp.Choices[0].Message.Content != "ok" || resp.Choices[0].FinishReason != "length" {
+		t.Errorf("Choices = %+v", resp.Choices)
+	}
+	// max_output_tokensは最小値に切り上げる
+	if got := requests[0]["max_output_tokens"]; got != float64(responsesMinOutputTokens) {
+		t.Errorf("max_output_tokens = %v, want %d", got, responsesMinOutputTokens)
+	}
+	if _, ok := requests[0]["max_tokens"]; ok {
+		t.Error("request should not contain max_tokens")
+	}
+
+	resp, err = client.ProbeMessagesWithParams("gpt-4o-mini", []Message{{Role: "system", Content: "a"}, {Role: "user", Content: "b"}}, 200, map[string]any{"seed": 1})
+	if err != nil {
+		t.Fatalf("ProbeMessagesWithParams() error = %v", err)
+	}
+	if resp.Choices[0].FinishReason != "stop" {
+		t.Errorf("FinishReason = %q, want stop", resp.Choices[0].FinishReason)
+	}
+	if input, ok := requests[1]["input"].([]any); !ok || len(input) != 2 {
+		t.Errorf("input = %v, want 2 messages", requests[1]["input"])
+	}
+	if requests[1]["seed"] != float64(1) {
+		t.Errorf("seed = %v, want 1", requests[1]["seed"])
+	}
+	if got := client.EndpointPath("gpt-4o-mini"); got != "/v1/responses" {
+		t.Errorf("EndpointPath() = %q", got)
+	}
+}
+
+func TestProbeClient_ResponsesErrors(t *testing.T) {
+	var requests []map[string]any
+	client := newEndpointTestClient(responsesServer(t, &requests), config.ProbeEndpointResponses)
+
+	// codeだけのエラーはtypeとして扱う
+	resp, err := client.ProbeModelWithMaxTokens("gpt-4o-mini", strings.NewReader(strings.Repeat("a", 2000)), 1)
+	if err == nil {
+		t.Fatal("ProbeModelWithMaxTokens() error = nil, want context length error")
+	}
+	if resp == nil || resp.Error == nil || resp.Error.Type != "context_length_exceeded" {
+		t.Errorf("Error = %+v", resp)
+	}
+
+	// ステータスコードが200でもstatus=failedはエラーとする
+	resp, err = client.ProbeModelWithMaxTokens("gpt-4o-mini", strings.NewReader("fail"), 1)
+	if err == nil || !strings.Contains(err.Error(), "server_error") {
+		t.Errorf("ProbeModelWithMaxTokens() error = %v, want server_error", err)
+	}
+	if resp != nil && len(resp.Choices) != 0 {
+		t.Errorf("Choices = %+v, want none", resp.Choices)
+	}
+}
@@ -5,17 +5,42 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/armaniacs/llm-info/internal/redact"
 )
 
 // StandardResponse はOpenAI標準APIのレスポンス形式を表す
 type StandardResponse struct {
-	Object string `json:"object"`
-	Data   []struct {
-		ID      string `json:"id"`
-		Object  string `json:"object"`
-		Created int64  `json:"created"`
-		OwnedBy string `json:"owned_by"`
-	} `json:"data"`
+	Object string          `json:"object"`
+	Data   []StandardModel `json:"data"`
+	Raw    []byte          `json:"-"` // 受信したままのレスポンスボディ
+}
+
+// StandardModel はOpenAI標準APIのモデル
+type StandardModel struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"`
+	Created int64        `json:"created"`
+	OwnedBy string       `json:"owned_by"`
+	Issues  []FieldIssue `json:"-"` // 欠落・不正だったフィールド
+}
+
+// UnmarshalJSON は型の誤ったフィールドがあってもモデルを読み込み、問題をIssuesに記録する
+// idは必須、それ以外は省略可能なフィールドとして検査する
+func (m *StandardModel) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r := &fieldReader{raw: raw}
+	*m = StandardModel{
+		ID:      r.string("id", true),
+		Object:  r.string("object", false),
+		Created: int64(r.int("created", false)),
+		OwnedBy: r.string("owned_by", false),
+	}
+	m.Issues = r.issues
+	return nil
 }
 
 // FetchStandardModels はOpenAI標準エンドポイントからモデル情報を取得する
@@ -32,11 +57,11 @@ func (c *Client) FetchStandardModels() (*StandardResponse, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.client.Do(req)
+	resp, err := doRequest(c.client, req, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
-	defer resp.Body.Close()
+	defer drainAndClose(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
 		// エラーレスポンスのボディを読み取って詳細なエラーメッセージを取得
@@ -48,13 +73,19 @@ func (c *Client) FetchStandardModels() (*StandardResponse, error) {
 			errorMsg = getDefaultStatusMessage(resp.StatusCode)
 		}
 
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, errorMsg)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, redact.String(errorMsg))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	var result StandardResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	result.Raw = body
 
 	return &result, nil
 }
@@ -68,7 +99,13 @@ func (c *Client) convertStandardResponse(resp *StandardResponse) *ModelInfoRespo
 			MaxTokens: 0,      // 標準APIでは提供されない
 			Mode:      "chat", // デフォルト値
 			InputCost: 0,      // 標準APIでは提供されない
+			Metadata: map[string]interface{}{
+				"object":   data.Object,
+				"created":  data.Created,
+				"owned_by": data.OwnedBy,
+			},
+			Issues: data.Issues,
 		})
 	}
-	return &ModelInfoResponse{Models: models}
+	return &ModelInfoResponse{Models: models, Raw: []RawResponse{{Endpoint: "/v1/models", Body: resp.Raw}}}
 }
@@ -14,12 +14,7 @@ func TestFetchStandardModels_Success(t *testing.T) {
 	// テスト用のモックサーバーを作成
 	mockResponse := StandardResponse{
 		Object: "list",
-		Data: []struct {
-			ID      string `json:"id"`
-			Object  string `json:"object"`
-			Created int64  `json:"created"`
-			OwnedBy string `json:"owned_by"`
-		}{
+		Data: []StandardModel{
 			{
 				ID:      "gpt-4",
 				Object:  "model",
@@ -121,12 +116,7 @@ func TestConvertStandardResponse(t *testing.T) {
 	// テスト用レスポンスを作成
 	standardResp := &StandardResponse{
 		Object: "list",
-		Data: []struct {
-			ID      string `json:"id"`
-			Object  string `json:"object"`
-			Created int64  `json:"created"`
-			OwnedBy string `json:"owned_by"`
-		}{
+		Data: []StandardModel{
 			{
 				ID:      "gpt-4",
 				Object:  "model",
@@ -176,3 +166,37 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestStandardModel_UnmarshalJSONIssues(t *testing.T) {
+	data := `{"object":"list","data":[
+		{"id":"gpt-4","object":"model","created":"2024-01-01","owned_by":"openai"},
+		{"object":"model"},
+		{"id":"ok"}
+	]}`
+
+	var resp StandardResponse
+	if err := json.Unmarshal([]byte(data), &resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v, want lenient parsing", err)
+	}
+	if len(resp.Data) != 3 {
+		t.Fatalf("got %d models, want 3", len(resp.Data))
+	}
+	if resp.Data[0].ID != "gpt-4" || resp.Data[0].OwnedBy != "openai" {
+		t.Errorf("Data[0] = %+v", resp.Data[0])
+	}
+	if got := FormatIssues(resp.Data[0].Issues); got != `created: malformed (expected non-negative integer, got string "2024-01-01")` {
+		t.Errorf("Data[0] issues = %q", got)
+	}
+	if got := FormatIssues(resp.Data[1].Issues); got != "id: missing" {
+		t.Errorf("Data[1] issues = %q", got)
+	}
+	// 省略可能なフィールドがなくても問題にしない
+	if len(resp.Data[2].Issues) != 0 {
+		t.Errorf("Data[2] issues = %+v", resp.Data[2].Issues)
+	}
+
+	converted := (&Client{}).convertStandardResponse(&resp)
+	if len(converted.Models[0].Issues) != 1 {
+		t.Errorf("converted issues = %+v", converted.Models[0].Issues)
+	}
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/ratelimit"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// maxIdleConnsPerHost は1つのゲートウェイに対して保持するアイドル接続数
+// 探索では同じホストへ連続してリクエストするため、標準の2より多く保持する
+const maxIdleConnsPerHost = 16
+
+// transportKey は共有するTransportを区別する接続設定
+type transportKey struct {
+	timeouts config.Timeouts
+	proxy    string
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = make(map[transportKey]*http.Transport)
+)
+
+// newHTTPClient はタイムアウトとプロキシの設定を反映したHTTPクライアントを作成する
+// totalはリクエスト全体、timeoutsは接続段階ごとのタイムアウト（0は標準値）
+// proxyは接続に使うプロキシ（空は環境変数のHTTP_PROXYなど、directは使わない）
+// 同じ接続設定のクライアントはTransportを共有し、接続を再利用する
+func newHTTPClient(total time.Duration, timeouts config.Timeouts, proxy string) *http.Client {
+	return &http.Client{
+		Timeout:   total,
+		Transport: sharedTransport(timeouts, proxy),
+	}
+}
+
+// doRequest はゲートウェイの同時リクエスト数とレートの上限（max_concurrency、rate_limit）に
+// 空きができるまで待ってからリクエストを送る。待ち時間もtimeoutに含まれる
+// reqのContextに期限がなければclientのタイムアウトを期限にしてから待つ（http.Client.Timeoutは送信を始めてから数えるため）
+// 同時リクエスト数の枠はレスポンスのボディを閉じるまで保持する（ストリーミングの受信中も数える）
+// ゲートウェイが429を返した場合はmax_retriesの回数まで、ratelimit.Backoffの間待ってから再送する
+// 再送まで待つ時間もtimeoutに含まれ、期限までに再送できない場合やボディを作り直せない（req.GetBodyがない）場合は429をそのまま返す
+// statsがnilでなければ待った時間と再送を記録する
+func doRequest(client *http.Client, req *http.Request, stats *ConnStats) (*http.Response, error) {
+	cancel := context.CancelFunc(func() {})
+	if _, ok := req.Context().Deadline(); !ok && client.Timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), client.Timeout)
+		req = req.WithContext(ctx)
+	}
+
+	retries := ratelimit.Retries(req.URL)
+	for attempt := 0; ; attempt++ {
+		resp, err := sendOnce(client, req, stats)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests || attempt >= retries || req.GetBody == nil {
+			resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: cancel}
+			return resp, nil
+		}
+		backoff := ratelimit.Backoff(attempt, resp.Header.Get("Retry-After"))
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < backoff {
+			resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: cancel}
+			return resp, nil
+		}
+
+		// 再送する429は呼び出し元に返らないため、ここで記録する
+		if stats != nil {
+			stats.recordResponse(resp)
+		}
+		drainAndClose(resp.Body)
+		if err := sleepContext(req.Context(), backoff); err != nil {
+			cancel()
+			return nil, err
+		}
+		stats.recordRetry(backoff)
+
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to rebuild request body for retry: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+}
+
+// sendOnce は上限に空きができるまで待ってからリクエストを1回送る
+// 同時リクエスト数の枠はレスポンスのボディを閉じたときに返す
+func sendOnce(client *http.Client, req *http.Request, stats *ConnStats) (*http.Response, error) {
+	release, wait, err := ratelimit.Acquire(req.Context(), req.URL)
+	if err != nil {
+		return nil, err
+	}
+	stats.recordWait(wait)
+	resp, err := client.Do(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// sleepContext はdの間待つ。ctxが終了した場合はそのエラーを返す
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseOnClose はボディを閉じたときに同時リクエスト数の枠を返す
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+// sharedTransport は接続設定ごとに共有するTransportを返す
+func sharedTransport(timeouts config.Timeouts, proxy string) *http.Transport {
+	// リクエスト全体の時間はhttp.Clientで管理するため、Transportのキーには含めない
+	timeouts.Total = 0
+	timeouts.Probe = 0
+	key := transportKey{timeouts: timeouts, proxy: proxy}
+
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	if transport, ok := transports[key]; ok {
+		return transport
+	}
+	transport := newTransport(timeouts, proxy)
+	transports[key] = transport
+	return transport
+}
+
+// newTransport はDefaultTransportを元に段階ごとのタイムアウトとプロキシを設定したTransportを作成する
+// keep-aliveとHTTP/2を有効にする
+func newTransport(timeouts config.Timeouts, proxy string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ForceAttemptHTTP2 = true
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	transport.Proxy = proxyFunc(proxy)
+
+	if timeouts.Dial > 0 {
+		dialer := &net.Dialer{
+			Timeout:   timeouts.Dial,
+			KeepAlive: 30 * time.Second,
+		}
+		transport.DialContext = dialer.DialContext
+	}
+	if timeouts.TLSHandshake > 0 {
+		transport.TLSHandshakeTimeout = timeouts.TLSHandshake
+	}
+	if timeouts.ResponseHeader > 0 {
+		transport.ResponseHeaderTimeout = timeouts.ResponseHeader
+	}
+
+	return transport
+}
+
+// proxyFunc はプロキシの指定からTransport.Proxyに設定する関数を返す
+// http://、https://に加えてsocks5://、socks5h://（プロキシ側で名前解決）に対応する
+// 不正な指定は設定ファイルの読み込み時に検証済みだが、ここでもリクエストごとにエラーにする
+func proxyFunc(proxy string) func(*http.Request) (*url.URL, error) {
+	switch proxy {
+	case "":
+		return http.ProxyFromEnvironment
+	case config.ProxyDirect:
+		return nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return func(*http.Request) (*url.URL, error) {
+			return nil, fmt.Errorf("invalid proxy: %s", proxy)
+		}
+	}
+	return http.ProxyURL(u)
+}
@@ -0,0 +1,244 @@
+package api
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/ratelimit"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestNewTransport(t *testing.T) {
+	defaults := http.DefaultTransport.(*http.Transport)
+
+	transport := newTransport(config.Timeouts{
+		TLSHandshake:   3 * time.Second,
+		ResponseHeader: 20 * time.Second,
+	}, "")
+	if transport.TLSHandshakeTimeout != 3*time.Second {
+		t.Errorf("TLSHandshakeTimeout = %v, want 3s", transport.TLSHandshakeTimeout)
+	}
+	if transport.ResponseHeaderTimeout != 20*time.Second {
+		t.Errorf("ResponseHeaderTimeout = %v, want 20s", transport.ResponseHeaderTimeout)
+	}
+
+	// 未設定の項目は標準値のまま
+	transport = newTransport(config.Timeouts{}, "")
+	if transport.TLSHandshakeTimeout != defaults.TLSHandshakeTimeout {
+		t.Errorf("TLSHandshakeTimeout = %v, want default %v", transport.TLSHandshakeTimeout, defaults.TLSHandshakeTimeout)
+	}
+	if transport.ResponseHeaderTimeout != 0 {
+		t.Errorf("ResponseHeaderTimeout = %v, want 0", transport.ResponseHeaderTimeout)
+	}
+}
+
+func TestNewHTTPClient_ResponseHeaderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newHTTPClient(5*time.Second, config.Timeouts{ResponseHeader: 50 * time.Millisecond}, "")
+	if _, err := client.Get(server.URL); err == nil {
+		t.Error("expected response header timeout error")
+	}
+
+	client = newHTTPClient(5*time.Second, config.Timeouts{}, "")
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+}
+
+// startSOCKS5Proxy は認証なしのCONNECTだけに対応するSOCKS5プロキシを起動し、接続先を記録する
+func startSOCKS5Proxy(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	targets := make(chan string, 16)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				// 挨拶: VER NMETHODS METHODS... → 認証なしを選ぶ
+				header := make([]byte, 2)
+				if _, err := io.ReadFull(conn, header); err != nil {
+					return
+				}
+				io.ReadFull(conn, make([]byte, header[1]))
+				conn.Write([]byte{5, 0})
+
+				// 要求: VER CMD RSV ATYP DST.ADDR DST.PORT
+				request := make([]byte, 4)
+				if _, err := io.ReadFull(conn, request); err != nil {
+					return
+				}
+				var host string
+				switch request[3] {
+				case 1:
+					addr := make([]byte, 4)
+					io.ReadFull(conn, addr)
+					host = net.IP(addr).String()
+				case 3:
+					length := make([]byte, 1)
+					io.ReadFull(conn, length)
+					name := make([]byte, length[0])
+					io.ReadFull(conn, name)
+					host = string(name)
+				default:
+					return
+				}
+				port := make([]byte, 2)
+				io.ReadFull(conn, port)
+				target := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+				targets <- target
+
+				upstream, err := net.Dial("tcp", target)
+				if err != nil {
+					conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer upstream.Close()
+				conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+				go io.Copy(upstream, conn)
+				io.Copy(conn, upstream)
+			}(conn)
+		}
+	}()
+	return listener.Addr().String(), targets
+}
+
+func TestNewHTTPClient_SOCKS5Proxy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	proxyAddr, targets := startSOCKS5Proxy(t)
+	for _, scheme := range []string{"socks5", "socks5h"} {
+		client := newHTTPClient(5*time.Second, config.Timeouts{}, scheme+"://"+proxyAddr)
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("%s: request through proxy failed: %v", scheme, err)
+		}
+		resp.Body.Close()
+
+		// ゲートウェイへの接続はプロキシ経由で行う
+		select {
+		case target := <-targets:
+			if target != server.Listener.Addr().String() {
+				t.Errorf("%s: proxy target = %s, want %s", scheme, target, server.Listener.Addr())
+			}
+		default:
+			t.Errorf("%s: request did not go through the proxy", scheme)
+		}
+	}
+}
+
+func TestSharedTransport_Proxy(t *testing.T) {
+	direct := newHTTPClient(10*time.Second, config.Timeouts{}, config.ProxyDirect)
+	socks := newHTTPClient(10*time.Second, config.Timeouts{}, "socks5://127.0.0.1:1080")
+	env := newHTTPClient(10*time.Second, config.Timeouts{}, "")
+
+	// プロキシが異なるゲートウェイとは接続を共有しない
+	if direct.Transport == socks.Transport || direct.Transport == env.Transport || socks.Transport == env.Transport {
+		t.Error("clients with different proxies should not share a transport")
+	}
+	if direct.Transport.(*http.Transport).Proxy != nil {
+		t.Error("direct should not use a proxy")
+	}
+
+	invalid := newHTTPClient(10*time.Second, config.Timeouts{}, "socks5://")
+	if _, err := invalid.Get("http://127.0.0.1:1"); err == nil {
+		t.Error("expected an error for an invalid proxy")
+	}
+}
+
+func TestDoRequest_MaxConcurrency(t *testing.T) {
+	var active, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ratelimit.Reset()
+	defer ratelimit.Reset()
+	ratelimit.Register(server.URL, ratelimit.Limit{MaxConcurrency: 1})
+
+	client := newHTTPClient(5*time.Second, config.Timeouts{}, "")
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			resp, err := doRequest(client, req, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	if peak != 1 {
+		t.Errorf("peak concurrency = %d, want 1", peak)
+	}
+}
+
+func TestDoRequest_WaitCountsTowardTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ratelimit.Reset()
+	defer ratelimit.Reset()
+	ratelimit.Register(server.URL, ratelimit.Limit{Interval: 2 * time.Second})
+
+	client := newHTTPClient(200*time.Millisecond, config.Timeouts{}, "")
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := doRequest(client, req, nil)
+	if err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	resp.Body.Close()
+
+	// 2つ目は上限の空きを待つ間にtimeoutを過ぎる（Contextに期限のないリクエストでも）
+	start := time.Now()
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, err := doRequest(client, req, nil); err == nil {
+		t.Fatal("expected the rate-limit wait to exceed the timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("request gave up after %v, want about the 200ms timeout", elapsed)
+	}
+}
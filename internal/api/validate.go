@@ -0,0 +1,130 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// フィールドの問題の種類
+const (
+	IssueMissing   = "missing"   // フィールドがない、またはnull
+	IssueMalformed = "malformed" // 型や値が期待と異なる
+)
+
+// FieldIssue はレスポンスのモデルの1つのフィールドの欠落・不正
+type FieldIssue struct {
+	Field   string `json:"field"`
+	Problem string `json:"problem"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// String は "max_tokens: malformed (expected integer, got string "8192")" の形式で返す
+func (i FieldIssue) String() string {
+	if i.Detail == "" {
+		return fmt.Sprintf("%s: %s", i.Field, i.Problem)
+	}
+	return fmt.Sprintf("%s: %s (%s)", i.Field, i.Problem, i.Detail)
+}
+
+// FormatIssues は問題の一覧を "; " 区切りで返す
+func FormatIssues(issues []FieldIssue) string {
+	parts := make([]string, len(issues))
+	for i, issue := range issues {
+		parts[i] = issue.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// fieldReader はモデルの生のフィールドを型を確かめながら読み出し、問題を記録する
+type fieldReader struct {
+	raw    map[string]interface{}
+	issues []FieldIssue
+}
+
+// value はフィールドの値を返す。required のフィールドがない場合は欠落として記録する
+func (r *fieldReader) value(field string, required bool) (interface{}, bool) {
+	value, ok := r.raw[field]
+	if !ok || value == nil {
+		if required {
+			r.issues = append(r.issues, FieldIssue{Field: field, Problem: IssueMissing})
+		}
+		return nil, false
+	}
+	return value, true
+}
+
+// malformed は型や値の誤りを記録する
+func (r *fieldReader) malformed(field, expected string, value interface{}) {
+	r.issues = append(r.issues, FieldIssue{
+		Field:   field,
+		Problem: IssueMalformed,
+		Detail:  fmt.Sprintf("expected %s, got %s", expected, describeValue(value)),
+	})
+}
+
+// string は文字列のフィールドを読み出す
+func (r *fieldReader) string(field string, required bool) string {
+	value, ok := r.value(field, required)
+	if !ok {
+		return ""
+	}
+	s, ok := value.(string)
+	if !ok {
+		r.malformed(field, "string", value)
+		return ""
+	}
+	if s == "" && required {
+		r.malformed(field, "non-empty string", value)
+	}
+	return s
+}
+
+// int は0以上の整数のフィールドを読み出す
+func (r *fieldReader) int(field string, required bool) int {
+	value, ok := r.value(field, required)
+	if !ok {
+		return 0
+	}
+	n, ok := value.(float64)
+	if !ok || n < 0 || n != math.Trunc(n) || n > math.MaxInt32 {
+		r.malformed(field, "non-negative integer", value)
+		return 0
+	}
+	return int(n)
+}
+
+// float は0以上の数値のフィールドを読み出す
+func (r *fieldReader) float(field string, required bool) float64 {
+	value, ok := r.value(field, required)
+	if !ok {
+		return 0
+	}
+	f, ok := value.(float64)
+	if !ok || f < 0 {
+		r.malformed(field, "non-negative number", value)
+		return 0
+	}
+	return f
+}
+
+// describeValue はエラー表示用に値の型と内容を返す
+func describeValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if len(v) > 40 {
+			v = v[:40] + "..."
+		}
+		return fmt.Sprintf("string %q", v)
+	case float64:
+		return fmt.Sprintf("number %v", v)
+	case bool:
+		return fmt.Sprintf("boolean %v", v)
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
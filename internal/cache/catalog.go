@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/internal/paths"
+)
+
+// CatalogEntry はキャッシュされたモデル一覧
+type CatalogEntry struct {
+	Gateway   string          `json:"gateway,omitempty"`
+	URL       string          `json:"url"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Models    []api.ModelInfo `json:"models"`
+}
+
+// Age はキャッシュ取得からの経過時間を返す
+func (e *CatalogEntry) Age() time.Duration {
+	return time.Since(e.FetchedAt)
+}
+
+// CatalogCache はゲートウェイごとのモデル一覧をファイルにキャッシュする
+type CatalogCache struct {
+	dir string
+}
+
+// NewCatalogCache は新しいCatalogCacheを作成する
+func NewCatalogCache(dir string) *CatalogCache {
+	return &CatalogCache{dir: dir}
+}
+
+// GetDefaultCacheDir はデフォルトのキャッシュディレクトリを返す
+func GetDefaultCacheDir() string {
+	return paths.CacheDir()
+}
+
+// Save はモデル一覧をキャッシュに保存する
+func (c *CatalogCache) Save(gateway, url string, models []api.ModelInfo) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	entry := CatalogEntry{
+		Gateway:   gateway,
+		URL:       url,
+		FetchedAt: time.Now(),
+		Models:    models,
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog cache: %w", err)
+	}
+
+	path := c.catalogPath(url)
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write catalog cache: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to write catalog cache: %w", err)
+	}
+	return nil
+}
+
+// Load はURLに対応するキャッシュを読み込む
+// キャッシュが存在しない場合はos.ErrNotExistを含むエラーを返す
+func (c *CatalogCache) Load(url string) (*CatalogEntry, error) {
+	data, err := os.ReadFile(c.catalogPath(url))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no cached catalog for %s: %w", url, os.ErrNotExist)
+		}
+		return nil, fmt.Errorf("failed to read catalog cache: %w", err)
+	}
+
+	var entry CatalogEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse catalog cache: %w", err)
+	}
+	return &entry, nil
+}
+
+// LoadAll はキャッシュ済みのすべてのモデル一覧をゲートウェイ名順に読み込む
+// 読み込めないファイルは無視する
+func (c *CatalogCache) LoadAll() ([]*CatalogEntry, error) {
+	paths, err := filepath.Glob(filepath.Join(c.dir, "catalog-*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list catalog cache: %w", err)
+	}
+
+	var entries []*CatalogEntry
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var entry CatalogEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Gateway != entries[j].Gateway {
+			return entries[i].Gateway < entries[j].Gateway
+		}
+		return entries[i].URL < entries[j].URL
+	})
+	return entries, nil
+}
+
+// catalogPath はURLに対応するキャッシュファイルのパスを返す
+// APIキーなどを含まないよう、URLはハッシュ化してファイル名に使う
+func (c *CatalogCache) catalogPath(url string) string {
+	sum := sha256.Sum256([]byte(strings.TrimRight(url, "/")))
+	return filepath.Join(c.dir, "catalog-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// FormatAge は経過時間を「3h ago」のような表示用文字列に変換する
+func FormatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
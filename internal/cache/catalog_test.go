@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+func TestCatalogCache_SaveAndLoad(t *testing.T) {
+	c := NewCatalogCache(t.TempDir())
+	models := []api.ModelInfo{
+		{ID: "gpt-4o", MaxTokens: 128000, Mode: "chat", InputCost: 0.0025},
+		{ID: "claude-3-haiku", MaxTokens: 200000, Mode: "chat"},
+	}
+
+	if err := c.Save("production", "https://gateway.example.com/v1", models); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// 末尾のスラッシュは同じキャッシュとして扱う
+	entry, err := c.Load("https://gateway.example.com/v1/")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if entry.Gateway != "production" {
+		t.Errorf("Gateway = %q, want %q", entry.Gateway, "production")
+	}
+	if len(entry.Models) != 2 || entry.Models[0].ID != "gpt-4o" {
+		t.Errorf("Models = %+v", entry.Models)
+	}
+	if entry.Age() < 0 || entry.Age() > time.Minute {
+		t.Errorf("Age() = %v, want recent", entry.Age())
+	}
+}
+
+func TestCatalogCache_LoadMissing(t *testing.T) {
+	c := NewCatalogCache(t.TempDir())
+
+	_, err := c.Load("https://unknown.example.com")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load() error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestCatalogCache_SeparatesURLs(t *testing.T) {
+	c := NewCatalogCache(t.TempDir())
+	c.Save("a", "https://a.example.com", []api.ModelInfo{{ID: "model-a"}})
+	c.Save("b", "https://b.example.com", []api.ModelInfo{{ID: "model-b"}})
+
+	entry, err := c.Load("https://a.example.com")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if entry.Models[0].ID != "model-a" {
+		t.Errorf("Models[0].ID = %q, want %q", entry.Models[0].ID, "model-a")
+	}
+}
+
+func TestCatalogCache_LoadAll(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCatalogCache(dir)
+	c.Save("staging", "https://staging.example.com", []api.ModelInfo{{ID: "model-b"}})
+	c.Save("production", "https://prod.example.com", []api.ModelInfo{{ID: "model-a"}})
+	os.WriteFile(dir+"/catalog-broken.json", []byte("{"), 0644)
+
+	entries, err := c.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2", len(entries))
+	}
+	if entries[0].Gateway != "production" || entries[1].Gateway != "staging" {
+		t.Errorf("gateways = %q, %q, want production, staging", entries[0].Gateway, entries[1].Gateway)
+	}
+}
+
+func TestCatalogCache_LoadAllMissingDir(t *testing.T) {
+	c := NewCatalogCache(t.TempDir() + "/missing")
+
+	entries, err := c.LoadAll()
+	if err != nil || len(entries) != 0 {
+		t.Errorf("LoadAll() = %v, %v, want empty", entries, err)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "just now"},
+		{15 * time.Minute, "15m ago"},
+		{5 * time.Hour, "5h ago"},
+		{72 * time.Hour, "3d ago"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatAge(tt.d); got != tt.want {
+			t.Errorf("FormatAge(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
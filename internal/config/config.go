@@ -1,12 +1,18 @@
 package config
 
-import "time"
+import (
+	"time"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
 
 // Config はアプリケーション設定を保持します
 type Config struct {
-	BaseURL string
-	APIKey  string
-	Timeout time.Duration
+	BaseURL  string
+	APIKey   string
+	Timeout  time.Duration
+	Timeouts config.Timeouts // 接続段階ごとのタイムアウト
+	Proxy    string          // 接続に使うプロキシ（空は環境変数、directは使わない）
 }
 
 // New は新しい設定を作成します
@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/armaniacs/llm-info/internal/redact"
 )
 
 // EnvConfig は環境変数から読み込んだ設定を表す
@@ -21,11 +23,13 @@ type EnvConfig struct {
 	ConfigPath     string
 	LogLevel       string
 	UserAgent      string
+	WebhookURL     string
 }
 
 // LoadEnvConfig は環境変数から設定を読み込む
 func LoadEnvConfig() *EnvConfig {
 	timeoutStr := os.Getenv("LLM_INFO_TIMEOUT")
+	redact.Register(os.Getenv("LLM_INFO_API_KEY"), os.Getenv("LLM_INFO_WEBHOOK_URL"))
 	return &EnvConfig{
 		URL:            os.Getenv("LLM_INFO_URL"),
 		APIKey:         os.Getenv("LLM_INFO_API_KEY"),
@@ -38,6 +42,7 @@ func LoadEnvConfig() *EnvConfig {
 		ConfigPath:     os.Getenv("LLM_INFO_CONFIG_PATH"),
 		LogLevel:       os.Getenv("LLM_INFO_LOG_LEVEL"),
 		UserAgent:      os.Getenv("LLM_INFO_USER_AGENT"),
+		WebhookURL:     os.Getenv("LLM_INFO_WEBHOOK_URL"),
 	}
 }
 
@@ -109,7 +114,7 @@ func (e *EnvConfig) Validate() error {
 	// 出力形式検証
 	if e.OutputFormat != "" {
 		validFormats := []string{"table", "json"}
-		if !contains(validFormats, e.OutputFormat) {
+		if format, _ := SplitOutputFormat(e.OutputFormat); !contains(validFormats, format) {
 			return fmt.Errorf("invalid LLM_INFO_OUTPUT_FORMAT: %s (valid: %s)",
 				e.OutputFormat, strings.Join(validFormats, ", "))
 		}
@@ -221,6 +226,7 @@ func PrintEnvHelp() {
 	fmt.Println("  LLM_INFO_CONFIG_PATH      設定ファイルのパス")
 	fmt.Println("  LLM_INFO_LOG_LEVEL        ログレベル")
 	fmt.Println("  LLM_INFO_USER_AGENT       ユーザーエージェント")
+	fmt.Println("  LLM_INFO_WEBHOOK_URL      通知先のWebhook URL")
 	fmt.Println()
 	fmt.Println("例:")
 	fmt.Println("  export LLM_INFO_URL=https://api.example.com/v1")
@@ -232,36 +238,12 @@ func PrintEnvHelp() {
 	fmt.Println("  llm-info")
 }
 
-// ValidateEnvVars は環境変数の妥当性を検証します
+// ValidateEnvVars は環境変数の妥当性を検証し、最初に見つかった問題を返します
 func ValidateEnvVars() error {
-	// タイムアウトの検証
-	if timeout := os.Getenv("LLM_INFO_TIMEOUT"); timeout != "" {
-		if _, err := time.ParseDuration(timeout); err != nil {
-			return fmt.Errorf("invalid LLM_INFO_TIMEOUT value: %s (%w)", timeout, err)
-		}
-	}
-
-	// 出力形式の検証
-	if format := os.Getenv("LLM_INFO_OUTPUT_FORMAT"); format != "" {
-		validFormats := []string{"table", "json"}
-		isValid := false
-		for _, validFormat := range validFormats {
-			if format == validFormat {
-				isValid = true
-				break
-			}
-		}
-		if !isValid {
-			return fmt.Errorf("invalid LLM_INFO_OUTPUT_FORMAT value: %s (valid formats: %v)", format, validFormats)
+	for _, status := range CheckEnvVars(os.Environ()) {
+		if status.Known && status.Error != "" {
+			return fmt.Errorf("invalid %s value: %s", status.Name, status.Error)
 		}
 	}
-
-	// 設定ファイルの存在チェック
-	if configFile := os.Getenv("LLM_INFO_CONFIG_FILE"); configFile != "" {
-		if _, err := os.Stat(configFile); os.IsNotExist(err) {
-			return fmt.Errorf("config file not found: %s", configFile)
-		}
-	}
-
 	return nil
 }
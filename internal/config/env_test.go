@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -406,3 +407,50 @@ func TestEnvConfig_ValidateNew(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckEnvVars(t *testing.T) {
+	environ := []string{
+		"PATH=/usr/bin",
+		"LLM_INFO_URL=https://api.example.com",
+		"LLM_INFO_API_KEY=sk-secret",
+		"LLM_INFO_OUTPUT_FORMAT=yaml",
+		"LLM_INFO_TIMOUT=5s",
+		"LLM_INFO_SOMETHING_ELSE=1",
+	}
+
+	statuses := CheckEnvVars(environ)
+	byName := make(map[string]EnvVarStatus)
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+	if len(statuses) != len(SupportedEnvVars)+2 {
+		t.Fatalf("got %d statuses, want %d", len(statuses), len(SupportedEnvVars)+2)
+	}
+
+	if s := byName["LLM_INFO_URL"]; !s.Set || s.Value != "https://api.example.com" || s.Error != "" {
+		t.Errorf("LLM_INFO_URL = %+v", s)
+	}
+	if s := byName["LLM_INFO_API_KEY"]; s.Value != "(set, hidden)" {
+		t.Errorf("LLM_INFO_API_KEY value = %q, want hidden", s.Value)
+	}
+	if s := byName["LLM_INFO_OUTPUT_FORMAT"]; s.Error == "" {
+		t.Errorf("LLM_INFO_OUTPUT_FORMAT should be invalid: %+v", s)
+	}
+	if s := byName["LLM_INFO_TIMEOUT"]; s.Set {
+		t.Errorf("LLM_INFO_TIMEOUT should not be set: %+v", s)
+	}
+	if s := byName["LLM_INFO_TIMOUT"]; s.Known || s.Suggestion != "LLM_INFO_TIMEOUT" || s.Value != "(set, hidden)" {
+		t.Errorf("LLM_INFO_TIMOUT = %+v", s)
+	}
+	if s := byName["LLM_INFO_SOMETHING_ELSE"]; s.Known || s.Suggestion != "" {
+		t.Errorf("LLM_INFO_SOMETHING_ELSE = %+v", s)
+	}
+
+	err := CheckUnknownEnvVars(environ)
+	if err == nil || !strings.Contains(err.Error(), "LLM_INFO_TIMOUT (did you mean LLM_INFO_TIMEOUT?)") {
+		t.Errorf("CheckUnknownEnvVars() error = %v", err)
+	}
+	if err := CheckUnknownEnvVars([]string{"LLM_INFO_URL=x"}); err != nil {
+		t.Errorf("CheckUnknownEnvVars() with known variables error = %v", err)
+	}
+}
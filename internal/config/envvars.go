@@ -0,0 +1,219 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvPrefix はllm-infoの環境変数の接頭辞
+const EnvPrefix = "LLM_INFO_"
+
+// EnvVar はサポートしている環境変数の定義
+type EnvVar struct {
+	Name        string
+	Description string
+	Secret      bool                     // 値を伏せて表示する
+	Validate    func(value string) error // nilの場合は値を検証しない
+}
+
+// SupportedEnvVars はサポートしているすべての環境変数
+var SupportedEnvVars = []EnvVar{
+	{Name: "LLM_INFO_URL", Description: "LLMゲートウェイのベースURL", Validate: validateEnvURL},
+	{Name: "LLM_INFO_API_KEY", Description: "認証に使用するAPIキー", Secret: true},
+	{Name: "LLM_INFO_TIMEOUT", Description: "リクエストタイムアウト (例: 10s, 1m)", Validate: validateEnvTimeout},
+	{Name: "LLM_INFO_DEFAULT_GATEWAY", Description: "デフォルトゲートウェイ名"},
+	{Name: "LLM_INFO_GATEWAY", Description: "デフォルトゲートウェイ名（旧形式）"},
+	{Name: "LLM_INFO_OUTPUT_FORMAT", Description: "出力形式 (table, json)", Validate: validateEnvOutputFormat},
+	{Name: "LLM_INFO_SORT_BY", Description: "ソート項目 (name, max_tokens, mode, input_cost)"},
+	{Name: "LLM_INFO_FILTER", Description: "フィルタ条件"},
+	{Name: "LLM_INFO_CONFIG_PATH", Description: "設定ファイルのパス"},
+	{Name: "LLM_INFO_CONFIG_FILE", Description: "設定ファイルのパス（旧形式）", Validate: validateEnvConfigFile},
+	{Name: "LLM_INFO_CONFIG_URL", Description: "組織の共有設定のURL（config_urlより優先）", Validate: validateEnvURL},
+	{Name: "LLM_INFO_LOG_LEVEL", Description: "ログレベル"},
+	{Name: "LLM_INFO_USER_AGENT", Description: "ユーザーエージェント"},
+	{Name: "LLM_INFO_WEBHOOK_URL", Description: "通知先のWebhook URL", Secret: true},
+	{Name: "LLM_INFO_ADMIN_KEY", Description: "spendで使うLiteLLMの管理キー", Secret: true},
+	{Name: "LLM_INFO_STATS", Description: "利用統計の記録 (true, false)", Validate: validateEnvBool},
+	{Name: "LLM_INFO_UPDATE_API_URL", Description: "self-updateで使うGitHub APIのURL", Validate: validateEnvURL},
+	{Name: "LLM_INFO_CONFIG_DIR", Description: "設定ファイルとメモを置くディレクトリ"},
+	{Name: "LLM_INFO_CACHE_DIR", Description: "モデル一覧と共有設定のキャッシュを置くディレクトリ"},
+	{Name: "LLM_INFO_STATE_DIR", Description: "探索結果・ログ・ロック・統計・クラッシュレポートを置くディレクトリ"},
+	{Name: "LLM_INFO_DEBUG", Description: "空でなければデバッグ情報を表示"},
+	{Name: "LLM_INFO_VERBOSE", Description: "空でなければ詳細なログを表示"},
+}
+
+// LookupEnvVar は名前に対応する環境変数の定義を返す
+func LookupEnvVar(name string) (EnvVar, bool) {
+	for _, v := range SupportedEnvVars {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return EnvVar{}, false
+}
+
+// EnvVarStatus は環境変数の現在の値と検証結果
+type EnvVarStatus struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Set         bool   `json:"set"`
+	Value       string `json:"value,omitempty"` // Secretの値は伏せる
+	Known       bool   `json:"known"`
+	Error       string `json:"error,omitempty"`
+	Suggestion  string `json:"suggestion,omitempty"` // 未知の変数に近い名前の変数
+}
+
+// CheckEnvVars はサポートしている環境変数の状態を定義順に返し、
+// 続けて未知のLLM_INFO_*変数を名前順に返す。environはos.Environ()の形式
+func CheckEnvVars(environ []string) []EnvVarStatus {
+	values := make(map[string]string)
+	for _, entry := range environ {
+		if name, value, ok := strings.Cut(entry, "="); ok && strings.HasPrefix(name, EnvPrefix) {
+			values[name] = value
+		}
+	}
+
+	statuses := make([]EnvVarStatus, 0, len(SupportedEnvVars))
+	for _, v := range SupportedEnvVars {
+		status := EnvVarStatus{Name: v.Name, Description: v.Description, Known: true}
+		value, ok := values[v.Name]
+		if ok {
+			status.Set = true
+			status.Value = value
+			if v.Secret && value != "" {
+				status.Value = "(set, hidden)"
+			}
+			if v.Validate != nil && value != "" {
+				if err := v.Validate(value); err != nil {
+					status.Error = err.Error()
+				}
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	// 未知の変数はAPIキーの綴り間違いかもしれないため、値を表示しない
+	for _, name := range UnknownEnvVars(environ) {
+		value := values[name]
+		if value != "" {
+			value = "(set, hidden)"
+		}
+		statuses = append(statuses, EnvVarStatus{
+			Name:       name,
+			Set:        true,
+			Value:      value,
+			Error:      "unknown variable; it is ignored",
+			Suggestion: suggestEnvVar(name),
+		})
+	}
+	return statuses
+}
+
+// UnknownEnvVars はサポートしていないLLM_INFO_*変数の名前を名前順に返す
+func UnknownEnvVars(environ []string) []string {
+	var unknown []string
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+		if _, ok := LookupEnvVar(name); !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// CheckUnknownEnvVars は未知のLLM_INFO_*変数があればエラーを返す（--strict-env用）
+func CheckUnknownEnvVars(environ []string) error {
+	unknown := UnknownEnvVars(environ)
+	if len(unknown) == 0 {
+		return nil
+	}
+	parts := make([]string, len(unknown))
+	for i, name := range unknown {
+		parts[i] = name
+		if suggestion := suggestEnvVar(name); suggestion != "" {
+			parts[i] += fmt.Sprintf(" (did you mean %s?)", suggestion)
+		}
+	}
+	return fmt.Errorf("unknown environment variable(s): %s", strings.Join(parts, ", "))
+}
+
+// suggestEnvVar は綴り間違いと思われる変数名に最も近いサポート済みの変数名を返す
+func suggestEnvVar(name string) string {
+	best, bestDistance := "", 4
+	for _, v := range SupportedEnvVars {
+		if d := editDistance(name, v.Name); d < bestDistance {
+			best, bestDistance = v.Name, d
+		}
+	}
+	return best
+}
+
+// editDistance は2つの文字列のレーベンシュタイン距離を返す
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// validateEnvURL はURLの形式を検証する
+func validateEnvURL(value string) error {
+	if !isValidURL(value) {
+		return fmt.Errorf("%q is not a valid URL", value)
+	}
+	return nil
+}
+
+// validateEnvTimeout はタイムアウトの形式を検証する
+func validateEnvTimeout(value string) error {
+	if _, err := time.ParseDuration(value); err != nil {
+		return fmt.Errorf("%q is not a duration such as 10s or 1m", value)
+	}
+	return nil
+}
+
+// validateEnvOutputFormat は出力形式を検証する
+func validateEnvOutputFormat(value string) error {
+	validFormats := []string{"table", "json"}
+	if !contains(validFormats, value) {
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(validFormats, ", "))
+	}
+	return nil
+}
+
+// validateEnvConfigFile は設定ファイルが存在するかを検証する
+func validateEnvConfigFile(value string) error {
+	if _, err := os.Stat(value); os.IsNotExist(err) {
+		return fmt.Errorf("config file not found: %s", value)
+	}
+	return nil
+}
+
+// validateEnvBool は真偽値の形式を検証する
+func validateEnvBool(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("%q is not true or false", value)
+	}
+	return nil
+}
@@ -1,12 +1,13 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
 	"time"
 
+	"github.com/armaniacs/llm-info/internal/paths"
 	"github.com/armaniacs/llm-info/pkg/config"
 	"gopkg.in/yaml.v3"
 	"errors"
@@ -14,11 +15,7 @@ import (
 
 // GetConfigPath は設定ファイルのパスを返す
 func GetConfigPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return ""
-	}
-	return filepath.Join(home, ".config", "llm-info", "llm-info.yaml")
+	return paths.ConfigFile("llm-info.yaml")
 }
 
 // LoadConfigFromFile はファイルから設定を読み込む
@@ -51,22 +48,13 @@ func SaveConfigToFile(cfg *config.Config, path string) error {
 		path = GetConfigPath()
 	}
 
-	// ディレクトリが存在しない場合は作成
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
-	}
-
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
-	}
-
-	return nil
+	// ディレクトリが存在しない場合は作成し、ロックを取得して置き換える
+	return WriteConfigFile(path, data, 0644)
 }
 
 // getDefaultConfig はデフォルト設定を返す
@@ -262,10 +250,15 @@ func tryLegacyFileConfigFormat(data []byte) (*config.Config, error) {
 		cfg.Gateways = make([]config.Gateway, len(fileConfig.Gateways))
 		for i, gw := range fileConfig.Gateways {
 			cfg.Gateways[i] = config.Gateway{
-				Name:    gw.Name,
-				URL:     gw.URL,
-				APIKey:  gw.APIKey,
-				Timeout: gw.Timeout,
+				Name:          gw.Name,
+				URL:           gw.URL,
+				APIKey:        gw.APIKey,
+				Timeout:       gw.Timeout,
+				Type:          gw.Type,
+				DefaultModel:  gw.DefaultModel,
+				MaxBodySize:   gw.MaxBodySize,
+				ProbeEndpoint: gw.ProbeEndpoint,
+				Proxy:         gw.Proxy,
 			}
 		}
 	}
@@ -334,10 +327,15 @@ func ConvertLegacyToNew(legacy *config.FileConfig) *config.Config {
 	gateways := make([]config.Gateway, len(legacy.Gateways))
 	for i, gw := range legacy.Gateways {
 		gateways[i] = config.Gateway{
-			Name:    gw.Name,
-			URL:     gw.URL,
-			APIKey:  gw.APIKey,
-			Timeout: gw.Timeout,
+			Name:          gw.Name,
+			URL:           gw.URL,
+			APIKey:        gw.APIKey,
+			Timeout:       gw.Timeout,
+			Type:          gw.Type,
+			DefaultModel:  gw.DefaultModel,
+			MaxBodySize:   gw.MaxBodySize,
+			ProbeEndpoint: gw.ProbeEndpoint,
+			Proxy:         gw.Proxy,
 		}
 	}
 
@@ -397,3 +395,225 @@ func LoadLegacyConfigFromString(content string) (*config.Config, error) {
 
 	return nil, fmt.Errorf("failed to parse config as any known format")
 }
+
+// gatewayEntry は設定ファイルに書き込むゲートウェイ設定
+type gatewayEntry struct {
+	Name    string `yaml:"name"`
+	URL     string `yaml:"url"`
+	APIKey  string `yaml:"api_key,omitempty"`
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+// globalEntry は設定ファイルを新規作成するときに書き込むグローバル設定
+type globalEntry struct {
+	Timeout      string `yaml:"timeout"`
+	OutputFormat string `yaml:"output_format"`
+	SortBy       string `yaml:"sort_by"`
+}
+
+// SaveGatewayToFile はゲートウェイ設定を設定ファイルに追加する
+// 同名のゲートウェイがある場合は置き換え、既存のコメントや他の設定は保持する
+// setDefaultがtrue、またはdefault_gatewayが未設定の場合はデフォルトゲートウェイにする
+func SaveGatewayToFile(gw config.Gateway, path string, setDefault bool) error {
+	if path == "" {
+		path = GetConfigPath()
+	}
+
+	return updateConfigDocument(path, func(root *yaml.Node) error {
+		return setGateway(root, gw, setDefault)
+	})
+}
+
+// setGateway は設定ファイルのノードにゲートウェイ設定を追加する（同名のゲートウェイは置き換える）
+func setGateway(root *yaml.Node, gw config.Gateway, setDefault bool) error {
+	entry := gatewayEntry{Name: gw.Name, URL: gw.URL, APIKey: gw.APIKey}
+	if gw.Timeout > 0 {
+		entry.Timeout = gw.Timeout.String()
+	}
+	var entryNode yaml.Node
+	if err := entryNode.Encode(entry); err != nil {
+		return fmt.Errorf("failed to marshal gateway: %w", err)
+	}
+
+	gateways := mappingValue(root, "gateways")
+	if gateways == nil || gateways.Kind != yaml.SequenceNode {
+		gateways = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		setMappingValue(root, "gateways", gateways)
+	}
+	replaced := false
+	for i, item := range gateways.Content {
+		if name := mappingValue(item, "name"); name != nil && name.Value == gw.Name {
+			gateways.Content[i] = &entryNode
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		gateways.Content = append(gateways.Content, &entryNode)
+	}
+
+	if current := mappingValue(root, "default_gateway"); setDefault || current == nil || current.Value == "" {
+		setMappingValue(root, "default_gateway", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: gw.Name})
+	}
+
+	// 新規作成時はデフォルト設定と同じグローバル設定を書き込む（検証でtimeoutが必須のため）
+	if mappingValue(root, "global") == nil {
+		defaults := getDefaultConfig().Global
+		global := globalEntry{Timeout: defaults.Timeout.String(), OutputFormat: defaults.OutputFormat, SortBy: defaults.SortBy}
+		if gw.Timeout > 0 {
+			global.Timeout = gw.Timeout.String()
+		}
+		var globalNode yaml.Node
+		if err := globalNode.Encode(global); err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		setMappingValue(root, "global", &globalNode)
+	}
+	return nil
+}
+
+// SavePinnedToFile は設定ファイルのpinnedをモデルIDの一覧で置き換える
+// 既存のコメントや他の設定は保持し、一覧が空の場合はpinnedを削除する
+func SavePinnedToFile(pinned []string, path string) error {
+	return UpdatePinnedInFile(path, func([]string) []string { return pinned })
+}
+
+// UpdatePinnedInFile は設定ファイルのpinnedを読み込み、updateが返す一覧で置き換える
+// 読み込みから書き込みまでロックを保持するため、同時に実行されたpin add/removeの変更も失われない
+// 他のプロセスに変更された場合は読み込みからやり直すため、updateは複数回呼ばれることがある
+func UpdatePinnedInFile(path string, update func(pinned []string) []string) error {
+	if path == "" {
+		path = GetConfigPath()
+	}
+
+	return updateConfigDocument(path, func(root *yaml.Node) error {
+		var current []string
+		if node := mappingValue(root, "pinned"); node != nil {
+			if err := node.Decode(&current); err != nil {
+				return fmt.Errorf("failed to parse pinned models: %w", err)
+			}
+		}
+
+		pinned := update(current)
+		if len(pinned) == 0 {
+			deleteMappingValue(root, "pinned")
+			return nil
+		}
+		var pinnedNode yaml.Node
+		if err := pinnedNode.Encode(pinned); err != nil {
+			return fmt.Errorf("failed to marshal pinned models: %w", err)
+		}
+		setMappingValue(root, "pinned", &pinnedNode)
+		return nil
+	})
+}
+
+// updateConfigDocument は設定ファイルのロックを取得し、読み込んだYAMLのノードをmodifyで書き換えて書き込む
+// ロックを使わない書き込み（エディターや古いバージョン）で読み込み後に変更された場合は、読み込みからやり直す
+func updateConfigDocument(path string, modify func(root *yaml.Node) error) error {
+	unlock, err := lockConfigFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for attempt := 0; attempt < maxConfigWriteAttempts; attempt++ {
+		doc, mode, snapshot, err := readConfigDocument(path)
+		if err != nil {
+			return err
+		}
+		if err := modify(doc.Content[0]); err != nil {
+			return err
+		}
+		err = writeConfigDocument(doc, path, mode, snapshot)
+		if !errors.Is(err, ErrConfigConflict) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to update %s: %w", path, ErrConfigConflict)
+}
+
+// readConfigDocument は書き換えのために設定ファイルをYAMLのノードとして読み込み、ファイルのパーミッションと
+// 読み込んだ内容（変更の検出用、ファイルがなければnil）と共に返す
+// ファイルがない場合は空のマッピングを返す（パーミッションはAPIキーを含むため0600）
+func readConfigDocument(path string) (*yaml.Node, os.FileMode, []byte, error) {
+	var doc yaml.Node
+	mode := os.FileMode(0600)
+	snapshot, err := readConfigSnapshot(path)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if snapshot != nil {
+		if err := yaml.Unmarshal(snapshot, &doc); err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, 0, nil, fmt.Errorf("failed to update config file: top level must be a mapping")
+	}
+	return &doc, mode, snapshot, nil
+}
+
+// writeConfigDocument は検証したうえでYAMLのノードを設定ファイルに書き込む
+// 書き込み中のファイルを読まれないよう、一時ファイルに書いてから置き換える
+// 読み込んだ時点の内容（snapshot）から変更されていた場合はErrConfigConflictを返す
+func writeConfigDocument(doc *yaml.Node, path string, mode os.FileMode, snapshot []byte) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	data := buf.Bytes()
+
+	// 読み込み時に拒否される設定は書き込まない
+	var updated config.Config
+	if err := yaml.Unmarshal(data, &updated); err != nil {
+		return fmt.Errorf("failed to parse updated config: %w", err)
+	}
+	if err := ValidateConfig(&updated); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	return replaceConfigFile(path, data, mode, snapshot, true)
+}
+
+// mappingValue はマッピングノードからキーに対応する値を返す
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue はマッピングノードのキーの値を設定する（キーがなければ追加する）
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// deleteMappingValue はマッピングノードからキーを削除する
+func deleteMappingValue(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
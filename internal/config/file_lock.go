@@ -0,0 +1,126 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// configLockTimeout は他の実行が設定ファイルを書き込み中の場合に待つ最大時間
+const configLockTimeout = 10 * time.Second
+
+// configLockStale はロックファイルを残したまま異常終了したとみなす経過時間
+// 設定ファイルの書き込みは一瞬で終わるため、これより古いロックは削除して取得し直す
+const configLockStale = 30 * time.Second
+
+// configLockPoll はロックの解放を再確認する間隔
+const configLockPoll = 50 * time.Millisecond
+
+// maxConfigWriteAttempts は読み込んでから書き込むまでに設定ファイルが変更された場合に、読み込みからやり直す回数
+const maxConfigWriteAttempts = 3
+
+// ErrConfigConflict は読み込んでから書き込むまでに、他のプロセス（エディターなど）が設定ファイルを変更したことを表す
+var ErrConfigConflict = errors.New("config file was modified by another process while updating it")
+
+// lockConfigFile は設定ファイルの隣にロックファイル（<path>.lock）を作成して勧告ロックを取得する
+// CIのマトリクスジョブなどで複数のllm-infoが同時に設定ファイルを書き換えても、更新が失われないようにする
+func lockConfigFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(configLockTimeout)
+
+	for {
+		file, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			file.WriteString(strconv.Itoa(os.Getpid()))
+			file.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock config file: %w", err)
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > configLockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			holder, _ := os.ReadFile(lockPath)
+			return nil, fmt.Errorf("config file %s is locked by another llm-info process (pid %s); remove %s if no other process is running",
+				path, bytes.TrimSpace(holder), lockPath)
+		}
+		time.Sleep(configLockPoll)
+	}
+}
+
+// readConfigSnapshot は書き込み前の変更の検出のために設定ファイルの内容を返す（ファイルがなければnil）
+func readConfigSnapshot(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return data, nil
+}
+
+// replaceConfigFile は設定ファイルを一時ファイル経由で置き換える
+// snapshotがnilでなければ、置き換える直前の内容がsnapshotと同じ（読み込んでから変更されていない）ことを確かめる
+// checkSnapshotがfalseの場合は確かめずに置き換える（テンプレートの作成など、既存の内容を使わない書き込み用）
+func replaceConfigFile(path string, data []byte, mode os.FileMode, snapshot []byte, checkSnapshot bool) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	tempFile := temp.Name()
+	_, writeErr := temp.Write(data)
+	closeErr := temp.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr == nil {
+		writeErr = os.Chmod(tempFile, mode)
+	}
+	if writeErr != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to write config file: %w", writeErr)
+	}
+
+	if checkSnapshot {
+		current, err := readConfigSnapshot(path)
+		if err != nil {
+			os.Remove(tempFile)
+			return err
+		}
+		if !bytes.Equal(current, snapshot) || (current == nil) != (snapshot == nil) {
+			os.Remove(tempFile)
+			return ErrConfigConflict
+		}
+	}
+
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// WriteConfigFile は設定ファイルのロックを取得して内容を書き込む（既存の内容は読まずに置き換える）
+func WriteConfigFile(path string, data []byte, mode os.FileMode) error {
+	unlock, err := lockConfigFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return replaceConfigFile(path, data, mode, nil, false)
+}
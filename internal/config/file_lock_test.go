@@ -0,0 +1,134 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestSaveGatewayToFile_Concurrent(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "llm-info.yaml")
+
+	const writers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			gw := config.Gateway{Name: fmt.Sprintf("gw-%d", i), URL: "https://gateway.example.com", Timeout: 10 * time.Second}
+			errs <- SaveGatewayToFile(gw, configPath, false)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("SaveGatewayToFile() failed: %v", err)
+		}
+	}
+
+	loaded, err := LoadConfigFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile() failed: %v", err)
+	}
+	if len(loaded.Gateways) != writers {
+		t.Errorf("len(Gateways) = %d, want %d (updates were lost)", len(loaded.Gateways), writers)
+	}
+	if _, err := os.Stat(configPath + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file was not removed: %v", err)
+	}
+}
+
+func TestUpdatePinnedInFile_Conflict(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "llm-info.yaml")
+	gw := config.Gateway{Name: "production", URL: "https://gateway.example.com", Timeout: 10 * time.Second}
+	if err := SaveGatewayToFile(gw, configPath, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavePinnedToFile([]string{"gpt-4o"}, configPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// 1回目の更新の途中で、ロックを使わないプロセスが設定ファイルを書き換える
+	calls := 0
+	err := UpdatePinnedInFile(configPath, func(pinned []string) []string {
+		calls++
+		if calls == 1 {
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			edited := strings.Replace(string(data), "- gpt-4o", "- gpt-4o\n  - claude-3-haiku", 1)
+			if err := os.WriteFile(configPath, []byte(edited), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return append(pinned, "o3")
+	})
+	if err != nil {
+		t.Fatalf("UpdatePinnedInFile() failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("update was called %d times, want 2 (retry after the conflict)", calls)
+	}
+
+	loaded, err := LoadConfigFromFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Pinned) != 3 || loaded.Pinned[1] != "claude-3-haiku" {
+		t.Errorf("Pinned = %v, want the concurrent edit preserved", loaded.Pinned)
+	}
+}
+
+func TestWriteConfigDocument_Conflict(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "llm-info.yaml")
+	gw := config.Gateway{Name: "production", URL: "https://gateway.example.com", Timeout: 10 * time.Second}
+	if err := SaveGatewayToFile(gw, configPath, true); err != nil {
+		t.Fatal(err)
+	}
+	doc, mode, snapshot, err := readConfigDocument(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(configPath, []byte("pinned: [o3]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeConfigDocument(doc, configPath, mode, snapshot); !errors.Is(err, ErrConfigConflict) {
+		t.Errorf("writeConfigDocument() error = %v, want ErrConfigConflict", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if string(data) != "pinned: [o3]\n" {
+		t.Errorf("config file was overwritten: %s", data)
+	}
+}
+
+func TestLockConfigFile_Stale(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "llm-info.yaml")
+	lockPath := configPath + ".lock"
+	if err := os.WriteFile(lockPath, []byte("12345"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * configLockStale)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := lockConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("lockConfigFile() with a stale lock failed: %v", err)
+	}
+	unlock()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("lock file was not removed: %v", err)
+	}
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -141,6 +143,143 @@ func TestSaveConfigToFile(t *testing.T) {
 	}
 }
 
+func TestSaveGatewayToFile_NewFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "llm-info", "llm-info.yaml")
+
+	gw := config.Gateway{Name: "production", URL: "https://gateway.example.com", APIKey: "sk-test", Timeout: 15 * time.Second}
+	if err := SaveGatewayToFile(gw, configPath, false); err != nil {
+		t.Fatalf("SaveGatewayToFile() failed: %v", err)
+	}
+
+	info, err := os.Stat(configPath)
+	if err != nil {
+		t.Fatalf("Config file was not created: %v", err)
+	}
+	// APIキーを含むため他のユーザーから読めないようにする
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	loaded, err := LoadConfigFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile() failed: %v", err)
+	}
+	if len(loaded.Gateways) != 1 || !reflect.DeepEqual(loaded.Gateways[0], gw) {
+		t.Errorf("Gateways = %+v, want [%+v]", loaded.Gateways, gw)
+	}
+	if loaded.DefaultGateway != "production" {
+		t.Errorf("DefaultGateway = %q, want %q", loaded.DefaultGateway, "production")
+	}
+}
+
+func TestSaveGatewayToFile_ExistingFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "llm-info.yaml")
+	existing := `# 既存の設定
+gateways:
+  - name: production
+    url: https://old.example.com
+    timeout: 10s
+  - name: staging # 検証環境
+    url: https://staging.example.com
+    timeout: 10s
+default_gateway: staging
+global:
+  timeout: 10s
+  output_format: json
+  sort_by: name
+`
+	if err := os.WriteFile(configPath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		gw          config.Gateway
+		setDefault  bool
+		wantCount   int
+		wantDefault string
+	}{
+		{"replace existing gateway", config.Gateway{Name: "production", URL: "https://new.example.com", Timeout: 5 * time.Second}, false, 2, "staging"},
+		{"append and set default", config.Gateway{Name: "dev", URL: "https://dev.example.com", Timeout: 5 * time.Second}, true, 3, "dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SaveGatewayToFile(tt.gw, configPath, tt.setDefault); err != nil {
+				t.Fatalf("SaveGatewayToFile() failed: %v", err)
+			}
+
+			loaded, err := LoadConfigFromFile(configPath)
+			if err != nil {
+				t.Fatalf("LoadConfigFromFile() failed: %v", err)
+			}
+			if len(loaded.Gateways) != tt.wantCount {
+				t.Fatalf("len(Gateways) = %d, want %d", len(loaded.Gateways), tt.wantCount)
+			}
+			found := false
+			for _, gw := range loaded.Gateways {
+				if gw.Name == tt.gw.Name {
+					found = gw.URL == tt.gw.URL
+				}
+			}
+			if !found {
+				t.Errorf("gateway %q was not saved with URL %q", tt.gw.Name, tt.gw.URL)
+			}
+			if loaded.DefaultGateway != tt.wantDefault {
+				t.Errorf("DefaultGateway = %q, want %q", loaded.DefaultGateway, tt.wantDefault)
+			}
+			if loaded.Global.OutputFormat != "json" {
+				t.Errorf("Global.OutputFormat = %q, want other settings to be kept", loaded.Global.OutputFormat)
+			}
+		})
+	}
+
+	data, _ := os.ReadFile(configPath)
+	if !strings.Contains(string(data), "# 検証環境") {
+		t.Errorf("comments were not preserved:\n%s", data)
+	}
+}
+
+func TestSavePinnedToFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "llm-info.yaml")
+	existing := `gateways:
+  - name: production # 本番環境
+    url: https://gateway.example.com
+    timeout: 10s
+global:
+  timeout: 10s
+  output_format: table
+  sort_by: name
+`
+	if err := os.WriteFile(configPath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SavePinnedToFile([]string{"gpt-4o", "claude-3-haiku"}, configPath); err != nil {
+		t.Fatalf("SavePinnedToFile() failed: %v", err)
+	}
+	loaded, err := LoadConfigFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile() failed: %v", err)
+	}
+	if strings.Join(loaded.Pinned, ",") != "gpt-4o,claude-3-haiku" {
+		t.Errorf("Pinned = %v, want [gpt-4o claude-3-haiku]", loaded.Pinned)
+	}
+	data, _ := os.ReadFile(configPath)
+	if !strings.Contains(string(data), "# 本番環境") {
+		t.Errorf("comments were not preserved:\n%s", data)
+	}
+
+	// 空にするとpinnedを削除する
+	if err := SavePinnedToFile(nil, configPath); err != nil {
+		t.Fatalf("SavePinnedToFile() failed: %v", err)
+	}
+	data, _ = os.ReadFile(configPath)
+	if strings.Contains(string(data), "pinned") {
+		t.Errorf("pinned was not removed:\n%s", data)
+	}
+}
+
 func TestLoadLegacyConfigFromFile(t *testing.T) {
 	// 一時ディレクトリを作成
 	tmpDir, err := os.MkdirTemp("", "llm-info-test")
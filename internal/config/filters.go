@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// filterPresetPattern は名前付きフィルタの名前（--filter @名前 で指定する値）の形式
+var filterPresetPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// UnknownFilterPresetError は--filterの@名前が設定ファイルのfiltersに定義されていないことを表す
+type UnknownFilterPresetError struct {
+	Name      string   // 見つからなかった名前（@なし）
+	Available []string // 定義されている名前（@付き、ソート済み）
+}
+
+func (e *UnknownFilterPresetError) Error() string {
+	if len(e.Available) == 0 {
+		return fmt.Sprintf("unknown filter preset @%s (no filters are defined in the config file)", e.Name)
+	}
+	return fmt.Sprintf("unknown filter preset @%s (available: %s)", e.Name, strings.Join(e.Available, ", "))
+}
+
+// ExpandFilterPresets はフィルタ文字列のうち@名前の条件を設定ファイルの名前付きフィルタに置き換える
+// 名前付きフィルタの中の@名前も再帰的に展開し、他の条件と組み合わせられる（例: @cheap-chat,tokens>100000）
+func ExpandFilterPresets(filter string, presets map[string]string) (string, error) {
+	if !strings.Contains(filter, "@") {
+		return filter, nil
+	}
+	return expandFilterPresets(filter, presets, nil)
+}
+
+// expandFilterPresets はstack（展開中の名前付きフィルタ）をたどりながら@名前を展開する
+func expandFilterPresets(filter string, presets map[string]string, stack []string) (string, error) {
+	var expanded []string
+	for _, part := range strings.Split(filter, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if !strings.HasPrefix(part, "@") {
+			expanded = append(expanded, part)
+			continue
+		}
+
+		name := strings.TrimPrefix(part, "@")
+		for i, parent := range stack {
+			if parent == name {
+				cycle := append(append([]string(nil), stack[i:]...), name)
+				return "", fmt.Errorf("filter preset @%s refers to itself (@%s)", name, strings.Join(cycle, " -> @"))
+			}
+		}
+		preset, ok := presets[name]
+		if !ok {
+			return "", &UnknownFilterPresetError{Name: name, Available: filterPresetNames(presets)}
+		}
+		value, err := expandFilterPresets(preset, presets, append(stack, name))
+		if err != nil {
+			return "", err
+		}
+		if value != "" {
+			expanded = append(expanded, value)
+		}
+	}
+	return strings.Join(expanded, ","), nil
+}
+
+// filterPresetNames は名前付きフィルタの名前を@付きでソートして返す
+func filterPresetNames(presets map[string]string) []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, "@"+name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateFilterPresets は名前付きフィルタの名前と、参照先がすべて展開できることを検証する
+func validateFilterPresets(presets map[string]string) error {
+	for _, name := range sortedKeys(presets) {
+		if !filterPresetPattern.MatchString(name) {
+			return fmt.Errorf("invalid filter name: %q (use lowercase letters, digits, - and _)", name)
+		}
+		if strings.TrimSpace(presets[name]) == "" {
+			return fmt.Errorf("filter %s: condition is required", name)
+		}
+		if _, err := expandFilterPresets(presets[name], presets, []string{name}); err != nil {
+			return fmt.Errorf("filter %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// sortedKeys はmapのキーをソートして返す（検証のエラーを毎回同じ順序で報告するため）
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
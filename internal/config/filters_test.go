@@ -0,0 +1,123 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandFilterPresets(t *testing.T) {
+	presets := map[string]string{
+		"chat":       "mode:chat",
+		"cheap-chat": "@chat,cost<0.000005",
+		"long":       "tokens>100000",
+		"cheap-long": "@cheap-chat, @long",
+		"loop-a":     "@loop-b",
+		"loop-b":     "name:gpt,@loop-a",
+	}
+
+	tests := []struct {
+		name    string
+		filter  string
+		want    string
+		wantErr string
+	}{
+		{"no presets", "name:gpt,tokens>1000", "name:gpt,tokens>1000", ""},
+		{"preset", "@chat", "mode:chat", ""},
+		{"nested preset", "@cheap-chat", "mode:chat,cost<0.000005", ""},
+		{"composed with conditions", "@cheap-long,exclude:beta", "mode:chat,cost<0.000005,tokens>100000,exclude:beta", ""},
+		{"unknown preset", "@missing", "", "unknown filter preset @missing (available: @chat, @cheap-chat"},
+		{"cycle", "@loop-a", "", "@loop-a -> @loop-b -> @loop-a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandFilterPresets(tt.filter, presets)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ExpandFilterPresets() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandFilterPresets() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandFilterPresets() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ExpandFilterPresets("@chat", nil); err == nil || !strings.Contains(err.Error(), "no filters are defined") {
+		t.Errorf("ExpandFilterPresets() without presets error = %v", err)
+	}
+}
+
+func TestValidateFilterPresets(t *testing.T) {
+	tests := []struct {
+		name    string
+		presets map[string]string
+		wantErr string
+	}{
+		{"valid", map[string]string{"chat": "mode:chat", "cheap-chat": "@chat,cost<0.000005"}, ""},
+		{"invalid name", map[string]string{"Cheap Chat": "mode:chat"}, "invalid filter name"},
+		{"empty condition", map[string]string{"chat": " "}, "condition is required"},
+		{"unknown reference", map[string]string{"chat": "@missing"}, "unknown filter preset @missing"},
+		{"self reference", map[string]string{"chat": "mode:chat,@chat"}, "refers to itself"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFilterPresets(tt.presets)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateFilterPresets() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateFilterPresets() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManager_ResolveConfig_FilterPresets(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "test-config.yaml")
+	configContent := `
+gateways:
+  - name: "prod"
+    url: "https://prod.example.com"
+    api_key: "sk-test"
+    timeout: "5s"
+default_gateway: "prod"
+global:
+  timeout: "10s"
+  output_format: "table"
+  sort_by: "name"
+filters:
+  chat: "mode:chat"
+  cheap-chat: "@chat,cost<0.000005"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	manager := NewManager(configPath)
+	if err := manager.Load(); err != nil {
+		t.Fatalf("Failed to load config file: %v", err)
+	}
+
+	resolved, err := manager.ResolveConfig(&CLIArgs{Filter: "@cheap-chat,tokens>100000"})
+	if err != nil {
+		t.Fatalf("ResolveConfig() error = %v", err)
+	}
+	if want := "mode:chat,cost<0.000005,tokens>100000"; resolved.Filter != want {
+		t.Errorf("Filter = %q, want %q", resolved.Filter, want)
+	}
+
+	_, err = manager.ResolveConfig(&CLIArgs{Filter: "@expensive"})
+	var unknown *UnknownFilterPresetError
+	if !errors.As(err, &unknown) || unknown.Name != "expensive" || len(unknown.Available) != 2 {
+		t.Errorf("ResolveConfig() with an unknown preset error = %v", err)
+	}
+}
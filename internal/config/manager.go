@@ -3,25 +3,40 @@ package config
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/armaniacs/llm-info/internal/numfmt"
+	"github.com/armaniacs/llm-info/internal/ratelimit"
+	"github.com/armaniacs/llm-info/internal/readonly"
+	"github.com/armaniacs/llm-info/internal/paths"
+	"github.com/armaniacs/llm-info/internal/redact"
 	"github.com/armaniacs/llm-info/pkg/config"
 )
 
 
 // ResolvedConfig は解決された設定を表す
 type ResolvedConfig struct {
-	Gateway      *config.GatewayConfig
-	OutputFormat string
-	SortBy       string
-	Filter       string
-	Columns      string
-	LogLevel     string
-	UserAgent    string
-	Sources      map[string]config.ConfigSource
-	Cost         *config.CostConfig
+	Gateway       *config.GatewayConfig
+	OutputFormat  string
+	SortBy        string
+	Filter        string
+	Columns       string
+	NumberFormat  string // トークン数とコストの表記（raw, thousands, si）
+	TableStyle    string // テーブルの罫線の種類（default, grid, plain, compact）
+	LogLevel      string
+	UserAgent     string
+	Sources       map[string]config.ConfigSource
+	Cost          *config.CostConfig
+	Notifications *config.NotificationConfig
+	Hooks         *config.HooksConfig
+	Formatters    map[string]config.FormatterConfig // --formatで選べる外部フォーマッター
+	FilterPresets map[string]string                 // --filter @名前 で使う名前付きのフィルタ
+	Normalization *config.NormalizationConfig
+	Dedupe        bool
+
+	SpendConfirmThreshold float64 // 見積もりコスト（USD）がこれを超える探索は実行前に確認する（0は確認しない）
 }
 
 // Manager は設定管理機能を提供します
@@ -30,6 +45,7 @@ type Manager struct {
 	fileConfig *config.FileConfig
 	newConfig  *config.Config // 新しい形式の設定
 	path       string
+	orgURL     string // 重ねた組織の共有設定のURL
 }
 
 // NewManager は新しい設定マネージャーを作成します
@@ -67,14 +83,52 @@ func (m *Manager) Load() error {
 		// 古い形式の設定は新しい形式に変換済みなので、そのまま使用
 		m.newConfig = legacyConfig
 	} else {
+		// 組織の共有設定があれば、その上にローカルの設定を重ねる
+		if url := orgConfigURL(newConfig); url != "" {
+			local := newConfig
+			if _, err := os.Stat(configPath); os.IsNotExist(err) {
+				// ローカルの設定ファイルがない場合はデフォルトのゲートウェイを重ねない
+				local = &config.Config{}
+			}
+			org, err := FetchOrgConfig(url, orgConfigCacheDir(local))
+			if err != nil {
+				return err
+			}
+			newConfig = MergeConfig(org, local)
+			m.orgURL = url
+		}
+
 		// 新しい形式の設定を検証
 		if err := ValidateConfig(newConfig); err != nil {
 			return fmt.Errorf("invalid config: %w", err)
 		}
 
+		// api_key_vaultのAPIキーをVaultから読み込む（ディスクには書き込まない）
+		if err := resolveSecrets(newConfig); err != nil {
+			return fmt.Errorf("failed to resolve secrets: %w", err)
+		}
+
 		m.newConfig = newConfig
 	}
 
+	// 設定ファイルのAPIキーが出力やログに現れないよう登録する
+	// Webhook URL（Slackなど）はURL自体が認証情報となるため同様に扱う
+	for _, gw := range m.newConfig.Gateways {
+		redact.Register(gw.APIKey)
+	}
+	redact.Register(m.newConfig.Notifications.WebhookURL)
+
+	// ゲートウェイごとの同時リクエスト数とレートの上限、429の再送回数を登録し、どのコマンドのリクエストにも適用する
+	for _, gw := range m.newConfig.Gateways {
+		interval, _ := ratelimit.ParseRate(gw.RateLimit) // 検証済み
+		ratelimit.Register(gw.URL, ratelimit.Limit{MaxConcurrency: gw.MaxConcurrency, Interval: interval, MaxRetries: gw.MaxRetries})
+	}
+
+	// 読み取り専用モードはどのコマンドから読み込んでも有効にする（一度有効にしたら解除しない）
+	if m.newConfig.Global.ReadOnly {
+		readonly.Enable()
+	}
+
 	m.appConfig.ConfigFile = configPath
 	return nil
 }
@@ -132,11 +186,21 @@ func (m *Manager) ResolveConfig(cliArgs *CLIArgs) (*ResolvedConfig, error) {
 		return nil, err
 	}
 
-	// 5. 最終的な設定の検証
+	// 5. フィルタの@名前を名前付きフィルタに展開
+	filter, err := ExpandFilterPresets(resolved.Filter, resolved.FilterPresets)
+	if err != nil {
+		return nil, err
+	}
+	resolved.Filter = filter
+
+	// 6. 最終的な設定の検証
 	if err := m.validateResolvedConfig(resolved); err != nil {
 		return nil, err
 	}
 
+	// 環境変数やコマンドライン引数で指定されたAPIキーも出力から取り除く
+	redact.Register(resolved.Gateway.APIKey)
+
 	return resolved, nil
 }
 
@@ -144,6 +208,8 @@ func (m *Manager) ResolveConfig(cliArgs *CLIArgs) (*ResolvedConfig, error) {
 func (m *Manager) applyDefaults(resolved *ResolvedConfig) error {
 	resolved.OutputFormat = "table"
 	resolved.SortBy = "name"
+	resolved.NumberFormat = numfmt.StyleRaw
+	resolved.TableStyle = config.TableStyleDefault
 	resolved.Cost = &config.CostConfig{
 		WarningThreshold: 0.05,
 		Pricing: map[string]config.Pricing{
@@ -168,6 +234,8 @@ func (m *Manager) applyDefaults(resolved *ResolvedConfig) error {
 	}
 	resolved.Sources["output_format"] = config.SourceDefault
 	resolved.Sources["sort_by"] = config.SourceDefault
+	resolved.Sources["number_format"] = config.SourceDefault
+	resolved.Sources["table_style"] = config.SourceDefault
 	resolved.Sources["cost.warning_threshold"] = config.SourceDefault
 	return nil
 }
@@ -179,9 +247,13 @@ func (m *Manager) applyFileConfig(resolved *ResolvedConfig) error {
 	}
 
 	// グローバル設定を適用
+	if m.newConfig.Global.TableStyle != "" {
+		resolved.TableStyle = m.newConfig.Global.TableStyle
+		resolved.Sources["table_style"] = config.SourceFile
+	}
+
 	if m.newConfig.Global.OutputFormat != "" {
-		resolved.OutputFormat = m.newConfig.Global.OutputFormat
-		resolved.Sources["output_format"] = config.SourceFile
+		setOutputFormat(resolved, m.newConfig.Global.OutputFormat, config.SourceFile)
 	}
 
 	if m.newConfig.Global.SortBy != "" {
@@ -189,16 +261,56 @@ func (m *Manager) applyFileConfig(resolved *ResolvedConfig) error {
 		resolved.Sources["sort_by"] = config.SourceFile
 	}
 
+	if m.newConfig.Global.NumberFormat != "" {
+		resolved.NumberFormat = m.newConfig.Global.NumberFormat
+		resolved.Sources["number_format"] = config.SourceFile
+	}
+
+	if m.newConfig.Global.SpendConfirmThreshold > 0 {
+		resolved.SpendConfirmThreshold = m.newConfig.Global.SpendConfirmThreshold
+		resolved.Sources["spend_confirm_threshold"] = config.SourceFile
+	}
+
+	// 通知設定を適用
+	if m.newConfig.Notifications.WebhookURL != "" {
+		notifications := m.newConfig.Notifications
+		resolved.Notifications = &notifications
+		resolved.Sources["notifications"] = config.SourceFile
+	}
+
+	// フック設定を適用
+	if !m.newConfig.Hooks.IsZero() {
+		hooks := m.newConfig.Hooks
+		resolved.Hooks = &hooks
+		resolved.Sources["hooks"] = config.SourceFile
+	}
+
+	// 外部フォーマッターを適用
+	if len(m.newConfig.Formatters) > 0 {
+		resolved.Formatters = m.newConfig.Formatters
+		resolved.Sources["formatters"] = config.SourceFile
+	}
+
+	// 名前付きフィルタを適用
+	if len(m.newConfig.Filters) > 0 {
+		resolved.FilterPresets = m.newConfig.Filters
+		resolved.Sources["filters"] = config.SourceFile
+	}
+
+	// モデルID正規化設定を適用
+	normalization := m.newConfig.Normalization
+	resolved.Normalization = &normalization
+	if normalization.Dedupe {
+		resolved.Dedupe = true
+		resolved.Sources["dedupe"] = config.SourceFile
+	}
+
 	// デフォルトゲートウェイを適用（まだゲートウェイが設定されていない場合）
 	if resolved.Gateway == nil && m.newConfig.DefaultGateway != "" {
 		for _, gw := range m.newConfig.Gateways {
 			if gw.Name == m.newConfig.DefaultGateway {
-				resolved.Gateway = &config.GatewayConfig{
-					Name:    gw.Name,
-					URL:     gw.URL,
-					APIKey:  gw.APIKey,
-					Timeout: gw.Timeout,
-				}
+				gatewayConfig := m.gatewayConfigFromFile(gw)
+				resolved.Gateway = &gatewayConfig
 				resolved.Gateway.URLSource = config.SourceFile
 				resolved.Gateway.APIKeySource = config.SourceFile
 				resolved.Gateway.TimeoutSource = config.SourceFile
@@ -214,6 +326,34 @@ func (m *Manager) applyFileConfig(resolved *ResolvedConfig) error {
 	return nil
 }
 
+// gatewayConfigFromFile は設定ファイルのゲートウェイ設定を実行時の設定に変換する
+// timeoutsの未設定項目はglobal.timeoutsで補う
+func (m *Manager) gatewayConfigFromFile(gw config.Gateway) config.GatewayConfig {
+	timeouts := gw.Timeouts.Merge(m.newConfig.Global.Timeouts)
+
+	timeout := gw.Timeout
+	if gw.Timeouts.Total > 0 {
+		timeout = gw.Timeouts.Total
+	}
+	if timeout <= 0 {
+		timeout = timeouts.Total
+	}
+
+	return config.GatewayConfig{
+		Name:          gw.Name,
+		URL:           gw.URL,
+		APIKey:        gw.APIKey,
+		Timeout:       timeout,
+		Timeouts:      timeouts,
+		Type:          gw.Type,
+		DefaultModel:  gw.DefaultModel,
+		MaxBodySize:   gw.MaxBodySize,
+		ProbeEndpoint: gw.ProbeEndpoint,
+		Proxy:         gw.Proxy,
+		Failover:      gw.Failover,
+	}
+}
+
 // applyEnvConfig は環境変数から設定を適用する
 func (m *Manager) applyEnvConfig(resolved *ResolvedConfig) error {
 	envConfig := LoadEnvConfig()
@@ -249,8 +389,7 @@ func (m *Manager) applyEnvConfig(resolved *ResolvedConfig) error {
 	}
 
 	if envConfig.OutputFormat != "" {
-		resolved.OutputFormat = envConfig.OutputFormat
-		resolved.Sources["output_format"] = config.SourceEnv
+		setOutputFormat(resolved, envConfig.OutputFormat, config.SourceEnv)
 	}
 
 	if envConfig.SortBy != "" {
@@ -273,6 +412,14 @@ func (m *Manager) applyEnvConfig(resolved *ResolvedConfig) error {
 		resolved.Sources["user_agent"] = config.SourceEnv
 	}
 
+	if envConfig.WebhookURL != "" {
+		if resolved.Notifications == nil {
+			resolved.Notifications = &config.NotificationConfig{}
+		}
+		resolved.Notifications.WebhookURL = envConfig.WebhookURL
+		resolved.Sources["notifications"] = config.SourceEnv
+	}
+
 	return nil
 }
 
@@ -321,8 +468,7 @@ func (m *Manager) applyCLIConfig(resolved *ResolvedConfig, cliArgs *CLIArgs) err
 
 	// その他の設定
 	if cliArgs.OutputFormat != "" {
-		resolved.OutputFormat = cliArgs.OutputFormat
-		resolved.Sources["output_format"] = config.SourceCLI
+		setOutputFormat(resolved, cliArgs.OutputFormat, config.SourceCLI)
 	}
 
 	if cliArgs.SortBy != "" {
@@ -340,6 +486,16 @@ func (m *Manager) applyCLIConfig(resolved *ResolvedConfig, cliArgs *CLIArgs) err
 		resolved.Sources["columns"] = config.SourceCLI
 	}
 
+	if cliArgs.NumberFormat != "" {
+		resolved.NumberFormat = cliArgs.NumberFormat
+		resolved.Sources["number_format"] = config.SourceCLI
+	}
+
+	if cliArgs.Dedupe {
+		resolved.Dedupe = true
+		resolved.Sources["dedupe"] = config.SourceCLI
+	}
+
 	return nil
 }
 
@@ -358,15 +514,20 @@ func (m *Manager) validateResolvedConfig(resolved *ResolvedConfig) error {
 		return fmt.Errorf("invalid gateway URL: %q", resolved.Gateway.URL)
 	}
 
-	// 出力形式の検証
+	// 出力形式の検証（外部フォーマッターの名前も指定できる）
 	if resolved.OutputFormat != "" {
-		validFormats := []string{"table", "json"}
+		validFormats := append([]string{"table", "json"}, formatterNames(resolved.Formatters)...)
 		if !contains(validFormats, resolved.OutputFormat) {
 			return fmt.Errorf("invalid output format: %s (valid: %s)",
 				resolved.OutputFormat, strings.Join(validFormats, ", "))
 		}
 	}
 
+	if !contains(config.ValidTableStyles, resolved.TableStyle) {
+		return fmt.Errorf("invalid table style: %s (valid: %s)",
+			resolved.TableStyle, strings.Join(config.ValidTableStyles, ", "))
+	}
+
 	// CLIで上書きされなかった環境変数の値を検証
 	envConfig := LoadEnvConfig()
 
@@ -391,8 +552,8 @@ func (m *Manager) validateResolvedConfig(resolved *ResolvedConfig) error {
 
 	// OutputFormatがCLIで上書きされていない場合に限り検証
 	if resolved.Sources["output_format"] == config.SourceEnv && envConfig.OutputFormat != "" {
-		validFormats := []string{"table", "json"}
-		if !contains(validFormats, envConfig.OutputFormat) {
+		validFormats := append([]string{"table", "json"}, formatterNames(resolved.Formatters)...)
+		if format, _ := SplitOutputFormat(envConfig.OutputFormat); !contains(validFormats, format) {
 			return fmt.Errorf("invalid LLM_INFO_OUTPUT_FORMAT from environment variables: %s (valid: %s)",
 				envConfig.OutputFormat, strings.Join(validFormats, ", "))
 		}
@@ -401,10 +562,40 @@ func (m *Manager) validateResolvedConfig(resolved *ResolvedConfig) error {
 	return nil
 }
 
+// SplitOutputFormat は"table=grid"のような出力形式を形式とテーブルの罫線の種類に分ける
+// 罫線の種類を指定していない場合は空を返す
+func SplitOutputFormat(format string) (string, string) {
+	if style, ok := strings.CutPrefix(format, "table="); ok {
+		return "table", style
+	}
+	return format, ""
+}
+
+// setOutputFormat は出力形式を設定する。"table=STYLE"の場合はテーブルの罫線の種類も設定する
+func setOutputFormat(resolved *ResolvedConfig, format string, source config.ConfigSource) {
+	format, style := SplitOutputFormat(format)
+	resolved.OutputFormat = format
+	resolved.Sources["output_format"] = source
+	if style != "" {
+		resolved.TableStyle = style
+		resolved.Sources["table_style"] = source
+	}
+}
+
+// OrgConfigURL は重ねた組織の共有設定のURLを返す（使っていなければ空）
+func (m *Manager) OrgConfigURL() string {
+	return m.orgURL
+}
+
 // GetConfigSourceInfo は設定ソース情報を返す
 func (m *Manager) GetConfigSourceInfo(resolved *ResolvedConfig) string {
 	info := "Configuration sources:\n"
 
+	// 設定ファイルの値には組織の共有設定の値も含まれる
+	if m.orgURL != "" {
+		info += fmt.Sprintf("  org config: %s (merged below the config file)\n", redact.String(m.orgURL))
+	}
+
 	// Gatewayの詳細なソース情報
 	if resolved.Gateway != nil {
 		if resolved.Gateway.URLSource > 0 {
@@ -428,6 +619,110 @@ func (m *Manager) GetConfigSourceInfo(resolved *ResolvedConfig) string {
 	return info
 }
 
+// SourceReport は設定項目ごとの設定ソース（--show-sources --format json の出力）
+type SourceReport struct {
+	OrgConfig string        `json:"org_config,omitempty"` // 重ねた組織の共有設定のURL
+	Fields    []FieldSource `json:"fields"`               // キーの順
+}
+
+// FieldSource は設定項目の解決済みの値とその設定ソース
+type FieldSource struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"` // APIキーの値は出力しない
+	Source string `json:"source"`          // default, file, env, cli
+}
+
+// GetConfigSourceReport は設定ソース情報を、セットアップの自動化で検証できる構造で返す
+func (m *Manager) GetConfigSourceReport(resolved *ResolvedConfig) *SourceReport {
+	sources := make(map[string]config.ConfigSource, len(resolved.Sources)+3)
+	for key, source := range resolved.Sources {
+		sources[key] = source
+	}
+	if resolved.Gateway != nil {
+		sources["gateway.url"] = resolved.Gateway.URLSource
+		sources["gateway.api_key"] = resolved.Gateway.APIKeySource
+		sources["gateway.timeout"] = resolved.Gateway.TimeoutSource
+	}
+
+	keys := make([]string, 0, len(sources))
+	for key := range sources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	report := &SourceReport{Fields: make([]FieldSource, 0, len(keys))}
+	if m.orgURL != "" {
+		report.OrgConfig = redact.String(m.orgURL)
+	}
+	for _, key := range keys {
+		report.Fields = append(report.Fields, FieldSource{
+			Key:    key,
+			Value:  resolvedValue(resolved, key),
+			Source: sourceID(sources[key]),
+		})
+	}
+	return report
+}
+
+// resolvedValue は設定項目の解決済みの値を文字列で返す（値を1つの文字列で表せない項目とAPIキーは空）
+func resolvedValue(resolved *ResolvedConfig, key string) string {
+	switch key {
+	case "gateway":
+		if resolved.Gateway != nil {
+			return resolved.Gateway.Name
+		}
+	case "gateway.url":
+		if resolved.Gateway != nil {
+			return redact.String(resolved.Gateway.URL)
+		}
+	case "gateway.timeout":
+		if resolved.Gateway != nil && resolved.Gateway.Timeout != 0 {
+			return resolved.Gateway.Timeout.String()
+		}
+	case "output_format":
+		return resolved.OutputFormat
+	case "sort_by":
+		return resolved.SortBy
+	case "filter":
+		return resolved.Filter
+	case "columns":
+		return resolved.Columns
+	case "number_format":
+		return resolved.NumberFormat
+	case "table_style":
+		return resolved.TableStyle
+	case "log_level":
+		return resolved.LogLevel
+	case "user_agent":
+		return resolved.UserAgent
+	case "dedupe":
+		return fmt.Sprintf("%t", resolved.Dedupe)
+	case "cost.warning_threshold":
+		if resolved.Cost != nil {
+			return fmt.Sprintf("%g", resolved.Cost.WarningThreshold)
+		}
+	case "spend_confirm_threshold":
+		return fmt.Sprintf("%g", resolved.SpendConfirmThreshold)
+	}
+	return ""
+}
+
+// sourceID はConfigSourceをJSON出力用の固定の識別子に変換
+func sourceID(source config.ConfigSource) string {
+	switch source {
+	case config.SourceDefault:
+		return "default"
+	case config.SourceFile:
+		return "file"
+	case config.SourceEnv:
+		return "env"
+	case config.SourceCLI:
+		return "cli"
+	default:
+		return "unknown"
+	}
+}
+
 // getSourceName はConfigSourceを文字列に変換
 func (m *Manager) getSourceName(source config.ConfigSource) string {
 	switch source {
@@ -454,6 +749,8 @@ type CLIArgs struct {
 	SortBy       string
 	Filter       string
 	Columns      string
+	NumberFormat string
+	Dedupe       bool
 }
 
 // ApplyGateway は指定されたゲートウェイ設定を適用します
@@ -464,12 +761,7 @@ func (m *Manager) ApplyGateway(gatewayName string) error {
 	if m.newConfig != nil {
 		// 新しい形式から古い形式に変換
 		for _, gw := range m.newConfig.Gateways {
-			gateways = append(gateways, config.GatewayConfig{
-				Name:    gw.Name,
-				URL:     gw.URL,
-				APIKey:  gw.APIKey,
-				Timeout: gw.Timeout,
-			})
+			gateways = append(gateways, m.gatewayConfigFromFile(gw))
 		}
 
 		// ゲートウェイ名が指定されていない場合はデフォルトを使用
@@ -500,6 +792,12 @@ func (m *Manager) ApplyGateway(gatewayName string) error {
 			if m.appConfig.Timeout == 10*time.Second { // デフォルト値の場合のみ上書き
 				m.appConfig.Timeout = gateway.Timeout
 			}
+			if m.appConfig.Timeouts == (config.Timeouts{}) {
+				m.appConfig.Timeouts = gateway.Timeouts
+			}
+			if m.appConfig.Proxy == "" {
+				m.appConfig.Proxy = gateway.Proxy
+			}
 
 			// グローバル設定を適用（新しい形式の場合）
 			if m.newConfig != nil {
@@ -568,12 +866,7 @@ func (m *Manager) GetGatewayConfig(name string) (*config.GatewayConfig, error) {
 	if m.newConfig != nil {
 		// 新しい形式から古い形式に変換
 		for _, gw := range m.newConfig.Gateways {
-			gateways = append(gateways, config.GatewayConfig{
-				Name:    gw.Name,
-				URL:     gw.URL,
-				APIKey:  gw.APIKey,
-				Timeout: gw.Timeout,
-			})
+			gateways = append(gateways, m.gatewayConfigFromFile(gw))
 		}
 	} else if m.fileConfig != nil {
 		gateways = m.fileConfig.Gateways
@@ -607,12 +900,7 @@ func (m *Manager) ListGateways() []string {
 	if m.newConfig != nil {
 		// 新しい形式から古い形式に変換
 		for _, gw := range m.newConfig.Gateways {
-			gateways = append(gateways, config.GatewayConfig{
-				Name:    gw.Name,
-				URL:     gw.URL,
-				APIKey:  gw.APIKey,
-				Timeout: gw.Timeout,
-			})
+			gateways = append(gateways, m.gatewayConfigFromFile(gw))
 		}
 	} else if m.fileConfig != nil {
 		gateways = m.fileConfig.Gateways
@@ -640,11 +928,7 @@ func (m *Manager) CreateExampleConfig() error {
 
 // GetDefaultConfigPath はデフォルトの設定ファイルパスを返します
 func GetDefaultConfigPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return ""
-	}
-	return filepath.Join(home, ".config", "llm-info", "llm-info.yaml")
+	return paths.ConfigFile("llm-info.yaml")
 }
 
 // ValidateConfig は設定の妥当性を検証します
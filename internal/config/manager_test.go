@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/armaniacs/llm-info/internal/redact"
 )
 
 func TestManager_LoadFromFile(t *testing.T) {
@@ -320,6 +322,52 @@ global:
 	}
 }
 
+func TestManager_LoadRegistersSecrets(t *testing.T) {
+	redact.Reset()
+	defer redact.Reset()
+
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "test-config.yaml")
+
+	configContent := `
+gateways:
+  - name: "first"
+    url: "https://first.example.com"
+    api_key: "first-secret-key"
+    timeout: "5s"
+  - name: "second"
+    url: "https://second.example.com"
+    api_key: "second-secret-key"
+    timeout: "5s"
+default_gateway: "first"
+global:
+  timeout: "10s"
+  output_format: "table"
+  sort_by: "name"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+
+	manager := NewManager(configPath)
+	if err := manager.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	got := redact.String("first-secret-key second-secret-key")
+	if got != "[REDACTED] [REDACTED]" {
+		t.Errorf("API keys from the config file should be redacted, got %q", got)
+	}
+
+	// コマンドライン引数で指定したキーはResolveConfigで登録される
+	if _, err := manager.ResolveConfig(&CLIArgs{Gateway: "first", APIKey: "flag-secret-key"}); err != nil {
+		t.Fatalf("ResolveConfig() error = %v", err)
+	}
+	if got := redact.String("flag-secret-key"); got != "[REDACTED]" {
+		t.Errorf("API key from flags should be redacted, got %q", got)
+	}
+}
+
 func TestManager_GetGatewayConfig(t *testing.T) {
 	// テスト用の設定ファイルを作成
 	tempDir := t.TempDir()
@@ -388,6 +436,218 @@ global:
 	}
 }
 
+func TestManager_GetGatewayConfig_Timeouts(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "test-config.yaml")
+
+	configContent := `
+gateways:
+  - name: "gateway1"
+    url: "https://gateway1.example.com"
+    timeout: "5s"
+    timeouts:
+      dial: "2s"
+      probe: "3m"
+  - name: "gateway2"
+    url: "https://gateway2.example.com"
+    timeouts:
+      total: "20s"
+default_gateway: "gateway1"
+global:
+  timeout: "10s"
+  timeouts:
+    dial: "4s"
+    tls_handshake: "3s"
+  output_format: "table"
+  sort_by: "name"
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+
+	manager := NewManager(configPath)
+	if err := manager.Load(); err != nil {
+		t.Fatalf("Failed to load config file: %v", err)
+	}
+
+	gw1, err := manager.GetGatewayConfig("gateway1")
+	if err != nil {
+		t.Fatalf("GetGatewayConfig(gateway1) error = %v", err)
+	}
+	// ゲートウェイの値が優先され、未設定の項目はglobal.timeoutsで補われる
+	if gw1.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v, want 5s", gw1.Timeout)
+	}
+	if gw1.Timeouts.Dial != 2*time.Second {
+		t.Errorf("Timeouts.Dial = %v, want 2s", gw1.Timeouts.Dial)
+	}
+	if gw1.Timeouts.TLSHandshake != 3*time.Second {
+		t.Errorf("Timeouts.TLSHandshake = %v, want 3s", gw1.Timeouts.TLSHandshake)
+	}
+	if got := gw1.ProbeTimeout(30 * time.Second); got != 3*time.Minute {
+		t.Errorf("ProbeTimeout() = %v, want 3m", got)
+	}
+
+	gw2, err := manager.GetGatewayConfig("gateway2")
+	if err != nil {
+		t.Fatalf("GetGatewayConfig(gateway2) error = %v", err)
+	}
+	// timeouts.totalはtimeoutとして扱われる
+	if gw2.Timeout != 20*time.Second {
+		t.Errorf("Timeout = %v, want 20s", gw2.Timeout)
+	}
+	if got := gw2.ProbeTimeout(30 * time.Second); got != 30*time.Second {
+		t.Errorf("ProbeTimeout() = %v, want fallback 30s", got)
+	}
+}
+
+func TestManager_ResolveConfig_DefaultModel(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "test-config.yaml")
+
+	configContent := `
+gateways:
+  - name: "prod"
+    url: "https://prod.example.com"
+    timeout: "5s"
+    default_model: "gpt-4o-mini"
+  - name: "dev"
+    url: "https://dev.example.com"
+    timeout: "5s"
+default_gateway: "dev"
+global:
+  timeout: "10s"
+  output_format: "table"
+  sort_by: "name"
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+
+	manager := NewManager(configPath)
+	if err := manager.Load(); err != nil {
+		t.Fatalf("Failed to load config file: %v", err)
+	}
+
+	resolved, err := manager.ResolveConfig(&CLIArgs{Gateway: "prod"})
+	if err != nil {
+		t.Fatalf("ResolveConfig(prod) error = %v", err)
+	}
+	if resolved.Gateway.DefaultModel != "gpt-4o-mini" {
+		t.Errorf("DefaultModel = %q, want gpt-4o-mini", resolved.Gateway.DefaultModel)
+	}
+
+	// default_modelを設定していないゲートウェイでは空
+	resolved, err = manager.ResolveConfig(&CLIArgs{})
+	if err != nil {
+		t.Fatalf("ResolveConfig(default) error = %v", err)
+	}
+	if resolved.Gateway.DefaultModel != "" {
+		t.Errorf("DefaultModel = %q, want empty", resolved.Gateway.DefaultModel)
+	}
+}
+
+func TestManager_ResolveConfig_TableStyle(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "test-config.yaml")
+	configContent := `
+gateways:
+  - name: "prod"
+    url: "https://prod.example.com"
+    timeout: "5s"
+default_gateway: "prod"
+global:
+  timeout: "10s"
+  output_format: "table"
+  sort_by: "name"
+  table_style: "plain"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	manager := NewManager(configPath)
+	if err := manager.Load(); err != nil {
+		t.Fatalf("Failed to load config file: %v", err)
+	}
+
+	tests := []struct {
+		format     string
+		wantFormat string
+		wantStyle  string
+		wantErr    bool
+	}{
+		{"", "table", "plain", false},
+		{"table=grid", "table", "grid", false},
+		{"json", "json", "plain", false},
+		{"table=fancy", "", "", true},
+	}
+	for _, tt := range tests {
+		resolved, err := manager.ResolveConfig(&CLIArgs{OutputFormat: tt.format})
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ResolveConfig(%q) error = nil, want error", tt.format)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ResolveConfig(%q) error = %v", tt.format, err)
+		}
+		if resolved.OutputFormat != tt.wantFormat || resolved.TableStyle != tt.wantStyle {
+			t.Errorf("ResolveConfig(%q) = %s, %s, want %s, %s", tt.format, resolved.OutputFormat, resolved.TableStyle, tt.wantFormat, tt.wantStyle)
+		}
+	}
+}
+
+func TestManager_GetConfigSourceReport(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "test-config.yaml")
+	configContent := `
+gateways:
+  - name: "prod"
+    url: "https://prod.example.com"
+    api_key: "sk-report-secret"
+    timeout: "5s"
+default_gateway: "prod"
+global:
+  timeout: "10s"
+  output_format: "table"
+  sort_by: "name"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	manager := NewManager(configPath)
+	if err := manager.Load(); err != nil {
+		t.Fatalf("Failed to load config file: %v", err)
+	}
+	resolved, err := manager.ResolveConfig(&CLIArgs{Filter: "gpt"})
+	if err != nil {
+		t.Fatalf("ResolveConfig() error = %v", err)
+	}
+
+	report := manager.GetConfigSourceReport(resolved)
+	fields := make(map[string]FieldSource)
+	for i, field := range report.Fields {
+		if i > 0 && report.Fields[i-1].Key >= field.Key {
+			t.Errorf("fields are not sorted: %s before %s", report.Fields[i-1].Key, field.Key)
+		}
+		fields[field.Key] = field
+	}
+
+	want := map[string]FieldSource{
+		"gateway":         {Key: "gateway", Value: "prod", Source: "file"},
+		"gateway.url":     {Key: "gateway.url", Value: "https://prod.example.com", Source: "file"},
+		"gateway.api_key": {Key: "gateway.api_key", Source: "file"},
+		"filter":          {Key: "filter", Value: "gpt", Source: "cli"},
+		"number_format":   {Key: "number_format", Value: "raw", Source: "default"},
+	}
+	for key, wantField := range want {
+		if got := fields[key]; got != wantField {
+			t.Errorf("field %s = %+v, want %+v", key, got, wantField)
+		}
+	}
+}
+
 func TestManager_ListGateways(t *testing.T) {
 	// テスト用の設定ファイルを作成
 	tempDir := t.TempDir()
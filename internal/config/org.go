@@ -0,0 +1,189 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/paths"
+	"github.com/armaniacs/llm-info/internal/storage"
+	"github.com/armaniacs/llm-info/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// orgConfigTimeout は組織の共有設定を取得するときのタイムアウト
+const orgConfigTimeout = 10 * time.Second
+
+// orgConfigCacheMeta はキャッシュした共有設定の取得情報
+type orgConfigCacheMeta struct {
+	URL       string    `json:"url"`
+	ETag      string    `json:"etag,omitempty"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// orgConfigURL は共有設定のURLを返す（LLM_INFO_CONFIG_URLがconfig_urlより優先）
+func orgConfigURL(local *config.Config) string {
+	if url := os.Getenv("LLM_INFO_CONFIG_URL"); url != "" {
+		return url
+	}
+	return local.ConfigURL
+}
+
+// FetchOrgConfig は組織の共有設定をURLから取得する
+// 取得した内容はETagと共にcacheDirにキャッシュし、次回はIf-None-Matchで変更がなければキャッシュを使う
+// 取得に失敗した場合はキャッシュがあればそれを使い、警告を表示する
+func FetchOrgConfig(url, cacheDir string) (*config.Config, error) {
+	key := sha256.Sum256([]byte(url))
+	base := filepath.Join(cacheDir, "org-config-"+hex.EncodeToString(key[:8]))
+	bodyPath, metaPath := base+".yaml", base+".json"
+
+	var meta orgConfigCacheMeta
+	cached, cacheErr := os.ReadFile(bodyPath)
+	if cacheErr == nil {
+		if data, err := os.ReadFile(metaPath); err == nil {
+			json.Unmarshal(data, &meta)
+		}
+	}
+
+	body, etag, err := fetchOrgConfigBody(url, meta.ETag, cacheErr == nil)
+	switch {
+	case err != nil && cacheErr == nil:
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch org config from %s, using the copy cached at %s: %v\n",
+			url, meta.FetchedAt.Local().Format("2006-01-02 15:04"), err)
+		body = cached
+	case err != nil:
+		return nil, fmt.Errorf("failed to fetch org config from %s: %w", url, err)
+	case body == nil:
+		// 304 Not Modified
+		body = cached
+	default:
+		if err := saveOrgConfigCache(bodyPath, metaPath, body, orgConfigCacheMeta{URL: url, ETag: etag, FetchedAt: time.Now()}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to cache org config: %v\n", err)
+		}
+	}
+
+	var org config.Config
+	if err := yaml.Unmarshal(body, &org); err != nil {
+		return nil, fmt.Errorf("failed to parse org config from %s: %w", url, err)
+	}
+	// 共有設定から別の共有設定はたどらない
+	org.ConfigURL = ""
+	return &org, nil
+}
+
+// fetchOrgConfigBody は共有設定を取得する。変更がない（304）場合はnilを返す
+func fetchOrgConfigBody(url, etag string, conditional bool) ([]byte, string, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if conditional && etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	client := &http.Client{Timeout: orgConfigTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && conditional {
+		return nil, etag, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, resp.Header.Get("ETag"), nil
+}
+
+// saveOrgConfigCache は取得した共有設定と取得情報をキャッシュに書き込む
+func saveOrgConfigCache(bodyPath, metaPath string, body []byte, meta orgConfigCacheMeta) error {
+	if err := os.MkdirAll(filepath.Dir(bodyPath), 0755); err != nil {
+		return err
+	}
+	// 共有設定にAPIキーが含まれる場合に備え、他のユーザーから読めないようにする
+	if err := os.WriteFile(bodyPath, body, 0600); err != nil {
+		return err
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(metaPath, data, 0600)
+}
+
+// orgConfigCacheDir は共有設定をキャッシュするディレクトリを返す（storage.cache_dir、未設定ならデフォルト）
+func orgConfigCacheDir(local *config.Config) string {
+	if dir := local.Storage.CacheDir; dir != "" {
+		if expanded, err := storage.ExpandPath(dir); err == nil {
+			return expanded
+		}
+		return dir
+	}
+	return paths.CacheDir()
+}
+
+// MergeConfig は共有設定（org）の上にローカルの設定（local）を重ねる
+// ローカルで設定した値（ゼロ値でない値）が優先され、ゲートウェイは名前ごとに項目単位で重ねる
+// そのため共有設定でURLを管理し、ローカルではnameとapi_keyだけを書くことができる
+func MergeConfig(org, local *config.Config) *config.Config {
+	merged := *org
+	mergeValue(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(local).Elem())
+
+	merged.Gateways = append([]config.Gateway(nil), org.Gateways...)
+	for _, gw := range local.Gateways {
+		found := false
+		for i := range merged.Gateways {
+			if merged.Gateways[i].Name == gw.Name {
+				mergeValue(reflect.ValueOf(&merged.Gateways[i]).Elem(), reflect.ValueOf(gw))
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged.Gateways = append(merged.Gateways, gw)
+		}
+	}
+	return &merged
+}
+
+// mergeValue はoverrideのゼロ値でない値をdstに書き込む
+// 構造体は項目ごと、マップはキーごとに重ね、それ以外（スライスを含む）は値ごと置き換える
+func mergeValue(dst, override reflect.Value) {
+	switch dst.Kind() {
+	case reflect.Struct:
+		for i := 0; i < dst.NumField(); i++ {
+			if dst.Type().Field(i).IsExported() {
+				mergeValue(dst.Field(i), override.Field(i))
+			}
+		}
+	case reflect.Map:
+		if override.Len() == 0 {
+			return
+		}
+		merged := reflect.MakeMap(dst.Type())
+		for _, m := range []reflect.Value{dst, override} {
+			iter := m.MapRange()
+			for iter.Next() {
+				merged.SetMapIndex(iter.Key(), iter.Value())
+			}
+		}
+		dst.Set(merged)
+	default:
+		if !override.IsZero() {
+			dst.Set(override)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+const testOrgConfig = `gateways:
+  - name: production
+    url: https://gateway.example.com
+    timeout: 30s
+  - name: staging
+    url: https://staging.example.com
+    timeout: 30s
+default_gateway: production
+global:
+  timeout: 30s
+  output_format: table
+  sort_by: name
+`
+
+func TestFetchOrgConfig_ETagCache(t *testing.T) {
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(testOrgConfig))
+	}))
+	cacheDir := t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		org, err := FetchOrgConfig(server.URL, cacheDir)
+		if err != nil {
+			t.Fatalf("FetchOrgConfig() #%d error = %v", i+1, err)
+		}
+		if len(org.Gateways) != 2 || org.DefaultGateway != "production" {
+			t.Fatalf("FetchOrgConfig() #%d = %+v", i+1, org)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("requests = %d, not modified = %d, want 2 and 1", requests, notModified)
+	}
+
+	// 取得に失敗してもキャッシュを使う
+	server.Close()
+	org, err := FetchOrgConfig(server.URL, cacheDir)
+	if err != nil {
+		t.Fatalf("FetchOrgConfig() with server down error = %v, want cached copy", err)
+	}
+	if len(org.Gateways) != 2 {
+		t.Errorf("cached Gateways = %+v", org.Gateways)
+	}
+
+	if _, err := FetchOrgConfig(server.URL, t.TempDir()); err == nil {
+		t.Error("FetchOrgConfig() without cache and server down error = nil, want error")
+	}
+}
+
+func TestMergeConfig(t *testing.T) {
+	org := &config.Config{
+		Gateways: []config.Gateway{
+			{Name: "production", URL: "https://gateway.example.com", Timeout: 30 * time.Second, Type: "litellm"},
+			{Name: "staging", URL: "https://staging.example.com", Timeout: 30 * time.Second},
+		},
+		DefaultGateway: "production",
+		Global:         config.Global{Timeout: 30 * time.Second, OutputFormat: "table", SortBy: "name"},
+		Formatters:     map[string]config.FormatterConfig{"org": {Command: "org-fmt"}},
+	}
+	local := &config.Config{
+		Gateways: []config.Gateway{
+			{Name: "production", APIKey: "sk-personal"},
+			{Name: "dev", URL: "http://localhost:4000", Timeout: 5 * time.Second},
+		},
+		Global:     config.Global{OutputFormat: "json"},
+		Formatters: map[string]config.FormatterConfig{"mine": {Command: "my-fmt"}},
+	}
+
+	merged := MergeConfig(org, local)
+
+	if len(merged.Gateways) != 3 {
+		t.Fatalf("Gateways = %+v, want production, staging and dev", merged.Gateways)
+	}
+	production := merged.Gateways[0]
+	if production.URL != "https://gateway.example.com" || production.APIKey != "sk-personal" || production.Type != "litellm" {
+		t.Errorf("production = %+v, want org URL and type with the local API key", production)
+	}
+	if merged.Gateways[2].Name != "dev" {
+		t.Errorf("local-only gateway was not appended: %+v", merged.Gateways)
+	}
+	if merged.DefaultGateway != "production" {
+		t.Errorf("DefaultGateway = %q, want org default", merged.DefaultGateway)
+	}
+	if merged.Global.OutputFormat != "json" || merged.Global.Timeout != 30*time.Second {
+		t.Errorf("Global = %+v, want local output_format over org timeout", merged.Global)
+	}
+	if len(merged.Formatters) != 2 {
+		t.Errorf("Formatters = %v, want both", merged.Formatters)
+	}
+	if org.Gateways[0].APIKey != "" {
+		t.Error("MergeConfig() modified the org config")
+	}
+}
+
+func TestManager_LoadWithOrgConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testOrgConfig))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "llm-info.yaml")
+	local := "config_url: " + server.URL + "\n" +
+		"storage:\n  cache_dir: " + filepath.Join(dir, "cache") + "\n" +
+		"gateways:\n  - name: production\n    api_key: sk-personal\n"
+	if err := os.WriteFile(configPath, []byte(local), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewManager(configPath)
+	if err := manager.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	resolved, err := manager.ResolveConfig(&CLIArgs{})
+	if err != nil {
+		t.Fatalf("ResolveConfig() error = %v", err)
+	}
+	if resolved.Gateway.URL != "https://gateway.example.com" || resolved.Gateway.APIKey != "sk-personal" {
+		t.Errorf("Gateway = %+v, want org URL with local API key", resolved.Gateway)
+	}
+}
@@ -1,9 +1,13 @@
 package config
 
 import (
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/armaniacs/llm-info/internal/logging"
+	"github.com/armaniacs/llm-info/internal/storage"
+	"github.com/armaniacs/llm-info/pkg/config"
 )
 
 // ProbeConfig contains configuration specific to probe commands
@@ -27,6 +31,43 @@ type ResultConfig struct {
 	Enabled   bool   `yaml:"enabled" json:"enabled"`
 	Dir       string `yaml:"dir" json:"dir"`
 	Overwrite bool   `yaml:"overwrite" json:"overwrite"`
+	Compress  bool   `yaml:"compress" json:"compress"`
+
+	// Remote is the shared result store; results are mirrored there when a bucket is set
+	Remote config.RemoteConfig `yaml:"remote" json:"remote"`
+}
+
+// StorageOptions returns the options used to open the result storage
+func (c *ResultConfig) StorageOptions() storage.Options {
+	opts := storage.Options{Compress: c.Compress}
+	if c.Remote.Enabled() {
+		opts.Remote = storage.NewS3Store(RemoteS3Config(c.Remote))
+		opts.RemotePrefix = c.Remote.Prefix
+	}
+	return opts
+}
+
+// RemoteS3Config converts the remote settings into an S3 client configuration.
+// Credentials missing from the config file are read from the standard AWS
+// environment variables.
+func RemoteS3Config(remote config.RemoteConfig) storage.S3Config {
+	cfg := storage.S3Config{
+		Endpoint:        remote.Endpoint,
+		Bucket:          remote.Bucket,
+		Region:          remote.Region,
+		AccessKeyID:     remote.AccessKeyID,
+		SecretAccessKey: remote.SecretAccessKey,
+		PathStyle:       remote.PathStyle,
+	}
+	if cfg.Region == "" {
+		cfg.Region = os.Getenv("AWS_REGION")
+	}
+	if cfg.AccessKeyID == "" && cfg.SecretAccessKey == "" {
+		cfg.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		cfg.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		cfg.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	return cfg
 }
 
 // GetDefaultProbeConfig returns default probe configuration
@@ -44,7 +85,7 @@ func GetDefaultProbeConfig() ProbeConfig {
 		},
 		Result: ResultConfig{
 			Enabled:   true,
-			Dir:       "/Users/y-araki/.config/llm-info/estimates",
+			Dir:       storage.GetDefaultResultDir(),
 			Overwrite: false,
 		},
 	}
@@ -60,4 +101,78 @@ func (c *LogConfig) ConvertToProbeLogConfig() logging.ProbeLogConfig {
 		Compress:        c.Compress,
 		Retention:       c.Retention,
 	}
-}
\ No newline at end of file
+}
+// GetProbeConfig returns the default probe configuration with the directories
+// from the storage section of the config file applied
+func (m *Manager) GetProbeConfig() ProbeConfig {
+	probeConfig := GetDefaultProbeConfig()
+
+	storageConfig := m.GetStorageConfig()
+	if storageConfig.ResultDir != "" {
+		probeConfig.Result.Dir = storageConfig.ResultDir
+	}
+	if storageConfig.LogDir != "" {
+		probeConfig.Log.Dir = storageConfig.LogDir
+	}
+	if storageConfig.LogFormat != "" {
+		probeConfig.Log.Format = storageConfig.LogFormat
+	}
+	probeConfig.Result.Compress = storageConfig.Compress
+	probeConfig.Result.Remote = storageConfig.Remote
+
+	return probeConfig
+}
+
+// GetStorageConfig returns the storage section of the config file
+func (m *Manager) GetStorageConfig() config.StorageConfig {
+	if m.newConfig == nil {
+		return config.StorageConfig{}
+	}
+	return m.newConfig.Storage
+}
+
+// GetStatsConfig returns the stats section of the config file
+func (m *Manager) GetStatsConfig() config.StatsConfig {
+	if m.newConfig == nil {
+		return config.StatsConfig{}
+	}
+	return m.newConfig.Stats
+}
+
+// GetProvidersConfig returns the providers section of the config file
+func (m *Manager) GetProvidersConfig() config.ProvidersConfig {
+	if m.newConfig == nil {
+		return config.ProvidersConfig{}
+	}
+	return m.newConfig.Providers
+}
+
+// GetExitCodes returns the exit_codes section of the config file
+func (m *Manager) GetExitCodes() config.ExitCodes {
+	if m.newConfig == nil {
+		return config.ExitCodes{}
+	}
+	return m.newConfig.ExitCodes
+}
+
+// GetPinnedModels returns the model IDs pinned with llm-info pin
+func (m *Manager) GetPinnedModels() []string {
+	if m.newConfig == nil {
+		return nil
+	}
+	return m.newConfig.Pinned
+}
+
+// ToRetentionPolicy converts the retention settings into a storage policy
+func ToRetentionPolicy(retention config.RetentionConfig) (storage.RetentionPolicy, error) {
+	maxTotalSize, err := storage.ParseSize(retention.MaxTotalSize)
+	if err != nil {
+		return storage.RetentionPolicy{}, fmt.Errorf("invalid max_total_size: %w", err)
+	}
+
+	return storage.RetentionPolicy{
+		MaxFiles:     retention.MaxFiles,
+		MaxAge:       retention.MaxAge,
+		MaxTotalSize: maxTotalSize,
+	}, nil
+}
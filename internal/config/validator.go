@@ -3,7 +3,18 @@ package config
 import (
 	"fmt"
 	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/armaniacs/llm-info/internal/logging"
+	"github.com/armaniacs/llm-info/internal/numfmt"
+	"github.com/armaniacs/llm-info/internal/ratelimit"
+	"github.com/armaniacs/llm-info/internal/schedule"
+	"github.com/armaniacs/llm-info/internal/storage"
 	"github.com/armaniacs/llm-info/pkg/config"
 )
 
@@ -33,11 +44,90 @@ func ValidateConfig(cfg *config.Config) error {
 		}
 	}
 
+	// フェイルオーバー先の存在チェック
+	for _, gw := range cfg.Gateways {
+		if err := validateFailover(gw, names); err != nil {
+			return fmt.Errorf("gateway %s: %w", gw.Name, err)
+		}
+	}
+
+	// 共有設定のURLの検証
+	if cfg.ConfigURL != "" && !isValidURL(cfg.ConfigURL) {
+		return fmt.Errorf("config_url %q is not a valid URL", cfg.ConfigURL)
+	}
+
+	// Vault設定の検証
+	if cfg.Vault.Auth != "" && !slices.Contains(config.ValidVaultAuths, cfg.Vault.Auth) {
+		return fmt.Errorf("vault: invalid auth: %s (valid: %s)", cfg.Vault.Auth, strings.Join(config.ValidVaultAuths, ", "))
+	}
+
 	// グローバル設定の検証
-	if err := validateGlobal(&cfg.Global); err != nil {
+	if err := validateGlobal(&cfg.Global, cfg.Formatters); err != nil {
 		return fmt.Errorf("global settings: %w", err)
 	}
 
+	// 通知設定の検証
+	if err := validateNotifications(&cfg.Notifications); err != nil {
+		return fmt.Errorf("notifications: %w", err)
+	}
+
+	// フック設定の検証
+	if err := validateHooks(&cfg.Hooks); err != nil {
+		return fmt.Errorf("hooks: %w", err)
+	}
+
+	// 外部フォーマッターの検証
+	if err := validateFormatters(cfg.Formatters); err != nil {
+		return fmt.Errorf("formatters: %w", err)
+	}
+
+	// 名前付きフィルタの検証
+	if err := validateFilterPresets(cfg.Filters); err != nil {
+		return fmt.Errorf("filters: %w", err)
+	}
+
+	// 保存設定の検証
+	if err := validateStorage(&cfg.Storage); err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
+
+	// モデルID正規化設定の検証
+	if err := validateNormalization(&cfg.Normalization); err != nil {
+		return fmt.Errorf("normalization: %w", err)
+	}
+
+	// プロバイダー名の判定設定の検証
+	if err := validateProviders(&cfg.Providers); err != nil {
+		return fmt.Errorf("providers: %w", err)
+	}
+
+	// デーモン設定の検証
+	if err := validateDaemon(&cfg.Daemon, names); err != nil {
+		return fmt.Errorf("daemon: %w", err)
+	}
+
+	// 終了コードの検証
+	if err := validateExitCodes(&cfg.ExitCodes); err != nil {
+		return fmt.Errorf("exit_codes: %w", err)
+	}
+
+	return nil
+}
+
+// validateExitCodes は終了コードがシェルで扱える範囲（0〜255）かを検証する
+// 上限に0を指定するとエラーでも成功を返してしまうため、上限は1以上とする
+func validateExitCodes(codes *config.ExitCodes) error {
+	for _, code := range []struct {
+		name  string
+		value *int
+	}{{"warning", codes.Warning}, {"error", codes.Error}, {"fatal", codes.Fatal}} {
+		if code.value != nil && (*code.value < 0 || *code.value > 255) {
+			return fmt.Errorf("%s must be between 0 and 255, got %d", code.name, *code.value)
+		}
+	}
+	if codes.Max != nil && (*codes.Max < 1 || *codes.Max > 255) {
+		return fmt.Errorf("max must be between 1 and 255, got %d", *codes.Max)
+	}
 	return nil
 }
 
@@ -55,24 +145,122 @@ func validateGateway(gw *config.Gateway) error {
 		return fmt.Errorf("invalid URL format")
 	}
 
-	if gw.Timeout <= 0 {
+	if gw.Timeout <= 0 && gw.Timeouts.Total <= 0 {
 		return fmt.Errorf("timeout must be positive")
 	}
 
+	if err := validateTimeouts(&gw.Timeouts); err != nil {
+		return err
+	}
+
+	if gw.Type != "" && !slices.Contains(config.ValidGatewayTypes, gw.Type) {
+		return fmt.Errorf("invalid type: %s (valid: %s)", gw.Type, strings.Join(config.ValidGatewayTypes, ", "))
+	}
+
+	if _, err := storage.ParseSize(gw.MaxBodySize); err != nil {
+		return fmt.Errorf("invalid max_body_size: %w", err)
+	}
+
+	if gw.ProbeEndpoint != "" && !slices.Contains(config.ValidProbeEndpoints, gw.ProbeEndpoint) {
+		return fmt.Errorf("invalid probe_endpoint: %s (valid: %s)", gw.ProbeEndpoint, strings.Join(config.ValidProbeEndpoints, ", "))
+	}
+
+	if err := validateProxy(gw.Proxy); err != nil {
+		return err
+	}
+
+	if gw.APIKeyVault != "" {
+		if _, _, err := ParseVaultRef(gw.APIKeyVault); err != nil {
+			return fmt.Errorf("api_key_vault: %w", err)
+		}
+	}
+
+	if gw.MaxConcurrency < 0 {
+		return fmt.Errorf("max_concurrency must not be negative")
+	}
+
+	if _, err := ratelimit.ParseRate(gw.RateLimit); err != nil {
+		return fmt.Errorf("rate_limit: %w", err)
+	}
+
+	if gw.MaxRetries < 0 || gw.MaxRetries > ratelimit.MaxRetries {
+		return fmt.Errorf("max_retries must be between 0 and %d", ratelimit.MaxRetries)
+	}
+
+	return nil
+}
+
+// validateFailover はfailoverに指定したゲートウェイが定義されていて、自身や重複を含まないことを検証する
+func validateFailover(gw config.Gateway, names map[string]bool) error {
+	seen := make(map[string]bool)
+	for _, name := range gw.Failover {
+		switch {
+		case name == gw.Name:
+			return fmt.Errorf("failover cannot include the gateway itself")
+		case !names[name]:
+			return fmt.Errorf("failover gateway '%s' not found", name)
+		case seen[name]:
+			return fmt.Errorf("duplicate failover gateway: %s", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// validateProxy はプロキシの指定を検証する（空は環境変数のプロキシを使う）
+func validateProxy(proxy string) error {
+	if proxy == "" || proxy == config.ProxyDirect {
+		return nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return fmt.Errorf("invalid proxy: %w", err)
+	}
+	if !slices.Contains(config.ValidProxySchemes, u.Scheme) {
+		return fmt.Errorf("invalid proxy scheme: %q (valid: %s, or %s)", u.Scheme, strings.Join(config.ValidProxySchemes, ", "), config.ProxyDirect)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid proxy: host is missing in %s", proxy)
+	}
+	return nil
+}
+
+// validateTimeouts は段階ごとのタイムアウト設定を検証する
+func validateTimeouts(t *config.Timeouts) error {
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"dial", t.Dial},
+		{"tls_handshake", t.TLSHandshake},
+		{"response_header", t.ResponseHeader},
+		{"total", t.Total},
+		{"probe", t.Probe},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value < 0 {
+			return fmt.Errorf("timeouts.%s must not be negative, got: %v", timeout.name, timeout.value)
+		}
+	}
 	return nil
 }
 
 // validateGlobal はグローバル設定を検証する
-func validateGlobal(global *config.Global) error {
-	if global.Timeout <= 0 {
+func validateGlobal(global *config.Global, formatters map[string]config.FormatterConfig) error {
+	if global.Timeout <= 0 && global.Timeouts.Total <= 0 {
 		return fmt.Errorf("global timeout must be positive")
 	}
 
-	// 出力形式の妥当性チェック
-	validFormats := []string{"table", "json"}
+	if err := validateTimeouts(&global.Timeouts); err != nil {
+		return err
+	}
+
+	// 出力形式の妥当性チェック（外部フォーマッターの名前も指定できる）
+	validFormats := append([]string{"table", "json"}, formatterNames(formatters)...)
+	outputFormat, outputStyle := SplitOutputFormat(global.OutputFormat)
 	isValidFormat := false
 	for _, format := range validFormats {
-		if global.OutputFormat == format {
+		if outputFormat == format {
 			isValidFormat = true
 			break
 		}
@@ -94,6 +282,209 @@ func validateGlobal(global *config.Global) error {
 		return fmt.Errorf("invalid sort by: %s (valid options: %v)", global.SortBy, validSortBy)
 	}
 
+	if err := numfmt.Validate(global.NumberFormat); err != nil {
+		return err
+	}
+
+	for _, style := range []string{global.TableStyle, outputStyle} {
+		if style != "" && !contains(config.ValidTableStyles, style) {
+			return fmt.Errorf("invalid table style: %s (valid: %s)", style, strings.Join(config.ValidTableStyles, ", "))
+		}
+	}
+
+	if global.SpendConfirmThreshold < 0 {
+		return fmt.Errorf("spend_confirm_threshold must not be negative")
+	}
+
+	return nil
+}
+
+// validateNotifications は通知設定を検証する
+func validateNotifications(n *config.NotificationConfig) error {
+	if n.WebhookURL == "" {
+		return nil
+	}
+
+	if !isValidURL(n.WebhookURL) {
+		return fmt.Errorf("invalid webhook URL: %q", n.WebhookURL)
+	}
+
+	if n.Format != "" && n.Format != "json" && n.Format != "slack" {
+		return fmt.Errorf("invalid notification format: %s (valid formats: [json slack])", n.Format)
+	}
+
+	validEvents := []string{"catalog_changed", "probe_completed"}
+	for _, event := range n.Events {
+		if !contains(validEvents, event) {
+			return fmt.Errorf("invalid notification event: %s (valid events: %v)", event, validEvents)
+		}
+	}
+
+	if n.Timeout < 0 {
+		return fmt.Errorf("notification timeout must be positive")
+	}
+
+	return nil
+}
+
+// validateStorage は保存設定を検証する
+func validateStorage(s *config.StorageConfig) error {
+	if s.Retention.MaxFiles < 0 {
+		return fmt.Errorf("retention.max_files must not be negative")
+	}
+
+	if s.Retention.MaxAge < 0 {
+		return fmt.Errorf("retention.max_age must not be negative")
+	}
+
+	if _, err := ToRetentionPolicy(s.Retention); err != nil {
+		return fmt.Errorf("retention.%w", err)
+	}
+
+	if err := logging.ValidateFormat(s.LogFormat); err != nil {
+		return fmt.Errorf("log_format: %w", err)
+	}
+
+	if err := validateRemote(&s.Remote); err != nil {
+		return fmt.Errorf("remote.%w", err)
+	}
+
+	return nil
+}
+
+// validateRemote は探索結果を共有するリモート保存先の設定を検証する
+func validateRemote(r *config.RemoteConfig) error {
+	if !r.Enabled() {
+		if r.Endpoint != "" || r.Prefix != "" || r.AccessKeyID != "" || r.SecretAccessKey != "" || r.Lock {
+			return fmt.Errorf("bucket is required when a remote store is configured")
+		}
+		return nil
+	}
+
+	if r.Endpoint != "" {
+		parsed, err := url.Parse(r.Endpoint)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("endpoint must be an http(s) URL: %s", r.Endpoint)
+		}
+	}
+
+	if (r.AccessKeyID == "") != (r.SecretAccessKey == "") {
+		return fmt.Errorf("access_key_id and secret_access_key must be set together")
+	}
+
+	return nil
+}
+
+// validateNormalization はモデルID正規化設定を検証する
+func validateNormalization(n *config.NormalizationConfig) error {
+	for i, rule := range n.Rules {
+		if rule.Pattern == "" {
+			return fmt.Errorf("rule %d: pattern cannot be empty", i+1)
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("rule %d: invalid pattern: %w", i+1, err)
+		}
+	}
+
+	for from, to := range n.Aliases {
+		if from == "" || to == "" {
+			return fmt.Errorf("aliases must not contain empty model IDs")
+		}
+	}
+
+	return nil
+}
+
+// validateProviders はプロバイダー名の判定設定を検証する
+func validateProviders(p *config.ProvidersConfig) error {
+	for host, name := range p.Hosts {
+		if host == "" || name == "" {
+			return fmt.Errorf("hosts must not contain empty hosts or provider names")
+		}
+		if _, err := path.Match(host, ""); err != nil {
+			return fmt.Errorf("invalid host pattern %q: %w", host, err)
+		}
+	}
+
+	for from, to := range p.Aliases {
+		if from == "" || to == "" {
+			return fmt.Errorf("aliases must not contain empty provider names")
+		}
+	}
+
+	return nil
+}
+
+// validateHooks はフック設定を検証する
+func validateHooks(h *config.HooksConfig) error {
+	if h.Timeout < 0 {
+		return fmt.Errorf("hook timeout must be positive")
+	}
+	return nil
+}
+
+// formatterNamePattern は外部フォーマッターの名前（--formatに指定する値）の形式
+var formatterNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// validateFormatters は外部フォーマッターの設定を検証する
+func validateFormatters(formatters map[string]config.FormatterConfig) error {
+	for name, f := range formatters {
+		if !formatterNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid formatter name: %q (use lowercase letters, digits, - and _)", name)
+		}
+		if name == "table" || name == "json" {
+			return fmt.Errorf("formatter name %q conflicts with a built-in format", name)
+		}
+		if f.Command == "" {
+			return fmt.Errorf("formatter %s: command is required", name)
+		}
+		if f.Timeout < 0 {
+			return fmt.Errorf("formatter %s: timeout must be positive", name)
+		}
+	}
+	return nil
+}
+
+// formatterNames は外部フォーマッターの名前をソートして返す
+func formatterNames(formatters map[string]config.FormatterConfig) []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateDaemon はデーモン設定を検証する
+func validateDaemon(d *config.DaemonConfig, gatewayNames map[string]bool) error {
+	if d.Schedule != "" {
+		if _, err := schedule.Parse(d.Schedule); err != nil {
+			return err
+		}
+	}
+
+	if d.Retention < 0 {
+		return fmt.Errorf("retention must be positive")
+	}
+
+	validProbes := []string{"", "all", "context", "max_output"}
+	for i, job := range d.Jobs {
+		if job.Model == "" {
+			return fmt.Errorf("job %d: model cannot be empty", i+1)
+		}
+		if job.Gateway != "" && !gatewayNames[job.Gateway] {
+			return fmt.Errorf("job %d: gateway '%s' not found", i+1, job.Gateway)
+		}
+		if !contains(validProbes, job.Probe) {
+			return fmt.Errorf("job %d: invalid probe type: %s (valid: all, context, max_output)", i+1, job.Probe)
+		}
+		if job.Schedule != "" {
+			if _, err := schedule.Parse(job.Schedule); err != nil {
+				return fmt.Errorf("job %d: %w", i+1, err)
+			}
+		}
+	}
+
 	return nil
 }
 
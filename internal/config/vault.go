@@ -0,0 +1,200 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// vaultTimeout はVaultへのリクエストのタイムアウト
+const vaultTimeout = 10 * time.Second
+
+// ParseVaultRef は"secret/data/llm/prod#key"形式の参照をシークレットのパスとキーに分ける
+func ParseVaultRef(ref string) (path, key string, err error) {
+	path, key, ok := strings.Cut(ref, "#")
+	path = strings.Trim(path, "/")
+	if !ok || path == "" || key == "" {
+		return "", "", fmt.Errorf("invalid vault reference %q (expected path#key such as secret/data/llm/prod#api_key)", ref)
+	}
+	return path, key, nil
+}
+
+// resolveSecrets はapi_key_vaultで指定したAPIキーをVaultから読み込み、api_keyに設定する
+// api_keyが設定されたゲートウェイはVaultに問い合わせない
+func resolveSecrets(cfg *config.Config) error {
+	var client *vaultClient
+	for i := range cfg.Gateways {
+		gw := &cfg.Gateways[i]
+		if gw.APIKeyVault == "" || gw.APIKey != "" {
+			continue
+		}
+		if client == nil {
+			var err error
+			if client, err = newVaultClient(cfg.Vault); err != nil {
+				return fmt.Errorf("gateway %s: api_key_vault: %w", gw.Name, err)
+			}
+		}
+		value, err := client.read(gw.APIKeyVault)
+		if err != nil {
+			return fmt.Errorf("gateway %s: api_key_vault: %w", gw.Name, err)
+		}
+		gw.APIKey = value
+	}
+	return nil
+}
+
+// vaultClient はVaultのKVシークレットを読み込む
+type vaultClient struct {
+	address   string
+	namespace string
+	token     string
+	http      *http.Client
+	secrets   map[string]map[string]interface{} // 同じパスは1度だけ読み込む
+}
+
+// newVaultClient は設定と環境変数からVaultのクライアントを作成し、認証する
+func newVaultClient(vc config.VaultConfig) (*vaultClient, error) {
+	client := &vaultClient{
+		address:   strings.TrimRight(firstNonEmpty(vc.Address, os.Getenv("VAULT_ADDR")), "/"),
+		namespace: firstNonEmpty(vc.Namespace, os.Getenv("VAULT_NAMESPACE")),
+		http:      &http.Client{Timeout: vaultTimeout},
+		secrets:   make(map[string]map[string]interface{}),
+	}
+	if client.address == "" {
+		return nil, fmt.Errorf("vault address is not set (set vault.address or VAULT_ADDR)")
+	}
+
+	switch vc.Auth {
+	case "", config.VaultAuthToken:
+		client.token = os.Getenv("VAULT_TOKEN")
+		if client.token == "" {
+			if home, err := os.UserHomeDir(); err == nil {
+				if data, err := os.ReadFile(filepath.Join(home, ".vault-token")); err == nil {
+					client.token = strings.TrimSpace(string(data))
+				}
+			}
+		}
+		if client.token == "" {
+			return nil, fmt.Errorf("vault token is not set (set VAULT_TOKEN or run vault login)")
+		}
+	case config.VaultAuthAppRole:
+		if err := client.loginAppRole(vc); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid vault auth: %s (valid: %s)", vc.Auth, strings.Join(config.ValidVaultAuths, ", "))
+	}
+	return client, nil
+}
+
+// loginAppRole はRole IDとSecret IDでログインしてトークンを取得する
+func (c *vaultClient) loginAppRole(vc config.VaultConfig) error {
+	roleID := firstNonEmpty(vc.RoleID, os.Getenv("VAULT_ROLE_ID"))
+	secretID := os.Getenv("VAULT_SECRET_ID")
+	if roleID == "" || secretID == "" {
+		return fmt.Errorf("approle auth requires a role ID (vault.role_id or VAULT_ROLE_ID) and VAULT_SECRET_ID")
+	}
+	mount := strings.Trim(firstNonEmpty(vc.AppRoleMount, "approle"), "/")
+
+	body, _ := json.Marshal(map[string]string{"role_id": roleID, "secret_id": secretID})
+	var resp struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	if err := c.do(http.MethodPost, "auth/"+mount+"/login", body, &resp); err != nil {
+		return fmt.Errorf("approle login failed: %w", err)
+	}
+	if resp.Auth.ClientToken == "" {
+		return fmt.Errorf("approle login failed: no client token in response")
+	}
+	c.token = resp.Auth.ClientToken
+	return nil
+}
+
+// read は"path#key"で指定したシークレットの値を返す
+// KV v2（secret/data/...）のdata.dataとKV v1のdataのどちらにも対応する
+func (c *vaultClient) read(ref string) (string, error) {
+	path, key, err := ParseVaultRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	data, ok := c.secrets[path]
+	if !ok {
+		var resp struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		if err := c.do(http.MethodGet, path, nil, &resp); err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		data = resp.Data
+		if nested, ok := data["data"].(map[string]interface{}); ok {
+			if _, hasMetadata := data["metadata"]; hasMetadata {
+				data = nested
+			}
+		}
+		c.secrets[path] = data
+	}
+
+	value, ok := data[key].(string)
+	if !ok || value == "" {
+		return "", fmt.Errorf("key %q not found in %s", key, path)
+	}
+	return value, nil
+}
+
+// do はVaultのAPIを呼び出し、レスポンスのJSONをoutに読み込む
+func (c *vaultClient) do(method, path string, body []byte, out interface{}) error {
+	req, err := http.NewRequest(method, c.address+"/v1/"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("X-Vault-Token", c.token)
+	}
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Errors []string `json:"errors"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		if len(errResp.Errors) > 0 {
+			return fmt.Errorf("vault returned %s: %s", resp.Status, strings.Join(errResp.Errors, "; "))
+		}
+		return fmt.Errorf("vault returned %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse vault response: %w", err)
+	}
+	return nil
+}
+
+// firstNonEmpty は空でない最初の値を返す
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
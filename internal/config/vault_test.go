@@ -0,0 +1,145 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// newTestVault はKV v2（secret/data/llm/prod）とKV v1（kv/llm）のシークレットを返すVaultを起動する
+func newTestVault(t *testing.T, reads *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/auth/approle/login" {
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["role_id"] != "role" || body["secret_id"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+				return
+			}
+			w.Write([]byte(`{"auth":{"client_token":"approle-token"}}`))
+			return
+		}
+		if token := r.Header.Get("X-Vault-Token"); token != "root-token" && token != "approle-token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		*reads++
+		switch r.URL.Path {
+		case "/v1/secret/data/llm/prod":
+			w.Write([]byte(`{"data":{"data":{"key":"sk-prod","admin":"sk-admin"},"metadata":{"version":3}}}`))
+		case "/v1/kv/llm":
+			w.Write([]byte(`{"data":{"key":"sk-v1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestResolveSecrets(t *testing.T) {
+	reads := 0
+	server := newTestVault(t, &reads)
+	t.Setenv("VAULT_TOKEN", "root-token")
+
+	cfg := &config.Config{
+		Vault: config.VaultConfig{Address: server.URL},
+		Gateways: []config.Gateway{
+			{Name: "prod", APIKeyVault: "secret/data/llm/prod#key"},
+			{Name: "admin", APIKeyVault: "secret/data/llm/prod#admin"},
+			{Name: "legacy", APIKeyVault: "kv/llm#key"},
+			{Name: "local", APIKey: "sk-local", APIKeyVault: "secret/data/llm/missing#key"},
+		},
+	}
+	if err := resolveSecrets(cfg); err != nil {
+		t.Fatalf("resolveSecrets() error = %v", err)
+	}
+
+	want := []string{"sk-prod", "sk-admin", "sk-v1", "sk-local"}
+	for i, gw := range cfg.Gateways {
+		if gw.APIKey != want[i] {
+			t.Errorf("%s: APIKey = %q, want %q", gw.Name, gw.APIKey, want[i])
+		}
+	}
+	// 同じパスは1度だけ読み込み、api_keyがあるゲートウェイは問い合わせない
+	if reads != 2 {
+		t.Errorf("vault reads = %d, want 2", reads)
+	}
+}
+
+func TestResolveSecrets_AppRole(t *testing.T) {
+	reads := 0
+	server := newTestVault(t, &reads)
+	t.Setenv("VAULT_TOKEN", "")
+	t.Setenv("VAULT_SECRET_ID", "secret")
+
+	cfg := &config.Config{
+		Vault:    config.VaultConfig{Address: server.URL, Auth: config.VaultAuthAppRole, RoleID: "role"},
+		Gateways: []config.Gateway{{Name: "prod", APIKeyVault: "secret/data/llm/prod#key"}},
+	}
+	if err := resolveSecrets(cfg); err != nil {
+		t.Fatalf("resolveSecrets() error = %v", err)
+	}
+	if cfg.Gateways[0].APIKey != "sk-prod" {
+		t.Errorf("APIKey = %q, want sk-prod", cfg.Gateways[0].APIKey)
+	}
+
+	t.Setenv("VAULT_SECRET_ID", "wrong")
+	cfg.Gateways[0].APIKey = ""
+	err := resolveSecrets(cfg)
+	if err == nil || !strings.Contains(err.Error(), "invalid role or secret ID") {
+		t.Errorf("resolveSecrets() with wrong secret ID error = %v", err)
+	}
+}
+
+func TestResolveSecrets_Errors(t *testing.T) {
+	reads := 0
+	server := newTestVault(t, &reads)
+
+	tests := []struct {
+		name    string
+		token   string
+		ref     string
+		address string
+		wantErr string
+	}{
+		{"missing key", "root-token", "secret/data/llm/prod#missing", server.URL, `key "missing" not found`},
+		{"missing path", "root-token", "secret/data/llm/none#key", server.URL, "404"},
+		{"denied", "bad-token", "secret/data/llm/prod#key", server.URL, "permission denied"},
+		{"no address", "root-token", "secret/data/llm/prod#key", "", "vault address is not set"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("VAULT_TOKEN", tt.token)
+			t.Setenv("VAULT_ADDR", "")
+			cfg := &config.Config{
+				Vault:    config.VaultConfig{Address: tt.address},
+				Gateways: []config.Gateway{{Name: "prod", APIKeyVault: tt.ref}},
+			}
+			err := resolveSecrets(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("resolveSecrets() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseVaultRef(t *testing.T) {
+	path, key, err := ParseVaultRef("/secret/data/llm/prod#key")
+	if err != nil || path != "secret/data/llm/prod" || key != "key" {
+		t.Errorf("ParseVaultRef() = %q, %q, %v", path, key, err)
+	}
+	for _, ref := range []string{"secret/data/llm/prod", "#key", "secret/data/llm/prod#"} {
+		if _, _, err := ParseVaultRef(ref); err == nil {
+			t.Errorf("ParseVaultRef(%q) error = nil, want error", ref)
+		}
+	}
+}
@@ -2,21 +2,21 @@ package cost
 
 // UsageSummary はAPI使用量の集計結果
 type UsageSummary struct {
-	TotalCost         float64
-	TotalInputTokens  int
-	TotalOutputTokens int
-	TotalTokens       int
-	WarningTriggered  bool
-	UnknownModelUsed  bool
-	BreakdownByProbe  map[string]*ProbeUsage
+	TotalCost         float64                `json:"total"`
+	TotalInputTokens  int                    `json:"input_tokens"`
+	TotalOutputTokens int                    `json:"output_tokens"`
+	TotalTokens       int                    `json:"total_tokens"`
+	WarningTriggered  bool                   `json:"warning_triggered"`
+	UnknownModelUsed  bool                   `json:"unknown_model"`
+	BreakdownByProbe  map[string]*ProbeUsage `json:"breakdown,omitempty"`
 }
 
 // ProbeUsage はプローブごとの使用量
 type ProbeUsage struct {
-	Cost         float64
-	InputTokens  int
-	OutputTokens int
-	Trials       int
+	Cost         float64 `json:"cost"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	Trials       int     `json:"trials"`
 }
 
 // TrialUsage は試行ごとの使用量情報（import cycleを避けるための独立構造体）
@@ -142,3 +142,28 @@ func EstimateUsage(
 
 	return summary
 }
+
+// EstimateFromTrials はDry-run用に、模擬実行した試行からプローブごとの概算を計算する
+// probeTrialsのキーはBreakdownByProbeのキー（context、max_output、max_input）
+// 課金されない試行（拒否されたリクエスト）はトークン数0で渡し、試行回数にだけ数える
+func EstimateFromTrials(calculator *Calculator, modelName string, probeTrials map[string][]TrialUsage) *UsageSummary {
+	summary := &UsageSummary{
+		BreakdownByProbe: make(map[string]*ProbeUsage),
+	}
+
+	_, exists := calculator.models[modelName]
+	summary.UnknownModelUsed = !exists
+
+	for probeName, trials := range probeTrials {
+		usage := aggregateProbeTrials(calculator, modelName, trials)
+		summary.BreakdownByProbe[probeName] = usage
+		summary.TotalInputTokens += usage.InputTokens
+		summary.TotalOutputTokens += usage.OutputTokens
+		summary.TotalCost += usage.Cost
+	}
+
+	summary.TotalTokens = summary.TotalInputTokens + summary.TotalOutputTokens
+	summary.WarningTriggered = summary.TotalCost > calculator.warningThreshold
+
+	return summary
+}
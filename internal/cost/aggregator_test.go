@@ -144,3 +144,40 @@ func TestAggregateProbeTrials(t *testing.T) {
 		t.Errorf("Expected positive cost, got %f", usage.Cost)
 	}
 }
+
+func TestEstimateFromTrials(t *testing.T) {
+	costConfig := &config.CostConfig{
+		WarningThreshold: 0.05,
+		Pricing: map[string]config.Pricing{
+			"gpt-4": {InputPricePer1K: 0.03, OutputPricePer1K: 0.06},
+		},
+	}
+	calculator := NewCalculator(costConfig, "gpt-4")
+
+	summary := EstimateFromTrials(calculator, "gpt-4", map[string][]TrialUsage{
+		"context": {
+			{PromptTokens: 4096, CompletionTokens: 16},
+			{}, // 拒否された試行は課金されない
+		},
+		"max_input": {
+			{PromptTokens: 1000, CompletionTokens: 1},
+		},
+	})
+
+	if got := summary.BreakdownByProbe["context"].Trials; got != 2 {
+		t.Errorf("context trials = %d, want 2", got)
+	}
+	if summary.TotalInputTokens != 5096 || summary.TotalOutputTokens != 17 {
+		t.Errorf("tokens = %d/%d, want 5096/17", summary.TotalInputTokens, summary.TotalOutputTokens)
+	}
+	want := 5096*0.03/1000 + 17*0.06/1000
+	if diff := summary.TotalCost - want; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("TotalCost = %f, want %f", summary.TotalCost, want)
+	}
+	if !summary.WarningTriggered {
+		t.Error("expected the warning threshold to be exceeded")
+	}
+	if summary.UnknownModelUsed {
+		t.Error("gpt-4 has pricing")
+	}
+}
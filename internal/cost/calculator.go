@@ -43,6 +43,34 @@ func (c *Calculator) SetWarningThreshold(threshold float64) {
 	}
 }
 
+// SetFallbackPricing sets the pricing of a model that has none in the
+// configured pricing table, e.g. from the gateway's model catalog.
+// Configured pricing always wins.
+func (c *Calculator) SetFallbackPricing(modelName string, pricing config.Pricing) {
+	if _, exists := c.models[modelName]; exists {
+		return
+	}
+	// Copy so the configured pricing table is not modified
+	models := make(map[string]config.Pricing, len(c.models)+1)
+	for name, p := range c.models {
+		models[name] = p
+	}
+	models[modelName] = pricing
+	c.models = models
+}
+
+// PricingFor returns the pricing used for a model and whether it is known;
+// unknown models are priced at the default rate
+func (c *Calculator) PricingFor(modelName string) (config.Pricing, bool) {
+	if pricing, exists := c.models[modelName]; exists {
+		return pricing, true
+	}
+	return config.Pricing{
+		InputPricePer1K:  0.00015,
+		OutputPricePer1K: 0.0006,
+	}, false
+}
+
 // CalculateTrialCost calculates the cost for a single trial
 func (c *Calculator) CalculateTrialCost(inputTokens, outputTokens int, modelName string) float64 {
 	pricing, exists := c.models[modelName]
@@ -125,3 +125,30 @@ func TestFormatTokenCount(t *testing.T) {
 		t.Errorf("Expected %s, got %s", expected, formatted)
 	}
 }
+
+func TestSetFallbackPricing(t *testing.T) {
+	costConfig := &config.CostConfig{
+		Pricing: map[string]config.Pricing{
+			"gpt-4": {InputPricePer1K: 0.03, OutputPricePer1K: 0.06},
+		},
+	}
+	calculator := NewCalculator(costConfig, "gpt-4")
+
+	// 設定された料金は上書きしない
+	calculator.SetFallbackPricing("gpt-4", config.Pricing{InputPricePer1K: 1, OutputPricePer1K: 1})
+	if pricing, known := calculator.PricingFor("gpt-4"); !known || pricing.InputPricePer1K != 0.03 {
+		t.Errorf("PricingFor(gpt-4) = %+v, %v", pricing, known)
+	}
+
+	if _, known := calculator.PricingFor("claude-3-haiku"); known {
+		t.Error("claude-3-haiku should be unknown before the fallback is set")
+	}
+	calculator.SetFallbackPricing("claude-3-haiku", config.Pricing{InputPricePer1K: 0.00025, OutputPricePer1K: 0.00125})
+	if pricing, known := calculator.PricingFor("claude-3-haiku"); !known || pricing.OutputPricePer1K != 0.00125 {
+		t.Errorf("PricingFor(claude-3-haiku) = %+v, %v", pricing, known)
+	}
+	// 設定の料金表は変更しない
+	if _, exists := costConfig.Pricing["claude-3-haiku"]; exists {
+		t.Error("SetFallbackPricing should not modify the configured pricing")
+	}
+}
@@ -0,0 +1,83 @@
+package cost
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+// DailySpend は1日・1モデル分の利用額
+type DailySpend struct {
+	Date        string  `json:"date"`
+	Model       string  `json:"model"`
+	Requests    int     `json:"requests,omitempty"` // 日ごとの集計形式のログでは0
+	TotalTokens int     `json:"total_tokens,omitempty"`
+	Spend       float64 `json:"spend"`
+}
+
+// SummarizeSpendLogs はLiteLLMの利用ログを日付とモデルごとに集計する
+// 新しい日付から順に、同じ日付内は利用額の大きい順に並べる
+func SummarizeSpendLogs(logs []api.SpendLog) []DailySpend {
+	type key struct{ date, model string }
+	totals := make(map[key]*DailySpend)
+	add := func(date, model string) *DailySpend {
+		if model == "" {
+			model = "unknown"
+		}
+		k := key{date, model}
+		if totals[k] == nil {
+			totals[k] = &DailySpend{Date: date, Model: model}
+		}
+		return totals[k]
+	}
+
+	for _, log := range logs {
+		date := spendDate(log.StartTime)
+		if len(log.Models) > 0 {
+			// 日ごとの集計形式
+			for model, spend := range log.Models {
+				add(date, model).Spend += spend
+			}
+			continue
+		}
+		entry := add(date, log.Model)
+		entry.Requests++
+		entry.TotalTokens += log.TotalTokens
+		entry.Spend += log.Spend
+	}
+
+	summary := make([]DailySpend, 0, len(totals))
+	for _, entry := range totals {
+		summary = append(summary, *entry)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Date != summary[j].Date {
+			return summary[i].Date > summary[j].Date
+		}
+		if summary[i].Spend != summary[j].Spend {
+			return summary[i].Spend > summary[j].Spend
+		}
+		return summary[i].Model < summary[j].Model
+	})
+	return summary
+}
+
+// TotalSpend は集計結果の合計額を返す
+func TotalSpend(summary []DailySpend) float64 {
+	var total float64
+	for _, entry := range summary {
+		total += entry.Spend
+	}
+	return total
+}
+
+// spendDate はstartTime（"2024-06-01T12:00:00Z" や "2024-06-01"）から日付部分を取り出す
+func spendDate(startTime string) string {
+	if startTime == "" {
+		return "unknown"
+	}
+	date, _, _ := strings.Cut(startTime, "T")
+	date, _, _ = strings.Cut(date, " ")
+	return date
+}
@@ -0,0 +1,43 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+func TestSummarizeSpendLogs(t *testing.T) {
+	logs := []api.SpendLog{
+		{StartTime: "2024-06-01T10:00:00Z", Model: "gpt-4o", Spend: 0.02, TotalTokens: 1000},
+		{StartTime: "2024-06-01T11:00:00Z", Model: "gpt-4o", Spend: 0.03, TotalTokens: 1500},
+		{StartTime: "2024-06-01T12:00:00Z", Model: "gpt-4o-mini", Spend: 0.001, TotalTokens: 800},
+		{StartTime: "2024-06-02 09:00:00", Model: "gpt-4o", Spend: 0.01, TotalTokens: 400},
+		{StartTime: "2024-05-31", Models: map[string]float64{"gpt-4o": 0.5, "claude-3-5-sonnet": 0.25}, Spend: 0.75},
+	}
+
+	summary := SummarizeSpendLogs(logs)
+
+	want := []DailySpend{
+		{Date: "2024-06-02", Model: "gpt-4o", Requests: 1, TotalTokens: 400, Spend: 0.01},
+		{Date: "2024-06-01", Model: "gpt-4o", Requests: 2, TotalTokens: 2500, Spend: 0.05},
+		{Date: "2024-06-01", Model: "gpt-4o-mini", Requests: 1, TotalTokens: 800, Spend: 0.001},
+		{Date: "2024-05-31", Model: "gpt-4o", Spend: 0.5},
+		{Date: "2024-05-31", Model: "claude-3-5-sonnet", Spend: 0.25},
+	}
+	if len(summary) != len(want) {
+		t.Fatalf("SummarizeSpendLogs() returned %d rows, want %d: %+v", len(summary), len(want), summary)
+	}
+	for i := range want {
+		got := summary[i]
+		if got.Date != want[i].Date || got.Model != want[i].Model || got.Requests != want[i].Requests || got.TotalTokens != want[i].TotalTokens {
+			t.Errorf("row %d = %+v, want %+v", i, got, want[i])
+		}
+		if diff := got.Spend - want[i].Spend; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("row %d spend = %v, want %v", i, got.Spend, want[i].Spend)
+		}
+	}
+
+	if total := TotalSpend(summary); total < 0.810999 || total > 0.811001 {
+		t.Errorf("TotalSpend() = %v, want 0.811", total)
+	}
+}
@@ -0,0 +1,169 @@
+// Package demo は認証情報なしでllm-infoの機能を試すための組み込みのデモ環境を提供する
+//
+// 実際のゲートウェイに近いモデル一覧（fixtures/models.json）を返すメモリ上の偽ゲートウェイと、
+// あらかじめ用意した探索結果（fixtures/results.json）を使う。llm-info demo、ドキュメントのスクリーンショット、
+// テストから同じデータで動作を確認できる。
+package demo
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/internal/cache"
+	"github.com/armaniacs/llm-info/internal/paths"
+	"github.com/armaniacs/llm-info/internal/probe"
+	"github.com/armaniacs/llm-info/internal/storage"
+)
+
+//go:embed fixtures/models.json fixtures/results.json
+var fixtures embed.FS
+
+// GatewayName はデモ環境の設定ファイルに登録するゲートウェイ名
+const GatewayName = "demo"
+
+// APIKey はデモ環境のゲートウェイに送るAPIキー（偽ゲートウェイは値を検証しない）
+const APIKey = "demo-key"
+
+// Model はデモのモデル一覧の1件（偽ゲートウェイが受け付ける上限値を含む）
+type Model struct {
+	ID              string  `json:"id"`
+	MaxTokens       int     `json:"max_tokens"`
+	MaxInputTokens  int     `json:"max_input_tokens"`
+	MaxOutputTokens int     `json:"max_output_tokens"`
+	Mode            string  `json:"mode"`
+	InputCost       float64 `json:"input_cost"`
+	OutputCost      float64 `json:"output_cost"`
+	Provider        string  `json:"litellm_provider"`
+}
+
+// modelsFile はfixtures/models.jsonの形式（/model/infoのレスポンスと同じ）
+type modelsFile struct {
+	Models []Model `json:"models"`
+}
+
+// ModelInfoJSON は偽ゲートウェイが/model/infoで返すレスポンスボディを返す
+func ModelInfoJSON() []byte {
+	data, err := fixtures.ReadFile("fixtures/models.json")
+	if err != nil {
+		panic(fmt.Sprintf("demo: missing embedded fixture: %v", err))
+	}
+	return data
+}
+
+// Models はデモのモデル一覧を返す
+func Models() []Model {
+	var file modelsFile
+	if err := json.Unmarshal(ModelInfoJSON(), &file); err != nil {
+		panic(fmt.Sprintf("demo: invalid models fixture: %v", err))
+	}
+	return file.Models
+}
+
+// Results はあらかじめ用意した探索結果（スキーマv2）を返す
+func Results() []*probe.Result {
+	data, err := fixtures.ReadFile("fixtures/results.json")
+	if err != nil {
+		panic(fmt.Sprintf("demo: missing embedded fixture: %v", err))
+	}
+	var results []*probe.Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		panic(fmt.Sprintf("demo: invalid results fixture: %v", err))
+	}
+	return results
+}
+
+// Environment はデモ用の設定・キャッシュ・状態のディレクトリ
+// 利用者の設定ファイルや保存済みの結果に触れないよう、一時ディレクトリの下にまとめる
+type Environment struct {
+	Root       string
+	GatewayURL string
+}
+
+// ConfigDir はデモ環境の設定ディレクトリを返す
+func (e *Environment) ConfigDir() string {
+	return filepath.Join(e.Root, "config")
+}
+
+// CacheDir はデモ環境のキャッシュディレクトリを返す
+func (e *Environment) CacheDir() string {
+	return filepath.Join(e.Root, "cache")
+}
+
+// StateDir はデモ環境の状態ディレクトリを返す
+func (e *Environment) StateDir() string {
+	return filepath.Join(e.Root, "state")
+}
+
+// Env はllm-infoをデモ環境で実行するための環境変数を返す
+func (e *Environment) Env() []string {
+	return []string{
+		paths.EnvConfigDir + "=" + e.ConfigDir(),
+		paths.EnvCacheDir + "=" + e.CacheDir(),
+		paths.EnvStateDir + "=" + e.StateDir(),
+	}
+}
+
+// Prepare はrootの下にデモ環境を作成する
+// 偽ゲートウェイを登録した設定ファイル、--offline用のモデル一覧のキャッシュ、あらかじめ用意した探索結果を書き込む
+func Prepare(root, gatewayURL string) (*Environment, error) {
+	env := &Environment{Root: root, GatewayURL: gatewayURL}
+	for _, dir := range []string{env.ConfigDir(), env.CacheDir(), env.StateDir()} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create demo directory: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(env.ConfigDir(), "llm-info.yaml"), []byte(configYAML(gatewayURL)), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write demo config: %w", err)
+	}
+
+	var response api.ModelInfoResponse
+	if err := json.NewDecoder(bytes.NewReader(ModelInfoJSON())).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to read demo models: %w", err)
+	}
+	if err := cache.NewCatalogCache(env.CacheDir()).Save(GatewayName, gatewayURL, response.Models); err != nil {
+		return nil, err
+	}
+
+	resultStorage, err := storage.NewResultStorageWithOptions(filepath.Join(env.StateDir(), "estimates"), storage.Options{})
+	if err != nil {
+		return nil, err
+	}
+	providers := make(map[string]string)
+	for _, m := range Models() {
+		providers[m.ID] = m.Provider
+	}
+	for _, result := range Results() {
+		save := resultStorage.SaveContextResult
+		if result.Type == probe.ProbeTypeMaxOutput {
+			save = resultStorage.SaveMaxOutputResult
+		}
+		if err := save(providers[result.Model], result.Model, result); err != nil {
+			return nil, fmt.Errorf("failed to save demo result: %w", err)
+		}
+	}
+	return env, nil
+}
+
+// configYAML はデモ環境の設定ファイルの内容を返す
+func configYAML(gatewayURL string) string {
+	return fmt.Sprintf(`# llm-info demo の設定ファイル（終了時に削除される）
+gateways:
+  - name: %q
+    url: %q
+    api_key: %q
+    timeout: "10s"
+    type: "litellm"
+    default_model: "gpt-4o-mini"
+default_gateway: %q
+global:
+  timeout: "10s"
+  output_format: "table"
+  sort_by: "name"
+`, GatewayName, gatewayURL, APIKey, GatewayName)
+}
@@ -0,0 +1,139 @@
+package demo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/internal/cache"
+	"github.com/armaniacs/llm-info/internal/config"
+	"github.com/armaniacs/llm-info/internal/storage"
+	pkgconfig "github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestFixtures(t *testing.T) {
+	models := Models()
+	if len(models) == 0 {
+		t.Fatal("Models() returned no models")
+	}
+	known := make(map[string]bool)
+	for _, m := range models {
+		if m.ID == "" || m.Provider == "" || m.Mode == "" {
+			t.Errorf("model %+v is missing id, provider or mode", m)
+		}
+		known[m.ID] = true
+	}
+	for _, result := range Results() {
+		if !known[result.Model] {
+			t.Errorf("result for unknown model %s", result.Model)
+		}
+		if result.Value <= 0 || !result.Success {
+			t.Errorf("result for %s has no value", result.Model)
+		}
+	}
+}
+
+func TestGateway_ListsModels(t *testing.T) {
+	server := httptest.NewServer(NewGateway())
+	defer server.Close()
+
+	client := api.NewClient(config.New(server.URL, APIKey, 5*time.Second))
+	response, err := client.FetchModelsWithFallback()
+	if err != nil {
+		t.Fatalf("FetchModelsWithFallback() error = %v", err)
+	}
+	if len(response.Models) != len(Models()) {
+		t.Errorf("got %d models, want %d", len(response.Models), len(Models()))
+	}
+}
+
+func TestGateway_EnforcesLimits(t *testing.T) {
+	server := httptest.NewServer(NewGateway())
+	defer server.Close()
+
+	client := api.NewProbeClient(&pkgconfig.AppConfig{BaseURL: server.URL, APIKey: APIKey, Timeout: 5 * time.Second})
+
+	tests := []struct {
+		name      string
+		model     string
+		prompt    string
+		maxTokens int
+		wantError string
+	}{
+		{"accepted", "gpt-4o", "hello", 16, ""},
+		{"context exceeded", "gpt-4o", strings.Repeat("word ", 110000), 16, "maximum context length is 128000 tokens"},
+		{"output exceeded", "gpt-4o", "hello", 32768, "supports at most 16384 completion tokens"},
+		{"anthropic format", "claude-sonnet-4", "hello", 100000, "maximum allowed number of output tokens"},
+		{"enforced below the advertised limit", "llama-3.1-70b-instruct", strings.Repeat("word ", 30000), 16, "maximum model length of 32768"},
+		{"unknown model", "no-such-model", "hello", 16, "does not exist"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := client.ProbeMessages(tt.model, []api.Message{{Role: "user", Content: tt.prompt}}, tt.maxTokens)
+			if tt.wantError == "" {
+				if err != nil {
+					t.Fatalf("ProbeMessages() error = %v", err)
+				}
+				if response.Usage == nil || response.Usage.PromptTokens == 0 {
+					t.Errorf("response has no usage: %+v", response)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("ProbeMessages() error = %v, want %q", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestGateway_UnknownEndpoint(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	NewGateway().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/v1/embeddings", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	root := t.TempDir()
+	env, err := Prepare(root, "http://127.0.0.1:1")
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+
+	manager := config.NewManager(filepath.Join(env.ConfigDir(), "llm-info.yaml"))
+	if err := manager.Load(); err != nil {
+		t.Fatalf("demo config is not valid: %v", err)
+	}
+	resolved, err := manager.ResolveConfig(&config.CLIArgs{})
+	if err != nil {
+		t.Fatalf("ResolveConfig() error = %v", err)
+	}
+	if resolved.Gateway.URL != "http://127.0.0.1:1" {
+		t.Errorf("demo gateway URL = %s", resolved.Gateway.URL)
+	}
+
+	entry, err := cache.NewCatalogCache(env.CacheDir()).Load("http://127.0.0.1:1")
+	if err != nil {
+		t.Fatalf("catalog cache not written: %v", err)
+	}
+	if len(entry.Models) != len(Models()) {
+		t.Errorf("cached %d models, want %d", len(entry.Models), len(Models()))
+	}
+
+	index, err := storage.LoadIndex(filepath.Join(env.StateDir(), "estimates"))
+	if err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+	if len(index.Entries) != len(Results()) {
+		t.Errorf("saved %d results, want %d", len(index.Entries), len(Results()))
+	}
+
+	if got := env.Env(); len(got) != 3 || !strings.HasPrefix(got[0], "LLM_INFO_CONFIG_DIR=") {
+		t.Errorf("Env() = %v", got)
+	}
+}
@@ -0,0 +1,266 @@
+package demo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// enforcedContext は公開しているmax_input_tokensより小さいコンテキスト長で運用しているモデル
+// 実際のゲートウェイでよくある食い違い（vLLMのmax_model_lenを小さくしている）を再現し、verifyなどで確認できるようにする
+var enforcedContext = map[string]int{
+	"llama-3.1-70b-instruct": 32768,
+}
+
+// demoBudget は/key/infoで返す仮想キーの予算（USD）
+const demoBudget = 50.0
+
+// demoReply は探索リクエストに返す本文
+const demoReply = "This is a reply from the llm-info demo gateway."
+
+// Gateway はOpenAI互換とLiteLLMのエンドポイントを持つメモリ上の偽ゲートウェイ
+// モデルごとの上限を超えるリクエストには、実際のプロバイダーと同じ形式のエラーメッセージを返す
+type Gateway struct {
+	models map[string]Model
+	order  []string
+
+	mu       sync.Mutex
+	spend    float64 // 受け付けたリクエストの料金の合計（/key/infoで返す）
+	requests int
+}
+
+// NewGateway は組み込みのモデル一覧を返す偽ゲートウェイを作成する
+func NewGateway() *Gateway {
+	g := &Gateway{models: make(map[string]Model)}
+	for _, m := range Models() {
+		g.models[m.ID] = m
+		g.order = append(g.order, m.ID)
+	}
+	return g
+}
+
+// Requests は受け付けた探索リクエストの数を返す
+func (g *Gateway) Requests() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.requests
+}
+
+// Start はループバックアドレスの空いているポートでゲートウェイを起動し、ベースURLと停止する関数を返す
+func (g *Gateway) Start() (string, func(), error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to start demo gateway: %w", err)
+	}
+	server := &http.Server{Handler: g, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	return "http://" + listener.Addr().String(), func() { server.Close() }, nil
+}
+
+// ServeHTTP はllm-infoが使うエンドポイントに応答する
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/models":
+		g.handleModels(w)
+	case r.Method == http.MethodGet && r.URL.Path == "/model/info":
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(ModelInfoJSON())
+	case r.Method == http.MethodGet && r.URL.Path == "/model_group/info":
+		g.handleModelGroups(w)
+	case r.Method == http.MethodGet && r.URL.Path == "/health":
+		g.handleHealth(w)
+	case r.Method == http.MethodGet && r.URL.Path == "/key/info":
+		g.handleKeyInfo(w)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/chat/completions":
+		g.handleChat(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "not_found_error", fmt.Sprintf("%s %s is not available on the demo gateway", r.Method, r.URL.Path))
+	}
+}
+
+// handleModels はOpenAI互換の/v1/modelsに応答する
+func (g *Gateway) handleModels(w http.ResponseWriter) {
+	data := make([]map[string]interface{}, 0, len(g.order))
+	for _, id := range g.order {
+		data = append(data, map[string]interface{}{
+			"id":       id,
+			"object":   "model",
+			"created":  1727740800,
+			"owned_by": g.models[id].Provider,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"object": "list", "data": data})
+}
+
+// handleModelGroups はLiteLLMの/model_group/infoに応答する
+func (g *Gateway) handleModelGroups(w http.ResponseWriter) {
+	data := make([]map[string]interface{}, 0, len(g.order))
+	for _, id := range g.order {
+		data = append(data, map[string]interface{}{
+			"model_group": id,
+			"providers":   []string{g.models[id].Provider},
+			"mode":        g.models[id].Mode,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+// handleHealth はLiteLLMの/healthに応答する（すべてのデプロイメントを正常とする）
+func (g *Gateway) handleHealth(w http.ResponseWriter) {
+	endpoints := make([]map[string]interface{}, 0, len(g.order))
+	for _, id := range g.order {
+		endpoints = append(endpoints, map[string]interface{}{
+			"model":               g.models[id].Provider + "/" + id,
+			"custom_llm_provider": g.models[id].Provider,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"healthy_endpoints":   endpoints,
+		"unhealthy_endpoints": []interface{}{},
+		"healthy_count":       len(endpoints),
+		"unhealthy_count":     0,
+	})
+}
+
+// handleKeyInfo はLiteLLMの/key/infoに応答する（このゲートウェイで使った料金を返す）
+func (g *Gateway) handleKeyInfo(w http.ResponseWriter) {
+	g.mu.Lock()
+	spend := g.spend
+	g.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"key": "sk-...demo",
+		"info": map[string]interface{}{
+			"key_alias":  "llm-info-demo",
+			"spend":      spend,
+			"max_budget": demoBudget,
+			"models":     g.order,
+		},
+	})
+}
+
+// chatRequest は/v1/chat/completionsのリクエストのうち、上限の判定に使うフィールド
+type chatRequest struct {
+	Model     string `json:"model"`
+	MaxTokens int    `json:"max_tokens"`
+	Messages  []struct {
+		Content string `json:"content"`
+	} `json:"messages"`
+}
+
+// handleChat は/v1/chat/completionsに応答する
+// 入力と出力のトークン数をモデルの上限と比べ、超えていればプロバイダーと同じ形式のエラーを返す
+func (g *Gateway) handleChat(w http.ResponseWriter, r *http.Request) {
+	var req chatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("invalid JSON body: %v", err))
+		return
+	}
+	m, ok := g.models[req.Model]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found_error", fmt.Sprintf("The model `%s` does not exist on the demo gateway", req.Model))
+		return
+	}
+	if m.Mode != "chat" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("%s is a %s model and does not support chat completions", m.ID, m.Mode))
+		return
+	}
+
+	promptTokens := 0
+	for _, message := range req.Messages {
+		promptTokens += CountTokens(message.Content) + 3
+	}
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = 16
+	}
+
+	if message, rejected := m.limitError(promptTokens, maxTokens); rejected {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", message)
+		return
+	}
+
+	completionTokens := min(maxTokens, CountTokens(demoReply))
+	g.mu.Lock()
+	g.requests++
+	g.spend += float64(promptTokens)*m.InputCost + float64(completionTokens)*m.OutputCost
+	g.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":      "chatcmpl-demo",
+		"object":  "chat.completion",
+		"created": time.Now().Unix(),
+		"model":   m.ID,
+		"choices": []map[string]interface{}{{
+			"index":         0,
+			"message":       map[string]string{"role": "assistant", "content": demoReply},
+			"finish_reason": "stop",
+		}},
+		"usage": map[string]int{
+			"prompt_tokens":     promptTokens,
+			"completion_tokens": completionTokens,
+			"total_tokens":      promptTokens + completionTokens,
+		},
+	})
+}
+
+// limitError はリクエストがモデルの上限を超えていれば、プロバイダーの形式のエラーメッセージを返す
+func (m Model) limitError(promptTokens, maxTokens int) (string, bool) {
+	if m.MaxOutputTokens > 0 && maxTokens > m.MaxOutputTokens {
+		if m.Provider == "anthropic" {
+			return fmt.Sprintf("max_tokens: %d > %d, which is the maximum allowed number of output tokens for %s", maxTokens, m.MaxOutputTokens, m.ID), true
+		}
+		return fmt.Sprintf("max_tokens is too large: %d. This model supports at most %d completion tokens, whereas you provided %d.", maxTokens, m.MaxOutputTokens, maxTokens), true
+	}
+
+	limit := m.MaxInputTokens
+	if enforced, ok := enforcedContext[m.ID]; ok {
+		limit = enforced
+	}
+	if limit <= 0 || promptTokens+maxTokens <= limit {
+		return "", false
+	}
+	switch m.Provider {
+	case "anthropic":
+		return fmt.Sprintf("prompt is too long: %d tokens > %d maximum", promptTokens+maxTokens, limit), true
+	case "vertex_ai":
+		return fmt.Sprintf("The input token count (%d) exceeds the maximum number of tokens allowed (%d).", promptTokens, limit), true
+	case "hosted_vllm":
+		return fmt.Sprintf("The decoder prompt (length %d) is longer than the maximum model length of %d.", promptTokens, limit), true
+	default:
+		return fmt.Sprintf("This model's maximum context length is %d tokens. However, your messages resulted in %d tokens.", limit, promptTokens+maxTokens), true
+	}
+}
+
+// CountTokens はテキストのトークン数を概算する
+// ASCIIは4バイトで1トークン、日本語などそれ以外の文字は3バイトで4トークンとする
+// 探索のテストデータ（目標トークン数の3/4バイトの日本語）が、おおむね目標どおりのトークン数になる
+func CountTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range strings.TrimSpace(text) {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other += utf8.RuneLen(r)
+		}
+	}
+	return (ascii+3)/4 + (other*4+2)/3
+}
+
+// writeJSON はvalueをJSONで書き込む
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeError はOpenAI形式のエラーを書き込む
+func writeError(w http.ResponseWriter, status int, errorType, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]string{"message": message, "type": errorType, "code": ""},
+	})
+}
@@ -0,0 +1,372 @@
+// Package doctor は設定・ネットワーク・ゲートウェイ・保存先を診断するチェックを提供する
+package doctor
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	internalConfig "github.com/armaniacs/llm-info/internal/config"
+	errhandler "github.com/armaniacs/llm-info/internal/error"
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/internal/storage"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// Status はチェック結果の状態
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// certExpiryWarning は証明書の有効期限切れを警告する残り期間
+const certExpiryWarning = 14 * 24 * time.Hour
+
+// envVars は診断対象の環境変数
+var envVars = []string{
+	"LLM_INFO_URL",
+	"LLM_INFO_API_KEY",
+	"LLM_INFO_TIMEOUT",
+	"LLM_INFO_DEFAULT_GATEWAY",
+	"LLM_INFO_OUTPUT_FORMAT",
+	"LLM_INFO_CONFIG_PATH",
+}
+
+// Result は1つのチェックの結果
+type Result struct {
+	Name      string   `json:"name"`
+	Status    Status   `json:"status"`
+	Detail    string   `json:"detail"`
+	Solutions []string `json:"solutions,omitempty"`
+}
+
+// Doctor は診断チェックを実行する
+type Doctor struct {
+	solutions *errhandler.SolutionProvider
+}
+
+// New は新しいDoctorを作成する
+func New() *Doctor {
+	return &Doctor{solutions: errhandler.NewSolutionProvider()}
+}
+
+// CheckConfig は設定ファイルを読み込み、検証する
+// 設定ファイルがない場合は環境変数やフラグだけで動作するため警告とする
+func (d *Doctor) CheckConfig(path string) Result {
+	result := Result{Name: "config file"}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		result.Status = StatusWarn
+		result.Detail = fmt.Sprintf("%s not found; only environment variables and flags are used", path)
+		result.Solutions = []string{"llm-info init で対話形式に設定を作成してください"}
+		return result
+	}
+
+	cfg, err := internalConfig.LoadConfigFromFile(path)
+	if err == nil {
+		err = internalConfig.ValidateConfig(cfg)
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Detail = err.Error()
+		result.Solutions = d.solutions.GetConfigSolutions(path)
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Detail = fmt.Sprintf("%s: %d gateway(s)", path, len(cfg.Gateways))
+	if cfg.DefaultGateway != "" {
+		result.Detail += fmt.Sprintf(", default %s", cfg.DefaultGateway)
+	}
+	return result
+}
+
+// CheckEnv は環境変数の値を検証する
+func (d *Doctor) CheckEnv() Result {
+	result := Result{Name: "environment"}
+
+	var set []string
+	for _, name := range envVars {
+		if _, ok := os.LookupEnv(name); ok {
+			set = append(set, name)
+		}
+	}
+
+	err := internalConfig.ValidateEnvVars()
+	if err == nil {
+		if envURL := os.Getenv("LLM_INFO_URL"); envURL != "" {
+			err = validateURL(envURL)
+			if err != nil {
+				err = fmt.Errorf("invalid LLM_INFO_URL value: %s (%w)", envURL, err)
+			}
+		}
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Detail = err.Error()
+		result.Solutions = []string{
+			"環境変数の値を確認してください",
+			"環境変数の一覧を確認してください: llm-info --help config",
+		}
+		return result
+	}
+
+	result.Status = StatusPass
+	if len(set) == 0 {
+		result.Detail = "no LLM_INFO_* variables set"
+	} else {
+		result.Detail = strings.Join(set, ", ") + " set"
+	}
+	return result
+}
+
+// CheckGateway はゲートウェイに対してDNS解決・TLSハンドシェイク・認証・モデル一覧の取得を順に確認する
+// 前の段階が失敗した場合、以降のチェックはスキップする
+func (d *Doctor) CheckGateway(gw *config.GatewayConfig) []Result {
+	dnsResult := Result{Name: "DNS"}
+	tlsResult := Result{Name: "TLS"}
+	authResult := Result{Name: "auth"}
+	modelsResult := Result{Name: "models endpoint"}
+	results := func() []Result {
+		return []Result{dnsResult, tlsResult, authResult, modelsResult}
+	}
+	skipRest := func(from int, reason string) []Result {
+		all := results()
+		for i := from; i < len(all); i++ {
+			all[i].Status = StatusSkip
+			all[i].Detail = reason
+		}
+		return all
+	}
+
+	parsed, err := url.Parse(gw.URL)
+	if err == nil {
+		err = validateURL(gw.URL)
+	}
+	if err != nil {
+		dnsResult.Status = StatusFail
+		dnsResult.Detail = fmt.Sprintf("invalid URL %q: %v", gw.URL, err)
+		dnsResult.Solutions = d.solutions.GetUserSolutions("invalid_argument", gw.URL)
+		return skipRest(1, "invalid URL")
+	}
+
+	timeout := gw.Timeouts.Total
+	if timeout <= 0 {
+		timeout = gw.Timeout
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	dnsResult = d.checkDNS(parsed.Hostname(), gw.URL, timeout)
+	if dnsResult.Status == StatusFail {
+		return skipRest(1, "DNS resolution failed")
+	}
+
+	if parsed.Scheme == "https" {
+		tlsResult = d.checkTLS(parsed, gw.URL, timeout)
+		if tlsResult.Status == StatusFail {
+			return skipRest(2, "TLS handshake failed")
+		}
+	} else {
+		tlsResult.Status = StatusSkip
+		tlsResult.Detail = "plain HTTP"
+	}
+
+	authResult, modelsResult = d.checkAPI(gw, timeout)
+	return results()
+}
+
+// checkDNS はホスト名を解決できるか確認する
+func (d *Doctor) checkDNS(host, gatewayURL string, timeout time.Duration) Result {
+	result := Result{Name: "DNS"}
+	if net.ParseIP(host) != nil {
+		result.Status = StatusPass
+		result.Detail = fmt.Sprintf("%s is an IP address", host)
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return d.failure(result, err, gatewayURL)
+	}
+
+	result.Status = StatusPass
+	result.Detail = fmt.Sprintf("%s resolved to %s", host, strings.Join(addrs, ", "))
+	return result
+}
+
+// checkTLS はTLSハンドシェイクを行い、証明書の有効期限を確認する
+func (d *Doctor) checkTLS(parsed *url.URL, gatewayURL string, timeout time.Duration) Result {
+	result := Result{Name: "TLS"}
+
+	host := parsed.Host
+	if parsed.Port() == "" {
+		host = net.JoinHostPort(parsed.Hostname(), "443")
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", host, &tls.Config{ServerName: parsed.Hostname()})
+	if err != nil {
+		return d.failure(result, err, gatewayURL)
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	result.Status = StatusPass
+	result.Detail = tls.VersionName(state.Version)
+	if len(state.PeerCertificates) > 0 {
+		notAfter := state.PeerCertificates[0].NotAfter
+		result.Detail += fmt.Sprintf(", certificate valid until %s", notAfter.Format("2006-01-02"))
+		if time.Until(notAfter) < certExpiryWarning {
+			result.Status = StatusWarn
+			result.Solutions = []string{"ゲートウェイのTLS証明書を更新してください"}
+		}
+	}
+	return result
+}
+
+// checkAPI はモデル一覧のエンドポイントで認証とレスポンスを確認する
+// OpenAI標準エンドポイントが使えない場合はLiteLLMの/model/infoを試す
+func (d *Doctor) checkAPI(gw *config.GatewayConfig, timeout time.Duration) (Result, Result) {
+	auth := Result{Name: "auth"}
+	models := Result{Name: "models endpoint"}
+
+	cfg := internalConfig.New(gw.URL, gw.APIKey, timeout)
+	cfg.Timeouts = gw.Timeouts
+	cfg.Proxy = gw.Proxy
+	client := api.NewClient(cfg)
+
+	var count int
+	var err error
+	endpoint := "/v1/models"
+	standard, standardErr := client.FetchStandardModels()
+	if standardErr == nil {
+		count = len(standard.Data)
+	} else {
+		endpoint = "/model/info"
+		info, infoErr := client.GetModelInfo()
+		if infoErr == nil {
+			count = len(info.Models)
+		} else if _, code := errhandler.DetectErrorType(standardErr); code == "endpoint_not_found" {
+			err = infoErr
+		} else {
+			err = standardErr
+		}
+	}
+
+	if err != nil {
+		_, code := errhandler.DetectErrorType(err)
+		if code == "authentication_failed" || code == "authorization_failed" {
+			auth = d.failure(auth, err, gw.URL)
+			if gw.APIKey == "" {
+				auth.Detail = "no API key configured: " + auth.Detail
+			}
+			models.Status = StatusSkip
+			models.Detail = "authentication failed"
+			return auth, models
+		}
+		auth.Status = StatusSkip
+		auth.Detail = "gateway did not answer the models request"
+		return auth, d.failure(models, err, gw.URL)
+	}
+
+	auth.Status = StatusPass
+	if gw.APIKey == "" {
+		auth.Detail = "no API key configured; gateway allows anonymous access"
+	} else {
+		auth.Detail = "API key accepted"
+	}
+
+	models.Status = StatusPass
+	models.Detail = fmt.Sprintf("%d model(s) via %s", count, endpoint)
+	if count == 0 {
+		models.Status = StatusWarn
+		models.Solutions = []string{"ゲートウェイにモデルが設定されているか確認してください"}
+	}
+	return auth, models
+}
+
+// CheckWritable はディレクトリにファイルを書き込めるか確認する
+// ディレクトリが存在しない場合は、作成先となる既存の親ディレクトリを確認する
+func (d *Doctor) CheckWritable(name, dir string) Result {
+	result := Result{Name: name}
+
+	expanded, err := storage.ExpandPath(dir)
+	if err != nil {
+		result.Status = StatusFail
+		result.Detail = err.Error()
+		return result
+	}
+
+	target := expanded
+	for {
+		if info, err := os.Stat(target); err == nil {
+			if !info.IsDir() {
+				result.Status = StatusFail
+				result.Detail = fmt.Sprintf("%s is not a directory", target)
+				result.Solutions = d.solutions.GetSystemSolutions("permission_denied", target)
+				return result
+			}
+			break
+		}
+		parent := filepath.Dir(target)
+		if parent == target {
+			break
+		}
+		target = parent
+	}
+
+	file, err := os.CreateTemp(target, ".llm-info-doctor-*")
+	if err != nil {
+		result.Status = StatusFail
+		result.Detail = fmt.Sprintf("%s is not writable: %v", target, err)
+		result.Solutions = d.solutions.GetSystemSolutions("permission_denied", target)
+		return result
+	}
+	file.Close()
+	os.Remove(file.Name())
+
+	result.Status = StatusPass
+	result.Detail = expanded
+	if target != expanded {
+		result.Detail += " (will be created on first use)"
+	}
+	return result
+}
+
+// failure はエラーから失敗の結果を作成し、SolutionProviderの解決策を付ける
+func (d *Doctor) failure(result Result, err error, gatewayURL string) Result {
+	appErr := d.solutions.EnhanceError(errhandler.WrapErrorWithDetection(err, gatewayURL))
+	result.Status = StatusFail
+	result.Detail = redact.String(err.Error())
+	result.Solutions = appErr.Solutions
+	return result
+}
+
+// validateURL はゲートウェイURLの形式を検証する
+func validateURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("URL must start with http:// or https://")
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("URL must contain a valid host")
+	}
+	return nil
+}
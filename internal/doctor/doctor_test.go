@@ -0,0 +1,171 @@
+package doctor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestCheckConfig(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	os.WriteFile(valid, []byte(`gateways:
+  - name: local
+    url: http://127.0.0.1:4000
+    timeout: 10s
+default_gateway: local
+global:
+  timeout: 10s
+  output_format: table
+  sort_by: name
+`), 0600)
+	broken := filepath.Join(dir, "broken.yaml")
+	os.WriteFile(broken, []byte("gateways: [\n"), 0600)
+
+	tests := []struct {
+		name string
+		path string
+		want Status
+	}{
+		{"valid config", valid, StatusPass},
+		{"missing config", filepath.Join(dir, "missing.yaml"), StatusWarn},
+		{"broken config", broken, StatusFail},
+	}
+
+	d := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := d.CheckConfig(tt.path)
+			if result.Status != tt.want {
+				t.Errorf("Status = %s, want %s (%s)", result.Status, tt.want, result.Detail)
+			}
+			if result.Status != StatusPass && len(result.Solutions) == 0 {
+				t.Error("expected remediation hints")
+			}
+		})
+	}
+}
+
+func TestCheckEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout string
+		url     string
+		want    Status
+	}{
+		{"valid", "15s", "https://gateway.example.com", StatusPass},
+		{"invalid timeout", "soon", "", StatusFail},
+		{"invalid url", "", "gateway.example.com", StatusFail},
+	}
+
+	d := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LLM_INFO_TIMEOUT", tt.timeout)
+			t.Setenv("LLM_INFO_URL", tt.url)
+			if result := d.CheckEnv(); result.Status != tt.want {
+				t.Errorf("Status = %s, want %s (%s)", result.Status, tt.want, result.Detail)
+			}
+		})
+	}
+}
+
+func TestCheckGateway(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"object":"list","data":[{"id":"gpt-4o","object":"model"}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		gw   config.GatewayConfig
+		want []Status // DNS, TLS, auth, models endpoint
+	}{
+		{
+			name: "healthy gateway",
+			gw:   config.GatewayConfig{URL: server.URL, APIKey: "good-key", Timeout: 5 * time.Second},
+			want: []Status{StatusPass, StatusSkip, StatusPass, StatusPass},
+		},
+		{
+			name: "rejected API key",
+			gw:   config.GatewayConfig{URL: server.URL, APIKey: "bad-key", Timeout: 5 * time.Second},
+			want: []Status{StatusPass, StatusSkip, StatusFail, StatusSkip},
+		},
+		{
+			name: "invalid URL",
+			gw:   config.GatewayConfig{URL: "gateway.example.com"},
+			want: []Status{StatusFail, StatusSkip, StatusSkip, StatusSkip},
+		},
+	}
+
+	d := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := d.CheckGateway(&tt.gw)
+			if len(results) != len(tt.want) {
+				t.Fatalf("got %d results, want %d", len(results), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if results[i].Status != want {
+					t.Errorf("%s: Status = %s, want %s (%s)", results[i].Name, results[i].Status, want, results[i].Detail)
+				}
+				if results[i].Status == StatusFail && len(results[i].Solutions) == 0 {
+					t.Errorf("%s: expected remediation hints", results[i].Name)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckGateway_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"object":"list","data":[]}`))
+	}))
+	defer server.Close()
+
+	// テストサーバーの証明書は信頼されていないため、ハンドシェイクは失敗として報告される
+	results := New().CheckGateway(&config.GatewayConfig{URL: server.URL, Timeout: 5 * time.Second})
+	if results[1].Status != StatusFail {
+		t.Errorf("TLS Status = %s, want %s (%s)", results[1].Status, StatusFail, results[1].Detail)
+	}
+	if results[2].Status != StatusSkip || results[3].Status != StatusSkip {
+		t.Errorf("checks after a failed handshake should be skipped: %+v", results[2:])
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, nil, 0600)
+
+	tests := []struct {
+		name string
+		dir  string
+		want Status
+	}{
+		{"existing directory", dir, StatusPass},
+		{"directory to be created", filepath.Join(dir, "a", "b"), StatusPass},
+		{"path is a file", file, StatusFail},
+	}
+
+	d := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := d.CheckWritable("dir", tt.dir); result.Status != tt.want {
+				t.Errorf("Status = %s, want %s (%s)", result.Status, tt.want, result.Detail)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a")); !os.IsNotExist(err) {
+		t.Error("CheckWritable should not create missing directories")
+	}
+}
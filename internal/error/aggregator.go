@@ -0,0 +1,120 @@
+package error
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+// maxListedTargets は集約したエラーごとに表示する対象の最大数（--verboseではすべて表示する）
+const maxListedTargets = 10
+
+// ErrorGroup は同じ内容のエラーと、そのエラーが発生した対象（ゲートウェイやモデル）
+type ErrorGroup struct {
+	Err     *AppError // 最初に発生したエラー（解決策の表示に使う）
+	Targets []string
+	Causes  []string // 対象ごとの原因エラー（Targetsと同じ順序、APIキーは伏せる）
+}
+
+// Aggregator は多数のゲートウェイやモデルで発生したエラーを同じ内容ごとにまとめる
+// 同じエラーが何十回も表示されないよう、種別・コード・メッセージが同じAppErrorを1つのグループにする
+type Aggregator struct {
+	groups []*ErrorGroup
+	index  map[string]*ErrorGroup
+	total  int
+}
+
+// NewAggregator は新しいAggregatorを作成する
+func NewAggregator() *Aggregator {
+	return &Aggregator{index: make(map[string]*ErrorGroup)}
+}
+
+// Add は対象で発生したエラーを追加する（AppErrorでない場合はエラー種別を検出してラップする）
+func (a *Aggregator) Add(target string, err error) {
+	if err == nil {
+		return
+	}
+	var appErr *AppError
+	if !AsAppError(err, &appErr) {
+		appErr = WrapErrorWithDetection(err, target)
+	}
+
+	key := fmt.Sprintf("%d\x00%s\x00%s", appErr.Type, appErr.Code, appErr.Message)
+	group, ok := a.index[key]
+	if !ok {
+		group = &ErrorGroup{Err: appErr}
+		a.index[key] = group
+		a.groups = append(a.groups, group)
+	}
+	cause := ""
+	if appErr.OriginalErr != nil {
+		cause = redact.String(appErr.OriginalErr.Error())
+	}
+	group.Targets = append(group.Targets, target)
+	group.Causes = append(group.Causes, cause)
+	a.total++
+}
+
+// Groups はエラーのグループを最初に発生した順に返す
+func (a *Aggregator) Groups() []*ErrorGroup {
+	return a.groups
+}
+
+// Len は追加されたエラーの総数を返す
+func (a *Aggregator) Len() int {
+	return a.total
+}
+
+// FormatAggregate は集約したエラーをグループごとに1回ずつ、件数と対象の一覧とともにフォーマットする
+// verboseの場合は対象ごとの原因エラーもすべて表示する
+func FormatAggregate(a *Aggregator, verbose bool) string {
+	var builder strings.Builder
+
+	for i, group := range a.groups {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(fmt.Sprintf("❌ %s (%s) ×%d\n", redact.String(group.Err.Message), group.Err.Code, len(group.Targets)))
+
+		if verbose {
+			builder.WriteString("   対象:\n")
+			for j, target := range group.Targets {
+				if group.Causes[j] != "" {
+					builder.WriteString(fmt.Sprintf("     - %s: %s\n", target, group.Causes[j]))
+				} else {
+					builder.WriteString(fmt.Sprintf("     - %s\n", target))
+				}
+			}
+		} else {
+			targets := group.Targets
+			more := ""
+			if len(targets) > maxListedTargets {
+				more = fmt.Sprintf(" ほか%d件", len(targets)-maxListedTargets)
+				targets = targets[:maxListedTargets]
+			}
+			builder.WriteString(fmt.Sprintf("   対象: %s%s\n", strings.Join(targets, ", "), more))
+		}
+
+		if len(group.Err.Solutions) > 0 {
+			builder.WriteString("   💡 解決策:\n")
+			for j, solution := range group.Err.Solutions {
+				builder.WriteString(fmt.Sprintf("     %d. %s\n", j+1, solution))
+			}
+		}
+	}
+
+	if !verbose && a.total > len(a.groups) {
+		builder.WriteString("\n原因エラーの詳細は --verbose で表示できます\n")
+	}
+	return builder.String()
+}
+
+// HandleAggregate は集約したエラーを標準エラー出力に表示する
+func (h *Handler) HandleAggregate(a *Aggregator) {
+	if a == nil || a.Len() == 0 {
+		return
+	}
+	fmt.Fprint(os.Stderr, FormatAggregate(a, h.verbose))
+}
@@ -0,0 +1,56 @@
+package error
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAggregator_GroupsIdenticalErrors(t *testing.T) {
+	a := NewAggregator()
+	for _, target := range []string{"gw-1", "gw-2", "gw-3"} {
+		a.Add(target, CreateNetworkError("connection_refused", "https://"+target, errors.New("dial "+target+": connection refused")))
+	}
+	a.Add("gw-4", CreateAPIError("authentication_failed", 401, "https://gw-4", errors.New("401 unauthorized")))
+	a.Add("gw-5", errors.New("API request failed with status 402: Payment Required"))
+	a.Add("gw-6", nil)
+
+	if a.Len() != 5 {
+		t.Errorf("Len() = %d, want 5", a.Len())
+	}
+	groups := a.Groups()
+	if len(groups) != 3 {
+		t.Fatalf("len(Groups()) = %d, want 3", len(groups))
+	}
+	if got := strings.Join(groups[0].Targets, ","); got != "gw-1,gw-2,gw-3" {
+		t.Errorf("Targets = %s, want gw-1,gw-2,gw-3", got)
+	}
+	if groups[2].Err.Code != "payment_required" {
+		t.Errorf("plain error code = %s, want payment_required", groups[2].Err.Code)
+	}
+
+	summary := FormatAggregate(a, false)
+	if strings.Count(summary, "connection_refused") != 1 || !strings.Contains(summary, "×3") {
+		t.Errorf("summary does not show the repeated error once with a count:\n%s", summary)
+	}
+	if strings.Contains(summary, "dial gw-2") || !strings.Contains(summary, "--verbose") {
+		t.Errorf("summary shows causes without --verbose:\n%s", summary)
+	}
+
+	detail := FormatAggregate(a, true)
+	for _, cause := range []string{"dial gw-1", "dial gw-2", "dial gw-3"} {
+		if !strings.Contains(detail, cause) {
+			t.Errorf("verbose output is missing %q:\n%s", cause, detail)
+		}
+	}
+}
+
+func TestFormatAggregate_TruncatesTargets(t *testing.T) {
+	a := NewAggregator()
+	for i := 0; i < maxListedTargets+3; i++ {
+		a.Add(string(rune('a'+i)), errors.New("connection refused"))
+	}
+	if summary := FormatAggregate(a, false); !strings.Contains(summary, "ほか3件") {
+		t.Errorf("summary does not truncate the targets:\n%s", summary)
+	}
+}
@@ -0,0 +1,112 @@
+package error
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/paths"
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+// CrashReport はパニック時に書き出すクラッシュレポートの内容
+// ユーザーから送られたバグレポートだけで原因を調べられるよう、実行環境と直前のログを含める
+type CrashReport struct {
+	Time      time.Time
+	Version   string
+	OS        string
+	Arch      string
+	GoVersion string
+	Args      []string // 秘密情報を取り除いたコマンドライン引数
+	Panic     string
+	Stack     string
+	Logs      []string // 直前に出力した（または--verboseでのみ出力する）メッセージ
+}
+
+// NewCrashReport はパニックの値とスタックトレースからクラッシュレポートを作成する
+// 引数、パニックの値、スタックトレース、ログから秘密情報を取り除く
+func NewCrashReport(version string, panicValue interface{}, stack []byte, logs []string) *CrashReport {
+	report := &CrashReport{
+		Time:      time.Now(),
+		Version:   version,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		GoVersion: runtime.Version(),
+		Args:      redact.Args(os.Args[1:]),
+		Panic:     fmt.Sprint(redact.Value(panicValue)),
+		Stack:     redact.String(string(stack)),
+	}
+	for _, line := range logs {
+		report.Logs = append(report.Logs, redact.String(line))
+	}
+	return report
+}
+
+// Format はクラッシュレポートをテキストとしてフォーマットする
+func (r *CrashReport) Format() string {
+	var builder strings.Builder
+
+	builder.WriteString("llm-info crash report\n\n")
+	builder.WriteString(fmt.Sprintf("time:    %s\n", r.Time.Format(time.RFC3339)))
+	builder.WriteString(fmt.Sprintf("version: %s\n", r.Version))
+	builder.WriteString(fmt.Sprintf("os:      %s/%s\n", r.OS, r.Arch))
+	builder.WriteString(fmt.Sprintf("go:      %s\n", r.GoVersion))
+	builder.WriteString(fmt.Sprintf("args:    %s\n", strings.Join(r.Args, " ")))
+	builder.WriteString(fmt.Sprintf("\npanic: %s\n", r.Panic))
+	builder.WriteString(fmt.Sprintf("\n%s\n", strings.TrimRight(r.Stack, "\n")))
+
+	builder.WriteString("\nrecent log:\n")
+	if len(r.Logs) == 0 {
+		builder.WriteString("  (none)\n")
+	}
+	for _, line := range r.Logs {
+		builder.WriteString(fmt.Sprintf("  %s\n", line))
+	}
+	return builder.String()
+}
+
+// WriteCrashReport はクラッシュレポートをディレクトリに書き出し、ファイルのパスを返す
+// 引数などを伏せきれない場合に備え、ファイルは本人だけが読めるようにする
+func WriteCrashReport(dir string, report *CrashReport) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create crash report directory: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s.txt", report.Time.Format("20060102-150405")))
+	if err := os.WriteFile(path, []byte(report.Format()), 0600); err != nil {
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+	return path, nil
+}
+
+// GetDefaultCrashDir はクラッシュレポートを書き出すデフォルトのディレクトリを返す
+func GetDefaultCrashDir() string {
+	return paths.StateFile("crash")
+}
+
+// EnableCrashReports はパニックから回復したときにクラッシュレポートを書き出すようにする
+// recentLogsは直前のログを返す関数（nilの場合はログを含めない）
+func (h *Handler) EnableCrashReports(dir, version string, recentLogs func() []string) {
+	h.crashDir = dir
+	h.version = version
+	h.recentLogs = recentLogs
+}
+
+// writeCrashReport はクラッシュレポートを書き出し、そのパスを返す（無効な場合や失敗した場合は空文字列）
+func (h *Handler) writeCrashReport(panicValue interface{}, stack []byte) string {
+	if h.crashDir == "" {
+		return ""
+	}
+	var logs []string
+	if h.recentLogs != nil {
+		logs = h.recentLogs()
+	}
+	path, err := WriteCrashReport(h.crashDir, NewCrashReport(h.version, panicValue, stack, logs))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,55 @@
+package error
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+func TestWriteCrashReport(t *testing.T) {
+	redact.Reset()
+	defer redact.Reset()
+	redact.Register("gw-secret-123")
+
+	oldArgs := os.Args
+	os.Args = []string{"llm-info", "probe", "--api-key", "short", "--model", "gpt-4o"}
+	defer func() { os.Args = oldArgs }()
+
+	report := NewCrashReport("1.2.3", "index out of range with key gw-secret-123",
+		[]byte("goroutine 1 [running]:\nmain.main()"), []string{"Using gateway production", "Authorization: Bearer abcdef123456"})
+
+	dir := filepath.Join(t.TempDir(), "crash")
+	path, err := WriteCrashReport(dir, report)
+	if err != nil {
+		t.Fatalf("WriteCrashReport() error = %v", err)
+	}
+	if filepath.Dir(path) != dir {
+		t.Errorf("path = %s, want a file in %s", path, dir)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat crash report: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("permissions = %o, want 600", info.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read crash report: %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{"version: 1.2.3", "args:    probe --api-key [REDACTED] --model gpt-4o", "panic: index out of range with key [REDACTED]", "main.main()", "Using gateway production"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("crash report does not contain %q:\n%s", want, content)
+		}
+	}
+	for _, secret := range []string{"short", "gw-secret-123", "abcdef123456"} {
+		if strings.Contains(content, secret) {
+			t.Errorf("crash report contains secret %q", secret)
+		}
+	}
+}
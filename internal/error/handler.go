@@ -7,6 +7,9 @@ import (
 	"os"
 	"runtime/debug"
 	"strings"
+
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/pkg/config"
 )
 
 // ErrorType はエラーの種類を表します
@@ -182,7 +185,11 @@ func getErrorInfo(errorType ErrorType) (string, int) {
 
 // Handler はエラーハンドラーを表す
 type Handler struct {
-	verbose bool
+	verbose    bool
+	exitCodes  config.ExitCodes // 重大度ごとの終了コードの置き換え（設定ファイルのexit_codes）
+	crashDir   string           // クラッシュレポートを書き出すディレクトリ（空の場合は書き出さない）
+	version    string
+	recentLogs func() []string
 }
 
 // NewHandler は新しいエラーハンドラーを作成する
@@ -216,17 +223,33 @@ func (h *Handler) Handle(err error) int {
 	}
 
 	// 重大度に応じて終了コードを返す
-	switch appErr.Severity {
+	return h.exitCodes.Resolve(severityExitCode(appErr.Severity))
+}
+
+// SetVerbose は詳細モードを切り替える
+func (h *Handler) SetVerbose(verbose bool) {
+	h.verbose = verbose
+}
+
+// SetExitCodes は重大度ごとの終了コードを置き換える
+// 他のツールに組み込む場合に、呼び出し側の規約（例: cronでは2以上を返さない）に合わせるために使う
+func (h *Handler) SetExitCodes(codes config.ExitCodes) {
+	h.exitCodes = codes
+}
+
+// severityExitCode は重大度に応じた既定の終了コードを返す
+func severityExitCode(severity ErrorSeverity) int {
+	switch severity {
 	case SeverityInfo:
 		return 0
 	case SeverityWarning:
-		return 1
+		return config.ExitCodeWarning
 	case SeverityError:
-		return 2
+		return config.ExitCodeError
 	case SeverityFatal:
-		return 3
+		return config.ExitCodeFatal
 	default:
-		return 2
+		return config.ExitCodeError
 	}
 }
 
@@ -243,8 +266,8 @@ func (h *Handler) HandleWithFallback(err error, fallback func() error) int {
 	if fallback != nil {
 		fmt.Fprintln(os.Stderr, "\n🔄 フォールバック処理を実行します...")
 		if fallbackErr := fallback(); fallbackErr != nil {
-			fmt.Fprintf(os.Stderr, "❌ フォールバック処理も失敗しました: %v\n", fallbackErr)
-			return 3
+			fmt.Fprintf(os.Stderr, "❌ フォールバック処理も失敗しました: %v\n", redact.Error(fallbackErr))
+			return h.exitCodes.Resolve(config.ExitCodeFatal)
 		}
 		fmt.Fprintln(os.Stderr, "✅ フォールバック処理が成功しました")
 		return 0
@@ -259,7 +282,7 @@ func (h *Handler) printVerboseInfo(err *AppError) {
 
 	// スタックトレース
 	if err.OriginalErr != nil {
-		fmt.Fprintf(os.Stderr, "原因エラー: %v\n", err.OriginalErr)
+		fmt.Fprintf(os.Stderr, "原因エラー: %v\n", redact.Error(err.OriginalErr))
 	}
 
 	// デバッグ情報
@@ -304,13 +327,20 @@ func AsAppError(err error, target **AppError) bool {
 // Recover はパニックから回復する
 func (h *Handler) Recover() {
 	if r := recover(); r != nil {
+		stack := debug.Stack()
 		err := NewAppError(ErrorTypeSystem, SeverityFatal, "panic", "アプリケーションがクラッシュしました").
-			WithCause(fmt.Errorf("panic: %v", r)).
+			WithCause(fmt.Errorf("panic: %s", redact.Value(r))).
 			WithSolution("開発者にバグレポートを送信してください").
 			WithHelpURL("https://github.com/armaniacs/llm-info/issues")
 
-		fmt.Printf("Panic recovered: %v\n", r)
-		debug.PrintStack()
+		fmt.Printf("Panic recovered: %s\n", redact.Value(r))
+		os.Stderr.Write(stack)
+
+		// クラッシュレポートがあれば、バグレポートに添付してもらう
+		if path := h.writeCrashReport(r, stack); path != "" {
+			err = err.WithContext("crash_report", path).
+				WithSolution(fmt.Sprintf("バグレポートにクラッシュレポート（%s）を添付してください", path))
+		}
 
 		os.Exit(h.Handle(err))
 	}
@@ -321,13 +351,13 @@ func FormatErrorMessage(err *AppError) string {
 	var builder strings.Builder
 
 	// 基本メッセージ
-	builder.WriteString(fmt.Sprintf("❌ %s\n", err.Message))
+	builder.WriteString(fmt.Sprintf("❌ %s\n", redact.String(err.Message)))
 
-	// コンテキスト情報
+	// コンテキスト情報（URLや原因エラーにAPIキーが含まれる場合があるため秘密情報を取り除く）
 	if len(err.Context) > 0 {
 		builder.WriteString("\n📋 詳細情報:\n")
 		for key, value := range err.Context {
-			builder.WriteString(fmt.Sprintf("   %s: %v\n", key, value))
+			builder.WriteString(fmt.Sprintf("   %s: %v\n", key, redact.Value(value)))
 		}
 	}
 
@@ -378,7 +408,7 @@ func isErrorType(err error, target interface{}) bool {
 
 // handleAppError はアプリケーションエラーを処理します
 func handleAppError(err *AppError) {
-	fmt.Fprintf(os.Stderr, "\n❌ Error: %s\n", err.Message)
+	fmt.Fprintf(os.Stderr, "\n❌ Error: %s\n", redact.String(err.Message))
 
 	if err.Suggestion != "" {
 		fmt.Fprintf(os.Stderr, "💡 Suggestion: %s\n", err.Suggestion)
@@ -386,7 +416,7 @@ func handleAppError(err *AppError) {
 
 	// デバッグ情報の表示（環境変数で制御）
 	if os.Getenv("LLM_INFO_DEBUG") != "" && err.OriginalErr != nil {
-		fmt.Fprintf(os.Stderr, "🔍 Debug: %v\n", err.OriginalErr)
+		fmt.Fprintf(os.Stderr, "🔍 Debug: %v\n", redact.Error(err.OriginalErr))
 	}
 }
 
@@ -419,7 +449,7 @@ func handleGenericError(err error) {
 		suggestion = "Check your network connection and verify the gateway URL is correct."
 	}
 
-	fmt.Fprintf(os.Stderr, "\n❌ Error: %s\n", errorMsg)
+	fmt.Fprintf(os.Stderr, "\n❌ Error: %s\n", redact.String(errorMsg))
 	fmt.Fprintf(os.Stderr, "💡 Suggestion: %s\n", suggestion)
 
 	// デバッグ情報の表示
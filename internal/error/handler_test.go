@@ -7,6 +7,9 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/armaniacs/llm-info/internal/redact"
+	"github.com/armaniacs/llm-info/pkg/config"
 )
 
 func TestAppError_Error(t *testing.T) {
@@ -195,6 +198,38 @@ func TestHandler_Handle(t *testing.T) {
 	}
 }
 
+func TestHandler_SetExitCodes(t *testing.T) {
+	oldStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	defer func() {
+		w.Close()
+		os.Stderr = oldStderr
+	}()
+
+	errorCode, fatalCode, maxCode := 10, 20, 1
+	tests := []struct {
+		name  string
+		codes config.ExitCodes
+		err   *AppError
+		want  int
+	}{
+		{"default", config.ExitCodes{}, NewAppErrorCompat(NetworkError, "network", nil), 2},
+		{"error overridden", config.ExitCodes{Error: &errorCode}, NewAppErrorCompat(NetworkError, "network", nil), 10},
+		{"fatal overridden", config.ExitCodes{Fatal: &fatalCode}, NewAppError(UnknownError, SeverityFatal, "panic", "panic"), 20},
+		{"clamped by max", config.ExitCodes{Error: &errorCode, Max: &maxCode}, NewAppErrorCompat(NetworkError, "network", nil), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(false)
+			handler.SetExitCodes(tt.codes)
+			if got := handler.Handle(tt.err); got != tt.want {
+				t.Errorf("Handle() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestHandler_HandleWithVerbose(t *testing.T) {
 	// 標準エラー出力をキャプチャ
 	oldStderr := os.Stderr
@@ -298,6 +333,49 @@ func TestFormatErrorMessage(t *testing.T) {
 	}
 }
 
+func TestFormatErrorMessage_RedactsSecrets(t *testing.T) {
+	redact.Reset()
+	defer redact.Reset()
+	redact.Register("gw-secret-123")
+
+	appErr := NewAppError(ErrorTypeAPI, SeverityError, "authentication_failed", "invalid key gw-secret-123").
+		WithCause(errors.New("401: Bearer gw-secret-123")).
+		WithContext("url", "https://gw.example.com/v1/models?api_key=qwerty99").
+		WithContext("cause", errors.New("key gw-secret-123 rejected"))
+
+	formatted := FormatErrorMessage(appErr)
+
+	for _, secret := range []string{"gw-secret-123", "qwerty99"} {
+		if strings.Contains(formatted, secret) {
+			t.Errorf("formatted message contains secret %q: %s", secret, formatted)
+		}
+	}
+	if !strings.Contains(formatted, "invalid key [REDACTED]") {
+		t.Errorf("expected redacted message, got: %s", formatted)
+	}
+}
+
+func TestHandleGenericError_RedactsSecrets(t *testing.T) {
+	redact.Reset()
+	defer redact.Reset()
+	redact.Register("gw-secret-123")
+
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	HandleError(errors.New("request with key gw-secret-123 failed"))
+
+	w.Close()
+	os.Stderr = oldStderr
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	if strings.Contains(buf.String(), "gw-secret-123") {
+		t.Errorf("stderr contains the secret: %s", buf.String())
+	}
+}
+
 func TestAsAppError(t *testing.T) {
 	originalErr := errors.New("original error")
 	appErr := NewAppErrorCompat(NetworkError, "test error", originalErr)
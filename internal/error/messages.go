@@ -1,6 +1,8 @@
 package error
 
 import (
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -25,6 +27,11 @@ var errorMessages = map[ErrorType]map[string]string{
 		"endpoint_not_found":    "エンドポイントが見つかりません",
 		"invalid_response":      "無効なレスポンス形式です",
 		"server_error":          "サーバーエラーが発生しました",
+		"payment_required":      "支払いが必要です（クレジットまたは請求設定を確認してください）",
+		"insufficient_quota":    "利用枠（クォータ）を使い切りました",
+		"payload_too_large":     "リクエストが大きすぎます",
+		"legal_block":           "法的な理由によりこの地域・アカウントでは利用できません",
+		"overloaded":            "プロバイダーが過負荷の状態です",
 	},
 	ErrorTypeConfig: {
 		"config_file_not_found":  "設定ファイルが見つかりません",
@@ -33,10 +40,12 @@ var errorMessages = map[ErrorType]map[string]string{
 		"invalid_env_variable":   "環境変数の値が無効です",
 	},
 	ErrorTypeUser: {
-		"invalid_argument":      "無効な引数です",
-		"invalid_filter_syntax": "フィルタ構文が無効です",
-		"invalid_sort_field":    "無効なソートフィールドです",
-		"gateway_not_found":     "指定されたゲートウェイが見つかりません",
+		"invalid_argument":        "無効な引数です",
+		"invalid_filter_syntax":   "フィルタ構文が無効です",
+		"invalid_sort_field":      "無効なソートフィールドです",
+		"gateway_not_found":       "指定されたゲートウェイが見つかりません",
+		"offline_cache_not_found": "オフライン表示用のキャッシュが見つかりません",
+		"unknown_filter_preset":   "名前付きフィルタが見つかりません",
 	},
 	ErrorTypeSystem: {
 		"permission_denied":   "ファイルアクセス権限がありません",
@@ -107,6 +116,26 @@ func CreateAPIError(code string, statusCode int, urlStr string, cause error) *Ap
 		err = err.WithSolution("ゲートウェイURLが正しいか確認してください").
 			WithSolution("APIバージョンが正しいか確認してください").
 			WithSolution("エンドポイントパスを確認してください")
+	case "payment_required":
+		err = err.WithSolution("プロバイダーのアカウントの残高・クレジットを確認してください").
+			WithSolution("請求先（支払い方法）が登録されているか確認してください").
+			WithSolution("ゲートウェイのユーザー・チームの予算上限を確認してください")
+	case "insufficient_quota":
+		err = err.WithSolution("再試行しても解消しません。プロバイダーの利用状況と請求情報を確認してください").
+			WithSolution("利用枠の引き上げを申請するか、クレジットを追加してください").
+			WithSolution("別のAPIキーまたはゲートウェイを使用してください: --gateway")
+	case "payload_too_large":
+		err = err.WithSolution("プロンプトを短くするか、リクエストを分割してください").
+			WithSolution("ゲートウェイやリバースプロキシのボディサイズ上限（例: nginxのclient_max_body_size）を確認してください").
+			WithSolution("探索の場合はゲートウェイの max_body_size に上限を設定してください")
+	case "legal_block":
+		err = err.WithSolution("プロバイダーが利用を許可している国・地域から接続しているか確認してください").
+			WithSolution("アカウントやモデルの利用規約上の制限を確認してください").
+			WithSolution("再試行しても解消しないため、ゲートウェイの管理者に問い合わせてください")
+	case "overloaded":
+		err = err.WithSolution("一時的な過負荷のため、しばらく待ってから再試行してください").
+			WithSolution("並列リクエスト数を減らしてください").
+			WithSolution("プロバイダーのステータスページを確認してください")
 	}
 
 	return err.WithHelpURL("https://github.com/armaniacs/llm-info/wiki/api-errors")
@@ -160,6 +189,12 @@ func CreateUserError(code string, argument string, cause error) *AppError {
 	case "gateway_not_found":
 		err = err.WithSolution("ゲートウェイ名が正しいか確認してください").
 			WithSolution("利用可能なゲートウェイを確認してください: llm-info --list-gateways")
+	case "unknown_filter_preset":
+		err = err.WithSolution("設定ファイルの filters に名前付きフィルタを定義してください").
+			WithSolution("ヘルプを確認してください: llm-info --help filter")
+	case "offline_cache_not_found":
+		err = err.WithSolution("一度オンラインで実行してキャッシュを作成してください").
+			WithSolution("--gateway または --url がキャッシュ作成時と同じか確認してください")
 	}
 
 	return err.WithHelpURL("https://github.com/armaniacs/llm-info/wiki/usage")
@@ -195,6 +230,23 @@ func CreateSystemError(code string, context string, cause error) *AppError {
 	return err.WithHelpURL("https://github.com/armaniacs/llm-info/issues")
 }
 
+// statusCodePattern はエラーメッセージから個別に扱うHTTPステータスコードを取り出す
+// 402、413、451、529（Anthropicの過負荷）はserver_errorや不明なエラーとして扱うと解決策が合わないため区別する
+var statusCodePattern = regexp.MustCompile(`\b(402|413|451|529)\b`)
+
+// DetectStatusCode はエラーメッセージに含まれる個別に扱うHTTPステータスコードを返す（なければ0）
+func DetectStatusCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	match := statusCodePattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0
+	}
+	code, _ := strconv.Atoi(match[1])
+	return code
+}
+
 // DetectErrorType はエラーメッセージからエラー種別を検出する
 func DetectErrorType(err error) (ErrorType, string) {
 	if err == nil {
@@ -203,6 +255,14 @@ func DetectErrorType(err error) (ErrorType, string) {
 
 	errorMsg := strings.ToLower(err.Error())
 
+	// プロバイダー固有のエラー種別の検出（429や5xxと一緒に返されるため、ステータスコードより先に調べる）
+	if strings.Contains(errorMsg, "insufficient_quota") {
+		return ErrorTypeAPI, "insufficient_quota"
+	}
+	if strings.Contains(errorMsg, "overloaded_error") || strings.Contains(errorMsg, "overloaded") {
+		return ErrorTypeAPI, "overloaded"
+	}
+
 	// ネットワークエラーの検出
 	if strings.Contains(errorMsg, "timeout") || strings.Contains(errorMsg, "deadline exceeded") {
 		return ErrorTypeNetwork, "connection_timeout"
@@ -218,6 +278,25 @@ func DetectErrorType(err error) (ErrorType, string) {
 	}
 
 	// APIエラーの検出
+	switch DetectStatusCode(err) {
+	case 402:
+		return ErrorTypeAPI, "payment_required"
+	case 413:
+		return ErrorTypeAPI, "payload_too_large"
+	case 451:
+		return ErrorTypeAPI, "legal_block"
+	case 529:
+		return ErrorTypeAPI, "overloaded"
+	}
+	if strings.Contains(errorMsg, "payment required") {
+		return ErrorTypeAPI, "payment_required"
+	}
+	if strings.Contains(errorMsg, "too large") {
+		return ErrorTypeAPI, "payload_too_large"
+	}
+	if strings.Contains(errorMsg, "unavailable for legal reasons") {
+		return ErrorTypeAPI, "legal_block"
+	}
 	if strings.Contains(errorMsg, "401") || strings.Contains(errorMsg, "unauthorized") {
 		return ErrorTypeAPI, "authentication_failed"
 	}
@@ -262,7 +341,7 @@ func WrapErrorWithDetection(err error, context string) *AppError {
 	case ErrorTypeNetwork:
 		return CreateNetworkError(code, context, err)
 	case ErrorTypeAPI:
-		return CreateAPIError(code, 0, context, err)
+		return CreateAPIError(code, DetectStatusCode(err), context, err)
 	case ErrorTypeConfig:
 		return CreateConfigError(code, context, err)
 	case ErrorTypeUser:
@@ -264,6 +264,11 @@ func TestCreateUserError(t *testing.T) {
 			code:              "gateway_not_found",
 			expectedSolutions: 2,
 		},
+		{
+			name:              "Offline cache not found",
+			code:              "offline_cache_not_found",
+			expectedSolutions: 2,
+		},
 	}
 
 	for _, tt := range tests {
@@ -407,6 +412,42 @@ func TestDetectErrorType(t *testing.T) {
 			expectedType: ErrorTypeAPI,
 			expectedCode: "server_error",
 		},
+		{
+			name:         "402 error",
+			err:          errors.New("API request failed with status 402: Payment Required"),
+			expectedType: ErrorTypeAPI,
+			expectedCode: "payment_required",
+		},
+		{
+			name:         "413 error",
+			err:          errors.New("unexpected status code: 413"),
+			expectedType: ErrorTypeAPI,
+			expectedCode: "payload_too_large",
+		},
+		{
+			name:         "451 error",
+			err:          errors.New("API request failed with status 451: unavailable for legal reasons"),
+			expectedType: ErrorTypeAPI,
+			expectedCode: "legal_block",
+		},
+		{
+			name:         "OpenAI insufficient_quota",
+			err:          errors.New(`API request failed with status 429: {"error":{"type":"insufficient_quota","message":"You exceeded your current quota"}}`),
+			expectedType: ErrorTypeAPI,
+			expectedCode: "insufficient_quota",
+		},
+		{
+			name:         "Anthropic overloaded_error",
+			err:          errors.New(`API request failed with status 529: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`),
+			expectedType: ErrorTypeAPI,
+			expectedCode: "overloaded",
+		},
+		{
+			name:         "Status code inside a number",
+			err:          errors.New("prompt has 4021 tokens"),
+			expectedType: ErrorTypeUnknown,
+			expectedCode: "unexpected_error",
+		},
 		{
 			name:         "File not found",
 			err:          errors.New("no such file or directory"),
@@ -79,14 +79,27 @@ func (sp *SolutionProvider) GetAPISolutions(statusCode int, urlStr string) []str
 	case 403:
 		solutions = append(solutions, "APIキーに必要な権限があるか確認してください")
 		solutions = append(solutions, "アカウントの利用制限を確認してください")
+	case 402:
+		solutions = append(solutions, "プロバイダーのアカウントの残高・クレジットを確認してください")
+		solutions = append(solutions, "請求先（支払い方法）が登録されているか確認してください")
+		solutions = append(solutions, "ゲートウェイのユーザー・チームの予算上限を確認してください")
 	case 404:
 		solutions = append(solutions, "ゲートウェイURLが正しいか確認してください")
 		solutions = append(solutions, "APIバージョンが正しいか確認してください")
 		solutions = append(solutions, "エンドポイントパスを確認してください")
+	case 413:
+		solutions = append(solutions, "プロンプトを短くするか、リクエストを分割してください")
+		solutions = append(solutions, "ゲートウェイやリバースプロキシのボディサイズ上限を確認してください")
 	case 429:
 		solutions = append(solutions, "しばらく待ってから再試行してください")
 		solutions = append(solutions, "APIプランのレート制限を確認してください")
 		solutions = append(solutions, "並列リクエスト数を減らしてください")
+	case 451:
+		solutions = append(solutions, "プロバイダーが利用を許可している国・地域から接続しているか確認してください")
+		solutions = append(solutions, "アカウントやモデルの利用規約上の制限を確認してください")
+	case 529:
+		solutions = append(solutions, "プロバイダーが過負荷の状態です。しばらく待ってから再試行してください")
+		solutions = append(solutions, "並列リクエスト数を減らしてください")
 	case 500, 502, 503, 504:
 		solutions = append(solutions, "サーバーが一時的に利用できない可能性があります")
 		solutions = append(solutions, "しばらく待ってから再試行してください")
@@ -119,6 +132,9 @@ func (sp *SolutionProvider) GetUserSolutions(code string, argument string) []str
 		solutions = append(solutions, "ゲートウェイ名が正しいか確認してください")
 		solutions = append(solutions, "利用可能なゲートウェイを確認してください: llm-info --list-gateways")
 		solutions = append(solutions, "設定ファイルにゲートウェイが登録されているか確認してください")
+	case "offline_cache_not_found":
+		solutions = append(solutions, "一度オンラインで実行してキャッシュを作成してください")
+		solutions = append(solutions, "--gateway または --url がキャッシュ作成時と同じか確認してください")
 	}
 
 	return solutions
@@ -209,6 +209,14 @@ func TestSolutionProvider_GetAPISolutions(t *testing.T) {
 				"並列リクエスト数を減らしてください",
 			},
 		},
+		{
+			name:       "451 Unavailable For Legal Reasons",
+			statusCode: 451,
+			expected: []string{
+				"プロバイダーが利用を許可している国・地域から接続しているか確認してください",
+				"アカウントやモデルの利用規約上の制限を確認してください",
+			},
+		},
 		{
 			name:       "500 Server Error",
 			statusCode: 500,
@@ -300,6 +308,15 @@ func TestSolutionProvider_GetUserSolutions(t *testing.T) {
 				"設定ファイルにゲートウェイが登録されているか確認してください",
 			},
 		},
+		{
+			name:     "Offline cache not found",
+			code:     "offline_cache_not_found",
+			argument: "--offline",
+			expected: []string{
+				"一度オンラインで実行してキャッシュを作成してください",
+				"--gateway または --url がキャッシュ作成時と同じか確認してください",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,98 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/numfmt"
+)
+
+// WriteCSV は表をヘッダー付きのCSVで書き出す
+// 値がないセルは空文字列、日時はRFC 3339（UTC）で出力する
+func WriteCSV(w io.Writer, table *Table) error {
+	writer := csv.NewWriter(w)
+
+	header := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		header[i] = column.Name
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i, cell := range row {
+			record[i] = formatCSVCell(cell)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// FormatNumbers はmax_tokensとinput_costの列をnumberFormatの表記の文字列にした表を返す
+// CSVを人が読むためのもので、型を持つParquetには使わない。ゼロ値（raw）の場合は表をそのまま返す
+func FormatNumbers(table *Table, numberFormat numfmt.Format) *Table {
+	if !numberFormat.Humanized() {
+		return table
+	}
+
+	formatted := &Table{Columns: append([]Column{}, table.Columns...)}
+	converters := make(map[int]func(interface{}) interface{})
+	for i, column := range formatted.Columns {
+		switch column.Name {
+		case "max_tokens":
+			converters[i] = func(cell interface{}) interface{} {
+				if v, ok := cell.(int64); ok {
+					return numberFormat.Tokens(int(v))
+				}
+				return cell
+			}
+		case "input_cost":
+			converters[i] = func(cell interface{}) interface{} {
+				if v, ok := cell.(float64); ok {
+					return numberFormat.Cost(v)
+				}
+				return cell
+			}
+		default:
+			continue
+		}
+		formatted.Columns[i].Type = TypeString
+	}
+
+	for _, row := range table.Rows {
+		cells := append([]interface{}{}, row...)
+		for i, convert := range converters {
+			cells[i] = convert(cells[i])
+		}
+		formatted.Rows = append(formatted.Rows, cells)
+	}
+	return formatted
+}
+
+// formatCSVCell はセルの値をCSV用の文字列にする
+func formatCSVCell(cell interface{}) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return ""
+	}
+}
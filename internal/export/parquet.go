@@ -0,0 +1,249 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// parquetMagic はParquetファイルの先頭と末尾に置くマジックナンバー
+const parquetMagic = "PAR1"
+
+// Parquetの列挙値（parquet.thriftの定義に合わせる）
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetOptional = 1
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageTypeData = 0
+)
+
+// WriteParquet は表をParquet形式で書き出す
+// カタログは大きくないため、行グループ1つ・列ごとにデータページ1つの非圧縮ファイルにする
+// すべての列はOPTIONALで、値がないセルはnullになる
+func WriteParquet(w io.Writer, table *Table) error {
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+
+	chunks := make([]columnChunk, len(table.Columns))
+	var totalSize int64
+	for i, column := range table.Columns {
+		page, err := encodeDataPage(column, table.Rows, i)
+		if err != nil {
+			return err
+		}
+		chunks[i] = columnChunk{
+			column: column,
+			offset: int64(file.Len()),
+			size:   int64(len(page)),
+		}
+		file.Write(page)
+		totalSize += int64(len(page))
+	}
+
+	footer := encodeFileMetaData(table, chunks, totalSize)
+	file.Write(footer)
+	binary.Write(&file, binary.LittleEndian, uint32(len(footer)))
+	file.WriteString(parquetMagic)
+
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// columnChunk はファイル内に書いた列データの位置
+type columnChunk struct {
+	column Column
+	offset int64
+	size   int64 // ページヘッダーを含む
+}
+
+// encodeDataPage は1列分のデータページ（ヘッダーと本体）を作る
+func encodeDataPage(column Column, rows [][]interface{}, index int) ([]byte, error) {
+	levels := make([]bool, len(rows))
+	var values bytes.Buffer
+	var bits []bool
+	for r, row := range rows {
+		cell := row[index]
+		if cell == nil {
+			continue
+		}
+		levels[r] = true
+		switch column.Type {
+		case TypeString:
+			s, ok := cell.(string)
+			if !ok {
+				return nil, cellTypeError(column, cell)
+			}
+			binary.Write(&values, binary.LittleEndian, uint32(len(s)))
+			values.WriteString(s)
+		case TypeInt64:
+			v, ok := cell.(int64)
+			if !ok {
+				return nil, cellTypeError(column, cell)
+			}
+			binary.Write(&values, binary.LittleEndian, v)
+		case TypeDouble:
+			v, ok := cell.(float64)
+			if !ok {
+				return nil, cellTypeError(column, cell)
+			}
+			binary.Write(&values, binary.LittleEndian, math.Float64bits(v))
+		case TypeBoolean:
+			v, ok := cell.(bool)
+			if !ok {
+				return nil, cellTypeError(column, cell)
+			}
+			bits = append(bits, v)
+		case TypeTimestamp:
+			v, ok := cell.(time.Time)
+			if !ok {
+				return nil, cellTypeError(column, cell)
+			}
+			binary.Write(&values, binary.LittleEndian, v.UnixMilli())
+		}
+	}
+	if column.Type == TypeBoolean {
+		values.Write(packBits(bits))
+	}
+
+	// 定義レベル（値があれば1、nullなら0）は長さ付きのRLEで値の前に置く
+	encodedLevels := encodeLevels(levels)
+	var body bytes.Buffer
+	binary.Write(&body, binary.LittleEndian, uint32(len(encodedLevels)))
+	body.Write(encodedLevels)
+	body.Write(values.Bytes())
+
+	var header compactWriter
+	header.beginStruct()
+	header.i32Field(1, pageTypeData)
+	header.i32Field(2, int32(body.Len()))
+	header.i32Field(3, int32(body.Len()))
+	header.structField(5, func() {
+		header.i32Field(1, int32(len(rows)))
+		header.i32Field(2, encodingPlain)
+		header.i32Field(3, encodingRLE)
+		header.i32Field(4, encodingRLE)
+	})
+	header.endStruct()
+
+	return append(header.buf.Bytes(), body.Bytes()...), nil
+}
+
+// encodeLevels はビット幅1の定義レベルをRLE/ビットパッキング混合形式のRLEランで表す
+func encodeLevels(levels []bool) []byte {
+	var out compactWriter
+	for start := 0; start < len(levels); {
+		end := start
+		for end < len(levels) && levels[end] == levels[start] {
+			end++
+		}
+		out.varint(uint64(end-start) << 1)
+		if levels[start] {
+			out.buf.WriteByte(1)
+		} else {
+			out.buf.WriteByte(0)
+		}
+		start = end
+	}
+	return out.buf.Bytes()
+}
+
+// packBits は真偽値を下位ビットから詰める（BOOLEANのPLAINエンコーディング）
+func packBits(bits []bool) []byte {
+	packed := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		if bit {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return packed
+}
+
+// encodeFileMetaData はフッターのFileMetaDataを作る
+func encodeFileMetaData(table *Table, chunks []columnChunk, totalSize int64) []byte {
+	var w compactWriter
+	w.beginStruct()
+	w.i32Field(1, 1) // version
+
+	w.listField(2, compactStruct, len(table.Columns)+1)
+	w.structElem(func() {
+		w.stringField(4, "schema")
+		w.i32Field(5, int32(len(table.Columns)))
+	})
+	for _, column := range table.Columns {
+		w.structElem(func() {
+			physical, converted, hasConverted := parquetType(column.Type)
+			w.i32Field(1, physical)
+			w.i32Field(3, parquetOptional)
+			w.stringField(4, column.Name)
+			if hasConverted {
+				w.i32Field(6, converted)
+			}
+		})
+	}
+
+	w.i64Field(3, int64(len(table.Rows)))
+
+	w.listField(4, compactStruct, 1)
+	w.structElem(func() {
+		w.listField(1, compactStruct, len(chunks))
+		for _, chunk := range chunks {
+			w.structElem(func() {
+				physical, _, _ := parquetType(chunk.column.Type)
+				w.i64Field(2, chunk.offset)
+				w.structField(3, func() {
+					w.i32Field(1, physical)
+					w.listField(2, compactI32, 2)
+					w.i32Elem(encodingPlain)
+					w.i32Elem(encodingRLE)
+					w.listField(3, compactBinary, 1)
+					w.stringElem(chunk.column.Name)
+					w.i32Field(4, 0) // UNCOMPRESSED
+					w.i64Field(5, int64(len(table.Rows)))
+					w.i64Field(6, chunk.size)
+					w.i64Field(7, chunk.size)
+					w.i64Field(9, chunk.offset)
+				})
+			})
+		}
+		w.i64Field(2, totalSize)
+		w.i64Field(3, int64(len(table.Rows)))
+	})
+
+	w.stringField(6, "llm-info")
+	w.endStruct()
+	return w.buf.Bytes()
+}
+
+// parquetType は列の型に対応する物理型と変換型を返す
+func parquetType(t ColumnType) (physical int32, converted int32, hasConverted bool) {
+	switch t {
+	case TypeInt64:
+		return parquetInt64, 0, false
+	case TypeDouble:
+		return parquetDouble, 0, false
+	case TypeBoolean:
+		return parquetBoolean, 0, false
+	case TypeTimestamp:
+		return parquetInt64, convertedTimestampMillis, true
+	default:
+		return parquetByteArray, convertedUTF8, true
+	}
+}
+
+// cellTypeError は列の型と合わない値が入っていたことを表すエラーを返す
+func cellTypeError(column Column, cell interface{}) error {
+	return fmt.Errorf("column %s: unexpected value of type %T", column.Name, cell)
+}
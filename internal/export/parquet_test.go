@@ -0,0 +1,228 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// thriftReader はテスト用の最小限のThrift compact protocolデコーダー
+// 構造体はフィールドIDをキーにしたマップ、リストはスライスとして読む
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) varint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) int() int64 {
+	u := r.varint()
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (r *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case compactI32, compactI64:
+		return r.int()
+	case compactBinary:
+		n := int(r.varint())
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case compactList:
+		header := r.data[r.pos]
+		r.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.varint())
+		}
+		items := make([]interface{}, size)
+		for i := range items {
+			items[i] = r.value(header & 0x0f)
+		}
+		return items
+	case compactStruct:
+		return r.structure()
+	}
+	panic("unsupported thrift type")
+}
+
+func (r *thriftReader) structure() map[int64]interface{} {
+	fields := make(map[int64]interface{})
+	var last int64
+	for {
+		header := r.data[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+		id := last + int64(header>>4)
+		if header>>4 == 0 {
+			id = r.int()
+		}
+		fields[id] = r.value(header & 0x0f)
+		last = id
+	}
+}
+
+// readParquet はParquetファイルのフッターと各列のページを読む
+func readParquet(t *testing.T, data []byte) (map[int64]interface{}, [][]byte) {
+	t.Helper()
+	if string(data[:4]) != parquetMagic || string(data[len(data)-4:]) != parquetMagic {
+		t.Fatal("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := &thriftReader{data: data[len(data)-8-footerLen : len(data)-8]}
+	meta := footer.structure()
+	if footer.pos != footerLen {
+		t.Fatalf("footer consumed %d bytes, want %d", footer.pos, footerLen)
+	}
+
+	var pages [][]byte
+	rowGroup := meta[4].([]interface{})[0].(map[int64]interface{})
+	for _, c := range rowGroup[1].([]interface{}) {
+		columnMeta := c.(map[int64]interface{})[3].(map[int64]interface{})
+		offset := columnMeta[9].(int64)
+		size := columnMeta[7].(int64)
+
+		page := &thriftReader{data: data[offset : offset+size]}
+		header := page.structure()
+		if header[3].(int64) != size-int64(page.pos) {
+			t.Fatalf("page size = %d, want %d", header[3], size-int64(page.pos))
+		}
+		pages = append(pages, page.data[page.pos:])
+	}
+	return meta, pages
+}
+
+// decodeLevels はビット幅1のRLEランを展開する
+func decodeLevels(t *testing.T, page []byte) ([]bool, []byte) {
+	t.Helper()
+	n := int(binary.LittleEndian.Uint32(page))
+	r := &thriftReader{data: page[4 : 4+n]}
+	var levels []bool
+	for r.pos < n {
+		header := r.varint()
+		if header&1 != 0 {
+			t.Fatal("unexpected bit-packed run")
+		}
+		value := r.data[r.pos] == 1
+		r.pos++
+		for i := uint64(0); i < header>>1; i++ {
+			levels = append(levels, value)
+		}
+	}
+	return levels, page[4+n:]
+}
+
+func TestWriteParquet(t *testing.T) {
+	table := BuildTable(testSnapshots())
+
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, table); err != nil {
+		t.Fatalf("WriteParquet() error = %v", err)
+	}
+	meta, pages := readParquet(t, buf.Bytes())
+
+	if meta[3].(int64) != 2 {
+		t.Errorf("num_rows = %v, want 2", meta[3])
+	}
+	schema := meta[2].([]interface{})
+	if len(schema) != len(table.Columns)+1 {
+		t.Fatalf("schema elements = %d, want %d", len(schema), len(table.Columns)+1)
+	}
+	for i, column := range table.Columns {
+		element := schema[i+1].(map[int64]interface{})
+		if element[4] != column.Name {
+			t.Errorf("schema[%d] name = %v, want %s", i+1, element[4], column.Name)
+		}
+	}
+	snapshotAt := schema[2].(map[int64]interface{})
+	if snapshotAt[1].(int64) != parquetInt64 || snapshotAt[6].(int64) != convertedTimestampMillis {
+		t.Errorf("snapshot_at schema = %v, want INT64 TIMESTAMP_MILLIS", snapshotAt)
+	}
+
+	// gateway: 両方の行に値がある文字列
+	levels, values := decodeLevels(t, pages[0])
+	if len(levels) != 2 || !levels[0] || !levels[1] {
+		t.Errorf("gateway levels = %v", levels)
+	}
+	if n := binary.LittleEndian.Uint32(values); string(values[4:4+n]) != "production" {
+		t.Errorf("gateway value = %q", values[4:4+n])
+	}
+
+	// snapshot_at: UTCのミリ秒
+	_, values = decodeLevels(t, pages[1])
+	if got := int64(binary.LittleEndian.Uint64(values)); got != testSnapshotTime.UnixMilli() {
+		t.Errorf("snapshot_at = %d, want %d", got, testSnapshotTime.UnixMilli())
+	}
+
+	// max_tokens: INT64
+	_, values = decodeLevels(t, pages[3])
+	if got := int64(binary.LittleEndian.Uint64(values[8:])); got != 200000 {
+		t.Errorf("max_tokens[1] = %d, want 200000", got)
+	}
+
+	// input_cost: DOUBLE
+	_, values = decodeLevels(t, pages[5])
+	if got := math.Float64frombits(binary.LittleEndian.Uint64(values)); got != 0.0000025 {
+		t.Errorf("input_cost[0] = %v, want 0.0000025", got)
+	}
+
+	// variants: 1行目はnullで、値は2行目の1つだけ
+	levels, values = decodeLevels(t, pages[6])
+	if len(levels) != 2 || levels[0] || !levels[1] {
+		t.Errorf("variants levels = %v, want [false true]", levels)
+	}
+	if n := binary.LittleEndian.Uint32(values); int(n)+4 != len(values) {
+		t.Errorf("variants values = %d bytes, want a single value", len(values))
+	}
+
+	// supports_function_calling: BOOLEANはビットで詰める
+	levels, values = decodeLevels(t, pages[10])
+	if len(levels) != 2 || !levels[0] || levels[1] || len(values) != 1 || values[0] != 1 {
+		t.Errorf("supports_function_calling levels = %v, values = %v", levels, values)
+	}
+}
+
+func TestWriteParquet_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, BuildTable(nil)); err != nil {
+		t.Fatalf("WriteParquet() error = %v", err)
+	}
+	meta, pages := readParquet(t, buf.Bytes())
+	if meta[3].(int64) != 0 {
+		t.Errorf("num_rows = %v, want 0", meta[3])
+	}
+	if len(pages) != len(baseColumns) {
+		t.Errorf("pages = %d, want %d", len(pages), len(baseColumns))
+	}
+}
+
+func TestWriteParquet_CellTypeMismatch(t *testing.T) {
+	table := &Table{
+		Columns: []Column{{Name: "max_tokens", Type: TypeInt64}},
+		Rows:    [][]interface{}{{"128k"}},
+	}
+	if err := WriteParquet(&bytes.Buffer{}, table); err == nil {
+		t.Error("expected an error for a string in an INT64 column")
+	}
+}
+
+func TestEncodeLevels(t *testing.T) {
+	got := encodeLevels([]bool{true, true, true, false, true})
+	// (3<<1, 1), (1<<1, 0), (1<<1, 1)
+	want := []byte{6, 1, 2, 0, 2, 1}
+	if !bytes.Equal(got, want) {
+		t.Errorf("encodeLevels() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,230 @@
+// Package export はモデル一覧をデータウェアハウスに取り込める形式（CSV、Parquet）で書き出す
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/model"
+)
+
+// ColumnType は列の型
+type ColumnType int
+
+const (
+	TypeString    ColumnType = iota
+	TypeInt64                // 整数
+	TypeDouble               // 浮動小数点数
+	TypeBoolean              // 真偽値
+	TypeTimestamp            // UTCのミリ秒（Parquetでは TIMESTAMP_MILLIS）
+)
+
+// Column は出力する列の定義
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Table は列定義と行からなる出力データ
+// 各セルは列の型に対応するGoの値（string, int64, float64, bool, time.Time）か、値がなければnil
+type Table struct {
+	Columns []Column
+	Rows    [][]interface{}
+}
+
+// Snapshot は1つのゲートウェイから取得したモデル一覧
+type Snapshot struct {
+	Gateway   string
+	FetchedAt time.Time
+	Models    []model.Model
+}
+
+// 固定列（この後にメタデータ列が続く）
+var baseColumns = []Column{
+	{Name: "gateway", Type: TypeString},
+	{Name: "snapshot_at", Type: TypeTimestamp},
+	{Name: "model", Type: TypeString},
+	{Name: "max_tokens", Type: TypeInt64},
+	{Name: "mode", Type: TypeString},
+	{Name: "input_cost", Type: TypeDouble},
+	{Name: "variants", Type: TypeString},
+}
+
+// metaPrefix はメタデータ列の接頭辞
+const metaPrefix = "meta_"
+
+// BuildTable はスナップショットを1モデル1行の表に変換する
+// メタデータはネストしたキーを展開し、"meta_model_info_max_input_tokens" のような列にする
+// 列名はBigQueryで使える英数字とアンダースコアのみにする
+func BuildTable(snapshots []Snapshot) *Table {
+	// メタデータ列を集める
+	metaValues := make([][]map[string]interface{}, len(snapshots))
+	metaKinds := make(map[string]ColumnType)
+	for i, snapshot := range snapshots {
+		metaValues[i] = make([]map[string]interface{}, len(snapshot.Models))
+		for j, m := range snapshot.Models {
+			flat := make(map[string]interface{})
+			flatten(flat, metaPrefix, m.Metadata)
+			metaValues[i][j] = flat
+			for name, value := range flat {
+				metaKinds[name] = mergeKind(metaKinds[name], value)
+			}
+		}
+	}
+
+	metaNames := make([]string, 0, len(metaKinds))
+	for name := range metaKinds {
+		metaNames = append(metaNames, name)
+	}
+	sort.Strings(metaNames)
+
+	table := &Table{Columns: append([]Column{}, baseColumns...)}
+	for _, name := range metaNames {
+		table.Columns = append(table.Columns, Column{Name: name, Type: metaKinds[name] &^ kindSeen})
+	}
+
+	for i, snapshot := range snapshots {
+		for j, m := range snapshot.Models {
+			row := []interface{}{
+				snapshot.Gateway,
+				snapshot.FetchedAt.UTC(),
+				m.Name,
+				int64(m.MaxTokens),
+				m.Mode,
+				m.InputCost,
+				nullIfEmpty(strings.Join(m.Variants, ",")),
+			}
+			for _, name := range metaNames {
+				row = append(row, convertCell(metaValues[i][j][name], metaKinds[name]&^kindSeen))
+			}
+			table.Rows = append(table.Rows, row)
+		}
+	}
+	return table
+}
+
+// SelectColumns は指定した名前の列だけを指定した順に残した表を返す
+// namesが空の場合は表をそのまま返す
+func SelectColumns(table *Table, names []string) (*Table, error) {
+	if len(names) == 0 {
+		return table, nil
+	}
+
+	indexes := make([]int, len(names))
+	selected := &Table{Columns: make([]Column, len(names))}
+	for i, name := range names {
+		index := -1
+		for j, column := range table.Columns {
+			if column.Name == name {
+				index = j
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("unknown column: %s", name)
+		}
+		indexes[i] = index
+		selected.Columns[i] = table.Columns[index]
+	}
+
+	for _, row := range table.Rows {
+		cells := make([]interface{}, len(indexes))
+		for i, index := range indexes {
+			cells[i] = row[index]
+		}
+		selected.Rows = append(selected.Rows, cells)
+	}
+	return selected, nil
+}
+
+// kindSeen は型推論中に値を1つ以上見たことを表すフラグ
+const kindSeen ColumnType = 1 << 8
+
+// mergeKind はこれまでの型と新しい値から列の型を決める
+// すべて数値ならDOUBLE、すべて真偽値ならBOOLEAN、混在すれば文字列にする
+func mergeKind(current ColumnType, value interface{}) ColumnType {
+	var kind ColumnType
+	switch value.(type) {
+	case nil:
+		return current
+	case float64, int, int64:
+		kind = TypeDouble
+	case bool:
+		kind = TypeBoolean
+	default:
+		kind = TypeString
+	}
+	if current&kindSeen == 0 {
+		return kind | kindSeen
+	}
+	if current&^kindSeen != kind {
+		return TypeString | kindSeen
+	}
+	return current
+}
+
+// flatten はネストしたメタデータを区切り文字 "_" で展開する
+func flatten(out map[string]interface{}, prefix string, meta map[string]interface{}) {
+	for key, value := range meta {
+		name := prefix + sanitize(key)
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(out, name+"_", nested)
+			continue
+		}
+		if _, exists := out[name]; !exists {
+			out[name] = value
+		}
+	}
+}
+
+// MetaColumnName はドット区切りのメタデータのキー（"model_info.max_input_tokens"）を
+// 書き出す列名（"meta_model_info_max_input_tokens"）に変換する
+func MetaColumnName(key string) string {
+	return metaPrefix + sanitize(key)
+}
+
+// sanitize は列名に使えない文字をアンダースコアに置き換える
+func sanitize(key string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(key) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
+
+// convertCell はメタデータの値を列の型に合わせて変換する
+func convertCell(value interface{}, kind ColumnType) interface{} {
+	if value == nil {
+		return nil
+	}
+	switch kind {
+	case TypeDouble:
+		switch v := value.(type) {
+		case float64:
+			return v
+		case int:
+			return float64(v)
+		case int64:
+			return float64(v)
+		}
+	case TypeBoolean:
+		if v, ok := value.(bool); ok {
+			return v
+		}
+	}
+	return model.FormatMetaValue(value)
+}
+
+// nullIfEmpty は空文字列をnilにする
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,181 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/numfmt"
+)
+
+var testSnapshotTime = time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+
+func testSnapshots() []Snapshot {
+	return []Snapshot{
+		{
+			Gateway:   "production",
+			FetchedAt: testSnapshotTime,
+			Models: []model.Model{
+				{
+					Name:      "gpt-4o",
+					MaxTokens: 128000,
+					Mode:      "chat",
+					InputCost: 0.0000025,
+					Metadata: map[string]interface{}{
+						"owned_by":          "openai",
+						"deployment_health": "healthy (2/2)",
+						"model_info": map[string]interface{}{
+							"max_input_tokens":          float64(128000),
+							"supports_function_calling": true,
+						},
+					},
+				},
+				{
+					Name:      "claude-sonnet",
+					MaxTokens: 200000,
+					Mode:      "chat",
+					Variants:  []string{"claude-sonnet", "anthropic/claude-sonnet"},
+					Metadata: map[string]interface{}{
+						"owned_by":        "anthropic",
+						"group_providers": []interface{}{"anthropic", "bedrock"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestBuildTable(t *testing.T) {
+	table := BuildTable(testSnapshots())
+
+	var names []string
+	types := make(map[string]ColumnType)
+	for _, column := range table.Columns {
+		names = append(names, column.Name)
+		types[column.Name] = column.Type
+	}
+	want := "gateway,snapshot_at,model,max_tokens,mode,input_cost,variants," +
+		"meta_deployment_health,meta_group_providers,meta_model_info_max_input_tokens," +
+		"meta_model_info_supports_function_calling,meta_owned_by"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("columns = %s\nwant %s", got, want)
+	}
+
+	if types["meta_model_info_max_input_tokens"] != TypeDouble {
+		t.Errorf("max_input_tokens type = %v, want TypeDouble", types["meta_model_info_max_input_tokens"])
+	}
+	if types["meta_model_info_supports_function_calling"] != TypeBoolean {
+		t.Errorf("supports_function_calling type = %v, want TypeBoolean", types["meta_model_info_supports_function_calling"])
+	}
+	if types["meta_group_providers"] != TypeString {
+		t.Errorf("group_providers type = %v, want TypeString", types["meta_group_providers"])
+	}
+
+	if len(table.Rows) != 2 {
+		t.Fatalf("rows = %d, want 2", len(table.Rows))
+	}
+	second := table.Rows[1]
+	if second[6] != "claude-sonnet,anthropic/claude-sonnet" {
+		t.Errorf("variants = %v", second[6])
+	}
+	if second[8] != "anthropic, bedrock" {
+		t.Errorf("group_providers = %v", second[8])
+	}
+	if second[9] != nil || table.Rows[0][6] != nil {
+		t.Error("expected missing values to be nil")
+	}
+}
+
+func TestBuildTable_MixedTypesFallBackToString(t *testing.T) {
+	table := BuildTable([]Snapshot{{
+		Gateway: "local",
+		Models: []model.Model{
+			{Name: "a", Metadata: map[string]interface{}{"context": float64(8192)}},
+			{Name: "b", Metadata: map[string]interface{}{"context": "8k"}},
+		},
+	}})
+
+	last := table.Columns[len(table.Columns)-1]
+	if last.Name != "meta_context" || last.Type != TypeString {
+		t.Fatalf("last column = %+v, want meta_context string", last)
+	}
+	if got := table.Rows[0][len(table.Columns)-1]; got != "8192" {
+		t.Errorf("numeric value not converted to string: %v", got)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := map[string]string{
+		"owned_by":          "owned_by",
+		"Max-Input.Tokens":  "max_input_tokens",
+		"litellm_provider ": "litellm_provider_",
+	}
+	for input, want := range tests {
+		if got := sanitize(input); got != want {
+			t.Errorf("sanitize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, BuildTable(testSnapshots())); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("lines = %d, want 3:\n%s", len(lines), buf.String())
+	}
+	want := "production,2026-10-01T09:30:00Z,gpt-4o,128000,chat,0.0000025,,healthy (2/2),,128000,true,openai"
+	if lines[1] != want {
+		t.Errorf("row = %s\nwant %s", lines[1], want)
+	}
+	if !strings.Contains(lines[2], `"claude-sonnet,anthropic/claude-sonnet"`) {
+		t.Errorf("expected variants to be quoted: %s", lines[2])
+	}
+}
+
+func TestSelectColumns(t *testing.T) {
+	table, err := SelectColumns(BuildTable(testSnapshots()), []string{"model", MetaColumnName("model_info.max_input_tokens"), "gateway"})
+	if err != nil {
+		t.Fatalf("SelectColumns() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, table); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	want := "model,meta_model_info_max_input_tokens,gateway\ngpt-4o,128000,production\nclaude-sonnet,,production\n"
+	if buf.String() != want {
+		t.Errorf("csv = %q\nwant %q", buf.String(), want)
+	}
+
+	if _, err := SelectColumns(BuildTable(testSnapshots()), []string{"name"}); err == nil {
+		t.Error("expected error for unknown column")
+	}
+	if all, _ := SelectColumns(BuildTable(testSnapshots()), nil); len(all.Columns) != 12 {
+		t.Errorf("nil names should keep every column, got %d", len(all.Columns))
+	}
+}
+
+func TestFormatNumbers(t *testing.T) {
+	f, _ := numfmt.New(numfmt.StyleThousands, "")
+	table, _ := SelectColumns(FormatNumbers(BuildTable(testSnapshots()), f), []string{"model", "max_tokens", "input_cost"})
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, table); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	want := "model,max_tokens,input_cost\ngpt-4o,\"128,000\",$2.50\nclaude-sonnet,\"200,000\",$0\n"
+	if buf.String() != want {
+		t.Errorf("csv = %q\nwant %q", buf.String(), want)
+	}
+
+	raw := BuildTable(testSnapshots())
+	if FormatNumbers(raw, numfmt.Format{}) != raw {
+		t.Error("raw format should return the table unchanged")
+	}
+}
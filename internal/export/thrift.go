@@ -0,0 +1,111 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocolの型ID（Parquetのメタデータの直列化に使う）
+const (
+	compactBinary = 8
+	compactI32    = 5
+	compactI64    = 6
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter はParquetのメタデータを書くのに必要な範囲のThrift compact protocolエンコーダー
+type compactWriter struct {
+	buf       bytes.Buffer
+	lastField []int16 // 構造体ごとの直前のフィールドID
+}
+
+// beginStruct は構造体の書き込みを始める
+func (w *compactWriter) beginStruct() {
+	w.lastField = append(w.lastField, 0)
+}
+
+// endStruct は構造体を閉じる（STOPフィールドを書く）
+func (w *compactWriter) endStruct() {
+	w.buf.WriteByte(0)
+	w.lastField = w.lastField[:len(w.lastField)-1]
+}
+
+// fieldHeader はフィールドIDと型を書く
+// 直前のフィールドIDとの差が1〜15なら1バイトにまとめる
+func (w *compactWriter) fieldHeader(id int16, typ byte) {
+	last := &w.lastField[len(w.lastField)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.varint(zigzag(int64(id)))
+	}
+	*last = id
+}
+
+func (w *compactWriter) i32Field(id int16, v int32) {
+	w.fieldHeader(id, compactI32)
+	w.varint(zigzag(int64(v)))
+}
+
+func (w *compactWriter) i64Field(id int16, v int64) {
+	w.fieldHeader(id, compactI64)
+	w.varint(zigzag(v))
+}
+
+func (w *compactWriter) stringField(id int16, v string) {
+	w.fieldHeader(id, compactBinary)
+	w.binary(v)
+}
+
+// structField は構造体のフィールドを書く。body内で中身のフィールドを書く
+func (w *compactWriter) structField(id int16, body func()) {
+	w.fieldHeader(id, compactStruct)
+	w.beginStruct()
+	body()
+	w.endStruct()
+}
+
+// listField はリストのフィールドのヘッダーを書く。続けて要素をsize個書く
+func (w *compactWriter) listField(id int16, elemType byte, size int) {
+	w.fieldHeader(id, compactList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		w.buf.WriteByte(0xf0 | elemType)
+		w.varint(uint64(size))
+	}
+}
+
+// i32Elem はリストの要素としてi32を書く
+func (w *compactWriter) i32Elem(v int32) {
+	w.varint(zigzag(int64(v)))
+}
+
+// stringElem はリストの要素として文字列を書く
+func (w *compactWriter) stringElem(v string) {
+	w.binary(v)
+}
+
+// structElem はリストの要素として構造体を書く
+func (w *compactWriter) structElem(body func()) {
+	w.beginStruct()
+	body()
+	w.endStruct()
+}
+
+func (w *compactWriter) binary(v string) {
+	w.varint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+func (w *compactWriter) varint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	w.buf.Write(tmp[:n])
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
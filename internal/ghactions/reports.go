@@ -0,0 +1,169 @@
+package ghactions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/numfmt"
+	"github.com/armaniacs/llm-info/internal/probe"
+)
+
+// ProbeReportSummary は探索レポートからサマリーを作成する
+func ProbeReportSummary(report *probe.Report) *Summary {
+	s := NewSummary(fmt.Sprintf("llm-info probe: `%s`", report.Model))
+	if report.Gateway != "" {
+		s.Line(fmt.Sprintf("Gateway: `%s`\n", report.Gateway))
+	}
+
+	var rows [][]string
+	for _, result := range report.Results {
+		status := "✅"
+		value := fmt.Sprintf("%d", result.Value)
+		if !result.Success {
+			status = "❌"
+			value = "-"
+			s.Error(fmt.Sprintf("Probe failed: %s", result.Type),
+				fmt.Sprintf("%s %s: %s", report.Model, result.Type, result.ErrorMessage))
+		} else if result.Level == "low" {
+			s.Warning(fmt.Sprintf("Low confidence: %s", result.Type),
+				fmt.Sprintf("%s %s = %d (confidence %.2f)", report.Model, result.Type, result.Value, result.Confidence))
+		}
+
+		rows = append(rows, []string{
+			status,
+			result.Type,
+			value,
+			result.Method,
+			fmt.Sprintf("%.2f (%s)", result.Confidence, result.Level),
+			fmt.Sprintf("%d", result.TrialCount),
+			(time.Duration(result.DurationMs) * time.Millisecond).String(),
+			fmt.Sprintf("$%.4f", result.CostSpent),
+		})
+	}
+
+	if len(rows) == 0 {
+		s.Line("No probe results.\n")
+		s.Error("Probe failed", fmt.Sprintf("no results for %s", report.Model))
+		return s
+	}
+
+	s.Table([]string{"", "Probe", "Value", "Method", "Confidence", "Trials", "Duration", "Cost"}, rows)
+	s.Line(fmt.Sprintf("Total: %d trials, %s; %s\n",
+		report.TotalTrials, time.Duration(report.TotalDurationMs)*time.Millisecond, report.SpendSummary()))
+
+	if report.Cost != nil && report.Cost.WarningTriggered {
+		s.Warning("Cost threshold exceeded",
+			fmt.Sprintf("probe cost $%.4f exceeded the configured warning threshold", report.Cost.TotalCost))
+	}
+
+	return s
+}
+
+// ComparisonSummary はゲートウェイ比較レポートからサマリーを作成する
+func ComparisonSummary(report *probe.ComparisonReport) *Summary {
+	s := NewSummary(fmt.Sprintf("llm-info probe-compare: `%s`", report.Model))
+
+	var rows [][]string
+	for _, gw := range report.Gateways {
+		status := "✅"
+		if gw.Error != "" {
+			status = "❌"
+			s.Error(fmt.Sprintf("Probe failed: %s", gw.Gateway), gw.Error)
+		}
+		rows = append(rows, []string{
+			status,
+			gw.Gateway,
+			optionalInt(int64(gw.ContextWindow)),
+			optionalInt(int64(gw.MaxOutput)),
+			optionalLatency(gw.AvgLatencyMs),
+		})
+	}
+	s.Table([]string{"", "Gateway", "Context Window", "Max Output", "Avg Latency"}, rows)
+
+	if !report.HasDifferences() {
+		s.Line("No differences detected between gateways.\n")
+		return s
+	}
+
+	s.Heading("Differences")
+	var items []string
+	for _, diff := range report.Differences {
+		item := fmt.Sprintf("`%s`: %s=%d, %s=%d", diff.Field, diff.Lowest, diff.Min, diff.Highest, diff.Max)
+		items = append(items, item)
+		// レイテンシ以外の差異は制約値の不一致として警告する
+		if diff.Field != probe.CompareFieldLatency {
+			s.Warning(fmt.Sprintf("Gateway mismatch: %s", diff.Field), item)
+		}
+	}
+	s.List(items)
+
+	return s
+}
+
+// CatalogSummary はモデル一覧からサマリーを作成する
+// numberFormatはトークン数と入力コストの表記で、ゼロ値では従来どおりそのまま表示する
+func CatalogSummary(gateway string, models []model.Model, numberFormat numfmt.Format) *Summary {
+	s := NewSummary("llm-info models")
+	if gateway != "" {
+		s.Line(fmt.Sprintf("Gateway: `%s`\n", gateway))
+	}
+
+	costHeader := "Input Cost"
+	if numberFormat.Humanized() {
+		costHeader = "Input Cost /1M"
+	}
+	rows := make([][]string, 0, len(models))
+	for _, m := range models {
+		maxTokens, cost := optionalInt(int64(m.MaxTokens)), fmt.Sprintf("%g", m.InputCost)
+		if numberFormat.Humanized() {
+			cost = numberFormat.Cost(m.InputCost)
+			if m.MaxTokens > 0 {
+				maxTokens = numberFormat.Tokens(m.MaxTokens)
+			}
+		}
+		rows = append(rows, []string{m.Name, maxTokens, m.Mode, cost})
+	}
+	s.Table([]string{"Model", "Max Tokens", "Mode", costHeader}, rows)
+	s.Line(fmt.Sprintf("%d models\n", len(models)))
+
+	if len(models) == 0 {
+		s.Warning("No models", "the gateway returned no models")
+	}
+	return s
+}
+
+// CatalogDiffSummary はモデル一覧の差分からサマリーを作成する
+func CatalogDiffSummary(gateway string, diff model.CatalogDiff) *Summary {
+	s := NewSummary("llm-info model catalog changes")
+	if gateway != "" {
+		s.Line(fmt.Sprintf("Gateway: `%s`\n", gateway))
+	}
+
+	if diff.IsEmpty() {
+		s.Line("No changes detected.\n")
+		return s
+	}
+
+	s.List(diff.Summary())
+	for _, m := range diff.Removed {
+		s.Warning("Model removed", fmt.Sprintf("%s is no longer available", m.Name))
+	}
+	return s
+}
+
+// optionalInt は0以下の場合に"-"を返す
+func optionalInt(v int64) string {
+	if v <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", v)
+}
+
+// optionalLatency は未測定の場合に"-"を返す
+func optionalLatency(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	return (time.Duration(ms) * time.Millisecond).String()
+}
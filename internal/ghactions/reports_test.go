@@ -0,0 +1,95 @@
+package ghactions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/numfmt"
+	"github.com/armaniacs/llm-info/internal/probe"
+)
+
+func TestProbeReportSummary(t *testing.T) {
+	report := probe.NewReport("gpt-4o", "production",
+		&probe.Result{Type: probe.ProbeTypeContextWindow, Value: 128000, Success: true, Confidence: 0.9, Level: "high", TrialCount: 12},
+		&probe.Result{Type: probe.ProbeTypeMaxOutput, Success: false, ErrorMessage: "timeout"},
+	)
+
+	s := ProbeReportSummary(report)
+	markdown := s.Markdown()
+	for _, want := range []string{"`gpt-4o`", "`production`", "| ✅ | context_window | 128000 |", "| ❌ | max_output | - |"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown() missing %q in:\n%s", want, markdown)
+		}
+	}
+
+	annotations := s.Annotations()
+	if len(annotations) != 1 || annotations[0].Level != LevelError {
+		t.Errorf("Annotations() = %+v, want one error", annotations)
+	}
+}
+
+func TestProbeReportSummary_LowConfidence(t *testing.T) {
+	report := probe.NewReport("gpt-4o", "",
+		&probe.Result{Type: probe.ProbeTypeContextWindow, Value: 4096, Success: true, Confidence: 0.3, Level: "low"},
+	)
+
+	annotations := ProbeReportSummary(report).Annotations()
+	if len(annotations) != 1 || annotations[0].Level != LevelWarning {
+		t.Errorf("Annotations() = %+v, want one warning", annotations)
+	}
+}
+
+func TestComparisonSummary(t *testing.T) {
+	report := probe.NewComparisonReport("gpt-4o", []probe.GatewayComparison{
+		{Gateway: "prodA", ContextWindow: 128000, AvgLatencyMs: 100},
+		{Gateway: "prodB", ContextWindow: 32000, AvgLatencyMs: 200},
+	})
+
+	s := ComparisonSummary(report)
+	if !strings.Contains(s.Markdown(), "### Differences") {
+		t.Errorf("Markdown() missing differences:\n%s", s.Markdown())
+	}
+
+	// レイテンシの差異は警告しない
+	annotations := s.Annotations()
+	if len(annotations) != 1 || !strings.Contains(annotations[0].Title, probe.CompareFieldContextWindow) {
+		t.Errorf("Annotations() = %+v, want one context_window warning", annotations)
+	}
+}
+
+func TestCatalogDiffSummary(t *testing.T) {
+	diff := model.Diff(
+		[]model.Model{{Name: "gpt-4"}, {Name: "gpt-3.5-turbo"}},
+		[]model.Model{{Name: "gpt-4"}, {Name: "gpt-4o", MaxTokens: 128000}},
+	)
+
+	s := CatalogDiffSummary("production", diff)
+	markdown := s.Markdown()
+	if !strings.Contains(markdown, "- added gpt-4o") || !strings.Contains(markdown, "- removed gpt-3.5-turbo") {
+		t.Errorf("Markdown() = %s", markdown)
+	}
+	if len(s.Annotations()) != 1 {
+		t.Errorf("Annotations() = %+v, want one removal warning", s.Annotations())
+	}
+
+	empty := CatalogDiffSummary("", model.CatalogDiff{})
+	if !strings.Contains(empty.Markdown(), "No changes detected.") {
+		t.Errorf("Markdown() = %s", empty.Markdown())
+	}
+}
+
+func TestCatalogSummary_NumberFormat(t *testing.T) {
+	models := []model.Model{{Name: "gpt-4o", MaxTokens: 128000, Mode: "chat", InputCost: 0.0000025}}
+
+	raw := CatalogSummary("production", models, numfmt.Format{}).Markdown()
+	if !strings.Contains(raw, "| gpt-4o | 128000 | chat | 2.5e-06 |") {
+		t.Errorf("raw Markdown() = %s", raw)
+	}
+
+	f, _ := numfmt.New(numfmt.StyleSI, "")
+	si := CatalogSummary("production", models, f).Markdown()
+	if !strings.Contains(si, "Input Cost /1M") || !strings.Contains(si, "| gpt-4o | 128K | chat | $2.50 |") {
+		t.Errorf("si Markdown() = %s", si)
+	}
+}
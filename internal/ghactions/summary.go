@@ -0,0 +1,165 @@
+package ghactions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// SummaryEnv はステップサマリーの出力先を示す環境変数
+const SummaryEnv = "GITHUB_STEP_SUMMARY"
+
+// アノテーションのレベル
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNotice  = "notice"
+)
+
+// Annotation はワークフローコマンドによるアノテーション
+type Annotation struct {
+	Level   string
+	Title   string
+	Message string
+}
+
+// String はワークフローコマンド形式の文字列を返す
+func (a Annotation) String() string {
+	if a.Title == "" {
+		return fmt.Sprintf("::%s::%s", a.Level, escapeData(a.Message))
+	}
+	return fmt.Sprintf("::%s title=%s::%s", a.Level, escapeProperty(a.Title), escapeData(a.Message))
+}
+
+// Summary はMarkdown形式のステップサマリーとアノテーションを組み立てる
+type Summary struct {
+	sb          strings.Builder
+	annotations []Annotation
+}
+
+// NewSummary は見出し付きの新しいSummaryを作成する
+func NewSummary(title string) *Summary {
+	s := &Summary{}
+	s.sb.WriteString("## " + title + "\n\n")
+	return s
+}
+
+// Heading は小見出しを追加する
+func (s *Summary) Heading(text string) {
+	s.sb.WriteString("### " + text + "\n\n")
+}
+
+// Line は1行のテキストを追加する
+func (s *Summary) Line(text string) {
+	s.sb.WriteString(text + "\n")
+}
+
+// List は箇条書きを追加する
+func (s *Summary) List(items []string) {
+	for _, item := range items {
+		s.sb.WriteString("- " + item + "\n")
+	}
+	s.sb.WriteString("\n")
+}
+
+// Table はMarkdownテーブルを追加する
+func (s *Summary) Table(headers []string, rows [][]string) {
+	s.sb.WriteString("| " + strings.Join(escapeCells(headers), " | ") + " |\n")
+	s.sb.WriteString("|" + strings.Repeat(" --- |", len(headers)) + "\n")
+	for _, row := range rows {
+		s.sb.WriteString("| " + strings.Join(escapeCells(row), " | ") + " |\n")
+	}
+	s.sb.WriteString("\n")
+}
+
+// Error はエラーアノテーションを追加する
+func (s *Summary) Error(title, message string) {
+	s.annotations = append(s.annotations, Annotation{Level: LevelError, Title: title, Message: message})
+}
+
+// Warning は警告アノテーションを追加する
+func (s *Summary) Warning(title, message string) {
+	s.annotations = append(s.annotations, Annotation{Level: LevelWarning, Title: title, Message: message})
+}
+
+// Annotations は追加されたアノテーションを返す
+func (s *Summary) Annotations() []Annotation {
+	return s.annotations
+}
+
+// Markdown はサマリーのMarkdownを返す
+func (s *Summary) Markdown() string {
+	markdown := s.sb.String()
+	if len(s.annotations) == 0 {
+		return markdown
+	}
+
+	// アノテーションはサマリー末尾にも一覧として残す
+	var sb strings.Builder
+	sb.WriteString(markdown)
+	sb.WriteString("### Annotations\n\n")
+	for _, a := range s.annotations {
+		icon := "⚠️"
+		if a.Level == LevelError {
+			icon = "❌"
+		}
+		if a.Title != "" {
+			sb.WriteString(fmt.Sprintf("- %s **%s**: %s\n", icon, a.Title, a.Message))
+		} else {
+			sb.WriteString(fmt.Sprintf("- %s %s\n", icon, a.Message))
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// Write はサマリーを$GITHUB_STEP_SUMMARYに追記し、アノテーションをwに出力する
+// 環境変数が未設定の場合はサマリーの書き込みのみスキップする
+func (s *Summary) Write(w io.Writer) error {
+	for _, a := range s.annotations {
+		fmt.Fprintln(w, a.String())
+	}
+
+	path := os.Getenv(SummaryEnv)
+	if path == "" {
+		return fmt.Errorf("%s is not set; skipping step summary", SummaryEnv)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open step summary: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(s.Markdown()); err != nil {
+		return fmt.Errorf("failed to write step summary: %w", err)
+	}
+	return nil
+}
+
+// escapeData はワークフローコマンドのメッセージ部をエスケープする
+func escapeData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	s = strings.ReplaceAll(s, "\n", "%0A")
+	return s
+}
+
+// escapeProperty はワークフローコマンドのプロパティ値をエスケープする
+func escapeProperty(s string) string {
+	s = escapeData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	s = strings.ReplaceAll(s, ",", "%2C")
+	return s
+}
+
+// escapeCells はMarkdownテーブルのセル内のパイプと改行をエスケープする
+func escapeCells(cells []string) []string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", "\\|")
+		escaped[i] = strings.ReplaceAll(cell, "\n", " ")
+	}
+	return escaped
+}
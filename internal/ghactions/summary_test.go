@@ -0,0 +1,91 @@
+package ghactions
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnnotation_String(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation Annotation
+		want       string
+	}{
+		{"without title", Annotation{Level: LevelWarning, Message: "low confidence"}, "::warning::low confidence"},
+		{"with title", Annotation{Level: LevelError, Title: "Probe failed: context_window", Message: "timeout"}, "::error title=Probe failed%3A context_window::timeout"},
+		{"multiline message", Annotation{Level: LevelError, Message: "line1\nline2 100%"}, "::error::line1%0Aline2 100%25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.annotation.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummary_Markdown(t *testing.T) {
+	s := NewSummary("llm-info probe")
+	s.Table([]string{"Probe", "Value"}, [][]string{{"context_window", "128000"}, {"a|b", "1"}})
+	s.Warning("Low confidence", "context_window = 128000")
+
+	got := s.Markdown()
+	for _, want := range []string{
+		"## llm-info probe\n",
+		"| Probe | Value |\n| --- | --- |\n",
+		"| context_window | 128000 |",
+		"| a\\|b | 1 |",
+		"### Annotations",
+		"**Low confidence**",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Markdown() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestSummary_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv(SummaryEnv, path)
+
+	s := NewSummary("first")
+	s.Error("Probe failed", "boom")
+	var annotations bytes.Buffer
+	if err := s.Write(&annotations); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := NewSummary("second").Write(&annotations); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if annotations.String() != "::error title=Probe failed::boom\n" {
+		t.Errorf("annotations = %q", annotations.String())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read summary: %v", err)
+	}
+	// 既存の内容に追記されること
+	if !strings.Contains(string(data), "## first") || !strings.Contains(string(data), "## second") {
+		t.Errorf("summary file = %q", string(data))
+	}
+}
+
+func TestSummary_WriteWithoutEnv(t *testing.T) {
+	t.Setenv(SummaryEnv, "")
+
+	s := NewSummary("test")
+	s.Warning("", "still annotated")
+	var annotations bytes.Buffer
+	if err := s.Write(&annotations); err == nil {
+		t.Error("Write() should return an error when GITHUB_STEP_SUMMARY is not set")
+	}
+	if annotations.String() != "::warning::still annotated\n" {
+		t.Errorf("annotations = %q", annotations.String())
+	}
+}
@@ -0,0 +1,111 @@
+// Package hooks は設定ファイルのhooksに指定した外部コマンドを実行する
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// フックを実行するイベント
+const (
+	EventPostFetch = "post_fetch"
+	EventPostProbe = "post_probe"
+)
+
+// defaultTimeout はフックの実行のデフォルトタイムアウト
+const defaultTimeout = 30 * time.Second
+
+// Info はフックに環境変数で渡す情報
+type Info struct {
+	Gateway string // LLM_INFO_GATEWAY
+	Model   string // LLM_INFO_MODEL（探索したモデル。一覧の取得では空）
+}
+
+// Runner はイベントごとに設定されたコマンドを実行する
+type Runner struct {
+	commands map[string]string
+	timeout  time.Duration
+	output   io.Writer // コマンドの標準出力・標準エラー出力の書き込み先
+}
+
+// NewRunner は新しいRunnerを作成する
+// フックが設定されていない場合はnilを返す
+func NewRunner(cfg *config.HooksConfig) *Runner {
+	if cfg == nil || cfg.IsZero() {
+		return nil
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &Runner{
+		commands: map[string]string{
+			EventPostFetch: cfg.PostFetch,
+			EventPostProbe: cfg.PostProbe,
+		},
+		timeout: timeout,
+		// 標準出力は結果のJSONなどに使うため、コマンドの出力は標準エラー出力に流す
+		output: os.Stderr,
+	}
+}
+
+// Enabled は指定されたイベントにコマンドが設定されているかを返す
+func (r *Runner) Enabled(event string) bool {
+	return r != nil && r.commands[event] != ""
+}
+
+// Run はイベントに設定されたコマンドを実行し、resultをJSONにして標準入力に渡す
+// コマンドが設定されていないイベントやnilのRunnerの場合は何もしない
+func (r *Runner) Run(event string, info Info, result interface{}) error {
+	if !r.Enabled(event) {
+		return nil
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s hook input: %w", event, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, r.commands[event])
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Stdout = r.output
+	cmd.Stderr = r.output
+	// タイムアウトでシェルを止めても子プロセスが出力を持ち続ける場合に待ち続けない
+	cmd.WaitDelay = time.Second
+	cmd.Env = append(os.Environ(),
+		"LLM_INFO_HOOK="+event,
+		"LLM_INFO_GATEWAY="+info.Gateway,
+		"LLM_INFO_MODEL="+info.Model,
+	)
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%s hook timed out after %s", event, r.timeout)
+		}
+		return fmt.Errorf("%s hook failed: %w", event, err)
+	}
+	return nil
+}
+
+// shellCommand はコマンド文字列をシェル経由で実行するコマンドを作成する（引数やパイプを書けるようにする）
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
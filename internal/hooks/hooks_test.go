@@ -0,0 +1,100 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestNewRunner(t *testing.T) {
+	if r := NewRunner(nil); r != nil {
+		t.Error("NewRunner(nil) should return nil")
+	}
+	if r := NewRunner(&config.HooksConfig{Timeout: time.Second}); r != nil {
+		t.Error("NewRunner without commands should return nil")
+	}
+
+	// nilのRunnerは何も実行しない
+	var r *Runner
+	if r.Enabled(EventPostProbe) {
+		t.Error("nil runner should not be enabled")
+	}
+	if err := r.Run(EventPostProbe, Info{}, map[string]string{}); err != nil {
+		t.Errorf("nil runner Run() error = %v", err)
+	}
+
+	probeOnly := NewRunner(&config.HooksConfig{PostProbe: "true"})
+	if !probeOnly.Enabled(EventPostProbe) || probeOnly.Enabled(EventPostFetch) {
+		t.Error("only post_probe should be enabled")
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	out := filepath.Join(t.TempDir(), "out")
+	r := NewRunner(&config.HooksConfig{
+		PostProbe: `cat > "` + out + `"; echo "$LLM_INFO_HOOK $LLM_INFO_GATEWAY $LLM_INFO_MODEL"`,
+	})
+	var output bytes.Buffer
+	r.output = &output
+
+	result := map[string]interface{}{"model": "gpt-4o", "success": true}
+	if err := r.Run(EventPostProbe, Info{Gateway: "production", Model: "gpt-4o"}, result); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// 結果のJSONを標準入力で受け取る
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook did not write stdin: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("stdin is not JSON: %v (%s)", err, data)
+	}
+	if got["model"] != "gpt-4o" || got["success"] != true {
+		t.Errorf("stdin = %v", got)
+	}
+
+	// イベントとゲートウェイは環境変数で受け取る
+	if strings.TrimSpace(output.String()) != "post_probe production gpt-4o" {
+		t.Errorf("output = %q", output.String())
+	}
+
+	// 設定されていないイベントでは実行しない
+	if err := r.Run(EventPostFetch, Info{}, result); err != nil {
+		t.Errorf("Run(post_fetch) error = %v", err)
+	}
+}
+
+func TestRunner_RunErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	failing := NewRunner(&config.HooksConfig{PostFetch: "exit 3"})
+	failing.output = &bytes.Buffer{}
+	if err := failing.Run(EventPostFetch, Info{}, []string{}); err == nil || !strings.Contains(err.Error(), "post_fetch hook failed") {
+		t.Errorf("Run() error = %v, want exit status error", err)
+	}
+
+	slow := NewRunner(&config.HooksConfig{PostFetch: "sleep 5", Timeout: 100 * time.Millisecond})
+	slow.output = &bytes.Buffer{}
+	start := time.Now()
+	if err := slow.Run(EventPostFetch, Info{}, []string{}); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Run() error = %v, want timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Run() took %s, want to stop at the timeout", elapsed)
+	}
+}
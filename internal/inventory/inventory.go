@@ -0,0 +1,128 @@
+// Package inventory は監査証跡用に、ゲートウェイとモデルの一覧を生のレスポンスのハッシュとともに記録する
+package inventory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/search"
+)
+
+// SchemaVersion はインベントリのJSONスキーマのバージョン
+const SchemaVersion = "1"
+
+// Inventory はある時点のゲートウェイとモデルの一覧
+type Inventory struct {
+	SchemaVersion string     `json:"schema_version"`
+	GeneratedAt   time.Time  `json:"generated_at"`
+	Tool          string     `json:"tool"`
+	Gateways      []Gateway  `json:"gateways"`
+	Signature     *Signature `json:"signature,omitempty"`
+}
+
+// Gateway は1つのゲートウェイの取得結果
+type Gateway struct {
+	Name      string     `json:"name"`
+	URL       string     `json:"url"`
+	FetchedAt time.Time  `json:"fetched_at"`
+	Error     string     `json:"error,omitempty"` // 取得に失敗した場合のエラー
+	Responses []Response `json:"responses"`
+	Models    []Model    `json:"models"`
+}
+
+// Response は取得したレスポンスのハッシュ
+type Response struct {
+	Endpoint string `json:"endpoint"`
+	SHA256   string `json:"sha256"`
+	Bytes    int    `json:"bytes"`
+}
+
+// Model はインベントリに記録するモデル
+type Model struct {
+	ID        string  `json:"id"`
+	Provider  string  `json:"provider,omitempty"`
+	Mode      string  `json:"mode,omitempty"`
+	MaxTokens int     `json:"max_tokens,omitempty"`
+	InputCost float64 `json:"input_cost,omitempty"`
+	SHA256    string  `json:"sha256"` // APIが返したモデルのフィールドのハッシュ
+}
+
+// New は空のインベントリを作成する
+func New(tool string, now time.Time) *Inventory {
+	return &Inventory{
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   now.UTC(),
+		Tool:          tool,
+		Gateways:      []Gateway{},
+	}
+}
+
+// AddGateway はゲートウェイから取得したモデル一覧を追加する
+// errが nil でない場合はモデルを持たない失敗の記録として追加する
+func (inv *Inventory) AddGateway(name, url string, fetchedAt time.Time, response *api.ModelInfoResponse, err error) {
+	gw := Gateway{
+		Name:      name,
+		URL:       url,
+		FetchedAt: fetchedAt.UTC(),
+		Responses: []Response{},
+		Models:    []Model{},
+	}
+	if err != nil {
+		gw.Error = err.Error()
+		inv.Gateways = append(inv.Gateways, gw)
+		return
+	}
+
+	for _, raw := range response.Raw {
+		gw.Responses = append(gw.Responses, Response{Endpoint: raw.Endpoint, SHA256: hashBytes(raw.Body), Bytes: len(raw.Body)})
+	}
+	for _, m := range model.FromAPIResponse(response.Models) {
+		gw.Models = append(gw.Models, Model{
+			ID:        m.Name,
+			Provider:  search.Provider(m),
+			Mode:      m.Mode,
+			MaxTokens: m.MaxTokens,
+			InputCost: m.InputCost,
+			SHA256:    hashMetadata(m.Metadata),
+		})
+	}
+	sort.Slice(gw.Models, func(i, j int) bool { return gw.Models[i].ID < gw.Models[j].ID })
+	inv.Gateways = append(inv.Gateways, gw)
+}
+
+// ModelCount はすべてのゲートウェイのモデル数の合計を返す
+func (inv *Inventory) ModelCount() int {
+	n := 0
+	for _, gw := range inv.Gateways {
+		n += len(gw.Models)
+	}
+	return n
+}
+
+// Payload は署名の対象となるバイト列（署名を除いたインベントリのJSON）を返す
+func (inv *Inventory) Payload() ([]byte, error) {
+	unsigned := *inv
+	unsigned.Signature = nil
+	return json.Marshal(unsigned)
+}
+
+// hashMetadata はメタデータのハッシュを返す
+// encoding/jsonはマップのキーを整列して出力するため、同じ内容なら同じハッシュになる
+func hashMetadata(metadata map[string]interface{}) string {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return ""
+	}
+	return hashBytes(data)
+}
+
+// hashBytes はSHA-256の16進表記を返す
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,165 @@
+package inventory
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+func testInventory() *Inventory {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	inv := New("llm-info 1.0.0", now)
+	inv.AddGateway("prod", "https://llm.example.com", now, &api.ModelInfoResponse{
+		Models: []api.ModelInfo{
+			{ID: "gpt-4o", MaxTokens: 128000, Mode: "chat", InputCost: 0.0000025, Metadata: map[string]interface{}{"id": "gpt-4o", "litellm_provider": "openai"}},
+			{ID: "claude-3-haiku", MaxTokens: 200000, Metadata: map[string]interface{}{"id": "claude-3-haiku"}},
+		},
+		Raw: []api.RawResponse{{Endpoint: "/v1/models", Body: []byte(`{"data":[]}`)}},
+	}, nil)
+	inv.AddGateway("staging", "https://staging.example.com", now, nil, errors.New("connection refused"))
+	return inv
+}
+
+func TestAddGateway(t *testing.T) {
+	inv := testInventory()
+
+	if len(inv.Gateways) != 2 || inv.ModelCount() != 2 {
+		t.Fatalf("gateways = %d, models = %d", len(inv.Gateways), inv.ModelCount())
+	}
+	prod := inv.Gateways[0]
+	if prod.Models[0].ID != "claude-3-haiku" || prod.Models[1].Provider != "openai" {
+		t.Errorf("models = %+v", prod.Models)
+	}
+	if len(prod.Models[0].SHA256) != 64 || prod.Models[0].SHA256 == prod.Models[1].SHA256 {
+		t.Errorf("model hashes = %q, %q", prod.Models[0].SHA256, prod.Models[1].SHA256)
+	}
+	if r := prod.Responses[0]; r.Endpoint != "/v1/models" || r.Bytes != 11 || len(r.SHA256) != 64 {
+		t.Errorf("response = %+v", r)
+	}
+	if staging := inv.Gateways[1]; staging.Error != "connection refused" || len(staging.Models) != 0 {
+		t.Errorf("failed gateway = %+v", staging)
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		algorithm string
+		sign      func(*Inventory) error
+		public    interface{}
+	}{
+		{"ed25519", AlgorithmEd25519, func(inv *Inventory) error { return inv.Sign(edKey) }, edKey.Public()},
+		{"ecdsa", AlgorithmECDSA, func(inv *Inventory) error { return inv.Sign(ecKey) }, ecKey.Public()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv := testInventory()
+			if err := tt.sign(inv); err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			if inv.Signature.Algorithm != tt.algorithm {
+				t.Errorf("algorithm = %s, want %s", inv.Signature.Algorithm, tt.algorithm)
+			}
+
+			// ファイルに書き出して読み戻しても検証できる
+			data, err := json.Marshal(inv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var loaded Inventory
+			if err := json.Unmarshal(data, &loaded); err != nil {
+				t.Fatal(err)
+			}
+			if err := loaded.Verify(tt.public); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+			if err := loaded.Verify(nil); err != nil {
+				t.Errorf("Verify(nil) error = %v", err)
+			}
+
+			loaded.Gateways[0].Models[0].MaxTokens = 1
+			if err := loaded.Verify(tt.public); err == nil || !strings.Contains(err.Error(), "modified") {
+				t.Errorf("Verify() after tampering error = %v", err)
+			}
+		})
+	}
+
+	inv := testInventory()
+	if err := inv.Sign(edKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := inv.Verify(ecKey.Public()); err == nil || !strings.Contains(err.Error(), "not by the trusted key") {
+		t.Errorf("Verify() with another key error = %v", err)
+	}
+	if err := testInventory().Verify(nil); err == nil {
+		t.Error("Verify() of an unsigned inventory error = nil")
+	}
+}
+
+func TestLoadKeys(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privatePath := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPath := filepath.Join(dir, "key.pub.pem")
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := LoadPrivateKey(privatePath)
+	if err != nil {
+		t.Fatalf("LoadPrivateKey() error = %v", err)
+	}
+	inv := testInventory()
+	if err := inv.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{publicPath, privatePath} {
+		public, err := LoadPublicKey(path)
+		if err != nil {
+			t.Fatalf("LoadPublicKey(%s) error = %v", path, err)
+		}
+		if err := inv.Verify(public); err != nil {
+			t.Errorf("Verify() with %s error = %v", path, err)
+		}
+	}
+
+	if _, err := LoadPrivateKey(publicPath); err == nil {
+		t.Error("LoadPrivateKey() of a public key error = nil")
+	}
+}
@@ -0,0 +1,209 @@
+package inventory
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// 署名アルゴリズム
+const (
+	AlgorithmEd25519 = "ed25519"
+	AlgorithmECDSA   = "ecdsa-sha256"
+	AlgorithmRSA     = "rsa-pkcs1v15-sha256"
+)
+
+// Signature はインベントリの署名
+type Signature struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"key_id"`     // 公開鍵（DER）のSHA-256の先頭16文字
+	PublicKey string `json:"public_key"` // 公開鍵（PKIX DER）のBase64
+	Value     string `json:"value"`      // 署名のBase64
+}
+
+// Sign はインベントリに署名する。既存の署名は置き換える
+func (inv *Inventory) Sign(signer crypto.Signer) error {
+	algorithm, err := algorithmFor(signer.Public())
+	if err != nil {
+		return err
+	}
+	der, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return fmt.Errorf("failed to encode public key: %w", err)
+	}
+	payload, err := inv.Payload()
+	if err != nil {
+		return err
+	}
+
+	var value []byte
+	if algorithm == AlgorithmEd25519 {
+		value, err = signer.Sign(rand.Reader, payload, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(payload)
+		value, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to sign inventory: %w", err)
+	}
+
+	inv.Signature = &Signature{
+		Algorithm: algorithm,
+		KeyID:     keyID(der),
+		PublicKey: base64.StdEncoding.EncodeToString(der),
+		Value:     base64.StdEncoding.EncodeToString(value),
+	}
+	return nil
+}
+
+// Verify は署名を検証する
+// trustedがnilの場合はインベントリに埋め込まれた公開鍵で検証する（改ざんの検出のみで、署名者は保証しない）
+func (inv *Inventory) Verify(trusted crypto.PublicKey) error {
+	sig := inv.Signature
+	if sig == nil {
+		return errors.New("inventory is not signed")
+	}
+	der, err := base64.StdEncoding.DecodeString(sig.PublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid public key in signature: %w", err)
+	}
+	embedded, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return fmt.Errorf("invalid public key in signature: %w", err)
+	}
+	key := embedded
+	if trusted != nil {
+		trustedDER, err := x509.MarshalPKIXPublicKey(trusted)
+		if err != nil {
+			return fmt.Errorf("failed to encode public key: %w", err)
+		}
+		if keyID(trustedDER) != keyID(der) {
+			return fmt.Errorf("inventory was signed by key %s, not by the trusted key %s", keyID(der), keyID(trustedDER))
+		}
+		key = trusted
+	}
+	algorithm, err := algorithmFor(key)
+	if err != nil {
+		return err
+	}
+	if algorithm != sig.Algorithm {
+		return fmt.Errorf("signature algorithm %s does not match the %s key", sig.Algorithm, algorithm)
+	}
+	value, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil {
+		return fmt.Errorf("invalid signature value: %w", err)
+	}
+	payload, err := inv.Payload()
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(payload)
+	valid := false
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(k, payload, value)
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(k, digest[:], value)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], value) == nil
+	}
+	if !valid {
+		return errors.New("signature does not match; the inventory was modified after signing")
+	}
+	return nil
+}
+
+// LoadPrivateKey はPEM形式の秘密鍵（PKCS#8、SEC 1、PKCS#1）を読み込む
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s: unsupported PEM block %q (expected a private key)", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to parse private key: %w", path, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s: unsupported private key type %T", path, key)
+	}
+	if _, err := algorithmFor(signer.Public()); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return signer, nil
+}
+
+// LoadPublicKey はPEM形式の公開鍵を読み込む。秘密鍵が渡された場合はその公開鍵を返す
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != "PUBLIC KEY" {
+		signer, err := LoadPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return signer.Public(), nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to parse public key: %w", path, err)
+	}
+	return key, nil
+}
+
+// readPEM はファイルの最初のPEMブロックを返す
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data found", path)
+	}
+	return block, nil
+}
+
+// algorithmFor は公開鍵の種類に対応する署名アルゴリズムを返す
+func algorithmFor(key crypto.PublicKey) (string, error) {
+	switch key.(type) {
+	case ed25519.PublicKey:
+		return AlgorithmEd25519, nil
+	case *ecdsa.PublicKey:
+		return AlgorithmECDSA, nil
+	case *rsa.PublicKey:
+		return AlgorithmRSA, nil
+	default:
+		return "", fmt.Errorf("unsupported key type %T (use Ed25519, ECDSA or RSA)", key)
+	}
+}
+
+// keyID は公開鍵を識別する短いハッシュを返す
+func keyID(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,236 @@
+// Package latency はモデルを呼び出さずにゲートウェイまでのネットワーク遅延を計測する
+package latency
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// Sample は1回の計測結果
+// 各フェーズの時間はリクエスト開始からではなく、そのフェーズ単体の所要時間
+type Sample struct {
+	DNS       time.Duration
+	Connect   time.Duration
+	TLS       time.Duration
+	FirstByte time.Duration // リクエスト開始から最初のレスポンスバイトまで
+	Status    int
+	Error     string
+}
+
+// MarshalJSON は各フェーズの時間をミリ秒で出力する
+func (s Sample) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		DNSMs       float64 `json:"dns_ms"`
+		ConnectMs   float64 `json:"connect_ms"`
+		TLSMs       float64 `json:"tls_ms,omitempty"`
+		FirstByteMs float64 `json:"first_byte_ms"`
+		Status      int     `json:"status,omitempty"`
+		Error       string  `json:"error,omitempty"`
+	}{milliseconds(s.DNS), milliseconds(s.Connect), milliseconds(s.TLS), milliseconds(s.FirstByte), s.Status, s.Error})
+}
+
+// Stats は1つのフェーズの集計値
+type Stats struct {
+	Min    time.Duration
+	Median time.Duration
+	Max    time.Duration
+}
+
+// MarshalJSON は集計値をミリ秒で出力する
+func (s Stats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		MinMs    float64 `json:"min_ms"`
+		MedianMs float64 `json:"median_ms"`
+		MaxMs    float64 `json:"max_ms"`
+	}{milliseconds(s.Min), milliseconds(s.Median), milliseconds(s.Max)})
+}
+
+// milliseconds はマイクロ秒精度のミリ秒に変換する
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// Result は1つのゲートウェイの計測結果
+type Result struct {
+	Gateway   string   `json:"gateway"`
+	URL       string   `json:"url"`
+	Samples   []Sample `json:"samples"`
+	Sent      int      `json:"sent"`
+	Failed    int      `json:"failed"`
+	DNS       Stats    `json:"dns"`
+	Connect   Stats    `json:"connect"`
+	TLS       Stats    `json:"tls"`
+	FirstByte Stats    `json:"first_byte"`
+}
+
+// Reachable は1回以上計測に成功したかを返す
+func (r *Result) Reachable() bool {
+	return r.Failed < r.Sent
+}
+
+// LastError は最後に失敗した計測のエラーを返す
+func (r *Result) LastError() string {
+	for i := len(r.Samples) - 1; i >= 0; i-- {
+		if r.Samples[i].Error != "" {
+			return r.Samples[i].Error
+		}
+	}
+	return ""
+}
+
+// Pinger はゲートウェイへの接続を繰り返して遅延を計測する
+type Pinger struct {
+	Count    int           // 計測回数
+	Interval time.Duration // 計測間の待機
+	Timeout  time.Duration // 1回の計測のタイムアウト
+}
+
+// NewPinger は新しいPingerを作成する
+func NewPinger(count int, interval, timeout time.Duration) *Pinger {
+	return &Pinger{Count: count, Interval: interval, Timeout: timeout}
+}
+
+// Ping はゲートウェイのベースURLにGETリクエストを送り、DNS・TCP接続・TLS・最初のバイトまでの時間を計測する
+// 毎回新しい接続を張るため、keep-aliveは無効にする。HTTPステータスは問わない
+func (p *Pinger) Ping(name, rawURL string) *Result {
+	result := &Result{Gateway: name, URL: rawURL}
+
+	if _, err := url.ParseRequestURI(rawURL); err != nil {
+		result.Sent = 1
+		result.Failed = 1
+		result.Samples = []Sample{{Error: fmt.Sprintf("invalid URL: %v", err)}}
+		return result
+	}
+
+	client := &http.Client{
+		Timeout: p.Timeout,
+		Transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			DisableKeepAlives: true,
+			TLSClientConfig:   &tls.Config{},
+		},
+		// リダイレクト先ではなくゲートウェイ自体の遅延を計測する
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	for i := 0; i < p.Count; i++ {
+		if i > 0 && p.Interval > 0 {
+			time.Sleep(p.Interval)
+		}
+		sample := p.measure(client, rawURL)
+		result.Samples = append(result.Samples, sample)
+		result.Sent++
+		if sample.Error != "" {
+			result.Failed++
+		}
+	}
+
+	result.summarize()
+	return result
+}
+
+// measure は1回分の計測を行う
+func (p *Pinger) measure(client *http.Client, rawURL string) Sample {
+	var sample Sample
+	var dnsStart, connectStart, tlsStart time.Time
+
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			if !dnsStart.IsZero() {
+				sample.DNS = time.Since(dnsStart)
+			}
+		},
+		ConnectStart: func(string, string) { connectStart = time.Now() },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil && !connectStart.IsZero() {
+				sample.Connect = time.Since(connectStart)
+			}
+		},
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil && !tlsStart.IsZero() {
+				sample.TLS = time.Since(tlsStart)
+			}
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodGet, rawURL, nil)
+	if err != nil {
+		sample.Error = err.Error()
+		return sample
+	}
+
+	start := time.Now()
+	trace.GotFirstResponseByte = func() { sample.FirstByte = time.Since(start) }
+
+	resp, err := client.Do(req)
+	if err != nil {
+		sample.Error = err.Error()
+		return sample
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	sample.Status = resp.StatusCode
+	return sample
+}
+
+// summarize は成功した計測からフェーズごとの集計値を計算する
+func (r *Result) summarize() {
+	var dns, connect, tlsTimes, firstByte []time.Duration
+	for _, s := range r.Samples {
+		if s.Error != "" {
+			continue
+		}
+		dns = append(dns, s.DNS)
+		connect = append(connect, s.Connect)
+		if s.TLS > 0 {
+			tlsTimes = append(tlsTimes, s.TLS)
+		}
+		firstByte = append(firstByte, s.FirstByte)
+	}
+	r.DNS = Summarize(dns)
+	r.Connect = Summarize(connect)
+	r.TLS = Summarize(tlsTimes)
+	r.FirstByte = Summarize(firstByte)
+}
+
+// Summarize は最小・中央値・最大を返す
+func Summarize(values []time.Duration) Stats {
+	if len(values) == 0 {
+		return Stats{}
+	}
+	sorted := make([]time.Duration, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+	return Stats{Min: sorted[0], Median: median, Max: sorted[len(sorted)-1]}
+}
+
+// SortByFirstByte は到達できたゲートウェイを最初のバイトまでの中央値が小さい順に並べる
+// 到達できなかったゲートウェイは末尾に置く
+func SortByFirstByte(results []*Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Reachable() != b.Reachable() {
+			return a.Reachable()
+		}
+		return a.FirstByte.Median < b.FirstByte.Median
+	})
+}
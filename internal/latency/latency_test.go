@@ -0,0 +1,121 @@
+package latency
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	result := NewPinger(3, 0, 5*time.Second).Ping("local", server.URL)
+
+	if result.Sent != 3 || result.Failed != 0 {
+		t.Fatalf("Sent = %d, Failed = %d, want 3, 0 (%s)", result.Sent, result.Failed, result.LastError())
+	}
+	if !result.Reachable() {
+		t.Error("expected gateway to be reachable")
+	}
+	for i, s := range result.Samples {
+		if s.Status != http.StatusUnauthorized {
+			t.Errorf("sample %d: Status = %d, want 401", i, s.Status)
+		}
+		if s.Connect <= 0 {
+			t.Errorf("sample %d: expected a new connection per sample", i)
+		}
+	}
+	if result.FirstByte.Min < 10*time.Millisecond {
+		t.Errorf("FirstByte.Min = %v, want >= 10ms", result.FirstByte.Min)
+	}
+	if result.FirstByte.Min > result.FirstByte.Median || result.FirstByte.Median > result.FirstByte.Max {
+		t.Errorf("FirstByte stats out of order: %+v", result.FirstByte)
+	}
+}
+
+func TestPing_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	pinger := NewPinger(1, 0, 5*time.Second)
+	result := pinger.Ping("tls", server.URL)
+
+	// 自己署名証明書のため検証に失敗するが、ハンドシェイクまでは計測できる
+	if result.Failed != 1 {
+		t.Fatalf("Failed = %d, want 1", result.Failed)
+	}
+	if result.Reachable() {
+		t.Error("expected gateway to be unreachable")
+	}
+	if result.LastError() == "" {
+		t.Error("expected an error message")
+	}
+}
+
+func TestPing_InvalidURL(t *testing.T) {
+	result := NewPinger(3, 0, time.Second).Ping("bad", "gateway.example.com")
+	if result.Sent != 1 || result.Failed != 1 {
+		t.Errorf("Sent = %d, Failed = %d, want 1, 1", result.Sent, result.Failed)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		values []time.Duration
+		want   Stats
+	}{
+		{"empty", nil, Stats{}},
+		{"odd", []time.Duration{30 * ms, 10 * ms, 20 * ms}, Stats{Min: 10 * ms, Median: 20 * ms, Max: 30 * ms}},
+		{"even", []time.Duration{40 * ms, 10 * ms, 20 * ms, 30 * ms}, Stats{Min: 10 * ms, Median: 25 * ms, Max: 40 * ms}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summarize(tt.values); got != tt.want {
+				t.Errorf("Summarize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortByFirstByte(t *testing.T) {
+	results := []*Result{
+		{Gateway: "down", Sent: 1, Failed: 1},
+		{Gateway: "slow", Sent: 1, FirstByte: Stats{Median: 80 * time.Millisecond}},
+		{Gateway: "fast", Sent: 1, FirstByte: Stats{Median: 20 * time.Millisecond}},
+	}
+	SortByFirstByte(results)
+
+	want := []string{"fast", "slow", "down"}
+	for i, name := range want {
+		if results[i].Gateway != name {
+			t.Errorf("results[%d] = %s, want %s", i, results[i].Gateway, name)
+		}
+	}
+}
+
+func TestResultJSON(t *testing.T) {
+	result := &Result{
+		Gateway:   "local",
+		Sent:      1,
+		Samples:   []Sample{{Connect: 1500 * time.Microsecond, FirstByte: 12 * time.Millisecond, Status: 200}},
+		FirstByte: Stats{Min: 12 * time.Millisecond, Median: 12 * time.Millisecond, Max: 12 * time.Millisecond},
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	for _, want := range []string{`"connect_ms":1.5`, `"first_byte_ms":12`, `"first_byte":{"min_ms":12,"median_ms":12,"max_ms":12}`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("JSON %s does not contain %s", data, want)
+		}
+	}
+}
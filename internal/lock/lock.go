@@ -0,0 +1,330 @@
+// Package lock はゲートウェイごとの勧告ロックを提供する
+// 複数の人やcronが同じゲートウェイを同時に探索してレート制限に達するのを防ぐ
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/paths"
+)
+
+// DefaultTTL はハートビートが途絶えてからロックが失効するまでの時間
+// 探索が異常終了してもこの時間が過ぎれば他の実行がロックを取得できる
+const DefaultTTL = 2 * time.Minute
+
+// DefaultPollInterval は--wait中にロックを再確認する間隔
+const DefaultPollInterval = 5 * time.Second
+
+// Info はロックの保持者
+type Info struct {
+	Gateway    string    `json:"gateway"`
+	Owner      string    `json:"owner"` // user@host
+	PID        int       `json:"pid"`
+	Command    string    `json:"command,omitempty"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// sameHolder は2つのロックが同じ取得によるものかを返す
+func (i Info) sameHolder(other Info) bool {
+	return i.Owner == other.Owner && i.PID == other.PID && i.AcquiredAt.Equal(other.AcquiredAt)
+}
+
+// HeldError は他の実行がロックを保持していることを表す
+type HeldError struct {
+	Holder Info
+	Where  string // ロックの場所（local、remote）
+}
+
+func (e *HeldError) Error() string {
+	command := ""
+	if e.Holder.Command != "" {
+		command = ", " + e.Holder.Command
+	}
+	return fmt.Sprintf("gateway %s is being probed by %s (pid %d%s) since %s (%s lock); use --wait to queue or --force to take over",
+		e.Holder.Gateway, e.Holder.Owner, e.Holder.PID, command,
+		e.Holder.AcquiredAt.Local().Format("15:04:05"), e.Where)
+}
+
+// ErrNotExist はロックが存在しないことを表す
+var ErrNotExist = errors.New("lock does not exist")
+
+// Store はロックを置く場所
+type Store interface {
+	// Create はロックがなければ作成してtrueを、すでにあればfalseを返す
+	Create(name string, data []byte) (bool, error)
+	// Read はロックの内容を返す（なければErrNotExist）
+	Read(name string) ([]byte, error)
+	// Write はロックを上書きする（ハートビートと--force用）
+	Write(name string, data []byte) error
+	// Replace はロックの内容がoldのままのときだけdataに置き換え、置き換えたかを返す
+	// 失効したロックを複数のプロセスが同時に奪わないために使う
+	Replace(name string, old, data []byte) (bool, error)
+	// Remove はロックを削除する
+	Remove(name string) error
+	// String はメッセージ用の名前を返す
+	String() string
+}
+
+// Options はロック取得の動作
+type Options struct {
+	Wait    time.Duration // ロックが解放されるまで待つ最大時間（0は待たない）
+	Force   bool          // 保持者がいても奪う
+	Command string        // 保持者として表示するコマンド
+	// OnWait は待ち始めたときに一度だけ呼ばれる
+	OnWait func(holder Info)
+}
+
+// Locker はロックを取得する
+type Locker struct {
+	stores []Store
+	ttl    time.Duration
+	poll   time.Duration
+	now    func() time.Time
+	owner  string
+}
+
+// NewLocker はstoresのすべてにロックを置くLockerを作成する（ローカル、リモートの順を想定）
+func NewLocker(stores ...Store) *Locker {
+	return &Locker{
+		stores: stores,
+		ttl:    DefaultTTL,
+		poll:   DefaultPollInterval,
+		now:    time.Now,
+		owner:  currentOwner(),
+	}
+}
+
+// Lock は取得したロック。Releaseで解放する
+type Lock struct {
+	locker *Locker
+	name   string
+	info   Info
+	stores []Store
+	stop   chan struct{}
+	done   sync.WaitGroup
+	once   sync.Once
+}
+
+// Info はロックの内容を返す
+func (lk *Lock) Info() Info {
+	return lk.info
+}
+
+// Acquire はゲートウェイのロックを取得する
+// 保持者がいる場合、opts.Waitの間待ってから*HeldErrorを返す。opts.Forceなら奪う
+func (l *Locker) Acquire(gateway string, opts Options) (*Lock, error) {
+	name := Name(gateway)
+	deadline := l.now().Add(opts.Wait)
+	notified := false
+
+	for {
+		info := Info{
+			Gateway:    gateway,
+			Owner:      l.owner,
+			PID:        os.Getpid(),
+			Command:    opts.Command,
+			AcquiredAt: l.now(),
+		}
+		info.ExpiresAt = info.AcquiredAt.Add(l.ttl)
+
+		lk, held, err := l.tryAcquire(name, info, opts.Force)
+		if err != nil {
+			return nil, err
+		}
+		if lk != nil {
+			return lk, nil
+		}
+
+		if !l.now().Before(deadline) {
+			return nil, held
+		}
+		if !notified && opts.OnWait != nil {
+			opts.OnWait(held.Holder)
+			notified = true
+		}
+		wait := l.poll
+		if remaining := deadline.Sub(l.now()); remaining < wait {
+			wait = remaining
+		}
+		time.Sleep(wait)
+	}
+}
+
+// tryAcquire はすべての場所でロックを取得する。取得できなければ取得した分を戻す
+func (l *Locker) tryAcquire(name string, info Info, force bool) (*Lock, *HeldError, error) {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var acquired []Store
+	rollback := func() {
+		for _, store := range acquired {
+			store.Remove(name)
+		}
+	}
+
+	for _, store := range l.stores {
+		ok, holder, err := l.acquireIn(store, name, data, force)
+		if err != nil {
+			rollback()
+			return nil, nil, fmt.Errorf("failed to acquire %s lock: %w", store, err)
+		}
+		if !ok {
+			rollback()
+			return nil, &HeldError{Holder: holder, Where: store.String()}, nil
+		}
+		acquired = append(acquired, store)
+	}
+
+	lk := &Lock{locker: l, name: name, info: info, stores: acquired, stop: make(chan struct{})}
+	lk.done.Add(1)
+	go lk.heartbeat()
+	return lk, nil, nil
+}
+
+// acquireIn は1つの場所でロックを取得する。失効したロックは読んだ内容のままなら置き換える
+func (l *Locker) acquireIn(store Store, name string, data []byte, force bool) (bool, Info, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		ok, err := store.Create(name, data)
+		if err != nil || ok {
+			return ok, Info{}, err
+		}
+
+		stale, err := store.Read(name)
+		if errors.Is(err, ErrNotExist) {
+			// 読む前に解放された
+			continue
+		}
+		if err != nil {
+			return false, Info{}, err
+		}
+		holder, err := decodeInfo(name, stale)
+		if err == nil && l.now().Before(holder.ExpiresAt) && !force {
+			return false, holder, nil
+		}
+		if force {
+			return true, Info{}, store.Write(name, data)
+		}
+		// 失効したか壊れたロック。同時に奪おうとした他のプロセスが先に置き換えていれば
+		// 次の試行でそちらを保持者として読む
+		ok, err = store.Replace(name, stale, data)
+		if err != nil || ok {
+			return ok, Info{}, err
+		}
+	}
+	holder, _ := l.read(store, name)
+	return false, holder, nil
+}
+
+// read はロックの内容を読む
+func (l *Locker) read(store Store, name string) (Info, error) {
+	data, err := store.Read(name)
+	if err != nil {
+		return Info{}, err
+	}
+	return decodeInfo(name, data)
+}
+
+// decodeInfo はロックの内容を解析する
+func decodeInfo(name string, data []byte) (Info, error) {
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return Info{}, fmt.Errorf("invalid lock %s: %w", name, err)
+	}
+	return info, nil
+}
+
+// heartbeat は保持している間、有効期限を延長し続ける
+func (lk *Lock) heartbeat() {
+	defer lk.done.Done()
+	ticker := time.NewTicker(lk.locker.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-lk.stop:
+			return
+		case <-ticker.C:
+			info := lk.info
+			info.ExpiresAt = lk.locker.now().Add(lk.locker.ttl)
+			data, err := json.Marshal(info)
+			if err != nil {
+				continue
+			}
+			for _, store := range lk.stores {
+				// --forceで奪われたロックは上書きしない
+				if current, err := lk.locker.read(store, lk.name); err == nil && current.sameHolder(lk.info) {
+					store.Write(lk.name, data)
+				}
+			}
+		}
+	}
+}
+
+// Release はロックを解放する。他の実行に奪われていた場合は削除しない
+func (lk *Lock) Release() error {
+	if lk == nil {
+		return nil
+	}
+	var firstErr error
+	lk.once.Do(func() {
+		close(lk.stop)
+		lk.done.Wait()
+		for _, store := range lk.stores {
+			current, err := lk.locker.read(store, lk.name)
+			if err != nil || !current.sameHolder(lk.info) {
+				continue
+			}
+			if err := store.Remove(lk.name); err != nil && !errors.Is(err, ErrNotExist) && firstErr == nil {
+				firstErr = err
+			}
+		}
+	})
+	return firstErr
+}
+
+// Name はゲートウェイのURLからロック名を作る
+// 同じゲートウェイを別の名前で登録していても同じロックになるよう、スキームを除いたホストとパスを使う
+func Name(gateway string) string {
+	key := gateway
+	if parsed, err := url.Parse(gateway); err == nil && parsed.Host != "" {
+		key = parsed.Host + strings.TrimRight(parsed.Path, "/")
+	}
+	var sb strings.Builder
+	for _, r := range strings.ToLower(key) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '.' {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String() + ".lock"
+}
+
+// GetDefaultLockDir はデフォルトのロックディレクトリを返す
+func GetDefaultLockDir() string {
+	return paths.StateFile("locks")
+}
+
+// currentOwner は「ユーザー名@ホスト名」を返す
+func currentOwner() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "localhost"
+	}
+	return name + "@" + host
+}
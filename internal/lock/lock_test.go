@@ -0,0 +1,308 @@
+package lock
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestLocker(owner string, stores ...Store) *Locker {
+	l := NewLocker(stores...)
+	l.owner = owner
+	l.ttl = time.Minute
+	l.poll = 10 * time.Millisecond
+	return l
+}
+
+func TestAcquire_HeldByOther(t *testing.T) {
+	dir := NewDirStore(t.TempDir())
+	alice := newTestLocker("alice@laptop", dir)
+	bob := newTestLocker("bob@desktop", dir)
+
+	lk, err := alice.Acquire("https://llm.example.com/v1", Options{Command: "probe-context"})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	_, err = bob.Acquire("https://llm.example.com/v1/", Options{})
+	var held *HeldError
+	if !errors.As(err, &held) {
+		t.Fatalf("Acquire() error = %v, want *HeldError", err)
+	}
+	if held.Holder.Owner != "alice@laptop" || held.Holder.Command != "probe-context" || held.Where != "local" {
+		t.Errorf("holder = %+v (%s)", held.Holder, held.Where)
+	}
+
+	// Another gateway is not affected
+	other, err := bob.Acquire("https://other.example.com", Options{})
+	if err != nil {
+		t.Fatalf("Acquire(other) error = %v", err)
+	}
+	other.Release()
+
+	if err := lk.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	lk2, err := bob.Acquire("https://llm.example.com/v1", Options{})
+	if err != nil {
+		t.Fatalf("Acquire() after release error = %v", err)
+	}
+	lk2.Release()
+}
+
+func TestAcquire_Wait(t *testing.T) {
+	dir := NewDirStore(t.TempDir())
+	alice := newTestLocker("alice@laptop", dir)
+	bob := newTestLocker("bob@desktop", dir)
+
+	lk, err := alice.Acquire("gw", Options{})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		lk.Release()
+	}()
+
+	waited := false
+	lk2, err := bob.Acquire("gw", Options{Wait: 5 * time.Second, OnWait: func(Info) { waited = true }})
+	if err != nil {
+		t.Fatalf("Acquire(--wait) error = %v", err)
+	}
+	defer lk2.Release()
+	if !waited {
+		t.Error("OnWait was not called")
+	}
+
+	// The wait gives up once the deadline passes
+	start := time.Now()
+	if _, err := alice.Acquire("gw", Options{Wait: 30 * time.Millisecond}); err == nil {
+		t.Fatal("Acquire() should time out")
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Acquire() waited %v", time.Since(start))
+	}
+}
+
+func TestAcquire_ForceAndStale(t *testing.T) {
+	dir := NewDirStore(t.TempDir())
+	alice := newTestLocker("alice@laptop", dir)
+	bob := newTestLocker("bob@desktop", dir)
+
+	lk, err := alice.Acquire("gw", Options{})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	forced, err := bob.Acquire("gw", Options{Force: true})
+	if err != nil {
+		t.Fatalf("Acquire(--force) error = %v", err)
+	}
+	// Releasing the lock that was taken over must not remove Bob's lock
+	lk.Release()
+	if _, err := alice.Acquire("gw", Options{}); err == nil {
+		t.Fatal("Alice's release removed the forced lock")
+	}
+	forced.Release()
+
+	// An expired lock (e.g. after a crash) is taken over without --force
+	past := time.Now().Add(-time.Hour)
+	stale, _ := json.Marshal(Info{Gateway: "gw", Owner: "cron@server", PID: 1, AcquiredAt: past, ExpiresAt: past.Add(time.Minute)})
+	if err := dir.Write(Name("gw"), stale); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	lk3, err := alice.Acquire("gw", Options{})
+	if err != nil {
+		t.Fatalf("Acquire() over a stale lock error = %v", err)
+	}
+	lk3.Release()
+}
+
+// barrierStore holds the first two reads until both have happened, so
+// competing lockers both see the lock before either of them replaces it
+type barrierStore struct {
+	Store
+	count atomic.Int32
+	reads sync.WaitGroup
+}
+
+func (b *barrierStore) Read(name string) ([]byte, error) {
+	data, err := b.Store.Read(name)
+	if b.count.Add(1) <= 2 {
+		b.reads.Done()
+		b.reads.Wait()
+	}
+	return data, err
+}
+
+func TestAcquire_StaleRace(t *testing.T) {
+	stores := map[string]func() Store{
+		"local": func() Store { return NewDirStore(t.TempDir()) },
+		"remote": func() Store {
+			return NewRemoteStore(&memoryObjects{objects: map[string][]byte{}}, "team", errMissing)
+		},
+	}
+	for where, newStore := range stores {
+		t.Run(where, func(t *testing.T) {
+			store := newStore()
+			past := time.Now().Add(-time.Hour)
+			stale, _ := json.Marshal(Info{Gateway: "gw", Owner: "cron@server", PID: 1, AcquiredAt: past, ExpiresAt: past.Add(time.Minute)})
+			if err := store.Write(Name("gw"), stale); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			// Two lockers see the same expired lock; only one of them may take it over
+			shared := &barrierStore{Store: store}
+			shared.reads.Add(2)
+			alice := newTestLocker("alice@laptop", shared)
+			bob := newTestLocker("bob@desktop", shared)
+			var wg sync.WaitGroup
+			locks := make([]*Lock, 2)
+			errs := make([]error, 2)
+			for i, locker := range []*Locker{alice, bob} {
+				wg.Add(1)
+				go func(i int, locker *Locker) {
+					defer wg.Done()
+					locks[i], errs[i] = locker.Acquire("gw", Options{})
+				}(i, locker)
+			}
+			wg.Wait()
+
+			acquired := 0
+			for i := range locks {
+				if errs[i] == nil {
+					acquired++
+					defer locks[i].Release()
+					continue
+				}
+				var held *HeldError
+				if !errors.As(errs[i], &held) {
+					t.Fatalf("Acquire() error = %v, want *HeldError", errs[i])
+				}
+			}
+			if acquired != 1 {
+				t.Fatalf("%d lockers took over the stale lock, want 1", acquired)
+			}
+		})
+	}
+}
+
+func TestAcquire_Heartbeat(t *testing.T) {
+	dir := NewDirStore(t.TempDir())
+	alice := newTestLocker("alice@laptop", dir)
+	alice.ttl = 60 * time.Millisecond
+
+	lk, err := alice.Acquire("gw", Options{})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer lk.Release()
+
+	// Well past the TTL the lock is still held thanks to the heartbeat
+	time.Sleep(150 * time.Millisecond)
+	bob := newTestLocker("bob@desktop", dir)
+	if _, err := bob.Acquire("gw", Options{}); err == nil {
+		t.Fatal("lock expired despite the heartbeat")
+	}
+}
+
+// memoryObjects is an ObjectStore with conditional writes
+type memoryObjects struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+var errMissing = errors.New("missing")
+
+func (m *memoryObjects) PutIfAbsent(key string, data []byte) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.objects[key]; ok {
+		return false, nil
+	}
+	m.objects[key] = data
+	return true, nil
+}
+
+func (m *memoryObjects) PutIfMatch(key string, old, data []byte) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if current, ok := m.objects[key]; !ok || !bytes.Equal(current, old) {
+		return false, nil
+	}
+	m.objects[key] = data
+	return true, nil
+}
+
+func (m *memoryObjects) Put(key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = data
+	return nil
+}
+
+func (m *memoryObjects) Get(key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, errMissing
+	}
+	return data, nil
+}
+
+func (m *memoryObjects) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+func TestAcquire_Remote(t *testing.T) {
+	objects := &memoryObjects{objects: map[string][]byte{}}
+	remote := NewRemoteStore(objects, "team", errMissing)
+	alice := newTestLocker("alice@laptop", NewDirStore(t.TempDir()), remote)
+	bob := newTestLocker("bob@desktop", NewDirStore(t.TempDir()), remote)
+
+	lk, err := alice.Acquire("https://llm.example.com", Options{})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if _, ok := objects.objects["team/locks/llm.example.com.lock"]; !ok {
+		t.Fatalf("remote objects = %v", objects.objects)
+	}
+
+	_, err = bob.Acquire("https://llm.example.com", Options{})
+	var held *HeldError
+	if !errors.As(err, &held) || held.Where != "remote" {
+		t.Fatalf("Acquire() error = %v, want a remote *HeldError", err)
+	}
+
+	// Bob's local lock was rolled back, so he can retry after Alice finishes
+	lk.Release()
+	if len(objects.objects) != 0 {
+		t.Errorf("remote objects after release = %v", objects.objects)
+	}
+	lk2, err := bob.Acquire("https://llm.example.com", Options{})
+	if err != nil {
+		t.Fatalf("Acquire() after release error = %v", err)
+	}
+	lk2.Release()
+}
+
+func TestName(t *testing.T) {
+	tests := map[string]string{
+		"https://llm.example.com/v1": "llm.example.com_v1.lock",
+		"http://LLM.example.com/v1/": "llm.example.com_v1.lock",
+		"http://localhost:4000":      "localhost_4000.lock",
+		"my gateway":                 "my_gateway.lock",
+	}
+	for gateway, want := range tests {
+		if got := Name(gateway); got != want {
+			t.Errorf("Name(%q) = %q, want %q", gateway, got, want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package lock
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// DirStore はローカルディレクトリにロックファイルを置く
+// 同じマシン上の実行（cronと手動実行など）の衝突を防ぐ
+type DirStore struct {
+	dir string
+}
+
+// NewDirStore はdirにロックファイルを置くStoreを作成する
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{dir: dir}
+}
+
+// Create は一時ファイルをハードリンクしてロックファイルを作成する
+// リンクは既存のファイルを上書きしないので、書きかけの内容が他のプロセスから見えることもない
+func (d *DirStore) Create(name string, data []byte) (bool, error) {
+	if err := os.MkdirAll(d.dir, 0755); err != nil {
+		return false, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	tmp, err := d.writeTemp(name, data)
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp)
+
+	err = os.Link(tmp, filepath.Join(d.dir, name))
+	if errors.Is(err, os.ErrExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Read はロックファイルを読む
+func (d *DirStore) Read(name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(d.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotExist
+	}
+	return data, err
+}
+
+// Write は一時ファイル経由でロックファイルを置き換える
+func (d *DirStore) Write(name string, data []byte) error {
+	tmp, err := d.writeTemp(name, data)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(d.dir, name)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// writeTemp はロックファイルと同じディレクトリにdataを書いた一時ファイルを作る
+func (d *DirStore) writeTemp(name string, data []byte) (string, error) {
+	tmp, err := os.CreateTemp(d.dir, name+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// Replace はロックファイルの内容がoldのときだけdataに置き換える
+// リネームはアトミックなので、ロックファイルを一意な名前へ移して確保できるのは1プロセスだけになる
+func (d *DirStore) Replace(name string, old, data []byte) (bool, error) {
+	claim, err := os.CreateTemp(d.dir, name+".*.stale")
+	if err != nil {
+		return false, err
+	}
+	claim.Close()
+	defer os.Remove(claim.Name())
+
+	if err := os.Rename(filepath.Join(d.dir, name), claim.Name()); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// 他のプロセスが先に確保した
+			return false, nil
+		}
+		return false, err
+	}
+	current, err := os.ReadFile(claim.Name())
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Equal(current, old) {
+		// 他のプロセスが置き換えた後のロックだったので元に戻す
+		_, err := d.Create(name, current)
+		return false, err
+	}
+	return d.Create(name, data)
+}
+
+// Remove はロックファイルを削除する
+func (d *DirStore) Remove(name string) error {
+	err := os.Remove(filepath.Join(d.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotExist
+	}
+	return err
+}
+
+func (d *DirStore) String() string {
+	return "local"
+}
+
+// ObjectStore は条件付き書き込みができるオブジェクトストレージ
+// storage.S3Storeが満たす
+type ObjectStore interface {
+	PutIfAbsent(key string, data []byte) (bool, error)
+	PutIfMatch(key string, old, data []byte) (bool, error)
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+}
+
+// RemoteStore は共有のオブジェクトストレージにロックを置く
+// 別のマシンから同じゲートウェイを探索するチームメンバー間の衝突を防ぐ
+type RemoteStore struct {
+	store    ObjectStore
+	prefix   string
+	notFound error
+}
+
+// NewRemoteStore はprefix/locks/以下にロックを置くStoreを作成する
+// notFoundはstoreがオブジェクトの不在を表すエラー
+func NewRemoteStore(store ObjectStore, prefix string, notFound error) *RemoteStore {
+	return &RemoteStore{store: store, prefix: prefix, notFound: notFound}
+}
+
+func (r *RemoteStore) key(name string) string {
+	return path.Join(r.prefix, "locks", name)
+}
+
+// Create は条件付きPUTでロックを作成する
+func (r *RemoteStore) Create(name string, data []byte) (bool, error) {
+	return r.store.PutIfAbsent(r.key(name), data)
+}
+
+// Read はロックを読む
+func (r *RemoteStore) Read(name string) ([]byte, error) {
+	data, err := r.store.Get(r.key(name))
+	if err != nil && r.notFound != nil && errors.Is(err, r.notFound) {
+		return nil, ErrNotExist
+	}
+	return data, err
+}
+
+// Write はロックを上書きする
+func (r *RemoteStore) Write(name string, data []byte) error {
+	return r.store.Put(r.key(name), data)
+}
+
+// Replace は内容がoldのままのときだけ条件付きPUTでロックを置き換える
+func (r *RemoteStore) Replace(name string, old, data []byte) (bool, error) {
+	return r.store.PutIfMatch(r.key(name), old, data)
+}
+
+// Remove はロックを削除する
+func (r *RemoteStore) Remove(name string) error {
+	return r.store.Delete(r.key(name))
+}
+
+func (r *RemoteStore) String() string {
+	return "remote"
+}
@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Outcomes of a JSONL record
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomeResult  = "result"
+)
+
+// TrialRecord is one line of a JSONL probe log. Every record is flat and
+// self-describing so it can be indexed without knowing the other lines.
+type TrialRecord struct {
+	Timestamp        time.Time       `json:"timestamp"`
+	ExecutionID      string          `json:"execution_id"`
+	Model            string          `json:"model"`
+	Gateway          string          `json:"gateway"`
+	Phase            string          `json:"phase"` // context, max_output, max_input
+	Trial            int             `json:"trial"`
+	Tokens           int             `json:"tokens"`
+	LatencyMS        int64           `json:"latency_ms"`
+	DurationMS       int64           `json:"duration_ms"`
+	Outcome          string          `json:"outcome"` // success, failure, result
+	Error            string          `json:"error,omitempty"`
+	PromptTokens     int             `json:"prompt_tokens,omitempty"`
+	CompletionTokens int             `json:"completion_tokens,omitempty"`
+	TotalTokens      int             `json:"total_tokens,omitempty"`
+	Result           json.RawMessage `json:"result,omitempty"`
+	Version          string          `json:"version"`
+}
+
+// JSONLProbeLogger implements ProbeLogger writing one TrialRecord per line
+// to a daily .jsonl file per model and probe type
+type JSONLProbeLogger struct {
+	dir         string
+	executionID string
+}
+
+// LogTrial logs a single trial attempt
+func (l *JSONLProbeLogger) LogTrial(model, gateway string, probeType string, trial TrialLogEntry) error {
+	record := TrialRecord{
+		Timestamp:        trial.Timestamp,
+		ExecutionID:      l.executionID,
+		Model:            model,
+		Gateway:          gateway,
+		Phase:            probeType,
+		Trial:            trial.Index,
+		Tokens:           trial.TokenCount,
+		LatencyMS:        trial.Latency.Milliseconds(),
+		DurationMS:       trial.Duration.Milliseconds(),
+		Outcome:          OutcomeFailure,
+		Error:            trial.ErrorMessage,
+		PromptTokens:     trial.PromptTokens,
+		CompletionTokens: trial.CompletionTokens,
+		TotalTokens:      trial.TotalTokens,
+		Version:          "2.1.0",
+	}
+	if trial.Success {
+		record.Outcome = OutcomeSuccess
+	}
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now()
+	}
+	return l.write(model, probeType, record)
+}
+
+// LogResult logs the final probe result as a record with outcome "result"
+func (l *JSONLProbeLogger) LogResult(model, gateway string, probeType string, result interface{}) error {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return l.write(model, probeType, TrialRecord{
+		Timestamp:   time.Now(),
+		ExecutionID: l.executionID,
+		Model:       model,
+		Gateway:     gateway,
+		Phase:       probeType,
+		Trial:       -1,
+		Outcome:     OutcomeResult,
+		Result:      resultJSON,
+		Version:     "2.1.0",
+	})
+}
+
+// Close finalizes the logger
+func (l *JSONLProbeLogger) Close() error {
+	return nil
+}
+
+func (l *JSONLProbeLogger) write(model, probeType string, record TrialRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log entry: %w", err)
+	}
+	return appendLogLine(logFilePath(l.dir, model, probeType, ".jsonl"), line)
+}
@@ -6,9 +6,38 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/armaniacs/llm-info/internal/paths"
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+// Log formats
+const (
+	// FormatJSON writes one envelope per trial with the trial and the session
+	// metadata nested (the default)
+	FormatJSON = "json"
+	// FormatJSONL writes one flat record per trial that log shippers such as
+	// Filebeat can ingest without further parsing
+	FormatJSONL = "jsonl"
 )
 
+// Formats returns the supported log formats
+func Formats() []string {
+	return []string{FormatJSON, FormatJSONL}
+}
+
+// ValidateFormat returns an error for an unsupported log format; an empty
+// format selects the default
+func ValidateFormat(format string) error {
+	switch format {
+	case "", FormatJSON, FormatJSONL:
+		return nil
+	}
+	return fmt.Errorf("unknown log format: %s (supported: %s)", format, strings.Join(Formats(), ", "))
+}
+
 // ProbeLogger defines the interface for probe logging
 type ProbeLogger interface {
 	LogTrial(model, gateway string, probeType string, trial TrialLogEntry) error
@@ -18,13 +47,19 @@ type ProbeLogger interface {
 
 // TrialLogEntry represents a single trial in the probing process
 type TrialLogEntry struct {
-	Index        int           `json:"index"`
-	TokenCount   int           `json:"token_count"`
-	Success      bool          `json:"success"`
-	Message      string        `json:"message,omitempty"`
-	Duration     time.Duration `json:"duration"`
-	Timestamp    time.Time     `json:"timestamp"`
-	ErrorMessage string        `json:"error_message,omitempty"`
+	Index            int           `json:"index"`
+	TokenCount       int           `json:"token_count"`
+	Success          bool          `json:"success"`
+	Message          string        `json:"message,omitempty"`
+	Duration         time.Duration `json:"duration"`
+	Timestamp        time.Time     `json:"timestamp"`
+	ErrorMessage     string        `json:"error_message,omitempty"`
+	StartedAt        time.Time     `json:"started_at,omitempty"`
+	EndedAt          time.Time     `json:"ended_at,omitempty"`
+	Latency          time.Duration `json:"latency,omitempty"`
+	PromptTokens     int           `json:"prompt_tokens,omitempty"`
+	CompletionTokens int           `json:"completion_tokens,omitempty"`
+	TotalTokens      int           `json:"total_tokens,omitempty"`
 }
 
 // ProbeLogEntry represents a complete probe log entry
@@ -67,6 +102,9 @@ func NewProbeLogger(config ProbeLogConfig) (ProbeLogger, error) {
 	if !config.Enabled {
 		return &NoOpLogger{}, nil
 	}
+	if err := ValidateFormat(config.Format); err != nil {
+		return nil, err
+	}
 
 	// Expand ~ to home directory
 	if len(config.Dir) > 0 && config.Dir[0] == '~' {
@@ -82,6 +120,10 @@ func NewProbeLogger(config ProbeLogConfig) (ProbeLogger, error) {
 		return nil, fmt.Errorf("failed to create log directory: %w", err)
 	}
 
+	if config.Format == FormatJSONL {
+		return &JSONLProbeLogger{dir: config.Dir, executionID: generateUUID()}, nil
+	}
+
 	logger := &JSONProbeLogger{
 		config:      config,
 		executionID: generateUUID(),
@@ -93,18 +135,6 @@ func NewProbeLogger(config ProbeLogConfig) (ProbeLogger, error) {
 
 // LogTrial logs a single trial attempt
 func (l *JSONProbeLogger) LogTrial(model, gateway string, probeType string, trial TrialLogEntry) error {
-	// Generate log file name
-	date := time.Now().Format("2006-01-02")
-	logFileName := fmt.Sprintf("%s-%s-%s.log", date, sanitizeModelName(model), probeType)
-	logFilePath := filepath.Join(l.config.Dir, logFileName)
-
-	// Open log file in append mode
-	file, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to open log file: %w", err)
-	}
-	defer file.Close()
-
 	// Create log entry
 	entry := ProbeLogEntry{
 		Timestamp: time.Now(),
@@ -125,12 +155,7 @@ func (l *JSONProbeLogger) LogTrial(model, gateway string, probeType string, tria
 		return fmt.Errorf("failed to marshal log entry: %w", err)
 	}
 
-	// Write to file with newline
-	if _, err := file.Write(append(entryJSON, '\n')); err != nil {
-		return fmt.Errorf("failed to write log entry: %w", err)
-	}
-
-	return nil
+	return appendLogLine(logFilePath(l.config.Dir, model, probeType, ".log"), entryJSON)
 }
 
 // LogResult logs the final probe result
@@ -176,6 +201,27 @@ func (l *NoOpLogger) Close() error {
 	return nil
 }
 
+// logFilePath returns the daily log file of a model and probe type
+func logFilePath(dir, model, probeType, ext string) string {
+	date := time.Now().Format("2006-01-02")
+	return filepath.Join(dir, fmt.Sprintf("%s-%s-%s%s", date, sanitizeModelName(model), probeType, ext))
+}
+
+// appendLogLine appends one line to a log file; error messages from the
+// gateway may echo the API key, so the line is redacted first
+func appendLogLine(path string, line []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(redact.Bytes(line), '\n')); err != nil {
+		return fmt.Errorf("failed to write log entry: %w", err)
+	}
+	return nil
+}
+
 // sanitizeModelName sanitizes model name for file system
 func sanitizeModelName(model string) string {
 	// Replace slashes and other problematic characters
@@ -222,14 +268,9 @@ func generateUUID() string {
 
 // GetDefaultLogConfig returns default logging configuration
 func GetDefaultLogConfig() ProbeLogConfig {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		home = "/tmp"
-	}
-
 	return ProbeLogConfig{
 		Enabled:         true,
-		Dir:             filepath.Join(home, ".config", "llm-info", "log"),
+		Dir:             paths.StateFile("log"),
 		Format:          "json",
 		IncludeHistory:  true,
 		Compress:        false,
@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+func TestJSONProbeLogger_RedactsSecrets(t *testing.T) {
+	redact.Reset()
+	defer redact.Reset()
+	redact.Register("gw-secret-123")
+
+	dir := t.TempDir()
+	logger, err := NewProbeLogger(ProbeLogConfig{Enabled: true, Dir: dir, Format: "json"})
+	if err != nil {
+		t.Fatalf("NewProbeLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	trial := TrialLogEntry{
+		Index:        1,
+		TokenCount:   1000,
+		ErrorMessage: "API error (invalid_request_error): Incorrect API key provided: gw-secret-123",
+	}
+	if err := logger.LogTrial("gpt-4", "default", "context", trial); err != nil {
+		t.Fatalf("LogTrial() error = %v", err)
+	}
+	if err := logger.LogResult("gpt-4", "default", "context", map[string]string{"api_key": "gw-secret-123"}); err != nil {
+		t.Fatalf("LogResult() error = %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected one log file, got %v (%v)", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+
+	if strings.Contains(string(data), "gw-secret-123") {
+		t.Errorf("log file contains the API key:\n%s", data)
+	}
+	if strings.Count(string(data), "\n") != 2 {
+		t.Errorf("expected two JSON lines, got:\n%s", data)
+	}
+}
+
+func TestJSONLProbeLogger(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := NewProbeLogger(ProbeLogConfig{Enabled: true, Dir: dir, Format: FormatJSONL})
+	if err != nil {
+		t.Fatalf("NewProbeLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	started := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	trials := []TrialLogEntry{
+		{Index: 0, TokenCount: 4096, Success: true, Timestamp: started, Duration: 1500 * time.Millisecond, Latency: 1200 * time.Millisecond, PromptTokens: 4100, CompletionTokens: 16, TotalTokens: 4116},
+		{Index: 1, TokenCount: 8192, Timestamp: started.Add(2 * time.Second), ErrorMessage: "context length exceeded"},
+	}
+	for _, trial := range trials {
+		if err := logger.LogTrial("openai/gpt-4o", "production", "context", trial); err != nil {
+			t.Fatalf("LogTrial() error = %v", err)
+		}
+	}
+	if err := logger.LogResult("openai/gpt-4o", "production", "context", map[string]int{"value": 4096}); err != nil {
+		t.Fatalf("LogResult() error = %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*-openai-gpt-4o-context.jsonl"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected one .jsonl file, got %v (%v)", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected three records, got:\n%s", data)
+	}
+	var records []TrialRecord
+	for _, line := range lines {
+		var record TrialRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+
+	first := records[0]
+	if first.Model != "openai/gpt-4o" || first.Gateway != "production" || first.Phase != "context" ||
+		first.Tokens != 4096 || first.LatencyMS != 1200 || first.DurationMS != 1500 ||
+		first.Outcome != OutcomeSuccess || !first.Timestamp.Equal(started) || first.TotalTokens != 4116 {
+		t.Errorf("first record = %+v", first)
+	}
+	if records[1].Outcome != OutcomeFailure || records[1].Error != "context length exceeded" {
+		t.Errorf("second record = %+v", records[1])
+	}
+	if records[2].Outcome != OutcomeResult || string(records[2].Result) != `{"value":4096}` {
+		t.Errorf("result record = %+v", records[2])
+	}
+	if first.ExecutionID == "" || records[2].ExecutionID != first.ExecutionID {
+		t.Errorf("records should share the execution ID: %q, %q", first.ExecutionID, records[2].ExecutionID)
+	}
+}
+
+func TestNewProbeLogger_UnknownFormat(t *testing.T) {
+	if _, err := NewProbeLogger(ProbeLogConfig{Enabled: true, Dir: t.TempDir(), Format: "xml"}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if err := ValidateFormat(""); err != nil {
+		t.Errorf("ValidateFormat(\"\") error = %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+// Package manifest はアプリケーションが必要とするモデルと性能の一覧（マニフェスト）をゲートウェイのモデル一覧で検査する
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/numfmt"
+	"github.com/armaniacs/llm-info/internal/policy"
+)
+
+// 検査項目（pass/failのマトリクスの列）
+const (
+	CheckListed     = "listed"
+	CheckMode       = "mode"
+	CheckContext    = "context"
+	CheckOutput     = "output"
+	CheckInputCost  = "input_cost"
+	CheckOutputCost = "output_cost"
+)
+
+// Checks は検査項目を表示する順に並べたもの
+var Checks = []string{CheckListed, CheckMode, CheckContext, CheckOutput, CheckInputCost, CheckOutputCost}
+
+// 値の出所
+const (
+	SourceAdvertised = "advertised" // ゲートウェイのモデル一覧が公表している値
+	SourceMeasured   = "measured"   // 保存済みの探索結果
+)
+
+// Requirement はアプリケーションが必要とするモデル1件分の条件（0や空の条件は検査しない）
+type Requirement struct {
+	ID                    string  `yaml:"id"`
+	Mode                  string  `yaml:"mode"`
+	MinContext            Tokens  `yaml:"min_context"`
+	MinOutput             Tokens  `yaml:"min_output"`
+	MaxInputCostPerToken  float64 `yaml:"max_input_cost_per_token"`
+	MaxOutputCostPerToken float64 `yaml:"max_output_cost_per_token"`
+	RequireMeasured       bool    `yaml:"require_measured"` // min_context・min_outputを探索結果だけで判定する
+}
+
+// Tokens はマニフェストのトークン数。フィルタと同じく k・m・b の単位（128k など）も書ける
+type Tokens int
+
+// UnmarshalYAML はnumfmt.ParseTokensでトークン数を解析する
+func (t *Tokens) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: token count must be a number (e.g. 100000, 128k)", value.Line)
+	}
+	n, err := numfmt.ParseTokens(value.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", value.Line, err)
+	}
+	*t = Tokens(n)
+	return nil
+}
+
+// Manifest はアプリケーションのモデルマニフェスト
+type Manifest struct {
+	Gateway string        `yaml:"gateway"` // 検査するゲートウェイ名（--gatewayで上書きできる）
+	Models  []Requirement `yaml:"models"`
+}
+
+// CheckResult は検査項目1件の結果
+type CheckResult struct {
+	Check  string `json:"check"`
+	Pass   bool   `json:"pass"`
+	Value  string `json:"value,omitempty"`  // 判定に使った値（不明な場合は空）
+	Source string `json:"source,omitempty"` // advertisedまたはmeasured
+	Detail string `json:"detail,omitempty"` // 満たさなかった理由
+}
+
+// Result はモデル1件分の検査結果。Checksには条件を指定した項目とlistedだけが含まれる
+type Result struct {
+	Model  string        `json:"model"`
+	Pass   bool          `json:"pass"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Find は検査項目の結果を返す（条件を指定していない項目はfalse）
+func (r Result) Find(check string) (CheckResult, bool) {
+	for _, c := range r.Checks {
+		if c.Check == check {
+			return c, true
+		}
+	}
+	return CheckResult{}, false
+}
+
+// Load はYAMLのマニフェストを読み込んで検証する
+// 綴り間違いで条件が無視されないよう、未知のキーはエラーにする
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse はYAMLのマニフェストを解析して検証する
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate はマニフェストの内容を検証する
+func (m *Manifest) Validate() error {
+	if len(m.Models) == 0 {
+		return fmt.Errorf("manifest has no models; list them under models with an id")
+	}
+	seen := make(map[string]bool, len(m.Models))
+	for i, r := range m.Models {
+		if strings.TrimSpace(r.ID) == "" {
+			return fmt.Errorf("models[%d]: id is required", i)
+		}
+		if seen[r.ID] {
+			return fmt.Errorf("models[%d]: duplicate id %s", i, r.ID)
+		}
+		seen[r.ID] = true
+		if r.MinContext < 0 || r.MinOutput < 0 {
+			return fmt.Errorf("%s: min_context and min_output must not be negative", r.ID)
+		}
+		if r.MaxInputCostPerToken < 0 || r.MaxOutputCostPerToken < 0 {
+			return fmt.Errorf("%s: cost ceilings must not be negative", r.ID)
+		}
+		if r.RequireMeasured && r.MinContext == 0 && r.MinOutput == 0 {
+			return fmt.Errorf("%s: require_measured needs min_context or min_output", r.ID)
+		}
+	}
+	return nil
+}
+
+// Check はマニフェストの各モデルをモデル一覧で検査し、マニフェストの順に結果を返す
+// 探索結果はmodel.ApplyMeasurementsで付加しておく。探索結果がある値は公表値より優先する
+func (m *Manifest) Check(models []model.Model) []Result {
+	byName := make(map[string]model.Model, len(models))
+	for _, listed := range models {
+		byName[listed.Name] = listed
+	}
+
+	results := make([]Result, 0, len(m.Models))
+	for _, r := range m.Models {
+		listed, ok := byName[r.ID]
+		result := Result{Model: r.ID}
+		if !ok {
+			// 一覧にないモデルは他の条件を判定できないため、listedだけを報告する
+			result.Checks = []CheckResult{{Check: CheckListed, Detail: "not listed by the gateway"}}
+			results = append(results, result)
+			continue
+		}
+
+		result.Checks = append(result.Checks, CheckResult{Check: CheckListed, Pass: true})
+		if r.Mode != "" {
+			result.Checks = append(result.Checks, checkMode(r.Mode, listed))
+		}
+		if r.MinContext > 0 {
+			result.Checks = append(result.Checks, checkMinimum(CheckContext, "min_context", int(r.MinContext), r.RequireMeasured,
+				listed.MaxTokens, listed.MaxTokens > 0, measured(listed, model.MetaMeasuredContext)))
+		}
+		if r.MinOutput > 0 {
+			advertised, ok := listed.AdvertisedMaxOutput()
+			result.Checks = append(result.Checks, checkMinimum(CheckOutput, "min_output", int(r.MinOutput), r.RequireMeasured,
+				advertised, ok, measured(listed, model.MetaMeasuredMaxOutput)))
+		}
+		if r.MaxInputCostPerToken > 0 {
+			result.Checks = append(result.Checks, checkCeiling(CheckInputCost, "max_input_cost_per_token", r.MaxInputCostPerToken,
+				listed.InputCost, listed.InputCost > 0))
+		}
+		if r.MaxOutputCostPerToken > 0 {
+			cost, ok := policy.OutputCost(listed)
+			result.Checks = append(result.Checks, checkCeiling(CheckOutputCost, "max_output_cost_per_token", r.MaxOutputCostPerToken, cost, ok))
+		}
+
+		result.Pass = true
+		for _, c := range result.Checks {
+			if !c.Pass {
+				result.Pass = false
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Passed はすべてのモデルが条件を満たしたかを返す
+func Passed(results []Result) bool {
+	for _, r := range results {
+		if !r.Pass {
+			return false
+		}
+	}
+	return true
+}
+
+// checkMode はモデルのmodeが一致するかを検査する
+func checkMode(want string, m model.Model) CheckResult {
+	c := CheckResult{Check: CheckMode, Value: m.Mode, Source: SourceAdvertised}
+	switch {
+	case m.Mode == "":
+		c.Source = ""
+		c.Detail = fmt.Sprintf("mode is unknown (want %s)", want)
+	case !strings.EqualFold(m.Mode, want):
+		c.Detail = fmt.Sprintf("mode %s is not %s", m.Mode, want)
+	default:
+		c.Pass = true
+	}
+	return c
+}
+
+// checkMinimum はトークン数が下限以上かを検査する。探索結果があれば公表値より優先する
+func checkMinimum(check, field string, minimum int, requireMeasured bool, advertised int, hasAdvertised bool, measuredValue int) CheckResult {
+	c := CheckResult{Check: check}
+	value := 0
+	switch {
+	case measuredValue > 0:
+		value, c.Source = measuredValue, SourceMeasured
+	case requireMeasured:
+		c.Detail = "no probe result (require_measured); run llm-info probe to measure it"
+		return c
+	case hasAdvertised:
+		value, c.Source = advertised, SourceAdvertised
+	default:
+		c.Detail = fmt.Sprintf("%s is unknown; the gateway does not advertise it and there is no probe result", check)
+		return c
+	}
+
+	c.Value = fmt.Sprintf("%d", value)
+	if value < minimum {
+		c.Detail = fmt.Sprintf("%s %d (%s) is below %s %d", check, value, c.Source, field, minimum)
+		return c
+	}
+	c.Pass = true
+	return c
+}
+
+// checkCeiling は1トークンあたりのコストが上限以下かを検査する
+func checkCeiling(check, field string, ceiling, cost float64, hasCost bool) CheckResult {
+	c := CheckResult{Check: check}
+	if !hasCost {
+		c.Detail = fmt.Sprintf("%s is unknown; the gateway does not advertise it", check)
+		return c
+	}
+	c.Value = model.FormatMetaValue(cost)
+	c.Source = SourceAdvertised
+	if cost > ceiling {
+		c.Detail = fmt.Sprintf("%s %s exceeds %s %s", check, c.Value, field, model.FormatMetaValue(ceiling))
+		return c
+	}
+	c.Pass = true
+	return c
+}
+
+// measured はApplyMeasurementsで付加した探索結果を返す（ない場合は0）
+func measured(m model.Model, key string) int {
+	value, ok := m.Metadata[key].(float64)
+	if !ok || value <= 0 {
+		return 0
+	}
+	return int(value)
+}
@@ -0,0 +1,99 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/armaniacs/llm-info/internal/model"
+)
+
+func TestParse(t *testing.T) {
+	m, err := Parse([]byte(`
+gateway: production
+models:
+  - id: gpt-4o
+    mode: chat
+    min_context: 100000
+  - id: text-embedding-3-small
+    mode: embedding
+  - id: claude-sonnet-4
+    min_context: 128k
+    min_output: 1.5M
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if m.Gateway != "production" || len(m.Models) != 3 || m.Models[0].MinContext != 100000 ||
+		m.Models[2].MinContext != 128000 || m.Models[2].MinOutput != 1500000 {
+		t.Errorf("Parse() = %+v", m)
+	}
+
+	invalid := map[string]string{
+		"unknown key":      "models:\n  - id: gpt-4o\n    min_contxt: 1000\n",
+		"empty":            "",
+		"missing id":       "models:\n  - min_context: 1000\n",
+		"duplicate id":     "models:\n  - id: gpt-4o\n  - id: gpt-4o\n",
+		"negative":         "models:\n  - id: gpt-4o\n    min_output: -1\n",
+		"unknown unit":     "models:\n  - id: gpt-4o\n    min_context: 128kb\n",
+		"require measured": "models:\n  - id: gpt-4o\n    require_measured: true\n",
+	}
+	for name, data := range invalid {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: Parse() error = nil, want error", name)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	m := &Manifest{Models: []Requirement{
+		{ID: "gpt-4o", Mode: "chat", MinContext: 100000, MinOutput: 16000, MaxInputCostPerToken: 0.00001},
+		{ID: "gpt-4o-mini", MinContext: 100000},
+		{ID: "claude-sonnet", MinContext: 150000, RequireMeasured: true},
+		{ID: "embed", Mode: "chat", MaxOutputCostPerToken: 0.00001},
+		{ID: "missing"},
+	}}
+	models := []model.Model{
+		{Name: "gpt-4o", Mode: "chat", MaxTokens: 128000, InputCost: 0.0000025, Metadata: map[string]interface{}{"max_output_tokens": 16384.0}},
+		// 公表値は条件を満たすが、探索結果が下回る
+		{Name: "gpt-4o-mini", MaxTokens: 128000, Metadata: map[string]interface{}{model.MetaMeasuredContext: 64000.0}},
+		{Name: "claude-sonnet", MaxTokens: 200000},
+		{Name: "embed", Mode: "embedding"},
+	}
+
+	results := m.Check(models)
+	if len(results) != 5 {
+		t.Fatalf("Check() returned %d results, want 5", len(results))
+	}
+
+	if !results[0].Pass || len(results[0].Checks) != 5 {
+		t.Errorf("gpt-4o = %+v, want all 5 checks to pass", results[0])
+	}
+
+	context, _ := results[1].Find(CheckContext)
+	if results[1].Pass || context.Source != SourceMeasured || context.Value != "64000" {
+		t.Errorf("gpt-4o-mini context = %+v, want the measured value to fail", context)
+	}
+
+	context, _ = results[2].Find(CheckContext)
+	if results[2].Pass || !strings.Contains(context.Detail, "require_measured") {
+		t.Errorf("claude-sonnet context = %+v, want a missing probe result to fail", context)
+	}
+
+	mode, _ := results[3].Find(CheckMode)
+	cost, _ := results[3].Find(CheckOutputCost)
+	if results[3].Pass || mode.Pass || cost.Pass || !strings.Contains(cost.Detail, "unknown") {
+		t.Errorf("embed = %+v, want mode and unknown output cost to fail", results[3])
+	}
+
+	listed, _ := results[4].Find(CheckListed)
+	if results[4].Pass || listed.Pass || len(results[4].Checks) != 1 {
+		t.Errorf("missing = %+v, want only a failed listed check", results[4])
+	}
+
+	if Passed(results) {
+		t.Error("Passed() = true, want false")
+	}
+	if !Passed(results[:1]) {
+		t.Error("Passed() = false for passing results, want true")
+	}
+}
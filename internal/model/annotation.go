@@ -0,0 +1,93 @@
+package model
+
+import "strings"
+
+// llm-info noteで付けたメモとタグから付加するメタデータのキー
+const (
+	MetaNotes = "notes" // モデルのメモ（追加した順）
+	MetaTags  = "tags"  // モデルのタグ
+)
+
+// Annotation はllm-info noteでモデルに付けたメモとタグです
+type Annotation struct {
+	Notes []string
+	Tags  []string
+}
+
+// ApplyAnnotations はメモとタグをモデルのメタデータに付加します
+// annotationsのキーはモデルIDで、--dedupeでまとめたモデルには元のIDのメモとタグもまとめて付加します
+// 値はAPIのメタデータと同じく[]interface{}で格納し、meta.tagsのフィルタでも比較できるようにします
+func ApplyAnnotations(models []Model, annotations map[string]Annotation) {
+	if len(annotations) == 0 {
+		return
+	}
+	for i := range models {
+		ids := append([]string{models[i].Name}, models[i].Variants...)
+		var notes, tags []interface{}
+		seenTags := make(map[string]bool)
+		seenIDs := make(map[string]bool)
+		for _, id := range ids {
+			annotation, ok := annotations[id]
+			if !ok || seenIDs[id] {
+				continue
+			}
+			seenIDs[id] = true
+			for _, note := range annotation.Notes {
+				notes = append(notes, note)
+			}
+			for _, tag := range annotation.Tags {
+				if !seenTags[tag] {
+					seenTags[tag] = true
+					tags = append(tags, tag)
+				}
+			}
+		}
+		if len(notes) == 0 && len(tags) == 0 {
+			continue
+		}
+		if models[i].Metadata == nil {
+			models[i].Metadata = make(map[string]interface{})
+		}
+		if len(notes) > 0 {
+			models[i].Metadata[MetaNotes] = notes
+		}
+		if len(tags) > 0 {
+			models[i].Metadata[MetaTags] = tags
+		}
+	}
+}
+
+// Notes はApplyAnnotationsで付加したメモを返します
+func (m Model) Notes() []string {
+	return metaStrings(m.Metadata[MetaNotes])
+}
+
+// Tags はApplyAnnotationsで付加したタグを返します
+func (m Model) Tags() []string {
+	return metaStrings(m.Metadata[MetaTags])
+}
+
+// HasTag はモデルにタグが付いているかを返します（大文字と小文字は区別しない）
+func (m Model) HasTag(tag string) bool {
+	for _, t := range m.Tags() {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// metaStrings はメタデータの文字列の配列を[]stringに変換します
+func metaStrings(value interface{}) []string {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
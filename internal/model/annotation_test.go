@@ -0,0 +1,45 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyAnnotations(t *testing.T) {
+	models := []Model{
+		{Name: "gpt-4o"},
+		{Name: "claude-3-haiku", Variants: []string{"anthropic/claude-3-haiku", "claude-3-haiku"}},
+		{Name: "gpt-4o-mini"},
+	}
+	ApplyAnnotations(models, map[string]Annotation{
+		"gpt-4o":                   {Notes: []string{"approved for PII workloads"}, Tags: []string{"approved"}},
+		"claude-3-haiku":           {Tags: []string{"approved", "cheap"}},
+		"anthropic/claude-3-haiku": {Notes: []string{"routed via Bedrock"}, Tags: []string{"cheap"}},
+	})
+
+	tests := []struct {
+		index     int
+		wantNotes string
+		wantTags  string
+	}{
+		{0, "approved for PII workloads", "approved"},
+		{1, "routed via Bedrock", "approved,cheap"},
+		{2, "", ""},
+	}
+	for _, tt := range tests {
+		m := models[tt.index]
+		if got := strings.Join(m.Notes(), "|"); got != tt.wantNotes {
+			t.Errorf("%s: Notes() = %q, want %q", m.Name, got, tt.wantNotes)
+		}
+		if got := strings.Join(m.Tags(), ","); got != tt.wantTags {
+			t.Errorf("%s: Tags() = %q, want %q", m.Name, got, tt.wantTags)
+		}
+	}
+
+	if !models[1].HasTag("Cheap") {
+		t.Error("HasTag() should ignore case")
+	}
+	if models[2].Metadata != nil {
+		t.Errorf("unannotated model got metadata: %v", models[2].Metadata)
+	}
+}
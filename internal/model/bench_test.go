@@ -0,0 +1,83 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/internal/parallel"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// benchAPIModels はベンチマーク用に大規模なゲートウェイのモデル一覧を作成する
+func benchAPIModels(n int) []api.ModelInfo {
+	providers := []string{"openai", "anthropic", "google", "azure", "bedrock"}
+	models := make([]api.ModelInfo, n)
+	for i := range models {
+		provider := providers[i%len(providers)]
+		models[i] = api.ModelInfo{
+			ID:        fmt.Sprintf("%s/model-%d-2024-08-%02d", provider, i/len(providers), i%28+1),
+			MaxTokens: 4096 * (i%32 + 1),
+			Mode:      "chat",
+			InputCost: float64(i%100) / 100000,
+			Metadata: map[string]interface{}{
+				"litellm_provider": provider,
+				"supports_vision":  i%2 == 0,
+				"max_input_tokens": float64(4096 * (i%32 + 1)),
+			},
+		}
+	}
+	return models
+}
+
+func BenchmarkFromAPIResponse(b *testing.B) {
+	apiModels := benchAPIModels(5000)
+
+	for _, bc := range []struct {
+		name    string
+		workers int
+	}{
+		{"serial", 1},
+		{"parallel", 0},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			workers := benchWorkers(bc.workers)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				fromAPIResponse(apiModels, workers)
+			}
+		})
+	}
+}
+
+func BenchmarkDedupe(b *testing.B) {
+	models := FromAPIResponse(benchAPIModels(5000))
+	normalizer, err := NewNormalizer(&config.NormalizationConfig{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, bc := range []struct {
+		name    string
+		workers int
+	}{
+		{"serial", 1},
+		{"parallel", 0},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			workers := benchWorkers(bc.workers)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				normalizer.dedupe(models, workers)
+			}
+		})
+	}
+}
+
+// benchWorkers は0を利用可能なCPU数として扱う
+func benchWorkers(workers int) int {
+	if workers == 0 {
+		return parallel.Workers()
+	}
+	return workers
+}
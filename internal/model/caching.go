@@ -0,0 +1,20 @@
+package model
+
+// cachedInputCostKeys はキャッシュされた入力の1トークンあたりの料金を表すメタデータのキー（先に見つかったものを使います）
+// LiteLLMはcache_read_input_token_cost、他のゲートウェイはcached_input_costなどで返します
+var cachedInputCostKeys = []string{"cache_read_input_token_cost", "cached_input_cost_per_token", "cached_input_cost"}
+
+// CachedInputCost はプロンプトキャッシュにヒットした入力トークンの料金を返します
+// ゲートウェイが料金を返さない場合はfalseを返します
+func (m Model) CachedInputCost() (float64, bool) {
+	for _, key := range cachedInputCostKeys {
+		value, ok := m.MetaValue(key)
+		if !ok {
+			continue
+		}
+		if cost, ok := value.(float64); ok && cost >= 0 {
+			return cost, true
+		}
+	}
+	return 0, false
+}
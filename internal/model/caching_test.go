@@ -0,0 +1,26 @@
+package model
+
+import "testing"
+
+func TestModel_CachedInputCost(t *testing.T) {
+	tests := []struct {
+		name   string
+		meta   map[string]interface{}
+		want   float64
+		wantOK bool
+	}{
+		{"litellm model_info", map[string]interface{}{"model_info": map[string]interface{}{"cache_read_input_token_cost": 0.00000125}}, 0.00000125, true},
+		{"top-level key", map[string]interface{}{"cached_input_cost": 0.0000005}, 0.0000005, true},
+		{"free cache reads", map[string]interface{}{"cache_read_input_token_cost": 0.0}, 0, true},
+		{"not reported", map[string]interface{}{"input_cost_per_token": 0.0000025}, 0, false},
+		{"not a number", map[string]interface{}{"cache_read_input_token_cost": "n/a"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Model{Name: "gpt-4o", Metadata: tt.meta}.CachedInputCost()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("CachedInputCost() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ModelChange は同名モデルの属性変更を表します
+type ModelChange struct {
+	Name   string `json:"name"`
+	Before Model  `json:"before"`
+	After  Model  `json:"after"`
+}
+
+// CatalogDiff はモデル一覧の差分です
+type CatalogDiff struct {
+	Added   []Model       `json:"added"`
+	Removed []Model       `json:"removed"`
+	Changed []ModelChange `json:"changed"`
+}
+
+// Diff は2つのモデル一覧を比較して差分を返します
+func Diff(before, after []Model) CatalogDiff {
+	beforeByName := make(map[string]Model, len(before))
+	for _, m := range before {
+		beforeByName[m.Name] = m
+	}
+	afterByName := make(map[string]Model, len(after))
+	for _, m := range after {
+		afterByName[m.Name] = m
+	}
+
+	var diff CatalogDiff
+	for _, m := range after {
+		old, exists := beforeByName[m.Name]
+		if !exists {
+			diff.Added = append(diff.Added, m)
+		} else if !old.Equal(m) {
+			diff.Changed = append(diff.Changed, ModelChange{Name: m.Name, Before: old, After: m})
+		}
+	}
+	for _, m := range before {
+		if _, exists := afterByName[m.Name]; !exists {
+			diff.Removed = append(diff.Removed, m)
+		}
+	}
+
+	// 出力を安定させるため名前順に並べる
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Name < diff.Added[j].Name })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Name < diff.Removed[j].Name })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Name < diff.Changed[j].Name })
+
+	return diff
+}
+
+// IsEmpty は差分がないかを返します
+func (d CatalogDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Summary は差分を1行ずつの説明文に変換します
+func (d CatalogDiff) Summary() []string {
+	var lines []string
+	for _, m := range d.Added {
+		lines = append(lines, fmt.Sprintf("added %s (max_tokens: %d)", m.Name, m.MaxTokens))
+	}
+	for _, m := range d.Removed {
+		lines = append(lines, fmt.Sprintf("removed %s", m.Name))
+	}
+	for _, c := range d.Changed {
+		if c.Before.MaxTokens != c.After.MaxTokens {
+			lines = append(lines, fmt.Sprintf("changed %s max_tokens: %d -> %d", c.Name, c.Before.MaxTokens, c.After.MaxTokens))
+		}
+		if c.Before.Mode != c.After.Mode {
+			lines = append(lines, fmt.Sprintf("changed %s mode: %s -> %s", c.Name, c.Before.Mode, c.After.Mode))
+		}
+		if c.Before.InputCost != c.After.InputCost {
+			lines = append(lines, fmt.Sprintf("changed %s input_cost: %g -> %g", c.Name, c.Before.InputCost, c.After.InputCost))
+		}
+		if !slices.Equal(c.Before.Variants, c.After.Variants) {
+			lines = append(lines, fmt.Sprintf("changed %s variants: [%s] -> [%s]", c.Name,
+				strings.Join(c.Before.Variants, ", "), strings.Join(c.After.Variants, ", ")))
+		}
+	}
+	return lines
+}
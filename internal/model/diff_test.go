@@ -0,0 +1,62 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := []Model{
+		{Name: "gpt-4", MaxTokens: 8192, Mode: "chat"},
+		{Name: "gpt-4o", MaxTokens: 128000, Mode: "chat"},
+		{Name: "old-model", MaxTokens: 4096, Mode: "chat"},
+	}
+	after := []Model{
+		{Name: "gpt-4o", MaxTokens: 64000, Mode: "chat"},
+		{Name: "gpt-4", MaxTokens: 8192, Mode: "chat"},
+		{Name: "new-model", MaxTokens: 32000, Mode: "chat"},
+	}
+
+	diff := Diff(before, after)
+
+	if len(diff.Added) != 1 || diff.Added[0].Name != "new-model" {
+		t.Errorf("Added = %v, want [new-model]", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Name != "old-model" {
+		t.Errorf("Removed = %v, want [old-model]", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Name != "gpt-4o" {
+		t.Fatalf("Changed = %v, want [gpt-4o]", diff.Changed)
+	}
+	if diff.Changed[0].Before.MaxTokens != 128000 || diff.Changed[0].After.MaxTokens != 64000 {
+		t.Errorf("Changed max_tokens = %d -> %d", diff.Changed[0].Before.MaxTokens, diff.Changed[0].After.MaxTokens)
+	}
+	if diff.IsEmpty() {
+		t.Error("IsEmpty() should be false")
+	}
+
+	summary := diff.Summary()
+	want := []string{
+		"added new-model (max_tokens: 32000)",
+		"removed old-model",
+		"changed gpt-4o max_tokens: 128000 -> 64000",
+	}
+	if len(summary) != len(want) {
+		t.Fatalf("Summary() = %v, want %v", summary, want)
+	}
+	for i := range want {
+		if summary[i] != want[i] {
+			t.Errorf("Summary()[%d] = %q, want %q", i, summary[i], want[i])
+		}
+	}
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	models := []Model{{Name: "gpt-4o", MaxTokens: 128000}}
+	diff := Diff(models, models)
+	if !diff.IsEmpty() {
+		t.Errorf("IsEmpty() = false, diff = %+v", diff)
+	}
+	if len(diff.Summary()) != 0 {
+		t.Errorf("Summary() = %v, want empty", diff.Summary())
+	}
+}
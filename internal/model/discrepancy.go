@@ -0,0 +1,136 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// 公称値と実測値を比較する制約値
+const (
+	DiscrepancyContextWindow = "context_window"
+	DiscrepancyMaxOutput     = "max_output"
+)
+
+// largeDiscrepancyPercent は重大度を上げる差の割合（%）
+const largeDiscrepancyPercent = 5.0
+
+// Discrepancy はカタログのメタデータ（公称値）と保存済みの探索結果（実測値）の食い違いです
+type Discrepancy struct {
+	Model       string    `json:"model"`
+	Field       string    `json:"field"` // context_window または max_output
+	Claimed     int       `json:"claimed"`
+	Measured    int       `json:"measured"`
+	Diff        int       `json:"diff"`         // 実測値 - 公称値
+	DiffPercent float64   `json:"diff_percent"` // 公称値に対する差の割合（%）
+	Severity    string    `json:"severity"`
+	MeasuredAt  time.Time `json:"measured_at"`
+}
+
+// DiscrepancyThresholds は小さな差を無視するための閾値です
+// どちらかを下回る差は報告しません
+type DiscrepancyThresholds struct {
+	MinPercent float64 // 公称値に対する差の割合（%）
+	MinTokens  int     // 差のトークン数
+}
+
+// discrepancyField は比較する制約値と公称値・実測値の取り出し方です
+type discrepancyField struct {
+	name     string
+	claimed  func(Model) (int, bool)
+	measured string // ApplyMeasurementsで付加したメタデータのキー
+}
+
+// discrepancyFields は比較する制約値（公称値はverifyと同じくmax_tokensとmax_output_tokensを使う）
+var discrepancyFields = []discrepancyField{
+	{name: DiscrepancyContextWindow, measured: MetaMeasuredContext, claimed: func(m Model) (int, bool) {
+		return m.MaxTokens, m.MaxTokens > 0
+	}},
+	{name: DiscrepancyMaxOutput, measured: MetaMeasuredMaxOutput, claimed: Model.AdvertisedMaxOutput},
+}
+
+// AdvertisedMaxOutput はカタログのメタデータ（max_output_tokens、max_output）が公表している最大出力トークン数を返します
+func (m Model) AdvertisedMaxOutput() (int, bool) {
+	for _, key := range []string{"max_output_tokens", "max_output"} {
+		if value, ok := m.MetaValue(key); ok {
+			if parsed, err := strconv.Atoi(FormatMetaValue(value)); err == nil && parsed > 0 {
+				return parsed, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// FindDiscrepancies は公称値と探索結果の両方があるモデルについて、閾値以上の食い違いを返します
+// 探索結果はApplyMeasurementsで付加しておきます
+// 結果は対処すべき順（重大度、差の割合、モデル名の順）に並びます
+func FindDiscrepancies(models []Model, thresholds DiscrepancyThresholds) []Discrepancy {
+	var discrepancies []Discrepancy
+	for _, m := range models {
+		measuredAt, _ := m.MeasuredAt()
+		for _, field := range discrepancyFields {
+			claimed, ok := field.claimed(m)
+			if !ok {
+				continue
+			}
+			value, ok := m.Metadata[field.measured].(float64)
+			if !ok || value <= 0 {
+				continue
+			}
+			measured := int(value)
+
+			diff := measured - claimed
+			percent := float64(diff) / float64(claimed) * 100
+			if diff == 0 || abs(diff) < thresholds.MinTokens || math.Abs(percent) < thresholds.MinPercent {
+				continue
+			}
+			discrepancies = append(discrepancies, Discrepancy{
+				Model:       m.Name,
+				Field:       field.name,
+				Claimed:     claimed,
+				Measured:    measured,
+				Diff:        diff,
+				DiffPercent: math.Round(percent*10) / 10,
+				Severity:    discrepancySeverity(percent),
+				MeasuredAt:  measuredAt,
+			})
+		}
+	}
+
+	sort.SliceStable(discrepancies, func(i, j int) bool {
+		a, b := discrepancies[i], discrepancies[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] < severityRank[b.Severity]
+		}
+		if math.Abs(a.DiffPercent) != math.Abs(b.DiffPercent) {
+			return math.Abs(a.DiffPercent) > math.Abs(b.DiffPercent)
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.Field < b.Field
+	})
+	return discrepancies
+}
+
+// discrepancySeverity は差の割合から重大度を返します
+// 実測値が公称値より小さい場合は公称値を信じたリクエストが失敗するため、大きい場合より重く扱います
+func discrepancySeverity(percent float64) string {
+	switch {
+	case percent <= -largeDiscrepancyPercent:
+		return SeverityHigh
+	case percent < 0, percent >= largeDiscrepancyPercent:
+		return SeverityMedium
+	default:
+		return SeverityLow
+	}
+}
+
+// abs は整数の絶対値を返します
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindDiscrepancies(t *testing.T) {
+	measuredAt := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	models := []Model{
+		{Name: "gpt-4o", MaxTokens: 128000, Metadata: map[string]interface{}{"max_output_tokens": 16384.0}},
+		{Name: "claude-3-haiku", MaxTokens: 200000, Metadata: map[string]interface{}{"max_output_tokens": 4096.0}},
+		{Name: "llama-3", MaxTokens: 8192},
+		{Name: "exact", MaxTokens: 32000},
+		{Name: "unmeasured", MaxTokens: 32000},
+	}
+	ApplyMeasurements(models, map[string]Measurement{
+		"gpt-4o":         {ContextWindow: 120000, MaxOutput: 16384, MeasuredAt: measuredAt},
+		"claude-3-haiku": {ContextWindow: 199000, MaxOutput: 8192, MeasuredAt: measuredAt},
+		"llama-3":        {ContextWindow: 8200, MaxOutput: 2048, MeasuredAt: measuredAt},
+		"exact":          {ContextWindow: 32000, MeasuredAt: measuredAt},
+	})
+
+	got := FindDiscrepancies(models, DiscrepancyThresholds{})
+	want := []struct {
+		model, field, severity string
+		diff                   int
+	}{
+		{"gpt-4o", DiscrepancyContextWindow, SeverityHigh, -8000},
+		{"claude-3-haiku", DiscrepancyMaxOutput, SeverityMedium, 4096},
+		{"claude-3-haiku", DiscrepancyContextWindow, SeverityMedium, -1000},
+		{"llama-3", DiscrepancyContextWindow, SeverityLow, 8},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("FindDiscrepancies() = %+v, want %d entries", got, len(want))
+	}
+	for i, w := range want {
+		d := got[i]
+		if d.Model != w.model || d.Field != w.field || d.Severity != w.severity || d.Diff != w.diff {
+			t.Errorf("[%d] = %+v, want %+v", i, d, w)
+		}
+	}
+	if got[0].Claimed != 128000 || got[0].Measured != 120000 || got[0].DiffPercent != -6.3 {
+		t.Errorf("gpt-4o = %+v", got[0])
+	}
+	if !got[0].MeasuredAt.Equal(measuredAt) {
+		t.Errorf("MeasuredAt = %v", got[0].MeasuredAt)
+	}
+}
+
+func TestFindDiscrepancies_Thresholds(t *testing.T) {
+	models := []Model{
+		{Name: "gpt-4o", MaxTokens: 128000},
+		{Name: "llama-3", MaxTokens: 8192},
+	}
+	ApplyMeasurements(models, map[string]Measurement{
+		"gpt-4o":  {ContextWindow: 120000, MeasuredAt: time.Now()},
+		"llama-3": {ContextWindow: 8000, MeasuredAt: time.Now()},
+	})
+
+	tests := []struct {
+		name       string
+		thresholds DiscrepancyThresholds
+		want       []string
+	}{
+		{"no thresholds", DiscrepancyThresholds{}, []string{"gpt-4o", "llama-3"}},
+		{"min percent", DiscrepancyThresholds{MinPercent: 5}, []string{"gpt-4o"}},
+		{"min tokens", DiscrepancyThresholds{MinTokens: 1000}, []string{"gpt-4o"}},
+		{"both", DiscrepancyThresholds{MinPercent: 1, MinTokens: 10000}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindDiscrepancies(models, tt.thresholds)
+			if len(got) != len(tt.want) {
+				t.Fatalf("FindDiscrepancies() = %+v, want %v", got, tt.want)
+			}
+			for i, name := range tt.want {
+				if got[i].Model != name {
+					t.Errorf("[%d] = %s, want %s", i, got[i].Model, name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 重複モデルの不一致の重大度
+const (
+	SeverityHigh   = "high"   // トークン上限の不一致（ルーティング先によって大きな入力が失敗する）
+	SeverityMedium = "medium" // 価格の不一致（コスト見積もりや費用ベースのルーティングが食い違う）
+	SeverityLow    = "low"    // モードの不一致
+)
+
+// severityRank は重大度の並び順（小さいほど先に対処する）
+var severityRank = map[string]int{SeverityHigh: 0, SeverityMedium: 1, SeverityLow: 2}
+
+// GatewayCatalog は1つのゲートウェイから取得したモデル一覧です
+type GatewayCatalog struct {
+	Gateway string
+	Models  []Model
+}
+
+// FieldConflict は同じモデルIDでゲートウェイごとに値が異なる属性です
+type FieldConflict struct {
+	Field       string            `json:"field"`
+	Severity    string            `json:"severity"`
+	Values      map[string]string `json:"values"`           // ゲートウェイ名→値（値のないゲートウェイは含まない）
+	Spread      float64           `json:"spread,omitempty"` // 数値の属性の最大値/最小値
+	Remediation string            `json:"remediation"`
+}
+
+// DuplicateModel は複数のゲートウェイに存在するモデルIDです
+type DuplicateModel struct {
+	ID        string          `json:"id"`
+	Gateways  []string        `json:"gateways"`
+	Severity  string          `json:"severity,omitempty"` // 最も重い不一致の重大度（不一致がなければ空）
+	Conflicts []FieldConflict `json:"conflicts,omitempty"`
+}
+
+// HasConflicts は値の異なる属性があるかを返します
+func (d DuplicateModel) HasConflicts() bool {
+	return len(d.Conflicts) > 0
+}
+
+// duplicateField は比較する属性と値の取り出し方です
+// numericがtrueの属性は0を「値なし」として扱い、比較から除外します
+type duplicateField struct {
+	name     string
+	severity string
+	numeric  bool
+	value    func(Model) (float64, string, bool)
+}
+
+// duplicateFields は比較する属性（重大度の順）
+var duplicateFields = []duplicateField{
+	{name: "max_tokens", severity: SeverityHigh, numeric: true, value: func(m Model) (float64, string, bool) {
+		return float64(m.MaxTokens), strconv.Itoa(m.MaxTokens), m.MaxTokens > 0
+	}},
+	{name: "max_input_tokens", severity: SeverityHigh, numeric: true, value: metaNumber("max_input_tokens")},
+	{name: "max_output_tokens", severity: SeverityHigh, numeric: true, value: metaNumber("max_output_tokens")},
+	{name: "input_cost", severity: SeverityMedium, numeric: true, value: func(m Model) (float64, string, bool) {
+		return m.InputCost, strconv.FormatFloat(m.InputCost, 'g', -1, 64), m.InputCost > 0
+	}},
+	{name: "output_cost", severity: SeverityMedium, numeric: true, value: metaNumber("output_cost_per_token", "output_cost")},
+	{name: "mode", severity: SeverityLow, value: func(m Model) (float64, string, bool) {
+		return 0, m.Mode, m.Mode != ""
+	}},
+}
+
+// metaNumber はメタデータの数値を取り出す関数を返します。keysは先に見つかったものを使います
+func metaNumber(keys ...string) func(Model) (float64, string, bool) {
+	return func(m Model) (float64, string, bool) {
+		for _, key := range keys {
+			value, ok := m.MetaValue(key)
+			if !ok {
+				continue
+			}
+			if number, ok := value.(float64); ok && number > 0 {
+				return number, FormatMetaValue(number), true
+			}
+		}
+		return 0, "", false
+	}
+}
+
+// FindDuplicates は複数のゲートウェイに存在するモデルIDを探し、属性の不一致を調べます
+// normalizerを指定すると正規化後のIDでまとめ、ゲートウェイ名の後ろに元のIDを付けます
+// 結果は対処すべき順（不一致の重大度、値の開き、ゲートウェイ数、IDの順）に並びます
+func FindDuplicates(catalogs []GatewayCatalog, normalizer *Normalizer) []DuplicateModel {
+	type entry struct {
+		label string
+		model Model
+	}
+	groups := make(map[string][]entry)
+	gateways := make(map[string]map[string]bool)
+	for _, catalog := range catalogs {
+		for _, m := range catalog.Models {
+			key := m.Name
+			label := catalog.Gateway
+			if normalizer != nil {
+				key = normalizer.Normalize(m.Name)
+				if key != m.Name {
+					label = fmt.Sprintf("%s (%s)", catalog.Gateway, m.Name)
+				}
+			}
+			if gateways[key] == nil {
+				gateways[key] = make(map[string]bool)
+			}
+			gateways[key][catalog.Gateway] = true
+			groups[key] = append(groups[key], entry{label: label, model: m})
+		}
+	}
+
+	var duplicates []DuplicateModel
+	for key, group := range groups {
+		// 同じゲートウェイ内の重複は--dedupeの対象なのでここでは扱わない
+		if len(gateways[key]) < 2 {
+			continue
+		}
+		duplicate := DuplicateModel{ID: key}
+		for gateway := range gateways[key] {
+			duplicate.Gateways = append(duplicate.Gateways, gateway)
+		}
+		sort.Strings(duplicate.Gateways)
+
+		for _, field := range duplicateFields {
+			values := make(map[string]string)
+			numbers := make(map[string]float64)
+			distinct := make(map[string]bool)
+			for _, e := range group {
+				number, text, ok := field.value(e.model)
+				if !ok {
+					continue
+				}
+				values[e.label] = text
+				numbers[e.label] = number
+				distinct[text] = true
+			}
+			if len(distinct) < 2 {
+				continue
+			}
+			conflict := FieldConflict{Field: field.name, Severity: field.severity, Values: values}
+			if field.numeric {
+				conflict.Spread = spread(numbers)
+			}
+			conflict.Remediation = remediation(field, values, numbers)
+			duplicate.Conflicts = append(duplicate.Conflicts, conflict)
+		}
+		if duplicate.HasConflicts() {
+			duplicate.Severity = duplicate.Conflicts[0].Severity
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		a, b := duplicates[i], duplicates[j]
+		if a.HasConflicts() != b.HasConflicts() {
+			return a.HasConflicts()
+		}
+		if a.HasConflicts() {
+			if severityRank[a.Severity] != severityRank[b.Severity] {
+				return severityRank[a.Severity] < severityRank[b.Severity]
+			}
+			if sa, sb := maxSpread(a), maxSpread(b); sa != sb {
+				return sa > sb
+			}
+		}
+		if len(a.Gateways) != len(b.Gateways) {
+			return len(a.Gateways) > len(b.Gateways)
+		}
+		return a.ID < b.ID
+	})
+	return duplicates
+}
+
+// spread は数値の最大値/最小値を返します
+func spread(numbers map[string]float64) float64 {
+	low, high := math.Inf(1), 0.0
+	for _, number := range numbers {
+		low = math.Min(low, number)
+		high = math.Max(high, number)
+	}
+	if low <= 0 || math.IsInf(low, 1) {
+		return 0
+	}
+	return high / low
+}
+
+// maxSpread は属性の不一致のうち最も大きい値の開きを返します
+func maxSpread(d DuplicateModel) float64 {
+	var result float64
+	for _, conflict := range d.Conflicts {
+		if conflict.Severity == d.Severity {
+			result = math.Max(result, conflict.Spread)
+		}
+	}
+	return result
+}
+
+// remediation は不一致の対処方法を返します
+func remediation(field duplicateField, values map[string]string, numbers map[string]float64) string {
+	if !field.numeric {
+		return fmt.Sprintf("Use the same %s on every gateway; clients that route by %s see %s",
+			field.name, field.name, joinValues(values))
+	}
+
+	low, high := math.Inf(1), 0.0
+	for _, number := range numbers {
+		low = math.Min(low, number)
+		high = math.Max(high, number)
+	}
+	var lowest, highest []string
+	for label, number := range numbers {
+		if number == low {
+			lowest = append(lowest, label)
+		}
+		if number == high {
+			highest = append(highest, label)
+		}
+	}
+	sort.Strings(lowest)
+	sort.Strings(highest)
+	lowValue := values[lowest[0]]
+
+	if field.severity == SeverityHigh {
+		return fmt.Sprintf("Align %s on every gateway; until then requests over %s tokens fail when routed to %s",
+			field.name, lowValue, strings.Join(lowest, ", "))
+	}
+	return fmt.Sprintf("Reconcile %s; %s charges %.1fx the price of %s, so cost estimates depend on routing",
+		field.name, strings.Join(highest, ", "), high/low, strings.Join(lowest, ", "))
+}
+
+// joinValues は"gateway=value"の形式で値を名前順に連結します
+func joinValues(values map[string]string) string {
+	labels := make([]string, 0, len(values))
+	for label := range values {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	parts := make([]string, 0, len(labels))
+	for _, label := range labels {
+		parts = append(parts, label+"="+values[label])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatConflictValues は不一致の値を"gateway=value"の形式で連結します
+func FormatConflictValues(conflict FieldConflict) string {
+	s := joinValues(conflict.Values)
+	if conflict.Spread > 1 {
+		s += fmt.Sprintf(" (%.1fx)", conflict.Spread)
+	}
+	return s
+}
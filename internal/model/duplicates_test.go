@@ -0,0 +1,90 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	catalogs := []GatewayCatalog{
+		{Gateway: "prod", Models: []Model{
+			{Name: "gpt-4o", MaxTokens: 128000, InputCost: 0.0000025},
+			{Name: "claude-3-haiku", MaxTokens: 200000, InputCost: 0.00000025, Metadata: map[string]interface{}{"output_cost_per_token": 0.00000125}},
+			{Name: "same", MaxTokens: 8192, Mode: "chat"},
+			{Name: "prod-only", MaxTokens: 4096},
+		}},
+		{Gateway: "staging", Models: []Model{
+			{Name: "gpt-4o", MaxTokens: 8192, InputCost: 0.0000025},
+			{Name: "claude-3-haiku", MaxTokens: 200000, InputCost: 0.0000005, Metadata: map[string]interface{}{"output_cost_per_token": 0.00000125}},
+			{Name: "same", MaxTokens: 8192, Mode: "chat"},
+		}},
+		{Gateway: "dev", Models: []Model{
+			// 値のない属性は不一致として扱わない
+			{Name: "same", Mode: "chat"},
+		}},
+	}
+
+	duplicates := FindDuplicates(catalogs, nil)
+	if len(duplicates) != 3 {
+		t.Fatalf("got %d duplicates, want 3: %+v", len(duplicates), duplicates)
+	}
+
+	// トークン上限の不一致が最初、価格の不一致が次、不一致のないIDが最後
+	gpt := duplicates[0]
+	if gpt.ID != "gpt-4o" || gpt.Severity != SeverityHigh || len(gpt.Conflicts) != 1 {
+		t.Fatalf("first duplicate = %+v", gpt)
+	}
+	conflict := gpt.Conflicts[0]
+	if conflict.Field != "max_tokens" || conflict.Values["prod"] != "128000" || conflict.Values["staging"] != "8192" {
+		t.Errorf("gpt-4o conflict = %+v", conflict)
+	}
+	if !strings.Contains(conflict.Remediation, "over 8192 tokens fail when routed to staging") {
+		t.Errorf("remediation = %q", conflict.Remediation)
+	}
+	if got := FormatConflictValues(conflict); got != "prod=128000, staging=8192 (15.6x)" {
+		t.Errorf("FormatConflictValues() = %q", got)
+	}
+
+	claude := duplicates[1]
+	if claude.ID != "claude-3-haiku" || claude.Severity != SeverityMedium || len(claude.Conflicts) != 1 {
+		t.Fatalf("second duplicate = %+v", claude)
+	}
+	if claude.Conflicts[0].Field != "input_cost" || claude.Conflicts[0].Spread != 2 {
+		t.Errorf("claude-3-haiku conflict = %+v", claude.Conflicts[0])
+	}
+	if !strings.Contains(claude.Conflicts[0].Remediation, "staging charges 2.0x the price of prod") {
+		t.Errorf("remediation = %q", claude.Conflicts[0].Remediation)
+	}
+
+	same := duplicates[2]
+	if same.ID != "same" || same.HasConflicts() || strings.Join(same.Gateways, ",") != "dev,prod,staging" {
+		t.Errorf("third duplicate = %+v", same)
+	}
+}
+
+func TestFindDuplicatesNormalized(t *testing.T) {
+	catalogs := []GatewayCatalog{
+		{Gateway: "prod", Models: []Model{{Name: "openai/gpt-4o", Mode: "chat"}}},
+		{Gateway: "staging", Models: []Model{{Name: "gpt-4o-2024-08-06", Mode: "completion"}}},
+	}
+
+	if duplicates := FindDuplicates(catalogs, nil); len(duplicates) != 0 {
+		t.Fatalf("without normalization got %+v", duplicates)
+	}
+
+	normalizer, err := NewNormalizer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	duplicates := FindDuplicates(catalogs, normalizer)
+	if len(duplicates) != 1 || duplicates[0].ID != "gpt-4o" {
+		t.Fatalf("got %+v", duplicates)
+	}
+	conflict := duplicates[0].Conflicts[0]
+	if conflict.Field != "mode" || conflict.Severity != SeverityLow {
+		t.Fatalf("conflict = %+v", conflict)
+	}
+	if conflict.Values["prod (openai/gpt-4o)"] != "chat" || conflict.Values["staging (gpt-4o-2024-08-06)"] != "completion" {
+		t.Errorf("values = %v", conflict.Values)
+	}
+}
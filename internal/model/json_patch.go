@@ -0,0 +1,144 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// PatchOperation はJSON Patch（RFC 6902）の操作1件です
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	From  string          `json:"from,omitempty"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSONPatch はモデル一覧をbeforeからafterに変えるJSON Patch（RFC 6902）を返します
+// 対象の文書はpost_fetchフックに渡すのと同じModelの配列で、パスはGoのフィールド名（/0/Name、/0/MaxTokensなど）を使います
+// 同名のモデルを同じモデルとして扱い、削除、afterの順に並べながらの移動・属性の変更・追加の順に操作を並べます
+// 削除・移動・変更の前にはtestでモデル名を確かめるため、想定と異なる一覧に適用すると途中で失敗します
+func JSONPatch(before, after []Model) ([]PatchOperation, error) {
+	afterNames := make(map[string]bool, len(after))
+	for _, m := range after {
+		afterNames[m.Name] = true
+	}
+	beforeByName := make(map[string]Model, len(before))
+	for _, m := range before {
+		beforeByName[m.Name] = m
+	}
+
+	ops := []PatchOperation{}
+	doc := make([]string, 0, len(before)) // 適用途中の一覧のモデル名
+	for _, m := range before {
+		doc = append(doc, m.Name)
+	}
+
+	// 削除（後ろから削除して、残りのインデックスをずらさない）
+	for i := len(before) - 1; i >= 0; i-- {
+		if afterNames[before[i].Name] {
+			continue
+		}
+		ops = append(ops, testName(i, before[i].Name), PatchOperation{Op: "remove", Path: indexPath(i)})
+		doc = slices.Delete(doc, i, i+1)
+	}
+
+	// 先頭から順にafterのモデルを置いていく
+	for i, m := range after {
+		old, exists := beforeByName[m.Name]
+		j := -1
+		if exists {
+			if k := slices.Index(doc[i:], m.Name); k >= 0 {
+				j = i + k
+			}
+		}
+		if j < 0 {
+			value, err := json.Marshal(m)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode model %s: %w", m.Name, err)
+			}
+			ops = append(ops, PatchOperation{Op: "add", Path: indexPath(i), Value: value})
+			doc = slices.Insert(doc, i, m.Name)
+			continue
+		}
+
+		if j != i {
+			ops = append(ops, testName(j, m.Name), PatchOperation{Op: "move", From: indexPath(j), Path: indexPath(i)})
+			doc = slices.Insert(slices.Delete(doc, j, j+1), i, m.Name)
+		}
+		if !old.Equal(m) {
+			fieldOps, err := modelFieldOps(i, old, m)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, testName(i, m.Name))
+			ops = append(ops, fieldOps...)
+		}
+	}
+	return ops, nil
+}
+
+// modelFieldOps は一覧のi番目のモデルの属性をbeforeからafterに変える操作を返します
+// メタデータなど入れ子の値は、トップレベルの属性ごとに置き換えます
+func modelFieldOps(i int, before, after Model) ([]PatchOperation, error) {
+	beforeFields, err := modelFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := modelFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(beforeFields)+len(afterFields))
+	for key := range beforeFields {
+		keys = append(keys, key)
+	}
+	for key := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var ops []PatchOperation
+	for _, key := range keys {
+		path := fmt.Sprintf("%s/%s", indexPath(i), key)
+		oldValue, hadValue := beforeFields[key]
+		newValue, hasValue := afterFields[key]
+		switch {
+		case !hasValue:
+			ops = append(ops, PatchOperation{Op: "remove", Path: path})
+		case !hadValue:
+			ops = append(ops, PatchOperation{Op: "add", Path: path, Value: newValue})
+		case string(oldValue) != string(newValue):
+			ops = append(ops, PatchOperation{Op: "replace", Path: path, Value: newValue})
+		}
+	}
+	return ops, nil
+}
+
+// modelFields はモデルをJSONの属性ごとに分けます（mapのキーは並べ替えて出力されるため、値をそのまま比較できます）
+func modelFields(m Model) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode model %s: %w", m.Name, err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode model %s: %w", m.Name, err)
+	}
+	return fields, nil
+}
+
+// testName は一覧のi番目がnameのモデルであることを確かめる操作を返します
+func testName(i int, name string) PatchOperation {
+	value, _ := json.Marshal(name)
+	return PatchOperation{Op: "test", Path: indexPath(i) + "/Name", Value: value}
+}
+
+// indexPath は一覧のi番目を指すJSON Pointerを返します
+func indexPath(i int) string {
+	return fmt.Sprintf("/%d", i)
+}
@@ -0,0 +1,175 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestJSONPatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		before []Model
+		after  []Model
+	}{
+		{
+			name:  "initial catalog",
+			after: []Model{{Name: "gpt-4", MaxTokens: 8192, Mode: "chat"}, {Name: "gpt-4o", MaxTokens: 128000, Mode: "chat"}},
+		},
+		{
+			name: "added, removed and changed",
+			before: []Model{
+				{Name: "gpt-4", MaxTokens: 8192, Mode: "chat"},
+				{Name: "gpt-4o", MaxTokens: 128000, Mode: "chat", InputCost: 0.0000025},
+				{Name: "old-model", MaxTokens: 4096, Mode: "chat"},
+			},
+			after: []Model{
+				{Name: "gpt-4", MaxTokens: 8192, Mode: "chat"},
+				{Name: "gpt-4o", MaxTokens: 64000, Mode: "chat", Metadata: map[string]interface{}{"owned_by": "openai"}},
+				{Name: "new-model", MaxTokens: 32000, Mode: "embedding"},
+			},
+		},
+		{
+			name: "reordered by a changed sort key",
+			before: []Model{
+				{Name: "a", MaxTokens: 1000},
+				{Name: "b", MaxTokens: 2000},
+				{Name: "c", MaxTokens: 3000},
+			},
+			after: []Model{
+				{Name: "c", MaxTokens: 3000},
+				{Name: "d", MaxTokens: 2500},
+				{Name: "a", MaxTokens: 1000},
+			},
+		},
+		{
+			name:   "all removed",
+			before: []Model{{Name: "a"}, {Name: "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, err := JSONPatch(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("JSONPatch() error = %v", err)
+			}
+
+			doc := decodeCatalog(t, tt.before)
+			for _, op := range ops {
+				if doc, err = applyPatchOperation(doc, op); err != nil {
+					t.Fatalf("failed to apply %+v: %v", op, err)
+				}
+			}
+			if want := decodeCatalog(t, tt.after); !reflect.DeepEqual(doc, want) {
+				t.Errorf("patched catalog = %v, want %v", doc, want)
+			}
+		})
+	}
+}
+
+func TestJSONPatch_Operations(t *testing.T) {
+	before := []Model{{Name: "gpt-4o", MaxTokens: 128000, Mode: "chat"}, {Name: "old-model", MaxTokens: 4096}}
+	after := []Model{{Name: "gpt-4o", MaxTokens: 64000, Mode: "chat"}}
+
+	ops, err := JSONPatch(before, after)
+	if err != nil {
+		t.Fatalf("JSONPatch() error = %v", err)
+	}
+	data, _ := json.Marshal(ops)
+	want := `[{"op":"test","path":"/1/Name","value":"old-model"},{"op":"remove","path":"/1"},` +
+		`{"op":"test","path":"/0/Name","value":"gpt-4o"},{"op":"replace","path":"/0/MaxTokens","value":64000}]`
+	if string(data) != want {
+		t.Errorf("JSONPatch() = %s, want %s", data, want)
+	}
+
+	if ops, _ := JSONPatch(after, after); len(ops) != 0 {
+		t.Errorf("JSONPatch() without changes = %+v, want no operations", ops)
+	}
+}
+
+// decodeCatalog はモデル一覧をpost_fetchフックに渡すのと同じJSONの値に変換する
+func decodeCatalog(t *testing.T, models []Model) []interface{} {
+	t.Helper()
+	if models == nil {
+		models = []Model{}
+	}
+	data, err := json.Marshal(models)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc []interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// applyPatchOperation はテスト用にJSON Patchの操作を一覧に適用する（/インデックス と /インデックス/属性 のみ）
+func applyPatchOperation(doc []interface{}, op PatchOperation) ([]interface{}, error) {
+	var value interface{}
+	if op.Value != nil {
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, err
+		}
+	}
+	index, field, err := splitPatchPath(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if field != "" {
+		if index >= len(doc) {
+			return nil, fmt.Errorf("index out of range: %d", index)
+		}
+		item := doc[index].(map[string]interface{})
+		switch op.Op {
+		case "test":
+			if !reflect.DeepEqual(item[field], value) {
+				return nil, fmt.Errorf("test failed: %v != %v", item[field], value)
+			}
+		case "add", "replace":
+			item[field] = value
+		case "remove":
+			delete(item, field)
+		default:
+			return nil, fmt.Errorf("unsupported op on a field: %s", op.Op)
+		}
+		return doc, nil
+	}
+
+	switch op.Op {
+	case "add":
+		if index > len(doc) {
+			return nil, fmt.Errorf("index out of range: %d", index)
+		}
+		return append(doc[:index], append([]interface{}{value}, doc[index:]...)...), nil
+	case "remove":
+		return append(doc[:index], doc[index+1:]...), nil
+	case "move":
+		from, _, err := splitPatchPath(op.From)
+		if err != nil {
+			return nil, err
+		}
+		item := doc[from]
+		doc = append(doc[:from], doc[from+1:]...)
+		return append(doc[:index], append([]interface{}{item}, doc[index:]...)...), nil
+	}
+	return nil, fmt.Errorf("unsupported op: %s", op.Op)
+}
+
+// splitPatchPath は /インデックス/属性 のJSON Pointerを分解する
+func splitPatchPath(path string) (int, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	index, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid path: %s", path)
+	}
+	if len(parts) == 2 {
+		return index, parts[1], nil
+	}
+	return index, "", nil
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+// LiteLLM固有のエンドポイントから付加するメタデータのキー
+const (
+	MetaDeploymentHealth = "deployment_health" // /healthから集計したデプロイメントの状態
+	MetaGroupProviders   = "group_providers"   // /model_group/infoのプロバイダー一覧
+)
+
+// deploymentCount はモデルごとの正常・異常なデプロイメント数
+type deploymentCount struct {
+	healthy, unhealthy int
+}
+
+// ApplyLiteLLMStatus はLiteLLMの/healthと/model_group/infoの結果をモデルのメタデータに付加します
+// どちらもnilの場合は何もしません。該当するデプロイメントやグループがないモデルには付加しません
+func ApplyLiteLLMStatus(models []Model, health *api.HealthResponse, groups *api.ModelGroupInfoResponse) {
+	counts := make(map[string]*deploymentCount)
+	if health != nil {
+		for _, endpoint := range health.HealthyEndpoints {
+			countDeployment(counts, models, endpoint, true)
+		}
+		for _, endpoint := range health.UnhealthyEndpoints {
+			countDeployment(counts, models, endpoint, false)
+		}
+	}
+
+	providers := make(map[string][]string)
+	if groups != nil {
+		for _, group := range groups.Data {
+			providers[group.ModelGroup] = group.Providers
+		}
+	}
+
+	for i := range models {
+		count, hasHealth := counts[models[i].Name]
+		groupProviders, hasGroup := providers[models[i].Name]
+		if !hasHealth && !hasGroup {
+			continue
+		}
+		if models[i].Metadata == nil {
+			models[i].Metadata = make(map[string]interface{})
+		}
+		if hasHealth {
+			models[i].Metadata[MetaDeploymentHealth] = count.String()
+		}
+		if hasGroup {
+			values := make([]interface{}, len(groupProviders))
+			for j, p := range groupProviders {
+				values[j] = p
+			}
+			models[i].Metadata[MetaGroupProviders] = values
+		}
+	}
+}
+
+// String は「healthy (2/2)」「degraded (1/2)」「unhealthy (0/2)」の形式で返します
+func (c *deploymentCount) String() string {
+	total := c.healthy + c.unhealthy
+	switch {
+	case c.unhealthy == 0:
+		return fmt.Sprintf("healthy (%d/%d)", c.healthy, total)
+	case c.healthy == 0:
+		return fmt.Sprintf("unhealthy (0/%d)", total)
+	default:
+		return fmt.Sprintf("degraded (%d/%d)", c.healthy, total)
+	}
+}
+
+// countDeployment は/healthのエンドポイントが属するモデルを探して集計します
+func countDeployment(counts map[string]*deploymentCount, models []Model, endpoint map[string]interface{}, healthy bool) {
+	name := deploymentModel(models, endpoint)
+	if name == "" {
+		return
+	}
+	count, ok := counts[name]
+	if !ok {
+		count = &deploymentCount{}
+		counts[name] = count
+	}
+	if healthy {
+		count.healthy++
+	} else {
+		count.unhealthy++
+	}
+}
+
+// deploymentModel は/healthのエンドポイントに対応するモデル名を返します
+// model_nameがあればそれを使い、なければlitellm_params.model（"openai/gpt-4o"など）から
+// プロバイダーの接頭辞を除いた名前で照合します
+func deploymentModel(models []Model, endpoint map[string]interface{}) string {
+	if name, ok := endpoint["model_name"].(string); ok && name != "" {
+		return name
+	}
+	deployment, _ := endpoint["model"].(string)
+	if deployment == "" {
+		return ""
+	}
+	for _, m := range models {
+		if m.Name == deployment {
+			return m.Name
+		}
+	}
+	short := deployment[strings.LastIndex(deployment, "/")+1:]
+	for _, m := range models {
+		if m.Name == short {
+			return m.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+func TestApplyLiteLLMStatus(t *testing.T) {
+	models := []Model{{Name: "gpt-4o"}, {Name: "claude-3-5-sonnet"}, {Name: "mistral-large"}, {Name: "embedding"}}
+	health := &api.HealthResponse{
+		HealthyEndpoints: []map[string]interface{}{
+			{"model": "openai/gpt-4o"},
+			{"model": "anthropic/claude-3-5-sonnet"},
+		},
+		UnhealthyEndpoints: []map[string]interface{}{
+			{"model": "azure/gpt-4o", "error": "timeout"},
+			{"model_name": "mistral-large", "model": "mistral/mistral-large-latest"},
+			{"model": "unknown/other"},
+		},
+	}
+	groups := &api.ModelGroupInfoResponse{Data: []api.ModelGroupInfo{
+		{ModelGroup: "gpt-4o", Providers: []string{"openai", "azure"}},
+	}}
+
+	ApplyLiteLLMStatus(models, health, groups)
+
+	want := map[string]string{
+		"gpt-4o":            "degraded (1/2)",
+		"claude-3-5-sonnet": "healthy (1/1)",
+		"mistral-large":     "unhealthy (0/1)",
+	}
+	for _, m := range models {
+		value, ok := m.MetaValue(MetaDeploymentHealth)
+		if want[m.Name] == "" {
+			if ok {
+				t.Errorf("%s: unexpected health %v", m.Name, value)
+			}
+			continue
+		}
+		if value != want[m.Name] {
+			t.Errorf("%s: health = %v, want %s", m.Name, value, want[m.Name])
+		}
+	}
+
+	providers, ok := models[0].MetaValue(MetaGroupProviders)
+	if !ok || FormatMetaValue(providers) != "openai, azure" {
+		t.Errorf("gpt-4o providers = %v", providers)
+	}
+	if models[3].Metadata != nil {
+		t.Errorf("embedding metadata = %v, want nil", models[3].Metadata)
+	}
+}
+
+func TestApplyLiteLLMStatus_Nil(t *testing.T) {
+	models := []Model{{Name: "gpt-4o"}}
+	ApplyLiteLLMStatus(models, nil, nil)
+	if models[0].Metadata != nil {
+		t.Errorf("Metadata = %v, want nil", models[0].Metadata)
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// 保存済みの探索結果から付加するメタデータのキー
+const (
+	MetaMeasuredContext   = "measured_context"    // 探索したコンテキストウィンドウ
+	MetaMeasuredMaxOutput = "measured_max_output" // 探索した最大出力トークン数
+	MetaMeasuredAt        = "measured_at"         // 最新の探索結果の保存時刻（RFC3339）
+	MetaLatencyP50        = "latency_p50"         // 探索の試行のレイテンシの中央値（ミリ秒）
+)
+
+// Measurement はモデルについて保存済みの探索結果です
+// 値が0のものは探索していないか、探索に失敗したことを表します
+type Measurement struct {
+	ContextWindow int
+	MaxOutput     int
+	MeasuredAt    time.Time
+	LatencyP50    time.Duration // 最新の探索結果に記録された試行のレイテンシの中央値
+}
+
+// ApplyMeasurements は保存済みの探索結果をモデルのメタデータに付加します
+// measurementsのキーはモデル名で、探索結果がないモデルには付加しません
+// 数値はAPIのメタデータと同じくfloat64で格納し、meta.<key>のフィルタでも比較できるようにします
+func ApplyMeasurements(models []Model, measurements map[string]Measurement) {
+	for i := range models {
+		measurement, ok := measurements[models[i].Name]
+		if !ok || measurement.MeasuredAt.IsZero() {
+			continue
+		}
+		if models[i].Metadata == nil {
+			models[i].Metadata = make(map[string]interface{})
+		}
+		if measurement.ContextWindow > 0 {
+			models[i].Metadata[MetaMeasuredContext] = float64(measurement.ContextWindow)
+		}
+		if measurement.MaxOutput > 0 {
+			models[i].Metadata[MetaMeasuredMaxOutput] = float64(measurement.MaxOutput)
+		}
+		if measurement.LatencyP50 > 0 {
+			models[i].Metadata[MetaLatencyP50] = float64(measurement.LatencyP50.Milliseconds())
+		}
+		models[i].Metadata[MetaMeasuredAt] = measurement.MeasuredAt.UTC().Format(time.RFC3339)
+	}
+}
+
+// LatencyP50 はApplyMeasurementsで付加した試行のレイテンシの中央値を返します
+func (m Model) LatencyP50() (time.Duration, bool) {
+	value, ok := m.Metadata[MetaLatencyP50].(float64)
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(value) * time.Millisecond, true
+}
+
+// MeasuredAt はApplyMeasurementsで付加した探索結果の保存時刻を返します
+func (m Model) MeasuredAt() (time.Time, bool) {
+	value, ok := m.Metadata[MetaMeasuredAt].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyMeasurements(t *testing.T) {
+	measuredAt := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	models := []Model{
+		{Name: "gpt-4o", Metadata: map[string]interface{}{"litellm_provider": "openai"}},
+		{Name: "claude-3-haiku"},
+		{Name: "unmeasured"},
+	}
+	ApplyMeasurements(models, map[string]Measurement{
+		"gpt-4o":         {ContextWindow: 128000, MaxOutput: 16384, MeasuredAt: measuredAt},
+		"claude-3-haiku": {MaxOutput: 4096, MeasuredAt: measuredAt},
+	})
+
+	if value, _ := models[0].MetaValue(MetaMeasuredContext); value != 128000.0 {
+		t.Errorf("gpt-4o measured_context = %v", value)
+	}
+	if value, _ := models[0].MetaValue(MetaMeasuredMaxOutput); value != 16384.0 {
+		t.Errorf("gpt-4o measured_max_output = %v", value)
+	}
+	if models[0].Metadata["litellm_provider"] != "openai" {
+		t.Errorf("existing metadata was lost: %v", models[0].Metadata)
+	}
+	if got, ok := models[0].MeasuredAt(); !ok || !got.Equal(measuredAt) {
+		t.Errorf("MeasuredAt() = %v, %v", got, ok)
+	}
+
+	// 探索に失敗した値は付加しない
+	if _, ok := models[1].MetaValue(MetaMeasuredContext); ok {
+		t.Errorf("claude-3-haiku should have no measured_context")
+	}
+	if models[2].Metadata != nil {
+		t.Errorf("unmeasured metadata = %v, want nil", models[2].Metadata)
+	}
+	if _, ok := models[2].MeasuredAt(); ok {
+		t.Errorf("unmeasured MeasuredAt() should be false")
+	}
+}
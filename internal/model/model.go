@@ -1,10 +1,16 @@
 package model
 
 import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/internal/parallel"
 )
 
 // Model はアプリケーション内のモデルデータです
@@ -13,26 +19,137 @@ type Model struct {
 	MaxTokens int
 	Mode      string
 	InputCost float64
+	Variants  []string               `json:",omitempty"` // --dedupeでまとめられた元のモデルID
+	Metadata  map[string]interface{} `json:",omitempty"` // APIが返した生のメタデータ
+	Issues    []api.FieldIssue       `json:",omitempty"` // レスポンスで欠落・不正だったフィールド
+}
+
+// Equal は2つのモデルの属性がすべて等しいかを返します
+func (m Model) Equal(other Model) bool {
+	return m.Name == other.Name &&
+		m.MaxTokens == other.MaxTokens &&
+		m.Mode == other.Mode &&
+		m.InputCost == other.InputCost &&
+		slices.Equal(m.Variants, other.Variants) &&
+		reflect.DeepEqual(m.Metadata, other.Metadata)
+}
+
+// MetaValue はメタデータからキーに対応する値を返します
+// "model_info.max_input_tokens" のようにドット区切りでネストした値を参照でき、
+// トップレベルにないキーはLiteLLMのmodel_info内も探します
+func (m Model) MetaValue(key string) (interface{}, bool) {
+	if value, ok := lookupMeta(m.Metadata, key); ok {
+		return value, true
+	}
+	if info, ok := m.Metadata["model_info"].(map[string]interface{}); ok {
+		return lookupMeta(info, key)
+	}
+	return nil, false
+}
+
+// lookupMeta はドット区切りのキーでネストしたマップをたどります
+func lookupMeta(meta map[string]interface{}, key string) (interface{}, bool) {
+	if meta == nil {
+		return nil, false
+	}
+	if value, ok := meta[key]; ok {
+		return value, true
+	}
+
+	head, rest, ok := strings.Cut(key, ".")
+	if !ok {
+		return nil, false
+	}
+	nested, ok := meta[head].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookupMeta(nested, rest)
+}
+
+// FormatMetaValue はメタデータの値を表示・比較用の文字列に変換します
+func FormatMetaValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = FormatMetaValue(item)
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
 }
 
 // FromAPIResponse はAPIレスポンスをアプリケーションモデルに変換します
+// モデル数が多い場合は複数のゴルーチンで変換し、並び順は入力と同じです
 func FromAPIResponse(apiModels []api.ModelInfo) []Model {
+	return fromAPIResponse(apiModels, parallel.Workers())
+}
+
+// fromAPIResponse はゴルーチン数を指定してFromAPIResponseを実行します
+func fromAPIResponse(apiModels []api.ModelInfo, workers int) []Model {
 	if apiModels == nil {
 		return nil
 	}
 
 	models := make([]Model, len(apiModels))
-	for i, apiModel := range apiModels {
-		models[i] = Model{
-			Name:      apiModel.ID,
-			MaxTokens: apiModel.MaxTokens,
-			Mode:      apiModel.Mode,
-			InputCost: apiModel.InputCost,
+	parallel.ForWorkers(len(apiModels), workers, func(start, end int) {
+		for i := start; i < end; i++ {
+			apiModel := apiModels[i]
+			models[i] = Model{
+				Name:      apiModel.ID,
+				MaxTokens: apiModel.MaxTokens,
+				Mode:      apiModel.Mode,
+				InputCost: apiModel.InputCost,
+				Metadata:  maps.Clone(apiModel.Metadata),
+				Issues:    apiModel.Issues,
+			}
 		}
-	}
+	})
 	return models
 }
 
+// CountIssues は欠落・不正なフィールドがあったモデルの数を返します
+func CountIssues(models []Model) int {
+	n := 0
+	for _, m := range models {
+		if len(m.Issues) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// IsIncomplete はモデルにmax_tokensか入力コストがないかを返します
+// OpenAI互換の/v1/modelsへのフォールバックでは両方とも返らず、0として扱われます
+// ワイルドカードのエントリはモデルではないため対象外です
+func (m Model) IsIncomplete() bool {
+	if IsWildcard(m.Name) {
+		return false
+	}
+	return m.MaxTokens <= 0 || m.InputCost <= 0
+}
+
+// CountIncomplete はmax_tokensか入力コストがないモデルの数を返します
+func CountIncomplete(models []Model) int {
+	n := 0
+	for _, m := range models {
+		if m.IsIncomplete() {
+			n++
+		}
+	}
+	return n
+}
+
 // FilterByName はモデル名でフィルタリングします
 func FilterByName(models []Model, filter string) []Model {
 	if filter == "" {
@@ -147,3 +147,60 @@ func TestFromAPIResponseNilInput(t *testing.T) {
 		t.Errorf("FromAPIResponse() with nil input should return nil, got %v", got)
 	}
 }
+
+func TestModel_MetaValue(t *testing.T) {
+	m := Model{
+		Name: "claude-3-haiku",
+		Metadata: map[string]interface{}{
+			"mode": "chat",
+			"model_info": map[string]interface{}{
+				"supports_vision":  true,
+				"max_input_tokens": 200000.0,
+			},
+		},
+	}
+
+	tests := []struct {
+		key    string
+		want   string
+		wantOk bool
+	}{
+		{"mode", "chat", true},
+		{"model_info.supports_vision", "true", true},
+		{"supports_vision", "true", true}, // model_info内も探す
+		{"max_input_tokens", "200000", true},
+		{"unknown", "", false},
+		{"model_info.unknown", "", false},
+	}
+
+	for _, tt := range tests {
+		value, ok := m.MetaValue(tt.key)
+		if ok != tt.wantOk {
+			t.Errorf("MetaValue(%q) ok = %v, want %v", tt.key, ok, tt.wantOk)
+			continue
+		}
+		if got := FormatMetaValue(value); got != tt.want {
+			t.Errorf("MetaValue(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestCountIncomplete(t *testing.T) {
+	models := []Model{
+		{Name: "gpt-4o", MaxTokens: 128000, InputCost: 0.0000025},
+		{Name: "no-cost", MaxTokens: 8192},
+		{Name: "no-tokens", InputCost: 0.000001},
+		{Name: "bare"},
+		{Name: "openai/*"}, // ワイルドカードは対象外
+	}
+
+	if got := CountIncomplete(models); got != 3 {
+		t.Errorf("CountIncomplete() = %d, want 3", got)
+	}
+	if models[0].IsIncomplete() {
+		t.Error("model with max_tokens and cost should not be incomplete")
+	}
+	if models[4].IsIncomplete() {
+		t.Error("wildcard entry should not be incomplete")
+	}
+}
@@ -0,0 +1,177 @@
+package model
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/armaniacs/llm-info/internal/parallel"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// DefaultNormalizationRules は組み込みの正規化ルール
+var DefaultNormalizationRules = []config.NormalizationRule{
+	{Pattern: `^[^/]+/`, Replace: ""},             // プロバイダー接頭辞（openai/gpt-4o）
+	{Pattern: `-\d{4}-\d{2}-\d{2}$`, Replace: ""}, // 日付サフィックス（gpt-4o-2024-08-06）
+	{Pattern: `-\d{8}$`, Replace: ""},             // 日付サフィックス（claude-3-5-sonnet-20241022）
+	{Pattern: `-latest$`, Replace: ""},            // エイリアス（claude-3-5-sonnet-latest）
+}
+
+// normalizationRule はコンパイル済みの置換ルール
+type normalizationRule struct {
+	pattern *regexp.Regexp
+	replace string
+}
+
+// Normalizer はモデルIDを正規化する
+type Normalizer struct {
+	rules   []normalizationRule
+	aliases map[string]string
+}
+
+// NewNormalizer は設定から新しいNormalizerを作成する
+// cfgがnilの場合は組み込みルールのみを使用する
+func NewNormalizer(cfg *config.NormalizationConfig) (*Normalizer, error) {
+	if cfg == nil {
+		cfg = &config.NormalizationConfig{}
+	}
+
+	var specs []config.NormalizationRule
+	if !cfg.DisableDefaults {
+		specs = append(specs, DefaultNormalizationRules...)
+	}
+	specs = append(specs, cfg.Rules...)
+
+	n := &Normalizer{aliases: make(map[string]string)}
+	for _, spec := range specs {
+		pattern, err := regexp.Compile(spec.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid normalization pattern %q: %w", spec.Pattern, err)
+		}
+		n.rules = append(n.rules, normalizationRule{pattern: pattern, replace: spec.Replace})
+	}
+	for from, to := range cfg.Aliases {
+		n.aliases[strings.ToLower(from)] = strings.ToLower(to)
+	}
+
+	return n, nil
+}
+
+// Normalize はモデルIDを正規化する
+// エイリアスは元のIDと置換後のIDの両方で照合する
+func (n *Normalizer) Normalize(id string) string {
+	normalized := strings.ToLower(strings.TrimSpace(id))
+	if alias, ok := n.aliases[normalized]; ok {
+		return alias
+	}
+
+	for _, rule := range n.rules {
+		normalized = rule.pattern.ReplaceAllString(normalized, rule.replace)
+	}
+
+	if alias, ok := n.aliases[normalized]; ok {
+		return alias
+	}
+	return normalized
+}
+
+// Dedupe は正規化後に同一となるモデルを1つにまとめる
+// まとめたモデルの名前は正規化後のIDとなり、元のIDはVariantsに残る
+// 並び順は各グループの最初の出現位置を保つ
+func (n *Normalizer) Dedupe(models []Model) []Model {
+	return n.dedupe(models, parallel.Workers())
+}
+
+// dedupe はゴルーチン数を指定してDedupeを実行する
+// 正規表現による正規化は並列に行い、グループ化は入力順に行う
+func (n *Normalizer) dedupe(models []Model, workers int) []Model {
+	keys := make([]string, len(models))
+	parallel.ForWorkers(len(models), workers, func(start, end int) {
+		for i := start; i < end; i++ {
+			if IsWildcard(models[i].Name) {
+				// "openai/*"と"anthropic/*"が同じ"*"にまとまらないよう、ワイルドカードは正規化しない
+				keys[i] = "\x00" + models[i].Name
+				continue
+			}
+			keys[i] = n.Normalize(models[i].Name)
+		}
+	})
+
+	groups := make(map[string][]Model)
+	var order []string
+	for i, m := range models {
+		key := keys[i]
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], m)
+	}
+
+	result := make([]Model, 0, len(order))
+	for _, key := range order {
+		group := groups[key]
+		if len(group) == 1 {
+			result = append(result, group[0])
+			continue
+		}
+		result = append(result, mergeVariants(strings.TrimPrefix(key, "\x00"), group))
+	}
+	return result
+}
+
+// mergeVariants はグループ内のモデルを1つにまとめる
+// 正規化後のIDと一致するモデルがあればその値を優先し、欠けている値は他のモデルで補う
+func mergeVariants(key string, group []Model) Model {
+	primary := group[0]
+	for _, m := range group {
+		if strings.EqualFold(m.Name, key) {
+			primary = m
+			break
+		}
+	}
+
+	merged := Model{
+		Name:      key,
+		MaxTokens: primary.MaxTokens,
+		Mode:      primary.Mode,
+		InputCost: primary.InputCost,
+		Metadata:  maps.Clone(primary.Metadata),
+	}
+
+	seen := make(map[string]bool)
+	for _, m := range group {
+		if merged.MaxTokens == 0 {
+			merged.MaxTokens = m.MaxTokens
+		}
+		if merged.Mode == "" {
+			merged.Mode = m.Mode
+		}
+		if merged.InputCost == 0 {
+			merged.InputCost = m.InputCost
+		}
+		for k, v := range m.Metadata {
+			if _, ok := merged.Metadata[k]; !ok {
+				if merged.Metadata == nil {
+					merged.Metadata = make(map[string]interface{})
+				}
+				merged.Metadata[k] = v
+			}
+		}
+
+		variants := m.Variants
+		if len(variants) == 0 {
+			variants = []string{m.Name}
+		}
+		for _, v := range variants {
+			if !seen[v] {
+				seen[v] = true
+				merged.Variants = append(merged.Variants, v)
+			}
+		}
+	}
+	sort.Strings(merged.Variants)
+
+	return merged
+}
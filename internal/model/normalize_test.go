@@ -0,0 +1,106 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestNormalizer_Normalize(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.NormalizationConfig
+		id   string
+		want string
+	}{
+		{"plain id", nil, "gpt-4o", "gpt-4o"},
+		{"provider prefix", nil, "openai/gpt-4o", "gpt-4o"},
+		{"dated suffix", nil, "gpt-4o-2024-08-06", "gpt-4o"},
+		{"compact date suffix", nil, "claude-3-5-sonnet-20241022", "claude-3-5-sonnet"},
+		{"latest alias", nil, "anthropic/claude-3-5-sonnet-latest", "claude-3-5-sonnet"},
+		{"case insensitive", nil, "OpenAI/GPT-4o", "gpt-4o"},
+		{"defaults disabled", &config.NormalizationConfig{DisableDefaults: true}, "openai/gpt-4o", "openai/gpt-4o"},
+		{
+			"custom rule",
+			&config.NormalizationConfig{Rules: []config.NormalizationRule{{Pattern: `^bedrock-`, Replace: ""}}},
+			"bedrock-claude-3-haiku",
+			"claude-3-haiku",
+		},
+		{
+			"alias on original id",
+			&config.NormalizationConfig{Aliases: map[string]string{"my-fast-model": "gpt-4o-mini"}},
+			"my-fast-model",
+			"gpt-4o-mini",
+		},
+		{
+			"alias after rules",
+			&config.NormalizationConfig{Aliases: map[string]string{"chatgpt-4o": "gpt-4o"}},
+			"openai/chatgpt-4o-latest",
+			"gpt-4o",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := NewNormalizer(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewNormalizer() error = %v", err)
+			}
+			if got := n.Normalize(tt.id); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewNormalizer_InvalidPattern(t *testing.T) {
+	_, err := NewNormalizer(&config.NormalizationConfig{
+		Rules: []config.NormalizationRule{{Pattern: "("}},
+	})
+	if err == nil {
+		t.Error("NewNormalizer() expected error for invalid pattern")
+	}
+}
+
+func TestNormalizer_Dedupe(t *testing.T) {
+	n, err := NewNormalizer(nil)
+	if err != nil {
+		t.Fatalf("NewNormalizer() error = %v", err)
+	}
+
+	models := []Model{
+		{Name: "openai/gpt-4o", MaxTokens: 128000},
+		{Name: "claude-3-haiku", MaxTokens: 200000, Mode: "chat"},
+		{Name: "gpt-4o", Mode: "chat", InputCost: 0.0025},
+		{Name: "gpt-4o-2024-08-06", MaxTokens: 128000, Mode: "chat"},
+	}
+
+	got := n.Dedupe(models)
+	want := []Model{
+		{
+			Name:      "gpt-4o",
+			MaxTokens: 128000,
+			Mode:      "chat",
+			InputCost: 0.0025,
+			Variants:  []string{"gpt-4o", "gpt-4o-2024-08-06", "openai/gpt-4o"},
+		},
+		{Name: "claude-3-haiku", MaxTokens: 200000, Mode: "chat"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Dedupe() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNormalizer_DedupeKeepsSingleModels(t *testing.T) {
+	n, _ := NewNormalizer(nil)
+
+	// まとめる対象がない場合は元のIDのまま残す
+	models := []Model{{Name: "openai/gpt-4o"}, {Name: "claude-3-haiku-20240307"}}
+	got := n.Dedupe(models)
+
+	if !reflect.DeepEqual(got, models) {
+		t.Errorf("Dedupe() = %+v, want %+v", got, models)
+	}
+}
@@ -0,0 +1,35 @@
+package model
+
+// MetaPinned はllm-info pinでピン留めしたモデルに付加するメタデータのキー
+const MetaPinned = "pinned"
+
+// ApplyPins はピン留めしたモデルIDに一致するモデルにMetaPinnedを付加します
+// --dedupeでまとめたモデルは、元のIDのいずれかがピン留めされていれば対象にします
+func ApplyPins(models []Model, pinned []string) {
+	if len(pinned) == 0 {
+		return
+	}
+	pins := make(map[string]bool, len(pinned))
+	for _, id := range pinned {
+		pins[id] = true
+	}
+	for i := range models {
+		match := pins[models[i].Name]
+		for _, variant := range models[i].Variants {
+			match = match || pins[variant]
+		}
+		if !match {
+			continue
+		}
+		if models[i].Metadata == nil {
+			models[i].Metadata = make(map[string]interface{})
+		}
+		models[i].Metadata[MetaPinned] = true
+	}
+}
+
+// IsPinned はApplyPinsでピン留めされたモデルかを返します
+func (m Model) IsPinned() bool {
+	pinned, _ := m.Metadata[MetaPinned].(bool)
+	return pinned
+}
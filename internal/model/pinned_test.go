@@ -0,0 +1,22 @@
+package model
+
+import "testing"
+
+func TestApplyPins(t *testing.T) {
+	models := []Model{
+		{Name: "gpt-4o"},
+		{Name: "claude-3-haiku", Variants: []string{"anthropic/claude-3-haiku", "claude-3-haiku"}},
+		{Name: "gpt-4o-mini", Metadata: map[string]interface{}{"mode": "chat"}},
+	}
+	ApplyPins(models, []string{"gpt-4o", "anthropic/claude-3-haiku"})
+
+	want := []bool{true, true, false}
+	for i, m := range models {
+		if got := m.IsPinned(); got != want[i] {
+			t.Errorf("%s: IsPinned() = %v, want %v", m.Name, got, want[i])
+		}
+	}
+	if _, ok := models[2].Metadata[MetaPinned]; ok {
+		t.Errorf("unpinned model got %s metadata", MetaPinned)
+	}
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ワイルドカードのルーティングエントリ（LiteLLMの"openai/*"など）に付加するメタデータのキー
+const (
+	MetaWildcard        = "wildcard"         // ワイルドカードのエントリならtrue
+	MetaWildcardMembers = "wildcard_members" // 一覧にある、パターンに一致する具体的なモデルID
+)
+
+// IsWildcard はモデルIDがワイルドカードのルーティングエントリかを返します
+func IsWildcard(id string) bool {
+	return strings.Contains(id, "*")
+}
+
+// MatchWildcard はモデルIDがワイルドカードのパターンに一致するかを返します
+// *は"/"を含む任意の文字列に一致し、大文字小文字は区別しません
+func MatchWildcard(pattern, id string) bool {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("(?i)^" + strings.Join(parts, ".*") + "$").MatchString(id)
+}
+
+// WildcardMembers は一覧にある具体的なモデルのうち、パターンに一致するモデルIDを名前順に返します
+func WildcardMembers(pattern string, models []Model) []string {
+	var members []string
+	for _, m := range models {
+		if !IsWildcard(m.Name) && MatchWildcard(pattern, m.Name) {
+			members = append(members, m.Name)
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+// TagWildcards はワイルドカードのエントリにMetaWildcardと、一覧にある一致するモデルIDを付加します
+// ワイルドカードはゲートウェイが任意のモデルIDを転送する設定のため、一覧にないモデルも利用できます
+func TagWildcards(models []Model) {
+	for i := range models {
+		if !IsWildcard(models[i].Name) {
+			continue
+		}
+		if models[i].Metadata == nil {
+			models[i].Metadata = make(map[string]interface{})
+		}
+		models[i].Metadata[MetaWildcard] = true
+		members := WildcardMembers(models[i].Name, models)
+		values := make([]interface{}, len(members))
+		for j, member := range members {
+			values[j] = member
+		}
+		models[i].Metadata[MetaWildcardMembers] = values
+	}
+}
+
+// Representative はワイルドカードのグループを代表して探索するモデルIDを返します
+// 一覧にある一致するモデルのうち、チャットモデルを優先し、その中で最も短いID（日付などのサフィックスがないもの）を選びます
+func Representative(pattern string, models []Model) (string, bool) {
+	var candidates []Model
+	for _, m := range models {
+		if !IsWildcard(m.Name) && MatchWildcard(pattern, m.Name) {
+			candidates = append(candidates, m)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if (a.Mode == "chat") != (b.Mode == "chat") {
+			return a.Mode == "chat"
+		}
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) < len(b.Name)
+		}
+		return a.Name < b.Name
+	})
+	return candidates[0].Name, true
+}
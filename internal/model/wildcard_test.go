@@ -0,0 +1,79 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchWildcard(t *testing.T) {
+	tests := []struct {
+		pattern, id string
+		want        bool
+	}{
+		{"openai/*", "openai/gpt-4o", true},
+		{"openai/*", "OpenAI/gpt-4o", true},
+		{"openai/*", "anthropic/claude-3-haiku", false},
+		{"bedrock/*", "bedrock/us.anthropic/claude", true},
+		{"*-mini", "gpt-4o-mini", true},
+		{"gpt-4.1*", "gpt-441", false},
+	}
+	for _, tt := range tests {
+		if got := MatchWildcard(tt.pattern, tt.id); got != tt.want {
+			t.Errorf("MatchWildcard(%q, %q) = %v, want %v", tt.pattern, tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestTagWildcards(t *testing.T) {
+	models := []Model{
+		{Name: "openai/*"},
+		{Name: "openai/gpt-4o", Mode: "chat"},
+		{Name: "openai/gpt-4o-mini", Mode: "chat"},
+		{Name: "anthropic/*"},
+		{Name: "gpt-4o"},
+	}
+	TagWildcards(models)
+
+	if models[0].Metadata[MetaWildcard] != true {
+		t.Errorf("openai/* metadata = %v", models[0].Metadata)
+	}
+	want := []interface{}{"openai/gpt-4o", "openai/gpt-4o-mini"}
+	if got := models[0].Metadata[MetaWildcardMembers]; !reflect.DeepEqual(got, want) {
+		t.Errorf("openai/* members = %v, want %v", got, want)
+	}
+	if got := models[3].Metadata[MetaWildcardMembers]; !reflect.DeepEqual(got, []interface{}{}) {
+		t.Errorf("anthropic/* members = %v, want empty", got)
+	}
+	if models[1].Metadata != nil {
+		t.Errorf("concrete model should not be tagged: %v", models[1].Metadata)
+	}
+}
+
+func TestRepresentative(t *testing.T) {
+	models := []Model{
+		{Name: "openai/*"},
+		{Name: "openai/text-embedding-3-small", Mode: "embedding"},
+		{Name: "openai/gpt-4o-2024-08-06", Mode: "chat"},
+		{Name: "openai/gpt-4o", Mode: "chat"},
+		{Name: "openai/o1", Mode: ""},
+	}
+
+	// チャットモデルを優先し、その中で最も短いIDを選ぶ
+	if got, ok := Representative("openai/*", models); !ok || got != "openai/gpt-4o" {
+		t.Errorf("Representative() = %q, %v, want openai/gpt-4o", got, ok)
+	}
+	if _, ok := Representative("mistral/*", models); ok {
+		t.Error("Representative() without members should fail")
+	}
+}
+
+func TestNormalizer_DedupeKeepsWildcards(t *testing.T) {
+	normalizer, err := NewNormalizer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	models := normalizer.Dedupe([]Model{{Name: "openai/*"}, {Name: "anthropic/*"}})
+	if len(models) != 2 || models[0].Name != "openai/*" || models[1].Name != "anthropic/*" {
+		t.Errorf("Dedupe() = %+v", models)
+	}
+}
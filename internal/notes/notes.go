@@ -0,0 +1,212 @@
+// Package notes はllm-info noteでモデルに付けたメモとタグをローカルのファイルに保存する
+//
+// チームのWikiなどで別に管理すると実際のモデル一覧とずれていくため、
+// モデル一覧の列やフィルタ（tag:approved）で参照できるようにする
+package notes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/paths"
+)
+
+// fileVersion はメモファイルの形式のバージョン
+const fileVersion = 1
+
+// GetDefaultNotesFile はデフォルトのメモファイルのパスを返す
+func GetDefaultNotesFile() string {
+	return paths.ConfigFile("notes.json")
+}
+
+// Notes はモデルIDごとのメモとタグ
+type Notes struct {
+	Version int               `json:"version"`
+	Models  map[string]*Entry `json:"models"`
+}
+
+// Entry は1つのモデルのメモとタグ
+type Entry struct {
+	Notes []Note   `json:"notes,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// Note はモデルに付けた1件のメモ
+type Note struct {
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// newNotes は空のメモを作成する
+func newNotes() *Notes {
+	return &Notes{Version: fileVersion, Models: make(map[string]*Entry)}
+}
+
+// Load はメモファイルを読み込む
+// ファイルが存在しない場合は空のメモを返す
+func Load(path string) (*Notes, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return newNotes(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes file: %w", err)
+	}
+
+	notes := newNotes()
+	if err := json.Unmarshal(data, notes); err != nil {
+		return nil, fmt.Errorf("failed to parse notes file %s: %w", path, err)
+	}
+	if notes.Models == nil {
+		notes.Models = make(map[string]*Entry)
+	}
+	return notes, nil
+}
+
+// Save はメモファイルを書き込む
+func (n *Notes) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create notes directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(n, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal notes: %w", err)
+	}
+
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write notes file: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to write notes file: %w", err)
+	}
+	return nil
+}
+
+// entry はモデルのエントリを返す（なければ作成する）
+func (n *Notes) entry(modelID string) *Entry {
+	e, ok := n.Models[modelID]
+	if !ok {
+		e = &Entry{}
+		n.Models[modelID] = e
+	}
+	return e
+}
+
+// AddNote はモデルにメモを追加する
+func (n *Notes) AddNote(modelID, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("note text is empty")
+	}
+	e := n.entry(modelID)
+	e.Notes = append(e.Notes, Note{Text: text, CreatedAt: time.Now().UTC()})
+	return nil
+}
+
+// RemoveNote はモデルのメモを削除する。indexは1始まりで、0の場合はすべてのメモを削除する
+func (n *Notes) RemoveNote(modelID string, index int) error {
+	e, ok := n.Models[modelID]
+	if !ok || len(e.Notes) == 0 {
+		return fmt.Errorf("%s has no notes", modelID)
+	}
+	switch {
+	case index == 0:
+		e.Notes = nil
+	case index < 0 || index > len(e.Notes):
+		return fmt.Errorf("%s has no note #%d (it has %d)", modelID, index, len(e.Notes))
+	default:
+		e.Notes = append(e.Notes[:index-1], e.Notes[index:]...)
+	}
+	n.prune(modelID)
+	return nil
+}
+
+// AddTags はモデルにタグを付ける。付いているタグは無視する
+func (n *Notes) AddTags(modelID string, tags ...string) error {
+	e := n.entry(modelID)
+	for _, tag := range tags {
+		if err := ValidateTag(tag); err != nil {
+			n.prune(modelID)
+			return err
+		}
+		if !containsFold(e.Tags, tag) {
+			e.Tags = append(e.Tags, tag)
+		}
+	}
+	return nil
+}
+
+// RemoveTags はモデルからタグを外す。付いていないタグは無視する
+func (n *Notes) RemoveTags(modelID string, tags ...string) {
+	e, ok := n.Models[modelID]
+	if !ok {
+		return
+	}
+	var kept []string
+	for _, tag := range e.Tags {
+		if !containsFold(tags, tag) {
+			kept = append(kept, tag)
+		}
+	}
+	e.Tags = kept
+	n.prune(modelID)
+}
+
+// prune はメモもタグもないモデルのエントリを削除する
+func (n *Notes) prune(modelID string) {
+	if e, ok := n.Models[modelID]; ok && len(e.Notes) == 0 && len(e.Tags) == 0 {
+		delete(n.Models, modelID)
+	}
+}
+
+// ModelIDs はメモかタグのあるモデルIDを名前順に返す
+func (n *Notes) ModelIDs() []string {
+	ids := make([]string, 0, len(n.Models))
+	for id := range n.Models {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Annotations はモデル一覧に付加するメモとタグを返す
+func (n *Notes) Annotations() map[string]model.Annotation {
+	annotations := make(map[string]model.Annotation, len(n.Models))
+	for id, e := range n.Models {
+		annotation := model.Annotation{Tags: e.Tags}
+		for _, note := range e.Notes {
+			annotation.Notes = append(annotation.Notes, note.Text)
+		}
+		annotations[id] = annotation
+	}
+	return annotations
+}
+
+// ValidateTag はタグに使えない文字が含まれていないかチェックする
+// フィルタ（tag:approved）はカンマ区切りのため、カンマと空白は使えない
+func ValidateTag(tag string) error {
+	if tag == "" || strings.ContainsAny(tag, ", \t\n") {
+		return fmt.Errorf("invalid tag %q: tags must be non-empty and cannot contain commas or spaces", tag)
+	}
+	return nil
+}
+
+// containsFold は大文字と小文字を区別せずに値が含まれるかを返す
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package notes
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNotes_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.json")
+
+	notes, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of missing file error = %v", err)
+	}
+	if err := notes.AddNote("gpt-4o", "approved for PII workloads"); err != nil {
+		t.Fatal(err)
+	}
+	if err := notes.AddNote("gpt-4o", "  "); err == nil {
+		t.Error("AddNote() with empty text error = nil, want error")
+	}
+	if err := notes.AddTags("gpt-4o", "approved", "Approved", "eu"); err != nil {
+		t.Fatal(err)
+	}
+	if err := notes.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	annotation := loaded.Annotations()["gpt-4o"]
+	if strings.Join(annotation.Notes, "|") != "approved for PII workloads" {
+		t.Errorf("Notes = %v", annotation.Notes)
+	}
+	if strings.Join(annotation.Tags, ",") != "approved,eu" {
+		t.Errorf("Tags = %v, want [approved eu]", annotation.Tags)
+	}
+}
+
+func TestNotes_Remove(t *testing.T) {
+	notes := newNotes()
+	notes.AddNote("gpt-4o", "first")
+	notes.AddNote("gpt-4o", "second")
+	notes.AddTags("gpt-4o", "approved")
+
+	if err := notes.RemoveNote("gpt-4o", 3); err == nil {
+		t.Error("RemoveNote() of missing index error = nil, want error")
+	}
+	if err := notes.RemoveNote("gpt-4o", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := notes.Models["gpt-4o"].Notes; len(got) != 1 || got[0].Text != "second" {
+		t.Errorf("Notes after removing #1 = %v", got)
+	}
+
+	// メモもタグもなくなったモデルは削除する
+	notes.RemoveNote("gpt-4o", 0)
+	notes.RemoveTags("gpt-4o", "APPROVED")
+	if len(notes.ModelIDs()) != 0 {
+		t.Errorf("ModelIDs() = %v, want empty", notes.ModelIDs())
+	}
+}
+
+func TestValidateTag(t *testing.T) {
+	for _, tag := range []string{"approved", "pii-ok", "team/ml"} {
+		if err := ValidateTag(tag); err != nil {
+			t.Errorf("ValidateTag(%q) error = %v", tag, err)
+		}
+	}
+	for _, tag := range []string{"", "a,b", "two words"} {
+		if err := ValidateTag(tag); err == nil {
+			t.Errorf("ValidateTag(%q) error = nil, want error", tag)
+		}
+	}
+
+	notes := newNotes()
+	if err := notes.AddTags("gpt-4o", "bad tag"); err == nil {
+		t.Error("AddTags() with invalid tag error = nil, want error")
+	}
+	if len(notes.ModelIDs()) != 0 {
+		t.Errorf("invalid tag left an entry: %v", notes.ModelIDs())
+	}
+}
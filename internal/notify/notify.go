@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// 通知イベントの種類
+const (
+	EventCatalogChanged = "catalog_changed"
+	EventProbeCompleted = "probe_completed"
+)
+
+// 通知ペイロードの形式
+const (
+	FormatJSON  = "json"
+	FormatSlack = "slack"
+)
+
+// defaultTimeout は通知送信のデフォルトタイムアウト
+const defaultTimeout = 10 * time.Second
+
+// Payload は通知内容を表す
+type Payload struct {
+	Event     string      `json:"event"`
+	Title     string      `json:"title"`
+	Summary   []string    `json:"summary"`
+	Gateway   string      `json:"gateway,omitempty"`
+	Model     string      `json:"model,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// slackMessage はSlack Incoming Webhookのメッセージ
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// Notifier はWebhookへ通知を送信する
+type Notifier struct {
+	client     *http.Client
+	webhookURL string
+	format     string
+	events     []string
+}
+
+// NewNotifier は新しいNotifierを作成する
+// Webhook URLが設定されていない場合はnilを返す
+func NewNotifier(cfg *config.NotificationConfig) *Notifier {
+	if cfg == nil || cfg.WebhookURL == "" {
+		return nil
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	format := cfg.Format
+	if format == "" {
+		format = FormatJSON
+	}
+
+	return &Notifier{
+		client:     &http.Client{Timeout: timeout},
+		webhookURL: cfg.WebhookURL,
+		format:     format,
+		events:     cfg.Events,
+	}
+}
+
+// Enabled は指定されたイベントが通知対象かを返す
+func (n *Notifier) Enabled(event string) bool {
+	if n == nil {
+		return false
+	}
+	if len(n.events) == 0 {
+		return true
+	}
+	for _, e := range n.events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Send は通知を送信する
+// 通知対象外のイベントやnilのNotifierの場合は何もしない
+func (n *Notifier) Send(payload *Payload) error {
+	if !n.Enabled(payload.Event) {
+		return nil
+	}
+
+	if payload.Timestamp.IsZero() {
+		payload.Timestamp = time.Now()
+	}
+
+	body, err := n.encode(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
+
+// encode は設定された形式でペイロードをエンコードする
+func (n *Notifier) encode(payload *Payload) ([]byte, error) {
+	if n.format == FormatSlack {
+		return json.Marshal(slackMessage{Text: FormatText(payload)})
+	}
+	return json.Marshal(payload)
+}
+
+// FormatText はペイロードを人間向けのテキストに整形する
+func FormatText(payload *Payload) string {
+	var sb strings.Builder
+	sb.WriteString("*" + payload.Title + "*")
+
+	var context []string
+	if payload.Model != "" {
+		context = append(context, "model: "+payload.Model)
+	}
+	if payload.Gateway != "" {
+		context = append(context, "gateway: "+payload.Gateway)
+	}
+	if len(context) > 0 {
+		sb.WriteString(" (" + strings.Join(context, ", ") + ")")
+	}
+
+	for _, line := range payload.Summary {
+		sb.WriteString("\n• " + line)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestNewNotifier(t *testing.T) {
+	if n := NewNotifier(nil); n != nil {
+		t.Error("NewNotifier(nil) should return nil")
+	}
+	if n := NewNotifier(&config.NotificationConfig{}); n != nil {
+		t.Error("NewNotifier without webhook URL should return nil")
+	}
+
+	// nilのNotifierは何も通知しない
+	var n *Notifier
+	if n.Enabled(EventProbeCompleted) {
+		t.Error("nil notifier should not be enabled")
+	}
+	if err := n.Send(&Payload{Event: EventProbeCompleted}); err != nil {
+		t.Errorf("nil notifier Send() error = %v", err)
+	}
+}
+
+func TestNotifier_Enabled(t *testing.T) {
+	all := NewNotifier(&config.NotificationConfig{WebhookURL: "https://hooks.example.com"})
+	if !all.Enabled(EventCatalogChanged) || !all.Enabled(EventProbeCompleted) {
+		t.Error("notifier without events should enable all events")
+	}
+
+	filtered := NewNotifier(&config.NotificationConfig{
+		WebhookURL: "https://hooks.example.com",
+		Events:     []string{EventCatalogChanged},
+	})
+	if !filtered.Enabled(EventCatalogChanged) {
+		t.Error("catalog_changed should be enabled")
+	}
+	if filtered.Enabled(EventProbeCompleted) {
+		t.Error("probe_completed should be disabled")
+	}
+}
+
+func TestNotifier_Send(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		status    int
+		wantErr   bool
+		checkBody func(t *testing.T, body []byte)
+	}{
+		{
+			name:   "generic json",
+			format: FormatJSON,
+			status: http.StatusOK,
+			checkBody: func(t *testing.T, body []byte) {
+				var payload Payload
+				if err := json.Unmarshal(body, &payload); err != nil {
+					t.Fatalf("invalid JSON payload: %v", err)
+				}
+				if payload.Event != EventProbeCompleted || payload.Model != "gpt-4o" {
+					t.Errorf("unexpected payload: %+v", payload)
+				}
+				if payload.Timestamp.IsZero() {
+					t.Error("timestamp should be set")
+				}
+			},
+		},
+		{
+			name:   "slack",
+			format: FormatSlack,
+			status: http.StatusOK,
+			checkBody: func(t *testing.T, body []byte) {
+				var msg map[string]string
+				if err := json.Unmarshal(body, &msg); err != nil {
+					t.Fatalf("invalid Slack payload: %v", err)
+				}
+				if !strings.Contains(msg["text"], "*Probe completed*") || !strings.Contains(msg["text"], "• context_window: 128000") {
+					t.Errorf("unexpected Slack text: %q", msg["text"])
+				}
+			},
+		},
+		{
+			name:    "server error",
+			format:  FormatJSON,
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+				}
+				received, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			notifier := NewNotifier(&config.NotificationConfig{WebhookURL: server.URL, Format: tt.format})
+			err := notifier.Send(&Payload{
+				Event:   EventProbeCompleted,
+				Title:   "Probe completed",
+				Summary: []string{"context_window: 128000"},
+				Model:   "gpt-4o",
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.checkBody != nil {
+				tt.checkBody(t, received)
+			}
+		})
+	}
+}
+
+func TestFormatText(t *testing.T) {
+	got := FormatText(&Payload{
+		Title:   "Model catalog changed",
+		Gateway: "production",
+		Summary: []string{"added gpt-5", "removed gpt-3.5-turbo"},
+	})
+	want := "*Model catalog changed* (gateway: production)\n• added gpt-5\n• removed gpt-3.5-turbo"
+	if got != want {
+		t.Errorf("FormatText() = %q, want %q", got, want)
+	}
+}
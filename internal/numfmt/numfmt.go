@@ -0,0 +1,212 @@
+// Package numfmt はトークン数とコストを表示用に整形する
+// テーブル・Markdown・CSVで同じ表記になるよう、--number-formatとglobal.number_formatの値をここで解釈する
+package numfmt
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 数値の表記
+const (
+	StyleRaw       = "raw"       // 1000000、0.0000025（デフォルト、従来の表記）
+	StyleThousands = "thousands" // 1,000,000、$2.50（100万トークンあたり）
+	StyleSI        = "si"        // 1M、128K、$2.50（100万トークンあたり）
+)
+
+// Styles は指定できる表記を返す
+func Styles() []string {
+	return []string{StyleRaw, StyleThousands, StyleSI}
+}
+
+// Validate は表記が有効かを検証する。空はrawとして扱う
+func Validate(style string) error {
+	if style == "" {
+		return nil
+	}
+	for _, s := range Styles() {
+		if style == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid number format: %s (valid: %s)", style, strings.Join(Styles(), ", "))
+}
+
+// Format は数値の表記と、ロケールに応じた桁区切り・小数点の文字
+// ゼロ値はrawの表記になる
+type Format struct {
+	Style    string
+	Group    string // 桁区切り（"," "." " "）
+	Decimal  string // 小数点（"." ","）
+	costUnit float64
+}
+
+// New は表記とロケールからFormatを作成する
+// localeは"de_DE.UTF-8"や"fr-FR"の形式で、空の場合は"en"の区切り文字を使う
+func New(style, locale string) (Format, error) {
+	if err := Validate(style); err != nil {
+		return Format{}, err
+	}
+	group, decimal := separators(locale)
+	f := Format{Style: style, Group: group, Decimal: decimal, costUnit: 1}
+	if f.Humanized() {
+		f.costUnit = 1_000_000
+	}
+	return f, nil
+}
+
+// FromEnv はLC_ALL、LC_NUMERIC、LANGのロケールでFormatを作成する
+func FromEnv(style string) (Format, error) {
+	return New(style, EnvLocale())
+}
+
+// EnvLocale は数値の表記に使うロケールを環境変数から返す
+func EnvLocale() string {
+	for _, key := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Humanized はraw以外の表記（桁区切りやSI接頭辞を使う）かを返す
+func (f Format) Humanized() bool {
+	return f.Style == StyleThousands || f.Style == StyleSI
+}
+
+// CostUnitLabel はコストの単位の説明を返す（rawでは1トークンあたり、それ以外は100万トークンあたり）
+func (f Format) CostUnitLabel() string {
+	if f.Humanized() {
+		return "per 1M tokens"
+	}
+	return "per token"
+}
+
+// Tokens はトークン数を整形する
+func (f Format) Tokens(n int) string {
+	switch f.Style {
+	case StyleThousands:
+		return f.group(strconv.Itoa(n))
+	case StyleSI:
+		return f.si(n)
+	default:
+		return strconv.Itoa(n)
+	}
+}
+
+// Cost は1トークンあたりのコストを整形する
+// rawでは従来どおり小数点以下6桁、それ以外は100万トークンあたりのドルで表示する
+func (f Format) Cost(perToken float64) string {
+	if !f.Humanized() {
+		return fmt.Sprintf("%.6f", perToken)
+	}
+	value := perToken * f.costUnit
+	var s string
+	switch {
+	case value == 0:
+		s = "0"
+	case math.Abs(value) >= 0.01:
+		s = strconv.FormatFloat(value, 'f', 2, 64)
+	default:
+		// 0.01ドル未満は有効数字2桁で表示する
+		digits := int(-math.Floor(math.Log10(math.Abs(value)))) + 1
+		s = strconv.FormatFloat(value, 'f', digits, 64)
+	}
+	return "$" + f.localize(s)
+}
+
+// ParseTokens はSI接頭辞（k、m、b、大文字小文字を区別しない）付きのトークン数を解析する
+// 表示（si）と同じく1000単位で、128kは128000、1.5mは1500000になる
+func ParseTokens(s string) (int, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	multiplier := 1.0
+	for _, unit := range []struct {
+		suffix string
+		value  float64
+	}{{"k", 1e3}, {"m", 1e6}, {"b", 1e9}} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value, multiplier = strings.TrimSuffix(value, unit.suffix), unit.value
+			break
+		}
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	// nanやintに収まらない値（inf、1e30など）はintへの変換で負の値になるため受け付けない
+	total := math.Round(number * multiplier)
+	if err != nil || math.IsNaN(number) || number < 0 || total >= float64(math.MaxInt) {
+		return 0, fmt.Errorf("invalid token count: %s (use a number with an optional k, m or b suffix, e.g. 128k)", s)
+	}
+	return int(total), nil
+}
+
+// si は1000単位のSI接頭辞（K、M、B）で整形する
+// 10未満は小数点以下1桁、それ以上は整数に丸める（128000は128K、1500000は1.5M）
+func (f Format) si(n int) string {
+	units := []struct {
+		value  float64
+		suffix string
+	}{{1e9, "B"}, {1e6, "M"}, {1e3, "K"}}
+
+	abs := math.Abs(float64(n))
+	for _, unit := range units {
+		if abs < unit.value {
+			continue
+		}
+		scaled := float64(n) / unit.value
+		var s string
+		if math.Abs(scaled) < 10 {
+			s = strings.TrimSuffix(strconv.FormatFloat(scaled, 'f', 1, 64), ".0")
+		} else {
+			s = strconv.FormatFloat(math.Round(scaled), 'f', 0, 64)
+		}
+		return f.localize(s) + unit.suffix
+	}
+	return strconv.Itoa(n)
+}
+
+// group は整数部に桁区切りを入れる
+func (f Format) group(digits string) string {
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	var sb strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteString(f.Group)
+		}
+		sb.WriteRune(r)
+	}
+	return sign + sb.String()
+}
+
+// localize は"1234.5"のような数値文字列の区切り文字をロケールに合わせる
+func (f Format) localize(s string) string {
+	integer, fraction, hasFraction := strings.Cut(s, ".")
+	if f.Style == StyleThousands {
+		integer = f.group(integer)
+	}
+	if !hasFraction {
+		return integer
+	}
+	return integer + f.Decimal + fraction
+}
+
+// separators はロケールの言語から桁区切りと小数点の文字を返す
+func separators(locale string) (group, decimal string) {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	switch lang {
+	case "de", "es", "it", "nl", "pt", "id", "tr", "da", "el":
+		return ".", ","
+	case "fr", "ru", "sv", "pl", "cs", "fi", "nb", "no", "uk", "sk", "hu":
+		return " ", ","
+	default:
+		return ",", "."
+	}
+}
@@ -0,0 +1,119 @@
+package numfmt
+
+import "testing"
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		style, locale string
+		n             int
+		want          string
+	}{
+		{StyleRaw, "", 1000000, "1000000"},
+		{"", "", 128000, "128000"},
+		{StyleThousands, "", 1000000, "1,000,000"},
+		{StyleThousands, "en_US.UTF-8", 999, "999"},
+		{StyleThousands, "de_DE.UTF-8", 1048576, "1.048.576"},
+		{StyleThousands, "fr-FR", 128000, "128 000"},
+		{StyleSI, "", 1000000, "1M"},
+		{StyleSI, "", 128000, "128K"},
+		{StyleSI, "", 131072, "131K"},
+		{StyleSI, "", 1500000, "1.5M"},
+		{StyleSI, "", 4096, "4.1K"},
+		{StyleSI, "de_DE", 1500000, "1,5M"},
+		{StyleSI, "", 999, "999"},
+		{StyleSI, "", 0, "0"},
+		{StyleSI, "", 2000000000, "2B"},
+	}
+	for _, tt := range tests {
+		f, err := New(tt.style, tt.locale)
+		if err != nil {
+			t.Fatalf("New(%q) error = %v", tt.style, err)
+		}
+		if got := f.Tokens(tt.n); got != tt.want {
+			t.Errorf("%s/%s Tokens(%d) = %q, want %q", tt.style, tt.locale, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestCost(t *testing.T) {
+	tests := []struct {
+		style, locale string
+		cost          float64
+		want          string
+	}{
+		{StyleRaw, "", 0.0000025, "0.000003"},
+		{StyleThousands, "", 0.0000025, "$2.50"},
+		{StyleSI, "", 0.00006, "$60.00"},
+		{StyleThousands, "", 0.0015, "$1,500.00"},
+		{StyleSI, "", 0.0000000025, "$0.0025"},
+		{StyleSI, "", 0, "$0"},
+		{StyleThousands, "de_DE", 0.0000025, "$2,50"},
+	}
+	for _, tt := range tests {
+		f, _ := New(tt.style, tt.locale)
+		if got := f.Cost(tt.cost); got != tt.want {
+			t.Errorf("%s/%s Cost(%g) = %q, want %q", tt.style, tt.locale, tt.cost, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, style := range append(Styles(), "") {
+		if err := Validate(style); err != nil {
+			t.Errorf("Validate(%q) error = %v", style, err)
+		}
+	}
+	if err := Validate("compact"); err == nil {
+		t.Error("expected error for unknown style")
+	}
+	if _, err := New("compact", ""); err == nil {
+		t.Error("New() should reject unknown style")
+	}
+}
+
+func TestFormat_ZeroValue(t *testing.T) {
+	// ゼロ値は従来の表記
+	var f Format
+	if got := f.Tokens(128000); got != "128000" {
+		t.Errorf("Tokens() = %q", got)
+	}
+	if got := f.Cost(0.0000025); got != "0.000003" {
+		t.Errorf("Cost() = %q", got)
+	}
+}
+
+func TestParseTokens(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"1000000", 1000000, false},
+		{"128k", 128000, false},
+		{"128K", 128000, false},
+		{"1m", 1000000, false},
+		{"1.5M", 1500000, false},
+		{"2b", 2000000000, false},
+		{" 32k ", 32000, false},
+		{"", 0, true},
+		{"k", 0, true},
+		{"12x", 0, true},
+		{"-1k", 0, true},
+		{"nan", 0, true},
+		{"NaN", 0, true},
+		{"inf", 0, true},
+		{"-inf", 0, true},
+		{"1e30", 0, true},
+		{"9.3e9b", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTokens(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTokens(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTokens(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
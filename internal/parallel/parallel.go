@@ -0,0 +1,48 @@
+// Package parallel はスライスの要素を複数のゴルーチンで処理するためのヘルパーを提供する
+package parallel
+
+import (
+	"runtime"
+	"sync"
+)
+
+// MinChunkSize は1つのゴルーチンに割り当てる要素数の下限
+// これより少ない要素の処理はゴルーチンの起動コストの方が大きくなる
+const MinChunkSize = 256
+
+// Workers は並列処理に使うゴルーチン数を返す
+func Workers() int {
+	return runtime.GOMAXPROCS(0)
+}
+
+// For は[0, n)をチャンクに分割し、fn(start, end)を並列に呼び出す
+// 各チャンクは重ならないため、fnがインデックスごとに結果を書き込めば出力順は入力順と一致する
+func For(n int, fn func(start, end int)) {
+	ForWorkers(n, Workers(), fn)
+}
+
+// ForWorkers はゴルーチン数を指定してForを実行する
+// workersが1以下、または要素数が少ない場合は呼び出し元のゴルーチンで処理する
+func ForWorkers(n, workers int, fn func(start, end int)) {
+	if n <= 0 {
+		return
+	}
+
+	chunks := min(workers, (n+MinChunkSize-1)/MinChunkSize)
+	if chunks <= 1 {
+		fn(0, n)
+		return
+	}
+
+	size := (n + chunks - 1) / chunks
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += size {
+		end := min(start+size, n)
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			fn(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,44 @@
+package parallel
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestForWorkers(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		workers int
+	}{
+		{"empty", 0, 4},
+		{"small input runs serially", 10, 4},
+		{"single worker", 1000, 1},
+		{"multiple workers", 1000, 4},
+		{"uneven chunks", 1031, 3},
+		{"more workers than chunks", 600, 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visited := make([]int32, tt.n)
+			var calls atomic.Int32
+
+			ForWorkers(tt.n, tt.workers, func(start, end int) {
+				calls.Add(1)
+				for i := start; i < end; i++ {
+					atomic.AddInt32(&visited[i], 1)
+				}
+			})
+
+			for i, v := range visited {
+				if v != 1 {
+					t.Fatalf("index %d visited %d times, want 1", i, v)
+				}
+			}
+			if tt.n > 0 && int(calls.Load()) > max(tt.workers, 1) {
+				t.Errorf("fn called %d times, want at most %d", calls.Load(), tt.workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// Package paths はllm-infoが設定・キャッシュ・状態を置くディレクトリを決める
+//
+// Linuxなどでは XDG Base Directory（XDG_CONFIG_HOME、XDG_CACHE_HOME、XDG_STATE_HOME）に、
+// Windowsでは %AppData%（設定）と %LocalAppData%（キャッシュと状態）に従う。
+// LLM_INFO_CONFIG_DIR、LLM_INFO_CACHE_DIR、LLM_INFO_STATE_DIR で個別に上書きできる。
+//
+// 以前のバージョンはすべてを ~/.config/llm-info に置いていたため、新しい場所にまだ何もなく
+// 以前の場所にファイルがある場合は以前の場所を使い続ける（保存済みの探索結果などを失わないため）
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// appName はディレクトリ名に使うアプリケーション名
+const appName = "llm-info"
+
+// 上書き用の環境変数
+const (
+	EnvConfigDir = "LLM_INFO_CONFIG_DIR"
+	EnvCacheDir  = "LLM_INFO_CACHE_DIR"
+	EnvStateDir  = "LLM_INFO_STATE_DIR"
+)
+
+// Kind はディレクトリの種類
+type Kind string
+
+const (
+	KindConfig Kind = "config" // 設定ファイルとユーザーが書いたデータ（メモなど）
+	KindCache  Kind = "cache"  // 消しても再取得できるデータ（モデル一覧、共有設定）
+	KindState  Kind = "state"  // 実行のたびに増える記録（探索結果、ログ、ロック、統計、クラッシュレポート）
+)
+
+// Dir は解決したディレクトリとその決め方
+type Dir struct {
+	Kind   Kind   `json:"kind"`
+	Path   string `json:"path"`
+	Source string `json:"source"` // 値を決めた環境変数、legacy（以前の場所）、default
+}
+
+// SourceLegacy は以前のバージョンの場所（~/.config/llm-info）を使い続けていることを表す
+const SourceLegacy = "legacy"
+
+// SourceDefault はプラットフォームの既定の場所を使っていることを表す
+const SourceDefault = "default"
+
+// resolver はプラットフォームと環境変数からディレクトリを解決する（テストで差し替えるため構造体にする）
+type resolver struct {
+	goos   string
+	getenv func(string) string
+	home   func() (string, error)
+	exists func(string) bool
+}
+
+// system は実行中のプラットフォームの resolver
+var system = resolver{
+	goos:   runtime.GOOS,
+	getenv: os.Getenv,
+	home:   os.UserHomeDir,
+	exists: func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	},
+}
+
+// ConfigDir は設定ファイルを置くディレクトリを返す
+func ConfigDir() string {
+	return system.dir(KindConfig).Path
+}
+
+// CacheDir はキャッシュを置くディレクトリを返す
+func CacheDir() string {
+	return system.dir(KindCache).Path
+}
+
+// StateDir は探索結果やログなどの状態を置くディレクトリを返す
+func StateDir() string {
+	return system.dir(KindState).Path
+}
+
+// Dirs は設定・キャッシュ・状態のディレクトリとその決め方を返す（llm-info paths で表示する）
+func Dirs() []Dir {
+	return []Dir{system.dir(KindConfig), system.dir(KindCache), system.dir(KindState)}
+}
+
+// ConfigFile は設定ディレクトリのファイル（またはディレクトリ）のパスを返す
+func ConfigFile(name string) string {
+	return system.file(KindConfig, name)
+}
+
+// StateFile は状態ディレクトリのファイル（またはディレクトリ）のパスを返す
+func StateFile(name string) string {
+	return system.file(KindState, name)
+}
+
+// LegacyDir は以前のバージョンがすべてを置いていたディレクトリ（~/.config/llm-info）を返す
+func LegacyDir() string {
+	return system.legacyDir()
+}
+
+// file は種類ごとのディレクトリにあるファイルのパスを返す
+// LLM_INFO_*_DIR で上書きされておらず、新しい場所になく以前の場所にある場合は以前の場所を返す
+func (r resolver) file(kind Kind, name string) string {
+	dir := r.dir(kind)
+	path := filepath.Join(dir.Path, name)
+	if r.overridden(dir) {
+		return path
+	}
+	if legacy := filepath.Join(r.legacyDir(), name); !r.exists(path) && r.exists(legacy) {
+		return legacy
+	}
+	return path
+}
+
+// dir は種類ごとのディレクトリを解決する
+// キャッシュは以前の場所では ~/.config/llm-info/cache にあったため、そのディレクトリを以前の場所とする
+func (r resolver) dir(kind Kind) Dir {
+	env, xdgEnv, xdgDefault, windowsEnv, windowsSub := r.layout(kind)
+
+	if path := r.getenv(env); path != "" {
+		return Dir{Kind: kind, Path: path, Source: env}
+	}
+
+	var dir Dir
+	switch {
+	case r.goos == "windows" && r.getenv(windowsEnv) != "":
+		dir = Dir{Kind: kind, Path: filepath.Join(r.getenv(windowsEnv), appName, windowsSub), Source: windowsEnv}
+	case r.goos != "windows" && r.getenv(xdgEnv) != "":
+		dir = Dir{Kind: kind, Path: filepath.Join(r.getenv(xdgEnv), appName), Source: xdgEnv}
+	default:
+		home, err := r.home()
+		if err != nil {
+			home = os.TempDir()
+		}
+		dir = Dir{Kind: kind, Path: filepath.Join(home, filepath.FromSlash(xdgDefault), appName), Source: SourceDefault}
+	}
+
+	if kind == KindCache {
+		if legacy := filepath.Join(r.legacyDir(), "cache"); !r.exists(dir.Path) && r.exists(legacy) {
+			return Dir{Kind: kind, Path: legacy, Source: SourceLegacy}
+		}
+	}
+	return dir
+}
+
+// overridden はディレクトリが LLM_INFO_*_DIR で指定されたかを返す
+func (r resolver) overridden(dir Dir) bool {
+	env, _, _, _, _ := r.layout(dir.Kind)
+	return dir.Source == env
+}
+
+// layout は種類ごとの上書き用の環境変数、XDGの環境変数と既定の場所、Windowsの環境変数とサブディレクトリを返す
+func (r resolver) layout(kind Kind) (env, xdgEnv, xdgDefault, windowsEnv, windowsSub string) {
+	switch kind {
+	case KindCache:
+		return EnvCacheDir, "XDG_CACHE_HOME", ".cache", "LOCALAPPDATA", "cache"
+	case KindState:
+		return EnvStateDir, "XDG_STATE_HOME", ".local/state", "LOCALAPPDATA", "state"
+	default:
+		return EnvConfigDir, "XDG_CONFIG_HOME", ".config", "APPDATA", ""
+	}
+}
+
+// legacyDir は以前のバージョンの場所を返す
+func (r resolver) legacyDir() string {
+	home, err := r.home()
+	if err != nil {
+		home = os.TempDir()
+	}
+	return filepath.Join(home, ".config", appName)
+}
@@ -0,0 +1,74 @@
+package paths
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// testResolver は環境変数と存在するパスを指定した resolver を返す
+func testResolver(goos string, env map[string]string, existing ...string) resolver {
+	return resolver{
+		goos:   goos,
+		getenv: func(name string) string { return env[name] },
+		home:   func() (string, error) { return filepath.FromSlash("/home/user"), nil },
+		exists: func(path string) bool {
+			for _, e := range existing {
+				if filepath.FromSlash(e) == path {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+func TestResolver_Dir(t *testing.T) {
+	tests := []struct {
+		name       string
+		goos       string
+		env        map[string]string
+		existing   []string
+		kind       Kind
+		wantPath   string
+		wantSource string
+	}{
+		{"linux config default", "linux", nil, nil, KindConfig, "/home/user/.config/llm-info", SourceDefault},
+		{"linux cache default", "linux", nil, nil, KindCache, "/home/user/.cache/llm-info", SourceDefault},
+		{"linux state default", "linux", nil, nil, KindState, "/home/user/.local/state/llm-info", SourceDefault},
+		{"xdg state", "linux", map[string]string{"XDG_STATE_HOME": "/xdg/state"}, nil, KindState, "/xdg/state/llm-info", "XDG_STATE_HOME"},
+		{"override wins over xdg", "linux", map[string]string{"XDG_CACHE_HOME": "/xdg/cache", EnvCacheDir: "/custom/cache"}, nil, KindCache, "/custom/cache", EnvCacheDir},
+		{"legacy cache", "linux", nil, []string{"/home/user/.config/llm-info/cache"}, KindCache, "/home/user/.config/llm-info/cache", SourceLegacy},
+		{"new cache exists", "linux", nil, []string{"/home/user/.config/llm-info/cache", "/home/user/.cache/llm-info"}, KindCache, "/home/user/.cache/llm-info", SourceDefault},
+		{"windows config", "windows", map[string]string{"APPDATA": "/appdata", "XDG_CONFIG_HOME": "/xdg"}, nil, KindConfig, "/appdata/llm-info", "APPDATA"},
+		{"windows state", "windows", map[string]string{"LOCALAPPDATA": "/local"}, nil, KindState, "/local/llm-info/state", "LOCALAPPDATA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := testResolver(tt.goos, tt.env, tt.existing...).dir(tt.kind)
+			if dir.Path != filepath.FromSlash(tt.wantPath) || dir.Source != tt.wantSource {
+				t.Errorf("dir(%s) = %s (%s), want %s (%s)", tt.kind, dir.Path, dir.Source, tt.wantPath, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestResolver_File(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		existing []string
+		want     string
+	}{
+		{"new location", nil, nil, "/home/user/.local/state/llm-info/estimates"},
+		{"keeps using the legacy location", nil, []string{"/home/user/.config/llm-info/estimates"}, "/home/user/.config/llm-info/estimates"},
+		{"prefers the new location", nil, []string{"/home/user/.config/llm-info/estimates", "/home/user/.local/state/llm-info/estimates"}, "/home/user/.local/state/llm-info/estimates"},
+		{"override ignores the legacy location", map[string]string{EnvStateDir: "/custom"}, []string{"/home/user/.config/llm-info/estimates"}, "/custom/estimates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testResolver("linux", tt.env, tt.existing...).file(KindState, "estimates"); got != filepath.FromSlash(tt.want) {
+				t.Errorf("file() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Package policy はゲートウェイのモデル一覧を許可・拒否リストとコスト上限のポリシーで検査する
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/search"
+)
+
+// regionKeys はリージョンとして参照するメタデータのキー（LiteLLMのlitellm_paramsを含む）
+var regionKeys = []string{
+	"region",
+	"aws_region_name",
+	"vertex_location",
+	"litellm_params.aws_region_name",
+	"litellm_params.vertex_location",
+}
+
+// Rules は許可・拒否するパターンのリスト
+// パターンは大文字小文字を区別しないglob（*は任意の文字列、?は任意の1文字）
+type Rules struct {
+	Allow        []string `yaml:"allow"`
+	Deny         []string `yaml:"deny"`
+	AllowUnknown bool     `yaml:"allow_unknown"` // 値が分からないモデルを許可リストの検査から除外する
+}
+
+// CostCeiling は1トークンあたりのコストの上限（0は上限なし）
+type CostCeiling struct {
+	MaxInputCostPerToken  float64 `yaml:"max_input_cost_per_token"`
+	MaxOutputCostPerToken float64 `yaml:"max_output_cost_per_token"`
+}
+
+// Policy はモデルの利用ポリシー
+type Policy struct {
+	Providers Rules       `yaml:"providers"`
+	Models    Rules       `yaml:"models"`
+	Regions   Rules       `yaml:"regions"`
+	Cost      CostCeiling `yaml:"cost"`
+}
+
+// Violation はポリシーに違反したモデル
+type Violation struct {
+	Model  string `json:"model"`
+	Rule   string `json:"rule"`  // "providers.deny"、"cost.max_input_cost_per_token"など
+	Value  string `json:"value"` // 違反した値（不明な場合は空）
+	Detail string `json:"detail"`
+}
+
+// Load はYAMLのポリシーファイルを読み込んで検証する
+// 綴り間違いでルールが無視されないよう、未知のキーはエラーにする
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse はYAMLのポリシーを解析して検証する
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse policy file: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Validate はポリシーの内容を検証する
+func (p *Policy) Validate() error {
+	sections := map[string]Rules{"providers": p.Providers, "models": p.Models, "regions": p.Regions}
+	empty := p.Cost.MaxInputCostPerToken == 0 && p.Cost.MaxOutputCostPerToken == 0
+	for name, rules := range sections {
+		for _, pattern := range append(append([]string{}, rules.Allow...), rules.Deny...) {
+			if strings.TrimSpace(pattern) == "" {
+				return fmt.Errorf("%s: empty pattern", name)
+			}
+		}
+		if len(rules.Allow) > 0 || len(rules.Deny) > 0 {
+			empty = false
+		}
+	}
+	if p.Cost.MaxInputCostPerToken < 0 || p.Cost.MaxOutputCostPerToken < 0 {
+		return fmt.Errorf("cost: ceilings must not be negative")
+	}
+	if empty {
+		return fmt.Errorf("policy has no rules; set providers, models, regions or cost")
+	}
+	return nil
+}
+
+// Check はモデル一覧を検査し、違反をモデル名とルールの順に返す
+// 拒否リストは許可リストより優先し、1つのモデルが複数のルールに違反した場合はすべて報告する
+func (p *Policy) Check(models []model.Model) []Violation {
+	var violations []Violation
+	for _, m := range models {
+		violations = append(violations, checkRules("models", p.Models, m.Name, m.Name)...)
+		violations = append(violations, checkRules("providers", p.Providers, m.Name, search.Provider(m))...)
+		violations = append(violations, checkRules("regions", p.Regions, m.Name, Region(m))...)
+		violations = append(violations, p.checkCost(m)...)
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Model != violations[j].Model {
+			return violations[i].Model < violations[j].Model
+		}
+		return violations[i].Rule < violations[j].Rule
+	})
+	return violations
+}
+
+// checkRules は値を許可・拒否リストと照合する
+func checkRules(section string, rules Rules, modelName, value string) []Violation {
+	if value != "" {
+		if pattern, ok := matchAny(rules.Deny, value); ok {
+			return []Violation{{
+				Model:  modelName,
+				Rule:   section + ".deny",
+				Value:  value,
+				Detail: fmt.Sprintf("%s matches denied pattern %q", value, pattern),
+			}}
+		}
+	}
+	if len(rules.Allow) == 0 {
+		return nil
+	}
+	if value == "" {
+		if rules.AllowUnknown {
+			return nil
+		}
+		return []Violation{{
+			Model:  modelName,
+			Rule:   section + ".allow",
+			Detail: fmt.Sprintf("%s is unknown; set %s.allow_unknown to permit it", strings.TrimSuffix(section, "s"), section),
+		}}
+	}
+	if _, ok := matchAny(rules.Allow, value); ok {
+		return nil
+	}
+	return []Violation{{
+		Model:  modelName,
+		Rule:   section + ".allow",
+		Value:  value,
+		Detail: fmt.Sprintf("%s is not in the allow list (%s)", value, strings.Join(rules.Allow, ", ")),
+	}}
+}
+
+// checkCost はコストの上限を検査する。コストが不明なモデルは検査しない
+func (p *Policy) checkCost(m model.Model) []Violation {
+	var violations []Violation
+	if ceiling := p.Cost.MaxInputCostPerToken; ceiling > 0 && m.InputCost > ceiling {
+		violations = append(violations, costViolation(m.Name, "max_input_cost_per_token", m.InputCost, ceiling))
+	}
+	if ceiling := p.Cost.MaxOutputCostPerToken; ceiling > 0 {
+		if cost, ok := OutputCost(m); ok && cost > ceiling {
+			violations = append(violations, costViolation(m.Name, "max_output_cost_per_token", cost, ceiling))
+		}
+	}
+	return violations
+}
+
+// costViolation はコスト上限の違反を作成する
+func costViolation(modelName, rule string, cost, ceiling float64) Violation {
+	return Violation{
+		Model:  modelName,
+		Rule:   "cost." + rule,
+		Value:  model.FormatMetaValue(cost),
+		Detail: fmt.Sprintf("%s exceeds the ceiling of %s per token", model.FormatMetaValue(cost), model.FormatMetaValue(ceiling)),
+	}
+}
+
+// Region はモデルのデプロイ先リージョンをメタデータから返す（不明な場合は空）
+func Region(m model.Model) string {
+	for _, key := range regionKeys {
+		if value, ok := m.MetaValue(key); ok {
+			if s := model.FormatMetaValue(value); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// OutputCost は1トークンあたりの出力コストをメタデータから返す
+func OutputCost(m model.Model) (float64, bool) {
+	for _, key := range []string{"output_cost_per_token", "output_cost"} {
+		if value, ok := m.MetaValue(key); ok {
+			if cost, ok := value.(float64); ok {
+				return cost, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// matchAny は値に一致した最初のパターンを返す
+func matchAny(patterns []string, value string) (string, bool) {
+	for _, pattern := range patterns {
+		if globMatch(pattern, value) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// globMatch は大文字小文字を区別せずにglobパターンと照合する
+// path.Matchと異なり、*は"/"を含む文字列にも一致する（openai/gpt-4oなど）
+func globMatch(pattern, value string) bool {
+	var sb strings.Builder
+	sb.WriteString("(?i)^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String()).MatchString(value)
+}
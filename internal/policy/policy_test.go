@@ -0,0 +1,91 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/armaniacs/llm-info/internal/model"
+)
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(`
+providers:
+  allow: [openai, anthropic]
+models:
+  deny: ["*-preview"]
+cost:
+  max_input_cost_per_token: 0.00001
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(p.Providers.Allow) != 2 || p.Models.Deny[0] != "*-preview" || p.Cost.MaxInputCostPerToken != 0.00001 {
+		t.Errorf("Parse() = %+v", p)
+	}
+
+	invalid := map[string]string{
+		"unknown key": "providers:\n  alow: [openai]\n",
+		"empty":       "",
+		"blank":       "models:\n  deny: [\" \"]\n",
+		"negative":    "cost:\n  max_input_cost_per_token: -1\n",
+	}
+	for name, data := range invalid {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: Parse() error = nil, want error", name)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	p := &Policy{
+		Providers: Rules{Allow: []string{"openai", "anthropic"}},
+		Models:    Rules{Deny: []string{"*-preview"}},
+		Regions:   Rules{Allow: []string{"us-*"}, AllowUnknown: true},
+		Cost:      CostCeiling{MaxInputCostPerToken: 0.00001, MaxOutputCostPerToken: 0.00003},
+	}
+	models := []model.Model{
+		{Name: "openai/gpt-4o", InputCost: 0.0000025},
+		{Name: "openai/gpt-4.5-preview", InputCost: 0.000075, Metadata: map[string]interface{}{"output_cost_per_token": 0.00015}},
+		{Name: "mistral/mistral-large", Metadata: map[string]interface{}{"region": "eu-west-1"}},
+		{Name: "claude-3-haiku", Metadata: map[string]interface{}{"litellm_provider": "anthropic", "region": "us-east-1"}},
+		{Name: "local-model"},
+	}
+
+	violations := p.Check(models)
+	var got []string
+	for _, v := range violations {
+		got = append(got, v.Model+" "+v.Rule)
+	}
+	want := []string{
+		"local-model providers.allow",
+		"mistral/mistral-large providers.allow",
+		"mistral/mistral-large regions.allow",
+		"openai/gpt-4.5-preview cost.max_input_cost_per_token",
+		"openai/gpt-4.5-preview cost.max_output_cost_per_token",
+		"openai/gpt-4.5-preview models.deny",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Check() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !strings.Contains(violations[0].Detail, "allow_unknown") {
+		t.Errorf("unknown provider detail = %q", violations[0].Detail)
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, value string
+		want           bool
+	}{
+		{"*-preview", "openai/gpt-4.5-preview", true},
+		{"GPT-4?", "gpt-4o", true},
+		{"gpt-4?", "gpt-4o-mini", false},
+		{"us-*", "eu-west-1", false},
+		{"gpt-4.1", "gpt-441", false},
+	}
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package probe
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+// requestBodyOverhead はプロンプト以外にリクエストボディに含まれるバイト数の見積もり
+// （モデル名などのJSONの外枠とエスケープによる増加分）
+const requestBodyOverhead = 1024
+
+// bodySizePatterns はリクエストボディのサイズ超過を表すエラーメッセージのパターン
+// トークン数の上限とは異なり、探索するトークン数を増やしても受け付けられることはない
+var bodySizePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)request entity too large`),
+	regexp.MustCompile(`(?i)payload too large`),
+	regexp.MustCompile(`(?i)(request )?body (is )?too large`),
+	regexp.MustCompile(`(?i)body size .*(exceed|limit)`),
+	regexp.MustCompile(`(?i)exceeds? .*maximum .*(body|request) size`),
+}
+
+// bodyGuard は探索リクエストのボディのサイズ上限を管理する
+// 上限は設定値（max_body_size）で初期化し、サイズ超過で拒否されるたびに拒否されたサイズ未満に下げる
+type bodyGuard struct {
+	limit int64 // リクエストボディの最大バイト数（0なら無制限）
+}
+
+// reset は上限を設定値に戻す
+func (g *bodyGuard) reset(limit int64) {
+	g.limit = limit
+}
+
+// exceeds はプロンプトを送信するとボディが上限を超えるかを返す
+func (g *bodyGuard) exceeds(promptSize int64) bool {
+	return g.limit > 0 && promptSize+requestBodyOverhead > g.limit
+}
+
+// reject はサイズ超過で拒否されたプロンプトのサイズを記録し、以降はそれ未満のプロンプトだけを送信する
+func (g *bodyGuard) reject(promptSize int64) {
+	limit := promptSize + requestBodyOverhead - 1
+	if g.limit == 0 || limit < g.limit {
+		g.limit = limit
+	}
+}
+
+// skipped はサイズ上限を超えるため送信しなかった試行の結果を返す
+func (g *bodyGuard) skipped(promptSize int64) *BoundarySearchResult {
+	return &BoundarySearchResult{
+		Success:      false,
+		ErrorMessage: fmt.Sprintf("request body too large: prompt of %d bytes exceeds the body size limit of %d bytes (not sent)", promptSize, g.limit),
+		Source:       "body_too_large",
+		Trials:       1,
+	}
+}
+
+// isBodyTooLarge はエラーがリクエストボディのサイズ超過によるものかを返す
+func isBodyTooLarge(err error, errorMessage string) bool {
+	if errors.Is(err, api.ErrRequestTooLarge) {
+		return true
+	}
+	for _, pattern := range bodySizePatterns {
+		if pattern.MatchString(errorMessage) {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyTooLargeResult はサイズ超過で拒否された試行の結果を返す
+func bodyTooLargeResult(errorMessage string) *BoundarySearchResult {
+	return &BoundarySearchResult{
+		Success:      false,
+		ErrorMessage: errorMessage,
+		Source:       "body_too_large",
+		Trials:       1,
+	}
+}
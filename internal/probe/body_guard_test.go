@@ -0,0 +1,167 @@
+package probe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestIsBodyTooLarge(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		message string
+		want    bool
+	}{
+		{"413", fmt.Errorf("%w (status 413)", api.ErrRequestTooLarge), "", true},
+		{"nginx", errors.New("API error"), "413 Request Entity Too Large", true},
+		{"payload", errors.New("API error"), "Payload Too Large", true},
+		{"body size", errors.New("API error"), "request body size exceeds the limit of 10485760 bytes", true},
+		{"token limit", errors.New("API error"), "This model's maximum context length is 8192 tokens", false},
+		{"rate limit", errors.New("API error"), "Request too large for gpt-4 on tokens per min", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBodyTooLarge(tt.err, tt.message); got != tt.want {
+				t.Errorf("isBodyTooLarge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBodyGuard(t *testing.T) {
+	var guard bodyGuard
+	if guard.exceeds(1 << 30) {
+		t.Error("exceeds() = true, want false without a limit")
+	}
+
+	// 拒否されたサイズ以上のプロンプトは送信しない
+	guard.reject(10000)
+	if !guard.exceeds(10000) || guard.exceeds(9999) {
+		t.Errorf("limit = %d, want prompts below 10000 bytes only", guard.limit)
+	}
+	// 大きいサイズで拒否されても上限は上げない
+	guard.reject(20000)
+	if !guard.exceeds(10000) {
+		t.Errorf("limit = %d, should not be raised", guard.limit)
+	}
+
+	guard.reset(0)
+	if guard.exceeds(10000) {
+		t.Error("exceeds() = true after reset, want false")
+	}
+}
+
+// bodyLimitServer はトークン数の検証より前にボディのサイズで拒否するゲートウェイを模したテストサーバー
+func bodyLimitServer(t *testing.T, maxBytes int, largest *atomic.Int64) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read request: %v", err)
+			return
+		}
+		if size := int64(len(body)); size > largest.Load() {
+			largest.Store(size)
+		}
+		if len(body) > maxBytes {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			io.WriteString(w, "<html><body>413 Request Entity Too Large</body></html>")
+			return
+		}
+
+		var req api.ProbeRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			return
+		}
+		promptTokens := len(req.Messages[0].Content) * 4 / 3
+		json.NewEncoder(w).Encode(api.ProbeResponse{
+			Choices: []api.ChatChoice{{FinishReason: "stop"}},
+			Usage:   &api.UsageInfo{PromptTokens: promptTokens, CompletionTokens: 1, TotalTokens: promptTokens + 1},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMaxInputProbe_BodySizeLimit(t *testing.T) {
+	const maxBytes = 6000
+
+	for _, configured := range []int64{0, maxBytes} {
+		t.Run(fmt.Sprintf("max_body_size=%d", configured), func(t *testing.T) {
+			var largest atomic.Int64
+			server := bodyLimitServer(t, maxBytes, &largest)
+			p := NewMaxInputProbe(api.NewProbeClient(&config.AppConfig{
+				BaseURL:     server.URL,
+				APIKey:      "test",
+				Timeout:     5 * time.Second,
+				MaxBodySize: configured,
+			}))
+			p.searcher.delay = 0
+
+			result, err := p.ProbeInputTokens("test-model", 16)
+			if err != nil {
+				t.Fatalf("ProbeInputTokens() error = %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("ProbeInputTokens() failed: %s", result.ErrorMessage)
+			}
+			// 1トークン≈3/4バイトのため、ボディの上限は約8000トークンに相当する
+			if result.MaxInputTokens < 4096 || result.MaxInputTokens > maxBytes*4/3 {
+				t.Errorf("MaxInputTokens = %d, want the boundary below the body size limit", result.MaxInputTokens)
+			}
+
+			found := false
+			for _, e := range result.Confidence.Evidence {
+				if e.Kind == EvidenceBodyTooLarge {
+					found = true
+				}
+				if e.Kind == EvidenceRejected {
+					t.Errorf("body size rejection recorded as %s", e)
+				}
+			}
+			if !found {
+				t.Error("no body_too_large evidence")
+			}
+
+			// 設定した上限を超えるリクエストは送信しない
+			if configured > 0 && largest.Load() > configured {
+				t.Errorf("largest request = %d bytes, want at most %d", largest.Load(), configured)
+			}
+		})
+	}
+}
+
+func TestExponentialSearch_StopsOnBodyTooLarge(t *testing.T) {
+	bs := NewBoundarySearcher()
+	bs.delay = 0
+
+	calls := 0
+	result, err := bs.ExponentialSearch(func(tokens int) (*BoundarySearchResult, error) {
+		calls++
+		return bodyTooLargeResult("request body too large"), nil
+	})
+	if err != nil {
+		t.Fatalf("ExponentialSearch() error = %v", err)
+	}
+	if result.Success || result.Source != "body_too_large" {
+		t.Errorf("result = %+v, want body_too_large failure", result)
+	}
+	// 値を増やしても受け付けられないため1回で打ち切る
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
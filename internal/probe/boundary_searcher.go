@@ -1,9 +1,6 @@
 package probe
 
 import (
-	"fmt"
-	"math"
-	"regexp"
 	"time"
 )
 
@@ -25,7 +22,7 @@ type BoundarySearchResult struct {
 	Value           int
 	Success         bool
 	ErrorMessage    string
-	Source          string // "validation_error" or "max_output_incomplete"
+	Source          string // "validation_error", "max_output_incomplete" or "body_too_large"
 	Trials          int
 	EstimatedTokens int
 }
@@ -34,6 +31,7 @@ type BoundarySearchResult struct {
 type BoundarySearcher struct {
 	maxTrials    int
 	initialValue int
+	delay        time.Duration // 試行間の待機（レート制限対策）
 	verbose      VerboseLogger
 }
 
@@ -42,6 +40,7 @@ func NewBoundarySearcher() *BoundarySearcher {
 	return &BoundarySearcher{
 		maxTrials:    10, // テスト用に減らす
 		initialValue: 4096,
+		delay:        500 * time.Millisecond, // テスト用に短くする
 	}
 }
 
@@ -102,7 +101,7 @@ func (bs *BoundarySearcher) Search(lower, upper int, runner func(int) (*Boundary
 		}
 
 		// API呼び出し間の待機（レート制限対策）
-		time.Sleep(500 * time.Millisecond) // テスト用に短くする
+		time.Sleep(bs.delay)
 	}
 
 	// 最終的な下界が成功した場合
@@ -210,6 +209,18 @@ func (bs *BoundarySearcher) ExponentialSearch(runner func(int) (*BoundarySearchR
 			}, nil
 		}
 
+		// ボディのサイズで拒否された場合、値を増やしても受け付けられない
+		if result.Source == "body_too_large" {
+			return &BoundarySearchResult{
+				Value:           value,
+				Success:         false,
+				ErrorMessage:    result.ErrorMessage,
+				Source:          "body_too_large",
+				Trials:          trials,
+				EstimatedTokens: 0,
+			}, nil
+		}
+
 		// 失敗した場合、値を増やして探索を続ける
 		value *= 2
 	}
@@ -226,40 +237,8 @@ func (bs *BoundarySearcher) ExponentialSearch(runner func(int) (*BoundarySearchR
 }
 
 // ExtractTokenLimitFromError はエラーメッセージからトークン制限を抽出する
+// プロバイダーごとのパターンを順に試し、どれにも一致しなければ汎用のパターンを使う
 func (bs *BoundarySearcher) ExtractTokenLimitFromError(errorMessage string) (int, bool) {
-	// 一般的な最大コンテキスト長のパターン
-	patterns := []string{
-		`maximum context length is (\d+) tokens`,
-		`your request resulted in (\d+) tokens`,
-		`this model's maximum context length is (\d+) tokens`,
-		`prompt tokens must be less than (\d+)`,
-	}
-
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindStringSubmatch(errorMessage)
-		if len(matches) > 1 {
-			// 最初のキャプチャグループを数値として返す
-			var result int
-			_, err := fmt.Sscanf(matches[1], "%d", &result)
-			if err == nil {
-				return result, true
-			}
-		}
-	}
-	return 0, false
+	value, _, found := matchLimit(errorMessage, limitKindInput)
+	return value, found
 }
-
-// CalculateConfidence は探索結果の信頼度を計算する
-func (bs *BoundarySearcher) CalculateConfidence(trials int, errorSource string, exactValue int) string {
-	if errorSource == "success" && trials > 5 {
-		return "high"
-	}
-	if errorSource == "validation_error" {
-		return "high"
-	}
-	if exactValue > 0 && (float64(trials)/math.Log2(float64(exactValue)) > 0.8) {
-		return "high"
-	}
-	return "medium"
-}
\ No newline at end of file
@@ -0,0 +1,82 @@
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Capabilities はメッセージロールとリクエストパラメータの互換性、プロンプトのオーバーヘッド、プロンプトキャッシュ、続きの依頼の探索結果
+// probe-roles、probe-params、probe-overhead、probe-caching、probe-continuationはそれぞれ自分の項目だけを更新し、他は前回の結果を引き継ぐ
+type Capabilities struct {
+	SchemaVersion      string                `json:"schema_version"`
+	Model              string                `json:"model"`
+	Gateway            string                `json:"gateway,omitempty"`
+	Roles              []RoleCompatResult    `json:"roles,omitempty"`
+	RolesProbedAt      *time.Time            `json:"roles_probed_at,omitempty"`
+	Parameters         []ParamSupportResult  `json:"parameters,omitempty"`
+	ParametersProbedAt *time.Time            `json:"parameters_probed_at,omitempty"`
+	PromptOverhead     *PromptOverheadResult `json:"prompt_overhead,omitempty"`
+	PromptCaching      *PromptCachingResult  `json:"prompt_caching,omitempty"`
+	Continuation       *ContinuationResult   `json:"continuation,omitempty"`
+}
+
+// NewCapabilities は空の互換性結果を作成する
+func NewCapabilities(model, gateway string) *Capabilities {
+	return &Capabilities{
+		SchemaVersion: ResultSchemaVersion,
+		Model:         model,
+		Gateway:       gateway,
+	}
+}
+
+// DecodeCapabilities は保存済みの結果（JSONをデコードした値）からCapabilitiesを復元する
+func DecodeCapabilities(saved interface{}) (*Capabilities, error) {
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read capabilities: %w", err)
+	}
+	var capabilities Capabilities
+	if err := json.Unmarshal(data, &capabilities); err != nil {
+		return nil, fmt.Errorf("failed to read capabilities: %w", err)
+	}
+	return &capabilities, nil
+}
+
+// SetRoles はロールの互換性の結果を記録する
+func (c *Capabilities) SetRoles(report *RoleCompatReport) {
+	probedAt := report.ProbedAt
+	c.Roles = report.Results
+	c.RolesProbedAt = &probedAt
+}
+
+// SetParameters はパラメータの対応状況を記録する
+func (c *Capabilities) SetParameters(results []ParamSupportResult, probedAt time.Time) {
+	c.Parameters = results
+	c.ParametersProbedAt = &probedAt
+}
+
+// SetPromptOverhead はゲートウェイが追加したプロンプトのオーバーヘッドを記録する
+func (c *Capabilities) SetPromptOverhead(result *PromptOverheadResult) {
+	c.PromptOverhead = result
+}
+
+// SetPromptCaching はプロンプトキャッシュへの対応状況を記録する
+func (c *Capabilities) SetPromptCaching(result *PromptCachingResult) {
+	c.PromptCaching = result
+}
+
+// SetContinuation は続きの依頼で長い出力をつなげられるかを記録する
+func (c *Capabilities) SetContinuation(result *ContinuationResult) {
+	c.Continuation = result
+}
+
+// Parameter は指定したパラメータの対応状況を返す（未探索なら空文字列）
+func (c *Capabilities) Parameter(name string) string {
+	for _, result := range c.Parameters {
+		if result.Parameter == name {
+			return result.Status
+		}
+	}
+	return ""
+}
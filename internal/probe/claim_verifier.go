@@ -0,0 +1,271 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+// 公称値の検証結果
+const (
+	ClaimConfirmed    = "CONFIRMED"    // 公称値ちょうどは受け付けられ、1つ超えると拒否される
+	ClaimOverstated   = "OVERSTATED"   // 公称値ちょうどでも拒否される
+	ClaimUnderstated  = "UNDERSTATED"  // 公称値を超えても受け付けられる
+	ClaimInconclusive = "INCONCLUSIVE" // 試行結果から判断できない
+)
+
+// 検証する制約値の種類
+const (
+	ClaimContextWindow = "context_window"
+	ClaimMaxOutput     = "max_output"
+)
+
+// verifyMaxTokens はcontext window検証時のmax_tokens
+const verifyMaxTokens = 16
+
+// Claims はゲートウェイのカタログが公表している制約値（0は公表なし）
+type Claims struct {
+	ContextWindow int
+	MaxOutput     int
+}
+
+// ClaimCheck は1つの公称値の検証結果
+type ClaimCheck struct {
+	Limit    string      `json:"limit"`
+	Claimed  int         `json:"claimed"`
+	Status   string      `json:"status"`
+	Observed int         `json:"observed,omitempty"` // エラーメッセージやusageから読み取った値
+	Detail   string      `json:"detail"`
+	Trials   []TrialInfo `json:"trials"`
+}
+
+// VerifyReport は1モデル分の検証結果
+type VerifyReport struct {
+	Model      string       `json:"model"`
+	Gateway    string       `json:"gateway,omitempty"`
+	Checks     []ClaimCheck `json:"checks"`
+	VerifiedAt time.Time    `json:"verified_at"`
+	Error      string       `json:"error,omitempty"` // 検証自体が失敗した場合のエラー（--continue-on-error）
+}
+
+// Mismatch は公称値と食い違う制約値があるかを返す
+func (r *VerifyReport) Mismatch() bool {
+	for _, check := range r.Checks {
+		if check.Status == ClaimOverstated || check.Status == ClaimUnderstated {
+			return true
+		}
+	}
+	return false
+}
+
+// Check は指定した制約値の検証結果を返す
+func (r *VerifyReport) Check(limit string) (ClaimCheck, bool) {
+	for _, check := range r.Checks {
+		if check.Limit == limit {
+			return check, true
+		}
+	}
+	return ClaimCheck{}, false
+}
+
+// ClaimVerifier は公称値ちょうどと公称値+1の2回の試行で公称値を検証する
+// 境界を探索しないため、定期的な監査では探索よりはるかに安く済む
+type ClaimVerifier struct {
+	client    *api.ProbeClient
+	generator *TestDataGenerator
+	searcher  *BoundarySearcher
+	recorder  trialRecorder
+	delay     time.Duration // リクエスト間の待機（レート制限対策）
+}
+
+// NewClaimVerifier は新しいClaimVerifierを作成する
+func NewClaimVerifier(client *api.ProbeClient) *ClaimVerifier {
+	return &ClaimVerifier{
+		client:    client,
+		generator: NewTestDataGenerator(),
+		searcher:  NewBoundarySearcher(),
+		delay:     500 * time.Millisecond,
+	}
+}
+
+// claimAttempt は1回の検証リクエストの結果
+type claimAttempt struct {
+	accepted     bool
+	promptTokens int    // 受け付けられた場合のusage.prompt_tokens
+	reported     int    // エラーメッセージから読み取った上限（0は読み取れず）
+	message      string // 拒否された場合のエラーメッセージ
+	failed       bool   // 通信エラーやサーバーエラーで、拒否かどうか判断できない
+}
+
+// Verify は公表されている制約値をそれぞれ検証する
+// 小さなリクエストが失敗した場合は接続や認証の問題とみなしてエラーを返す
+func (v *ClaimVerifier) Verify(model, gateway string, claims Claims) (*VerifyReport, error) {
+	report := &VerifyReport{Model: model, Gateway: gateway, VerifiedAt: time.Now()}
+
+	if _, err := v.client.ProbeMessages(model, []api.Message{{Role: "user", Content: "Reply with OK."}}, verifyMaxTokens); err != nil {
+		return nil, fmt.Errorf("baseline request failed: %w", err)
+	}
+
+	if claims.ContextWindow > 0 {
+		v.wait()
+		report.Checks = append(report.Checks, v.VerifyContextWindow(model, claims.ContextWindow))
+	}
+	if claims.MaxOutput > 0 {
+		v.wait()
+		report.Checks = append(report.Checks, v.VerifyMaxOutput(model, claims.MaxOutput))
+	}
+	return report, nil
+}
+
+// VerifyContextWindow は入力とmax_tokensの合計が公称値ちょうど、公称値+1になるリクエストを送る
+func (v *ClaimVerifier) VerifyContextWindow(model string, claimed int) ClaimCheck {
+	v.recorder.reset()
+	check := ClaimCheck{Limit: ClaimContextWindow, Claimed: claimed}
+
+	atLimit := v.tryContext(model, claimed-verifyMaxTokens)
+	v.wait()
+	overLimit := v.tryContext(model, claimed-verifyMaxTokens+1)
+	check.Trials = v.recorder.history()
+
+	switch {
+	case atLimit.reported > 0:
+		classifyReported(&check, atLimit.reported, "limit reported when sending the claimed size")
+	case overLimit.reported > 0:
+		classifyReported(&check, overLimit.reported, "limit reported when exceeding the claim")
+	case atLimit.failed || overLimit.failed:
+		markFailed(&check, atLimit, overLimit)
+	case !atLimit.accepted:
+		check.Status = ClaimOverstated
+		check.Detail = "request at the claimed limit was rejected: " + atLimit.message
+	case overLimit.accepted && overLimit.promptTokens > 0 && overLimit.promptTokens+verifyMaxTokens <= claimed:
+		// 生成したプロンプトが想定より少なく数えられた
+		check.Status = ClaimInconclusive
+		check.Observed = overLimit.promptTokens + verifyMaxTokens
+		check.Detail = fmt.Sprintf("prompt over the claim was counted as %d tokens, not enough to exceed it", overLimit.promptTokens)
+	case overLimit.accepted:
+		check.Status = ClaimUnderstated
+		check.Observed = overLimit.promptTokens + verifyMaxTokens
+		check.Detail = fmt.Sprintf("request over the claim was accepted (%d prompt tokens)", overLimit.promptTokens)
+	default:
+		check.Status = ClaimConfirmed
+		check.Observed = claimed
+		check.Detail = "accepted at the claimed limit, rejected above it"
+	}
+	return check
+}
+
+// VerifyMaxOutput はmax_tokensを公称値ちょうど、公称値+1にしたリクエストを送る
+// 短い応答を求めるため、受け付けられても出力トークンはほとんど消費しない
+func (v *ClaimVerifier) VerifyMaxOutput(model string, claimed int) ClaimCheck {
+	v.recorder.reset()
+	check := ClaimCheck{Limit: ClaimMaxOutput, Claimed: claimed}
+
+	atLimit := v.tryOutput(model, claimed)
+	v.wait()
+	overLimit := v.tryOutput(model, claimed+1)
+	check.Trials = v.recorder.history()
+
+	switch {
+	case atLimit.reported > 0:
+		classifyReported(&check, atLimit.reported, "limit reported for max_tokens at the claim")
+	case overLimit.reported > 0:
+		classifyReported(&check, overLimit.reported, "limit reported for max_tokens above the claim")
+	case atLimit.failed || overLimit.failed:
+		markFailed(&check, atLimit, overLimit)
+	case !atLimit.accepted:
+		check.Status = ClaimOverstated
+		check.Detail = "max_tokens at the claimed limit was rejected: " + atLimit.message
+	case overLimit.accepted:
+		check.Status = ClaimUnderstated
+		check.Detail = fmt.Sprintf("max_tokens=%d was accepted; the limit is higher or not enforced", claimed+1)
+	default:
+		check.Status = ClaimConfirmed
+		check.Observed = claimed
+		check.Detail = "accepted at the claimed limit, rejected above it"
+	}
+	return check
+}
+
+// classifyReported はエラーメッセージから読み取った上限と公称値を比較する
+func classifyReported(check *ClaimCheck, reported int, detail string) {
+	check.Observed = reported
+	check.Detail = fmt.Sprintf("%s: %d", detail, reported)
+	switch {
+	case reported < check.Claimed:
+		check.Status = ClaimOverstated
+	case reported > check.Claimed:
+		check.Status = ClaimUnderstated
+	default:
+		check.Status = ClaimConfirmed
+	}
+}
+
+// markFailed は通信エラーなどで判断できなかった検証をINCONCLUSIVEにする
+func markFailed(check *ClaimCheck, attempts ...claimAttempt) {
+	check.Status = ClaimInconclusive
+	for _, attempt := range attempts {
+		if attempt.failed {
+			check.Detail = "request failed: " + attempt.message
+			return
+		}
+	}
+}
+
+// tryContext は指定したトークン数の入力とmax_tokens=verifyMaxTokensでリクエストを1回送る
+func (v *ClaimVerifier) tryContext(model string, tokens int) claimAttempt {
+	prompt := v.generator.NewPrompt(tokens, End, defaultNeedle, defaultQuestion)
+	return v.try(tokens, func() (*api.ProbeResponse, error) {
+		return v.client.ProbeModelWithMaxTokens(model, prompt.Reader(), verifyMaxTokens)
+	}, v.searcher.ExtractTokenLimitFromError)
+}
+
+// tryOutput は短い入力と指定したmax_tokensでリクエストを1回送る
+func (v *ClaimVerifier) tryOutput(model string, maxTokens int) claimAttempt {
+	messages := []api.Message{{Role: "user", Content: "Reply with OK."}}
+	return v.try(maxTokens, func() (*api.ProbeResponse, error) {
+		return v.client.ProbeMessages(model, messages, maxTokens)
+	}, extractOutputLimitFromError)
+}
+
+// try はリクエストを送信して結果を試行履歴に記録する
+func (v *ClaimVerifier) try(tokens int, send func() (*api.ProbeResponse, error), extract func(string) (int, bool)) (attempt claimAttempt) {
+	trialStart := time.Now()
+	throttleStart := v.client.Throttling()
+	response, err := send()
+	latency := time.Since(trialStart)
+
+	result := &BoundarySearchResult{Success: err == nil, Source: "success"}
+	if err != nil {
+		attempt.message = err.Error()
+		if response != nil && response.Error != nil {
+			attempt.message = response.Error.Message
+		} else if !strings.HasPrefix(attempt.message, "unexpected status code: 4") {
+			// APIが返したエラーでなければリクエストが拒否されたとはいえない
+			attempt.failed = true
+		}
+		result.ErrorMessage = attempt.message
+		result.Source = "error"
+		if limit, found := extract(attempt.message); found {
+			attempt.reported = limit
+			result.Value = limit
+			result.Source = "validation_error"
+		}
+	} else {
+		attempt.accepted = true
+		if response.Usage != nil {
+			attempt.promptTokens = response.Usage.PromptTokens
+			result.Value = response.Usage.PromptTokens
+		}
+	}
+	v.recorder.record(tokens, trialStart, latency, v.client.Throttling().Sub(throttleStart), response, result)
+	return attempt
+}
+
+// wait はリクエスト間で待機する
+func (v *ClaimVerifier) wait() {
+	if v.delay > 0 {
+		time.Sleep(v.delay)
+	}
+}
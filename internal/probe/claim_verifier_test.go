@@ -0,0 +1,134 @@
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func newTestClaimVerifier(server *httptest.Server) *ClaimVerifier {
+	v := NewClaimVerifier(api.NewProbeClient(&config.AppConfig{
+		BaseURL: server.URL,
+		APIKey:  "test",
+		Timeout: 5 * time.Second,
+	}))
+	v.delay = 0
+	return v
+}
+
+// outputLimitServer はmax_tokensがlimitを超えると拒否するテストサーバー（limit=0は拒否しない）
+func outputLimitServer(t *testing.T, limit int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req api.ProbeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			return
+		}
+		if limit > 0 && req.MaxTokens > limit {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(api.ProbeResponse{Error: &api.OpenAIError{
+				Type:    "invalid_request_error",
+				Message: fmt.Sprintf("max_tokens must be <= %d", limit),
+			}})
+			return
+		}
+		json.NewEncoder(w).Encode(api.ProbeResponse{
+			Choices: []api.ChatChoice{{FinishReason: "stop"}},
+			Usage:   &api.UsageInfo{PromptTokens: 5, CompletionTokens: 1, TotalTokens: 6},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClaimVerifier_VerifyContextWindow(t *testing.T) {
+	const limitMessage = "This model's maximum context length is 16000 tokens"
+
+	tests := []struct {
+		name         string
+		claimed      int
+		message      string
+		wantStatus   string
+		wantObserved int
+	}{
+		{"confirmed", 16000, limitMessage, ClaimConfirmed, 16000},
+		{"overstated", 32000, limitMessage, ClaimOverstated, 16000},
+		{"understated", 8000, "context length exceeded", ClaimUnderstated, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := limitServer(t, 0, 16000, "", tt.message)
+			check := newTestClaimVerifier(server).VerifyContextWindow("test-model", tt.claimed)
+
+			if check.Status != tt.wantStatus {
+				t.Errorf("Status = %s (%s), want %s", check.Status, check.Detail, tt.wantStatus)
+			}
+			if tt.wantObserved > 0 && check.Observed != tt.wantObserved {
+				t.Errorf("Observed = %d, want %d", check.Observed, tt.wantObserved)
+			}
+			if len(check.Trials) != 2 {
+				t.Errorf("Trials = %d, want 2", len(check.Trials))
+			}
+		})
+	}
+}
+
+func TestClaimVerifier_VerifyMaxOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int
+		claimed    int
+		wantStatus string
+	}{
+		{"confirmed", 4096, 4096, ClaimConfirmed},
+		{"overstated", 4096, 8192, ClaimOverstated},
+		{"understated by reported limit", 8192, 4096, ClaimUnderstated},
+		{"not enforced", 0, 4096, ClaimUnderstated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := outputLimitServer(t, tt.limit)
+			check := newTestClaimVerifier(server).VerifyMaxOutput("test-model", tt.claimed)
+			if check.Status != tt.wantStatus {
+				t.Errorf("Status = %s (%s), want %s", check.Status, check.Detail, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestClaimVerifier_Verify(t *testing.T) {
+	server := outputLimitServer(t, 4096)
+	report, err := newTestClaimVerifier(server).Verify("test-model", "default", Claims{MaxOutput: 4096})
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(report.Checks) != 1 || report.Mismatch() {
+		t.Errorf("Checks = %+v, want one confirmed max_output check", report.Checks)
+	}
+	if _, ok := report.Check(ClaimContextWindow); ok {
+		t.Error("context window should not be checked without a claim")
+	}
+}
+
+func TestClaimVerifier_ServerErrorIsInconclusive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(server.Close)
+
+	check := newTestClaimVerifier(server).VerifyMaxOutput("test-model", 4096)
+	if check.Status != ClaimInconclusive {
+		t.Errorf("Status = %s (%s), want %s", check.Status, check.Detail, ClaimInconclusive)
+	}
+}
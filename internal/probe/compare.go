@@ -0,0 +1,144 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 比較対象の項目
+const (
+	CompareFieldContextWindow = "context_window"
+	CompareFieldMaxOutput     = "max_output"
+	CompareFieldLatency       = "avg_latency_ms"
+)
+
+// GatewayComparison は1つのゲートウェイでの探索結果の要約
+type GatewayComparison struct {
+	Gateway       string  `json:"gateway"`
+	URL           string  `json:"url,omitempty"`
+	ContextWindow int     `json:"context_window"`
+	MaxOutput     int     `json:"max_output"`
+	AvgLatencyMs  int64   `json:"avg_latency_ms"`
+	Report        *Report `json:"report,omitempty"`
+	Error         string  `json:"error,omitempty"`
+}
+
+// ComparisonDifference はゲートウェイ間で値が異なる項目
+type ComparisonDifference struct {
+	Field   string           `json:"field"`
+	Values  map[string]int64 `json:"values"`
+	Min     int64            `json:"min"`
+	Max     int64            `json:"max"`
+	Lowest  string           `json:"lowest_gateway"`
+	Highest string           `json:"highest_gateway"`
+}
+
+// ComparisonReport は複数ゲートウェイの比較結果
+type ComparisonReport struct {
+	SchemaVersion string                 `json:"schema_version"`
+	Model         string                 `json:"model"`
+	Gateways      []GatewayComparison    `json:"gateways"`
+	Differences   []ComparisonDifference `json:"differences"`
+	GeneratedAt   time.Time              `json:"generated_at"`
+}
+
+// NewGatewayComparison はレポートからゲートウェイごとの要約を作成する
+func NewGatewayComparison(gateway, url string, report *Report) GatewayComparison {
+	comparison := GatewayComparison{
+		Gateway: gateway,
+		URL:     url,
+		Report:  report,
+	}
+	if report == nil {
+		return comparison
+	}
+
+	var latencyTotal time.Duration
+	var latencyCount int64
+	var errs []string
+	for _, result := range report.Results {
+		for _, trial := range result.Trials {
+			if trial.Latency > 0 {
+				latencyTotal += trial.Latency
+				latencyCount++
+			}
+		}
+		// 失敗・中断した探索の途中の値をゲートウェイの上限として比較しないよう、エラーとして扱う
+		if !result.Success {
+			message := result.ErrorMessage
+			if message == "" {
+				message = "probe did not succeed"
+			}
+			errs = append(errs, fmt.Sprintf("%s: %s", result.Type, message))
+			continue
+		}
+		switch result.Type {
+		case ProbeTypeContextWindow:
+			comparison.ContextWindow = result.Value
+		case ProbeTypeMaxOutput:
+			comparison.MaxOutput = result.Value
+		}
+	}
+	comparison.Error = strings.Join(errs, "; ")
+	if latencyCount > 0 {
+		comparison.AvgLatencyMs = (latencyTotal / time.Duration(latencyCount)).Milliseconds()
+	}
+
+	return comparison
+}
+
+// NewComparisonReport はゲートウェイごとの結果を比較して差分を抽出する
+func NewComparisonReport(model string, gateways []GatewayComparison) *ComparisonReport {
+	report := &ComparisonReport{
+		SchemaVersion: ResultSchemaVersion,
+		Model:         model,
+		Gateways:      gateways,
+		Differences:   []ComparisonDifference{},
+		GeneratedAt:   time.Now(),
+	}
+
+	fields := []struct {
+		name  string
+		value func(GatewayComparison) int64
+	}{
+		{CompareFieldContextWindow, func(g GatewayComparison) int64 { return int64(g.ContextWindow) }},
+		{CompareFieldMaxOutput, func(g GatewayComparison) int64 { return int64(g.MaxOutput) }},
+		{CompareFieldLatency, func(g GatewayComparison) int64 { return g.AvgLatencyMs }},
+	}
+
+	for _, field := range fields {
+		diff := ComparisonDifference{
+			Field:  field.name,
+			Values: make(map[string]int64),
+		}
+
+		for _, gw := range gateways {
+			// エラーや未測定のゲートウェイは比較対象外
+			value := field.value(gw)
+			if gw.Error != "" || value <= 0 {
+				continue
+			}
+			diff.Values[gw.Gateway] = value
+			if diff.Lowest == "" || value < diff.Min {
+				diff.Min = value
+				diff.Lowest = gw.Gateway
+			}
+			if diff.Highest == "" || value > diff.Max {
+				diff.Max = value
+				diff.Highest = gw.Gateway
+			}
+		}
+
+		if len(diff.Values) >= 2 && diff.Min != diff.Max {
+			report.Differences = append(report.Differences, diff)
+		}
+	}
+
+	return report
+}
+
+// HasDifferences はゲートウェイ間で差異があるかを返す
+func (r *ComparisonReport) HasDifferences() bool {
+	return len(r.Differences) > 0
+}
@@ -0,0 +1,147 @@
+package probe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewGatewayComparison(t *testing.T) {
+	report := NewReport("gpt-4o", "prodA",
+		&Result{
+			Type:    ProbeTypeContextWindow,
+			Value:   128000,
+			Success: true,
+			Trials: []TrialInfo{
+				{Latency: 100 * time.Millisecond},
+				{Latency: 300 * time.Millisecond},
+			},
+		},
+		&Result{
+			Type:    ProbeTypeMaxOutput,
+			Value:   16384,
+			Success: true,
+			Trials:  []TrialInfo{{Latency: 200 * time.Millisecond}, {}},
+		},
+	)
+
+	got := NewGatewayComparison("prodA", "https://a.example.com", report)
+	if got.ContextWindow != 128000 {
+		t.Errorf("ContextWindow = %d, want 128000", got.ContextWindow)
+	}
+	if got.MaxOutput != 16384 {
+		t.Errorf("MaxOutput = %d, want 16384", got.MaxOutput)
+	}
+	// レイテンシ0の試行は平均から除外される
+	if got.AvgLatencyMs != 200 {
+		t.Errorf("AvgLatencyMs = %d, want 200", got.AvgLatencyMs)
+	}
+
+	// 失敗した探索の途中の値は比較せず、エラーとして表示する
+	failed := NewGatewayComparison("prodC", "", NewReport("gpt-4o", "prodC",
+		&Result{Type: ProbeTypeContextWindow, Value: 32000, ErrorMessage: "aborted after 3 trials"},
+		&Result{Type: ProbeTypeMaxOutput, Value: 16384, Success: true},
+	))
+	if failed.ContextWindow != 0 || failed.Error != "context_window: aborted after 3 trials" {
+		t.Errorf("failed probe = %+v, want no context window and an error", failed)
+	}
+	compared := NewComparisonReport("gpt-4o", []GatewayComparison{got, failed})
+	for _, diff := range compared.Differences {
+		if diff.Field == CompareFieldContextWindow {
+			t.Errorf("failed probe reported as a difference: %+v", diff)
+		}
+	}
+
+	empty := NewGatewayComparison("prodB", "", nil)
+	if empty.ContextWindow != 0 || empty.AvgLatencyMs != 0 {
+		t.Errorf("nil report should produce zero values, got %+v", empty)
+	}
+}
+
+func TestNewComparisonReport(t *testing.T) {
+	tests := []struct {
+		name       string
+		gateways   []GatewayComparison
+		wantFields []string
+	}{
+		{
+			name: "identical gateways",
+			gateways: []GatewayComparison{
+				{Gateway: "prodA", ContextWindow: 128000, MaxOutput: 16384, AvgLatencyMs: 100},
+				{Gateway: "prodB", ContextWindow: 128000, MaxOutput: 16384, AvgLatencyMs: 100},
+			},
+			wantFields: nil,
+		},
+		{
+			name: "context window clamped",
+			gateways: []GatewayComparison{
+				{Gateway: "prodA", ContextWindow: 128000, MaxOutput: 16384, AvgLatencyMs: 100},
+				{Gateway: "prodB", ContextWindow: 32000, MaxOutput: 16384, AvgLatencyMs: 100},
+			},
+			wantFields: []string{CompareFieldContextWindow},
+		},
+		{
+			name: "all fields differ",
+			gateways: []GatewayComparison{
+				{Gateway: "prodA", ContextWindow: 128000, MaxOutput: 16384, AvgLatencyMs: 100},
+				{Gateway: "prodB", ContextWindow: 32000, MaxOutput: 4096, AvgLatencyMs: 250},
+			},
+			wantFields: []string{CompareFieldContextWindow, CompareFieldMaxOutput, CompareFieldLatency},
+		},
+		{
+			name: "failed gateway is excluded",
+			gateways: []GatewayComparison{
+				{Gateway: "prodA", ContextWindow: 128000},
+				{Gateway: "prodB", ContextWindow: 32000, Error: "connection refused"},
+			},
+			wantFields: nil,
+		},
+		{
+			name: "unmeasured value is excluded",
+			gateways: []GatewayComparison{
+				{Gateway: "prodA", ContextWindow: 128000, MaxOutput: 16384},
+				{Gateway: "prodB", ContextWindow: 128000},
+			},
+			wantFields: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := NewComparisonReport("gpt-4o", tt.gateways)
+
+			if len(report.Differences) != len(tt.wantFields) {
+				t.Fatalf("Differences length = %d, want %d", len(report.Differences), len(tt.wantFields))
+			}
+			for i, field := range tt.wantFields {
+				if report.Differences[i].Field != field {
+					t.Errorf("Differences[%d].Field = %q, want %q", i, report.Differences[i].Field, field)
+				}
+			}
+			if report.HasDifferences() != (len(tt.wantFields) > 0) {
+				t.Errorf("HasDifferences() = %v", report.HasDifferences())
+			}
+		})
+	}
+}
+
+func TestNewComparisonReport_LowestHighest(t *testing.T) {
+	report := NewComparisonReport("gpt-4o", []GatewayComparison{
+		{Gateway: "prodA", ContextWindow: 128000},
+		{Gateway: "prodB", ContextWindow: 32000},
+		{Gateway: "staging", ContextWindow: 64000},
+	})
+
+	if len(report.Differences) != 1 {
+		t.Fatalf("Differences length = %d, want 1", len(report.Differences))
+	}
+	diff := report.Differences[0]
+	if diff.Lowest != "prodB" || diff.Min != 32000 {
+		t.Errorf("Lowest = %s (%d), want prodB (32000)", diff.Lowest, diff.Min)
+	}
+	if diff.Highest != "prodA" || diff.Max != 128000 {
+		t.Errorf("Highest = %s (%d), want prodA (128000)", diff.Highest, diff.Max)
+	}
+	if len(diff.Values) != 3 {
+		t.Errorf("Values length = %d, want 3", len(diff.Values))
+	}
+}
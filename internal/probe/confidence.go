@@ -0,0 +1,133 @@
+package probe
+
+import (
+	"fmt"
+	"math"
+)
+
+// 根拠の種類
+const (
+	EvidenceValidationError    = "validation_error"     // エラーメッセージから上限値を取得
+	EvidenceUsageConfirmed     = "usage_confirmed"      // usageで成功が確認されたトークン数
+	EvidenceFinishReasonLength = "finish_reason_length" // finish_reason=lengthで打ち切られたトークン数
+	EvidenceRejected           = "rejected"             // 上限値を含まないエラーで拒否されたトークン数
+	EvidenceUsageMismatch      = "usage_mismatch"       // usageがmax_tokensやfinish_reasonと矛盾している
+	EvidenceBodyTooLarge       = "body_too_large"       // トークン数ではなくリクエストボディのサイズで拒否されたトークン数
+)
+
+// 信頼度ラベルの閾値
+const (
+	highConfidenceThreshold   = 0.75
+	mediumConfidenceThreshold = 0.45
+)
+
+// Evidence は探索結果を裏付ける個々の根拠
+type Evidence struct {
+	Kind       string `json:"kind"`
+	TokenCount int    `json:"token_count"`
+	Detail     string `json:"detail,omitempty"`
+	Pattern    string `json:"pattern,omitempty"` // validation_errorで上限値を読み取ったパターン（例: anthropic/prompt_too_long）
+}
+
+// String は根拠を人間が読める形式で返す
+func (e Evidence) String() string {
+	switch e.Kind {
+	case EvidenceValidationError:
+		if e.Pattern != "" {
+			return fmt.Sprintf("validation_error match (limit %d, pattern %s)", e.TokenCount, e.Pattern)
+		}
+		return fmt.Sprintf("validation_error match (limit %d)", e.TokenCount)
+	case EvidenceUsageConfirmed:
+		return fmt.Sprintf("usage-confirmed success at %d", e.TokenCount)
+	case EvidenceFinishReasonLength:
+		return fmt.Sprintf("finish_reason=length at %d", e.TokenCount)
+	case EvidenceRejected:
+		return fmt.Sprintf("rejected at %d", e.TokenCount)
+	case EvidenceUsageMismatch:
+		return fmt.Sprintf("usage mismatch: %s", e.Detail)
+	case EvidenceBodyTooLarge:
+		return fmt.Sprintf("request body too large at %d", e.TokenCount)
+	}
+	return fmt.Sprintf("%s at %d", e.Kind, e.TokenCount)
+}
+
+// Confidence は数値スコア（0.0〜1.0）と根拠リストからなる信頼度
+type Confidence struct {
+	Score    float64    `json:"score"`
+	Evidence []Evidence `json:"evidence"`
+}
+
+// Level はスコアに対応するラベル（high/medium/low）を返す
+func (c Confidence) Level() string {
+	switch {
+	case c.Score >= highConfidenceThreshold:
+		return "high"
+	case c.Score >= mediumConfidenceThreshold:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// String はスコアとラベルを返す
+func (c Confidence) String() string {
+	return fmt.Sprintf("%.2f (%s)", c.Score, c.Level())
+}
+
+// CalculateConfidence は推定値と根拠リストから信頼度を計算する
+func (bs *BoundarySearcher) CalculateConfidence(value int, evidence []Evidence) Confidence {
+	confidence := Confidence{Evidence: evidence}
+	if confidence.Evidence == nil {
+		confidence.Evidence = []Evidence{}
+	}
+	if value <= 0 {
+		return confidence
+	}
+
+	score := 0.2
+	var validationMatch, confirmedNear, confirmedAny, boundedAbove, usageMismatch bool
+
+	for _, e := range evidence {
+		switch e.Kind {
+		case EvidenceValidationError:
+			if e.TokenCount == value {
+				validationMatch = true
+			}
+		case EvidenceUsageConfirmed:
+			confirmedAny = true
+			if float64(e.TokenCount) >= float64(value)*0.95 {
+				confirmedNear = true
+			}
+		case EvidenceFinishReasonLength, EvidenceRejected:
+			if e.TokenCount > value {
+				boundedAbove = true
+			}
+		case EvidenceUsageMismatch:
+			usageMismatch = true
+		case EvidenceBodyTooLarge:
+			// ボディのサイズで拒否された試行はトークン数の上限を押さえる根拠にならない
+		}
+	}
+
+	// ゲートウェイが上限値を明示した場合が最も信頼できる
+	if validationMatch {
+		score += 0.5
+	}
+	// 推定値付近での成功がusageで確認できている
+	if confirmedNear {
+		score += 0.25
+	} else if confirmedAny {
+		score += 0.1
+	}
+	// 推定値より上で失敗しており、境界が上からも押さえられている
+	if boundedAbove {
+		score += 0.15
+	}
+	// usageが信頼できないゲートウェイでは、usageに基づく根拠も割り引く
+	if usageMismatch {
+		score = math.Max(0, score-0.15)
+	}
+
+	confidence.Score = math.Min(1.0, math.Round(score*100)/100)
+	return confidence
+}
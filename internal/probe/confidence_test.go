@@ -0,0 +1,129 @@
+package probe
+
+import (
+	"testing"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+func TestBoundarySearcher_CalculateConfidence(t *testing.T) {
+	searcher := NewBoundarySearcher()
+
+	tests := []struct {
+		name      string
+		value     int
+		evidence  []Evidence
+		wantLevel string
+		wantScore float64
+	}{
+		{
+			name:      "no value",
+			value:     0,
+			evidence:  []Evidence{{Kind: EvidenceRejected, TokenCount: 4096}},
+			wantLevel: "low",
+			wantScore: 0,
+		},
+		{
+			name:      "validation error matches value",
+			value:     16384,
+			evidence:  []Evidence{{Kind: EvidenceValidationError, TokenCount: 16384}},
+			wantLevel: "medium",
+			wantScore: 0.7,
+		},
+		{
+			name:  "validation error with confirmed success near boundary",
+			value: 16384,
+			evidence: []Evidence{
+				{Kind: EvidenceUsageConfirmed, TokenCount: 16000},
+				{Kind: EvidenceValidationError, TokenCount: 16384},
+			},
+			wantLevel: "high",
+			wantScore: 0.95,
+		},
+		{
+			name:  "binary search bounded on both sides",
+			value: 128000,
+			evidence: []Evidence{
+				{Kind: EvidenceUsageConfirmed, TokenCount: 127900},
+				{Kind: EvidenceRejected, TokenCount: 129000},
+			},
+			wantLevel: "medium",
+			wantScore: 0.6,
+		},
+		{
+			name:      "only distant success",
+			value:     128000,
+			evidence:  []Evidence{{Kind: EvidenceUsageConfirmed, TokenCount: 4096}},
+			wantLevel: "low",
+			wantScore: 0.3,
+		},
+		{
+			name:  "all evidence",
+			value: 8192,
+			evidence: []Evidence{
+				{Kind: EvidenceValidationError, TokenCount: 8192},
+				{Kind: EvidenceUsageConfirmed, TokenCount: 8192},
+				{Kind: EvidenceFinishReasonLength, TokenCount: 9000},
+			},
+			wantLevel: "high",
+			wantScore: 1.0,
+		},
+		{
+			name:  "usage mismatch lowers score",
+			value: 16384,
+			evidence: []Evidence{
+				{Kind: EvidenceValidationError, TokenCount: 16384},
+				{Kind: EvidenceUsageMismatch, TokenCount: 20000, Detail: "completion_tokens 20000 exceeds requested max_tokens 16384"},
+			},
+			wantLevel: "medium",
+			wantScore: 0.55,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := searcher.CalculateConfidence(tt.value, tt.evidence)
+			if got.Score != tt.wantScore {
+				t.Errorf("Score = %v, want %v", got.Score, tt.wantScore)
+			}
+			if got.Level() != tt.wantLevel {
+				t.Errorf("Level() = %q, want %q", got.Level(), tt.wantLevel)
+			}
+			if len(got.Evidence) != len(tt.evidence) {
+				t.Errorf("Evidence length = %d, want %d", len(got.Evidence), len(tt.evidence))
+			}
+		})
+	}
+}
+
+func TestEvidenceFromResult(t *testing.T) {
+	withUsage := &api.ProbeResponse{Usage: &api.UsageInfo{PromptTokens: 4100}}
+
+	tests := []struct {
+		name     string
+		tokens   int
+		response *api.ProbeResponse
+		result   *BoundarySearchResult
+		wantKind string
+		wantOK   bool
+	}{
+		{"validation error", 8192, nil, &BoundarySearchResult{Value: 8000, Source: "validation_error"}, EvidenceValidationError, true},
+		{"truncated", 2048, withUsage, &BoundarySearchResult{Value: 2048, Source: "max_output_incomplete"}, EvidenceFinishReasonLength, true},
+		{"confirmed", 4096, withUsage, &BoundarySearchResult{Value: 4100, Success: true, Source: "success"}, EvidenceUsageConfirmed, true},
+		{"rejected", 8192, nil, &BoundarySearchResult{Source: "error", ErrorMessage: "timeout"}, EvidenceRejected, true},
+		{"success without usage", 4096, nil, &BoundarySearchResult{Success: true}, "", false},
+		{"nil result", 4096, nil, nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := evidenceFromResult(tt.tokens, tt.response, tt.result)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got.Kind != tt.wantKind {
+				t.Errorf("Kind = %q, want %q", got.Kind, tt.wantKind)
+			}
+		})
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/armaniacs/llm-info/internal/api"
-	"github.com/armaniacs/llm-info/pkg/config"
 )
 
 // ContextWindowProbe はコンテキストウィンドウを探索する
@@ -15,6 +14,8 @@ type ContextWindowProbe struct {
 	generator             *TestDataGenerator
 	searcher              *BoundarySearcher
 	lastComprehensionResult []bool  // test-all-positions用の一時的な保存領域
+	recorder              trialRecorder // 試行履歴
+	body                  bodyGuard     // リクエストボディのサイズ上限
 }
 
 // NewContextWindowProbe は新しいContextWindowProbeを作成する
@@ -35,6 +36,7 @@ func (p *ContextWindowProbe) SetVerboseLogger(verbose VerboseLogger) {
 func (p *ContextWindowProbe) Probe(model string, verbose bool) (*ContextWindowResult, error) {
 	// Reset comprehension results to prevent memory leak
 	p.lastComprehensionResult = p.lastComprehensionResult[:0]
+	p.recorder.reset()
 
 	startTime := time.Now()
 
@@ -51,11 +53,12 @@ func (p *ContextWindowProbe) Probe(model string, verbose bool) (*ContextWindowRe
 	if !upperLimit.Success {
 		return &ContextWindowResult{
 			MaxContextTokens: upperLimit.Value,
-			MethodConfidence: "low",
+			Confidence:       p.searcher.CalculateConfidence(0, p.recorder.evidenceList()),
 			Trials:           upperLimit.Trials,
 			Duration:        time.Since(startTime),
 			ErrorMessage:    upperLimit.ErrorMessage,
 			Success:          false,
+			TrialHistory:        p.recorder.history(),
 		}, nil
 	}
 
@@ -63,12 +66,13 @@ func (p *ContextWindowProbe) Probe(model string, verbose bool) (*ContextWindowRe
 	if tokenLimit, found := p.searcher.ExtractTokenLimitFromError(upperLimit.ErrorMessage); found {
 		return &ContextWindowResult{
 			MaxContextTokens: tokenLimit,
-			MethodConfidence: p.searcher.CalculateConfidence(upperLimit.Trials, upperLimit.Source, tokenLimit),
+			Confidence:       p.searcher.CalculateConfidence(tokenLimit, p.recorder.evidenceList()),
 			Trials:           upperLimit.Trials,
 			Duration:        time.Since(startTime),
 			ErrorMessage:    upperLimit.ErrorMessage,
 			Source:          "validation_error",
 			Success:         true,
+			TrialHistory:        p.recorder.history(),
 		}, nil
 	}
 
@@ -85,9 +89,11 @@ func (p *ContextWindowProbe) Probe(model string, verbose bool) (*ContextWindowRe
 	result := &ContextWindowResult{
 		Model:              model,
 		MaxContextTokens:    boundaryResult.Value,
-		MethodConfidence:    p.searcher.CalculateConfidence(boundaryResult.Trials, boundaryResult.Source, boundaryResult.Value),
+		Confidence:          p.searcher.CalculateConfidence(boundaryResult.Value, p.recorder.evidenceList()),
 		Trials:              upperLimit.Trials + boundaryResult.Trials + 1,
 		Duration:            time.Since(startTime),
+		Success:             true,
+		TrialHistory:        p.recorder.history(),
 	}
 
 	return result, nil
@@ -95,8 +101,15 @@ func (p *ContextWindowProbe) Probe(model string, verbose bool) (*ContextWindowRe
 
 // ProbeWithNeedle はneedle位置を指定してcontext windowを推定する
 func (p *ContextWindowProbe) ProbeWithNeedle(model string, position NeedlePosition, needleKeyword, needleAnswer string, _ bool) (*ContextWindowResult, error) {
+	return p.ProbeWithStrategy(model, SearchStrategy{}, position, needleKeyword, needleAnswer)
+}
+
+// ProbeWithStrategy は指定した探索戦略とneedle位置でcontext windowを推定する
+func (p *ContextWindowProbe) ProbeWithStrategy(model string, strategy Strategy, position NeedlePosition, needleKeyword, needleAnswer string) (*ContextWindowResult, error) {
 	// Reset comprehension results to prevent memory leak
 	p.lastComprehensionResult = p.lastComprehensionResult[:0]
+	p.recorder.reset()
+	p.body.reset(p.client.GetConfig().MaxBodySize)
 
 	startTime := time.Now()
 
@@ -108,68 +121,44 @@ func (p *ContextWindowProbe) ProbeWithNeedle(model string, position NeedlePositi
 		needleAnswer = "青色"
 	}
 
-	// 第1段階: 指数探索で上限を特定
-	upperLimit, err := p.searcher.ExponentialSearch(func(tokens int) (*BoundarySearchResult, error) {
-		return p.testWithNeedlePosition(model, tokens, position, needleKeyword, needleAnswer, false)
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("exponential search phase failed: %w", err)
+	env := &StrategyEnv{
+		Model:    model,
+		Searcher: p.searcher,
+		Trial: func(tokens int) (*BoundarySearchResult, error) {
+			return p.testWithNeedlePosition(model, tokens, position, needleKeyword, needleAnswer, false)
+		},
+		Validate: func(tokens, maxTokens int) *BoundarySearchResult {
+			return p.validateWithTokenCount(model, tokens, maxTokens)
+		},
 	}
 
-	// 上限が見つからなかった場合
-	if !upperLimit.Success {
-		return &ContextWindowResult{
-			Model:               model,
-			MaxContextTokens:    upperLimit.Value,
-			MethodConfidence:    "low",
-			Trials:              upperLimit.Trials,
-			Duration:            time.Since(startTime),
-			ErrorMessage:        upperLimit.ErrorMessage,
-			Success:             false,
-			NeedlePosition:      position,
-			NeedleKeyword:       needleKeyword,
-			NeedleAnswer:        needleAnswer,
-		}, nil
-	}
-
-	// 値がエラーメッセージから抽出された場合
-	if tokenLimit, found := p.searcher.ExtractTokenLimitFromError(upperLimit.ErrorMessage); found {
-		return &ContextWindowResult{
-			Model:               model,
-			MaxContextTokens:    tokenLimit,
-			MethodConfidence:    p.searcher.CalculateConfidence(upperLimit.Trials, upperLimit.Source, tokenLimit),
-			Trials:              upperLimit.Trials,
-			Duration:            time.Since(startTime),
-			ErrorMessage:        upperLimit.ErrorMessage,
-			Source:              "validation_error",
-			Success:             true,
-			NeedlePosition:      position,
-			NeedleKeyword:       needleKeyword,
-			NeedleAnswer:        needleAnswer,
-		}, nil
-	}
-
-	// 第2段階: 二分探索で境界を絞る
-	boundaryResult, err := p.searcher.Search(upperLimit.Value-1024, upperLimit.Value+1024, func(tokens int) (*BoundarySearchResult, error) {
-		return p.testWithNeedlePosition(model, tokens, position, needleKeyword, needleAnswer, false)
-	})
-
+	outcome, err := strategy.Search(env)
 	if err != nil {
-		return nil, fmt.Errorf("binary search phase failed: %w", err)
+		return nil, err
 	}
 
 	// 結果の整形
 	result := &ContextWindowResult{
-		Model:               model,
-		MaxContextTokens:    boundaryResult.Value,
-		MethodConfidence:    p.searcher.CalculateConfidence(boundaryResult.Trials, boundaryResult.Source, boundaryResult.Value),
-		Trials:              upperLimit.Trials + boundaryResult.Trials + 1,
-		Duration:            time.Since(startTime),
-		Success:             true,
-		NeedlePosition:      position,
-		NeedleKeyword:       needleKeyword,
-		NeedleAnswer:        needleAnswer,
+		Model:            model,
+		MaxContextTokens: outcome.Value,
+		Trials:           outcome.Trials,
+		Duration:         time.Since(startTime),
+		Success:          outcome.Success,
+		Source:           outcome.Source,
+		Strategy:         strategy.Name(),
+		NeedlePosition:   position,
+		NeedleKeyword:    needleKeyword,
+		NeedleAnswer:     needleAnswer,
+		TrialHistory:     p.recorder.history(),
+	}
+	if outcome.Success {
+		result.Confidence = p.searcher.CalculateConfidence(outcome.Value, p.recorder.evidenceList())
+	} else {
+		result.Confidence = p.searcher.CalculateConfidence(0, p.recorder.evidenceList())
+	}
+	// 失敗時とエラーメッセージから読み取った場合はメッセージを残す
+	if !outcome.Success || outcome.Source == "validation_error" {
+		result.ErrorMessage = outcome.ErrorMessage
 	}
 
 	return result, nil
@@ -179,6 +168,8 @@ func (p *ContextWindowProbe) ProbeWithNeedle(model string, position NeedlePositi
 func (p *ContextWindowProbe) ProbeAllNeedlePositions(model string, needleKeyword, needleAnswer string, _ bool) (*ContextWindowResult, error) {
 	// Reset comprehension results to prevent memory leak
 	p.lastComprehensionResult = p.lastComprehensionResult[:0]
+	p.recorder.reset()
+	p.body.reset(p.client.GetConfig().MaxBodySize)
 
 	startTime := time.Now()
 
@@ -259,7 +250,7 @@ func (p *ContextWindowProbe) ProbeAllNeedlePositions(model string, needleKeyword
 	return &ContextWindowResult{
 		Model:               model,
 		MaxContextTokens:    maxTokens,
-		MethodConfidence:    "medium",
+		Confidence:          p.searcher.CalculateConfidence(maxTokens, p.recorder.evidenceList()),
 		Trials:              len(needleTests) * 10, // 概算値
 		Duration:            time.Since(startTime),
 		Success:             maxTokens > 0,
@@ -269,38 +260,44 @@ func (p *ContextWindowProbe) ProbeAllNeedlePositions(model string, needleKeyword
 		NeedleAnswer:        needleAnswer,
 		NeedleComprehension: averageComprehension > 0,
 		NeedleTests:         needleTests,
+		TrialHistory:        p.recorder.history(),
 	}, nil
 }
 
 // testWithNeedlePosition はneedle位置を指定してテストを実行する
-func (p *ContextWindowProbe) testWithNeedlePosition(model string, tokens int, position NeedlePosition, needleKeyword, needleAnswer string, _ bool) (*BoundarySearchResult, error) {
-	// テストデータを生成
-	content, _ := p.generator.GenerateWithNeedlePosition(tokens, position)
-
-	// needleキーワードと回答を置換
-	content = strings.ReplaceAll(content, "【重要情報】ラッキーカラーは青色です", needleKeyword)
-	content = strings.ReplaceAll(content, "ラッキーカラーは何色でしたか？", strings.Split(needleKeyword, "は")[1]+"は何色でしたか？")
+func (p *ContextWindowProbe) testWithNeedlePosition(model string, tokens int, position NeedlePosition, needleKeyword, needleAnswer string, _ bool) (result *BoundarySearchResult, err error) {
+	// 試行履歴を記録
+	trialStart := time.Now()
+	throttleStart := p.client.Throttling()
+	var response *api.ProbeResponse
+	var latency time.Duration
+	defer func() {
+		p.recorder.record(tokens, trialStart, latency, p.client.Throttling().Sub(throttleStart), response, result)
+	}()
+
+	// テストデータを作成（本文は送信しながら生成する）
+	question := strings.Split(needleKeyword, "は")[1] + "は何色でしたか？"
+	prompt := p.generator.NewPrompt(tokens, position, needleKeyword, question)
+
+	// ボディのサイズ上限を超えるプロンプトは送信しない
+	if p.body.exceeds(prompt.Size()) {
+		return p.body.skipped(prompt.Size()), nil
+	}
 
-	// APIクライアントを作成（既存のprobeクライアントを利用）
+	// 既存のprobeクライアントを再利用し、試行間で接続を共有する
 	cfg := p.client.GetConfig()
-
-	// 注: Configをコピーしてtimeoutのみ調整
-	adjustedCfg := config.NewAppConfig()
-	adjustedCfg.BaseURL = cfg.BaseURL
-	adjustedCfg.APIKey = cfg.APIKey
-	adjustedCfg.Timeout = cfg.Timeout
-
-	client := api.NewProbeClient(adjustedCfg)
+	client := p.client
 
 	// Log API request details if verbose logger is available
 	if p.searcher.verbose != nil {
-		p.searcher.verbose.LogAPIRequest("POST", cfg.BaseURL+"/v1/chat/completions", tokens, 0)
+		p.searcher.verbose.LogAPIRequest("POST", cfg.BaseURL+client.EndpointPath(model), tokens, 0)
 	}
 
 	// APIリクエストを送信
 	start := time.Now()
-	response, err := client.ProbeModelWithContent(model, content)
+	response, err = client.ProbeModelWithReader(model, prompt.Reader())
 	duration := time.Since(start)
+	latency = duration
 
 	// Log API response if verbose logger is available
 	if p.searcher.verbose != nil {
@@ -332,9 +329,16 @@ func (p *ContextWindowProbe) testWithNeedlePosition(model string, tokens int, po
 				Value:        tokenLimit,
 				Success:      false,
 				ErrorMessage: errorMessage,
+				Source:       "validation_error",
 			}, nil
 		}
 
+		// ボディのサイズ超過はトークン数の上限ではないため区別して記録する
+		if isBodyTooLarge(err, errorMessage) {
+			p.body.reject(prompt.Size())
+			return bodyTooLargeResult(fmt.Sprintf("API request failed: %v", err)), nil
+		}
+
 		return &BoundarySearchResult{
 			Success:      false,
 			ErrorMessage: fmt.Sprintf("API request failed: %v", err),
@@ -346,8 +350,8 @@ func (p *ContextWindowProbe) testWithNeedlePosition(model string, tokens int, po
 		// Comprehensionをチェック
 		var comprehension bool
 		if len(response.Choices) > 0 && response.Choices[0].Message.Content != "" {
-			result := CheckComprehension(response.Choices[0].Message.Content, needleAnswer)
-			comprehension = result.Correct
+			comprehensionResult := CheckComprehension(response.Choices[0].Message.Content, needleAnswer)
+			comprehension = comprehensionResult.Correct
 
 			// 結果を保存（test-all-positionsで使用）
 			p.lastComprehensionResult = append(p.lastComprehensionResult, comprehension)
@@ -368,30 +372,30 @@ func (p *ContextWindowProbe) testWithNeedlePosition(model string, tokens int, po
 }
 
 // testWithTokenCount は指定されたトークン数でテストを実行する
-func (p *ContextWindowProbe) testWithTokenCount(model string, tokens int, _ bool) (*BoundarySearchResult, error) {
-	// テストデータを生成
-	_, _ = p.generator.GenerateData(tokens)
-
-	// APIクライアントを作成（既存のprobeクライアントを利用）
+func (p *ContextWindowProbe) testWithTokenCount(model string, tokens int, _ bool) (result *BoundarySearchResult, err error) {
+	// 試行履歴を記録
+	trialStart := time.Now()
+	throttleStart := p.client.Throttling()
+	var response *api.ProbeResponse
+	var latency time.Duration
+	defer func() {
+		p.recorder.record(tokens, trialStart, latency, p.client.Throttling().Sub(throttleStart), response, result)
+	}()
+
+	// 既存のprobeクライアントを再利用し、試行間で接続を共有する
 	cfg := p.client.GetConfig()
-
-	// 注: Configをコピーしてtimeoutのみ調整
-	adjustedCfg := config.NewAppConfig()
-	adjustedCfg.BaseURL = cfg.BaseURL
-	adjustedCfg.APIKey = cfg.APIKey
-	adjustedCfg.Timeout = cfg.Timeout
-
-	client := api.NewProbeClient(adjustedCfg)
+	client := p.client
 
 	// Log API request details if verbose logger is available
 	if p.searcher.verbose != nil {
-		p.searcher.verbose.LogAPIRequest("POST", cfg.BaseURL+"/v1/chat/completions", tokens, 0)
+		p.searcher.verbose.LogAPIRequest("POST", cfg.BaseURL+client.EndpointPath(model), tokens, 0)
 	}
 
 	// APIリクエストを送信
 	start := time.Now()
-	response, err := client.ProbeModel(model)
+	response, err = client.ProbeModel(model)
 	duration := time.Since(start)
+	latency = duration
 	// Log API response if verbose logger is available
 	if p.searcher.verbose != nil {
 		status := 200 // Default to success status
@@ -475,25 +479,37 @@ func (p *ContextWindowProbe) testWithTokenCount(model string, tokens int, _ bool
 
 // TrialInfo は試行情報
 type TrialInfo struct {
-	TokenCount int
-	Success    bool
-	Message    string
-	Usage      *api.UsageInfo // API使用量情報
+	TokenCount int            `json:"token_count"`
+	Success    bool           `json:"success"`
+	Message    string         `json:"message,omitempty"`
+	Usage      *api.UsageInfo `json:"usage,omitempty"`           // API使用量情報
+	StartedAt  time.Time      `json:"started_at"`                // 試行開始時刻
+	EndedAt    time.Time      `json:"ended_at"`                  // 試行終了時刻
+	Latency    time.Duration  `json:"latency"`                   // HTTPリクエストのレイテンシ（レート制限と再送で待った時間を除く）
+	Wait       time.Duration  `json:"rate_limit_wait,omitempty"` // max_concurrency、rate_limitの空きを待った時間
+	Throttled  bool           `json:"throttled,omitempty"`       // ゲートウェイが429を返した
+	Retries    int            `json:"retries,omitempty"`         // 429を返されて再送した回数（max_retries）
+	Backoff    time.Duration  `json:"backoff,omitempty"`         // 再送までに待った時間の合計
 }
 
 // ContextWindowResult は探索結果を表す
 type ContextWindowResult struct {
 	Model             string
 	MaxContextTokens  int    // *実際の*最大コンテキストトークン数
-	MethodConfidence  string // high/medium/low
+	Confidence        Confidence // 信頼度スコアと根拠
 	Trials            int    // 試行した試行回数
 	Duration          time.Duration
 	MaxInputAtSuccess int    // 最後に成功した入力トークン数
 	Success           bool   // 成功フラグ
 	ErrorMessage      string // エラー情報（あれば）
 	Source            string // 情報ソース
+	Strategy          string // 探索戦略（ProbeWithStrategyで探索した場合）
 	TrialHistory      []TrialInfo // 試行履歴
 
+	// Multi-turn fields
+	TurnTokens      int // 複数ターンの探索で1往復に含めたトークン数（0なら単発の探索）
+	SingleShotLimit int // 比較用の保存済みの単発の探索結果（0なら未探索）
+
 	// Needle test fields
 	NeedlePosition      NeedlePosition // Needleの位置
 	NeedleKeyword       string         // 使用されたneedleキーワード
@@ -524,7 +540,7 @@ func (r *ContextWindowResult) String() string {
 			"Duration: %v\n",
 		r.Model,
 		r.MaxContextTokens,
-		r.MethodConfidence,
+		r.Confidence,
 		r.Trials,
 		r.Duration.Round(time.Second),
 	)
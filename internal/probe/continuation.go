@@ -0,0 +1,209 @@
+package probe
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+// DefaultContinuationMaxTokens は保存済みのmax output tokensがない場合に1回のリクエストで指定するmax_tokens
+const DefaultContinuationMaxTokens = 4096
+
+// DefaultMaxContinuations は最初の応答に続けて送る続きの依頼の既定の回数
+const DefaultMaxContinuations = 3
+
+// continuationPrompt は打ち切られた位置が分かるよう、1行に1つずつ数を数えさせるプロンプト
+const continuationPrompt = "Count upward from 1, writing one number per line and nothing else. Do not stop counting until you are told to."
+
+// continuationRequest は打ち切られた応答の続きを依頼するメッセージ
+const continuationRequest = "Continue counting exactly where you stopped, one number per line, without repeating any number."
+
+// 続きの依頼の探索結果
+const (
+	ContinuationCoherent     = "coherent"      // 続きの応答が打ち切られた位置から数え続けた
+	ContinuationIncoherent   = "incoherent"    // 続きの応答が最初からやり直したか、数が飛んだ
+	ContinuationRejected     = "rejected"      // 続きの依頼がエラーになった
+	ContinuationNotTruncated = "not_truncated" // 最初の応答がmax_tokensに達する前に終わった
+)
+
+// countLine は行頭の数を取り出す
+var countLine = regexp.MustCompile(`^\s*(\d+)`)
+
+// ContinuationSegment は続きの依頼の連鎖の1回の応答
+type ContinuationSegment struct {
+	CompletionTokens int    `json:"completion_tokens"`
+	FinishReason     string `json:"finish_reason"`
+	FirstNumber      int    `json:"first_number"` // 応答の最初の数（数がなければ0）
+	LastNumber       int    `json:"last_number"`  // 最後の完全な行の数（数がなければ0）
+	Coherent         bool   `json:"coherent"`     // 前の応答の続きから数えていた（最初の応答は常にtrue）
+	tail             string // 改行で終わっていない最後の行（打ち切られた数の一部）
+	second           int    // 応答の2番目の数（打ち切られた数の残りの桁から始めたかの確認に使う）
+}
+
+// ContinuationResult は長い出力を続きの依頼でつなげられるかの探索結果
+type ContinuationResult struct {
+	Model                 string                `json:"model"`
+	Gateway               string                `json:"gateway,omitempty"`
+	Endpoint              string                `json:"endpoint"`
+	MaxTokens             int                   `json:"max_tokens"`        // 1回のリクエストで指定したmax_tokens
+	MaxTokensSource       string                `json:"max_tokens_source"` // saved（保存済みの探索結果）、flag、default
+	Segments              []ContinuationSegment `json:"segments"`
+	Continuation          string                `json:"continuation"` // coherent、incoherent、rejected、not_truncated
+	ContinuationError     string                `json:"continuation_error,omitempty"`
+	EffectiveOutputTokens int                   `json:"effective_output_tokens"` // 続きから数え続けた応答までのcompletion_tokensの合計
+	Supported             bool                  `json:"supported"`               // 少なくとも1回、続きの依頼で数え続けた
+	ProbedAt              time.Time             `json:"probed_at"`
+	Error                 string                `json:"error,omitempty"` // 探索自体が失敗した場合のエラー（--continue-on-error）
+}
+
+// ContinuationProbe はmax_tokensに近い長い出力を依頼し、finish_reason=lengthで打ち切られた応答を
+// 続きの依頼で一貫してつなげられるかを調べる
+type ContinuationProbe struct {
+	client           *api.ProbeClient
+	maxContinuations int
+}
+
+// NewContinuationProbe は新しいContinuationProbeを作成する
+func NewContinuationProbe(client *api.ProbeClient, maxContinuations int) *ContinuationProbe {
+	if maxContinuations <= 0 {
+		maxContinuations = DefaultMaxContinuations
+	}
+	return &ContinuationProbe{
+		client:           client,
+		maxContinuations: maxContinuations,
+	}
+}
+
+// Probe は1行に1つずつ数を数えさせ、打ち切られるたびに応答を会話履歴に加えて続きを依頼する
+// 続きの応答が直前の最後の数の次から始まっていれば一貫しているとみなし、その応答までの出力の合計を実効的な最大出力とする
+func (p *ContinuationProbe) Probe(model, gateway string, maxTokens int) (*ContinuationResult, error) {
+	if maxTokens <= 0 {
+		maxTokens = DefaultContinuationMaxTokens
+	}
+	result := &ContinuationResult{
+		Model:     model,
+		Gateway:   gateway,
+		MaxTokens: maxTokens,
+		ProbedAt:  time.Now(),
+	}
+
+	messages := []api.Message{{Role: "user", Content: continuationPrompt}}
+	response, err := p.client.ProbeMessages(model, messages, maxTokens)
+	if err != nil {
+		return nil, fmt.Errorf("request with max_tokens=%d failed: %w", maxTokens, err)
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("the gateway returned no choices")
+	}
+	result.Endpoint = p.client.Endpoint(model)
+	segment := result.addSegment(response)
+	if segment.FinishReason != "length" {
+		result.Continuation = ContinuationNotTruncated
+		return result, nil
+	}
+
+	for i := 0; i < p.maxContinuations; i++ {
+		messages = append(messages,
+			api.Message{Role: "assistant", Content: response.Choices[0].Message.Content},
+			api.Message{Role: "user", Content: continuationRequest},
+		)
+		response, err = p.client.ProbeMessages(model, messages, maxTokens)
+		if err == nil && len(response.Choices) == 0 {
+			err = fmt.Errorf("the gateway returned no choices")
+		}
+		if err != nil {
+			// 最初の続きの依頼が拒否された場合は続きの依頼に対応していない
+			if i == 0 {
+				result.Continuation = ContinuationRejected
+				result.ContinuationError = err.Error()
+			}
+			break
+		}
+
+		previous := result.Segments[len(result.Segments)-1]
+		segment := result.addSegment(response)
+		if !continuesFrom(previous, *segment) {
+			if i == 0 {
+				result.Continuation = ContinuationIncoherent
+			}
+			break
+		}
+		segment.Coherent = true
+		result.Continuation = ContinuationCoherent
+		result.Supported = true
+		if segment.FinishReason != "length" {
+			break
+		}
+	}
+
+	for _, segment := range result.Segments {
+		if !segment.Coherent {
+			break
+		}
+		result.EffectiveOutputTokens += segment.CompletionTokens
+	}
+	return result, nil
+}
+
+// addSegment はレスポンスの応答を連鎖に加える（最初の応答は一貫しているとみなす）
+func (r *ContinuationResult) addSegment(response *api.ProbeResponse) *ContinuationSegment {
+	choice := response.Choices[0]
+	segment := parseCountSegment(choice.Message.Content)
+	segment.FinishReason = choice.FinishReason
+	segment.Coherent = len(r.Segments) == 0
+	if response.Usage != nil {
+		segment.CompletionTokens = response.Usage.CompletionTokens
+	}
+	r.Segments = append(r.Segments, segment)
+	return &r.Segments[len(r.Segments)-1]
+}
+
+// parseCountSegment は応答の最初の数と最後の完全な行の数を取り出す
+func parseCountSegment(content string) ContinuationSegment {
+	var segment ContinuationSegment
+	lines := strings.Split(content, "\n")
+	// 改行で終わっていない最後の行は打ち切られた数の一部かもしれない
+	segment.tail = strings.TrimSpace(lines[len(lines)-1])
+	for i, line := range lines {
+		match := countLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		if segment.FirstNumber == 0 {
+			segment.FirstNumber = n
+		} else if segment.second == 0 {
+			segment.second = n
+		}
+		if i < len(lines)-1 {
+			segment.LastNumber = n
+		}
+	}
+	return segment
+}
+
+// continuesFrom はnextがpreviousの最後の完全な行の次の数から始まっているかを返す
+// 打ち切られた数を書き直す場合、その次から始める場合、打ち切られた数の残りの桁から始める場合を許容する
+func continuesFrom(previous, next ContinuationSegment) bool {
+	if previous.LastNumber == 0 || next.FirstNumber == 0 {
+		return false
+	}
+	expected := previous.LastNumber + 1
+	if next.FirstNumber == expected || next.FirstNumber == expected+1 {
+		return true
+	}
+	// 残りの桁から始めた場合は、数え直した場合と区別するため2番目の数も確かめる
+	if previous.tail != "" && next.second == expected+1 {
+		if n, err := strconv.Atoi(previous.tail + strconv.Itoa(next.FirstNumber)); err == nil && n == expected {
+			return true
+		}
+	}
+	return false
+}
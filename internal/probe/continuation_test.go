@@ -0,0 +1,111 @@
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// countingServer は1応答につきperResponse個の数を数え、totalに達するまではfinish_reason=lengthで打ち切るテストサーバー
+// restartがtrueの場合は続きの依頼でも1から数え直す
+func countingServer(t *testing.T, perResponse, total int, restart bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req api.ProbeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			return
+		}
+		start := 1
+		if !restart {
+			// 会話履歴のassistantの応答の数だけ進める
+			start += perResponse * (len(req.Messages) / 2)
+		}
+		var b strings.Builder
+		end := min(start+perResponse-1, total)
+		for n := start; n <= end; n++ {
+			fmt.Fprintf(&b, "%d\n", n)
+		}
+		finishReason := "stop"
+		if end < total {
+			finishReason = "length"
+			// 最後の数は途中で打ち切られる
+			fmt.Fprintf(&b, "%d", (end+1)/10)
+		}
+		json.NewEncoder(w).Encode(api.ProbeResponse{
+			Choices: []api.ChatChoice{{Message: api.ChatMessage{Role: "assistant", Content: b.String()}, FinishReason: finishReason}},
+			Usage:   &api.UsageInfo{PromptTokens: 20, CompletionTokens: perResponse},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestContinuationProbe_Probe(t *testing.T) {
+	tests := []struct {
+		name          string
+		total         int
+		restart       bool
+		want          string
+		wantSegments  int
+		wantEffective int
+	}{
+		{"coherent chain", 1000, false, ContinuationCoherent, 4, 400},
+		{"chain ends with stop", 250, false, ContinuationCoherent, 3, 300},
+		{"restarts from 1", 1000, true, ContinuationIncoherent, 2, 100},
+		{"not truncated", 50, false, ContinuationNotTruncated, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := countingServer(t, 100, tt.total, tt.restart)
+			p := NewContinuationProbe(api.NewProbeClient(&config.AppConfig{BaseURL: server.URL, APIKey: "test", Timeout: 5 * time.Second}), 3)
+
+			result, err := p.Probe("test-model", "production", 100)
+			if err != nil {
+				t.Fatalf("Probe() error = %v", err)
+			}
+			if result.Continuation != tt.want {
+				t.Errorf("Continuation = %s, want %s", result.Continuation, tt.want)
+			}
+			if len(result.Segments) != tt.wantSegments {
+				t.Errorf("len(Segments) = %d, want %d", len(result.Segments), tt.wantSegments)
+			}
+			if result.EffectiveOutputTokens != tt.wantEffective {
+				t.Errorf("EffectiveOutputTokens = %d, want %d", result.EffectiveOutputTokens, tt.wantEffective)
+			}
+			if result.Supported != (tt.want == ContinuationCoherent) {
+				t.Errorf("Supported = %v", result.Supported)
+			}
+		})
+	}
+}
+
+func TestContinuesFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous string
+		next     string
+		want     bool
+	}{
+		{"next number", "1\n2\n3\n", "4\n5\n", true},
+		{"rewrites the cut number", "1\n2\n3\n4", "4\n5\n", true},
+		{"skips the cut number", "1\n2\n3\n4", "5\n6\n", true},
+		{"completes the cut digits", "98\n99\n10", "0\n101\n", true},
+		{"starts over", "1\n2\n3\n", "1\n2\n", false},
+		{"no numbers", "1\n2\n3\n", "Sure, continuing.", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := continuesFrom(parseCountSegment(tt.previous), parseCountSegment(tt.next)); got != tt.want {
+				t.Errorf("continuesFrom() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,7 @@
 package probe
 
 import (
-	"fmt"
+	"io"
 	"strings"
 )
 
@@ -33,104 +33,204 @@ func NewTestDataGenerator() *TestDataGenerator {
 	}
 }
 
-// GenerateData は指定されたトークン数に合うテストデータを生成する
-func (g *TestDataGenerator) GenerateData(targetTokens int) (string, []string) {
-	// まず基本的なコンポーネント
-	preamble := "以下の内容を記憶してください。"
-	needle := "【重要情報】ラッキーカラーは青色です"
-	question := "ラッキーカラーは何色でしたか？"
-
-	// 本文を構築
-	var bodyBuilder strings.Builder
-
-	// サンプルテキストを繰り返して目標トに近づける
-	for len(bodyBuilder.String()) < targetTokens*3/4 { // 日本語なので1文字≈1トークンと仮定
-		for _, text := range g.sampleTexts {
-			if len(bodyBuilder.String())+len(text)+len(needle)+len(question) > targetTokens*3/4 {
-				break
-			}
-			bodyBuilder.WriteString(text)
-			bodyBuilder.WriteString(" ")
+const (
+	defaultPreamble = "以下の内容を記憶してください。"
+	defaultNeedle   = "【重要情報】ラッキーカラーは青色です"
+	defaultQuestion = "ラッキーカラーは何色でしたか？"
+
+	// promptSeparator はプロンプトの各部分の区切り
+	promptSeparator = "\n\n"
+)
+
+// Prompt はneedle付きの探索用プロンプトを表す
+// 本文は読み出すたびに生成するため、数百万トークンのプロンプトでも全体をメモリに保持しない
+type Prompt struct {
+	texts    []string
+	preamble string
+	needle   string
+	question string
+	units    int // 本文に含めるサンプルテキストの数
+	split    int // needleを挿入する位置（本文の先頭からのサンプルテキストの数）
+	position NeedlePosition
+}
+
+// NewPrompt は指定されたトークン数に合うプロンプトを作成する
+// 本文はサンプルテキスト単位で区切るため、マルチバイト文字の途中で分割されない
+func (g *TestDataGenerator) NewPrompt(targetTokens int, position NeedlePosition, needle, question string) *Prompt {
+	p := &Prompt{
+		texts:    g.sampleTexts,
+		preamble: defaultPreamble,
+		needle:   needle,
+		question: question,
+		position: position,
+	}
+
+	// 日本語なので1文字≈1トークンと仮定し、目標の3/4バイトまで本文を繰り返す
+	limit := targetTokens * 3 / 4
+	size := len(needle) + len(question)
+	for {
+		text := g.sampleTexts[p.units%len(g.sampleTexts)]
+		if size+len(text)+1 > limit {
+			break
 		}
+		size += len(text) + 1
+		p.units++
 	}
 
-	// 最終的なテキストを組み立て
-	result := fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s",
-		preamble,
-		bodyBuilder.String(),
-		needle,
-		question,
-	)
+	switch position {
+	case Middle:
+		p.split = p.units / 2
+	case Percent80:
+		p.split = p.units * 4 / 5
+	default:
+		p.split = p.units
+	}
 
-	return result, []string{preamble, bodyBuilder.String(), needle, question}
+	return p
 }
 
-// GenerateWithNeedlePosition はneedleの位置を指定してデータを生成する
-func (g *TestDataGenerator) GenerateWithNeedlePosition(targetTokens int, needlePosition NeedlePosition) (string, []string) {
-	preamble := "以下の内容を記憶してください。"
-	needle := "【重要情報】ラッキーカラーは青色です"
-	question := "ラッキーカラーは何色でしたか？"
+// parts はプロンプトを構成する部分を先頭から順に返す
+func (p *Prompt) parts() []promptPart {
+	if p.position == Middle || p.position == Percent80 {
+		return []promptPart{
+			{text: p.preamble}, {text: promptSeparator},
+			{from: 0, to: p.split, body: true}, {text: promptSeparator},
+			{text: p.needle}, {text: promptSeparator},
+			{from: p.split, to: p.units, body: true}, {text: promptSeparator},
+			{text: p.question},
+		}
+	}
+	return []promptPart{
+		{text: p.preamble}, {text: promptSeparator},
+		{from: 0, to: p.units, body: true}, {text: promptSeparator},
+		{text: p.needle}, {text: promptSeparator},
+		{text: p.question},
+	}
+}
+
+// Size はプロンプトのバイト数を返す
+func (p *Prompt) Size() int64 {
+	var size int64
+	for _, part := range p.parts() {
+		if !part.body {
+			size += int64(len(part.text))
+			continue
+		}
+		for i := part.from; i < part.to; i++ {
+			size += int64(len(p.texts[i%len(p.texts)]) + 1)
+		}
+	}
+	return size
+}
+
+// Reader はプロンプトを先頭から読み出すio.Readerを返す
+func (p *Prompt) Reader() io.Reader {
+	return &promptReader{prompt: p, parts: p.parts(), part: -1}
+}
+
+// String はプロンプト全体を文字列で返す
+// 全体をメモリに展開するため、小さなプロンプトの確認やテストに使う
+func (p *Prompt) String() string {
+	var b strings.Builder
+	b.Grow(int(p.Size()))
+	io.Copy(&b, p.Reader())
+	return b.String()
+}
+
+// body は本文全体を文字列で返す
+func (p *Prompt) body() string {
+	var b strings.Builder
+	for i := 0; i < p.units; i++ {
+		b.WriteString(p.texts[i%len(p.texts)])
+		b.WriteString(" ")
+	}
+	return b.String()
+}
+
+// promptPart はプロンプトの一部分（固定文字列または本文の範囲）
+type promptPart struct {
+	text     string
+	from, to int
+	body     bool
+}
+
+// promptReader はプロンプトを少しずつ生成しながら読み出す
+type promptReader struct {
+	prompt *Prompt
+	parts  []promptPart
+	part   int    // 現在の部分
+	unit   int    // 本文の部分で次に出力するサンプルテキスト
+	cur    string // 出力途中の文字列
+	space  bool   // サンプルテキストの後の空白が未出力か
+}
 
-	bodyBuilder := strings.Builder{}
+// Replay はapi.Replayerを実装する（429で再送するときに同じプロンプトを先頭から生成し直す）
+func (r *promptReader) Replay() io.Reader {
+	return r.prompt.Reader()
+}
 
-	// 基本的な本文
-	for i := 0; i < len(g.sampleTexts); i++ {
-		bodyBuilder.WriteString(g.sampleTexts[i])
-		if bodyBuilder.Len() > targetTokens*3/4 {
+// Read はio.Readerを実装する
+func (r *promptReader) Read(b []byte) (int, error) {
+	n := 0
+	for n < len(b) {
+		if r.cur == "" && !r.advance() {
 			break
 		}
-		bodyBuilder.WriteString(" ")
+		copied := copy(b[n:], r.cur)
+		r.cur = r.cur[copied:]
+		n += copied
 	}
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
 
-	var fullText string
-
-	switch needlePosition {
-	case End:
-		fullText = fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s",
-			preamble,
-			bodyBuilder.String(),
-			needle,
-			question,
-		)
-	case Middle:
-		mid := bodyBuilder.Len() / 2
-		begin := bodyBuilder.String()[:mid]
-		end := bodyBuilder.String()[mid:]
-		fullText = fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s\n\n%s",
-			preamble,
-			begin,
-			needle,
-			end,
-			question,
-		)
-	case Percent80:
-		targetLen := targetTokens * 4 / 5
-		if bodyBuilder.Len() < targetLen {
-			// 本文が不足している場合は繰り返す
-			for bodyBuilder.Len() < targetLen {
-				for _, text := range g.sampleTexts {
-					bodyBuilder.WriteString(text)
-					if bodyBuilder.Len() >= targetLen {
-						break
-					}
-					bodyBuilder.WriteString(" ")
-				}
+// advance は次に出力する文字列に進む。すべて出力済みの場合はfalseを返す
+func (r *promptReader) advance() bool {
+	for {
+		if r.space {
+			r.space = false
+			r.cur = " "
+			return true
+		}
+		if r.part >= 0 && r.part < len(r.parts) {
+			part := r.parts[r.part]
+			if part.body && r.unit < part.to {
+				texts := r.prompt.texts
+				r.cur = texts[r.unit%len(texts)]
+				r.unit++
+				r.space = true
+				return true
 			}
 		}
 
-		pos := int(float64(bodyBuilder.Len()) * 0.8)
-		begin := bodyBuilder.String()[:pos]
-		end := bodyBuilder.String()[pos:]
-		fullText = fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s\n\n%s",
-			preamble,
-			begin,
-			needle,
-			end,
-			question,
-		)
+		r.part++
+		if r.part >= len(r.parts) {
+			return false
+		}
+		part := r.parts[r.part]
+		if part.body {
+			r.unit = part.from
+			continue
+		}
+		if part.text != "" {
+			r.cur = part.text
+			return true
+		}
 	}
+}
 
-	return fullText, []string{preamble, bodyBuilder.String(), needle, question}
+// GenerateData は指定されたトークン数に合うテストデータを生成する
+func (g *TestDataGenerator) GenerateData(targetTokens int) (string, []string) {
+	prompt := g.NewPrompt(targetTokens, End, defaultNeedle, defaultQuestion)
+	return prompt.String(), []string{defaultPreamble, prompt.body(), defaultNeedle, defaultQuestion}
+}
+
+// GenerateWithNeedlePosition はneedleの位置を指定してデータを生成する
+func (g *TestDataGenerator) GenerateWithNeedlePosition(targetTokens int, needlePosition NeedlePosition) (string, []string) {
+	prompt := g.NewPrompt(targetTokens, needlePosition, defaultNeedle, defaultQuestion)
+	return prompt.String(), []string{defaultPreamble, prompt.body(), defaultNeedle, defaultQuestion}
 }
 
 // NeedlePosition はneedle（重要情報）の埋め込み位置
@@ -0,0 +1,122 @@
+package probe
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+func TestPrompt_Reader(t *testing.T) {
+	g := NewTestDataGenerator()
+
+	tests := []struct {
+		name     string
+		position NeedlePosition
+		tokens   int
+	}{
+		{"end", End, 2000},
+		{"middle", Middle, 2000},
+		{"80pct", Percent80, 2000},
+		{"too small for body", End, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt := g.NewPrompt(tt.tokens, tt.position, "合言葉はみかんです", "合言葉は何でしたか？")
+
+			// 1バイトずつ読み出しても同じ内容になる
+			data, err := io.ReadAll(iotest.OneByteReader(prompt.Reader()))
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if string(data) != prompt.String() {
+				t.Error("Reader() and String() returned different content")
+			}
+			if int64(len(data)) != prompt.Size() {
+				t.Errorf("Size() = %d, read %d bytes", prompt.Size(), len(data))
+			}
+			if !utf8.Valid(data) {
+				t.Error("prompt is not valid UTF-8")
+			}
+			if !bytes.HasPrefix(data, []byte(defaultPreamble)) || !bytes.HasSuffix(data, []byte("合言葉は何でしたか？")) {
+				t.Errorf("unexpected prompt layout: %q...", data[:min(len(data), 80)])
+			}
+			if !bytes.Contains(data, []byte("合言葉はみかんです")) {
+				t.Error("needle not found in prompt")
+			}
+
+			// 429で再送するときは、読み出し済みのReaderから同じ内容を先頭から作り直す
+			replayer, ok := prompt.Reader().(api.Replayer)
+			if !ok {
+				t.Fatal("Reader() should implement api.Replayer")
+			}
+			if replayed, _ := io.ReadAll(replayer.Replay()); !bytes.Equal(replayed, data) {
+				t.Error("Replay() returned different content")
+			}
+		})
+	}
+}
+
+func TestPrompt_NeedlePosition(t *testing.T) {
+	g := NewTestDataGenerator()
+
+	tests := []struct {
+		position NeedlePosition
+		min, max float64
+	}{
+		{End, 0.95, 1.0},
+		{Middle, 0.4, 0.6},
+		{Percent80, 0.7, 0.9},
+	}
+
+	for _, tt := range tests {
+		content := g.NewPrompt(20000, tt.position, defaultNeedle, defaultQuestion).String()
+		ratio := float64(strings.Index(content, defaultNeedle)) / float64(len(content))
+		if ratio < tt.min || ratio > tt.max {
+			t.Errorf("%s: needle at %.2f of prompt, want between %.2f and %.2f", tt.position, ratio, tt.min, tt.max)
+		}
+	}
+}
+
+func TestPrompt_Size(t *testing.T) {
+	g := NewTestDataGenerator()
+	prompt := g.NewPrompt(1000000, End, defaultNeedle, defaultQuestion)
+
+	// 目標トークン数の3/4バイトを超えない範囲で本文を繰り返す
+	limit := int64(1000000 * 3 / 4)
+	if prompt.Size() > limit+int64(len(defaultPreamble)+3*len(promptSeparator)) || prompt.Size() < limit*9/10 {
+		t.Errorf("Size() = %d, want about %d", prompt.Size(), limit)
+	}
+}
+
+func TestGenerateData(t *testing.T) {
+	g := NewTestDataGenerator()
+	content, parts := g.GenerateData(1000)
+
+	if len(parts) != 4 {
+		t.Fatalf("GenerateData() returned %d parts, want 4", len(parts))
+	}
+	want := strings.Join(parts, promptSeparator)
+	if content != want {
+		t.Errorf("GenerateData() content does not match its parts")
+	}
+}
+
+func BenchmarkPrompt_Reader(b *testing.B) {
+	g := NewTestDataGenerator()
+	prompt := g.NewPrompt(1000000, Percent80, defaultNeedle, defaultQuestion)
+	buf := make([]byte, 32*1024)
+
+	b.ReportAllocs()
+	b.SetBytes(prompt.Size())
+	for i := 0; i < b.N; i++ {
+		if _, err := io.CopyBuffer(io.Discard, prompt.Reader(), buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
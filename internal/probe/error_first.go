@@ -0,0 +1,135 @@
+package probe
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+const (
+	// errorFirstMaxTokens はどのモデルのウィンドウよりも大きいmax_tokens
+	// 入力とmax_tokensの合計がウィンドウを超えると、多くのプロバイダーは上限をエラーで返す
+	errorFirstMaxTokens = 10000000
+	// errorFirstInputTokens はどのモデルのウィンドウよりも大きい入力トークン数
+	// 検証で拒否されるため課金されないが、受け付けられた場合は通常の探索に移る
+	errorFirstInputTokens = 4 * 1024 * 1024
+)
+
+// ErrorFirstStrategy は検証エラーを意図的に発生させてコンテキストウィンドウを読み取る
+// 1回目は小さな入力に巨大なmax_tokensを、2回目は巨大な入力にmax_tokens=1を指定する
+// どちらのエラーからも上限が読み取れなかった場合はFallbackで探索する
+type ErrorFirstStrategy struct {
+	Fallback Strategy
+}
+
+// Name は戦略の名前を返す
+func (ErrorFirstStrategy) Name() string { return StrategyErrorFirst }
+
+// Plan は探索の手順を返す
+func (s ErrorFirstStrategy) Plan() []string {
+	plan := []string{
+		"Error First: Read the limit from validation errors (1-2 calls, not billed when rejected)",
+		"  - Tiny input with max_tokens=10000000",
+		"  - Oversized input with max_tokens=1",
+	}
+	if s.Fallback != nil {
+		plan = append(plan, "Fallback ("+s.Fallback.Name()+"), only if neither error contains the limit:")
+		for _, step := range s.Fallback.Plan() {
+			plan = append(plan, "  - "+step)
+		}
+	}
+	return plan
+}
+
+// Search は探索を実行する
+func (s ErrorFirstStrategy) Search(env *StrategyEnv) (*BoundarySearchResult, error) {
+	if env.Searcher.verbose != nil {
+		env.Searcher.verbose.LogSearchStrategy("Error First", "Reading the limit from validation errors", map[string]any{
+			"max_tokens":   errorFirstMaxTokens,
+			"input_tokens": errorFirstInputTokens,
+		})
+	}
+
+	shortcuts := []struct{ tokens, maxTokens int }{
+		{1, errorFirstMaxTokens},
+		{errorFirstInputTokens, 1},
+	}
+	for i, shortcut := range shortcuts {
+		result := env.Validate(shortcut.tokens, shortcut.maxTokens)
+		if limit, ok := validationLimit(result); ok {
+			if env.Searcher.verbose != nil {
+				env.Searcher.verbose.LogCompletion("Error First", limit.Value, 0)
+			}
+			return limit.withTrials(i + 1), nil
+		}
+		if env.Searcher.verbose != nil {
+			env.Searcher.verbose.LogFailure(i+1, shortcut.tokens, "no limit in error: "+result.ErrorMessage)
+		}
+	}
+
+	if s.Fallback == nil {
+		return &BoundarySearchResult{ErrorMessage: "no limit found in validation errors", Trials: len(shortcuts)}, nil
+	}
+
+	// 上限が読み取れなかったので通常の探索に戻る
+	env.logInfo("No limit found in validation errors, falling back to " + s.Fallback.Name())
+	result, err := s.Fallback.Search(env)
+	if err != nil {
+		return nil, err
+	}
+	result.Trials += len(shortcuts)
+	return result, nil
+}
+
+// validateWithTokenCount は検証エラーを狙ったリクエストを1回送信し、エラーから上限を読み取る
+func (p *ContextWindowProbe) validateWithTokenCount(model string, tokens, maxTokens int) (result *BoundarySearchResult) {
+	trialStart := time.Now()
+	throttleStart := p.client.Throttling()
+	var response *api.ProbeResponse
+	var latency time.Duration
+	defer func() {
+		p.recorder.record(tokens, trialStart, latency, p.client.Throttling().Sub(throttleStart), response, result)
+	}()
+
+	var content io.Reader = strings.NewReader("test")
+	var size int64
+	if tokens > 1 {
+		prompt := p.generator.NewPrompt(tokens, End, defaultNeedle, defaultQuestion)
+		// ボディのサイズ上限を超えるプロンプトは送信しない
+		if size = prompt.Size(); p.body.exceeds(size) {
+			return p.body.skipped(size)
+		}
+		content = prompt.Reader()
+	}
+
+	if p.searcher.verbose != nil {
+		p.searcher.verbose.LogAPIRequest("POST", p.client.GetConfig().BaseURL+p.client.EndpointPath(model), tokens, 0)
+	}
+
+	response, err := p.client.ProbeModelWithMaxTokens(model, content, maxTokens)
+	latency = time.Since(trialStart)
+
+	if err == nil {
+		// 受け付けられた場合は上限の手がかりにならない
+		result = &BoundarySearchResult{Success: true, Source: "success", Trials: 1}
+		if response.Usage != nil {
+			result.Value = response.Usage.PromptTokens
+		}
+		return result
+	}
+
+	errorMessage := err.Error()
+	if response != nil && response.Error != nil {
+		errorMessage = response.Error.Message
+	}
+	if limit, found := p.searcher.ExtractTokenLimitFromError(errorMessage); found {
+		return &BoundarySearchResult{Value: limit, ErrorMessage: errorMessage, Source: "validation_error", Trials: 1}
+	}
+	if isBodyTooLarge(err, errorMessage) {
+		p.body.reject(size)
+		return bodyTooLargeResult(errorMessage)
+	}
+	return &BoundarySearchResult{ErrorMessage: errorMessage, Source: "error", Trials: 1}
+}
@@ -0,0 +1,55 @@
+package probe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestErrorFirstStrategy(t *testing.T) {
+	tests := []struct {
+		name                          string
+		inputLimit, combinedLimit     int
+		inputMessage, combinedMessage string
+		wantTrials                    int
+	}{
+		{
+			name:            "limit in max_tokens validation",
+			combinedLimit:   128000,
+			combinedMessage: "This model's maximum context length is 128000 tokens. However, you requested 10000001 tokens",
+			wantTrials:      1,
+		},
+		{
+			name:            "max_tokens error without limit, limit in input validation",
+			inputLimit:      128000,
+			combinedLimit:   200000,
+			inputMessage:    "This model's maximum context length is 128000 tokens",
+			combinedMessage: "max_tokens is too large: 10000000",
+			wantTrials:      2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := limitServer(t, tt.inputLimit, tt.combinedLimit, tt.inputMessage, tt.combinedMessage)
+			p := NewContextWindowProbe(api.NewProbeClient(&config.AppConfig{
+				BaseURL: server.URL,
+				APIKey:  "test",
+				Timeout: 10 * time.Second,
+			}))
+
+			result, err := p.ProbeWithStrategy("test-model", ErrorFirstStrategy{Fallback: SearchStrategy{}}, End, "", "")
+			if err != nil {
+				t.Fatalf("ProbeWithStrategy() error = %v", err)
+			}
+			if !result.Success || result.MaxContextTokens != 128000 || result.Source != "validation_error" {
+				t.Errorf("result = %d (%s, success=%v), want 128000 from validation_error", result.MaxContextTokens, result.Source, result.Success)
+			}
+			if result.Trials != tt.wantTrials || len(result.TrialHistory) != tt.wantTrials {
+				t.Errorf("Trials = %d, history = %d, want %d", result.Trials, len(result.TrialHistory), tt.wantTrials)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package probe
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// 上限値の種類
+const (
+	limitKindInput  = "input"  // コンテキスト長・入力トークン数の上限
+	limitKindOutput = "output" // max_tokens・出力トークン数の上限
+)
+
+// limitPattern はエラーメッセージから上限値を読み取るパターン
+type limitPattern struct {
+	name      string         // 根拠に記録する名前（プロバイダー名/パターン名）
+	kind      string         // limitKindInput or limitKindOutput
+	re        *regexp.Regexp // 最初のキャプチャグループが上限値
+	exclusive bool           // 上限値を含まない範囲（「〜未満」）として返すプロバイダー
+}
+
+// errorParser はプロバイダーごとのエラーメッセージのパターン
+type errorParser struct {
+	provider string
+	patterns []limitPattern
+}
+
+// newErrorParser はプロバイダー名を付けたパターンをまとめる
+func newErrorParser(provider string, patterns ...limitPattern) errorParser {
+	for i := range patterns {
+		patterns[i].name = provider + "/" + patterns[i].name
+	}
+	return errorParser{provider: provider, patterns: patterns}
+}
+
+// inputPattern は入力トークン数の上限を読み取るパターンを作成する
+func inputPattern(name, expr string) limitPattern {
+	return limitPattern{name: name, kind: limitKindInput, re: regexp.MustCompile(expr)}
+}
+
+// outputPattern は出力トークン数の上限を読み取るパターンを作成する
+func outputPattern(name, expr string) limitPattern {
+	return limitPattern{name: name, kind: limitKindOutput, re: regexp.MustCompile(expr)}
+}
+
+// errorParsers はエラーメッセージを解析するパーサーの一覧（先に一致したものを使う）
+// プロバイダー固有の形式を先に試し、どれにも一致しなければ汎用のパターンにフォールバックする
+var errorParsers = []errorParser{
+	newErrorParser("anthropic",
+		// prompt is too long: 215000 tokens > 200000 maximum
+		inputPattern("prompt_too_long", `(?i)prompt is too long: \d+ tokens > (\d+) maximum`),
+		// input length and `max_tokens` exceed context limit: 198000 + 8192 > 200000, decrease input length or `max_tokens` and try again
+		inputPattern("context_limit", `(?i)exceed context limit: \d+ \+ \d+ > (\d+)`),
+		// max_tokens: 100000 > 64000, which is the maximum allowed number of output tokens for claude-sonnet-4
+		outputPattern("max_tokens", `(?i)max_tokens: \d+ > (\d+), which is the maximum allowed number of output tokens`),
+	),
+	newErrorParser("google",
+		// The input token count (1200000) exceeds the maximum number of tokens allowed (1048576).
+		inputPattern("input_token_count", `(?i)input token count \(\d+\) exceeds the maximum number of tokens allowed \((\d+)\)`),
+		// Unable to submit request because the input token count is 1200000 but model only supports up to 1048575.
+		inputPattern("vertex_input_token_count", `(?i)input token count is \d+ but model only supports up to (\d+)`),
+		// Unable to submit request because it has a maxOutputTokens value of 100000 but the supported range is from 1 (inclusive) to 65537 (exclusive).
+		limitPattern{
+			name:      "max_output_tokens_range",
+			kind:      limitKindOutput,
+			re:        regexp.MustCompile(`(?i)maxOutputTokens value of \d+ but the supported range is from \d+ \(inclusive\) to (\d+) \(exclusive\)`),
+			exclusive: true,
+		},
+	),
+	newErrorParser("vllm",
+		// The decoder prompt (length 5000) is longer than the maximum model length of 4096.
+		inputPattern("max_model_len", `(?i)is longer than the maximum model length of (\d+)`),
+		// Input prompt (5000 tokens) is too long and exceeds limit of 4096
+		inputPattern("prompt_exceeds_limit", `(?i)input prompt \(\d+ tokens\) is too long and exceeds limit of (\d+)`),
+	),
+	newErrorParser("openai",
+		// This model's maximum context length is 8192 tokens. However, your messages resulted in 9000 tokens.
+		// （vLLMなどOpenAI互換のゲートウェイも同じ形式で返す）
+		inputPattern("context_length", `(?i)maximum context length is (\d+) tokens`),
+		// Responses API: Input tokens exceed the configured limit of 272000 tokens.
+		inputPattern("configured_limit", `(?i)exceed the configured limit of (\d+) tokens`),
+		// max_tokens is too large: 100000. This model supports at most 16384 completion tokens, whereas you provided 100000.
+		outputPattern("max_completion_tokens", `(?i)supports at most (\d+) completion tokens`),
+		// Responses API: Invalid 'max_output_tokens': integer above maximum value. Expected a value <= 16384, ...
+		outputPattern("max_output_tokens", `max_output_tokens'?: integer above maximum value\. Expected a value <= (\d+)`),
+	),
+	newErrorParser("generic",
+		inputPattern("request_resulted_in", `your request resulted in (\d+) tokens`),
+		inputPattern("prompt_tokens", `prompt tokens must be less than (\d+)`),
+		outputPattern("max_output_tokens_le", `max_output_tokens must be <= (\d+)`),
+		outputPattern("maximum_output_tokens", `maximum output tokens is (\d+)`),
+		outputPattern("max_output_tokens_should_be", `the value of max_output_tokens should be <= (\d+)`),
+		outputPattern("max_tokens_le", `max_tokens.*must be.*<= (\d+)`),
+	),
+}
+
+// matchLimit はエラーメッセージから指定した種類の上限値を読み取り、一致したパターンの名前とともに返す
+func matchLimit(errorMessage, kind string) (int, string, bool) {
+	for _, parser := range errorParsers {
+		for _, pattern := range parser.patterns {
+			if pattern.kind != kind {
+				continue
+			}
+			if value, ok := pattern.match(errorMessage); ok {
+				return value, pattern.name, true
+			}
+		}
+	}
+	return 0, "", false
+}
+
+// match はエラーメッセージから上限値を読み取る
+func (p limitPattern) match(errorMessage string) (int, bool) {
+	matches := p.re.FindStringSubmatch(errorMessage)
+	if len(matches) < 2 {
+		return 0, false
+	}
+	value, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
+	}
+	if p.exclusive {
+		value--
+	}
+	return value, true
+}
+
+// matchedPattern はエラーメッセージから上限値valueを読み取ったパターンの名前を返す
+// 入力・出力のどちらの上限かは呼び出し元で区別しないため、値が一致するパターンを探す
+func matchedPattern(errorMessage string, value int) string {
+	for _, kind := range []string{limitKindInput, limitKindOutput} {
+		if found, name, ok := matchLimit(errorMessage, kind); ok && found == value {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+package probe
+
+import "testing"
+
+// limitFixture はプロバイダーが実際に返すエラーメッセージと期待する上限値
+type limitFixture struct {
+	name    string
+	message string
+	kind    string
+	want    int
+	pattern string
+}
+
+var openAIFixtures = []limitFixture{
+	{"context length", "This model's maximum context length is 8192 tokens. However, your messages resulted in 9000 tokens. Please reduce the length of the messages.", limitKindInput, 8192, "openai/context_length"},
+	{"responses input", "Input tokens exceed the configured limit of 272000 tokens. Your messages resulted in 300000 tokens.", limitKindInput, 272000, "openai/configured_limit"},
+	{"completion tokens", "max_tokens is too large: 100000. This model supports at most 16384 completion tokens, whereas you provided 100000.", limitKindOutput, 16384, "openai/max_completion_tokens"},
+	{"responses output", "Invalid 'max_output_tokens': integer above maximum value. Expected a value <= 16384, but got 100000 instead.", limitKindOutput, 16384, "openai/max_output_tokens"},
+}
+
+var anthropicFixtures = []limitFixture{
+	{"prompt too long", "prompt is too long: 215000 tokens > 200000 maximum", limitKindInput, 200000, "anthropic/prompt_too_long"},
+	{"context limit", "input length and `max_tokens` exceed context limit: 198000 + 8192 > 200000, decrease input length or `max_tokens` and try again", limitKindInput, 200000, "anthropic/context_limit"},
+	{"max tokens", "max_tokens: 100000 > 64000, which is the maximum allowed number of output tokens for claude-sonnet-4-20250514", limitKindOutput, 64000, "anthropic/max_tokens"},
+}
+
+var googleFixtures = []limitFixture{
+	{"gemini input", "The input token count (1200000) exceeds the maximum number of tokens allowed (1048576).", limitKindInput, 1048576, "google/input_token_count"},
+	{"vertex input", "Unable to submit request because the input token count is 1200000 but model only supports up to 1048575. Reduce the input token count and try again.", limitKindInput, 1048575, "google/vertex_input_token_count"},
+	// 上限値を含まない範囲で返すため1を引く
+	{"max output range", "Unable to submit request because it has a maxOutputTokens value of 100000 but the supported range is from 1 (inclusive) to 65537 (exclusive). Update the value and try again.", limitKindOutput, 65536, "google/max_output_tokens_range"},
+}
+
+var vllmFixtures = []limitFixture{
+	{"max model len", "The decoder prompt (length 5000) is longer than the maximum model length of 4096. Make sure that `max_model_len` is no smaller than the number of text tokens.", limitKindInput, 4096, "vllm/max_model_len"},
+	{"prompt exceeds limit", "Input prompt (5000 tokens) is too long and exceeds limit of 4096", limitKindInput, 4096, "vllm/prompt_exceeds_limit"},
+	// OpenAI互換の形式はopenaiのパターンで読み取る
+	{"openai compatible", "This model's maximum context length is 4096 tokens. However, you requested 5000 tokens (4000 in the messages, 1000 in the completion). Please reduce the length of the messages or completion.", limitKindInput, 4096, "openai/context_length"},
+}
+
+var genericFixtures = []limitFixture{
+	{"prompt tokens", "prompt tokens must be less than 32768", limitKindInput, 32768, "generic/prompt_tokens"},
+	{"max output tokens", "max_output_tokens must be <= 8192", limitKindOutput, 8192, "generic/max_output_tokens_le"},
+	{"maximum output tokens", "the maximum output tokens is 4096", limitKindOutput, 4096, "generic/maximum_output_tokens"},
+	{"max tokens", "max_tokens: value must be <= 32000", limitKindOutput, 32000, "generic/max_tokens_le"},
+}
+
+func TestMatchLimit(t *testing.T) {
+	providers := map[string][]limitFixture{
+		"openai":    openAIFixtures,
+		"anthropic": anthropicFixtures,
+		"google":    googleFixtures,
+		"vllm":      vllmFixtures,
+		"generic":   genericFixtures,
+	}
+
+	for provider, fixtures := range providers {
+		for _, tt := range fixtures {
+			t.Run(provider+"/"+tt.name, func(t *testing.T) {
+				value, pattern, found := matchLimit(tt.message, tt.kind)
+				if !found {
+					t.Fatalf("matchLimit(%q) found no limit", tt.message)
+				}
+				if value != tt.want || pattern != tt.pattern {
+					t.Errorf("matchLimit() = (%d, %s), want (%d, %s)", value, pattern, tt.want, tt.pattern)
+				}
+			})
+		}
+	}
+}
+
+func TestMatchLimit_NoMatch(t *testing.T) {
+	for _, message := range []string{
+		"Rate limit reached for gpt-4 on tokens per min. Limit: 10000, Used: 9000, Requested: 2000.",
+		"Invalid API key",
+		"",
+	} {
+		if value, pattern, found := matchLimit(message, limitKindInput); found {
+			t.Errorf("matchLimit(%q) = (%d, %s), want no match", message, value, pattern)
+		}
+	}
+
+	// 出力の上限のメッセージから入力の上限を読み取らない
+	if _, _, found := matchLimit(anthropicFixtures[2].message, limitKindInput); found {
+		t.Error("output limit message matched as input limit")
+	}
+}
+
+func TestEvidenceFromResult_Pattern(t *testing.T) {
+	message := anthropicFixtures[0].message
+	e, ok := evidenceFromResult(250000, nil, &BoundarySearchResult{Value: 200000, ErrorMessage: message, Source: "validation_error"})
+	if !ok || e.Kind != EvidenceValidationError {
+		t.Fatalf("evidenceFromResult() = %+v, %v", e, ok)
+	}
+	if e.Pattern != "anthropic/prompt_too_long" {
+		t.Errorf("Pattern = %q", e.Pattern)
+	}
+	if got := e.String(); got != "validation_error match (limit 200000, pattern anthropic/prompt_too_long)" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,135 @@
+package probe
+
+// contextTrialMaxTokens はコンテキストウィンドウの試行で指定するmax_tokens
+const contextTrialMaxTokens = 16
+
+// PlannedCall は見積もり上の1回のAPI呼び出し
+type PlannedCall struct {
+	InputTokens int  `json:"input_tokens"`
+	MaxTokens   int  `json:"max_tokens"`
+	Accepted    bool `json:"accepted"`
+}
+
+// CallEstimate は想定した上限に対して探索を模擬実行したときのAPI呼び出し
+// 実際の探索と同じ手順で試行する値を決めるため、APIを呼ばずに呼び出し回数とトークン数がわかる
+// 上限はエラーメッセージで示されないものとする（示される場合は呼び出しが減る）
+type CallEstimate struct {
+	Probe        string        `json:"probe"`         // ProbeTypeContextWindowなど
+	AssumedLimit int           `json:"assumed_limit"` // 模擬実行で想定した上限
+	Calls        []PlannedCall `json:"calls"`
+}
+
+// BilledTokens は受け付けられた呼び出しの入力トークン数と出力トークン数の合計を返す
+// 出力はmax_tokensまで生成されるものとする。拒否されたリクエストは多くのプロバイダーで課金されない
+func (e *CallEstimate) BilledTokens() (input, output int) {
+	for _, call := range e.Calls {
+		if call.Accepted {
+			input += call.InputTokens
+			output += call.MaxTokens
+		}
+	}
+	return input, output
+}
+
+// callSimulator は想定した上限に対する試行の結果を返し、呼び出しを記録する
+type callSimulator struct {
+	calls []PlannedCall
+}
+
+func (s *callSimulator) call(inputTokens, maxTokens int, accepted bool) *BoundarySearchResult {
+	s.calls = append(s.calls, PlannedCall{InputTokens: inputTokens, MaxTokens: maxTokens, Accepted: accepted})
+	if accepted {
+		return &BoundarySearchResult{Value: inputTokens, Success: true, Source: "success", Trials: 1}
+	}
+	return &BoundarySearchResult{Value: inputTokens, ErrorMessage: "rejected", Trials: 1}
+}
+
+// newEstimateSearcher は待機なしで試行するBoundarySearcherを作成する
+func newEstimateSearcher() *BoundarySearcher {
+	searcher := NewBoundarySearcher()
+	searcher.delay = 0
+	return searcher
+}
+
+// EstimateContextCalls はstrategyでコンテキストウィンドウを探索したときの呼び出しを見積もる
+func EstimateContextCalls(strategy Strategy, assumedLimit int) (*CallEstimate, error) {
+	sim := &callSimulator{}
+	env := &StrategyEnv{
+		Searcher: newEstimateSearcher(),
+		Trial: func(tokens int) (*BoundarySearchResult, error) {
+			return sim.call(tokens, contextTrialMaxTokens, tokens <= assumedLimit), nil
+		},
+		Validate: func(tokens, maxTokens int) *BoundarySearchResult {
+			return sim.call(tokens, maxTokens, tokens+maxTokens <= assumedLimit)
+		},
+	}
+	if _, err := strategy.Search(env); err != nil {
+		return nil, err
+	}
+	return &CallEstimate{Probe: ProbeTypeContextWindow, AssumedLimit: assumedLimit, Calls: sim.calls}, nil
+}
+
+// EstimateAllPositionsCalls はProbeAllNeedlePositionsの呼び出しを見積もる（needle位置ごとに指数探索する）
+func EstimateAllPositionsCalls(assumedLimit int) *CallEstimate {
+	sim := &callSimulator{}
+	searcher := newEstimateSearcher()
+	for range []NeedlePosition{End, Middle, Percent80} {
+		searcher.ExponentialSearch(func(tokens int) (*BoundarySearchResult, error) {
+			return sim.call(tokens, contextTrialMaxTokens, tokens <= assumedLimit), nil
+		})
+	}
+	return &CallEstimate{Probe: ProbeTypeContextWindow, AssumedLimit: assumedLimit, Calls: sim.calls}
+}
+
+// EstimateMaxOutputCalls はProbeOutputTokensの呼び出しを見積もる
+func EstimateMaxOutputCalls(assumedLimit int) (*CallEstimate, error) {
+	sim := &callSimulator{}
+	searcher := newEstimateSearcher()
+	runner := func(maxTokens int) (*BoundarySearchResult, error) {
+		return sim.call(maxOutputInputTokens, maxTokens, maxTokens <= assumedLimit), nil
+	}
+
+	// ProbeOutputTokensと同じく、指数探索の後に二分探索で境界を絞る
+	upperLimit, err := searcher.ExponentialSearch(runner)
+	if err != nil {
+		return nil, err
+	}
+	if upperLimit.Success {
+		if _, err := searcher.Search(upperLimit.Value/2, upperLimit.Value, runner); err != nil {
+			return nil, err
+		}
+	}
+	return &CallEstimate{Probe: ProbeTypeMaxOutput, AssumedLimit: assumedLimit, Calls: sim.calls}, nil
+}
+
+// EstimateMaxInputCalls はProbeInputTokensの呼び出しを見積もる
+// assumedLimitは入力+出力の合計上限として扱う
+func EstimateMaxInputCalls(assumedLimit, outputReserve int) (*CallEstimate, error) {
+	if outputReserve <= 0 {
+		outputReserve = DefaultOutputReserve
+	}
+	sim := &callSimulator{}
+	searcher := newEstimateSearcher()
+
+	// ProbeInputTokensと同じく、max_tokensを最小にした探索と出力を予約した探索を行う
+	for _, maxTokens := range []int{inputProbeMaxTokens, outputReserve} {
+		runner := func(tokens int) (*BoundarySearchResult, error) {
+			return sim.call(tokens, maxTokens, tokens+maxTokens <= assumedLimit), nil
+		}
+		upperLimit, err := searcher.ExponentialSearch(runner)
+		if err != nil {
+			return nil, err
+		}
+		if !upperLimit.Success {
+			if maxTokens == inputProbeMaxTokens {
+				// 入力上限が見つからなければ合計上限の探索は行わない
+				break
+			}
+			continue
+		}
+		if _, err := searcher.Search(upperLimit.Value, upperLimit.Value*2, runner); err != nil {
+			return nil, err
+		}
+	}
+	return &CallEstimate{Probe: ProbeTypeMaxInput, AssumedLimit: assumedLimit, Calls: sim.calls}, nil
+}
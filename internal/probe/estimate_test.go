@@ -0,0 +1,112 @@
+package probe
+
+import "testing"
+
+func TestEstimateContextCalls_MatchesSearch(t *testing.T) {
+	// 見積もりの試行は実際の探索と同じ値になる
+	env, tried := limitEnv(100000, "")
+	env.Searcher.delay = 0
+	if _, err := (SearchStrategy{}).Search(env); err != nil {
+		t.Fatal(err)
+	}
+
+	estimate, err := EstimateContextCalls(SearchStrategy{}, 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(estimate.Calls) != len(*tried) {
+		t.Fatalf("estimated %d calls, search made %d", len(estimate.Calls), len(*tried))
+	}
+	for i, call := range estimate.Calls {
+		if call.InputTokens != (*tried)[i] {
+			t.Errorf("call %d: input %d, search tried %d", i, call.InputTokens, (*tried)[i])
+		}
+		if call.Accepted != (call.InputTokens <= 100000) {
+			t.Errorf("call %d: accepted = %v for %d tokens", i, call.Accepted, call.InputTokens)
+		}
+	}
+	if estimate.Probe != ProbeTypeContextWindow || estimate.AssumedLimit != 100000 {
+		t.Errorf("estimate = %+v", estimate)
+	}
+}
+
+func TestEstimateContextCalls_ErrorFirst(t *testing.T) {
+	search, err := EstimateContextCalls(SearchStrategy{}, 128000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errorFirst, err := EstimateContextCalls(ErrorFirstStrategy{Fallback: SearchStrategy{}}, 128000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 上限がエラーで示されない場合、検証の2回の後に通常の探索を行う
+	if len(errorFirst.Calls) != len(search.Calls)+2 {
+		t.Errorf("error-first calls = %d, want %d", len(errorFirst.Calls), len(search.Calls)+2)
+	}
+	for _, call := range errorFirst.Calls[:2] {
+		if call.Accepted {
+			t.Errorf("validation call %+v should be rejected", call)
+		}
+	}
+}
+
+func TestEstimateMaxOutputCalls(t *testing.T) {
+	estimate, err := EstimateMaxOutputCalls(16384)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(estimate.Calls) == 0 {
+		t.Fatal("no calls estimated")
+	}
+	for _, call := range estimate.Calls {
+		if call.InputTokens != maxOutputInputTokens {
+			t.Errorf("input tokens = %d, want %d", call.InputTokens, maxOutputInputTokens)
+		}
+		if call.Accepted != (call.MaxTokens <= 16384) {
+			t.Errorf("accepted = %v for max_tokens %d", call.Accepted, call.MaxTokens)
+		}
+	}
+
+	input, output := estimate.BilledTokens()
+	wantInput, wantOutput := 0, 0
+	for _, call := range estimate.Calls {
+		if call.Accepted {
+			wantInput += call.InputTokens
+			wantOutput += call.MaxTokens
+		}
+	}
+	if input != wantInput || output != wantOutput {
+		t.Errorf("BilledTokens() = %d, %d, want %d, %d", input, output, wantInput, wantOutput)
+	}
+}
+
+func TestEstimateMaxInputCalls(t *testing.T) {
+	estimate, err := EstimateMaxInputCalls(32768, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	phases := map[int]int{}
+	for _, call := range estimate.Calls {
+		phases[call.MaxTokens]++
+		if call.Accepted != (call.InputTokens+call.MaxTokens <= 32768) {
+			t.Errorf("accepted = %v for %+v", call.Accepted, call)
+		}
+	}
+	// 入力上限の探索と、出力を予約した合計上限の探索の両方を行う
+	if phases[inputProbeMaxTokens] == 0 || phases[DefaultOutputReserve] == 0 {
+		t.Errorf("calls per max_tokens = %v", phases)
+	}
+}
+
+func TestEstimateAllPositionsCalls(t *testing.T) {
+	single := EstimateAllPositionsCalls(65536)
+	searcher := newEstimateSearcher()
+	var calls int
+	searcher.ExponentialSearch(func(tokens int) (*BoundarySearchResult, error) {
+		calls++
+		return &BoundarySearchResult{Success: tokens <= 65536}, nil
+	})
+	if len(single.Calls) != 3*calls {
+		t.Errorf("calls = %d, want 3 x %d", len(single.Calls), calls)
+	}
+}
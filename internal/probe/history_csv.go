@@ -0,0 +1,73 @@
+package probe
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// historyErrorLimit はCSVに書き出すエラーメッセージの最大文字数
+const historyErrorLimit = 200
+
+// HistoryCSVHeader は試行履歴CSVの列
+var HistoryCSVHeader = []string{"probe", "index", "tokens", "success", "latency_ms", "started_at", "error"}
+
+// TrialHistory は1つの探索の試行履歴を表す
+type TrialHistory struct {
+	Probe  string // 探索の種類（ProbeTypeContextWindowなど）
+	Trials []TrialInfo
+}
+
+// WriteHistoryCSV は試行履歴を1試行1行のCSVとして書き出す
+// indexは探索ごとに1から数え、errorは失敗した試行のメッセージの先頭のみを書き出す
+func WriteHistoryCSV(w io.Writer, histories ...TrialHistory) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(HistoryCSVHeader); err != nil {
+		return err
+	}
+
+	for _, history := range histories {
+		for i, trial := range history.Trials {
+			startedAt := ""
+			if !trial.StartedAt.IsZero() {
+				startedAt = trial.StartedAt.UTC().Format(time.RFC3339Nano)
+			}
+			errorExcerpt := ""
+			if !trial.Success {
+				errorExcerpt = historyExcerpt(trial.Message)
+			}
+
+			record := []string{
+				history.Probe,
+				strconv.Itoa(i + 1),
+				strconv.Itoa(trial.TokenCount),
+				strconv.FormatBool(trial.Success),
+				strconv.FormatInt(trial.Latency.Milliseconds(), 10),
+				startedAt,
+				errorExcerpt,
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write trial history: %w", err)
+	}
+	return nil
+}
+
+// historyExcerpt はメッセージを1行にまとめ、長い場合は切り詰める
+func historyExcerpt(message string) string {
+	message = strings.Join(strings.Fields(message), " ")
+	runes := []rune(message)
+	if len(runes) <= historyErrorLimit {
+		return message
+	}
+	return string(runes[:historyErrorLimit-3]) + "..."
+}
@@ -0,0 +1,59 @@
+package probe
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteHistoryCSV(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	histories := []TrialHistory{
+		{Probe: ProbeTypeContextWindow, Trials: []TrialInfo{
+			{TokenCount: 4096, Success: true, Message: "success", StartedAt: start, Latency: 1500 * time.Millisecond},
+			{TokenCount: 8192, Success: false, Message: "context length\nexceeded, \"max\" 6000", Latency: 20 * time.Millisecond},
+		}},
+		{Probe: ProbeTypeMaxOutput, Trials: []TrialInfo{
+			{TokenCount: 1024, Success: false, Message: strings.Repeat("x", 300)},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHistoryCSV(&buf, histories...); err != nil {
+		t.Fatalf("WriteHistoryCSV() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("records = %d, want 4 (header + 3 trials)", len(records))
+	}
+	if strings.Join(records[0], ",") != strings.Join(HistoryCSVHeader, ",") {
+		t.Errorf("header = %v", records[0])
+	}
+
+	want := []string{"context_window", "1", "4096", "true", "1500", "2025-01-02T03:04:05Z", ""}
+	if strings.Join(records[1], "|") != strings.Join(want, "|") {
+		t.Errorf("first row = %v, want %v", records[1], want)
+	}
+
+	// 失敗した試行はメッセージを1行にまとめて書き出す
+	if records[2][6] != `context length exceeded, "max" 6000` {
+		t.Errorf("error = %q", records[2][6])
+	}
+	if records[2][5] != "" {
+		t.Errorf("started_at = %q, want empty for zero time", records[2][5])
+	}
+
+	// indexは探索ごとに1から数え、長いメッセージは切り詰める
+	if records[3][0] != "max_output" || records[3][1] != "1" {
+		t.Errorf("unexpected row: %v", records[3][:2])
+	}
+	if got := len([]rune(records[3][6])); got != historyErrorLimit {
+		t.Errorf("error length = %d, want %d", got, historyErrorLimit)
+	}
+}
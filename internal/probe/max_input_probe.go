@@ -0,0 +1,327 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+const (
+	// inputProbeMaxTokens は入力上限の探索で指定するmax_tokens（出力の予約分を最小にする）
+	inputProbeMaxTokens = 1
+
+	// DefaultOutputReserve は合計上限の探索で出力用に予約するmax_tokens
+	DefaultOutputReserve = 4096
+
+	// inputLimitTolerance は入力上限と合計上限を同じとみなす誤差（二分探索の精度の2回分）
+	inputLimitTolerance = 256
+)
+
+// MaxInputProbe は受け付けられる最大入力トークン数を、入力+出力の合計上限と区別して探索する
+// ゲートウェイによっては入力だけの上限と合計の上限を別々に課しているため、
+// max_tokensを最小にした探索と、出力を予約した探索の2回を行う
+type MaxInputProbe struct {
+	client    *api.ProbeClient
+	generator *TestDataGenerator
+	searcher  *BoundarySearcher
+	recorder  trialRecorder // 試行履歴
+	body      bodyGuard     // リクエストボディのサイズ上限
+
+	// 探索中にバリデーションエラーから得た上限（0なら未取得）
+	reportedLimit   int
+	reportedMessage string
+}
+
+// NewMaxInputProbe は新しいMaxInputProbeを作成する
+func NewMaxInputProbe(client *api.ProbeClient) *MaxInputProbe {
+	return &MaxInputProbe{
+		client:    client,
+		generator: NewTestDataGenerator(),
+		searcher:  NewBoundarySearcher(),
+	}
+}
+
+// SetVerboseLogger sets the verbose logger for real-time output
+func (p *MaxInputProbe) SetVerboseLogger(verbose VerboseLogger) {
+	p.searcher.SetVerboseLogger(verbose)
+}
+
+// ProbeInputTokens は指定されたモデルの最大入力トークン数と合計上限を推定する
+// outputReserveは合計上限の探索で指定するmax_tokens（0以下ならDefaultOutputReserve）
+func (p *MaxInputProbe) ProbeInputTokens(model string, outputReserve int) (*MaxInputResult, error) {
+	p.recorder.reset()
+	p.body.reset(p.client.GetConfig().MaxBodySize)
+	startTime := time.Now()
+
+	if outputReserve <= 0 {
+		outputReserve = DefaultOutputReserve
+	}
+
+	result := &MaxInputResult{
+		Model:         model,
+		OutputReserve: outputReserve,
+	}
+
+	// 第1段階: max_tokensを最小にして入力だけの上限を探索
+	if p.searcher.verbose != nil {
+		p.searcher.verbose.LogInfo(fmt.Sprintf("Phase 1: input limit (max_tokens=%d)", inputProbeMaxTokens))
+	}
+	input, err := p.searchInput(model, inputProbeMaxTokens)
+	if err != nil {
+		return nil, fmt.Errorf("input limit search failed: %w", err)
+	}
+	result.Trials += input.Trials
+	if !input.Success {
+		result.Confidence = p.searcher.CalculateConfidence(0, p.recorder.evidenceList())
+		result.Duration = time.Since(startTime)
+		result.ErrorMessage = input.ErrorMessage
+		result.TrialHistory = p.recorder.history()
+		return result, nil
+	}
+	result.MaxInputTokens = input.Value
+	result.InputSource = input.Source
+
+	// 第2段階: 出力を予約して入力+出力の合計上限を探索
+	if p.searcher.verbose != nil {
+		p.searcher.verbose.LogInfo(fmt.Sprintf("Phase 2: combined limit (max_tokens=%d)", outputReserve))
+	}
+	combined, err := p.searchInput(model, outputReserve)
+	if err != nil {
+		return nil, fmt.Errorf("combined limit search failed: %w", err)
+	}
+	result.Trials += combined.Trials
+	if combined.Success {
+		result.CombinedLimit = combined.Value + outputReserve
+		result.CombinedSource = combined.Source
+		// 出力を予約しても入力上限まで受け付けられた場合、合計上限には達していない
+		if combined.Value+inputLimitTolerance >= result.MaxInputTokens {
+			result.CombinedSource = "lower_bound"
+		}
+	} else {
+		result.ErrorMessage = combined.ErrorMessage
+	}
+
+	result.Confidence = p.searcher.CalculateConfidence(result.MaxInputTokens, p.recorder.evidenceList())
+	result.Duration = time.Since(startTime)
+	result.Success = true
+	result.TrialHistory = p.recorder.history()
+
+	return result, nil
+}
+
+// searchInput はmax_tokensを固定して受け付けられる最大入力トークン数を探索する
+// バリデーションエラーで上限が示された場合はその値を優先する
+func (p *MaxInputProbe) searchInput(model string, maxTokens int) (*BoundarySearchResult, error) {
+	p.reportedLimit = 0
+	p.reportedMessage = ""
+
+	runner := func(tokens int) (*BoundarySearchResult, error) {
+		return p.testWithInputTokens(model, tokens, maxTokens)
+	}
+
+	// 指数探索で上限を特定
+	upperLimit, err := p.searcher.ExponentialSearch(runner)
+	if err != nil {
+		return nil, fmt.Errorf("exponential search phase failed: %w", err)
+	}
+	if reported := p.reportedResult(upperLimit.Trials); reported != nil {
+		return reported, nil
+	}
+	if !upperLimit.Success {
+		return upperLimit, nil
+	}
+
+	// 二分探索で境界を絞る（指数探索では2倍の値で失敗している）
+	boundaryResult, err := p.searcher.Search(upperLimit.Value, upperLimit.Value*2, runner)
+	if err != nil {
+		return nil, fmt.Errorf("binary search phase failed: %w", err)
+	}
+	if reported := p.reportedResult(upperLimit.Trials + boundaryResult.Trials); reported != nil {
+		return reported, nil
+	}
+
+	boundaryResult.Trials += upperLimit.Trials
+	if boundaryResult.Success {
+		boundaryResult.Source = "success"
+	}
+	return boundaryResult, nil
+}
+
+// reportedResult はバリデーションエラーから得た上限を探索結果として返す（なければnil）
+func (p *MaxInputProbe) reportedResult(trials int) *BoundarySearchResult {
+	if p.reportedLimit <= 0 {
+		return nil
+	}
+	return &BoundarySearchResult{
+		Value:           p.reportedLimit,
+		Success:         true,
+		ErrorMessage:    p.reportedMessage,
+		Source:          "validation_error",
+		Trials:          trials,
+		EstimatedTokens: p.reportedLimit,
+	}
+}
+
+// testWithInputTokens は指定された入力トークン数とmax_tokensでテストを実行する
+func (p *MaxInputProbe) testWithInputTokens(model string, tokens, maxTokens int) (result *BoundarySearchResult, err error) {
+	// 試行履歴を記録
+	trialStart := time.Now()
+	throttleStart := p.client.Throttling()
+	var response *api.ProbeResponse
+	var latency time.Duration
+	defer func() {
+		p.recorder.record(tokens, trialStart, latency, p.client.Throttling().Sub(throttleStart), response, result)
+	}()
+
+	// テストデータを作成（本文は送信しながら生成する）
+	prompt := p.generator.NewPrompt(tokens, End, defaultNeedle, defaultQuestion)
+
+	// ボディのサイズ上限を超えるプロンプトは送信しない
+	if p.body.exceeds(prompt.Size()) {
+		return p.body.skipped(prompt.Size()), nil
+	}
+
+	// Log API request details if verbose logger is available
+	if p.searcher.verbose != nil {
+		p.searcher.verbose.LogAPIRequest("POST", p.client.GetConfig().BaseURL+p.client.EndpointPath(model), tokens, 0)
+	}
+
+	// APIリクエストを送信
+	start := time.Now()
+	response, err = p.client.ProbeModelWithMaxTokens(model, prompt.Reader(), maxTokens)
+	latency = time.Since(start)
+
+	// Log API response if verbose logger is available
+	if p.searcher.verbose != nil {
+		status := 200 // Default to success status
+		promptTokens := 0
+		completionTokens := 0
+		if response != nil {
+			if response.Error != nil {
+				status = 400
+			}
+			if response.Usage != nil {
+				promptTokens = response.Usage.PromptTokens
+				completionTokens = response.Usage.CompletionTokens
+			}
+		}
+		p.searcher.verbose.LogAPIResponse(status, promptTokens, completionTokens, latency)
+	}
+
+	if err != nil {
+		errorMessage := err.Error()
+		if response != nil && response.Error != nil {
+			errorMessage = response.Error.Message
+		}
+
+		// トークン制限エラーかチェック
+		if limit, found := p.inputLimitFromError(errorMessage, maxTokens); found {
+			// 入力上限と合計上限の両方に当たった場合は小さい方が有効な上限
+			if p.reportedLimit == 0 || limit < p.reportedLimit {
+				p.reportedLimit = limit
+				p.reportedMessage = errorMessage
+			}
+			return &BoundarySearchResult{
+				Value:           limit,
+				Success:         false,
+				ErrorMessage:    errorMessage,
+				Source:          "validation_error",
+				Trials:          1,
+				EstimatedTokens: limit,
+			}, nil
+		}
+
+		// ボディのサイズ超過はトークン数の上限ではないため区別して記録する
+		if isBodyTooLarge(err, errorMessage) {
+			p.body.reject(prompt.Size())
+			return bodyTooLargeResult(errorMessage), nil
+		}
+
+		return &BoundarySearchResult{
+			Success:      false,
+			ErrorMessage: errorMessage,
+			Source:       "error",
+			Trials:       1,
+		}, nil
+	}
+
+	if response.Usage == nil {
+		return &BoundarySearchResult{
+			Success:      false,
+			ErrorMessage: "Response missing usage information",
+			Source:       "api_error",
+			Trials:       1,
+		}, nil
+	}
+
+	return &BoundarySearchResult{
+		Value:           tokens,
+		Success:         true,
+		Source:          "success",
+		Trials:          1,
+		EstimatedTokens: response.Usage.PromptTokens,
+	}, nil
+}
+
+// inputLimitFromError はエラーメッセージから受け付けられる入力トークン数の上限を求める
+// コンテキスト長（入力+出力）の上限が示された場合は、予約したmax_tokensを差し引く
+func (p *MaxInputProbe) inputLimitFromError(errorMessage string, maxTokens int) (int, bool) {
+	limit, found := p.searcher.ExtractTokenLimitFromError(errorMessage)
+	if !found {
+		return 0, false
+	}
+	if strings.Contains(errorMessage, "context length") {
+		limit -= maxTokens
+	}
+	return limit, limit > 0
+}
+
+// MaxInputResult は最大入力トークン数の探索結果を表す
+type MaxInputResult struct {
+	Model          string
+	MaxInputTokens int        // max_tokensを最小にしたときに受け付けられた最大入力トークン数
+	CombinedLimit  int        // 入力+出力の合計上限（0なら不明）
+	OutputReserve  int        // 合計上限の探索で指定したmax_tokens
+	InputSource    string     // 入力上限の情報ソース
+	CombinedSource string     // 合計上限の情報ソース（"lower_bound"なら合計上限に達していない）
+	Confidence     Confidence // 信頼度スコアと根拠
+	Trials         int        // 試行回数
+	Duration       time.Duration
+	ErrorMessage   string      // エラー情報（あれば）
+	Success        bool        // 成功フラグ
+	TrialHistory   []TrialInfo // 試行履歴
+}
+
+// Independent は入力上限が合計上限とは別に課されているかを返す
+// 合計上限だけの場合、max_tokensを最小にした入力上限は合計上限とほぼ一致する
+func (r *MaxInputResult) Independent() bool {
+	if r.MaxInputTokens == 0 || r.CombinedLimit == 0 {
+		return false
+	}
+	return r.MaxInputTokens+inputLimitTolerance < r.CombinedLimit
+}
+
+// String は結果を文字列として返す
+func (r *MaxInputResult) String() string {
+	if !r.Success {
+		return fmt.Sprintf("Error probing: %s", r.ErrorMessage)
+	}
+
+	return fmt.Sprintf(
+		"Model: %s\n"+
+			"Max Input Tokens: %d\n"+
+			"Combined Limit: %d (max_tokens=%d)\n"+
+			"Method Confidence: %s\n"+
+			"Trials: %d\n"+
+			"Duration: %v\n",
+		r.Model,
+		r.MaxInputTokens,
+		r.CombinedLimit,
+		r.OutputReserve,
+		r.Confidence,
+		r.Trials,
+		r.Duration,
+	)
+}
@@ -0,0 +1,159 @@
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// limitServer は入力上限と合計上限を別々に課すゲートウェイを模したテストサーバー
+// プロンプトのトークン数はNewPromptの見積もり（1トークン≈3/4バイト）の逆算で数える
+func limitServer(t *testing.T, inputLimit, combinedLimit int, inputMessage, combinedMessage string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req api.ProbeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			return
+		}
+		promptTokens := len(req.Messages[0].Content) * 4 / 3
+
+		reject := func(message string) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(api.ProbeResponse{Error: &api.OpenAIError{Type: "invalid_request_error", Message: message}})
+		}
+		if inputLimit > 0 && promptTokens > inputLimit {
+			reject(inputMessage)
+			return
+		}
+		if promptTokens+req.MaxTokens > combinedLimit {
+			reject(combinedMessage)
+			return
+		}
+
+		json.NewEncoder(w).Encode(api.ProbeResponse{
+			Choices: []api.ChatChoice{{FinishReason: "stop"}},
+			Usage:   &api.UsageInfo{PromptTokens: promptTokens, CompletionTokens: 1, TotalTokens: promptTokens + 1},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestMaxInputProbe(server *httptest.Server) *MaxInputProbe {
+	return NewMaxInputProbe(api.NewProbeClient(&config.AppConfig{
+		BaseURL: server.URL,
+		APIKey:  "test",
+		Timeout: 5 * time.Second,
+	}))
+}
+
+func TestMaxInputProbe_ValidationErrors(t *testing.T) {
+	t.Run("independent input limit", func(t *testing.T) {
+		server := limitServer(t, 6000, 16000,
+			"prompt tokens must be less than 6000",
+			"This model's maximum context length is 16000 tokens")
+
+		result, err := newTestMaxInputProbe(server).ProbeInputTokens("test-model", 12000)
+		if err != nil {
+			t.Fatalf("ProbeInputTokens() error = %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("ProbeInputTokens() failed: %s", result.ErrorMessage)
+		}
+		if result.MaxInputTokens != 6000 || result.InputSource != "validation_error" {
+			t.Errorf("MaxInputTokens = %d (%s), want 6000 from validation_error", result.MaxInputTokens, result.InputSource)
+		}
+		if result.CombinedLimit != 16000 || result.CombinedSource != "validation_error" {
+			t.Errorf("CombinedLimit = %d (%s), want 16000 from validation_error", result.CombinedLimit, result.CombinedSource)
+		}
+		if !result.Independent() {
+			t.Error("Independent() = false, want true")
+		}
+	})
+
+	t.Run("output reserve below the gap", func(t *testing.T) {
+		server := limitServer(t, 6000, 16000,
+			"prompt tokens must be less than 6000",
+			"This model's maximum context length is 16000 tokens")
+
+		// 入力上限+予約分が合計上限に届かない場合は下限しか分からない
+		result, err := newTestMaxInputProbe(server).ProbeInputTokens("test-model", 8000)
+		if err != nil {
+			t.Fatalf("ProbeInputTokens() error = %v", err)
+		}
+		if result.CombinedLimit != 14000 || result.CombinedSource != "lower_bound" {
+			t.Errorf("CombinedLimit = %d (%s), want 14000 as lower_bound", result.CombinedLimit, result.CombinedSource)
+		}
+	})
+
+	t.Run("combined limit only", func(t *testing.T) {
+		message := fmt.Sprintf("This model's maximum context length is %d tokens", 16000)
+		server := limitServer(t, 0, 16000, "", message)
+
+		result, err := newTestMaxInputProbe(server).ProbeInputTokens("test-model", 4096)
+		if err != nil {
+			t.Fatalf("ProbeInputTokens() error = %v", err)
+		}
+		// max_tokens=1の予約分を差し引いた値が入力上限になる
+		if result.MaxInputTokens != 15999 {
+			t.Errorf("MaxInputTokens = %d, want 15999", result.MaxInputTokens)
+		}
+		if result.CombinedLimit != 16000 {
+			t.Errorf("CombinedLimit = %d, want 16000", result.CombinedLimit)
+		}
+		if result.Independent() {
+			t.Error("Independent() = true, want false")
+		}
+	})
+}
+
+func TestMaxInputProbe_BoundarySearch(t *testing.T) {
+	// 上限値を含まないエラーの場合は二分探索で境界を求める
+	server := limitServer(t, 6000, 100000, "input too long", "request too large")
+
+	result, err := newTestMaxInputProbe(server).ProbeInputTokens("test-model", 1<<20)
+	if err != nil {
+		t.Fatalf("ProbeInputTokens() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("ProbeInputTokens() failed: %s", result.ErrorMessage)
+	}
+	if result.MaxInputTokens < 6000-256 || result.MaxInputTokens > 6000 {
+		t.Errorf("MaxInputTokens = %d, want within 256 tokens below 6000", result.MaxInputTokens)
+	}
+	// 合計上限の探索はすべて拒否されるため不明のまま
+	if result.CombinedLimit != 0 || result.ErrorMessage == "" {
+		t.Errorf("CombinedLimit = %d, ErrorMessage = %q, want 0 and an error", result.CombinedLimit, result.ErrorMessage)
+	}
+}
+
+func TestMaxInputResult_ToResult(t *testing.T) {
+	input := &MaxInputResult{
+		Model:          "gpt-4o",
+		MaxInputTokens: 6000,
+		CombinedLimit:  16000,
+		OutputReserve:  4096,
+		InputSource:    "validation_error",
+		CombinedSource: "validation_error",
+		Success:        true,
+	}
+
+	result := input.ToResult("production")
+	if result.Type != ProbeTypeMaxInput || result.Value != 6000 {
+		t.Errorf("Type = %s, Value = %d, want %s and 6000", result.Type, result.Value, ProbeTypeMaxInput)
+	}
+	if result.Method != MethodErrorMessage {
+		t.Errorf("Method = %s, want %s", result.Method, MethodErrorMessage)
+	}
+	if result.InputLimit == nil || result.InputLimit.CombinedLimit != 16000 || !result.InputLimit.Independent {
+		t.Errorf("InputLimit = %+v, want combined 16000 and independent", result.InputLimit)
+	}
+}
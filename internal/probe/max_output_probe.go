@@ -2,18 +2,20 @@ package probe
 
 import (
 	"fmt"
-	"regexp"
 	"time"
 
 	"github.com/armaniacs/llm-info/internal/api"
-	"github.com/armaniacs/llm-info/pkg/config"
 )
 
+// maxOutputInputTokens はmax output tokens探索で送信する入力トークン数
+const maxOutputInputTokens = 1000
+
 // MaxOutputTokensProbe はmax output tokensを探索する
 type MaxOutputTokensProbe struct {
 	client    *api.ProbeClient
 	generator *TestDataGenerator
 	searcher  *BoundarySearcher
+	recorder  trialRecorder // 試行履歴
 }
 
 // NewMaxOutputTokensProbe は新しいMaxOutputTokensProbeを作成する
@@ -32,11 +34,12 @@ func (p *MaxOutputTokensProbe) SetVerboseLogger(verbose VerboseLogger) {
 
 // ProbeOutputTokens は指定されたモデルのmax output tokensを推定する
 func (p *MaxOutputTokensProbe) ProbeOutputTokens(model string, verbose bool) (*MaxOutputResult, error) {
+	p.recorder.reset()
 	startTime := time.Now()
 
 	// 十分な入力長を確保する（推定：context windowの50%）
 	// TODO: Context Window探索結果を利用する改善ポイント
-	inputTokens := 1000 // 一時的な固定値
+	inputTokens := maxOutputInputTokens // 一時的な固定値
 
 	// 第1段階: 指数探索で上限を特定
 	upperLimit, err := p.searcher.ExponentialSearch(func(tokens int) (*BoundarySearchResult, error) {
@@ -52,12 +55,14 @@ func (p *MaxOutputTokensProbe) ProbeOutputTokens(model string, verbose bool) (*M
 		return &MaxOutputResult{
 			Model:             model,
 			MaxOutputTokens:   upperLimit.Value,
-			MethodConfidence:  "low",
+			Confidence:        p.searcher.CalculateConfidence(0, p.recorder.evidenceList()),
 			Trials:            upperLimit.Trials,
 			Duration:          time.Since(startTime),
 			ErrorMessage:      upperLimit.ErrorMessage,
 			InputTokensUsed:   inputTokens,
 			Success:           false,
+			TrialHistory:      p.recorder.history(),
+			UsageDiscrepancies: p.recorder.usageDiscrepancies(),
 		}, nil
 	}
 
@@ -66,13 +71,15 @@ func (p *MaxOutputTokensProbe) ProbeOutputTokens(model string, verbose bool) (*M
 		return &MaxOutputResult{
 			Model:             model,
 			MaxOutputTokens:   tokenLimit,
-			MethodConfidence:  p.searcher.CalculateConfidence(upperLimit.Trials, "validation_error", tokenLimit),
+			Confidence:        p.searcher.CalculateConfidence(tokenLimit, p.recorder.evidenceList()),
 			Trials:            upperLimit.Trials,
 			Duration:          time.Since(startTime),
 			ErrorMessage:      upperLimit.ErrorMessage,
 			InputTokensUsed:   inputTokens,
 			Evidence:          "validation_error",
 			Success:           true,
+			TrialHistory:      p.recorder.history(),
+			UsageDiscrepancies: p.recorder.usageDiscrepancies(),
 		}, nil
 	}
 
@@ -89,37 +96,45 @@ func (p *MaxOutputTokensProbe) ProbeOutputTokens(model string, verbose bool) (*M
 	result := &MaxOutputResult{
 		Model:                 model,
 		MaxOutputTokens:       boundaryResult.Value,
-		MethodConfidence:      p.searcher.CalculateConfidence(boundaryResult.Trials, boundaryResult.Source, boundaryResult.Value),
+		Confidence:            p.searcher.CalculateConfidence(boundaryResult.Value, p.recorder.evidenceList()),
 		Trials:                upperLimit.Trials + boundaryResult.Trials + 1,
 		Duration:              time.Since(startTime),
 		InputTokensUsed:       inputTokens,
 		MaxSuccessfullyGenerated: boundaryResult.Value,
 		Evidence:              boundaryResult.Source,
+		Success:               true,
+		TrialHistory:          p.recorder.history(),
+		UsageDiscrepancies:    p.recorder.usageDiscrepancies(),
 	}
 
 	return result, nil
 }
 
 // testWithMaxTokens は指定されたmax tokensでテストを実行する
-func (p *MaxOutputTokensProbe) testWithMaxTokens(model string, inputTokens, maxTokens int, _ bool) (*BoundarySearchResult, error) {
-	// APIクライアント設定
+func (p *MaxOutputTokensProbe) testWithMaxTokens(model string, inputTokens, maxTokens int, _ bool) (result *BoundarySearchResult, err error) {
+	// 試行履歴を記録
+	trialStart := time.Now()
+	throttleStart := p.client.Throttling()
+	var response *api.ProbeResponse
+	var latency time.Duration
+	defer func() {
+		p.recorder.record(maxTokens, trialStart, latency, p.client.Throttling().Sub(throttleStart), response, result)
+	}()
+
+	// 既存のprobeクライアントを再利用し、試行間で接続を共有する
 	cfg := p.client.GetConfig()
-	adjustedCfg := config.NewAppConfig()
-	adjustedCfg.BaseURL = cfg.BaseURL
-	adjustedCfg.APIKey = cfg.APIKey
-	adjustedCfg.Timeout = cfg.Timeout
-
-	client := api.NewProbeClient(adjustedCfg)
+	client := p.client
 
 	// Log API request details if verbose logger is available
 	if p.searcher.verbose != nil {
-		p.searcher.verbose.LogAPIRequest("POST", cfg.BaseURL+"/v1/chat/completions", inputTokens, 0)
+		p.searcher.verbose.LogAPIRequest("POST", cfg.BaseURL+client.EndpointPath(model), inputTokens, 0)
 	}
 
 	// APIリクエストを送信
 	start := time.Now()
-	response, err := client.ProbeModel(model)
+	response, err = client.ProbeMessages(model, []api.Message{{Role: "user", Content: "test"}}, maxTokens)
 	duration := time.Since(start)
+	latency = duration
 
 	// Log API response if verbose logger is available
 	if p.searcher.verbose != nil {
@@ -190,6 +205,15 @@ func (p *MaxOutputTokensProbe) testWithMaxTokens(model string, inputTokens, maxT
 		}, nil
 	}
 
+	// usageがmax_tokensやfinish_reasonと矛盾していないか照合する
+	discrepancies := reconcileUsage(maxTokens, response)
+	p.recorder.addEvidence(discrepancies...)
+	if p.searcher.verbose != nil {
+		for _, d := range discrepancies {
+			p.searcher.verbose.LogInfo("Usage mismatch: " + d.Detail)
+		}
+	}
+
 	// incomplete statusをチェック
 	if len(response.Choices) > 0 {
 		choice := response.Choices[0]
@@ -228,25 +252,13 @@ func (p *MaxOutputTokensProbe) testWithMaxTokens(model string, inputTokens, maxT
 
 // extractMaxTokensFromError はエラーメッセージからmax_output_tokens制限を抽出する
 func (p *MaxOutputTokensProbe) extractMaxTokensFromError(errorMessage string) (int, bool) {
-	patterns := []string{
-		`max_output_tokens must be <= (\d+)`,
-		`maximum output tokens is (\d+)`,
-		`the value of max_output_tokens should be <= (\d+)`,
-		`max_tokens.*must be.*<= (\d+)`,
-	}
+	return extractOutputLimitFromError(errorMessage)
+}
 
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindStringSubmatch(errorMessage)
-		if len(matches) > 1 {
-			var result int
-			_, err := fmt.Sscanf(matches[1], "%d", &result)
-			if err == nil {
-				return result, true
-			}
-		}
-	}
-	return 0, false
+// extractOutputLimitFromError はエラーメッセージからmax_output_tokens制限を抽出する
+func extractOutputLimitFromError(errorMessage string) (int, bool) {
+	value, _, found := matchLimit(errorMessage, limitKindOutput)
+	return value, found
 }
 
 // generateLongPrompt は指定されたトークン数に合わせて長いプロンプトを生成する
@@ -267,7 +279,7 @@ func generateLongPrompt(targetTokens int) string {
 type MaxOutputResult struct {
 	Model                   string
 	MaxOutputTokens         int    // 推定された最大出力トークン数
-	MethodConfidence        string // high/medium/low
+	Confidence              Confidence // 信頼度スコアと根拠
 	Trials                  int    // 試行回数
 	Duration                time.Duration
 	ErrorMessage            string // エラー情報（あれば）
@@ -276,6 +288,7 @@ type MaxOutputResult struct {
 	MaxSuccessfullyGenerated int    // 実際に生成できた最大トークン数
 	Success                 bool   // 成功フラグ
 	TrialHistory            []TrialInfo // 試行履歴
+	UsageDiscrepancies      []Evidence  // usageの照合で見つかった矛盾
 }
 
 // String は結果を文字列として返す
@@ -295,7 +308,7 @@ func (r *MaxOutputResult) String() string {
 			"Max Successfully Generated: %d\n",
 		r.Model,
 		r.MaxOutputTokens,
-		r.MethodConfidence,
+		r.Confidence,
 		r.Trials,
 		r.Duration.Round(time.Second),
 		r.InputTokensUsed,
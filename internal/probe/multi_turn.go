@@ -0,0 +1,164 @@
+package probe
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+// DefaultTurnTokens は複数ターンの探索で1往復（userとassistant）に含めるトークン数
+const DefaultTurnTokens = 4096
+
+// multiTurnMaxTokens は複数ターンの探索で指定するmax_tokens（単発の探索と同じ）
+const multiTurnMaxTokens = 16
+
+// MultiTurnDetails は複数ターンの会話履歴で探索したコンテキスト上限の詳細
+type MultiTurnDetails struct {
+	TurnTokens      int `json:"turn_tokens"`                 // 1往復に含めたトークン数
+	Turns           int `json:"turns"`                       // 上限に達したときの往復の数
+	SingleShotLimit int `json:"single_shot_limit,omitempty"` // 保存済みの単発の探索結果（0なら未探索）
+}
+
+// ProbeMultiTurn は会話履歴を積み上げたリクエストで実用上のコンテキスト上限を探索する
+// ゲートウェイやモデルによっては、1つのメッセージと会話履歴とで上限の扱いが異なるため、
+// assistantの応答を含む往復を増やしながら、受け付けられる履歴の長さを求める
+func (p *ContextWindowProbe) ProbeMultiTurn(model string, strategy Strategy, turnTokens int) (*ContextWindowResult, error) {
+	p.recorder.reset()
+	p.body.reset(p.client.GetConfig().MaxBodySize)
+	startTime := time.Now()
+
+	if turnTokens <= 0 {
+		turnTokens = DefaultTurnTokens
+	}
+
+	env := &StrategyEnv{
+		Model:    model,
+		Searcher: p.searcher,
+		Trial: func(tokens int) (*BoundarySearchResult, error) {
+			return p.testWithTurns(model, tokens, turnTokens, multiTurnMaxTokens), nil
+		},
+		Validate: func(tokens, maxTokens int) *BoundarySearchResult {
+			return p.testWithTurns(model, tokens, turnTokens, maxTokens)
+		},
+	}
+
+	outcome, err := strategy.Search(env)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ContextWindowResult{
+		Model:            model,
+		MaxContextTokens: outcome.Value,
+		Trials:           outcome.Trials,
+		Duration:         time.Since(startTime),
+		Success:          outcome.Success,
+		Source:           outcome.Source,
+		Strategy:         strategy.Name(),
+		TurnTokens:       turnTokens,
+		TrialHistory:     p.recorder.history(),
+	}
+	if outcome.Success {
+		result.Confidence = p.searcher.CalculateConfidence(outcome.Value, p.recorder.evidenceList())
+	} else {
+		result.Confidence = p.searcher.CalculateConfidence(0, p.recorder.evidenceList())
+	}
+	if !outcome.Success || outcome.Source == "validation_error" {
+		result.ErrorMessage = outcome.ErrorMessage
+	}
+
+	return result, nil
+}
+
+// testWithTurns は合計でおよそtokensトークンの会話履歴を送信して1回試行する
+func (p *ContextWindowProbe) testWithTurns(model string, tokens, turnTokens, maxTokens int) (result *BoundarySearchResult) {
+	trialStart := time.Now()
+	throttleStart := p.client.Throttling()
+	var response *api.ProbeResponse
+	var latency time.Duration
+	defer func() {
+		p.recorder.record(tokens, trialStart, latency, p.client.Throttling().Sub(throttleStart), response, result)
+	}()
+
+	messages := p.generator.NewConversation(tokens, turnTokens)
+	var size int64
+	for _, message := range messages {
+		size += int64(len(message.Content))
+	}
+	// ボディのサイズ上限を超える会話は送信しない
+	if p.body.exceeds(size) {
+		return p.body.skipped(size)
+	}
+
+	if p.searcher.verbose != nil {
+		p.searcher.verbose.LogAPIRequest("POST", p.client.GetConfig().BaseURL+p.client.EndpointPath(model), tokens, 0)
+	}
+
+	response, err := p.client.ProbeMessages(model, messages, maxTokens)
+	latency = time.Since(trialStart)
+
+	if p.searcher.verbose != nil {
+		status, promptTokens, completionTokens := 200, 0, 0
+		if response != nil {
+			if response.Error != nil {
+				status = 400
+			}
+			if response.Usage != nil {
+				promptTokens = response.Usage.PromptTokens
+				completionTokens = response.Usage.CompletionTokens
+			}
+		}
+		p.searcher.verbose.LogAPIResponse(status, promptTokens, completionTokens, latency)
+	}
+
+	if err == nil {
+		if response.Usage == nil || response.Usage.PromptTokens == 0 {
+			return &BoundarySearchResult{ErrorMessage: "No usage information in response", Source: "api_error", Trials: 1}
+		}
+		return &BoundarySearchResult{Value: response.Usage.PromptTokens, Success: true, Source: "success", Trials: 1}
+	}
+
+	errorMessage := err.Error()
+	if response != nil && response.Error != nil {
+		errorMessage = response.Error.Message
+	}
+	if limit, found := p.searcher.ExtractTokenLimitFromError(errorMessage); found {
+		return &BoundarySearchResult{Value: limit, ErrorMessage: errorMessage, Source: "validation_error", Trials: 1}
+	}
+	if isBodyTooLarge(err, errorMessage) {
+		p.body.reject(size)
+		return bodyTooLargeResult(errorMessage)
+	}
+	return &BoundarySearchResult{ErrorMessage: fmt.Sprintf("API request failed: %v", err), Source: "error", Trials: 1}
+}
+
+// NewConversation は合計でおよそtargetTokensトークンになる会話履歴を作成する
+// 各ターンはuserのメッセージとそれをそのまま繰り返すassistantの応答からなり、1往復でturnTokensトークンを使う
+// 最初のメッセージにneedleを入れ、最後のuserのメッセージで質問する
+func (g *TestDataGenerator) NewConversation(targetTokens, turnTokens int) []api.Message {
+	var messages []api.Message
+	remaining := targetTokens
+	for turn := 1; remaining > 0; turn++ {
+		size := min(turnTokens, remaining)
+		remaining -= size
+
+		content := fmt.Sprintf("Turn %d.%s%s", turn, promptSeparator, g.NewPrompt(size/2, End, "", "").body())
+		if turn == 1 {
+			content = defaultPreamble + promptSeparator + defaultNeedle + promptSeparator + content
+		}
+		messages = append(messages,
+			api.Message{Role: "user", Content: content},
+			api.Message{Role: "assistant", Content: content},
+		)
+	}
+	return append(messages, api.Message{Role: "user", Content: defaultQuestion})
+}
+
+// Turns は上限に達したときの会話の往復の数を返す（単発の探索では0）
+func (r *ContextWindowResult) Turns() int {
+	if r.TurnTokens <= 0 || r.MaxContextTokens <= 0 {
+		return 0
+	}
+	return (r.MaxContextTokens + r.TurnTokens - 1) / r.TurnTokens
+}
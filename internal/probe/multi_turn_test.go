@@ -0,0 +1,82 @@
+package probe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// multiTurnServer はプロンプトのバイト数をprompt_tokensとして報告し、
+// 会話履歴（複数のメッセージ）の合計がhistoryLimitを超えると拒否するテストサーバー
+func multiTurnServer(t *testing.T, historyLimit int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req api.ProbeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			return
+		}
+		tokens := 0
+		for _, message := range req.Messages {
+			tokens += len(message.Content)
+		}
+		if len(req.Messages) > 1 && tokens > historyLimit {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(api.ProbeResponse{Error: &api.OpenAIError{Type: "invalid_request_error", Message: "conversation history is too long"}})
+			return
+		}
+		json.NewEncoder(w).Encode(api.ProbeResponse{
+			Choices: []api.ChatChoice{{FinishReason: "stop"}},
+			Usage:   &api.UsageInfo{PromptTokens: tokens, CompletionTokens: 1},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestContextWindowProbe_ProbeMultiTurn(t *testing.T) {
+	server := multiTurnServer(t, 20000)
+	p := NewContextWindowProbe(api.NewProbeClient(&config.AppConfig{BaseURL: server.URL, APIKey: "test", Timeout: 5 * time.Second}))
+
+	result, err := p.ProbeMultiTurn("test-model", SearchStrategy{}, 2048)
+	if err != nil {
+		t.Fatalf("ProbeMultiTurn() error = %v", err)
+	}
+	if !result.Success || result.MaxContextTokens <= 0 || result.MaxContextTokens > 20000 {
+		t.Fatalf("MaxContextTokens = %d (success %v), want at most the history limit", result.MaxContextTokens, result.Success)
+	}
+	if result.Turns() < 2 {
+		t.Errorf("Turns() = %d, want several turns", result.Turns())
+	}
+
+	saved := result.ToResult("production")
+	if saved.Type != ProbeTypeMultiTurn || saved.MultiTurn == nil || saved.MultiTurn.TurnTokens != 2048 {
+		t.Errorf("ToResult() type = %s, multi_turn = %+v", saved.Type, saved.MultiTurn)
+	}
+}
+
+func TestTestDataGenerator_NewConversation(t *testing.T) {
+	messages := NewTestDataGenerator().NewConversation(10000, 4096)
+
+	// 3往復（4096 + 4096 + 1808）と最後の質問
+	if len(messages) != 7 {
+		t.Fatalf("len(messages) = %d, want 7", len(messages))
+	}
+	for i := 0; i < 6; i += 2 {
+		if messages[i].Role != "user" || messages[i+1].Role != "assistant" || messages[i].Content != messages[i+1].Content {
+			t.Errorf("turn %d = %s/%s, want a user message echoed by the assistant", i/2+1, messages[i].Role, messages[i+1].Role)
+		}
+	}
+	if !strings.Contains(messages[0].Content, defaultNeedle) {
+		t.Error("first message does not contain the needle")
+	}
+	if last := messages[len(messages)-1]; last.Role != "user" || last.Content != defaultQuestion {
+		t.Errorf("last message = %+v, want the question", last)
+	}
+}
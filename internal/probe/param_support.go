@@ -0,0 +1,178 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+// パラメータの対応状況
+const (
+	ParamSupported = "supported" // 値が反映される、または不正な値が検証で拒否される
+	ParamIgnored   = "ignored"   // 受け付けられるが反映されていない（ゲートウェイが落としている）
+	ParamRejected  = "rejected"  // 正しい値でもリクエストが拒否される
+)
+
+// ParamSupportCase は対応状況を調べる1つのパラメータ
+type ParamSupportCase struct {
+	Name string
+	// Valid は正しい値。これで拒否された場合はrejected
+	Valid any
+	// Invalid は範囲外や型違いの値。パラメータを解釈するゲートウェイはこれを拒否する
+	Invalid any
+	// Observed はレスポンスから値が反映されたかを判定する（nilならInvalidで判定する）
+	Observed func(response *api.ProbeResponse) bool
+	// Prompt はこのパラメータ用のプロンプト（空なら共通のプロンプトを使う）
+	Prompt string
+	// MaxTokens はこのパラメータ用のmax_tokens（0なら共通の値を使う）
+	MaxTokens int
+}
+
+// ParamSupportCases はゲートウェイが落としたり拒否したりしやすいパラメータの一覧
+var ParamSupportCases = []ParamSupportCase{
+	{Name: "temperature", Valid: 0.7, Invalid: 50},
+	{Name: "top_p", Valid: 0.9, Invalid: 50},
+	{Name: "frequency_penalty", Valid: 0.5, Invalid: 50},
+	{Name: "presence_penalty", Valid: 0.5, Invalid: 50},
+	{Name: "seed", Valid: 42, Invalid: "not-a-seed"},
+	{Name: "logprobs", Valid: true, Observed: hasLogprobs},
+	{Name: "stop", Valid: []string{"5"}, Observed: stoppedBefore("5"), Prompt: stopPrompt, MaxTokens: 40},
+	{Name: "n", Valid: 2, Observed: hasChoices(2)},
+}
+
+// stopPrompt はstopシーケンスの手前で止まったかを確認するためのプロンプト
+const stopPrompt = "Count from 1 to 10, separated by commas. Reply with the numbers only."
+
+// paramProbeMaxTokens はパラメータの探索で使うmax_tokens
+const paramProbeMaxTokens = 16
+
+// hasLogprobs はレスポンスにlogprobsが含まれているかを返す
+func hasLogprobs(response *api.ProbeResponse) bool {
+	if response == nil {
+		return false
+	}
+	for _, choice := range response.Choices {
+		if len(choice.Logprobs) > 0 && string(choice.Logprobs) != "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// stoppedBefore はstopシーケンスの手前で出力が止まったかを判定する関数を返す
+// stopシーケンス自体は出力に含まれず、その手前までは生成されているはず
+func stoppedBefore(stop string) func(response *api.ProbeResponse) bool {
+	return func(response *api.ProbeResponse) bool {
+		if response == nil || len(response.Choices) == 0 {
+			return false
+		}
+		for _, choice := range response.Choices {
+			content := choice.Message.Content
+			if strings.Contains(content, stop) || !strings.Contains(content, "1") {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// hasChoices はレスポンスに指定数以上の選択肢が含まれているかを判定する関数を返す
+func hasChoices(n int) func(response *api.ProbeResponse) bool {
+	return func(response *api.ProbeResponse) bool {
+		return response != nil && len(response.Choices) >= n
+	}
+}
+
+// ParamSupportResult は1つのパラメータの対応状況
+type ParamSupportResult struct {
+	Parameter string `json:"parameter"`
+	Status    string `json:"status"`
+	Evidence  string `json:"evidence"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ParamSupportProbe はパラメータの対応状況を調べる
+type ParamSupportProbe struct {
+	client *api.ProbeClient
+	cases  []ParamSupportCase
+	delay  time.Duration // リクエスト間の待機（レート制限対策）
+}
+
+// NewParamSupportProbe は新しいParamSupportProbeを作成する
+func NewParamSupportProbe(client *api.ProbeClient) *ParamSupportProbe {
+	return &ParamSupportProbe{
+		client: client,
+		cases:  ParamSupportCases,
+		delay:  200 * time.Millisecond,
+	}
+}
+
+// Probe は各パラメータを付けたリクエストを送信し、supported/ignored/rejectedに分類する
+// パラメータなしのリクエストが失敗した場合は接続や認証の問題とみなしてエラーを返す
+func (p *ParamSupportProbe) Probe(model string) ([]ParamSupportResult, error) {
+	messages := []api.Message{{Role: "user", Content: "Reply with OK."}}
+
+	if _, err := p.client.ProbeMessages(model, messages, paramProbeMaxTokens); err != nil {
+		return nil, fmt.Errorf("baseline request failed: %w", err)
+	}
+
+	var results []ParamSupportResult
+	for _, c := range p.cases {
+		results = append(results, p.probeParam(model, messages, c))
+	}
+	return results, nil
+}
+
+// probeParam は1つのパラメータを分類する
+func (p *ParamSupportProbe) probeParam(model string, messages []api.Message, c ParamSupportCase) ParamSupportResult {
+	result := ParamSupportResult{Parameter: c.Name}
+
+	if c.Prompt != "" {
+		messages = []api.Message{{Role: "user", Content: c.Prompt}}
+	}
+	maxTokens := paramProbeMaxTokens
+	if c.MaxTokens > 0 {
+		maxTokens = c.MaxTokens
+	}
+
+	p.wait()
+	response, err := p.client.ProbeMessagesWithParams(model, messages, maxTokens, map[string]any{c.Name: c.Valid})
+	if err != nil {
+		result.Status = ParamRejected
+		result.Evidence = fmt.Sprintf("request with %s=%v was rejected", c.Name, c.Valid)
+		result.Error = err.Error()
+		return result
+	}
+
+	// レスポンスで確認できるパラメータ
+	if c.Observed != nil {
+		if c.Observed(response) {
+			result.Status = ParamSupported
+			result.Evidence = "reflected in the response"
+		} else {
+			result.Status = ParamIgnored
+			result.Evidence = "accepted but not reflected in the response"
+		}
+		return result
+	}
+
+	// 不正な値が拒否されればパラメータは解釈されている
+	p.wait()
+	if _, err := p.client.ProbeMessagesWithParams(model, messages, maxTokens, map[string]any{c.Name: c.Invalid}); err != nil {
+		result.Status = ParamSupported
+		result.Evidence = fmt.Sprintf("invalid value %v was rejected", c.Invalid)
+		return result
+	}
+	result.Status = ParamIgnored
+	result.Evidence = fmt.Sprintf("invalid value %v was accepted", c.Invalid)
+	return result
+}
+
+// wait はリクエスト間で待機する
+func (p *ParamSupportProbe) wait() {
+	if p.delay > 0 {
+		time.Sleep(p.delay)
+	}
+}
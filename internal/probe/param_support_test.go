@@ -0,0 +1,145 @@
+package probe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// paramServer はtemperatureとtop_pを検証し、seedとpresence_penaltyとnを黙って落とし、
+// frequency_penaltyを拒否し、stopを反映するゲートウェイを模したテストサーバー
+func paramServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			return
+		}
+
+		reject := func(message string) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(api.ProbeResponse{Error: &api.OpenAIError{Type: "invalid_request_error", Message: message}})
+		}
+		for _, name := range []string{"temperature", "top_p"} {
+			if value, ok := req[name].(float64); ok && value > 2 {
+				reject(name + " must be <= 2")
+				return
+			}
+		}
+		if _, ok := req["frequency_penalty"]; ok {
+			reject("unsupported parameter: frequency_penalty")
+			return
+		}
+
+		content := "OK"
+		if messages, ok := req["messages"].([]any); ok && messages[0].(map[string]any)["content"] == stopPrompt {
+			content = "1, 2, 3, 4, 5, 6, 7, 8, 9, 10"
+			if _, ok := req["stop"]; ok {
+				content = "1, 2, 3, 4, "
+			}
+		}
+		choice := map[string]any{"index": 0, "finish_reason": "stop", "message": map[string]any{"role": "assistant", "content": content}}
+		if req["logprobs"] == true {
+			choice["logprobs"] = map[string]any{"content": []any{}}
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []any{choice},
+			"usage":   map[string]any{"prompt_tokens": 5, "completion_tokens": 1, "total_tokens": 6},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestParamSupportProbe_Probe(t *testing.T) {
+	server := paramServer(t)
+	p := NewParamSupportProbe(api.NewProbeClient(&config.AppConfig{
+		BaseURL: server.URL,
+		APIKey:  "test",
+		Timeout: 5 * time.Second,
+	}))
+	p.delay = 0
+
+	results, err := p.Probe("test-model")
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+
+	capabilities := NewCapabilities("test-model", "")
+	capabilities.SetParameters(results, time.Now())
+
+	want := map[string]string{
+		"temperature":       ParamSupported,
+		"top_p":             ParamSupported,
+		"frequency_penalty": ParamRejected,
+		"presence_penalty":  ParamIgnored,
+		"seed":              ParamIgnored,
+		"logprobs":          ParamSupported,
+		"stop":              ParamSupported,
+		"n":                 ParamIgnored,
+	}
+	for name, status := range want {
+		if got := capabilities.Parameter(name); got != status {
+			t.Errorf("Parameter(%s) = %q, want %q", name, got, status)
+		}
+	}
+}
+
+func TestStoppedBefore(t *testing.T) {
+	response := func(contents ...string) *api.ProbeResponse {
+		r := &api.ProbeResponse{}
+		for _, content := range contents {
+			r.Choices = append(r.Choices, api.ChatChoice{Message: api.ChatMessage{Content: content}})
+		}
+		return r
+	}
+
+	observed := stoppedBefore("5")
+	tests := []struct {
+		name     string
+		response *api.ProbeResponse
+		want     bool
+	}{
+		{"stopped", response("1, 2, 3, 4, "), true},
+		{"not stopped", response("1, 2, 3, 4, 5, 6"), false},
+		{"one of n not stopped", response("1, 2, 3, 4, ", "1, 2, 3, 4, 5"), false},
+		{"unrelated output", response("OK"), false},
+		{"no choices", response(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := observed(tt.response); got != tt.want {
+				t.Errorf("stoppedBefore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCapabilities(t *testing.T) {
+	original := NewCapabilities("gpt-4o", "production")
+	original.SetParameters([]ParamSupportResult{{Parameter: "seed", Status: ParamIgnored}}, time.Now())
+	original.SetRoles(&RoleCompatReport{Results: []RoleCompatResult{{Case: "developer", Accepted: false}}, ProbedAt: time.Now()})
+
+	// 保存済みの結果はmapとして読み込まれる
+	data, _ := json.Marshal(original)
+	var saved interface{}
+	json.Unmarshal(data, &saved)
+
+	decoded, err := DecodeCapabilities(saved)
+	if err != nil {
+		t.Fatalf("DecodeCapabilities() error = %v", err)
+	}
+	if decoded.Model != "gpt-4o" || decoded.Parameter("seed") != ParamIgnored {
+		t.Errorf("decoded = %+v", decoded)
+	}
+	if len(decoded.Roles) != 1 || decoded.RolesProbedAt == nil {
+		t.Errorf("Roles = %+v, RolesProbedAt = %v", decoded.Roles, decoded.RolesProbedAt)
+	}
+}
@@ -0,0 +1,152 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// cachingPrefixWords はキャッシュの対象にする共通のプレフィックスの単語数
+// OpenAIとAnthropicはおおむね1024トークン以上のプレフィックスだけをキャッシュするため、それを超える長さにする
+const cachingPrefixWords = 1500
+
+// cachingQuestion はプレフィックスの後に付ける質問
+const cachingQuestion = "Reply with OK."
+
+// cache_controlの探索結果
+const (
+	CacheControlAccepted = "accepted"
+	CacheControlRejected = "rejected"
+	CacheControlSkipped  = "skipped" // チャット形式以外のエンドポイントでは送れない
+)
+
+// CachingSample は同じプレフィックスを送った1回のリクエストの使用量
+type CachingSample struct {
+	CacheControl        bool `json:"cache_control"` // cache_controlを付けて送ったか
+	PromptTokens        int  `json:"prompt_tokens"`
+	CachedTokens        int  `json:"cached_tokens"`                   // prompt_tokens_details.cached_tokensまたはcache_read_input_tokens
+	CacheCreationTokens int  `json:"cache_creation_tokens,omitempty"` // cache_creation_input_tokens
+}
+
+// PromptCachingResult はプロンプトキャッシュへの対応状況の探索結果
+type PromptCachingResult struct {
+	Model               string          `json:"model"`
+	Gateway             string          `json:"gateway,omitempty"`
+	Endpoint            string          `json:"endpoint"`
+	PrefixWords         int             `json:"prefix_words"`
+	Samples             []CachingSample `json:"samples"`
+	ReportsCachedTokens bool            `json:"reports_cached_tokens"` // usageにキャッシュの内訳が含まれていた
+	AutomaticHit        bool            `json:"automatic_hit"`         // 同じプレフィックスの2回目でcached_tokensが報告された
+	CacheControl        string          `json:"cache_control"`         // accepted、rejected、skipped
+	CacheControlHit     bool            `json:"cache_control_hit"`     // cache_controlを付けた2回目でcached_tokensが報告された
+	CacheControlError   string          `json:"cache_control_error,omitempty"`
+	Supported           bool            `json:"supported"` // どちらかの方法でキャッシュにヒットした
+	ProbedAt            time.Time       `json:"probed_at"`
+	Error               string          `json:"error,omitempty"` // 探索自体が失敗した場合のエラー（--continue-on-error）
+}
+
+// PromptCachingProbe は長い同じプレフィックスを2回送り、2回目にキャッシュされたトークン数が報告されるかを調べる
+type PromptCachingProbe struct {
+	client *api.ProbeClient
+	delay  time.Duration // 1回目のキャッシュの書き込みを待つ時間
+}
+
+// NewPromptCachingProbe は新しいPromptCachingProbeを作成する
+func NewPromptCachingProbe(client *api.ProbeClient) *PromptCachingProbe {
+	return &PromptCachingProbe{
+		client: client,
+		delay:  time.Second,
+	}
+}
+
+// Probe は自動のキャッシュ（OpenAI形式）とcache_controlを付けたキャッシュ（Anthropic形式）をそれぞれ調べる
+// 前回の実行や他の利用者のキャッシュにヒットしないよう、プレフィックスの先頭には実行ごとに異なる値を入れる
+func (p *PromptCachingProbe) Probe(model, gateway string) (*PromptCachingResult, error) {
+	result := &PromptCachingResult{
+		Model:       model,
+		Gateway:     gateway,
+		PrefixWords: cachingPrefixWords,
+		ProbedAt:    time.Now(),
+	}
+
+	prefix := cachingPrefix(result.ProbedAt, "auto")
+	messages := []api.Message{{Role: "user", Content: prefix + "\n\n" + cachingQuestion}}
+	for i := 0; i < 2; i++ {
+		if i > 0 && p.delay > 0 {
+			time.Sleep(p.delay)
+		}
+		response, err := p.client.ProbeMessages(model, messages, 16)
+		if err != nil {
+			return nil, fmt.Errorf("request %d with a %d-word prefix failed: %w", i+1, cachingPrefixWords, err)
+		}
+		if response.Usage == nil || response.Usage.PromptTokens == 0 {
+			return nil, fmt.Errorf("the gateway did not report usage.prompt_tokens")
+		}
+		result.Endpoint = p.client.Endpoint(model)
+		result.addSample(response.Usage, false)
+	}
+	result.AutomaticHit = result.Samples[1].CachedTokens > 0
+
+	p.probeCacheControl(model, result)
+	result.Supported = result.AutomaticHit || result.CacheControlHit
+
+	return result, nil
+}
+
+// probeCacheControl はcache_controlを付けた内容のパーツを送り、受け付けられるかとキャッシュにヒットするかを調べる
+// 内容をパーツの配列で送るため、チャット形式のエンドポイントでのみ調べる
+func (p *PromptCachingProbe) probeCacheControl(model string, result *PromptCachingResult) {
+	if result.Endpoint != config.ProbeEndpointChat {
+		result.CacheControl = CacheControlSkipped
+		return
+	}
+
+	params := map[string]any{
+		"messages": []map[string]any{{
+			"role": "user",
+			"content": []map[string]any{
+				{"type": "text", "text": cachingPrefix(result.ProbedAt, "cache_control"), "cache_control": map[string]string{"type": "ephemeral"}},
+				{"type": "text", "text": cachingQuestion},
+			},
+		}},
+	}
+	for i := 0; i < 2; i++ {
+		time.Sleep(p.delay)
+		response, err := p.client.ProbeMessagesWithParams(model, nil, 16, params)
+		if err != nil {
+			// 1回目が拒否された場合はcache_controlに対応していない
+			if i == 0 {
+				result.CacheControl = CacheControlRejected
+				result.CacheControlError = err.Error()
+			}
+			return
+		}
+		result.CacheControl = CacheControlAccepted
+		if response.Usage == nil {
+			return
+		}
+		result.addSample(response.Usage, true)
+		if i == 1 {
+			result.CacheControlHit = response.Usage.CachedTokens() > 0
+		}
+	}
+}
+
+// addSample はレスポンスの使用量を記録する
+func (r *PromptCachingResult) addSample(usage *api.UsageInfo, cacheControl bool) {
+	r.Samples = append(r.Samples, CachingSample{
+		CacheControl:        cacheControl,
+		PromptTokens:        usage.PromptTokens,
+		CachedTokens:        usage.CachedTokens(),
+		CacheCreationTokens: usage.CacheCreationInputTokens,
+	})
+	r.ReportsCachedTokens = r.ReportsCachedTokens || usage.ReportsCaching()
+}
+
+// cachingPrefix はキャッシュの対象にするプレフィックスを作成する
+func cachingPrefix(probedAt time.Time, kind string) string {
+	return fmt.Sprintf("Prompt caching probe %s %d.\n%s", kind, probedAt.UnixNano(), strings.TrimSpace(strings.Repeat(overheadWord+" ", cachingPrefixWords)))
+}
@@ -0,0 +1,102 @@
+package probe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// cachingServer は同じリクエスト本文の2回目にキャッシュされたトークン数を報告するテストサーバー
+// automaticは通常のリクエストをキャッシュするか、cacheControlはcache_controlを受け付けるか
+func cachingServer(t *testing.T, automatic, cacheControl bool) *httptest.Server {
+	t.Helper()
+	seen := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Content json.RawMessage `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			return
+		}
+		content := string(req.Messages[0].Content)
+		parts := content[0] == '['
+		if parts && !cacheControl {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(api.ProbeResponse{Error: &api.OpenAIError{Type: "invalid_request_error", Message: "cache_control is not permitted"}})
+			return
+		}
+
+		usage := &api.UsageInfo{PromptTokens: 1510, CompletionTokens: 1}
+		if parts {
+			// Anthropic形式
+			if seen[content] {
+				usage.CacheReadInputTokens = 1500
+			} else {
+				usage.CacheCreationInputTokens = 1500
+			}
+		} else if automatic {
+			usage.PromptTokensDetails = &api.PromptTokensDetails{}
+			if seen[content] {
+				usage.PromptTokensDetails.CachedTokens = 1024
+			}
+		}
+		seen[content] = true
+		json.NewEncoder(w).Encode(api.ProbeResponse{
+			Choices: []api.ChatChoice{{FinishReason: "stop"}},
+			Usage:   usage,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPromptCachingProbe_Probe(t *testing.T) {
+	tests := []struct {
+		name             string
+		automatic        bool
+		cacheControl     bool
+		wantAutomaticHit bool
+		wantControl      string
+		wantControlHit   bool
+		wantReports      bool
+	}{
+		{"automatic caching", true, false, true, CacheControlRejected, false, true},
+		{"cache_control only", false, true, false, CacheControlAccepted, true, true},
+		{"no caching", false, false, false, CacheControlRejected, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := cachingServer(t, tt.automatic, tt.cacheControl)
+			p := NewPromptCachingProbe(api.NewProbeClient(&config.AppConfig{BaseURL: server.URL, APIKey: "test", Timeout: 5 * time.Second}))
+			p.delay = 0
+
+			result, err := p.Probe("test-model", "production")
+			if err != nil {
+				t.Fatalf("Probe() error = %v", err)
+			}
+			if result.AutomaticHit != tt.wantAutomaticHit {
+				t.Errorf("AutomaticHit = %v, want %v", result.AutomaticHit, tt.wantAutomaticHit)
+			}
+			if result.CacheControl != tt.wantControl || result.CacheControlHit != tt.wantControlHit {
+				t.Errorf("CacheControl = %s (hit %v), want %s (hit %v)", result.CacheControl, result.CacheControlHit, tt.wantControl, tt.wantControlHit)
+			}
+			if result.ReportsCachedTokens != tt.wantReports {
+				t.Errorf("ReportsCachedTokens = %v, want %v", result.ReportsCachedTokens, tt.wantReports)
+			}
+			if result.Supported != (tt.wantAutomaticHit || tt.wantControlHit) {
+				t.Errorf("Supported = %v", result.Supported)
+			}
+			if tt.wantControl == CacheControlRejected && result.CacheControlError == "" {
+				t.Error("CacheControlError is empty for a rejected cache_control")
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package probe
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// overheadWord はオーバーヘッドの測定に使う単語（主要なトークナイザーで1トークンになる）
+const overheadWord = "hello"
+
+// overheadWordCounts は測定に使うプロンプトの単語数（短いプロンプトと長いプロンプト）
+var overheadWordCounts = [2]int{1, 201}
+
+// SignificantOverhead はゲートウェイがシステムプロンプトなどを追加しているとみなすオーバーヘッドのトークン数
+// メッセージの区切りなどのテンプレートの誤差はこれより小さい
+const SignificantOverhead = 16
+
+// chatFramingTokens はチャット形式で1つのuserメッセージを送ったときのテンプレートのトークン数の見積もり
+// （メッセージごとの3トークン、ロール名の1トークン、応答の開始を示す3トークン）
+const chatFramingTokens = 7
+
+// OverheadSample は1回の測定で送ったプロンプトと、ゲートウェイが報告したprompt_tokens
+type OverheadSample struct {
+	Words           int `json:"words"`
+	EstimatedTokens int `json:"estimated_tokens"` // 送ったと見積もったトークン数（本文＋テンプレート）
+	PromptTokens    int `json:"prompt_tokens"`    // usage.prompt_tokens
+}
+
+// PromptOverheadResult はゲートウェイが追加したプロンプトのオーバーヘッドの測定結果
+type PromptOverheadResult struct {
+	Model         string           `json:"model"`
+	Gateway       string           `json:"gateway,omitempty"`
+	Endpoint      string           `json:"endpoint"`
+	Samples       []OverheadSample `json:"samples"`
+	TokensPerWord float64          `json:"tokens_per_word"` // 2つの測定の差から求めた1単語あたりのトークン数
+	Overhead      int              `json:"overhead"`        // 報告されたprompt_tokensと見積もりの差
+	Significant   bool             `json:"significant"`     // SignificantOverhead以上（システムプロンプトの挿入などが疑われる）
+	ProbedAt      time.Time        `json:"probed_at"`
+	Error         string           `json:"error,omitempty"` // 探索自体が失敗した場合のエラー（--continue-on-error）
+}
+
+// PromptOverheadProbe は送ったと見積もったトークン数とusage.prompt_tokensを比べ、
+// ゲートウェイやプロキシが追加したプロンプト（システムプロンプトなど）のトークン数を求める
+type PromptOverheadProbe struct {
+	client *api.ProbeClient
+	delay  time.Duration // リクエスト間の待機（レート制限対策）
+}
+
+// NewPromptOverheadProbe は新しいPromptOverheadProbeを作成する
+func NewPromptOverheadProbe(client *api.ProbeClient) *PromptOverheadProbe {
+	return &PromptOverheadProbe{
+		client: client,
+		delay:  200 * time.Millisecond,
+	}
+}
+
+// Probe は単語数の異なる2つのプロンプトを送り、オーバーヘッドを求める
+// 2つの測定の差から1単語あたりのトークン数を求めるため、トークナイザーが見積もりと異なっても
+// 本文の長さによらない部分（テンプレートと追加されたプロンプト）だけを取り出せる
+func (p *PromptOverheadProbe) Probe(model, gateway string) (*PromptOverheadResult, error) {
+	result := &PromptOverheadResult{
+		Model:    model,
+		Gateway:  gateway,
+		ProbedAt: time.Now(),
+	}
+
+	for i, words := range overheadWordCounts {
+		if i > 0 && p.delay > 0 {
+			time.Sleep(p.delay)
+		}
+		messages := []api.Message{{Role: "user", Content: strings.TrimSpace(strings.Repeat(overheadWord+" ", words))}}
+		response, err := p.client.ProbeMessages(model, messages, 16)
+		if err != nil {
+			return nil, fmt.Errorf("request with %d words failed: %w", words, err)
+		}
+		if response.Usage == nil || response.Usage.PromptTokens == 0 {
+			return nil, fmt.Errorf("the gateway did not report usage.prompt_tokens")
+		}
+		result.Endpoint = p.client.Endpoint(model)
+		result.Samples = append(result.Samples, OverheadSample{
+			Words:           words,
+			EstimatedTokens: words + framingTokens(result.Endpoint),
+			PromptTokens:    response.Usage.PromptTokens,
+		})
+	}
+
+	short, long := result.Samples[0], result.Samples[1]
+	result.TokensPerWord = float64(long.PromptTokens-short.PromptTokens) / float64(long.Words-short.Words)
+
+	// 1単語が1トークンにならないトークナイザーでは、見積もりの本文の部分を実測の比率で補正する
+	content := float64(short.Words)
+	if result.TokensPerWord > 0 {
+		content *= result.TokensPerWord
+	}
+	result.Overhead = short.PromptTokens - int(math.Round(content)) - framingTokens(result.Endpoint)
+	result.Significant = result.Overhead >= SignificantOverhead
+
+	return result, nil
+}
+
+// framingTokens はエンドポイントごとのテンプレートのトークン数の見積もりを返す
+// /v1/completionsはプロンプトをそのまま送るためテンプレートはない
+func framingTokens(endpoint string) int {
+	if endpoint == config.ProbeEndpointCompletions {
+		return 0
+	}
+	return chatFramingTokens
+}
@@ -0,0 +1,70 @@
+package probe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// overheadServer はプロンプトの単語数に応じたprompt_tokensを報告するテストサーバー
+// injectedは追加されるシステムプロンプト、tokensPerWordは1単語あたりのトークン数
+func overheadServer(t *testing.T, injected, tokensPerWord int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req api.ProbeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			return
+		}
+		words := len(strings.Fields(req.Messages[0].Content))
+		json.NewEncoder(w).Encode(api.ProbeResponse{
+			Choices: []api.ChatChoice{{FinishReason: "stop"}},
+			Usage:   &api.UsageInfo{PromptTokens: words*tokensPerWord + chatFramingTokens + injected, CompletionTokens: 1},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPromptOverheadProbe_Probe(t *testing.T) {
+	tests := []struct {
+		name          string
+		injected      int
+		tokensPerWord int
+		wantSignif    bool
+	}{
+		{"no overhead", 0, 1, false},
+		{"injected system prompt", 120, 1, true},
+		{"two tokens per word", 120, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := overheadServer(t, tt.injected, tt.tokensPerWord)
+			p := NewPromptOverheadProbe(api.NewProbeClient(&config.AppConfig{BaseURL: server.URL, APIKey: "test", Timeout: 5 * time.Second}))
+			p.delay = 0
+
+			result, err := p.Probe("test-model", "production")
+			if err != nil {
+				t.Fatalf("Probe() error = %v", err)
+			}
+			if result.Overhead != tt.injected {
+				t.Errorf("Overhead = %d, want %d", result.Overhead, tt.injected)
+			}
+			if result.TokensPerWord != float64(tt.tokensPerWord) {
+				t.Errorf("TokensPerWord = %v, want %d", result.TokensPerWord, tt.tokensPerWord)
+			}
+			if result.Significant != tt.wantSignif {
+				t.Errorf("Significant = %v, want %v", result.Significant, tt.wantSignif)
+			}
+			if len(result.Samples) != 2 || result.Endpoint != config.ProbeEndpointChat {
+				t.Errorf("Samples = %+v, Endpoint = %q", result.Samples, result.Endpoint)
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package probe
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/cost"
+	"github.com/armaniacs/llm-info/internal/numfmt"
+)
+
+// ResultSchemaVersion は探索結果JSONスキーマのバージョン
+const ResultSchemaVersion = "2.1"
+
+// 探索の種類
+const (
+	ProbeTypeContextWindow = "context_window"
+	ProbeTypeMaxOutput     = "max_output"
+	ProbeTypeMaxInput      = "max_input"
+	ProbeTypeMultiTurn     = "multi_turn_context"
+)
+
+// 探索手法
+const (
+	MethodBoundarySearch = "exponential_binary_search"
+	MethodErrorMessage   = "error_message_extraction"
+	MethodNeedlePosition = "needle_in_haystack"
+)
+
+// Result はcontext window / max output共通の探索結果（スキーマv2）
+type Result struct {
+	SchemaVersion string             `json:"schema_version"`
+	Type          string             `json:"type"`
+	Model         string             `json:"model"`
+	Gateway       string             `json:"gateway,omitempty"`
+	Value         int                `json:"value"`
+	Success       bool               `json:"success"`
+	Method        string             `json:"method"`
+	Strategy      string             `json:"strategy,omitempty"` // context window探索の戦略
+	Source        string             `json:"source,omitempty"`
+	Confidence    float64            `json:"confidence"`
+	Level         string             `json:"confidence_level"`
+	Evidence      []Evidence         `json:"evidence"`
+	TrialCount    int                `json:"trial_count"`
+	Trials        []TrialInfo        `json:"trials"`
+	CostSpent     float64            `json:"cost_spent"`
+	Spend         Spend              `json:"spend"`
+	Throttling    *Throttling        `json:"throttling,omitempty"` // レート制限で待った、または429を返された試行がある場合のみ
+	DurationMs    int64              `json:"duration_ms"`
+	ProbedAt      time.Time          `json:"probed_at"`
+	ErrorMessage  string             `json:"error,omitempty"`
+	InputTokens   int                `json:"input_tokens_used,omitempty"` // max output探索時の入力トークン数
+	Needle        *NeedleDetails     `json:"needle,omitempty"`
+	InputLimit    *InputLimitDetails `json:"input_limit,omitempty"`
+	MultiTurn     *MultiTurnDetails  `json:"multi_turn,omitempty"`
+}
+
+// Spend は探索で実際に消費したトークン数（レスポンスのusageの合計）
+type Spend struct {
+	PromptTokens       int `json:"prompt_tokens"`
+	CompletionTokens   int `json:"completion_tokens"`
+	TrialsWithoutUsage int `json:"trials_without_usage,omitempty"` // usageが返らなかった試行（トークン数に含まれない）
+}
+
+// TotalTokens は入力と出力のトークン数の合計を返す
+func (s Spend) TotalTokens() int {
+	return s.PromptTokens + s.CompletionTokens
+}
+
+// add はsの値にotherを加える
+func (s *Spend) add(other Spend) {
+	s.PromptTokens += other.PromptTokens
+	s.CompletionTokens += other.CompletionTokens
+	s.TrialsWithoutUsage += other.TrialsWithoutUsage
+}
+
+// Throttling は探索中にレート制限で待った時間と、ゲートウェイが429を返した試行、429で再送した回数と待った時間の集計
+// 所要時間が長いときに、モデルが遅いのか、ゲートウェイに制限されていたのかを区別するために記録する
+type Throttling struct {
+	RateLimitWaits  int   `json:"rate_limit_waits"`   // max_concurrency、rate_limitの空きを待った試行
+	RateLimitWaitMs int64 `json:"rate_limit_wait_ms"` // 空きを待った時間の合計
+	Throttled       int   `json:"throttled"`          // ゲートウェイが429を返した試行
+	Retries         int   `json:"retries"`            // 429を返されて再送した回数（max_retries）
+	BackoffMs       int64 `json:"backoff_ms"`         // 再送までに待った時間の合計
+}
+
+// throttlingFromTrials は試行履歴からレート制限と再送の集計を作成する（該当する試行がなければnil）
+func throttlingFromTrials(trials []TrialInfo) *Throttling {
+	var t Throttling
+	var wait, backoff time.Duration
+	for _, trial := range trials {
+		if trial.Wait > 0 {
+			t.RateLimitWaits++
+			wait += trial.Wait
+		}
+		if trial.Throttled {
+			t.Throttled++
+		}
+		t.Retries += trial.Retries
+		backoff += trial.Backoff
+	}
+	if t.RateLimitWaits == 0 && t.Throttled == 0 && t.Retries == 0 {
+		return nil
+	}
+	t.RateLimitWaitMs = wait.Milliseconds()
+	t.BackoffMs = backoff.Milliseconds()
+	return &t
+}
+
+// addThrottling はtotalにotherを加えた集計を返す（どちらもnilならnil）
+func addThrottling(total, other *Throttling) *Throttling {
+	if other == nil {
+		return total
+	}
+	if total == nil {
+		total = &Throttling{}
+	}
+	total.RateLimitWaits += other.RateLimitWaits
+	total.RateLimitWaitMs += other.RateLimitWaitMs
+	total.Throttled += other.Throttled
+	total.Retries += other.Retries
+	total.BackoffMs += other.BackoffMs
+	return total
+}
+
+// NeedleDetails はneedle-in-haystackテストの詳細
+type NeedleDetails struct {
+	Position      NeedlePosition     `json:"position"`
+	Keyword       string             `json:"keyword"`
+	Answer        string             `json:"answer"`
+	Comprehension bool               `json:"comprehension"`
+	Tests         []NeedleTestResult `json:"tests,omitempty"`
+}
+
+// InputLimitDetails は最大入力トークン探索の詳細
+// Valueはmax_tokensを最小にしたときの入力上限で、合計上限は別に記録する
+type InputLimitDetails struct {
+	CombinedLimit  int    `json:"combined_limit"`
+	CombinedSource string `json:"combined_source,omitempty"`
+	OutputReserve  int    `json:"output_reserve"`
+	Independent    bool   `json:"independent"`
+}
+
+// Report は1回のコマンド実行で得られた探索結果の集合
+type Report struct {
+	SchemaVersion   string             `json:"schema_version"`
+	Model           string             `json:"model"`
+	Gateway         string             `json:"gateway,omitempty"`
+	Results         []*Result          `json:"results"`
+	TotalTrials     int                `json:"total_trials"`
+	TotalDurationMs int64              `json:"total_duration_ms"`
+	CostSpent       float64            `json:"cost_spent"`
+	Spend           Spend              `json:"spend"`
+	Throttling      *Throttling        `json:"throttling,omitempty"`
+	Success         bool               `json:"success"`
+	Cost            *cost.UsageSummary `json:"cost,omitempty"`
+	GeneratedAt     time.Time          `json:"generated_at"`
+}
+
+// ToResult はContextWindowResultをスキーマv2の結果に変換する
+func (r *ContextWindowResult) ToResult(gateway string) *Result {
+	result := &Result{
+		SchemaVersion: ResultSchemaVersion,
+		Type:          ProbeTypeContextWindow,
+		Model:         r.Model,
+		Gateway:       gateway,
+		Value:         r.MaxContextTokens,
+		Success:       r.Success,
+		Method:        MethodBoundarySearch,
+		Source:        r.Source,
+		Strategy:      r.Strategy,
+		Confidence:    r.Confidence.Score,
+		Level:         r.Confidence.Level(),
+		Evidence:      r.Confidence.Evidence,
+		TrialCount:    r.Trials,
+		Trials:        r.TrialHistory,
+		Throttling:    throttlingFromTrials(r.TrialHistory),
+		DurationMs:    r.Duration.Milliseconds(),
+		ProbedAt:      probedAt(r.TrialHistory, r.Duration),
+		ErrorMessage:  r.ErrorMessage,
+	}
+
+	if r.Source == "validation_error" {
+		result.Method = MethodErrorMessage
+	}
+
+	// 複数ターンの探索は単発の探索と区別して記録する
+	if r.TurnTokens > 0 {
+		result.Type = ProbeTypeMultiTurn
+		result.MultiTurn = &MultiTurnDetails{
+			TurnTokens:      r.TurnTokens,
+			Turns:           r.Turns(),
+			SingleShotLimit: r.SingleShotLimit,
+		}
+	}
+
+	if r.NeedlePosition != "" || len(r.NeedleTests) > 0 {
+		result.Method = MethodNeedlePosition
+		result.Needle = &NeedleDetails{
+			Position:      r.NeedlePosition,
+			Keyword:       r.NeedleKeyword,
+			Answer:        r.NeedleAnswer,
+			Comprehension: r.NeedleComprehension,
+			Tests:         r.NeedleTests,
+		}
+	}
+
+	if result.Trials == nil {
+		result.Trials = []TrialInfo{}
+	}
+	if result.Evidence == nil {
+		result.Evidence = []Evidence{}
+	}
+
+	return result
+}
+
+// ToResult はMaxOutputResultをスキーマv2の結果に変換する
+func (r *MaxOutputResult) ToResult(gateway string) *Result {
+	result := &Result{
+		SchemaVersion: ResultSchemaVersion,
+		Type:          ProbeTypeMaxOutput,
+		Model:         r.Model,
+		Gateway:       gateway,
+		Value:         r.MaxOutputTokens,
+		Success:       r.Success,
+		Method:        MethodBoundarySearch,
+		Source:        r.Evidence,
+		Confidence:    r.Confidence.Score,
+		Level:         r.Confidence.Level(),
+		Evidence:      r.Confidence.Evidence,
+		TrialCount:    r.Trials,
+		Trials:        r.TrialHistory,
+		Throttling:    throttlingFromTrials(r.TrialHistory),
+		DurationMs:    r.Duration.Milliseconds(),
+		ProbedAt:      probedAt(r.TrialHistory, r.Duration),
+		ErrorMessage:  r.ErrorMessage,
+		InputTokens:   r.InputTokensUsed,
+	}
+
+	if r.Evidence == "validation_error" {
+		result.Method = MethodErrorMessage
+	}
+
+	if result.Trials == nil {
+		result.Trials = []TrialInfo{}
+	}
+	if result.Evidence == nil {
+		result.Evidence = []Evidence{}
+	}
+
+	return result
+}
+
+// ToResult はMaxInputResultをスキーマv2の結果に変換する
+func (r *MaxInputResult) ToResult(gateway string) *Result {
+	result := &Result{
+		SchemaVersion: ResultSchemaVersion,
+		Type:          ProbeTypeMaxInput,
+		Model:         r.Model,
+		Gateway:       gateway,
+		Value:         r.MaxInputTokens,
+		Success:       r.Success,
+		Method:        MethodBoundarySearch,
+		Source:        r.InputSource,
+		Confidence:    r.Confidence.Score,
+		Level:         r.Confidence.Level(),
+		Evidence:      r.Confidence.Evidence,
+		TrialCount:    r.Trials,
+		Trials:        r.TrialHistory,
+		Throttling:    throttlingFromTrials(r.TrialHistory),
+		DurationMs:    r.Duration.Milliseconds(),
+		ProbedAt:      probedAt(r.TrialHistory, r.Duration),
+		ErrorMessage:  r.ErrorMessage,
+		InputLimit: &InputLimitDetails{
+			CombinedLimit:  r.CombinedLimit,
+			CombinedSource: r.CombinedSource,
+			OutputReserve:  r.OutputReserve,
+			Independent:    r.Independent(),
+		},
+	}
+
+	if r.InputSource == "validation_error" {
+		result.Method = MethodErrorMessage
+	}
+
+	if result.Trials == nil {
+		result.Trials = []TrialInfo{}
+	}
+	if result.Evidence == nil {
+		result.Evidence = []Evidence{}
+	}
+
+	return result
+}
+
+// ApplyCost は試行ごとの使用量から実際に消費したトークン数とコストを計算する
+// calculatorがnilの場合はトークン数のみ集計する
+func (r *Result) ApplyCost(calculator *cost.Calculator) {
+	var spend Spend
+	total := 0.0
+	for _, trial := range r.Trials {
+		if trial.Usage == nil {
+			spend.TrialsWithoutUsage++
+			continue
+		}
+		spend.PromptTokens += trial.Usage.PromptTokens
+		spend.CompletionTokens += trial.Usage.CompletionTokens
+		if calculator != nil {
+			total += calculator.CalculateTrialCost(trial.Usage.PromptTokens, trial.Usage.CompletionTokens, r.Model)
+		}
+	}
+	r.Spend = spend
+	if calculator != nil {
+		r.CostSpent = total
+	}
+}
+
+// NewReport は探索結果からレポートを作成する（nilの結果は無視する）
+func NewReport(model, gateway string, results ...*Result) *Report {
+	report := &Report{
+		SchemaVersion: ResultSchemaVersion,
+		Model:         model,
+		Gateway:       gateway,
+		Results:       []*Result{},
+		Success:       true,
+		GeneratedAt:   time.Now(),
+	}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		report.Results = append(report.Results, result)
+		report.TotalTrials += result.TrialCount
+		report.TotalDurationMs += result.DurationMs
+		report.CostSpent += result.CostSpent
+		report.Spend.add(result.Spend)
+		report.Throttling = addThrottling(report.Throttling, result.Throttling)
+		if !result.Success {
+			report.Success = false
+		}
+	}
+
+	if len(report.Results) == 0 {
+		report.Success = false
+	}
+
+	return report
+}
+
+// SpendSummary は"this run cost ~$0.42, 183K tokens"の形式で実行全体の消費量を返す
+func (r *Report) SpendSummary() string {
+	costText := fmt.Sprintf("~$%.2f", r.CostSpent)
+	if r.CostSpent > 0 && r.CostSpent < 0.01 {
+		costText = fmt.Sprintf("~$%.4f", r.CostSpent)
+	}
+	si, _ := numfmt.New(numfmt.StyleSI, "")
+	summary := fmt.Sprintf("this run cost %s, %s tokens (%s prompt + %s completion)", costText,
+		si.Tokens(r.Spend.TotalTokens()), si.Tokens(r.Spend.PromptTokens), si.Tokens(r.Spend.CompletionTokens))
+	if r.Spend.TrialsWithoutUsage > 0 {
+		summary += fmt.Sprintf("; %d trial(s) reported no usage and are not counted", r.Spend.TrialsWithoutUsage)
+	}
+	return summary
+}
+
+// Find は指定された種類の探索結果を返す（存在しなければnil）
+func (r *Report) Find(probeType string) *Result {
+	for _, result := range r.Results {
+		if result.Type == probeType {
+			return result
+		}
+	}
+	return nil
+}
+
+// probedAt は探索の開始時刻を返す（試行履歴がなければ所要時間から逆算する）
+func probedAt(trials []TrialInfo, duration time.Duration) time.Time {
+	if len(trials) > 0 && !trials[0].StartedAt.IsZero() {
+		return trials[0].StartedAt
+	}
+	return time.Now().Add(-duration)
+}
@@ -0,0 +1,233 @@
+package probe
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/internal/cost"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestContextWindowResult_ToResult(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		input      *ContextWindowResult
+		wantMethod string
+		wantNeedle bool
+	}{
+		{
+			name: "boundary search",
+			input: &ContextWindowResult{
+				Model:            "gpt-4o",
+				MaxContextTokens: 128000,
+				Confidence:       Confidence{Score: 0.9},
+				Trials:           12,
+				Duration:         45 * time.Second,
+				Success:          true,
+				TrialHistory:     []TrialInfo{{TokenCount: 4096, Success: true, StartedAt: start}},
+			},
+			wantMethod: MethodBoundarySearch,
+		},
+		{
+			name: "validation error",
+			input: &ContextWindowResult{
+				Model:            "gpt-4o",
+				MaxContextTokens: 128000,
+				Source:           "validation_error",
+				Success:          true,
+			},
+			wantMethod: MethodErrorMessage,
+		},
+		{
+			name: "needle test",
+			input: &ContextWindowResult{
+				Model:          "gpt-4o",
+				NeedlePosition: Middle,
+				NeedleKeyword:  "keyword",
+				NeedleAnswer:   "answer",
+				Success:        true,
+			},
+			wantMethod: MethodNeedlePosition,
+			wantNeedle: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.input.ToResult("production")
+
+			if got.SchemaVersion != ResultSchemaVersion {
+				t.Errorf("SchemaVersion = %q, want %q", got.SchemaVersion, ResultSchemaVersion)
+			}
+			if got.Type != ProbeTypeContextWindow {
+				t.Errorf("Type = %q, want %q", got.Type, ProbeTypeContextWindow)
+			}
+			if got.Gateway != "production" {
+				t.Errorf("Gateway = %q, want production", got.Gateway)
+			}
+			if got.Method != tt.wantMethod {
+				t.Errorf("Method = %q, want %q", got.Method, tt.wantMethod)
+			}
+			if (got.Needle != nil) != tt.wantNeedle {
+				t.Errorf("Needle = %v, wantNeedle %v", got.Needle, tt.wantNeedle)
+			}
+			if got.Trials == nil {
+				t.Error("Trials should never be nil")
+			}
+			if len(tt.input.TrialHistory) > 0 && !got.ProbedAt.Equal(start) {
+				t.Errorf("ProbedAt = %v, want %v", got.ProbedAt, start)
+			}
+		})
+	}
+}
+
+func TestMaxOutputResult_ToResult(t *testing.T) {
+	input := &MaxOutputResult{
+		Model:           "gpt-4o",
+		MaxOutputTokens: 16384,
+		Confidence:      Confidence{Score: 0.9},
+		Trials:          3,
+		Duration:        1500 * time.Millisecond,
+		InputTokensUsed: 1000,
+		Evidence:        "validation_error",
+		Success:         true,
+	}
+
+	got := input.ToResult("")
+	if got.Type != ProbeTypeMaxOutput {
+		t.Errorf("Type = %q, want %q", got.Type, ProbeTypeMaxOutput)
+	}
+	if got.Value != 16384 {
+		t.Errorf("Value = %d, want 16384", got.Value)
+	}
+	if got.Method != MethodErrorMessage {
+		t.Errorf("Method = %q, want %q", got.Method, MethodErrorMessage)
+	}
+	if got.DurationMs != 1500 {
+		t.Errorf("DurationMs = %d, want 1500", got.DurationMs)
+	}
+	if got.InputTokens != 1000 {
+		t.Errorf("InputTokens = %d, want 1000", got.InputTokens)
+	}
+}
+
+func TestResult_ApplyCost(t *testing.T) {
+	calculator := cost.NewCalculator(&config.CostConfig{
+		Pricing: map[string]config.Pricing{
+			"gpt-4o": {InputPricePer1K: 0.01, OutputPricePer1K: 0.02},
+		},
+	}, "gpt-4o")
+
+	result := &Result{
+		Model: "gpt-4o",
+		Trials: []TrialInfo{
+			{Usage: &api.UsageInfo{PromptTokens: 1000, CompletionTokens: 1000}},
+			{Usage: nil},
+			{Usage: &api.UsageInfo{PromptTokens: 2000}},
+		},
+	}
+	result.ApplyCost(calculator)
+
+	want := 0.01 + 0.02 + 0.02
+	if diff := result.CostSpent - want; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("CostSpent = %f, want %f", result.CostSpent, want)
+	}
+	wantSpend := Spend{PromptTokens: 3000, CompletionTokens: 1000, TrialsWithoutUsage: 1}
+	if result.Spend != wantSpend {
+		t.Errorf("Spend = %+v, want %+v", result.Spend, wantSpend)
+	}
+}
+
+func TestReport_SpendSummary(t *testing.T) {
+	report := NewReport("gpt-4o", "production",
+		&Result{Type: ProbeTypeContextWindow, CostSpent: 0.3, Spend: Spend{PromptTokens: 150000, CompletionTokens: 3000}},
+		&Result{Type: ProbeTypeMaxOutput, CostSpent: 0.12, Spend: Spend{PromptTokens: 10000, CompletionTokens: 20000, TrialsWithoutUsage: 2}},
+	)
+
+	if report.Spend.TotalTokens() != 183000 {
+		t.Errorf("Spend.TotalTokens() = %d, want 183000", report.Spend.TotalTokens())
+	}
+	want := "this run cost ~$0.42, 183K tokens (160K prompt + 23K completion); 2 trial(s) reported no usage and are not counted"
+	if got := report.SpendSummary(); got != want {
+		t.Errorf("SpendSummary() = %q, want %q", got, want)
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	contextResult := &Result{Type: ProbeTypeContextWindow, Success: true, TrialCount: 10, DurationMs: 1000, CostSpent: 0.1}
+	outputResult := &Result{Type: ProbeTypeMaxOutput, Success: false, TrialCount: 5, DurationMs: 500, CostSpent: 0.05}
+
+	report := NewReport("gpt-4o", "production", contextResult, nil, outputResult)
+
+	if len(report.Results) != 2 {
+		t.Fatalf("Results length = %d, want 2", len(report.Results))
+	}
+	if report.TotalTrials != 15 {
+		t.Errorf("TotalTrials = %d, want 15", report.TotalTrials)
+	}
+	if report.TotalDurationMs != 1500 {
+		t.Errorf("TotalDurationMs = %d, want 1500", report.TotalDurationMs)
+	}
+	if report.Success {
+		t.Error("Success should be false when any result failed")
+	}
+	if report.Find(ProbeTypeMaxOutput) != outputResult {
+		t.Error("Find should return the max output result")
+	}
+
+	empty := NewReport("gpt-4o", "")
+	if empty.Success {
+		t.Error("empty report should not be successful")
+	}
+
+	// JSONとしてシリアライズできることを確認
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("failed to marshal report: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal report: %v", err)
+	}
+	if decoded["schema_version"] != ResultSchemaVersion {
+		t.Errorf("schema_version = %v, want %s", decoded["schema_version"], ResultSchemaVersion)
+	}
+}
+
+func TestResult_Throttling(t *testing.T) {
+	trials := []TrialInfo{
+		{TokenCount: 1000, Success: true, Latency: time.Second},
+		{TokenCount: 2000, Success: true, Latency: time.Second, Wait: 1500 * time.Millisecond},
+		{TokenCount: 4000, Success: false, Latency: 200 * time.Millisecond, Throttled: true},
+		{TokenCount: 3000, Success: true, Latency: time.Second, Throttled: true, Retries: 2, Backoff: 3 * time.Second},
+	}
+	contextResult := (&ContextWindowResult{Model: "gpt-4o", Success: true, TrialHistory: trials}).ToResult("production")
+	want := Throttling{RateLimitWaits: 1, RateLimitWaitMs: 1500, Throttled: 2, Retries: 2, BackoffMs: 3000}
+	if contextResult.Throttling == nil || *contextResult.Throttling != want {
+		t.Fatalf("Throttling = %+v, want %+v", contextResult.Throttling, want)
+	}
+
+	// レート制限がかからなかった探索はthrottlingを出力しない
+	outputResult := (&MaxOutputResult{Model: "gpt-4o", Success: true, TrialHistory: trials[:1]}).ToResult("production")
+	if outputResult.Throttling != nil {
+		t.Errorf("Throttling = %+v, want nil", outputResult.Throttling)
+	}
+	data, _ := json.Marshal(outputResult)
+	if strings.Contains(string(data), "throttling") || strings.Contains(string(data), "rate_limit_wait") {
+		t.Errorf("unthrottled result should omit throttling: %s", data)
+	}
+
+	report := NewReport("gpt-4o", "production", contextResult, outputResult, contextResult)
+	want = Throttling{RateLimitWaits: 2, RateLimitWaitMs: 3000, Throttled: 4, Retries: 4, BackoffMs: 6000}
+	if report.Throttling == nil || *report.Throttling != want {
+		t.Errorf("report Throttling = %+v, want %+v", report.Throttling, want)
+	}
+	if NewReport("gpt-4o", "production", outputResult).Throttling != nil {
+		t.Error("report without throttled trials should omit throttling")
+	}
+}
@@ -0,0 +1,166 @@
+package probe
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+// RoleCompatCase はメッセージのロールやフィールドの互換性を調べる1つのケース
+type RoleCompatCase struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Messages    []api.Message `json:"-"`
+	// ExpectReject は拒否されるのが望ましいケース（空のメッセージなど）
+	ExpectReject bool `json:"expect_reject,omitempty"`
+}
+
+// RoleCompatCases はプロンプトテンプレートで使うメッセージ構成の一覧
+var RoleCompatCases = []RoleCompatCase{
+	{
+		Name:        "user",
+		Description: "single user message (baseline)",
+		Messages:    []api.Message{{Role: "user", Content: "Reply with OK."}},
+	},
+	{
+		Name:        "system",
+		Description: "system message before user",
+		Messages: []api.Message{
+			{Role: "system", Content: "You are a helpful assistant."},
+			{Role: "user", Content: "Reply with OK."},
+		},
+	},
+	{
+		Name:        "developer",
+		Description: "developer message before user",
+		Messages: []api.Message{
+			{Role: "developer", Content: "You are a helpful assistant."},
+			{Role: "user", Content: "Reply with OK."},
+		},
+	},
+	{
+		Name:        "multiple_system",
+		Description: "two system messages before user",
+		Messages: []api.Message{
+			{Role: "system", Content: "You are a helpful assistant."},
+			{Role: "system", Content: "Answer in one word."},
+			{Role: "user", Content: "Reply with OK."},
+		},
+	},
+	{
+		Name:        "system_after_user",
+		Description: "system message after the first user message",
+		Messages: []api.Message{
+			{Role: "user", Content: "Reply with OK."},
+			{Role: "system", Content: "Answer in one word."},
+		},
+	},
+	{
+		Name:        "name_field",
+		Description: "user message with a name field",
+		Messages:    []api.Message{{Role: "user", Content: "Reply with OK.", Name: "tester"}},
+	},
+	{
+		Name:        "assistant_prefill",
+		Description: "conversation ending with an assistant message",
+		Messages: []api.Message{
+			{Role: "user", Content: "Reply with OK."},
+			{Role: "assistant", Content: "O"},
+		},
+	},
+	{
+		Name:         "empty_user",
+		Description:  "user message with empty content",
+		Messages:     []api.Message{{Role: "user", Content: ""}},
+		ExpectReject: true,
+	},
+	{
+		Name:        "empty_system",
+		Description: "system message with empty content",
+		Messages: []api.Message{
+			{Role: "system", Content: ""},
+			{Role: "user", Content: "Reply with OK."},
+		},
+	},
+}
+
+// RoleCompatResult は1つのケースの結果
+type RoleCompatResult struct {
+	Case         string        `json:"case"`
+	Description  string        `json:"description"`
+	Accepted     bool          `json:"accepted"`
+	ExpectReject bool          `json:"expect_reject,omitempty"`
+	Error        string        `json:"error,omitempty"`
+	Latency      time.Duration `json:"latency"`
+}
+
+// RoleCompatReport は1つのモデルに対する互換性の結果
+type RoleCompatReport struct {
+	Model    string             `json:"model"`
+	Gateway  string             `json:"gateway,omitempty"`
+	Results  []RoleCompatResult `json:"results"`
+	ProbedAt time.Time          `json:"probed_at"`
+	Error    string             `json:"error,omitempty"` // 探索自体が失敗した場合のエラー（--continue-on-error）
+}
+
+// Accepted は指定したケースが受け付けられたかを返す
+func (r *RoleCompatReport) Accepted(name string) (bool, bool) {
+	for _, result := range r.Results {
+		if result.Case == name {
+			return result.Accepted, true
+		}
+	}
+	return false, false
+}
+
+// RoleCompatProbe はロールやメッセージの制約を調べる
+type RoleCompatProbe struct {
+	client *api.ProbeClient
+	cases  []RoleCompatCase
+	delay  time.Duration // リクエスト間の待機（レート制限対策）
+}
+
+// NewRoleCompatProbe は新しいRoleCompatProbeを作成する
+func NewRoleCompatProbe(client *api.ProbeClient) *RoleCompatProbe {
+	return &RoleCompatProbe{
+		client: client,
+		cases:  RoleCompatCases,
+		delay:  200 * time.Millisecond,
+	}
+}
+
+// Probe は各ケースのリクエストを送信し、受け付けられたかを記録する
+// ベースライン（userのみ）が失敗した場合は接続や認証の問題とみなしてエラーを返す
+func (p *RoleCompatProbe) Probe(model, gateway string) (*RoleCompatReport, error) {
+	report := &RoleCompatReport{
+		Model:    model,
+		Gateway:  gateway,
+		ProbedAt: time.Now(),
+	}
+
+	for i, c := range p.cases {
+		if i > 0 && p.delay > 0 {
+			time.Sleep(p.delay)
+		}
+
+		start := time.Now()
+		_, err := p.client.ProbeMessages(model, c.Messages, 16)
+		result := RoleCompatResult{
+			Case:         c.Name,
+			Description:  c.Description,
+			Accepted:     err == nil,
+			ExpectReject: c.ExpectReject,
+			Latency:      time.Since(start),
+		}
+		if err != nil {
+			result.Error = err.Error()
+			if i == 0 {
+				return nil, fmt.Errorf("baseline request failed: %w", err)
+			}
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,103 @@
+package probe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// roleServer はdeveloperロールと空のメッセージを拒否するゲートウェイを模したテストサーバー
+func roleServer(t *testing.T, failAll bool) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req api.ProbeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			return
+		}
+
+		reject := func(message string) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(api.ProbeResponse{Error: &api.OpenAIError{Type: "invalid_request_error", Message: message}})
+		}
+		if failAll {
+			reject("invalid api key")
+			return
+		}
+		for _, message := range req.Messages {
+			if message.Role == "developer" {
+				reject("unsupported role: developer")
+				return
+			}
+			if message.Content == "" {
+				reject("message content must not be empty")
+				return
+			}
+		}
+
+		json.NewEncoder(w).Encode(api.ProbeResponse{
+			Choices: []api.ChatChoice{{FinishReason: "stop"}},
+			Usage:   &api.UsageInfo{PromptTokens: 10, CompletionTokens: 1, TotalTokens: 11},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestRoleCompatProbe(server *httptest.Server) *RoleCompatProbe {
+	p := NewRoleCompatProbe(api.NewProbeClient(&config.AppConfig{
+		BaseURL: server.URL,
+		APIKey:  "test",
+		Timeout: 5 * time.Second,
+	}))
+	p.delay = 0
+	return p
+}
+
+func TestRoleCompatProbe_Probe(t *testing.T) {
+	report, err := newTestRoleCompatProbe(roleServer(t, false)).Probe("test-model", "production")
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if len(report.Results) != len(RoleCompatCases) {
+		t.Fatalf("got %d results, want %d", len(report.Results), len(RoleCompatCases))
+	}
+
+	want := map[string]bool{
+		"user":            true,
+		"system":          true,
+		"developer":       false,
+		"multiple_system": true,
+		"name_field":      true,
+		"empty_user":      false,
+		"empty_system":    false,
+	}
+	for name, accepted := range want {
+		got, ok := report.Accepted(name)
+		if !ok {
+			t.Errorf("missing result for %s", name)
+			continue
+		}
+		if got != accepted {
+			t.Errorf("Accepted(%s) = %v, want %v", name, got, accepted)
+		}
+	}
+
+	for _, result := range report.Results {
+		if result.Case == "developer" && result.Error == "" {
+			t.Error("rejected case should keep the error message")
+		}
+	}
+}
+
+func TestRoleCompatProbe_BaselineFailure(t *testing.T) {
+	if _, err := newTestRoleCompatProbe(roleServer(t, true)).Probe("test-model", ""); err == nil {
+		t.Error("Probe() should fail when the baseline request is rejected")
+	}
+}
@@ -0,0 +1,311 @@
+package probe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Strategy はコンテキストウィンドウの探索手法
+// 試行の送信や履歴の記録はContextWindowProbeが行い、戦略はどのトークン数で試行するかだけを決める
+type Strategy interface {
+	// Name は--strategyで指定する名前を返す
+	Name() string
+	// Plan はdry-runで表示する探索の手順を返す
+	Plan() []string
+	// Search は探索を実行し、推定値を返す
+	// Successがfalseの場合は上限が特定できなかったことを表す
+	Search(env *StrategyEnv) (*BoundarySearchResult, error)
+}
+
+// StrategyEnv は探索戦略に渡される探索環境
+type StrategyEnv struct {
+	Model    string
+	Searcher *BoundarySearcher
+	// Trial は指定したトークン数の入力で1回試行する
+	Trial func(tokens int) (*BoundarySearchResult, error)
+	// Validate は入力トークン数とmax_tokensを指定し、検証エラーを狙ったリクエストを1回送信する
+	Validate func(tokens, maxTokens int) *BoundarySearchResult
+}
+
+// logInfo はverboseロガーが設定されていればメッセージを出力する
+func (env *StrategyEnv) logInfo(message string) {
+	if env.Searcher.verbose != nil {
+		env.Searcher.verbose.LogInfo(message)
+	}
+}
+
+// StrategyOptions は探索戦略の設定
+type StrategyOptions struct {
+	ClaimedLimit int   // bisect-claimed: 公称のコンテキストウィンドウ
+	Candidates   []int // fixed-list: 検証する候補値（空ならDefaultCandidates）
+}
+
+// StrategyFactory は設定から探索戦略を作成する
+type StrategyFactory func(opts StrategyOptions) (Strategy, error)
+
+// 登録済みの探索戦略（名前は登録順に並べる）
+var (
+	strategyFactories = map[string]StrategyFactory{}
+	strategyNames     []string
+)
+
+// RegisterStrategy は探索戦略を登録する
+func RegisterStrategy(name string, factory StrategyFactory) {
+	if _, exists := strategyFactories[name]; !exists {
+		strategyNames = append(strategyNames, name)
+	}
+	strategyFactories[name] = factory
+}
+
+// StrategyNames は登録済みの探索戦略の名前を返す
+func StrategyNames() []string {
+	names := make([]string, len(strategyNames))
+	copy(names, strategyNames)
+	return names
+}
+
+// NewStrategy は名前から探索戦略を作成する
+func NewStrategy(name string, opts StrategyOptions) (Strategy, error) {
+	factory, ok := strategyFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("invalid strategy '%s'. Valid values: %s", name, strings.Join(strategyNames, ", "))
+	}
+	return factory(opts)
+}
+
+// コンテキストウィンドウの探索戦略
+const (
+	StrategySearch        = "search"         // 指数探索と二分探索（デフォルト）
+	StrategyErrorFirst    = "error-first"    // 検証エラーから上限を読み取り、失敗したら探索する
+	StrategyBisectClaimed = "bisect-claimed" // 公称値を起点に二分探索する
+	StrategyFixedList     = "fixed-list"     // よくあるウィンドウサイズを大きい順に検証する
+)
+
+// DefaultCandidates はfixed-listで検証するよくあるコンテキストウィンドウのサイズ
+var DefaultCandidates = []int{4096, 8192, 16384, 32768, 65536, 128000, 131072, 200000, 262144, 1000000, 1048576}
+
+func init() {
+	RegisterStrategy(StrategySearch, func(StrategyOptions) (Strategy, error) {
+		return SearchStrategy{}, nil
+	})
+	RegisterStrategy(StrategyErrorFirst, func(StrategyOptions) (Strategy, error) {
+		return ErrorFirstStrategy{Fallback: SearchStrategy{}}, nil
+	})
+	RegisterStrategy(StrategyBisectClaimed, func(opts StrategyOptions) (Strategy, error) {
+		if opts.ClaimedLimit <= 0 {
+			return nil, fmt.Errorf("strategy %s requires a claimed limit (--claimed-limit)", StrategyBisectClaimed)
+		}
+		return BisectClaimedStrategy{Claimed: opts.ClaimedLimit}, nil
+	})
+	RegisterStrategy(StrategyFixedList, func(opts StrategyOptions) (Strategy, error) {
+		candidates := opts.Candidates
+		if len(candidates) == 0 {
+			candidates = DefaultCandidates
+		}
+		return FixedListStrategy{Candidates: candidates}, nil
+	})
+}
+
+// SearchStrategy は指数探索で上限を見つけ、二分探索で境界を絞る
+type SearchStrategy struct{}
+
+// Name は戦略の名前を返す
+func (SearchStrategy) Name() string { return StrategySearch }
+
+// Plan は探索の手順を返す
+func (SearchStrategy) Plan() []string {
+	return []string{
+		"Exponential Search: Find upper bound by doubling token count",
+		"Binary Search: Refine boundary within ±1024 tokens",
+		"Error Analysis: Extract token limits from error messages",
+	}
+}
+
+// Search は探索を実行する
+func (SearchStrategy) Search(env *StrategyEnv) (*BoundarySearchResult, error) {
+	// 第1段階: 指数探索で上限を特定
+	upperLimit, err := env.Searcher.ExponentialSearch(env.Trial)
+	if err != nil {
+		return nil, fmt.Errorf("exponential search phase failed: %w", err)
+	}
+
+	// 上限が見つからなかった場合
+	if !upperLimit.Success {
+		return &BoundarySearchResult{
+			Value:        upperLimit.Value,
+			ErrorMessage: upperLimit.ErrorMessage,
+			Trials:       upperLimit.Trials,
+		}, nil
+	}
+
+	// 値がエラーメッセージから抽出された場合
+	if tokenLimit, found := env.Searcher.ExtractTokenLimitFromError(upperLimit.ErrorMessage); found {
+		return &BoundarySearchResult{
+			Value:        tokenLimit,
+			Success:      true,
+			ErrorMessage: upperLimit.ErrorMessage,
+			Source:       "validation_error",
+			Trials:       upperLimit.Trials,
+		}, nil
+	}
+
+	// 第2段階: 二分探索で境界を絞る
+	boundaryResult, err := env.Searcher.Search(upperLimit.Value-1024, upperLimit.Value+1024, env.Trial)
+	if err != nil {
+		return nil, fmt.Errorf("binary search phase failed: %w", err)
+	}
+
+	return &BoundarySearchResult{
+		Value:   boundaryResult.Value,
+		Success: true,
+		Trials:  upperLimit.Trials + boundaryResult.Trials + 1,
+	}, nil
+}
+
+// BisectClaimedStrategy は公称値（モデル一覧やドキュメントの値）を起点に境界を探す
+// 公称値が正しければ数回の試行で確認でき、指数探索の大半を省略できる
+type BisectClaimedStrategy struct {
+	Claimed int
+}
+
+// claimedMargin は公称値を超えて受け付けられるかを確認する幅
+const claimedMargin = 1024
+
+// Name は戦略の名前を返す
+func (BisectClaimedStrategy) Name() string { return StrategyBisectClaimed }
+
+// Plan は探索の手順を返す
+func (s BisectClaimedStrategy) Plan() []string {
+	return []string{
+		fmt.Sprintf("Claimed Limit: Try the claimed limit (%d tokens)", s.Claimed),
+		fmt.Sprintf("Overshoot Check: If accepted, try %d tokens above the claim", claimedMargin),
+		"Binary Search: Bisect between half the claim and the claim if rejected, or above the claim if exceeded",
+		"Error Analysis: Extract token limits from error messages",
+	}
+}
+
+// Search は探索を実行する
+func (s BisectClaimedStrategy) Search(env *StrategyEnv) (*BoundarySearchResult, error) {
+	result, err := env.Trial(s.Claimed)
+	if err != nil {
+		return nil, err
+	}
+	trials := 1
+	if limit, ok := validationLimit(result); ok {
+		return limit.withTrials(trials), nil
+	}
+
+	var lower, upper int
+	if !result.Success {
+		// 公称値より小さい
+		env.logInfo(fmt.Sprintf("Claimed limit %d was rejected, bisecting below it", s.Claimed))
+		lower, upper = s.Claimed/2, s.Claimed
+	} else {
+		over, err := env.Trial(s.Claimed + claimedMargin)
+		if err != nil {
+			return nil, err
+		}
+		trials++
+		if limit, ok := validationLimit(over); ok {
+			return limit.withTrials(trials), nil
+		}
+		if over.Success {
+			// 公称値より大きい（2倍を超える場合は2倍を下限として返す）
+			env.logInfo(fmt.Sprintf("Claimed limit %d was exceeded, bisecting above it", s.Claimed))
+			lower, upper = s.Claimed+claimedMargin, s.Claimed*2
+		} else {
+			lower, upper = s.Claimed, s.Claimed+claimedMargin
+		}
+	}
+
+	boundaryResult, err := env.Searcher.Search(lower, upper, env.Trial)
+	if err != nil {
+		return nil, fmt.Errorf("binary search phase failed: %w", err)
+	}
+	return &BoundarySearchResult{
+		Value:        boundaryResult.Value,
+		Success:      boundaryResult.Success,
+		ErrorMessage: boundaryResult.ErrorMessage,
+		Trials:       trials + boundaryResult.Trials,
+	}, nil
+}
+
+// FixedListStrategy はよくあるウィンドウサイズを大きい順に試し、最初に受け付けられた値を返す
+// 拒否された試行は通常課金されないため、小さい順に試すより安く済む
+type FixedListStrategy struct {
+	Candidates []int
+}
+
+// Name は戦略の名前を返す
+func (FixedListStrategy) Name() string { return StrategyFixedList }
+
+// Plan は探索の手順を返す
+func (s FixedListStrategy) Plan() []string {
+	return []string{
+		fmt.Sprintf("Fixed List: Try %s tokens, largest first", joinInts(s.sorted())),
+		"Stop at the first accepted size, or at a validation error that reports the limit",
+	}
+}
+
+// sorted は候補値を大きい順に並べて返す
+func (s FixedListStrategy) sorted() []int {
+	candidates := make([]int, len(s.Candidates))
+	copy(candidates, s.Candidates)
+	sort.Sort(sort.Reverse(sort.IntSlice(candidates)))
+	return candidates
+}
+
+// Search は探索を実行する
+func (s FixedListStrategy) Search(env *StrategyEnv) (*BoundarySearchResult, error) {
+	var lastError string
+	trials := 0
+	for i, tokens := range s.sorted() {
+		if i > 0 {
+			// API呼び出し間の待機（レート制限対策）
+			time.Sleep(env.Searcher.delay)
+		}
+		result, err := env.Trial(tokens)
+		if err != nil {
+			return nil, err
+		}
+		trials++
+		if limit, ok := validationLimit(result); ok {
+			return limit.withTrials(trials), nil
+		}
+		if result.Success {
+			return &BoundarySearchResult{Value: tokens, Success: true, Source: "fixed_list", Trials: trials}, nil
+		}
+		lastError = result.ErrorMessage
+	}
+	return &BoundarySearchResult{ErrorMessage: fmt.Sprintf("no candidate size was accepted: %s", lastError), Trials: trials}, nil
+}
+
+// validationLimit は試行結果が上限を含む検証エラーであれば、その上限を推定値として返す
+func validationLimit(result *BoundarySearchResult) (*BoundarySearchResult, bool) {
+	if result == nil || result.Source != "validation_error" || result.Value <= 0 {
+		return nil, false
+	}
+	return &BoundarySearchResult{
+		Value:        result.Value,
+		Success:      true,
+		ErrorMessage: result.ErrorMessage,
+		Source:       "validation_error",
+	}, true
+}
+
+// withTrials は試行回数を設定して返す
+func (r *BoundarySearchResult) withTrials(trials int) *BoundarySearchResult {
+	r.Trials = trials
+	return r
+}
+
+// joinInts は整数のリストをカンマ区切りの文字列にする
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%d", v)
+	}
+	return strings.Join(parts, ", ")
+}
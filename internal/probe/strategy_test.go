@@ -0,0 +1,94 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// limitEnv はlimitトークンまで受け付ける試行を持つ探索環境を作成する
+// limitMessageを指定すると、拒否時にその検証エラーを返す
+func limitEnv(limit int, limitMessage string) (*StrategyEnv, *[]int) {
+	var tried []int
+	searcher := NewBoundarySearcher()
+	env := &StrategyEnv{
+		Model:    "test-model",
+		Searcher: searcher,
+		Trial: func(tokens int) (*BoundarySearchResult, error) {
+			tried = append(tried, tokens)
+			if tokens <= limit {
+				return &BoundarySearchResult{Value: tokens, Success: true, Source: "success"}, nil
+			}
+			if value, found := searcher.ExtractTokenLimitFromError(limitMessage); found {
+				return &BoundarySearchResult{Value: value, ErrorMessage: limitMessage, Source: "validation_error"}, nil
+			}
+			return &BoundarySearchResult{ErrorMessage: "context length exceeded"}, nil
+		},
+	}
+	return env, &tried
+}
+
+func TestNewStrategy(t *testing.T) {
+	for _, name := range []string{StrategySearch, StrategyErrorFirst, StrategyBisectClaimed, StrategyFixedList} {
+		found := false
+		for _, registered := range StrategyNames() {
+			found = found || registered == name
+		}
+		if !found {
+			t.Errorf("StrategyNames() does not contain %s", name)
+		}
+	}
+
+	if _, err := NewStrategy("unknown", StrategyOptions{}); err == nil || !strings.Contains(err.Error(), "search") {
+		t.Errorf("NewStrategy(unknown) error = %v, want list of valid values", err)
+	}
+	if _, err := NewStrategy(StrategyBisectClaimed, StrategyOptions{}); err == nil {
+		t.Error("NewStrategy(bisect-claimed) without claimed limit should fail")
+	}
+
+	strategy, err := NewStrategy(StrategyFixedList, StrategyOptions{})
+	if err != nil {
+		t.Fatalf("NewStrategy(fixed-list) error = %v", err)
+	}
+	if got := len(strategy.(FixedListStrategy).Candidates); got != len(DefaultCandidates) {
+		t.Errorf("fixed-list candidates = %d, want defaults (%d)", got, len(DefaultCandidates))
+	}
+}
+
+func TestBisectClaimedStrategy(t *testing.T) {
+	t.Run("claim confirmed", func(t *testing.T) {
+		env, _ := limitEnv(100000, "")
+		result, err := BisectClaimedStrategy{Claimed: 100000}.Search(env)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if !result.Success || result.Value < 100000-128 || result.Value > 100000 {
+			t.Errorf("Search() = %d (success=%v), want ~100000", result.Value, result.Success)
+		}
+	})
+
+	t.Run("limit in validation error", func(t *testing.T) {
+		env, tried := limitEnv(96000, "This model's maximum context length is 96000 tokens")
+		result, err := BisectClaimedStrategy{Claimed: 128000}.Search(env)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if result.Value != 96000 || result.Source != "validation_error" || len(*tried) != 1 {
+			t.Errorf("Search() = %d (%s) after %d trials, want 96000 from validation_error after 1", result.Value, result.Source, len(*tried))
+		}
+	})
+}
+
+func TestFixedListStrategy(t *testing.T) {
+	env, tried := limitEnv(131072, "")
+	result, err := FixedListStrategy{Candidates: []int{8192, 200000, 131072}}.Search(env)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if !result.Success || result.Value != 131072 {
+		t.Errorf("Search() = %d (success=%v), want 131072", result.Value, result.Success)
+	}
+	if got := fmt.Sprint(*tried); got != "[200000 131072]" {
+		t.Errorf("tried = %s, want largest first and stop at first accepted", got)
+	}
+}
@@ -0,0 +1,142 @@
+package probe
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+// TokenizerSample は言語ごとのトークン数の測定に使う固定のテキスト
+type TokenizerSample struct {
+	Language string
+	Text     string
+}
+
+// TokenizerSamples は測定に使う英語、日本語、中国語、コードのテキスト
+// 結果を比較できるよう、どのモデルにも同じ内容を送る（内容はおおむね同じ意味の文章）
+var TokenizerSamples = []TokenizerSample{
+	{"en", "Large language models read text as tokens rather than characters. " +
+		"A tokenizer splits the input into pieces that appear often in its training data, " +
+		"so common English words usually become a single token, while rare words are split into several. " +
+		"The number of tokens decides how much text fits in the context window and how much a request costs, " +
+		"which is why the same document can be cheap in one language and expensive in another."},
+	{"ja", "大規模言語モデルは、テキストを文字ではなくトークンとして読み込みます。" +
+		"トークナイザーは入力を学習データによく現れる断片に分割するため、英語のよく使われる単語はたいてい1つのトークンになり、" +
+		"まれな単語は複数に分割されます。トークン数によって、コンテキストウィンドウに収まるテキストの量とリクエストの料金が決まります。" +
+		"そのため、同じ文書でもある言語では安く、別の言語では高くなることがあります。"},
+	{"zh", "大型语言模型以词元而不是字符来读取文本。" +
+		"分词器会把输入切分成在训练数据中经常出现的片段，因此常见的英语单词通常是一个词元，而罕见的单词会被切分成多个。" +
+		"词元的数量决定了上下文窗口能容纳多少文本以及请求的费用。" +
+		"因此，同一份文档在一种语言中可能很便宜，而在另一种语言中却很昂贵。"},
+	{"code", "func countTokens(samples []string, limit int) (map[string]int, error) {\n" +
+		"\tcounts := make(map[string]int, len(samples))\n" +
+		"\tfor i, sample := range samples {\n" +
+		"\t\tif len(sample) > limit {\n" +
+		"\t\t\treturn nil, fmt.Errorf(\"sample %d is too long: %d > %d\", i, len(sample), limit)\n" +
+		"\t\t}\n" +
+		"\t\tcounts[sample] = len(strings.Fields(sample))\n" +
+		"\t}\n" +
+		"\treturn counts, nil\n" +
+		"}\n"},
+}
+
+// TokenizerLanguageResult は1つの言語のテキストで報告されたトークン数と文字あたりのトークン数
+type TokenizerLanguageResult struct {
+	Language      string  `json:"language"`
+	Characters    int     `json:"characters"`      // Unicodeの文字数
+	PromptTokens  int     `json:"prompt_tokens"`   // usage.prompt_tokens
+	Tokens        int     `json:"tokens"`          // テンプレートなどを除いたテキストのトークン数
+	TokensPerChar float64 `json:"tokens_per_char"` // Tokens / Characters
+	RelativeToEN  float64 `json:"relative_to_en"`  // 英語の文字あたりのトークン数との比（英語がなければ0）
+}
+
+// TokenizerReport はモデルのトークナイザーの言語ごとの効率の測定結果
+type TokenizerReport struct {
+	Model          string                    `json:"model"`
+	Gateway        string                    `json:"gateway,omitempty"`
+	Endpoint       string                    `json:"endpoint"`
+	BaselineTokens int                       `json:"baseline_tokens"` // 1トークンのプロンプトで報告されたprompt_tokensから1を引いた値
+	Languages      []TokenizerLanguageResult `json:"languages"`
+	ProbedAt       time.Time                 `json:"probed_at"`
+	Error          string                    `json:"error,omitempty"` // 探索自体が失敗した場合のエラー（--continue-on-error）
+}
+
+// TokenizerProbe は固定のテキストを送り、usage.prompt_tokensから言語ごとの文字あたりのトークン数を求める
+type TokenizerProbe struct {
+	client *api.ProbeClient
+	delay  time.Duration // リクエスト間の待機（レート制限対策）
+}
+
+// NewTokenizerProbe は新しいTokenizerProbeを作成する
+func NewTokenizerProbe(client *api.ProbeClient) *TokenizerProbe {
+	return &TokenizerProbe{
+		client: client,
+		delay:  200 * time.Millisecond,
+	}
+}
+
+// Probe は1トークンのプロンプトで本文以外のトークン数（テンプレートや追加されたプロンプト）を測定してから、
+// 各言語のテキストを送り、報告されたprompt_tokensからその分を引いてテキストのトークン数を求める
+func (p *TokenizerProbe) Probe(model, gateway string) (*TokenizerReport, error) {
+	report := &TokenizerReport{
+		Model:    model,
+		Gateway:  gateway,
+		ProbedAt: time.Now(),
+	}
+
+	baseline, err := p.promptTokens(model, overheadWord)
+	if err != nil {
+		return nil, fmt.Errorf("baseline request failed: %w", err)
+	}
+	report.Endpoint = p.client.Endpoint(model)
+	report.BaselineTokens = baseline - 1
+
+	for _, sample := range TokenizerSamples {
+		if p.delay > 0 {
+			time.Sleep(p.delay)
+		}
+		promptTokens, err := p.promptTokens(model, sample.Text)
+		if err != nil {
+			return nil, fmt.Errorf("request with the %s sample failed: %w", sample.Language, err)
+		}
+		result := TokenizerLanguageResult{
+			Language:     sample.Language,
+			Characters:   utf8.RuneCountInString(sample.Text),
+			PromptTokens: promptTokens,
+			Tokens:       max(promptTokens-report.BaselineTokens, 0),
+		}
+		result.TokensPerChar = float64(result.Tokens) / float64(result.Characters)
+		report.Languages = append(report.Languages, result)
+	}
+
+	if en := report.Language("en"); en != nil && en.TokensPerChar > 0 {
+		for i := range report.Languages {
+			report.Languages[i].RelativeToEN = report.Languages[i].TokensPerChar / en.TokensPerChar
+		}
+	}
+	return report, nil
+}
+
+// promptTokens はテキストを1つのuserメッセージとして送り、報告されたprompt_tokensを返す
+func (p *TokenizerProbe) promptTokens(model, text string) (int, error) {
+	response, err := p.client.ProbeMessages(model, []api.Message{{Role: "user", Content: text}}, 16)
+	if err != nil {
+		return 0, err
+	}
+	if response.Usage == nil || response.Usage.PromptTokens == 0 {
+		return 0, fmt.Errorf("the gateway did not report usage.prompt_tokens")
+	}
+	return response.Usage.PromptTokens, nil
+}
+
+// Language は指定した言語の結果を返す（なければnil）
+func (r *TokenizerReport) Language(language string) *TokenizerLanguageResult {
+	for i := range r.Languages {
+		if r.Languages[i].Language == language {
+			return &r.Languages[i]
+		}
+	}
+	return nil
+}
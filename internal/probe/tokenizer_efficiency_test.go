@@ -0,0 +1,67 @@
+package probe
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestTokenizerProbe_Probe(t *testing.T) {
+	// ASCIIは4文字で1トークン、それ以外は1文字で1トークン、テンプレートは7トークンとして報告する
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req api.ProbeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			return
+		}
+		ascii, other := 0, 0
+		for _, c := range req.Messages[0].Content {
+			if c < utf8.RuneSelf {
+				ascii++
+			} else {
+				other++
+			}
+		}
+		tokens := 7 + (ascii+3)/4 + other
+		if req.Messages[0].Content == overheadWord {
+			tokens = 8
+		}
+		json.NewEncoder(w).Encode(api.ProbeResponse{
+			Choices: []api.ChatChoice{{FinishReason: "stop"}},
+			Usage:   &api.UsageInfo{PromptTokens: tokens, CompletionTokens: 1},
+		})
+	}))
+	defer server.Close()
+
+	p := NewTokenizerProbe(api.NewProbeClient(&config.AppConfig{BaseURL: server.URL, APIKey: "test", Timeout: 5 * time.Second}))
+	p.delay = 0
+
+	report, err := p.Probe("test-model", "production")
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if report.BaselineTokens != 7 {
+		t.Errorf("BaselineTokens = %d, want 7", report.BaselineTokens)
+	}
+	if len(report.Languages) != len(TokenizerSamples) {
+		t.Fatalf("len(Languages) = %d, want %d", len(report.Languages), len(TokenizerSamples))
+	}
+
+	en, ja := report.Language("en"), report.Language("ja")
+	if math.Abs(en.TokensPerChar-0.25) > 0.01 {
+		t.Errorf("en TokensPerChar = %.3f, want about 0.25", en.TokensPerChar)
+	}
+	if en.RelativeToEN != 1 {
+		t.Errorf("en RelativeToEN = %.2f, want 1", en.RelativeToEN)
+	}
+	if ja.TokensPerChar <= en.TokensPerChar || ja.RelativeToEN <= 1 {
+		t.Errorf("ja TokensPerChar = %.3f (x%.2f), want more tokens per character than English", ja.TokensPerChar, ja.RelativeToEN)
+	}
+}
@@ -0,0 +1,107 @@
+package probe
+
+import (
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+// trialRecorder は探索中の各試行を記録する
+type trialRecorder struct {
+	trials   []TrialInfo
+	evidence []Evidence
+}
+
+// reset は記録済みの試行履歴を破棄する
+func (r *trialRecorder) reset() {
+	r.trials = nil
+	r.evidence = nil
+}
+
+// record は1回分の試行結果を履歴に追加する
+// throttlingは試行中のレート制限と再送の集計で、待った時間はレイテンシから除く
+func (r *trialRecorder) record(tokens int, startedAt time.Time, latency time.Duration, throttling api.Throttling, response *api.ProbeResponse, result *BoundarySearchResult) {
+	trial := TrialInfo{
+		TokenCount: tokens,
+		StartedAt:  startedAt,
+		EndedAt:    time.Now(),
+		Latency:    max(latency-throttling.Wait-throttling.Backoff, 0),
+		Wait:       throttling.Wait,
+		Throttled:  throttling.Throttled > 0,
+		Retries:    int(throttling.Retries),
+		Backoff:    throttling.Backoff,
+	}
+
+	if result != nil {
+		trial.Success = result.Success
+		trial.Message = result.ErrorMessage
+		if trial.Message == "" {
+			trial.Message = result.Source
+		}
+	}
+
+	if response != nil && response.Usage != nil {
+		usage := *response.Usage
+		trial.Usage = &usage
+	}
+
+	r.trials = append(r.trials, trial)
+
+	if e, ok := evidenceFromResult(tokens, response, result); ok {
+		r.evidence = append(r.evidence, e)
+	}
+}
+
+// addEvidence は試行結果以外から得た根拠を追加する
+func (r *trialRecorder) addEvidence(evidence ...Evidence) {
+	r.evidence = append(r.evidence, evidence...)
+}
+
+// history は記録済みの試行履歴のコピーを返す
+func (r *trialRecorder) history() []TrialInfo {
+	if len(r.trials) == 0 {
+		return nil
+	}
+	history := make([]TrialInfo, len(r.trials))
+	copy(history, r.trials)
+	return history
+}
+
+// evidenceList は記録済みの根拠リストのコピーを返す
+func (r *trialRecorder) evidenceList() []Evidence {
+	evidence := make([]Evidence, len(r.evidence))
+	copy(evidence, r.evidence)
+	return evidence
+}
+
+// usageDiscrepancies は記録済みの根拠のうちusageの矛盾を返す
+func (r *trialRecorder) usageDiscrepancies() []Evidence {
+	var found []Evidence
+	for _, e := range r.evidence {
+		if e.Kind == EvidenceUsageMismatch {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// evidenceFromResult は1回の試行結果から信頼度の根拠を抽出する
+func evidenceFromResult(tokens int, response *api.ProbeResponse, result *BoundarySearchResult) (Evidence, bool) {
+	if result == nil {
+		return Evidence{}, false
+	}
+
+	switch {
+	case result.Source == "validation_error" && result.Value > 0:
+		return Evidence{Kind: EvidenceValidationError, TokenCount: result.Value, Detail: result.ErrorMessage, Pattern: matchedPattern(result.ErrorMessage, result.Value)}, true
+	case result.Source == "body_too_large":
+		return Evidence{Kind: EvidenceBodyTooLarge, TokenCount: tokens, Detail: result.ErrorMessage}, true
+	case result.Source == "max_output_incomplete":
+		return Evidence{Kind: EvidenceFinishReasonLength, TokenCount: result.Value}, true
+	case result.Success && response != nil && response.Usage != nil && result.Value > 0:
+		return Evidence{Kind: EvidenceUsageConfirmed, TokenCount: result.Value}, true
+	case !result.Success:
+		return Evidence{Kind: EvidenceRejected, TokenCount: tokens, Detail: result.ErrorMessage}, true
+	}
+	return Evidence{}, false
+}
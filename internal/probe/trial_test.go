@@ -0,0 +1,74 @@
+package probe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+func TestTrialRecorder_Record(t *testing.T) {
+	var recorder trialRecorder
+
+	start := time.Now().Add(-2 * time.Second)
+	response := &api.ProbeResponse{
+		Usage: &api.UsageInfo{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+	}
+
+	// 成功した試行
+	recorder.record(4096, start, 1500*time.Millisecond, api.Throttling{}, response, &BoundarySearchResult{Success: true, Source: "success"})
+	// 失敗した試行（レスポンスなし、レート制限で待ったうえで429を返された）
+	throttling := api.Throttling{Waits: 1, Wait: 300 * time.Millisecond, Throttled: 2, Retries: 1, Backoff: 200 * time.Millisecond}
+	recorder.record(8192, start, 2*time.Second, throttling, nil, &BoundarySearchResult{Success: false, ErrorMessage: "context length exceeded"})
+
+	history := recorder.history()
+	if len(history) != 2 {
+		t.Fatalf("history length = %d, want 2", len(history))
+	}
+
+	first := history[0]
+	if first.TokenCount != 4096 || !first.Success {
+		t.Errorf("unexpected first trial: %+v", first)
+	}
+	if first.Message != "success" {
+		t.Errorf("first.Message = %q, want %q", first.Message, "success")
+	}
+	if !first.StartedAt.Equal(start) {
+		t.Errorf("first.StartedAt = %v, want %v", first.StartedAt, start)
+	}
+	if first.EndedAt.Before(first.StartedAt) {
+		t.Error("EndedAt should not be before StartedAt")
+	}
+	if first.Latency != 1500*time.Millisecond {
+		t.Errorf("first.Latency = %v, want 1.5s", first.Latency)
+	}
+	if first.Usage == nil || first.Usage.TotalTokens != 120 {
+		t.Errorf("first.Usage = %+v, want total 120", first.Usage)
+	}
+
+	// レスポンスのUsageを書き換えても履歴に影響しないこと
+	response.Usage.TotalTokens = 0
+	if history[0].Usage.TotalTokens != 120 {
+		t.Error("recorded usage should be copied from response")
+	}
+
+	second := history[1]
+	if second.Success || second.Message != "context length exceeded" || second.Usage != nil {
+		t.Errorf("unexpected second trial: %+v", second)
+	}
+	if first.Wait != 0 || first.Throttled {
+		t.Errorf("first trial should not be throttled: %+v", first)
+	}
+	// レート制限と再送で待った時間はレイテンシから除く
+	if second.Latency != 1500*time.Millisecond || second.Wait != 300*time.Millisecond || !second.Throttled {
+		t.Errorf("second trial latency = %v, wait = %v, throttled = %v, want 1.5s, 300ms and true", second.Latency, second.Wait, second.Throttled)
+	}
+	if second.Retries != 1 || second.Backoff != 200*time.Millisecond {
+		t.Errorf("second trial retries = %d, backoff = %v, want 1 and 200ms", second.Retries, second.Backoff)
+	}
+
+	recorder.reset()
+	if got := recorder.history(); got != nil {
+		t.Errorf("history after reset = %v, want nil", got)
+	}
+}
@@ -0,0 +1,52 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/armaniacs/llm-info/internal/api"
+)
+
+// reconcileUsage はレスポンスのusage.completion_tokensを、要求したmax_tokensとfinish_reasonに照らして確認する
+// 食い違いがあればEvidenceUsageMismatchの根拠を返す（ゲートウェイがusageを誤って報告している可能性がある）
+func reconcileUsage(maxTokens int, response *api.ProbeResponse) []Evidence {
+	if response == nil || response.Error != nil {
+		return nil
+	}
+
+	mismatch := func(tokens int, format string, args ...any) Evidence {
+		return Evidence{Kind: EvidenceUsageMismatch, TokenCount: tokens, Detail: fmt.Sprintf(format, args...)}
+	}
+
+	usage := response.Usage
+	if usage == nil {
+		return []Evidence{mismatch(maxTokens, "no usage reported for max_tokens=%d", maxTokens)}
+	}
+
+	var found []Evidence
+	if usage.CompletionTokens > maxTokens {
+		found = append(found, mismatch(usage.CompletionTokens,
+			"completion_tokens %d exceeds requested max_tokens %d", usage.CompletionTokens, maxTokens))
+	}
+	if usage.TotalTokens > 0 && usage.TotalTokens < usage.PromptTokens+usage.CompletionTokens {
+		found = append(found, mismatch(usage.TotalTokens,
+			"total_tokens %d is less than prompt_tokens %d + completion_tokens %d", usage.TotalTokens, usage.PromptTokens, usage.CompletionTokens))
+	}
+
+	if len(response.Choices) == 0 {
+		return found
+	}
+	choice := response.Choices[0]
+	content := strings.TrimSpace(choice.Message.Content)
+
+	switch {
+	case choice.FinishReason == "length" && usage.CompletionTokens == 0:
+		found = append(found, mismatch(0, "finish_reason=length but completion_tokens is 0 (max_tokens=%d)", maxTokens))
+	case usage.CompletionTokens == 0 && content != "":
+		found = append(found, mismatch(0, "completion_tokens is 0 but the response has %d bytes of content", len(content)))
+	case choice.FinishReason == "stop" && usage.CompletionTokens == maxTokens && maxTokens > 1 && content == "":
+		// 上限まで生成して自然に終わったのに本文がない
+		found = append(found, mismatch(usage.CompletionTokens, "finish_reason=stop at exactly max_tokens=%d with empty content", maxTokens))
+	}
+	return found
+}
@@ -0,0 +1,98 @@
+package probe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/api"
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestReconcileUsage(t *testing.T) {
+	response := func(finishReason, content string, usage *api.UsageInfo) *api.ProbeResponse {
+		return &api.ProbeResponse{
+			Choices: []api.ChatChoice{{FinishReason: finishReason, Message: api.ChatMessage{Role: "assistant", Content: content}}},
+			Usage:   usage,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		maxTokens int
+		response  *api.ProbeResponse
+		want      int
+	}{
+		{"consistent stop", 16, response("stop", "ok", &api.UsageInfo{PromptTokens: 8, CompletionTokens: 2, TotalTokens: 10}), 0},
+		{"consistent length", 16, response("length", "long text", &api.UsageInfo{PromptTokens: 8, CompletionTokens: 16, TotalTokens: 24}), 0},
+		{"exceeds max_tokens", 16, response("stop", "ok", &api.UsageInfo{PromptTokens: 8, CompletionTokens: 40, TotalTokens: 48}), 1},
+		{"length with zero completion", 16, response("length", "long text", &api.UsageInfo{PromptTokens: 8}), 1},
+		{"content without completion", 16, response("stop", "ok", &api.UsageInfo{PromptTokens: 8, TotalTokens: 8}), 1},
+		{"total below sum", 16, response("stop", "ok", &api.UsageInfo{PromptTokens: 8, CompletionTokens: 2, TotalTokens: 8}), 1},
+		{"missing usage", 16, response("stop", "ok", nil), 1},
+		{"api error", 16, &api.ProbeResponse{Error: &api.OpenAIError{Message: "bad request"}}, 0},
+		{"nil response", 16, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := reconcileUsage(tt.maxTokens, tt.response)
+			if len(got) != tt.want {
+				t.Fatalf("reconcileUsage() = %v, want %d discrepancies", got, tt.want)
+			}
+			for _, e := range got {
+				if e.Kind != EvidenceUsageMismatch || e.Detail == "" {
+					t.Errorf("unexpected evidence %+v", e)
+				}
+			}
+		})
+	}
+}
+
+func TestMaxOutputProbeFlagsMisreportedUsage(t *testing.T) {
+	var requested []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req api.ProbeRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		requested = append(requested, req.MaxTokens)
+		if req.MaxTokens > 8192 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"message": "max_tokens must be <= 8192"}})
+			return
+		}
+		// completion_tokensを常に2倍で報告するゲートウェイ
+		json.NewEncoder(w).Encode(api.ProbeResponse{
+			Choices: []api.ChatChoice{{FinishReason: "stop", Message: api.ChatMessage{Role: "assistant", Content: "text"}}},
+			Usage:   &api.UsageInfo{PromptTokens: 5, CompletionTokens: req.MaxTokens * 2, TotalTokens: 5 + req.MaxTokens*2},
+		})
+	}))
+	defer server.Close()
+
+	client := api.NewProbeClient(&config.AppConfig{
+		BaseURL: server.URL,
+		APIKey:  "test",
+		Timeout: 5 * time.Second,
+	})
+	result, err := NewMaxOutputTokensProbe(client).ProbeOutputTokens("test-model", false)
+	if err != nil {
+		t.Fatalf("ProbeOutputTokens() error = %v", err)
+	}
+
+	if len(requested) == 0 || requested[0] != 4096 {
+		t.Errorf("first request max_tokens = %v, want 4096", requested)
+	}
+	if len(result.UsageDiscrepancies) == 0 {
+		t.Fatal("expected usage discrepancies to be reported")
+	}
+	found := false
+	for _, e := range result.Confidence.Evidence {
+		if e.Kind == EvidenceUsageMismatch {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("usage mismatch missing from confidence evidence")
+	}
+}
@@ -0,0 +1,212 @@
+// Package provider は探索結果を保存するプロバイダー名を判定する
+package provider
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+// Unknown はプロバイダーを判定できなかったときの名前
+const Unknown = "unknown"
+
+// knownProviders はURLやヘッダーの値から探すプロバイダー名（先に一致したものを使う）
+var knownProviders = []string{"openai", "anthropic", "google", "azure"}
+
+// builtinAliases はLiteLLMなどが返す名前と保存に使うプロバイダー名の対応
+var builtinAliases = map[string]string{
+	"azure_ai":               "azure",
+	"azure_text":             "azure",
+	"text-completion-openai": "openai",
+	"gemini":                 "google",
+	"vertex_ai":              "google",
+	"vertex_ai_beta":         "google",
+}
+
+// metadataKeys はプロバイダー名として参照するモデルのメタデータのキー
+var metadataKeys = []string{"litellm_provider"}
+
+// Evidence はプロバイダー名の判定に使う情報
+type Evidence struct {
+	URL      string                 // ゲートウェイのURL
+	Metadata map[string]interface{} // モデル一覧で返されたモデルのメタデータ
+	Header   http.Header            // 探索リクエストのレスポンスヘッダー
+}
+
+// Detector はプロバイダー名を判定する
+type Detector struct {
+	hosts   []hostRule
+	aliases map[string]string
+}
+
+// hostRule はホストのパターンとプロバイダー名の対応
+type hostRule struct {
+	pattern  string
+	provider string
+}
+
+// NewDetector は設定ファイルのprovidersセクションを反映したDetectorを作成する
+func NewDetector(cfg config.ProvidersConfig) *Detector {
+	d := &Detector{aliases: make(map[string]string)}
+	for name, provider := range builtinAliases {
+		d.aliases[name] = provider
+	}
+	for name, provider := range cfg.Aliases {
+		d.aliases[strings.ToLower(name)] = strings.ToLower(provider)
+	}
+	for pattern, provider := range cfg.Hosts {
+		d.hosts = append(d.hosts, hostRule{pattern: strings.ToLower(pattern), provider: strings.ToLower(provider)})
+	}
+	// ワイルドカードのないパターンを優先し、同じ種類では長い（具体的な）パターンを先に試す
+	sort.Slice(d.hosts, func(i, j int) bool {
+		wi, wj := strings.Contains(d.hosts[i].pattern, "*"), strings.Contains(d.hosts[j].pattern, "*")
+		if wi != wj {
+			return !wi
+		}
+		if len(d.hosts[i].pattern) != len(d.hosts[j].pattern) {
+			return len(d.hosts[i].pattern) > len(d.hosts[j].pattern)
+		}
+		return d.hosts[i].pattern < d.hosts[j].pattern
+	})
+	return d
+}
+
+// Detect はプロバイダー名を判定する
+// モデルのメタデータ（litellm_provider）、設定したホストの対応、レスポンスヘッダー、
+// URLに含まれる名前の順に調べ、どれでも判定できなければUnknownを返す
+func (d *Detector) Detect(e Evidence) string {
+	if name := d.FromMetadata(e.Metadata); name != "" {
+		return name
+	}
+	if name := d.FromHost(e.URL); name != "" {
+		return name
+	}
+	if name := d.FromHeader(e.Header); name != "" {
+		return name
+	}
+	if name := d.FromURL(e.URL); name != "" {
+		return name
+	}
+	return Unknown
+}
+
+// FromMetadata はモデルのメタデータ（LiteLLMのmodel_infoを含む）からプロバイダー名を返す
+func (d *Detector) FromMetadata(metadata map[string]interface{}) string {
+	if metadata == nil {
+		return ""
+	}
+	sources := []map[string]interface{}{metadata}
+	if info, ok := metadata["model_info"].(map[string]interface{}); ok {
+		sources = append(sources, info)
+	}
+	for _, source := range sources {
+		for _, key := range metadataKeys {
+			if name, ok := source[key].(string); ok && strings.TrimSpace(name) != "" {
+				return d.normalize(name)
+			}
+		}
+	}
+	return ""
+}
+
+// FromHost は設定したホストの対応からプロバイダー名を返す
+func (d *Detector) FromHost(rawURL string) string {
+	host := hostOf(rawURL)
+	if host == "" {
+		return ""
+	}
+	for _, rule := range d.hosts {
+		if ok, _ := path.Match(rule.pattern, host); ok {
+			return rule.provider
+		}
+	}
+	return ""
+}
+
+// FromHeader はレスポンスヘッダーからプロバイダー名を返す
+// LiteLLMが転送する上流のヘッダー（llm_provider-）と上流のURL、プロバイダー固有のヘッダー、
+// serverとviaの値を調べる
+func (d *Detector) FromHeader(header http.Header) string {
+	if len(header) == 0 {
+		return ""
+	}
+
+	// LiteLLMは上流のAPIのURLを返す
+	if name := d.FromURL(header.Get("x-litellm-model-api-base")); name != "" {
+		return name
+	}
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// LiteLLMは上流のヘッダーに接頭辞を付けて転送する
+		name = strings.TrimPrefix(name, "llm_provider-")
+		switch {
+		case strings.HasPrefix(name, "openai-"):
+			return "openai"
+		case strings.HasPrefix(name, "anthropic-"):
+			return "anthropic"
+		case strings.HasPrefix(name, "x-goog-"):
+			return "google"
+		case strings.HasPrefix(name, "x-ms-"), name == "apim-request-id", strings.HasPrefix(name, "azureml-"):
+			return "azure"
+		}
+	}
+
+	for _, key := range []string{"Server", "Via", "llm_provider-server", "llm_provider-via"} {
+		value := strings.ToLower(strings.Join(header.Values(key), " "))
+		if value == "" {
+			continue
+		}
+		if strings.Contains(value, "google frontend") || value == "esf" {
+			return "google"
+		}
+		if name := matchKnown(value); name != "" {
+			return d.normalize(name)
+		}
+	}
+	return ""
+}
+
+// FromURL はURLに含まれる名前からプロバイダー名を返す
+func (d *Detector) FromURL(rawURL string) string {
+	if name := matchKnown(strings.ToLower(rawURL)); name != "" {
+		return d.normalize(name)
+	}
+	return ""
+}
+
+// normalize は検出した名前を保存に使うプロバイダー名に変換する
+func (d *Detector) normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := d.aliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// matchKnown は文字列に含まれる既知のプロバイダー名を返す
+func matchKnown(value string) string {
+	for _, name := range knownProviders {
+		if strings.Contains(value, name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// hostOf はURLのホスト名を小文字で返す
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
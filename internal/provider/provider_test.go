@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/armaniacs/llm-info/pkg/config"
+)
+
+func TestDetector_Detect(t *testing.T) {
+	detector := NewDetector(config.ProvidersConfig{
+		Hosts: map[string]string{
+			"llm.corp.example.com": "OpenAI",
+			"*.corp.example.com":   "anthropic",
+		},
+		Aliases: map[string]string{"bedrock": "aws"},
+	})
+
+	tests := []struct {
+		name     string
+		evidence Evidence
+		want     string
+	}{
+		{
+			name:     "URLに含まれる名前（従来の判定）",
+			evidence: Evidence{URL: "https://api.openai.com"},
+			want:     "openai",
+		},
+		{
+			name:     "判定できない",
+			evidence: Evidence{URL: "https://gateway.example.com"},
+			want:     Unknown,
+		},
+		{
+			name:     "ホストの対応は完全一致を優先",
+			evidence: Evidence{URL: "https://llm.corp.example.com/v1"},
+			want:     "openai",
+		},
+		{
+			name:     "ホストの対応のワイルドカード",
+			evidence: Evidence{URL: "https://claude.corp.example.com"},
+			want:     "anthropic",
+		},
+		{
+			name: "litellm_providerはホストの対応より優先",
+			evidence: Evidence{
+				URL:      "https://llm.corp.example.com",
+				Metadata: map[string]interface{}{"model_info": map[string]interface{}{"litellm_provider": "vertex_ai"}},
+			},
+			want: "google",
+		},
+		{
+			name:     "litellm_providerに別名を適用",
+			evidence: Evidence{URL: "https://gateway.example.com", Metadata: map[string]interface{}{"litellm_provider": "Bedrock"}},
+			want:     "aws",
+		},
+		{
+			name:     "LiteLLMが転送した上流のヘッダー",
+			evidence: Evidence{URL: "https://gateway.example.com", Header: header("llm_provider-anthropic-ratelimit-requests-limit", "50")},
+			want:     "anthropic",
+		},
+		{
+			name:     "LiteLLMが返す上流のURL",
+			evidence: Evidence{URL: "https://gateway.example.com", Header: header("x-litellm-model-api-base", "https://myresource.azure.com")},
+			want:     "azure",
+		},
+		{
+			name:     "serverヘッダー",
+			evidence: Evidence{URL: "https://gateway.example.com", Header: header("Server", "Google Frontend")},
+			want:     "google",
+		},
+		{
+			name:     "viaヘッダー",
+			evidence: Evidence{URL: "https://gateway.example.com", Header: header("Via", "1.1 anthropic-edge")},
+			want:     "anthropic",
+		},
+		{
+			name:     "関係のないヘッダーは無視",
+			evidence: Evidence{URL: "https://gateway.example.com", Header: header("Server", "nginx")},
+			want:     Unknown,
+		},
+		{
+			name:     "ホストの対応はヘッダーより優先",
+			evidence: Evidence{URL: "https://claude.corp.example.com", Header: header("Openai-Version", "2020-10-01")},
+			want:     "anthropic",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detector.Detect(tt.evidence); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func header(key, value string) http.Header {
+	h := http.Header{}
+	h.Set(key, value)
+	return h
+}
@@ -0,0 +1,232 @@
+// Package ratelimit はゲートウェイごとの同時リクエスト数とリクエストレートの上限を管理する
+//
+// 設定ファイルのmax_concurrencyとrate_limitはRegisterでゲートウェイのホストごとに登録され、
+// APIクライアントがリクエストの前にAcquireで待つ。一覧の並行取得、
+// 複数モデルの探索、デーモンなど、どのコマンドから送ったリクエストにも同じ上限がかかる
+// max_retriesも同じく登録され、ゲートウェイが429を返したリクエストをBackoffの間待ってから再送する
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxRetries はmax_retriesに指定できる上限
+const MaxRetries = 10
+
+// 429の再送までの待ち時間（Retry-Afterがない場合は1秒から倍にしていく）
+const (
+	initialBackoff = time.Second
+	maxBackoff     = time.Minute
+)
+
+// Limit はゲートウェイに送るリクエストの上限（0は無制限）
+type Limit struct {
+	MaxConcurrency int           // 同時に送るリクエストの上限
+	Interval       time.Duration // リクエストの最小間隔（rate_limitから計算する）
+	MaxRetries     int           // 429を返されたリクエストを再送する回数（0は再送しない）
+}
+
+// IsZero は上限も再送の指定もないかを返す
+func (l Limit) IsZero() bool {
+	return l.MaxConcurrency <= 0 && l.Interval <= 0 && l.MaxRetries <= 0
+}
+
+// ParseRate はrate_limitの値（例: 60/m、5/s、1000/h）をリクエストの最小間隔に変換する
+// 空は無制限として0を返す
+func ParseRate(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	count, unit, ok := strings.Cut(s, "/")
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if !ok || err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid rate limit: %s (use requests per unit, e.g. 60/m, 5/s or 1000/h)", s)
+	}
+	var period time.Duration
+	switch strings.TrimSpace(unit) {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		return 0, fmt.Errorf("invalid rate limit unit: %s (valid: s, m, h)", unit)
+	}
+	return period / time.Duration(n), nil
+}
+
+// limiter は1つのゲートウェイの上限を守るための状態
+type limiter struct {
+	limit Limit
+	slots chan struct{} // 同時に送っているリクエスト（MaxConcurrencyが0ならnil）
+
+	mu   sync.Mutex
+	next time.Time // 次のリクエストを送ってよい時刻
+}
+
+var (
+	mu       sync.RWMutex
+	limiters = make(map[string]*limiter)
+)
+
+// Register はゲートウェイのベースURLのホストに上限を登録する
+// 同じホストに複数のゲートウェイがある場合は、より厳しい上限を使う
+func Register(baseURL string, limit Limit) {
+	host := hostOf(baseURL)
+	if host == "" || limit.IsZero() {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if existing, ok := limiters[host]; ok {
+		limit = stricter(existing.limit, limit)
+		if limit == existing.limit {
+			return
+		}
+	}
+	l := &limiter{limit: limit}
+	if limit.MaxConcurrency > 0 {
+		l.slots = make(chan struct{}, limit.MaxConcurrency)
+	}
+	limiters[host] = l
+}
+
+// Reset は登録したすべての上限を削除する（テスト用）
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	limiters = make(map[string]*limiter)
+}
+
+// Retries はリクエスト先のホストに登録された429の再送回数を返す（登録されていなければ0）
+func Retries(target *url.URL) int {
+	mu.RLock()
+	defer mu.RUnlock()
+	if l := limiters[target.Host]; l != nil {
+		return l.limit.MaxRetries
+	}
+	return 0
+}
+
+// Backoff は429を返されたリクエストを再送するまでの待ち時間を返す（attemptは0から数える再送の回数）
+// Retry-After（秒数またはHTTPの日時）があればそれに従い、なければ1秒、2秒、4秒と倍にする。どちらも1分を上限とする
+func Backoff(attempt int, retryAfter string) time.Duration {
+	retryAfter = strings.TrimSpace(retryAfter)
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return min(time.Duration(seconds)*time.Second, maxBackoff)
+	}
+	if at, err := http.ParseTime(retryAfter); err == nil {
+		return min(max(time.Until(at), 0), maxBackoff)
+	}
+	delay := initialBackoff
+	for i := 0; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// Acquire はリクエスト先のホストの上限に空きができるまで待ち、リクエストを終えたときに呼ぶ関数と
+// 空きを待った時間（すぐに送れた場合は0）を返す
+// 上限が登録されていないホストはすぐに返る。ctxが終了した場合はそのエラーを返す
+func Acquire(ctx context.Context, target *url.URL) (func(), time.Duration, error) {
+	mu.RLock()
+	l := limiters[target.Host]
+	mu.RUnlock()
+	if l == nil {
+		return func() {}, 0, nil
+	}
+
+	start := time.Now()
+	blocked := false
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		default:
+			blocked = true
+			select {
+			case l.slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, 0, ctx.Err()
+			}
+		}
+	}
+	release := func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	delayed, err := l.wait(ctx)
+	if err != nil {
+		release()
+		return nil, 0, err
+	}
+	var waited time.Duration
+	if blocked || delayed {
+		waited = time.Since(start)
+	}
+	var once sync.Once
+	return func() { once.Do(release) }, waited, nil
+}
+
+// wait は前のリクエストからInterval経つまで待ち、待ったかどうかを返す
+// 待つ前に次の枠を予約するため、同時に待っているリクエストも間隔を空けて順に送られる
+func (l *limiter) wait(ctx context.Context) (bool, error) {
+	if l.limit.Interval <= 0 {
+		return false, nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.limit.Interval)
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return false, nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true, nil
+	case <-ctx.Done():
+		return true, ctx.Err()
+	}
+}
+
+// stricter はそれぞれの項目でより厳しい値を選んだ上限を返す
+func stricter(a, b Limit) Limit {
+	if a.MaxConcurrency <= 0 || (b.MaxConcurrency > 0 && b.MaxConcurrency < a.MaxConcurrency) {
+		a.MaxConcurrency = b.MaxConcurrency
+	}
+	if b.Interval > a.Interval {
+		a.Interval = b.Interval
+	}
+	if a.MaxRetries <= 0 || (b.MaxRetries > 0 && b.MaxRetries < a.MaxRetries) {
+		a.MaxRetries = b.MaxRetries
+	}
+	return a
+}
+
+// hostOf はベースURLのホスト（ポートを含む）を返す
+func hostOf(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"5/s", 200 * time.Millisecond, false},
+		{"60/m", time.Second, false},
+		{"3600/h", time.Second, false},
+		{" 120 / m ", 500 * time.Millisecond, false},
+		{"60", 0, true},
+		{"0/s", 0, true},
+		{"ten/s", 0, true},
+		{"5/d", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRate(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRate(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestAcquire_MaxConcurrency(t *testing.T) {
+	Reset()
+	defer Reset()
+	Register("http://vllm.internal:8000/v1", Limit{MaxConcurrency: 2})
+	target, _ := url.Parse("http://vllm.internal:8000/v1/chat/completions")
+
+	var active, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, _, err := Acquire(context.Background(), target)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer release()
+			n := atomic.AddInt32(&active, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+		}()
+	}
+	wg.Wait()
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", peak)
+	}
+}
+
+func TestAcquire_Interval(t *testing.T) {
+	Reset()
+	defer Reset()
+	Register("https://gw.example.com", Limit{Interval: 20 * time.Millisecond})
+	target, _ := url.Parse("https://gw.example.com/v1/models")
+
+	start := time.Now()
+	var waited time.Duration
+	for i := 0; i < 4; i++ {
+		release, wait, err := Acquire(context.Background(), target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 && wait != 0 {
+			t.Errorf("first request waited %v, want 0", wait)
+		}
+		waited += wait
+		release()
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("4 requests took %v, want at least 60ms", elapsed)
+	}
+	if waited < 60*time.Millisecond {
+		t.Errorf("reported wait = %v, want at least 60ms", waited)
+	}
+
+	// 他のホストには上限がかからない
+	other, _ := url.Parse("https://api.openai.com/v1/models")
+	start = time.Now()
+	for i := 0; i < 4; i++ {
+		release, wait, _ := Acquire(context.Background(), other)
+		if wait != 0 {
+			t.Errorf("unlimited host reported a wait of %v", wait)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("unlimited host took %v", elapsed)
+	}
+}
+
+func TestAcquire_ContextCanceled(t *testing.T) {
+	Reset()
+	defer Reset()
+	Register("https://gw.example.com", Limit{MaxConcurrency: 1})
+	target, _ := url.Parse("https://gw.example.com/v1/models")
+
+	release, _, err := Acquire(context.Background(), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := Acquire(ctx, target); err == nil {
+		t.Error("Acquire() with a full slot and a canceled context should fail")
+	}
+}
+
+func TestRegister_Stricter(t *testing.T) {
+	Reset()
+	defer Reset()
+	Register("https://gw.example.com/team-a", Limit{MaxConcurrency: 4, Interval: time.Second, MaxRetries: 3})
+	Register("https://gw.example.com/team-b", Limit{MaxConcurrency: 2, MaxRetries: 1})
+
+	got := limiters["gw.example.com"].limit
+	if want := (Limit{MaxConcurrency: 2, Interval: time.Second, MaxRetries: 1}); got != want {
+		t.Errorf("limit = %+v, want %+v", got, want)
+	}
+	if retries := Retries(&url.URL{Host: "gw.example.com"}); retries != 1 {
+		t.Errorf("Retries() = %d, want 1", retries)
+	}
+	if retries := Retries(&url.URL{Host: "other.example.com"}); retries != 0 {
+		t.Errorf("Retries() for an unregistered host = %d, want 0", retries)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		want       time.Duration
+	}{
+		{"first retry", 0, "", time.Second},
+		{"doubles", 2, "", 4 * time.Second},
+		{"capped", 10, "", time.Minute},
+		{"retry-after seconds", 0, "3", 3 * time.Second},
+		{"retry-after zero", 3, "0", 0},
+		{"retry-after capped", 0, "3600", time.Minute},
+		{"retry-after in the past", 0, "Mon, 02 Jan 2006 15:04:05 GMT", 0},
+		{"invalid retry-after", 1, "soon", 2 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt, tt.retryAfter); got != tt.want {
+			t.Errorf("%s: Backoff(%d, %q) = %v, want %v", tt.name, tt.attempt, tt.retryAfter, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Package readonly は補完を生成するリクエスト（探索やchat）を送らない読み取り専用モードを管理する
+//
+// --read-onlyまたは設定ファイルのglobal.read_onlyで有効になり、プロセスが終了するまで解除しない。
+// 有効な間は探索用のAPIクライアントが送信の前にリクエストを拒否するため、
+// どのコマンドから呼び出しても料金の発生するリクエストは送られない
+package readonly
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrReadOnly は読み取り専用モードで補完を生成するリクエストを拒否したことを表す
+var ErrReadOnly = errors.New("read-only mode")
+
+var enabled atomic.Bool
+
+// Enable は読み取り専用モードを有効にする
+func Enable() {
+	enabled.Store(true)
+}
+
+// Enabled は読み取り専用モードが有効かを返す
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Reset は読み取り専用モードを解除する（テスト用）
+func Reset() {
+	enabled.Store(false)
+}
+
+// Check は読み取り専用モードでcommandを実行できない場合にエラーを返す
+// 補完を生成するコマンドは、ゲートウェイに接続する前に呼び出して実行を拒否する
+func Check(command string) error {
+	if !Enabled() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s sends completion requests and cannot run with --read-only or global.read_only (model listing, --offline and saved results are still available)", ErrReadOnly, command)
+}
@@ -0,0 +1,25 @@
+package readonly
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	if err := Check("probe"); err != nil {
+		t.Errorf("Check() before Enable() error = %v", err)
+	}
+
+	Enable()
+	err := Check("probe")
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("Check() error = %v, want ErrReadOnly", err)
+	}
+	if !strings.Contains(err.Error(), "probe sends completion requests") {
+		t.Errorf("Check() error = %q, want the command name", err)
+	}
+}
@@ -0,0 +1,194 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears は次回実行時刻を探索する上限年数
+const maxSearchYears = 5
+
+// descriptors は@で始まる定義済みスケジュール
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field はcron式の1フィールドの定義
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// Schedule は解析済みのcron式
+type Schedule struct {
+	expr    string
+	minute  map[int]bool
+	hour    map[int]bool
+	dom     map[int]bool
+	month   map[int]bool
+	dow     map[int]bool
+	domStar bool
+	dowStar bool
+}
+
+// Parse は5フィールドのcron式（分 時 日 月 曜日）を解析する
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	spec := expr
+	if strings.HasPrefix(spec, "@") {
+		resolved, ok := descriptors[spec]
+		if !ok {
+			return nil, fmt.Errorf("unknown schedule descriptor: %s", spec)
+		}
+		spec = resolved
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("invalid cron expression %q: expected %d fields, got %d", expr, len(fields), len(parts))
+	}
+
+	sets := make([]map[int]bool, len(fields))
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+
+	// 曜日の7は日曜日として扱う
+	if sets[4][7] {
+		delete(sets[4], 7)
+		sets[4][0] = true
+	}
+
+	return &Schedule{
+		expr:    expr,
+		minute:  sets[0],
+		hour:    sets[1],
+		dom:     sets[2],
+		month:   sets[3],
+		dow:     sets[4],
+		domStar: parts[2] == "*",
+		dowStar: parts[4] == "*",
+	}, nil
+}
+
+// String は元のcron式を返す
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// Next はafterより後で最初にスケジュールに一致する時刻を返す
+// 一致する時刻が見つからない場合はゼロ値を返す
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if !s.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchDay は日と曜日の条件を判定する
+// 両方が指定されている場合はいずれかに一致すればよい（標準cronの挙動）
+func (s *Schedule) matchDay(t time.Time) bool {
+	domMatch := s.dom[t.Day()]
+	dowMatch := s.dow[int(t.Weekday())]
+
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dowMatch
+	case s.dowStar:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// parseField はフィールドを値の集合に変換する
+func parseField(part string, f field) (map[int]bool, error) {
+	max := f.max
+	if f.name == "day of week" {
+		max = 7
+	}
+
+	set := make(map[int]bool)
+	for _, item := range strings.Split(part, ",") {
+		step := 1
+		rangePart := item
+		if idx := strings.Index(item, "/"); idx >= 0 {
+			n, err := strconv.Atoi(item[idx+1:])
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid step in %s field: %q", f.name, item)
+			}
+			step = n
+			rangePart = item[:idx]
+		}
+
+		lo, hi := f.min, max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid value in %s field: %q", f.name, item)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("invalid value in %s field: %q", f.name, item)
+				}
+			} else if step > 1 {
+				// "5/15" は5から最大値までを意味する
+				hi = max
+			}
+		}
+
+		if lo < f.min || hi > max || lo > hi {
+			return nil, fmt.Errorf("%s field out of range (%d-%d): %q", f.name, f.min, max, item)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+
+	return set, nil
+}
@@ -0,0 +1,81 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{"daily at 3am", "0 3 * * *", false},
+		{"every 15 minutes", "*/15 * * * *", false},
+		{"weekdays", "30 9 * * 1-5", false},
+		{"list", "0 0,12 1,15 * *", false},
+		{"sunday as 7", "0 0 * * 7", false},
+		{"descriptor", "@daily", false},
+		{"too few fields", "0 3 * *", true},
+		{"out of range", "60 * * * *", true},
+		{"invalid step", "*/0 * * * *", true},
+		{"reversed range", "0 5-3 * * *", true},
+		{"unknown descriptor", "@sometimes", true},
+		{"not a number", "a * * * *", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// 2025-01-01は水曜日
+	base := time.Date(2025, 1, 1, 10, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"daily at 3am", "0 3 * * *", base, time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)},
+		{"every 15 minutes", "*/15 * * * *", base, time.Date(2025, 1, 1, 10, 45, 0, 0, time.UTC)},
+		{"hourly", "@hourly", base, time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC)},
+		{"next weekday morning", "30 9 * * 1-5", base, time.Date(2025, 1, 2, 9, 30, 0, 0, time.UTC)},
+		{"sunday", "0 0 * * 0", base, time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", base, time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"monthly", "@monthly", base, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", base, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"dom or dow", "0 0 15 * 5", base, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"strictly after", "30 10 * * *", time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC), time.Date(2025, 1, 2, 10, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expr, err)
+			}
+			if got := s.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_NextNeverMatches(t *testing.T) {
+	s, err := Parse("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := s.Next(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("Next() = %v, want zero time", got)
+	}
+}
@@ -0,0 +1,206 @@
+// Package search はキャッシュ済みのモデル一覧をあいまい検索する
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/armaniacs/llm-info/internal/model"
+)
+
+// 検索対象のフィールド
+const (
+	FieldID          = "id"
+	FieldProvider    = "provider"
+	FieldDescription = "description"
+)
+
+// スコアの基準値（大きいほど一致度が高い）
+const (
+	scoreExact     = 1000
+	scorePrefix    = 800
+	scoreWord      = 600
+	scoreSubstring = 400
+	scoreFuzzy     = 200
+)
+
+// providerKeys はプロバイダー名として参照するメタデータのキー
+var providerKeys = []string{"litellm_provider", "provider", "owned_by"}
+
+// Document は検索対象のモデルとその取得元ゲートウェイ
+type Document struct {
+	Gateway string
+	Model   model.Model
+}
+
+// Result は検索結果の1件
+type Result struct {
+	Document
+	Score int    // 一致度
+	Field string // 最も一致したフィールド
+}
+
+// Search はクエリに一致するドキュメントを一致度の高い順に返す
+// クエリを空白で区切った各語が、モデルID・プロバイダー名・説明のいずれかに一致する必要がある
+func Search(query string, docs []Document) []Result {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var results []Result
+	for _, doc := range docs {
+		if result, ok := match(terms, doc); ok {
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].Model.Name != results[j].Model.Name {
+			return results[i].Model.Name < results[j].Model.Name
+		}
+		return results[i].Gateway < results[j].Gateway
+	})
+	return results
+}
+
+// match は全ての語についてフィールドごとのスコアを合計する
+// IDへの一致を優先し、プロバイダー名と説明への一致は低く評価する
+func match(terms []string, doc Document) (Result, bool) {
+	fields := []struct {
+		name   string
+		text   string
+		weight int
+	}{
+		{FieldID, strings.ToLower(doc.Model.Name), 3},
+		{FieldProvider, strings.ToLower(Provider(doc.Model)), 2},
+		{FieldDescription, strings.ToLower(Description(doc.Model)), 1},
+	}
+
+	result := Result{Document: doc}
+	fieldScores := make(map[string]int)
+	for _, term := range terms {
+		best, bestField := 0, ""
+		for _, f := range fields {
+			if s := Score(term, f.text) * f.weight; s > best {
+				best, bestField = s, f.name
+			}
+		}
+		if best == 0 {
+			return Result{}, false
+		}
+		result.Score += best
+		fieldScores[bestField] += best
+	}
+
+	for _, f := range fields {
+		if fieldScores[f.name] > fieldScores[result.Field] {
+			result.Field = f.name
+		}
+	}
+	return result, true
+}
+
+// Score は小文字化済みの語がテキストにどの程度一致するかを返す
+// 完全一致・前方一致・単語の先頭での一致・部分一致・あいまい一致（部分列）の順に高く、一致しない場合は0を返す
+func Score(term, text string) int {
+	if term == "" || text == "" {
+		return 0
+	}
+
+	// 同じ種類の一致では短いテキストを優先する
+	extra := min(len(text)-len(term), 99)
+	if extra < 0 {
+		return 0
+	}
+
+	switch {
+	case text == term:
+		return scoreExact
+	case strings.HasPrefix(text, term):
+		return scorePrefix - extra
+	}
+
+	if wordIndex(text, term) >= 0 {
+		return scoreWord - extra
+	}
+	if pos := strings.Index(text, term); pos >= 0 {
+		return scoreSubstring - min(pos, 99) - extra
+	}
+	return fuzzyScore(term, text)
+}
+
+// wordIndex は単語の先頭から始まる最初の一致位置を返す
+func wordIndex(text, term string) int {
+	for offset := 0; offset < len(text); {
+		pos := strings.Index(text[offset:], term)
+		if pos < 0 {
+			return -1
+		}
+		pos += offset
+		if pos == 0 || !isWordChar(rune(text[pos-1])) {
+			return pos
+		}
+		offset = pos + 1
+	}
+	return -1
+}
+
+// fuzzyScore は語の文字が順番通りに現れる場合のスコアを返す
+// 連続して一致する文字が多く、間の文字が少ないほど高い
+func fuzzyScore(term, text string) int {
+	score := scoreFuzzy
+	ti := 0
+	last := -1
+	for i := 0; i < len(text) && ti < len(term); i++ {
+		if text[i] != term[ti] {
+			continue
+		}
+		if last >= 0 {
+			if i == last+1 {
+				score += 5
+			} else {
+				score -= min(i-last-1, 20)
+			}
+		}
+		last = i
+		ti++
+	}
+	if ti < len(term) {
+		return 0
+	}
+	return max(score, 1)
+}
+
+// isWordChar は単語を構成する文字かどうかを返す
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Provider はモデルのプロバイダー名を返す
+// メタデータにない場合は「openai/gpt-4o」のようなIDの接頭辞を使う
+func Provider(m model.Model) string {
+	for _, key := range providerKeys {
+		if value, ok := m.MetaValue(key); ok {
+			if s := model.FormatMetaValue(value); s != "" {
+				return s
+			}
+		}
+	}
+	if provider, _, ok := strings.Cut(m.Name, "/"); ok {
+		return provider
+	}
+	return ""
+}
+
+// Description はモデルの説明を返す
+func Description(m model.Model) string {
+	if value, ok := m.MetaValue("description"); ok {
+		return model.FormatMetaValue(value)
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/armaniacs/llm-info/internal/model"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name string
+		term string
+		text string
+		want bool
+	}{
+		{"exact", "gpt-4o", "gpt-4o", true},
+		{"prefix", "claude", "claude-3-5-sonnet", true},
+		{"word", "sonnet", "claude-3-5-sonnet", true},
+		{"substring", "onne", "claude-3-5-sonnet", true},
+		{"fuzzy", "c35s", "claude-3-5-sonnet", true},
+		{"no match", "gemini", "claude-3-5-sonnet", false},
+		{"term longer than text", "gpt-4o-mini", "gpt-4o", false},
+		{"empty text", "gpt", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(tt.term, tt.text) > 0; got != tt.want {
+				t.Errorf("Score(%q, %q) > 0 = %v, want %v", tt.term, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScore_Ranking(t *testing.T) {
+	// 一致の種類ごとにスコアが高い順に並ぶ
+	ordered := []string{"sonnet", "sonnet-4", "claude-sonnet-4", "claudesonnet", "s-o-n-n-e-t"}
+	prev := Score("sonnet", ordered[0])
+	for _, text := range ordered[1:] {
+		score := Score("sonnet", text)
+		if score >= prev {
+			t.Errorf("Score(%q) = %d, want less than %d", text, score, prev)
+		}
+		prev = score
+	}
+}
+
+func TestSearch(t *testing.T) {
+	docs := []Document{
+		{Gateway: "prod", Model: model.Model{Name: "gpt-4o"}},
+		{Gateway: "prod", Model: model.Model{Name: "anthropic/claude-3-5-sonnet-20241022"}},
+		{Gateway: "staging", Model: model.Model{Name: "claude-sonnet-4", Metadata: map[string]interface{}{"litellm_provider": "bedrock"}}},
+		{Gateway: "staging", Model: model.Model{Name: "gemini-pro", Metadata: map[string]interface{}{"description": "Google model with a long context window"}}},
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		want      []string
+		wantField string
+	}{
+		{"single term", "sonnet", []string{"claude-sonnet-4", "anthropic/claude-3-5-sonnet-20241022"}, FieldID},
+		{"multiple terms", "claude 3.5", nil, ""},
+		{"all terms must match", "claude 3 5", []string{"anthropic/claude-3-5-sonnet-20241022"}, FieldID},
+		{"provider from metadata", "bedrock", []string{"claude-sonnet-4"}, FieldProvider},
+		{"provider from id prefix", "anthropic", []string{"anthropic/claude-3-5-sonnet-20241022"}, FieldID},
+		{"description", "context", []string{"gemini-pro"}, FieldDescription},
+		{"case insensitive", "GPT", []string{"gpt-4o"}, FieldID},
+		{"empty query", "  ", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := Search(tt.query, docs)
+			if len(results) != len(tt.want) {
+				t.Fatalf("Search(%q) returned %d results, want %d: %+v", tt.query, len(results), len(tt.want), results)
+			}
+			for i, name := range tt.want {
+				if results[i].Model.Name != name {
+					t.Errorf("results[%d] = %q, want %q", i, results[i].Model.Name, name)
+				}
+			}
+			if len(results) > 0 && results[0].Field != tt.wantField {
+				t.Errorf("results[0].Field = %q, want %q", results[0].Field, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Package stats はllm-infoの利用状況をローカルのファイルに集計する
+//
+// 集計はオプトインで、ネットワークには一切送信しない。記録するのは
+// コマンドの実行回数、ゲートウェイごとの取得レイテンシ、probeの消費トークンとコストのみで、
+// URLやAPIキー、プロンプトの内容は保存しない
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/paths"
+)
+
+// fileVersion は統計ファイルの形式のバージョン
+const fileVersion = 1
+
+// GetDefaultStatsFile はデフォルトの統計ファイルのパスを返す
+func GetDefaultStatsFile() string {
+	return paths.StateFile("stats.json")
+}
+
+// Stats は集計済みの利用統計
+type Stats struct {
+	Version   int                      `json:"version"`
+	Since     time.Time                `json:"since"`
+	UpdatedAt time.Time                `json:"updated_at"`
+	Commands  map[string]int           `json:"commands"`
+	Gateways  map[string]*GatewayStats `json:"gateways"`
+	Probes    map[string]*ProbeStats   `json:"probes"`
+}
+
+// GatewayStats はゲートウェイごとのモデル一覧取得の統計
+type GatewayStats struct {
+	Fetches        int   `json:"fetches"`
+	Failures       int   `json:"failures"`
+	TotalLatencyMs int64 `json:"total_latency_ms"`
+}
+
+// AverageLatency は成功した取得の平均レイテンシを返す
+func (g *GatewayStats) AverageLatency() time.Duration {
+	succeeded := g.Fetches - g.Failures
+	if succeeded <= 0 {
+		return 0
+	}
+	return time.Duration(g.TotalLatencyMs/int64(succeeded)) * time.Millisecond
+}
+
+// ProbeStats はモデルごとのprobeの消費量
+type ProbeStats struct {
+	Runs         int     `json:"runs"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	Cost         float64 `json:"cost"`
+}
+
+// TotalProbeSpend はすべてのモデルのprobeの消費量を合計する
+func (s *Stats) TotalProbeSpend() ProbeStats {
+	var total ProbeStats
+	for _, p := range s.Probes {
+		total.Runs += p.Runs
+		total.InputTokens += p.InputTokens
+		total.OutputTokens += p.OutputTokens
+		total.Cost += p.Cost
+	}
+	return total
+}
+
+// newStats は空の統計を作成する
+func newStats() *Stats {
+	now := time.Now()
+	return &Stats{
+		Version:   fileVersion,
+		Since:     now,
+		UpdatedAt: now,
+		Commands:  make(map[string]int),
+		Gateways:  make(map[string]*GatewayStats),
+		Probes:    make(map[string]*ProbeStats),
+	}
+}
+
+// Load は統計ファイルを読み込む
+// ファイルが存在しない場合は空の統計を返す
+func Load(path string) (*Stats, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return newStats(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stats file: %w", err)
+	}
+
+	stats := newStats()
+	if err := json.Unmarshal(data, stats); err != nil {
+		return nil, fmt.Errorf("failed to parse stats file %s: %w", path, err)
+	}
+	if stats.Commands == nil {
+		stats.Commands = make(map[string]int)
+	}
+	if stats.Gateways == nil {
+		stats.Gateways = make(map[string]*GatewayStats)
+	}
+	if stats.Probes == nil {
+		stats.Probes = make(map[string]*ProbeStats)
+	}
+	return stats, nil
+}
+
+// Save は統計ファイルを書き込む
+func (s *Stats) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create stats directory: %w", err)
+	}
+
+	s.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats: %w", err)
+	}
+
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write stats file: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to write stats file: %w", err)
+	}
+	return nil
+}
+
+// Reset は統計ファイルを削除する
+func Reset(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove stats file: %w", err)
+	}
+	return nil
+}
+
+// Recorder は有効な場合のみ統計ファイルを更新する
+// 統計の記録でコマンドを失敗させないよう、書き込みエラーは無視する
+type Recorder struct {
+	path    string
+	enabled bool
+}
+
+// NewRecorder は新しいRecorderを作成する
+func NewRecorder(path string, enabled bool) *Recorder {
+	if path == "" {
+		path = GetDefaultStatsFile()
+	}
+	return &Recorder{path: path, enabled: enabled}
+}
+
+// Enabled は記録が有効かどうかを返す
+func (r *Recorder) Enabled() bool {
+	return r != nil && r.enabled
+}
+
+// Path は統計ファイルのパスを返す
+func (r *Recorder) Path() string {
+	return r.path
+}
+
+// RecordCommand はコマンドの実行を1回記録する
+func (r *Recorder) RecordCommand(name string) {
+	r.update(func(s *Stats) {
+		s.Commands[name]++
+	})
+}
+
+// RecordFetch はゲートウェイからのモデル一覧取得を記録する
+// 失敗した取得はレイテンシの平均に含めない
+func (r *Recorder) RecordFetch(gateway string, latency time.Duration, err error) {
+	r.update(func(s *Stats) {
+		g, ok := s.Gateways[gateway]
+		if !ok {
+			g = &GatewayStats{}
+			s.Gateways[gateway] = g
+		}
+		g.Fetches++
+		if err != nil {
+			g.Failures++
+			return
+		}
+		g.TotalLatencyMs += latency.Milliseconds()
+	})
+}
+
+// RecordProbe はprobeの消費トークンとコストを記録する
+func (r *Recorder) RecordProbe(model string, inputTokens, outputTokens int, cost float64) {
+	r.update(func(s *Stats) {
+		p, ok := s.Probes[model]
+		if !ok {
+			p = &ProbeStats{}
+			s.Probes[model] = p
+		}
+		p.Runs++
+		p.InputTokens += inputTokens
+		p.OutputTokens += outputTokens
+		p.Cost += cost
+	})
+}
+
+// update は統計ファイルを読み込み、変更して書き戻す
+func (r *Recorder) update(fn func(*Stats)) {
+	if !r.Enabled() {
+		return
+	}
+	stats, err := Load(r.path)
+	if err != nil {
+		// 壊れたファイルは集計し直す
+		stats = newStats()
+	}
+	fn(stats)
+	stats.Save(r.path)
+}
@@ -0,0 +1,115 @@
+package stats
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecorder_Disabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	recorder := NewRecorder(path, false)
+
+	recorder.RecordCommand("list")
+	recorder.RecordFetch("production", time.Second, nil)
+	recorder.RecordProbe("gpt-4o", 100, 10, 0.01)
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("disabled recorder should not create %s", path)
+	}
+}
+
+func TestRecorder_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "stats.json")
+	recorder := NewRecorder(path, true)
+
+	recorder.RecordCommand("list")
+	recorder.RecordCommand("list")
+	recorder.RecordCommand("probe")
+	recorder.RecordFetch("production", 100*time.Millisecond, nil)
+	recorder.RecordFetch("production", 300*time.Millisecond, nil)
+	recorder.RecordFetch("production", 5*time.Second, errors.New("timeout"))
+	recorder.RecordProbe("gpt-4o", 1000, 50, 0.02)
+	recorder.RecordProbe("gpt-4o", 500, 25, 0.01)
+	recorder.RecordProbe("claude-3-haiku", 200, 10, 0)
+
+	stats, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if stats.Commands["list"] != 2 || stats.Commands["probe"] != 1 {
+		t.Errorf("Commands = %v, want list=2 probe=1", stats.Commands)
+	}
+
+	gw := stats.Gateways["production"]
+	if gw == nil {
+		t.Fatal("expected stats for gateway production")
+	}
+	if gw.Fetches != 3 || gw.Failures != 1 {
+		t.Errorf("Fetches = %d, Failures = %d, want 3 and 1", gw.Fetches, gw.Failures)
+	}
+	// 失敗した取得は平均に含めない
+	if gw.AverageLatency() != 200*time.Millisecond {
+		t.Errorf("AverageLatency() = %v, want 200ms", gw.AverageLatency())
+	}
+
+	p := stats.Probes["gpt-4o"]
+	if p == nil || p.Runs != 2 || p.InputTokens != 1500 || p.OutputTokens != 75 {
+		t.Errorf("Probes[gpt-4o] = %+v", p)
+	}
+
+	total := stats.TotalProbeSpend()
+	if total.Runs != 3 || total.InputTokens != 1700 || total.OutputTokens != 85 {
+		t.Errorf("TotalProbeSpend() = %+v", total)
+	}
+	if total.Cost < 0.0299 || total.Cost > 0.0301 {
+		t.Errorf("TotalProbeSpend().Cost = %v, want 0.03", total.Cost)
+	}
+}
+
+func TestRecorder_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("failed to write stats file: %v", err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Error("Load() should report a corrupt stats file")
+	}
+
+	// 記録は壊れたファイルを置き換えて続行する
+	NewRecorder(path, true).RecordCommand("list")
+	stats, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() after recording error = %v", err)
+	}
+	if stats.Commands["list"] != 1 {
+		t.Errorf("Commands = %v, want list=1", stats.Commands)
+	}
+}
+
+func TestLoadAndReset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+
+	stats, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of a missing file error = %v", err)
+	}
+	if len(stats.Commands) != 0 || stats.Version != fileVersion {
+		t.Errorf("Load() of a missing file = %+v, want empty stats", stats)
+	}
+
+	NewRecorder(path, true).RecordCommand("list")
+	if err := Reset(path); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Reset() should remove the stats file")
+	}
+	if err := Reset(path); err != nil {
+		t.Errorf("Reset() of a missing file error = %v", err)
+	}
+}
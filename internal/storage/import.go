@@ -0,0 +1,312 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxClockSkew is how far in the future an imported result may be dated
+const maxClockSkew = time.Hour
+
+// ImportOutcome describes what happened to one result during an import
+type ImportOutcome struct {
+	Source    string    `json:"source"`
+	Provider  string    `json:"provider,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	Type      string    `json:"type,omitempty"`
+	SavedAt   time.Time `json:"saved_at,omitempty"`
+	Path      string    `json:"path,omitempty"` // relative to the result directory
+	Duplicate bool      `json:"duplicate,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// ImportCandidate is a result read from an import source, before validation
+type ImportCandidate struct {
+	Source string
+	Result *SavedResult
+	Err    error // set when the source could not be parsed
+}
+
+// ReadImportSource reads results from a file or, recursively, a directory.
+// Files may hold a single saved result or an array of them and may be
+// gzip-compressed (.gz). Index files are skipped so another machine's
+// result directory can be imported as a whole.
+func ReadImportSource(path string) ([]ImportCandidate, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return readImportFile(path), nil
+	}
+
+	var candidates []ImportCandidate
+	err = filepath.WalkDir(path, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isResultFile(d.Name()) {
+			return nil
+		}
+		candidates = append(candidates, readImportFile(filePath)...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", path, err)
+	}
+	return candidates, nil
+}
+
+// readImportFile parses a single import file
+func readImportFile(path string) []ImportCandidate {
+	data, err := readMaybeCompressed(path)
+	if err != nil {
+		return []ImportCandidate{{Source: path, Err: err}}
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var results []*SavedResult
+		if err := json.Unmarshal(trimmed, &results); err != nil {
+			return []ImportCandidate{{Source: path, Err: fmt.Errorf("invalid JSON: %w", err)}}
+		}
+		candidates := make([]ImportCandidate, len(results))
+		for i, result := range results {
+			candidates[i] = ImportCandidate{Source: fmt.Sprintf("%s[%d]", path, i), Result: result}
+		}
+		return candidates
+	}
+
+	var result SavedResult
+	if err := json.Unmarshal(trimmed, &result); err != nil {
+		return []ImportCandidate{{Source: path, Err: fmt.Errorf("invalid JSON: %w", err)}}
+	}
+	return []ImportCandidate{{Source: path, Result: &result}}
+}
+
+// readMaybeCompressed reads a file, decompressing it if it ends in .gz
+func readMaybeCompressed(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	return io.ReadAll(reader)
+}
+
+// ValidateSavedResult checks that a result produced elsewhere is complete
+// enough to be indexed and read back by this version
+func ValidateSavedResult(result *SavedResult, now time.Time) error {
+	if result == nil {
+		return fmt.Errorf("empty result")
+	}
+	if err := validateName("provider", result.Provider); err != nil {
+		return err
+	}
+	if err := validateName("model", result.Model); err != nil {
+		return err
+	}
+	if result.EstimatedAt.IsZero() {
+		return fmt.Errorf("estimated_at is missing")
+	}
+	if result.EstimatedAt.After(now.Add(maxClockSkew)) {
+		return fmt.Errorf("estimated_at %s is in the future", result.EstimatedAt.Format(time.RFC3339))
+	}
+
+	types := 0
+	for _, part := range []struct {
+		name  string
+		value interface{}
+	}{
+		{ResultTypeContextWindow, result.ContextWindow},
+		{ResultTypeMaxOutput, result.MaxOutput},
+		{ResultTypeCapabilities, result.Capabilities},
+		{ResultTypeMultiTurn, result.MultiTurn},
+	} {
+		if part.value == nil {
+			continue
+		}
+		types++
+		fields, ok := part.value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an object", part.name)
+		}
+		if part.name == ResultTypeCapabilities {
+			continue
+		}
+		if success, ok := fields["success"]; ok {
+			if _, isBool := success.(bool); !isBool {
+				return fmt.Errorf("%s.success must be a boolean", part.name)
+			}
+		}
+		if value, ok := fields["value"]; ok {
+			number, isNumber := value.(float64)
+			if !isNumber || number < 0 {
+				return fmt.Errorf("%s.value must be a non-negative number", part.name)
+			}
+		} else if success, _ := fields["success"].(bool); success {
+			return fmt.Errorf("%s.value is missing", part.name)
+		}
+	}
+	if types == 0 {
+		return fmt.Errorf("no context_window, max_output, capabilities or multi_turn_context result")
+	}
+	return nil
+}
+
+// validateName rejects names that would escape the result directory
+func validateName(field, name string) error {
+	switch strings.TrimSpace(name) {
+	case "":
+		return fmt.Errorf("%s is missing", field)
+	case ".", "..":
+		return fmt.Errorf("invalid %s: %q", field, name)
+	}
+	return nil
+}
+
+// Import validates the candidates and writes the valid ones into the
+// partitioned layout, keeping their original estimated_at. Results that are
+// already indexed (same provider, model, type and time) are reported as
+// duplicates, so importing the same file twice is harmless. With dryRun
+// nothing is written.
+func (s *PartitionedResultStorage) Import(candidates []ImportCandidate, dryRun bool) ([]ImportOutcome, error) {
+	index, err := OpenIndex(s.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var outcomes []ImportOutcome
+	changed := false
+	for _, candidate := range candidates {
+		if candidate.Err != nil {
+			outcomes = append(outcomes, ImportOutcome{Source: candidate.Source, Error: candidate.Err.Error()})
+			continue
+		}
+		result := candidate.Result
+		if err := ValidateSavedResult(result, now); err != nil {
+			outcomes = append(outcomes, ImportOutcome{Source: candidate.Source, Error: err.Error()})
+			continue
+		}
+
+		// A legacy file may hold several result types; each is stored on its own
+		for _, part := range splitResultTypes(result) {
+			outcome := ImportOutcome{
+				Source:   candidate.Source,
+				Provider: part.Provider,
+				Model:    part.Model,
+				Type:     part.resultType,
+				SavedAt:  part.EstimatedAt,
+			}
+			if existing, ok := index.lookup(part.Provider, part.Model, part.resultType, part.EstimatedAt); ok {
+				outcome.Path = existing.Path
+				outcome.Duplicate = true
+				outcomes = append(outcomes, outcome)
+				continue
+			}
+
+			outcome.Path = partitionPath(part.Provider, part.Model, part.resultType, part.EstimatedAt, s.compress)
+			if !dryRun {
+				filePath := filepath.Join(s.baseDir, outcome.Path)
+				if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+					return outcomes, fmt.Errorf("failed to create partition directory: %w", err)
+				}
+				if part.Source == "" {
+					part.Source = candidate.Source
+				}
+				size, err := writeResultFile(filePath, part.SavedResult, s.compress)
+				if err != nil {
+					return outcomes, err
+				}
+				index.Add(IndexEntry{
+					Provider:   part.Provider,
+					Model:      part.Model,
+					Type:       part.resultType,
+					Path:       outcome.Path,
+					SavedAt:    part.EstimatedAt,
+					Size:       size,
+					Compressed: s.compress,
+					Source:     part.Source,
+				})
+				changed = true
+				if err := s.upload(outcome.Path); err != nil {
+					index.Save(s.baseDir)
+					return outcomes, err
+				}
+			}
+			outcomes = append(outcomes, outcome)
+		}
+	}
+
+	if changed {
+		if err := index.Save(s.baseDir); err != nil {
+			return outcomes, err
+		}
+	}
+	return outcomes, nil
+}
+
+// typedResult is a saved result holding exactly one result type
+type typedResult struct {
+	SavedResult
+	resultType string
+}
+
+// splitResultTypes returns one result per result type present in r
+func splitResultTypes(r *SavedResult) []typedResult {
+	base := *r
+	base.ContextWindow, base.MaxOutput, base.Capabilities, base.MultiTurn = nil, nil, nil, nil
+
+	var parts []typedResult
+	if r.ContextWindow != nil {
+		part := typedResult{SavedResult: base, resultType: ResultTypeContextWindow}
+		part.ContextWindow = r.ContextWindow
+		parts = append(parts, part)
+	}
+	if r.MaxOutput != nil {
+		part := typedResult{SavedResult: base, resultType: ResultTypeMaxOutput}
+		part.MaxOutput = r.MaxOutput
+		parts = append(parts, part)
+	}
+	if r.Capabilities != nil {
+		part := typedResult{SavedResult: base, resultType: ResultTypeCapabilities}
+		part.Capabilities = r.Capabilities
+		parts = append(parts, part)
+	}
+	if r.MultiTurn != nil {
+		part := typedResult{SavedResult: base, resultType: ResultTypeMultiTurn}
+		part.MultiTurn = r.MultiTurn
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// lookup finds the entry for a result of the given type saved at t
+func (idx *Index) lookup(provider, model, resultType string, t time.Time) (IndexEntry, bool) {
+	for _, entry := range idx.Entries {
+		if entry.Type == resultType && entry.SavedAt.Equal(t) &&
+			strings.EqualFold(entry.Provider, provider) && strings.EqualFold(entry.Model, model) {
+			return entry, true
+		}
+	}
+	return IndexEntry{}, false
+}
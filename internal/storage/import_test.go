@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeImportFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestImport(t *testing.T) {
+	src := t.TempDir()
+	path := writeImportFile(t, src, "ci.json", `[
+		{"provider": "openai", "model": "gpt-4o", "estimated_at": "2026-10-01T12:00:00Z",
+		 "context_window": {"value": 128000, "success": true}, "llm_info_version": "2.1.0"},
+		{"provider": "openai", "model": "gpt-4o", "estimated_at": "2026-10-01T12:05:00Z",
+		 "context_window": {"value": 127000}, "max_output": {"value": 16384}}
+	]`)
+
+	s, err := NewResultStorageWithOptions(t.TempDir(), Options{})
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+
+	candidates, err := ReadImportSource(path)
+	if err != nil {
+		t.Fatalf("ReadImportSource() error = %v", err)
+	}
+	outcomes, err := s.Import(candidates, false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if len(outcomes) != 3 {
+		t.Fatalf("outcomes = %d, want 3 (the second result holds two types)", len(outcomes))
+	}
+	for _, outcome := range outcomes {
+		if outcome.Error != "" || outcome.Duplicate {
+			t.Errorf("unexpected outcome: %+v", outcome)
+		}
+	}
+
+	// The newest context window result is the imported one and keeps its time
+	loaded, err := s.LoadContextResult("openai", "gpt-4o")
+	if err != nil {
+		t.Fatalf("LoadContextResult() error = %v", err)
+	}
+	if fields := loaded.(map[string]interface{}); fields["value"] != float64(127000) {
+		t.Errorf("LoadContextResult() = %v, want value 127000", loaded)
+	}
+	index, err := LoadIndex(s.BaseDir())
+	if err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+	entries := index.Find(IndexQuery{Type: ResultTypeMaxOutput})
+	if len(entries) != 1 || !entries[0].SavedAt.Equal(time.Date(2026, 10, 1, 12, 5, 0, 0, time.UTC)) {
+		t.Fatalf("max output entries = %+v", entries)
+	}
+	if !strings.HasPrefix(entries[0].Path, filepath.Join("openai", "gpt-4o", "2026-10-01")) {
+		t.Errorf("Path = %s, want the partition of the original date", entries[0].Path)
+	}
+	if entries[0].Source != path+"[1]" {
+		t.Errorf("Source = %q, want %q", entries[0].Source, path+"[1]")
+	}
+
+	// Importing the same file again only reports duplicates
+	outcomes, err = s.Import(candidates, false)
+	if err != nil {
+		t.Fatalf("second Import() error = %v", err)
+	}
+	for _, outcome := range outcomes {
+		if !outcome.Duplicate {
+			t.Errorf("expected duplicate on re-import: %+v", outcome)
+		}
+	}
+
+	// The source survives an index rebuild
+	index, err = RebuildIndex(s.BaseDir())
+	if err != nil {
+		t.Fatalf("RebuildIndex() error = %v", err)
+	}
+	if len(index.Entries) != 3 {
+		t.Errorf("rebuilt index has %d entries, want 3", len(index.Entries))
+	}
+	for _, entry := range index.Entries {
+		if entry.Source == "" {
+			t.Errorf("rebuilt entry lost its source: %+v", entry)
+		}
+	}
+}
+
+func TestImport_DryRun(t *testing.T) {
+	src := t.TempDir()
+	path := writeImportFile(t, src, "one.json", `{"provider": "anthropic", "model": "claude", "estimated_at": "2026-10-01T12:00:00Z", "max_output": {"value": 8192}}`)
+
+	dir := t.TempDir()
+	s, err := NewResultStorageWithOptions(dir, Options{})
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+	candidates, err := ReadImportSource(path)
+	if err != nil {
+		t.Fatalf("ReadImportSource() error = %v", err)
+	}
+	outcomes, err := s.Import(candidates, true)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if len(outcomes) != 1 || outcomes[0].Path == "" {
+		t.Fatalf("outcomes = %+v", outcomes)
+	}
+	if _, err := os.Stat(filepath.Join(dir, outcomes[0].Path)); !os.IsNotExist(err) {
+		t.Error("dry run must not write result files")
+	}
+}
+
+func TestReadImportSource_Directory(t *testing.T) {
+	// A result directory from another machine, including its index
+	other := t.TempDir()
+	remote, err := NewResultStorageWithOptions(other, Options{Compress: true})
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+	if err := remote.SaveMaxOutputResult("openai", "gpt-4o-mini", map[string]interface{}{"value": 16384}); err != nil {
+		t.Fatalf("SaveMaxOutputResult() error = %v", err)
+	}
+	writeImportFile(t, other, "notes.json", `not json`)
+
+	candidates, err := ReadImportSource(other)
+	if err != nil {
+		t.Fatalf("ReadImportSource() error = %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("candidates = %d, want 2 (index.json is skipped)", len(candidates))
+	}
+
+	local, err := NewResultStorageWithOptions(t.TempDir(), Options{})
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+	outcomes, err := local.Import(candidates, false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	var imported, failed int
+	for _, outcome := range outcomes {
+		if outcome.Error != "" {
+			failed++
+		} else {
+			imported++
+		}
+	}
+	if imported != 1 || failed != 1 {
+		t.Errorf("imported = %d, failed = %d, want 1, 1", imported, failed)
+	}
+	if _, err := local.LoadMaxOutputResult("openai", "gpt-4o-mini"); err != nil {
+		t.Errorf("LoadMaxOutputResult() error = %v", err)
+	}
+}
+
+func TestValidateSavedResult(t *testing.T) {
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	at := now.Add(-time.Hour)
+	tests := []struct {
+		name    string
+		result  SavedResult
+		wantErr string
+	}{
+		{"valid", SavedResult{Provider: "openai", Model: "gpt-4o", EstimatedAt: at, ContextWindow: map[string]interface{}{"value": 128000.0}}, ""},
+		{"failed probe", SavedResult{Provider: "openai", Model: "gpt-4o", EstimatedAt: at, MaxOutput: map[string]interface{}{"success": false}}, ""},
+		{"capabilities", SavedResult{Provider: "openai", Model: "gpt-4o", EstimatedAt: at, Capabilities: map[string]interface{}{}}, ""},
+		{"missing provider", SavedResult{Model: "gpt-4o", EstimatedAt: at, ContextWindow: map[string]interface{}{}}, "provider is missing"},
+		{"path traversal", SavedResult{Provider: "..", Model: "gpt-4o", EstimatedAt: at, ContextWindow: map[string]interface{}{}}, "invalid provider"},
+		{"missing time", SavedResult{Provider: "openai", Model: "gpt-4o", ContextWindow: map[string]interface{}{}}, "estimated_at is missing"},
+		{"future", SavedResult{Provider: "openai", Model: "gpt-4o", EstimatedAt: now.Add(2 * time.Hour), ContextWindow: map[string]interface{}{}}, "in the future"},
+		{"no result", SavedResult{Provider: "openai", Model: "gpt-4o", EstimatedAt: at}, "no context_window"},
+		{"not an object", SavedResult{Provider: "openai", Model: "gpt-4o", EstimatedAt: at, ContextWindow: 128000.0}, "must be an object"},
+		{"negative value", SavedResult{Provider: "openai", Model: "gpt-4o", EstimatedAt: at, ContextWindow: map[string]interface{}{"value": -1.0}}, "non-negative"},
+		{"success without value", SavedResult{Provider: "openai", Model: "gpt-4o", EstimatedAt: at, ContextWindow: map[string]interface{}{"success": true}}, "value is missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSavedResult(&tt.result, now)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateSavedResult() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateSavedResult() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IndexFileName is the name of the index file in the result directory
+const IndexFileName = "index.json"
+
+// indexVersion is the current index file format version
+const indexVersion = 1
+
+// IndexEntry describes a single saved result file
+type IndexEntry struct {
+	Provider   string    `json:"provider"`
+	Model      string    `json:"model"`
+	Type       string    `json:"type"`
+	Path       string    `json:"path"` // relative to the result directory
+	SavedAt    time.Time `json:"saved_at"`
+	Size       int64     `json:"size"`
+	Compressed bool      `json:"compressed,omitempty"`
+	Source     string    `json:"source,omitempty"` // import source of results produced elsewhere
+}
+
+// Index lists saved results so they can be looked up without walking the tree
+type Index struct {
+	Version   int          `json:"version"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	Entries   []IndexEntry `json:"entries"`
+}
+
+// IndexQuery filters index entries. Empty fields match everything.
+type IndexQuery struct {
+	Provider string
+	Model    string
+	Type     string
+	Since    time.Time
+	Limit    int
+}
+
+// LoadIndex reads the index in baseDir. A missing index is returned as empty.
+func LoadIndex(baseDir string) (*Index, error) {
+	data, err := os.ReadFile(filepath.Join(baseDir, IndexFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Index{Version: indexVersion}, nil
+		}
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse index: %w", err)
+	}
+	if index.Version > indexVersion {
+		return nil, fmt.Errorf("unsupported index version: %d", index.Version)
+	}
+
+	return &index, nil
+}
+
+// OpenIndex loads the index in baseDir, rebuilding it when it is missing or
+// unreadable (for example in directories written by earlier versions)
+func OpenIndex(baseDir string) (*Index, error) {
+	if _, err := os.Stat(filepath.Join(baseDir, IndexFileName)); err == nil {
+		if index, err := LoadIndex(baseDir); err == nil {
+			return index, nil
+		}
+	}
+	return RebuildIndex(baseDir)
+}
+
+// Save writes the index to baseDir
+func (idx *Index) Save(baseDir string) error {
+	idx.Version = indexVersion
+	idx.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal index: %w", err)
+	}
+
+	return writeFileAtomic(filepath.Join(baseDir, IndexFileName), data)
+}
+
+// Add appends an entry to the index
+func (idx *Index) Add(entry IndexEntry) {
+	idx.Entries = append(idx.Entries, entry)
+}
+
+// Find returns the entries matching the query, newest first
+func (idx *Index) Find(q IndexQuery) []IndexEntry {
+	var matches []IndexEntry
+	for _, entry := range idx.Entries {
+		if q.Provider != "" && !strings.EqualFold(entry.Provider, q.Provider) {
+			continue
+		}
+		if q.Model != "" && !strings.EqualFold(entry.Model, q.Model) {
+			continue
+		}
+		if q.Type != "" && entry.Type != q.Type {
+			continue
+		}
+		if !q.Since.IsZero() && entry.SavedAt.Before(q.Since) {
+			continue
+		}
+		matches = append(matches, entry)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].SavedAt.After(matches[j].SavedAt)
+	})
+
+	if q.Limit > 0 && len(matches) > q.Limit {
+		matches = matches[:q.Limit]
+	}
+	return matches
+}
+
+// removePaths drops entries whose files were removed
+func (idx *Index) removePaths(paths map[string]bool) {
+	kept := idx.Entries[:0]
+	for _, entry := range idx.Entries {
+		if !paths[entry.Path] {
+			kept = append(kept, entry)
+		}
+	}
+	idx.Entries = kept
+}
+
+// RebuildIndex scans baseDir and writes a fresh index. Flat result files written
+// by earlier versions are included so they remain searchable.
+func RebuildIndex(baseDir string) (*Index, error) {
+	index := &Index{Version: indexVersion}
+
+	err := filepath.WalkDir(baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isResultFile(d.Name()) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		saved, err := ReadSavedResult(path)
+		if err != nil {
+			// Skip files that are not results written by llm-info
+			return nil
+		}
+
+		relPath, err := filepath.Rel(baseDir, path)
+		if err != nil {
+			return nil
+		}
+
+		entry := IndexEntry{
+			Provider:   saved.Provider,
+			Model:      saved.Model,
+			Path:       relPath,
+			SavedAt:    saved.EstimatedAt,
+			Size:       info.Size(),
+			Compressed: strings.HasSuffix(path, ".gz"),
+			Source:     saved.Source,
+		}
+		if entry.Provider == "" || entry.Model == "" {
+			entry.Provider, entry.Model = namesFromPath(relPath)
+		}
+		if entry.SavedAt.IsZero() {
+			entry.SavedAt = info.ModTime()
+		}
+
+		// Legacy files may hold both result types
+		if saved.ContextWindow != nil {
+			entry.Type = ResultTypeContextWindow
+			index.Add(entry)
+		}
+		if saved.MaxOutput != nil {
+			entry.Type = ResultTypeMaxOutput
+			index.Add(entry)
+		}
+		if saved.Capabilities != nil {
+			entry.Type = ResultTypeCapabilities
+			index.Add(entry)
+		}
+		if saved.MultiTurn != nil {
+			entry.Type = ResultTypeMultiTurn
+			index.Add(entry)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to scan result directory: %w", err)
+	}
+
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create result directory: %w", err)
+	}
+	if err := index.Save(baseDir); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// isResultFile reports whether name looks like a saved result file
+func isResultFile(name string) bool {
+	if name == IndexFileName {
+		return false
+	}
+	return strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".json.gz")
+}
+
+// namesFromPath derives provider and model from a partitioned or legacy path
+func namesFromPath(relPath string) (string, string) {
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	if len(parts) >= 4 {
+		return parts[0], parts[1]
+	}
+
+	// Legacy layout: <provider>-<model>.json
+	name := strings.TrimSuffix(parts[len(parts)-1], ".json")
+	if provider, model, ok := strings.Cut(name, "-"); ok {
+		return provider, model
+	}
+	return "", name
+}
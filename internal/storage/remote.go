@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// RemoteStore is an object store shared by the team. Result files are
+// immutable and uniquely named, so the partitioned layout is mirrored as-is
+// and each machine keeps its own index over a local cache of the files.
+type RemoteStore interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+	List(prefix string) ([]string, error)
+}
+
+// remoteMirror maps result paths to keys under a prefix of a RemoteStore
+type remoteMirror struct {
+	store  RemoteStore
+	prefix string
+}
+
+// key returns the object key of a result path relative to the base directory
+func (m *remoteMirror) key(relPath string) string {
+	return path.Join(m.prefix, filepath.ToSlash(relPath))
+}
+
+// relPath returns the local path of an object key, or false for keys that
+// are outside the prefix or would escape the result directory
+func (m *remoteMirror) relPath(key string) (string, bool) {
+	rel := key
+	if m.prefix != "" {
+		var ok bool
+		rel, ok = strings.CutPrefix(key, m.prefix+"/")
+		if !ok {
+			return "", false
+		}
+	}
+	rel = path.Clean(rel)
+	if rel == "." || strings.HasPrefix(rel, "../") || path.IsAbs(rel) || !isResultFile(path.Base(rel)) {
+		return "", false
+	}
+	return filepath.FromSlash(rel), true
+}
+
+// upload copies a local result file to the remote store
+func (s *PartitionedResultStorage) upload(relPath string) error {
+	if s.remote == nil {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(s.baseDir, relPath))
+	if err != nil {
+		return fmt.Errorf("failed to read %s for upload: %w", relPath, err)
+	}
+	if err := s.remote.store.Put(s.remote.key(relPath), data); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", relPath, err)
+	}
+	return nil
+}
+
+// SyncReport counts the files transferred by Pull and Push
+type SyncReport struct {
+	Downloaded int
+	Uploaded   int
+}
+
+// HasRemote reports whether a shared remote store is configured
+func (s *PartitionedResultStorage) HasRemote() bool {
+	return s.remote != nil
+}
+
+// Pull downloads the remote results under sub (e.g. "<provider>/<model>",
+// or "" for everything) that are missing from the local cache and adds them
+// to the index
+func (s *PartitionedResultStorage) Pull(sub string) (SyncReport, error) {
+	var report SyncReport
+	if s.remote == nil {
+		return report, nil
+	}
+
+	prefix := s.remote.prefix
+	if sub != "" {
+		prefix = path.Join(prefix, sub)
+	}
+	if prefix != "" {
+		prefix += "/"
+	}
+	keys, err := s.remote.store.List(prefix)
+	if err != nil {
+		return report, err
+	}
+
+	index, err := OpenIndex(s.baseDir)
+	if err != nil {
+		return report, err
+	}
+	for _, key := range keys {
+		relPath, ok := s.remote.relPath(key)
+		if !ok {
+			continue
+		}
+		filePath := filepath.Join(s.baseDir, relPath)
+		if _, err := os.Stat(filePath); err == nil {
+			continue
+		}
+
+		data, err := s.remote.store.Get(key)
+		if err != nil {
+			return report, err
+		}
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return report, fmt.Errorf("failed to create partition directory: %w", err)
+		}
+		if err := writeFileAtomic(filePath, data); err != nil {
+			return report, err
+		}
+
+		saved, err := ReadSavedResult(filePath)
+		if err != nil {
+			// Not a result written by llm-info; keep the cache consistent with the index
+			os.Remove(filePath)
+			continue
+		}
+		for _, part := range splitResultTypes(saved) {
+			index.Add(IndexEntry{
+				Provider:   part.Provider,
+				Model:      part.Model,
+				Type:       part.resultType,
+				Path:       relPath,
+				SavedAt:    part.EstimatedAt,
+				Size:       int64(len(data)),
+				Compressed: strings.HasSuffix(relPath, ".gz"),
+				Source:     part.Source,
+			})
+		}
+		report.Downloaded++
+	}
+
+	if report.Downloaded > 0 {
+		if err := index.Save(s.baseDir); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// Push uploads the indexed local results that are missing from the remote
+// store, e.g. history saved before the remote was configured
+func (s *PartitionedResultStorage) Push() (SyncReport, error) {
+	var report SyncReport
+	if s.remote == nil {
+		return report, nil
+	}
+
+	prefix := s.remote.prefix
+	if prefix != "" {
+		prefix += "/"
+	}
+	keys, err := s.remote.store.List(prefix)
+	if err != nil {
+		return report, err
+	}
+	existing := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		existing[key] = true
+	}
+
+	index, err := OpenIndex(s.baseDir)
+	if err != nil {
+		return report, err
+	}
+	for _, entry := range index.Entries {
+		// Flat files written by earlier versions are not mirrored
+		if len(strings.Split(filepath.ToSlash(entry.Path), "/")) < 4 {
+			continue
+		}
+		key := s.remote.key(entry.Path)
+		if existing[key] {
+			continue
+		}
+		if err := s.upload(entry.Path); err != nil {
+			return report, err
+		}
+		existing[key] = true
+		report.Uploaded++
+	}
+	return report, nil
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRemote_SharedHistory(t *testing.T) {
+	remote, fake := newFakeS3Store(t, "AKID")
+	opts := Options{Remote: remote, RemotePrefix: "/llm-info/estimates/"}
+
+	alice, err := NewResultStorageWithOptions(t.TempDir(), opts)
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+	bob, err := NewResultStorageWithOptions(t.TempDir(), opts)
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+
+	if err := alice.SaveContextResult("openai", "gpt-4o", map[string]interface{}{"value": 128000}); err != nil {
+		t.Fatalf("SaveContextResult() error = %v", err)
+	}
+	if len(fake.objects) != 1 {
+		t.Fatalf("remote holds %d objects, want 1", len(fake.objects))
+	}
+	for key := range fake.objects {
+		if filepath.Dir(filepath.Dir(filepath.Dir(key))) != "llm-info/estimates/openai" {
+			t.Errorf("key = %s, want it under llm-info/estimates/openai/gpt-4o", key)
+		}
+	}
+
+	// Bob reads Alice's result through his local cache
+	loaded, err := bob.LoadContextResult("openai", "gpt-4o")
+	if err != nil {
+		t.Fatalf("LoadContextResult() error = %v", err)
+	}
+	if fields := loaded.(map[string]interface{}); fields["value"] != float64(128000) {
+		t.Errorf("LoadContextResult() = %v", loaded)
+	}
+	index, err := LoadIndex(bob.BaseDir())
+	if err != nil || len(index.Entries) != 1 {
+		t.Fatalf("Bob's index = %+v, %v; want 1 entry", index, err)
+	}
+
+	// A second pull finds nothing new
+	report, err := bob.Pull("")
+	if err != nil || report.Downloaded != 0 {
+		t.Errorf("Pull() = %+v, %v; want nothing downloaded", report, err)
+	}
+}
+
+func TestRemote_PushExistingHistory(t *testing.T) {
+	dir := t.TempDir()
+	local, err := NewResultStorageWithOptions(dir, Options{})
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+	if err := local.SaveMaxOutputResult("anthropic", "claude", map[string]interface{}{"value": 8192}); err != nil {
+		t.Fatalf("SaveMaxOutputResult() error = %v", err)
+	}
+
+	remote, fake := newFakeS3Store(t, "AKID")
+	shared, err := NewResultStorageWithOptions(dir, Options{Remote: remote})
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+	report, err := shared.Push()
+	if err != nil || report.Uploaded != 1 {
+		t.Fatalf("Push() = %+v, %v; want 1 uploaded", report, err)
+	}
+	if report, _ := shared.Push(); report.Uploaded != 0 {
+		t.Errorf("second Push() uploaded %d, want 0", report.Uploaded)
+	}
+	if len(fake.objects) != 1 {
+		t.Errorf("remote holds %d objects, want 1", len(fake.objects))
+	}
+}
+
+func TestRemote_UnreachableFallsBackToCache(t *testing.T) {
+	remote := NewS3Store(S3Config{Endpoint: "http://127.0.0.1:1", Bucket: "b", PathStyle: true})
+	dir := t.TempDir()
+
+	local, err := NewResultStorageWithOptions(dir, Options{})
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+	if err := local.SaveContextResult("openai", "gpt-4o", map[string]interface{}{"value": 8192}); err != nil {
+		t.Fatalf("SaveContextResult() error = %v", err)
+	}
+
+	shared, err := NewResultStorageWithOptions(dir, Options{Remote: remote})
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+	if _, err := shared.LoadContextResult("openai", "gpt-4o"); err != nil {
+		t.Errorf("LoadContextResult() error = %v, want the cached result", err)
+	}
+	if err := shared.SaveContextResult("openai", "gpt-4o", map[string]interface{}{"value": 8192}); err == nil {
+		t.Error("SaveContextResult() should report the failed upload")
+	}
+}
+
+func TestRemoteMirror_RelPath(t *testing.T) {
+	m := &remoteMirror{prefix: "team"}
+	tests := []struct {
+		key  string
+		want string
+		ok   bool
+	}{
+		{"team/openai/gpt-4o/2026-10-01/120000.000-context_window.json", filepath.FromSlash("openai/gpt-4o/2026-10-01/120000.000-context_window.json"), true},
+		{"team/openai/gpt-4o/2026-10-01/notes.txt", "", false},
+		{"team/../etc/passwd.json", "", false},
+		{"other/openai/gpt-4o/2026-10-01/a.json", "", false},
+		{"team/index.json", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := m.relPath(tt.key)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("relPath(%q) = %q, %v; want %q, %v", tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -1,11 +1,19 @@
 package storage
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/armaniacs/llm-info/internal/paths"
+	"github.com/armaniacs/llm-info/internal/redact"
 )
 
 // ResultStorage defines the interface for storing probe results
@@ -16,28 +24,115 @@ type ResultStorage interface {
 	LoadMaxOutputResult(provider, model string) (interface{}, error)
 }
 
+// Result types stored by ResultStorage
+const (
+	ResultTypeContextWindow = "context_window"
+	ResultTypeMaxOutput     = "max_output"
+	ResultTypeCapabilities  = "capabilities"
+	ResultTypeMultiTurn     = "multi_turn_context"
+)
+
 // SavedResult represents the structure of saved probe results
 type SavedResult struct {
+	Provider       string      `json:"provider,omitempty"`
+	Model          string      `json:"model,omitempty"`
 	ContextWindow  interface{} `json:"context_window,omitempty"`
 	MaxOutput      interface{} `json:"max_output,omitempty"`
-	EstimatedAt    time.Time  `json:"estimated_at"`
-	LLMInfoVersion string     `json:"llm_info_version"`
+	Capabilities   interface{} `json:"capabilities,omitempty"`
+	MultiTurn      interface{} `json:"multi_turn_context,omitempty"`
+	EstimatedAt    time.Time   `json:"estimated_at"`
+	LLMInfoVersion string      `json:"llm_info_version"`
+	Source         string      `json:"source,omitempty"` // set when the result was imported from elsewhere
 }
 
-// JSONResultStorage implements ResultStorage with JSON files
-type JSONResultStorage struct {
-	baseDir string
+// fields returns the saved result of the given result type as decoded JSON
+func (r *SavedResult) fields(resultType string) (map[string]interface{}, bool) {
+	result := r.ContextWindow
+	switch resultType {
+	case ResultTypeMaxOutput:
+		result = r.MaxOutput
+	case ResultTypeMultiTurn:
+		result = r.MultiTurn
+	case ResultTypeCapabilities:
+		result = r.Capabilities
+	}
+	fields, ok := result.(map[string]interface{})
+	return fields, ok
 }
 
-// NewResultStorage creates a new result storage instance
-func NewResultStorage(baseDir string) (ResultStorage, error) {
-	// Expand ~ to home directory
-	if len(baseDir) > 0 && baseDir[0] == '~' {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
+// Value returns the measured value of the given result type, if present
+func (r *SavedResult) Value(resultType string) (int, bool) {
+	if resultType == ResultTypeCapabilities {
+		// Capabilities have no single measured value
+		return 0, false
+	}
+
+	fields, ok := r.fields(resultType)
+	if !ok {
+		return 0, false
+	}
+	if success, ok := fields["success"].(bool); ok && !success {
+		return 0, false
+	}
+	value, ok := fields["value"].(float64)
+	if !ok {
+		return 0, false
+	}
+	return int(value), true
+}
+
+// TrialLatencies returns the request latency of each recorded trial of the given result type
+// Trials saved before latencies were recorded are skipped
+func (r *SavedResult) TrialLatencies(resultType string) []time.Duration {
+	fields, ok := r.fields(resultType)
+	if !ok {
+		return nil
+	}
+	trials, _ := fields["trials"].([]interface{})
+	var latencies []time.Duration
+	for _, trial := range trials {
+		trialFields, ok := trial.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// time.Duration is encoded as nanoseconds
+		if latency, ok := trialFields["latency"].(float64); ok && latency > 0 {
+			latencies = append(latencies, time.Duration(latency))
 		}
-		baseDir = filepath.Join(home, baseDir[1:])
+	}
+	return latencies
+}
+
+// Options controls how results are written
+type Options struct {
+	// Compress writes results as gzip-compressed JSON (.json.gz)
+	Compress bool
+	// Remote mirrors results to a store shared by the team. The local
+	// directory then acts as a cache of the remote results.
+	Remote RemoteStore
+	// RemotePrefix is the key prefix of the results in the remote store
+	RemotePrefix string
+}
+
+// PartitionedResultStorage implements ResultStorage with one file per probe run,
+// partitioned as <provider>/<model>/<YYYY-MM-DD>/<time>-<type>.json[.gz] and
+// tracked by an index file in the base directory.
+type PartitionedResultStorage struct {
+	baseDir  string
+	compress bool
+	remote   *remoteMirror
+}
+
+// NewResultStorage creates a new result storage instance with default options
+func NewResultStorage(baseDir string) (ResultStorage, error) {
+	return NewResultStorageWithOptions(baseDir, Options{})
+}
+
+// NewResultStorageWithOptions creates a new result storage instance
+func NewResultStorageWithOptions(baseDir string, opts Options) (*PartitionedResultStorage, error) {
+	baseDir, err := ExpandPath(baseDir)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create directory if it doesn't exist
@@ -45,111 +140,240 @@ func NewResultStorage(baseDir string) (ResultStorage, error) {
 		return nil, fmt.Errorf("failed to create result directory: %w", err)
 	}
 
-	return &JSONResultStorage{
-		baseDir: baseDir,
-	}, nil
+	s := &PartitionedResultStorage{
+		baseDir:  baseDir,
+		compress: opts.Compress,
+	}
+	if opts.Remote != nil {
+		s.remote = &remoteMirror{store: opts.Remote, prefix: strings.Trim(opts.RemotePrefix, "/")}
+	}
+	return s, nil
+}
+
+// BaseDir returns the directory results are stored in
+func (s *PartitionedResultStorage) BaseDir() string {
+	return s.baseDir
 }
 
 // SaveContextResult saves a context window probe result
-func (s *JSONResultStorage) SaveContextResult(provider, model string, result interface{}) error {
-	fileName := fmt.Sprintf("%s-%s.json", sanitizeProviderName(provider), sanitizeModelName(model))
-	filePath := filepath.Join(s.baseDir, fileName)
-
-	// Try to load existing file
-	var existing SavedResult
-	if data, err := os.ReadFile(filePath); err == nil {
-		if err := json.Unmarshal(data, &existing); err != nil {
-			// If unmarshal fails, start fresh
-			existing = SavedResult{}
-		}
+func (s *PartitionedResultStorage) SaveContextResult(provider, model string, result interface{}) error {
+	return s.save(provider, model, ResultTypeContextWindow, result)
+}
+
+// SaveMaxOutputResult saves a max output probe result
+func (s *PartitionedResultStorage) SaveMaxOutputResult(provider, model string, result interface{}) error {
+	return s.save(provider, model, ResultTypeMaxOutput, result)
+}
+
+// SaveCapabilitiesResult saves a message role / parameter capabilities result
+func (s *PartitionedResultStorage) SaveCapabilitiesResult(provider, model string, result interface{}) error {
+	return s.save(provider, model, ResultTypeCapabilities, result)
+}
+
+// SaveMultiTurnResult saves a context window result probed with a multi-turn
+// conversation, kept apart from the single-shot context window result
+func (s *PartitionedResultStorage) SaveMultiTurnResult(provider, model string, result interface{}) error {
+	return s.save(provider, model, ResultTypeMultiTurn, result)
+}
+
+// LoadContextResult loads the latest context window probe result
+func (s *PartitionedResultStorage) LoadContextResult(provider, model string) (interface{}, error) {
+	saved, err := s.loadLatest(provider, model, ResultTypeContextWindow)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update with new result
-	existing.ContextWindow = result
-	existing.EstimatedAt = time.Now()
-	existing.LLMInfoVersion = "2.1.0"
+	if saved.ContextWindow == nil {
+		return nil, fmt.Errorf("no context window result found")
+	}
 
-	// Save to file
-	return s.saveToFile(filePath, existing)
+	return saved.ContextWindow, nil
 }
 
-// SaveMaxOutputResult saves a max output probe result
-func (s *JSONResultStorage) SaveMaxOutputResult(provider, model string, result interface{}) error {
-	fileName := fmt.Sprintf("%s-%s.json", sanitizeProviderName(provider), sanitizeModelName(model))
-	filePath := filepath.Join(s.baseDir, fileName)
-
-	// Try to load existing file
-	var existing SavedResult
-	if data, err := os.ReadFile(filePath); err == nil {
-		if err := json.Unmarshal(data, &existing); err != nil {
-			// If unmarshal fails, start fresh
-			existing = SavedResult{}
-		}
+// LoadMaxOutputResult loads the latest max output probe result
+func (s *PartitionedResultStorage) LoadMaxOutputResult(provider, model string) (interface{}, error) {
+	saved, err := s.loadLatest(provider, model, ResultTypeMaxOutput)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update with new result
-	existing.MaxOutput = result
-	existing.EstimatedAt = time.Now()
-	existing.LLMInfoVersion = "2.1.0"
+	if saved.MaxOutput == nil {
+		return nil, fmt.Errorf("no max output result found")
+	}
 
-	// Save to file
-	return s.saveToFile(filePath, existing)
+	return saved.MaxOutput, nil
 }
 
-// LoadContextResult loads a context window probe result
-func (s *JSONResultStorage) LoadContextResult(provider, model string) (interface{}, error) {
-	fileName := fmt.Sprintf("%s-%s.json", sanitizeProviderName(provider), sanitizeModelName(model))
-	filePath := filepath.Join(s.baseDir, fileName)
+// LoadCapabilitiesResult loads the latest capabilities result
+func (s *PartitionedResultStorage) LoadCapabilitiesResult(provider, model string) (interface{}, error) {
+	saved, err := s.loadLatest(provider, model, ResultTypeCapabilities)
+	if err != nil {
+		return nil, err
+	}
+
+	if saved.Capabilities == nil {
+		return nil, fmt.Errorf("no capabilities result found")
+	}
 
-	data, err := os.ReadFile(filePath)
+	return saved.Capabilities, nil
+}
+
+// save writes a single result into its partition and records it in the index
+func (s *PartitionedResultStorage) save(provider, model, resultType string, result interface{}) error {
+	now := time.Now()
+	saved := SavedResult{
+		Provider:       provider,
+		Model:          model,
+		EstimatedAt:    now,
+		LLMInfoVersion: "2.1.0",
+	}
+	switch resultType {
+	case ResultTypeContextWindow:
+		saved.ContextWindow = result
+	case ResultTypeMaxOutput:
+		saved.MaxOutput = result
+	case ResultTypeCapabilities:
+		saved.Capabilities = result
+	case ResultTypeMultiTurn:
+		saved.MultiTurn = result
+	}
+
+	relPath := partitionPath(provider, model, resultType, now, s.compress)
+	filePath := filepath.Join(s.baseDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create partition directory: %w", err)
+	}
+
+	size, err := writeResultFile(filePath, saved, s.compress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read result file: %w", err)
+		return err
 	}
 
-	var result SavedResult
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal result: %w", err)
+	index, err := LoadIndex(s.baseDir)
+	if err != nil {
+		// A broken index is rebuilt from the files on disk
+		if index, err = RebuildIndex(s.baseDir); err != nil {
+			return err
+		}
+	} else {
+		index.Add(IndexEntry{
+			Provider:   provider,
+			Model:      model,
+			Type:       resultType,
+			Path:       relPath,
+			SavedAt:    now,
+			Size:       size,
+			Compressed: s.compress,
+		})
 	}
 
-	if result.ContextWindow == nil {
-		return nil, fmt.Errorf("no context window result found")
+	if err := index.Save(s.baseDir); err != nil {
+		return err
+	}
+	if err := s.upload(relPath); err != nil {
+		return fmt.Errorf("saved locally but not shared: %w", err)
+	}
+	return nil
+}
+
+// loadLatest reads the newest result of the given type, falling back to the
+// flat <provider>-<model>.json files written by earlier versions
+func (s *PartitionedResultStorage) loadLatest(provider, model, resultType string) (*SavedResult, error) {
+	if s.remote != nil {
+		// Results shared by others are fetched first; when the remote store is
+		// unreachable the local cache is used as-is
+		s.Pull(path.Join(sanitizeProviderName(provider), sanitizeModelName(model)))
+	}
+
+	index, err := LoadIndex(s.baseDir)
+	if err == nil {
+		entries := index.Find(IndexQuery{Provider: provider, Model: model, Type: resultType, Limit: 1})
+		if len(entries) > 0 {
+			return ReadIndexedResult(s.baseDir, entries[0])
+		}
 	}
 
-	return result.ContextWindow, nil
+	return ReadSavedResult(filepath.Join(s.baseDir, legacyFileName(provider, model)))
 }
 
-// LoadMaxOutputResult loads a max output probe result
-func (s *JSONResultStorage) LoadMaxOutputResult(provider, model string) (interface{}, error) {
-	fileName := fmt.Sprintf("%s-%s.json", sanitizeProviderName(provider), sanitizeModelName(model))
-	filePath := filepath.Join(s.baseDir, fileName)
+// partitionPath returns the path of a result file relative to the base directory
+func partitionPath(provider, model, resultType string, t time.Time, compress bool) string {
+	t = t.UTC()
+	fileName := fmt.Sprintf("%s-%s.json", t.Format("150405.000"), resultType)
+	if compress {
+		fileName += ".gz"
+	}
+	return filepath.Join(sanitizeProviderName(provider), sanitizeModelName(model), t.Format("2006-01-02"), fileName)
+}
+
+// legacyFileName returns the flat file name used before results were partitioned
+func legacyFileName(provider, model string) string {
+	return fmt.Sprintf("%s-%s.json", sanitizeProviderName(provider), sanitizeModelName(model))
+}
 
-	data, err := os.ReadFile(filePath)
+// ReadSavedResult reads a result file, decompressing it if it ends in .gz
+func ReadSavedResult(filePath string) (*SavedResult, error) {
+	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read result file: %w", err)
 	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(filePath, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress result file: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
 
 	var result SavedResult
-	if err := json.Unmarshal(data, &result); err != nil {
+	if err := json.NewDecoder(reader).Decode(&result); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal result: %w", err)
 	}
 
-	if result.MaxOutput == nil {
-		return nil, fmt.Errorf("no max output result found")
-	}
+	return &result, nil
+}
 
-	return result.MaxOutput, nil
+// ReadIndexedResult reads the result file referenced by an index entry
+func ReadIndexedResult(baseDir string, entry IndexEntry) (*SavedResult, error) {
+	return ReadSavedResult(filepath.Join(baseDir, entry.Path))
 }
 
-// saveToFile helper function to save data as JSON
-func (s *JSONResultStorage) saveToFile(filePath string, data SavedResult) error {
+// writeResultFile writes a result as (optionally compressed) JSON and returns its size
+func writeResultFile(filePath string, data SavedResult, compress bool) (int64, error) {
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal result: %w", err)
+		return 0, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	// Saved results may be shared, so strip API keys echoed in errors or metadata
+	jsonData = redact.Bytes(jsonData)
+
+	if compress {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(jsonData); err != nil {
+			return 0, fmt.Errorf("failed to compress result: %w", err)
+		}
+		if err := gz.Close(); err != nil {
+			return 0, fmt.Errorf("failed to compress result: %w", err)
+		}
+		jsonData = buf.Bytes()
+	}
+
+	if err := writeFileAtomic(filePath, jsonData); err != nil {
+		return 0, err
 	}
+	return int64(len(jsonData)), nil
+}
 
+// writeFileAtomic writes data to a temporary file and renames it into place
+func writeFileAtomic(filePath string, data []byte) error {
 	// Write to temporary file first
 	tempFile := filePath + ".tmp"
-	if err := os.WriteFile(tempFile, jsonData, 0644); err != nil {
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write temporary file: %w", err)
 	}
 
@@ -162,6 +386,18 @@ func (s *JSONResultStorage) saveToFile(filePath string, data SavedResult) error
 	return nil
 }
 
+// ExpandPath expands a leading ~ to the user's home directory
+func ExpandPath(path string) (string, error) {
+	if len(path) > 0 && path[0] == '~' {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	return path, nil
+}
+
 // sanitizeProviderName sanitizes provider name for file system
 func sanitizeProviderName(provider string) string {
 	// Convert to lowercase and replace spaces
@@ -224,9 +460,5 @@ func replaceAll(s, old, new string) string {
 
 // GetDefaultResultDir returns the default directory for storing results
 func GetDefaultResultDir() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		home = "/tmp"
-	}
-	return filepath.Join(home, ".config", "llm-info", "estimates")
+	return paths.StateFile("estimates")
 }
\ No newline at end of file
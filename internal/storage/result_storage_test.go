@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+func TestWriteResultFile_RedactsSecrets(t *testing.T) {
+	redact.Reset()
+	defer redact.Reset()
+	redact.Register("gw-secret-123")
+
+	for _, compress := range []bool{false, true} {
+		dir := t.TempDir()
+		filePath := filepath.Join(dir, "result.json")
+		if compress {
+			filePath += ".gz"
+		}
+		data := SavedResult{
+			Provider: "openai",
+			Model:    "gpt-4",
+			ContextWindow: map[string]interface{}{
+				"value": 8192,
+				"error": "Incorrect API key provided: gw-secret-123",
+			},
+		}
+
+		if _, err := writeResultFile(filePath, data, compress); err != nil {
+			t.Fatalf("writeResultFile(compress=%v) error = %v", compress, err)
+		}
+
+		raw, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("failed to read result file: %v", err)
+		}
+		if compress {
+			gz, err := gzip.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				t.Fatalf("failed to open gzip result: %v", err)
+			}
+			raw, _ = io.ReadAll(gz)
+		}
+
+		if strings.Contains(string(raw), "gw-secret-123") {
+			t.Errorf("saved result (compress=%v) contains the API key:\n%s", compress, raw)
+		}
+
+		// The redacted file must still be readable
+		result, err := ReadSavedResult(filePath)
+		if err != nil {
+			t.Fatalf("ReadSavedResult(compress=%v) error = %v", compress, err)
+		}
+		if value, ok := result.Value(ResultTypeContextWindow); !ok || value != 8192 {
+			t.Errorf("Value() = %d, %v; want 8192, true", value, ok)
+		}
+	}
+}
+
+func TestPartitionedResultStorage_Capabilities(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewResultStorageWithOptions(dir, Options{})
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+
+	if _, err := s.LoadCapabilitiesResult("openai", "gpt-4o"); err == nil {
+		t.Error("LoadCapabilitiesResult() should fail before anything is saved")
+	}
+
+	result := map[string]interface{}{"model": "gpt-4o", "parameters": []interface{}{}}
+	if err := s.SaveCapabilitiesResult("openai", "gpt-4o", result); err != nil {
+		t.Fatalf("SaveCapabilitiesResult() error = %v", err)
+	}
+	// A max output result for the same model must not shadow the capabilities
+	if err := s.SaveMaxOutputResult("openai", "gpt-4o", map[string]interface{}{"value": 4096}); err != nil {
+		t.Fatalf("SaveMaxOutputResult() error = %v", err)
+	}
+
+	loaded, err := s.LoadCapabilitiesResult("openai", "gpt-4o")
+	if err != nil {
+		t.Fatalf("LoadCapabilitiesResult() error = %v", err)
+	}
+	if fields, ok := loaded.(map[string]interface{}); !ok || fields["model"] != "gpt-4o" {
+		t.Errorf("LoadCapabilitiesResult() = %v", loaded)
+	}
+
+	// Rebuilding the index keeps the capabilities type
+	index, err := RebuildIndex(dir)
+	if err != nil {
+		t.Fatalf("RebuildIndex() error = %v", err)
+	}
+	if entries := index.Find(IndexQuery{Type: ResultTypeCapabilities}); len(entries) != 1 {
+		t.Errorf("RebuildIndex() found %d capabilities entries, want 1", len(entries))
+	}
+}
+
+func TestPartitionedResultStorage_MultiTurn(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewResultStorageWithOptions(dir, Options{})
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+
+	if err := s.SaveContextResult("openai", "gpt-4o", map[string]interface{}{"success": true, "value": 128000}); err != nil {
+		t.Fatalf("SaveContextResult() error = %v", err)
+	}
+	if err := s.SaveMultiTurnResult("openai", "gpt-4o", map[string]interface{}{"success": true, "value": 96000}); err != nil {
+		t.Fatalf("SaveMultiTurnResult() error = %v", err)
+	}
+
+	// A multi-turn result must not shadow the single-shot context window
+	loaded, err := s.LoadContextResult("openai", "gpt-4o")
+	if err != nil {
+		t.Fatalf("LoadContextResult() error = %v", err)
+	}
+	if fields, _ := loaded.(map[string]interface{}); fields["value"] != float64(128000) {
+		t.Errorf("LoadContextResult() = %v, want the single-shot result", loaded)
+	}
+
+	index, err := RebuildIndex(dir)
+	if err != nil {
+		t.Fatalf("RebuildIndex() error = %v", err)
+	}
+	entries := index.Find(IndexQuery{Type: ResultTypeMultiTurn})
+	if len(entries) != 1 {
+		t.Fatalf("RebuildIndex() found %d multi-turn entries, want 1", len(entries))
+	}
+	saved, err := ReadIndexedResult(dir, entries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, ok := saved.Value(ResultTypeMultiTurn); !ok || value != 96000 {
+		t.Errorf("Value() = %d, %v, want 96000", value, ok)
+	}
+}
+
+func TestSavedResult_TrialLatencies(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewResultStorageWithOptions(dir, Options{})
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+	result := map[string]interface{}{
+		"success": true,
+		"value":   128000,
+		"trials": []map[string]interface{}{
+			{"token_count": 1000, "success": true, "latency": 800 * time.Millisecond},
+			{"token_count": 2000, "success": true, "latency": 1200 * time.Millisecond},
+			{"token_count": 4000, "success": false}, // saved before latencies were recorded
+		},
+	}
+	if err := s.SaveContextResult("openai", "gpt-4o", result); err != nil {
+		t.Fatalf("SaveContextResult() error = %v", err)
+	}
+
+	index, err := RebuildIndex(dir)
+	if err != nil {
+		t.Fatalf("RebuildIndex() error = %v", err)
+	}
+	entries := index.Find(IndexQuery{Type: ResultTypeContextWindow})
+	if len(entries) != 1 {
+		t.Fatalf("RebuildIndex() found %d entries, want 1", len(entries))
+	}
+	saved, err := ReadIndexedResult(dir, entries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	latencies := saved.TrialLatencies(ResultTypeContextWindow)
+	if len(latencies) != 2 || latencies[0] != 800*time.Millisecond || latencies[1] != 1200*time.Millisecond {
+		t.Errorf("TrialLatencies() = %v, want [800ms 1.2s]", latencies)
+	}
+	if latencies := saved.TrialLatencies(ResultTypeMaxOutput); len(latencies) != 0 {
+		t.Errorf("TrialLatencies(max_output) = %v, want none", latencies)
+	}
+}
@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// prunableExtensions lists the file types written by llm-info that may be pruned
+var prunableExtensions = map[string]bool{
+	".json":  true,
+	".jsonl": true,
+	".log":   true,
+	".gz":    true,
+}
+
+// RetentionPolicy limits how many result and log files are kept in a directory.
+// A zero value for any limit disables that limit.
+type RetentionPolicy struct {
+	MaxFiles     int
+	MaxAge       time.Duration
+	MaxTotalSize int64
+}
+
+// IsZero reports whether the policy has no limits configured
+func (p RetentionPolicy) IsZero() bool {
+	return p.MaxFiles <= 0 && p.MaxAge <= 0 && p.MaxTotalSize <= 0
+}
+
+// PruneResult describes the files removed by Prune
+type PruneResult struct {
+	Dir        string
+	Removed    []string
+	FreedBytes int64
+	Remaining  int
+}
+
+// prunableFile is a candidate for pruning
+type prunableFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// Prune removes files from dir until the retention policy is satisfied.
+// Files older than MaxAge are removed first, then the oldest files are removed
+// until both MaxFiles and MaxTotalSize hold. When dryRun is true, nothing is
+// deleted but the result reports what would have been removed.
+func Prune(dir string, policy RetentionPolicy, dryRun bool) (*PruneResult, error) {
+	result := &PruneResult{Dir: dir}
+
+	files, err := listPrunableFiles(dir)
+	if err != nil {
+		return result, err
+	}
+	if policy.IsZero() {
+		result.Remaining = len(files)
+		return result, nil
+	}
+
+	// Oldest first so that the newest files are kept
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	var totalSize int64
+	for _, f := range files {
+		totalSize += f.size
+	}
+
+	cutoff := time.Now().Add(-policy.MaxAge)
+	remaining := len(files)
+	for _, f := range files {
+		expired := policy.MaxAge > 0 && f.modTime.Before(cutoff)
+		tooMany := policy.MaxFiles > 0 && remaining > policy.MaxFiles
+		tooLarge := policy.MaxTotalSize > 0 && totalSize > policy.MaxTotalSize
+		if !expired && !tooMany && !tooLarge {
+			// Files are sorted by age, so the remaining ones are all within limits
+			break
+		}
+
+		if !dryRun {
+			if err := os.Remove(f.path); err != nil {
+				result.Remaining = remaining
+				return result, fmt.Errorf("failed to remove %s: %w", f.path, err)
+			}
+		}
+		result.Removed = append(result.Removed, f.path)
+		result.FreedBytes += f.size
+		totalSize -= f.size
+		remaining--
+	}
+
+	result.Remaining = remaining
+
+	if !dryRun && len(result.Removed) > 0 {
+		if err := cleanupAfterPrune(dir, result.Removed); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// listPrunableFiles returns the result and log files under dir, including
+// files in partition subdirectories. The result index is never pruned.
+func listPrunableFiles(dir string) ([]prunableFile, error) {
+	var files []prunableFile
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || d.Name() == IndexFileName || !prunableExtensions[filepath.Ext(d.Name())] {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, prunableFile{
+			path:    path,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	return files, nil
+}
+
+// cleanupAfterPrune drops removed files from the result index and deletes
+// partition directories that became empty
+func cleanupAfterPrune(dir string, removed []string) error {
+	if _, err := os.Stat(filepath.Join(dir, IndexFileName)); err == nil {
+		index, err := LoadIndex(dir)
+		if err != nil {
+			return err
+		}
+		paths := make(map[string]bool, len(removed))
+		for _, path := range removed {
+			if rel, err := filepath.Rel(dir, path); err == nil {
+				paths[rel] = true
+			}
+		}
+		index.removePaths(paths)
+		if err := index.Save(dir); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range removed {
+		// Remove now-empty parents up to (but not including) dir
+		for parent := filepath.Dir(path); parent != dir && strings.HasPrefix(parent, dir); parent = filepath.Dir(parent) {
+			if os.Remove(parent) != nil {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// sizeUnits maps size suffixes to their multiplier in bytes
+var sizeUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"G", 1 << 30},
+	{"M", 1 << 20},
+	{"K", 1 << 10},
+	{"B", 1},
+}
+
+// ParseSize parses a human readable size such as "500MB" or "1G" into bytes.
+// An empty string returns 0.
+func ParseSize(s string) (int64, error) {
+	original := s
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			multiplier = unit.multiplier
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size: %q", original)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+// FormatSize formats a byte count for display
+func FormatSize(bytes int64) string {
+	switch {
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.1fGB", float64(bytes)/(1<<30))
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(bytes)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", bytes)
+	}
+}
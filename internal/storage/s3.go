@@ -0,0 +1,378 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+// S3Config configures an S3-compatible object store
+type S3Config struct {
+	Endpoint        string // e.g. https://s3.eu-west-1.amazonaws.com or https://storage.googleapis.com
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	PathStyle       bool // address the bucket as /<bucket>/<key> instead of <bucket>.<host>
+	Timeout         time.Duration
+}
+
+// S3Store implements RemoteStore on top of the S3 REST API with Signature
+// Version 4. It covers the few calls result sharing needs, so no SDK is
+// required; GCS is reached through its S3-compatible XML API with HMAC keys.
+type S3Store struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Store creates a store for the given bucket
+func NewS3Store(cfg S3Config) *S3Store {
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	// Keys must never end up in error messages or saved results
+	redact.Register(cfg.SecretAccessKey)
+	redact.Register(cfg.SessionToken)
+
+	return &S3Store{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		now:    time.Now,
+	}
+}
+
+// String describes the store for messages
+func (s *S3Store) String() string {
+	return fmt.Sprintf("s3://%s", s.cfg.Bucket)
+}
+
+// Put uploads an object
+func (s *S3Store) Put(key string, data []byte) error {
+	resp, err := s.do(http.MethodPut, key, nil, nil, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkS3Response(resp, "PUT "+key)
+}
+
+// Get downloads an object
+func (s *S3Store) Get(key string) ([]byte, error) {
+	data, _, err := s.get(key)
+	return data, err
+}
+
+// get downloads an object along with its ETag
+func (s *S3Store) get(key string) ([]byte, string, error) {
+	resp, err := s.do(http.MethodGet, key, nil, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if err := checkS3Response(resp, "GET "+key); err != nil {
+		return nil, "", err
+	}
+	data, err := io.ReadAll(resp.Body)
+	return data, resp.Header.Get("ETag"), err
+}
+
+// PutIfAbsent uploads an object only if no object exists under key and
+// reports whether it was created. It relies on conditional writes
+// (If-None-Match: *), which AWS S3, GCS and recent MinIO releases support.
+func (s *S3Store) PutIfAbsent(key string, data []byte) (bool, error) {
+	header := http.Header{}
+	header.Set("If-None-Match", "*")
+	resp, err := s.do(http.MethodPut, key, nil, header, data)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusPreconditionFailed || resp.StatusCode == http.StatusConflict {
+		return false, nil
+	}
+	if err := checkS3Response(resp, "PUT "+key); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PutIfMatch replaces an object only if its content is still old and reports
+// whether it was replaced. The PUT carries If-Match with the ETag read
+// alongside old, so a concurrent writer in between makes it fail.
+func (s *S3Store) PutIfMatch(key string, old, data []byte) (bool, error) {
+	current, etag, err := s.get(key)
+	if errors.Is(err, ErrObjectNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Equal(current, old) || etag == "" {
+		return false, nil
+	}
+
+	header := http.Header{}
+	header.Set("If-Match", etag)
+	resp, err := s.do(http.MethodPut, key, nil, header, data)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusPreconditionFailed || resp.StatusCode == http.StatusConflict || resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err := checkS3Response(resp, "PUT "+key); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete removes an object. Deleting a missing object is not an error.
+func (s *S3Store) Delete(key string) error {
+	resp, err := s.do(http.MethodDelete, key, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkS3Response(resp, "DELETE "+key); err != nil && !errors.Is(err, ErrObjectNotFound) {
+		return err
+	}
+	return nil
+}
+
+// listBucketResult is the ListObjectsV2 response
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns the keys of all objects under prefix
+func (s *S3Store) List(prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result listBucketResult
+		err = checkS3Response(resp, "LIST "+prefix)
+		if err == nil {
+			err = xml.NewDecoder(resp.Body).Decode(&result)
+		}
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+		}
+
+		for _, object := range result.Contents {
+			keys = append(keys, object.Key)
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do sends a signed request for key (or the bucket itself when key is empty)
+func (s *S3Store) do(method, key string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	endpoint, err := url.Parse(s.cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", s.cfg.Endpoint, err)
+	}
+
+	path := "/" + key
+	if s.cfg.PathStyle {
+		path = "/" + s.cfg.Bucket + path
+	} else {
+		endpoint.Host = s.cfg.Bucket + "." + endpoint.Host
+	}
+	endpoint.Path = path
+	endpoint.RawPath = uriEncode(path, false)
+	if query != nil {
+		endpoint.RawQuery = canonicalQuery(query)
+	}
+
+	req, err := http.NewRequest(method, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+	signV4(req, body, s.cfg.AccessKeyID, s.cfg.SecretAccessKey, s.cfg.Region, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", s.cfg.Endpoint, err)
+	}
+	return resp, nil
+}
+
+// ErrObjectNotFound is returned when a requested object does not exist
+var ErrObjectNotFound = errors.New("object not found")
+
+// checkS3Response turns an error response into an error
+func checkS3Response(resp *http.Response, operation string) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound && !strings.HasPrefix(operation, "LIST") {
+		return fmt.Errorf("%s: %w", operation, ErrObjectNotFound)
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var s3Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(body, &s3Error) == nil && s3Error.Code != "" {
+		return fmt.Errorf("%s failed with status %d: %s: %s", operation, resp.StatusCode, s3Error.Code, redact.String(s3Error.Message))
+	}
+	return fmt.Errorf("%s failed with status %d", operation, resp.StatusCode)
+}
+
+// signV4 adds AWS Signature Version 4 headers to req. The host, range,
+// content-type and all x-amz-* headers are signed.
+func signV4(req *http.Request, body []byte, accessKeyID, secretAccessKey, region string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "range" || lower == "content-type" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes the query sorted by key as SigV4 requires
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything but unreserved characters. Slashes
+// are kept unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			sb.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"sort"
+	"time"
+)
+
+// TrendPoint is one saved result in the history of a measured value
+type TrendPoint struct {
+	SavedAt  time.Time `json:"saved_at"`
+	Provider string    `json:"provider"`
+	Value    int       `json:"value,omitempty"`
+	Success  bool      `json:"success"`
+	// Change is set on the first successful result whose value differs from
+	// the previous successful one
+	Change   bool   `json:"change,omitempty"`
+	Previous int    `json:"previous,omitempty"`
+	Path     string `json:"path"`
+}
+
+// LoadTrend reads the results matching the query, oldest first, and marks
+// the points where the measured value changed. Results that cannot be read
+// are skipped.
+func LoadTrend(baseDir string, index *Index, q IndexQuery) []TrendPoint {
+	entries := index.Find(q)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].SavedAt.Before(entries[j].SavedAt)
+	})
+
+	var points []TrendPoint
+	for _, entry := range entries {
+		saved, err := ReadIndexedResult(baseDir, entry)
+		if err != nil {
+			continue
+		}
+		value, ok := saved.Value(q.Type)
+		points = append(points, TrendPoint{
+			SavedAt:  entry.SavedAt,
+			Provider: entry.Provider,
+			Value:    value,
+			Success:  ok,
+			Path:     entry.Path,
+		})
+	}
+	MarkChanges(points)
+	return points
+}
+
+// MarkChanges sets Change and Previous on the successful points whose value
+// differs from the previous successful point. Failed runs are not changes.
+func MarkChanges(points []TrendPoint) {
+	previous, seen := 0, false
+	for i := range points {
+		if !points[i].Success {
+			continue
+		}
+		if seen && points[i].Value != previous {
+			points[i].Change = true
+			points[i].Previous = previous
+		}
+		previous, seen = points[i].Value, true
+	}
+}
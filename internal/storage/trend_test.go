@@ -0,0 +1,65 @@
+package storage
+
+import "testing"
+
+func TestLoadTrend(t *testing.T) {
+	src := t.TempDir()
+	path := writeImportFile(t, src, "history.json", `[
+		{"provider": "openai", "model": "gpt-4o", "estimated_at": "2026-03-01T12:00:00Z",
+		 "context_window": {"value": 128000, "success": true}},
+		{"provider": "openai", "model": "gpt-4o", "estimated_at": "2026-04-01T12:00:00Z",
+		 "context_window": {"success": false}},
+		{"provider": "openai", "model": "gpt-4o", "estimated_at": "2026-05-01T12:00:00Z",
+		 "context_window": {"value": 128000, "success": true}, "max_output": {"value": 16384, "success": true}},
+		{"provider": "openai", "model": "gpt-4o", "estimated_at": "2026-06-01T12:00:00Z",
+		 "context_window": {"value": 120000, "success": true}},
+		{"provider": "openai", "model": "gpt-4o-mini", "estimated_at": "2026-06-01T12:00:00Z",
+		 "context_window": {"value": 64000, "success": true}}
+	]`)
+
+	s, err := NewResultStorageWithOptions(t.TempDir(), Options{})
+	if err != nil {
+		t.Fatalf("NewResultStorageWithOptions() error = %v", err)
+	}
+	candidates, err := ReadImportSource(path)
+	if err != nil {
+		t.Fatalf("ReadImportSource() error = %v", err)
+	}
+	if _, err := s.Import(candidates, false); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	index, err := LoadIndex(s.BaseDir())
+	if err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+
+	points := LoadTrend(s.BaseDir(), index, IndexQuery{Model: "gpt-4o", Type: ResultTypeContextWindow})
+	want := []struct {
+		value           int
+		success, change bool
+		previous        int
+	}{
+		{128000, true, false, 0},
+		{0, false, false, 0},
+		{128000, true, false, 0}, // A failed run in between is not a change
+		{120000, true, true, 128000},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("LoadTrend() = %+v, want %d points", points, len(want))
+	}
+	for i, w := range want {
+		p := points[i]
+		if p.Value != w.value || p.Success != w.success || p.Change != w.change || p.Previous != w.previous {
+			t.Errorf("[%d] = %+v, want %+v", i, p, w)
+		}
+		if i > 0 && !p.SavedAt.After(points[i-1].SavedAt) {
+			t.Errorf("points are not oldest first: %v", points)
+		}
+	}
+
+	// Limit counts from the newest result
+	recent := LoadTrend(s.BaseDir(), index, IndexQuery{Model: "gpt-4o", Type: ResultTypeContextWindow, Limit: 2})
+	if len(recent) != 2 || recent[1].Value != 120000 || !recent[1].Change {
+		t.Errorf("LoadTrend(limit 2) = %+v", recent)
+	}
+}
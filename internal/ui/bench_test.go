@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/parallel"
+)
+
+// benchModels はベンチマーク用に大規模なゲートウェイのモデル一覧を作成する
+func benchModels(n int) []model.Model {
+	providers := []string{"openai", "anthropic", "google", "azure", "bedrock"}
+	models := make([]model.Model, n)
+	for i := range models {
+		provider := providers[i%len(providers)]
+		models[i] = model.Model{
+			Name:      fmt.Sprintf("%s/model-%d", provider, i/len(providers)),
+			MaxTokens: 4096 * (i%32 + 1),
+			Mode:      "chat",
+			InputCost: float64(i%100) / 100000,
+			Metadata: map[string]interface{}{
+				"litellm_provider": provider,
+				"supports_vision":  i%2 == 0,
+			},
+		}
+	}
+	return models
+}
+
+var benchCriteria = &FilterCriteria{
+	NamePattern:    "^(openai|anthropic)/",
+	ExcludePattern: "-9$",
+	MinTokens:      8192,
+	MetaFilters:    []MetaFilter{{Key: "supports_vision", Operator: ":", Value: "true"}},
+}
+
+func TestFilter_ParallelMatchesSerial(t *testing.T) {
+	models := benchModels(5000)
+
+	serial := filterWithWorkers(models, benchCriteria, 1)
+	concurrent := filterWithWorkers(models, benchCriteria, 8)
+
+	if len(serial) == 0 {
+		t.Fatal("expected some models to match")
+	}
+	if !reflect.DeepEqual(serial, concurrent) {
+		t.Errorf("parallel filter returned %d models in a different order than serial (%d)", len(concurrent), len(serial))
+	}
+}
+
+func BenchmarkFilter(b *testing.B) {
+	models := benchModels(5000)
+
+	// 変更前の実装と同様に、モデルごとに正規表現をコンパイルする場合
+	b.Run("per-model-regexp", func(b *testing.B) {
+		rest := newCriteriaMatcher(&FilterCriteria{MinTokens: benchCriteria.MinTokens, MetaFilters: benchCriteria.MetaFilters})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var filtered []model.Model
+			for _, m := range models {
+				if matched, _ := regexp.MatchString(benchCriteria.NamePattern, m.Name); !matched {
+					continue
+				}
+				if matched, _ := regexp.MatchString(benchCriteria.ExcludePattern, m.Name); matched {
+					continue
+				}
+				if rest.match(m) {
+					filtered = append(filtered, m)
+				}
+			}
+		}
+	})
+
+	for _, bc := range []struct {
+		name    string
+		workers int
+	}{
+		{"serial", 1},
+		{"parallel", parallel.Workers()},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				filterWithWorkers(models, benchCriteria, bc.workers)
+			}
+		})
+	}
+}
+
+func BenchmarkTableRender(b *testing.B) {
+	models := benchModels(5000)
+	renderer := NewTableRenderer()
+
+	// 表示自体のコストを除くため標準出力を捨てる
+	stdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	os.Stdout = devNull
+	defer func() {
+		os.Stdout = stdout
+		devNull.Close()
+	}()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := renderer.Render(models, &RenderOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
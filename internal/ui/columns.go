@@ -59,10 +59,167 @@ func NewColumnManager() *ColumnManager {
 				Format:   "%.6f",
 				Priority: 4,
 			},
+			{
+				Name:     "variants",
+				Header:   "VARIANTS",
+				Visible:  false,
+				Width:    30,
+				Format:   "%s",
+				Priority: 5,
+			},
 		},
 	}
 }
 
+// liteLLMColumnDefs はLiteLLMの/healthと/model_group/infoから付加した値を表示するカラム
+// 参照するメタデータのキーとヘッダーを持つ
+var liteLLMColumnDefs = map[string]struct{ key, header string }{
+	"health": {model.MetaDeploymentHealth, "HEALTH"},
+	"group":  {model.MetaGroupProviders, "GROUP PROVIDERS"},
+}
+
+// measuredColumnDefs は保存済みの探索結果から付加した値を表示するカラム
+var measuredColumnDefs = map[string]struct{ key, header string }{
+	"measured_context":    {model.MetaMeasuredContext, "MEASURED CONTEXT"},
+	"measured_max_output": {model.MetaMeasuredMaxOutput, "MEASURED MAX OUTPUT"},
+	"measured_at":         {model.MetaMeasuredAt, "MEASURED AT"},
+	"latency_p50":         {model.MetaLatencyP50, "LATENCY P50"},
+}
+
+// pricingColumnDefs はゲートウェイが返す料金のうち、INPUT COST以外を表示するカラム
+// キャッシュの料金はゲートウェイによってキーが異なるため、値はmodel.CachedInputCostで取得する
+var pricingColumnDefs = map[string]struct{ key, header string }{
+	"cached_input_cost": {"cache_read_input_token_cost", "CACHED INPUT COST"},
+}
+
+// userColumnDefs はユーザーが付けたピン留め（llm-info pin）とメモ・タグ（llm-info note）を表示するカラム
+var userColumnDefs = map[string]struct{ key, header string }{
+	"pinned": {model.MetaPinned, "PINNED"},
+	"notes":  {model.MetaNotes, "NOTES"},
+	"tags":   {model.MetaTags, "TAGS"},
+}
+
+// derivedColumnDef はLiteLLM固有のカラム、料金のカラム、探索結果のカラム、ピン留めとメモ・タグのカラムの定義を返す
+func derivedColumnDef(columnName string) (struct{ key, header string }, bool) {
+	if column, ok := liteLLMColumnDefs[columnName]; ok {
+		return column, true
+	}
+	if column, ok := pricingColumnDefs[columnName]; ok {
+		return column, true
+	}
+	if column, ok := userColumnDefs[columnName]; ok {
+		return column, true
+	}
+	column, ok := measuredColumnDefs[columnName]
+	return column, ok
+}
+
+// AllColumns は--columnsで指定するとすべてのカラムを表示する値
+const AllColumns = "all"
+
+// ColumnInfo は--columnsで指定できるカラムの説明
+type ColumnInfo struct {
+	Name        string `json:"name"`
+	Header      string `json:"header"`
+	Description string `json:"description"`
+	Source      string `json:"source"` // 値を取得する元
+}
+
+// AvailableColumns は--columnsで指定できる組み込みのカラムを返す
+// meta.<key>のカラムはゲートウェイが返すメタデータによって変わるため含まない
+func AvailableColumns() []ColumnInfo {
+	return []ColumnInfo{
+		{"name", "MODEL NAME", "Model ID", "/models"},
+		{"max_tokens", "MAX TOKENS", "Advertised maximum tokens", "/models, /model/info"},
+		{"mode", "MODE", "Model mode (chat, completion, embedding, ...)", "/models, /model/info"},
+		{"input_cost", "INPUT COST", "Input cost per token", "/model/info"},
+		{"cached_input_cost", "CACHED INPUT COST", "Input cost per token on a prompt cache hit", "/model/info"},
+		{"variants", "VARIANTS", "Original IDs merged into the row", "--dedupe"},
+		{"health", "HEALTH", "Healthy deployments of the model", "/health (type: litellm)"},
+		{"group", "GROUP PROVIDERS", "Providers of the model group", "/model_group/info (type: litellm)"},
+		{"measured_context", "MEASURED CONTEXT", "Probed context window", "saved probe results"},
+		{"measured_max_output", "MEASURED MAX OUTPUT", "Probed maximum output tokens", "saved probe results"},
+		{"measured_at", "MEASURED AT", "Time of the latest saved probe result", "saved probe results"},
+		{"latency_p50", "LATENCY P50", "Median request latency of the latest probe trials", "saved probe results"},
+		{"pinned", "PINNED", "Whether the model is pinned", "config file (llm-info pin)"},
+		{"notes", "NOTES", "Notes attached to the model", "notes file (llm-info note)"},
+		{"tags", "TAGS", "Tags attached to the model", "notes file (llm-info note)"},
+		{"meta.<key>", "<KEY>", "Any metadata field returned by the gateway", "gateway metadata"},
+	}
+}
+
+// MetaColumns はモデルのメタデータから指定できるmeta.<key>のカラム名を返す
+// ネストした値は"meta.model_info.max_input_tokens"のようにドット区切りで展開し、
+// health、group、measured_*、pinned、notes、tagsのカラムで表示する値は含まない
+func MetaColumns(models []model.Model) []string {
+	derived := make(map[string]bool)
+	for _, defs := range []map[string]struct{ key, header string }{liteLLMColumnDefs, measuredColumnDefs, userColumnDefs} {
+		for _, column := range defs {
+			derived[column.key] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, m := range models {
+		collectMetaKeys(seen, "", m.Metadata)
+	}
+	var names []string
+	for key := range seen {
+		if !derived[key] {
+			names = append(names, "meta."+key)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// collectMetaKeys はネストしたメタデータのキーをドット区切りで集める
+func collectMetaKeys(seen map[string]bool, prefix string, meta map[string]interface{}) {
+	for key, value := range meta {
+		if nested, ok := value.(map[string]interface{}); ok {
+			collectMetaKeys(seen, prefix+key+".", nested)
+			continue
+		}
+		seen[prefix+key] = true
+	}
+}
+
+// ShowAllColumns はモデルに値がある限りすべてのカラムを表示する（--columns all）
+// 組み込みのカラムの後にmeta.<key>のカラムを名前順に並べる
+func (cm *ColumnManager) ShowAllColumns(models []model.Model) {
+	for i := range cm.columns {
+		cm.columns[i].Visible = false
+	}
+
+	names := []string{"name", "max_tokens", "mode", "input_cost"}
+	if hasVariants(models) {
+		names = append(names, "variants")
+	}
+	for _, m := range models {
+		if _, ok := m.CachedInputCost(); ok {
+			names = append(names, "cached_input_cost")
+			break
+		}
+	}
+	for _, name := range []string{"health", "group", "measured_context", "measured_max_output", "measured_at", "latency_p50", "pinned", "tags", "notes"} {
+		column, _ := derivedColumnDef(name)
+		for _, m := range models {
+			if _, ok := m.MetaValue(column.key); ok {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	names = append(names, MetaColumns(models)...)
+
+	for _, name := range names {
+		cm.SetColumnVisibility(name, true)
+	}
+}
+
+// LiteLLMColumns はtype: litellmのゲートウェイで--columns未指定時に表示するカラム
+const LiteLLMColumns = "name,max_tokens,mode,input_cost,health,group"
+
 // GetVisibleColumns は表示可能なカラムを返す
 func (cm *ColumnManager) GetVisibleColumns() []Column {
 	var visible []Column
@@ -81,6 +238,7 @@ func (cm *ColumnManager) GetVisibleColumns() []Column {
 }
 
 // SetColumnVisibility はカラムの表示/非表示を設定する
+// "meta.<key>" 形式のカラム、LiteLLM固有のカラム、料金のカラム、探索結果のカラムは初めて指定されたときに追加される
 func (cm *ColumnManager) SetColumnVisibility(columnName string, visible bool) error {
 	for i, col := range cm.columns {
 		if col.Name == columnName {
@@ -88,9 +246,43 @@ func (cm *ColumnManager) SetColumnVisibility(columnName string, visible bool) er
 			return nil
 		}
 	}
+
+	if column, ok := derivedColumnDef(columnName); ok {
+		cm.columns = append(cm.columns, Column{
+			Name:     columnName,
+			Header:   column.header,
+			Visible:  visible,
+			Width:    20,
+			Format:   "%s",
+			Priority: len(cm.columns) + 1,
+		})
+		return nil
+	}
+
+	if key, ok := metaColumnKey(columnName); ok {
+		cm.columns = append(cm.columns, Column{
+			Name:     columnName,
+			Header:   strings.ToUpper(key),
+			Visible:  visible,
+			Width:    20,
+			Format:   "%s",
+			Priority: len(cm.columns) + 1,
+		})
+		return nil
+	}
+
 	return fmt.Errorf("column not found: %s", columnName)
 }
 
+// metaColumnKey は "meta.<key>" 形式のカラム名からメタデータのキーを取り出す
+func metaColumnKey(columnName string) (string, bool) {
+	key, ok := strings.CutPrefix(columnName, "meta.")
+	if !ok || key == "" {
+		return "", false
+	}
+	return key, true
+}
+
 // ParseColumnsString はカラム文字列を解析してカラム設定を更新する
 func (cm *ColumnManager) ParseColumnsString(columnsStr string) error {
 	if columnsStr == "" {
@@ -129,11 +321,56 @@ func (cm *ColumnManager) GetColumnValue(model model.Model, columnName string) (i
 		return model.Mode, nil
 	case "input_cost":
 		return model.InputCost, nil
+	case "cached_input_cost":
+		if cost, ok := model.CachedInputCost(); ok {
+			return cost, nil
+		}
+		return "-", nil
+	case "variants":
+		return strings.Join(model.Variants, ", "), nil
+	case "health", "group":
+		return metaColumnValue(model, liteLLMColumnDefs[columnName].key), nil
+	case "measured_context", "measured_max_output":
+		return metaColumnValue(model, measuredColumnDefs[columnName].key), nil
+	case "measured_at":
+		if measuredAt, ok := model.MeasuredAt(); ok {
+			return measuredAt.Local().Format("2006-01-02 15:04"), nil
+		}
+		return "-", nil
+	case "latency_p50":
+		if latency, ok := model.LatencyP50(); ok {
+			return latency.String(), nil
+		}
+		return "-", nil
+	case "pinned":
+		if model.IsPinned() {
+			return "yes", nil
+		}
+		return "-", nil
+	case "notes":
+		if notes := model.Notes(); len(notes) > 0 {
+			return strings.Join(notes, "; "), nil
+		}
+		return "-", nil
+	case "tags":
+		return metaColumnValue(model, userColumnDefs[columnName].key), nil
 	default:
+		if key, ok := metaColumnKey(columnName); ok {
+			return metaColumnValue(model, key), nil
+		}
 		return nil, fmt.Errorf("unknown column: %s", columnName)
 	}
 }
 
+// metaColumnValue はメタデータの値を表示用の文字列で返す
+func metaColumnValue(m model.Model, key string) string {
+	value, ok := m.MetaValue(key)
+	if !ok {
+		return "-"
+	}
+	return model.FormatMetaValue(value)
+}
+
 // GetColumnNames は利用可能なカラム名のリストを返す
 func (cm *ColumnManager) GetColumnNames() []string {
 	var names []string
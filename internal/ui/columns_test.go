@@ -1,7 +1,9 @@
 package ui
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/armaniacs/llm-info/internal/model"
 )
@@ -12,14 +14,14 @@ func TestNewColumnManager(t *testing.T) {
 		t.Fatal("NewColumnManager() returned nil")
 	}
 
-	if len(cm.columns) != 4 {
-		t.Errorf("NewColumnManager() created %d columns, want 4", len(cm.columns))
+	if len(cm.columns) != 5 {
+		t.Errorf("NewColumnManager() created %d columns, want 5", len(cm.columns))
 	}
 
-	// デフォルトですべてのカラムが表示されていることを確認
+	// デフォルトでvariants以外のカラムが表示されていることを確認
 	for _, col := range cm.columns {
-		if !col.Visible {
-			t.Errorf("NewColumnManager() column %s is not visible by default", col.Name)
+		if col.Visible != (col.Name != "variants") {
+			t.Errorf("NewColumnManager() column %s visible = %v by default", col.Name, col.Visible)
 		}
 	}
 }
@@ -136,6 +138,18 @@ func TestParseColumnsString(t *testing.T) {
 			wantErr:    true,
 			expected:   nil,
 		},
+		{
+			name:       "meta column",
+			columnsStr: "name,meta.supports_vision",
+			wantErr:    false,
+			expected:   []string{"name", "meta.supports_vision"},
+		},
+		{
+			name:       "meta column without key",
+			columnsStr: "name,meta.",
+			wantErr:    true,
+			expected:   nil,
+		},
 		{
 			name:       "empty parts",
 			columnsStr: "name,,mode",
@@ -179,6 +193,11 @@ func TestGetColumnValue(t *testing.T) {
 		MaxTokens: 8192,
 		Mode:      "chat",
 		InputCost: 0.00003,
+		Variants:  []string{"gpt-4", "openai/gpt-4"},
+		Metadata: map[string]interface{}{
+			"supports_vision":  true,
+			"litellm_provider": "openai",
+		},
 	}
 
 	tests := []struct {
@@ -211,6 +230,24 @@ func TestGetColumnValue(t *testing.T) {
 			expected:    0.00003,
 			expectError: false,
 		},
+		{
+			name:        "variants column",
+			columnName:  "variants",
+			expected:    "gpt-4, openai/gpt-4",
+			expectError: false,
+		},
+		{
+			name:        "meta column",
+			columnName:  "meta.supports_vision",
+			expected:    "true",
+			expectError: false,
+		},
+		{
+			name:        "missing meta column",
+			columnName:  "meta.max_input_tokens",
+			expected:    "-",
+			expectError: false,
+		},
 		{
 			name:        "nonexistent column",
 			columnName:  "nonexistent",
@@ -240,7 +277,7 @@ func TestGetColumnNames(t *testing.T) {
 	cm := NewColumnManager()
 	names := cm.GetColumnNames()
 
-	expected := []string{"name", "max_tokens", "mode", "input_cost"}
+	expected := []string{"name", "max_tokens", "mode", "input_cost", "variants"}
 	if len(names) != len(expected) {
 		t.Errorf("GetColumnNames() returned %d names, want %d", len(names), len(expected))
 	}
@@ -262,15 +299,15 @@ func TestResetToDefaults(t *testing.T) {
 	// リセットする
 	cm.ResetToDefaults()
 
-	// すべてのカラムが表示されていることを確認
+	// variants以外のカラムが表示されていることを確認
 	visible := cm.GetVisibleColumns()
 	if len(visible) != 4 {
 		t.Errorf("ResetToDefaults() visible columns count = %v, want 4", len(visible))
 	}
 
 	for _, col := range cm.columns {
-		if !col.Visible {
-			t.Errorf("ResetToDefaults() column %s is not visible", col.Name)
+		if col.Visible != (col.Name != "variants") {
+			t.Errorf("ResetToDefaults() column %s visible = %v", col.Name, col.Visible)
 		}
 	}
 }
@@ -320,3 +357,173 @@ func TestColumnProperties(t *testing.T) {
 		}
 	}
 }
+
+func TestLiteLLMColumns(t *testing.T) {
+	cm := NewColumnManager()
+	if err := cm.ParseColumnsString(LiteLLMColumns); err != nil {
+		t.Fatalf("ParseColumnsString(%q) error = %v", LiteLLMColumns, err)
+	}
+
+	visible := cm.GetVisibleColumns()
+	if len(visible) != 6 || visible[4].Header != "HEALTH" || visible[5].Header != "GROUP PROVIDERS" {
+		t.Fatalf("GetVisibleColumns() = %+v", visible)
+	}
+
+	m := model.Model{Name: "gpt-4o", Metadata: map[string]interface{}{
+		model.MetaDeploymentHealth: "degraded (1/2)",
+		model.MetaGroupProviders:   []interface{}{"openai", "azure"},
+	}}
+	tests := map[string]string{"health": "degraded (1/2)", "group": "openai, azure"}
+	for column, want := range tests {
+		got, err := cm.GetColumnValue(m, column)
+		if err != nil || got != want {
+			t.Errorf("GetColumnValue(%s) = %v, %v, want %s", column, got, err, want)
+		}
+	}
+
+	got, _ := cm.GetColumnValue(model.Model{Name: "other"}, "health")
+	if got != "-" {
+		t.Errorf("GetColumnValue(health) without status = %v, want -", got)
+	}
+}
+
+func TestMeasuredColumns(t *testing.T) {
+	cm := NewColumnManager()
+	if err := cm.ParseColumnsString("name,measured_context,measured_max_output,measured_at"); err != nil {
+		t.Fatalf("ParseColumnsString() error = %v", err)
+	}
+	visible := cm.GetVisibleColumns()
+	if len(visible) != 4 || visible[1].Header != "MEASURED CONTEXT" || visible[3].Header != "MEASURED AT" {
+		t.Fatalf("GetVisibleColumns() = %+v", visible)
+	}
+
+	measuredAt := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	models := []model.Model{{Name: "gpt-4o"}}
+	model.ApplyMeasurements(models, map[string]model.Measurement{
+		"gpt-4o": {ContextWindow: 128000, MeasuredAt: measuredAt, LatencyP50: 1250 * time.Millisecond},
+	})
+	tests := map[string]string{
+		"measured_context":    "128000",
+		"measured_max_output": "-",
+		"measured_at":         measuredAt.Local().Format("2006-01-02 15:04"),
+		"latency_p50":         "1.25s",
+	}
+	for column, want := range tests {
+		got, err := cm.GetColumnValue(models[0], column)
+		if err != nil || got != want {
+			t.Errorf("GetColumnValue(%s) = %v, %v, want %s", column, got, err, want)
+		}
+	}
+
+	got, _ := cm.GetColumnValue(model.Model{Name: "other"}, "measured_at")
+	if got != "-" {
+		t.Errorf("GetColumnValue(measured_at) without results = %v, want -", got)
+	}
+}
+
+func TestCachedInputCostColumn(t *testing.T) {
+	cm := NewColumnManager()
+	if err := cm.ParseColumnsString("name,input_cost,cached_input_cost"); err != nil {
+		t.Fatalf("ParseColumnsString() error = %v", err)
+	}
+	visible := cm.GetVisibleColumns()
+	if len(visible) != 3 || visible[2].Header != "CACHED INPUT COST" {
+		t.Fatalf("GetVisibleColumns() = %+v", visible)
+	}
+
+	cached := model.Model{Name: "gpt-4o", Metadata: map[string]interface{}{
+		"model_info": map[string]interface{}{"cache_read_input_token_cost": 0.00000125},
+	}}
+	if got, err := cm.GetColumnValue(cached, "cached_input_cost"); err != nil || got != 0.00000125 {
+		t.Errorf("GetColumnValue(cached_input_cost) = %v, %v, want 0.00000125", got, err)
+	}
+	if got, _ := cm.GetColumnValue(model.Model{Name: "other"}, "cached_input_cost"); got != "-" {
+		t.Errorf("GetColumnValue(cached_input_cost) without pricing = %v, want -", got)
+	}
+
+	cm = NewColumnManager()
+	cm.ShowAllColumns([]model.Model{{Name: "other"}, cached})
+	found := false
+	for _, column := range cm.GetVisibleColumns() {
+		found = found || column.Name == "cached_input_cost"
+	}
+	if !found {
+		t.Error("ShowAllColumns() did not show cached_input_cost")
+	}
+}
+
+func TestAnnotationColumns(t *testing.T) {
+	models := []model.Model{{Name: "gpt-4o"}, {Name: "gpt-4o-mini"}}
+	model.ApplyAnnotations(models, map[string]model.Annotation{
+		"gpt-4o": {Notes: []string{"approved for PII workloads", "EU region only"}, Tags: []string{"approved", "eu"}},
+	})
+
+	cm := NewColumnManager()
+	tests := []struct {
+		model  model.Model
+		column string
+		want   string
+	}{
+		{models[0], "notes", "approved for PII workloads; EU region only"},
+		{models[0], "tags", "approved, eu"},
+		{models[1], "notes", "-"},
+		{models[1], "tags", "-"},
+	}
+	for _, tt := range tests {
+		got, err := cm.GetColumnValue(tt.model, tt.column)
+		if err != nil || got != tt.want {
+			t.Errorf("GetColumnValue(%s, %s) = %v, %v, want %s", tt.model.Name, tt.column, got, err, tt.want)
+		}
+	}
+
+	if got := MetaColumns(models); len(got) != 0 {
+		t.Errorf("MetaColumns() = %v, want notes and tags excluded", got)
+	}
+}
+
+func TestShowAllColumns(t *testing.T) {
+	models := []model.Model{
+		{Name: "gpt-4o", Metadata: map[string]interface{}{
+			"owned_by":                 "openai",
+			model.MetaDeploymentHealth: "healthy (1/1)",
+			"model_info":               map[string]interface{}{"supports_vision": true},
+		}},
+		{Name: "claude-3-haiku", Variants: []string{"anthropic/claude-3-haiku"}},
+	}
+
+	if got := MetaColumns(models); len(got) != 2 || got[0] != "meta.model_info.supports_vision" || got[1] != "meta.owned_by" {
+		t.Errorf("MetaColumns() = %v", got)
+	}
+
+	cm := NewColumnManager()
+	cm.ShowAllColumns(models)
+	var names []string
+	for _, column := range cm.GetVisibleColumns() {
+		names = append(names, column.Name)
+	}
+	want := "name,max_tokens,mode,input_cost,variants,health,meta.model_info.supports_vision,meta.owned_by"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("visible columns = %s\nwant %s", got, want)
+	}
+
+	value, err := cm.GetColumnValue(models[0], "meta.model_info.supports_vision")
+	if err != nil || value != "true" {
+		t.Errorf("GetColumnValue() = %v, %v", value, err)
+	}
+}
+
+func TestAvailableColumns(t *testing.T) {
+	// 説明に載せたカラムはすべて--columnsで指定できる
+	cm := NewColumnManager()
+	for _, column := range AvailableColumns() {
+		if column.Name == "meta.<key>" {
+			continue
+		}
+		if err := cm.SetColumnVisibility(column.Name, true); err != nil {
+			t.Errorf("SetColumnVisibility(%s) error = %v", column.Name, err)
+		}
+		if column.Description == "" || column.Source == "" {
+			t.Errorf("%s: missing description or source", column.Name)
+		}
+	}
+}
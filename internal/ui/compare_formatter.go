@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/probe"
+)
+
+// FormatComparison はゲートウェイ間の比較結果を整形する
+func (tf *TableFormatter) FormatComparison(report *probe.ComparisonReport) string {
+	var sb strings.Builder
+
+	// ヘッダー
+	title := fmt.Sprintf("Gateway Comparison: %s", report.Model)
+	sb.WriteString(title + "\n")
+	sb.WriteString(strings.Repeat("=", len(title)) + "\n")
+
+	sb.WriteString(fmt.Sprintf("%-20s %-16s %-16s %-12s %s\n", "Gateway", "Context Window", "Max Output", "Avg Latency", "Status"))
+	sb.WriteString(strings.Repeat("-", 76) + "\n")
+
+	for _, gw := range report.Gateways {
+		status := "✓"
+		if gw.Error != "" {
+			// 日本語などのエラーメッセージを文字の途中で切らないよう、rune単位で切り詰める
+			status = "✗ " + gw.Error
+			if runes := []rune(status); len(runes) > 40 {
+				status = string(runes[:37]) + "..."
+			}
+		}
+
+		sb.WriteString(fmt.Sprintf("%-20s %-16s %-16s %-12s %s\n",
+			gw.Gateway,
+			formatOptionalTokens(gw.ContextWindow),
+			formatOptionalTokens(gw.MaxOutput),
+			formatOptionalLatency(gw.AvgLatencyMs),
+			status,
+		))
+	}
+
+	sb.WriteString("\n")
+
+	if !report.HasDifferences() {
+		sb.WriteString("No differences detected between gateways.\n")
+		return sb.String()
+	}
+
+	sb.WriteString("Differences:\n")
+	for _, diff := range report.Differences {
+		switch diff.Field {
+		case probe.CompareFieldLatency:
+			sb.WriteString(fmt.Sprintf("  - Avg latency: fastest %s (%s), slowest %s (%s)\n",
+				diff.Lowest, formatOptionalLatency(diff.Min),
+				diff.Highest, formatOptionalLatency(diff.Max)))
+		default:
+			label := "Context window"
+			if diff.Field == probe.CompareFieldMaxOutput {
+				label = "Max output"
+			}
+			clamp := 100 - int(diff.Min*100/diff.Max)
+			sb.WriteString(fmt.Sprintf("  - %s: %s clamps to %s tokens, %d%% below %s (%s tokens)\n",
+				label,
+				diff.Lowest, formatNumber(int(diff.Min)),
+				clamp,
+				diff.Highest, formatNumber(int(diff.Max))))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatOptionalTokens は未測定の場合に"-"を返すトークン数整形
+func formatOptionalTokens(tokens int) string {
+	if tokens <= 0 {
+		return "-"
+	}
+	return formatNumber(tokens)
+}
+
+// formatOptionalLatency は未測定の場合に"-"を返すレイテンシ整形
+func formatOptionalLatency(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	return formatDuration(time.Duration(ms) * time.Millisecond)
+}
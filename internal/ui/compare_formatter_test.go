@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/armaniacs/llm-info/internal/probe"
+)
+
+func TestTableFormatter_FormatComparison(t *testing.T) {
+	formatter := NewTableFormatter()
+
+	tests := []struct {
+		name     string
+		gateways []probe.GatewayComparison
+		want     []string
+	}{
+		{
+			name: "no differences",
+			gateways: []probe.GatewayComparison{
+				{Gateway: "prodA", ContextWindow: 128000, MaxOutput: 16384},
+				{Gateway: "prodB", ContextWindow: 128000, MaxOutput: 16384},
+			},
+			want: []string{"Gateway Comparison: gpt-4o", "prodA", "128,000", "No differences detected"},
+		},
+		{
+			name: "clamped context window",
+			gateways: []probe.GatewayComparison{
+				{Gateway: "prodA", ContextWindow: 128000, AvgLatencyMs: 120},
+				{Gateway: "prodB", ContextWindow: 32000, AvgLatencyMs: 480},
+			},
+			want: []string{
+				"Differences:",
+				"prodB clamps to 32,000 tokens, 75% below prodA",
+				"fastest prodA",
+				"slowest prodB",
+			},
+		},
+		{
+			name: "failed gateway",
+			gateways: []probe.GatewayComparison{
+				{Gateway: "prodA", ContextWindow: 128000},
+				{Gateway: "prodB", Error: "connection refused"},
+			},
+			want: []string{"✗ connection refused", "-"},
+		},
+		{
+			name: "long multibyte error",
+			gateways: []probe.GatewayComparison{
+				{Gateway: "prodA", ContextWindow: 128000},
+				{Gateway: "prodB", Error: strings.Repeat("接続が拒否されました", 5)},
+			},
+			want: []string{"✗ 接続が拒否されました接続が拒否されました接続が拒否されました接続が拒否..."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := probe.NewComparisonReport("gpt-4o", tt.gateways)
+			got := formatter.FormatComparison(report)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("FormatComparison() missing %q in:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/armaniacs/llm-info/internal/cost"
 )
 
-// FormatDryRunCostEstimate はDry-run時のコスト概算を表示用にフォーマットする
+// FormatDryRunCostEstimate はDry-run時の呼び出し回数・トークン数・コストの概算を表示用にフォーマットする
 func FormatDryRunCostEstimate(summary *cost.UsageSummary, calculator *cost.Calculator) string {
 	var sb strings.Builder
 
@@ -15,21 +15,29 @@ func FormatDryRunCostEstimate(summary *cost.UsageSummary, calculator *cost.Calcu
 	sb.WriteString("Estimated API Usage:\n")
 	sb.WriteString(strings.Repeat("─", 40) + "\n")
 
-	// Context Window Probeの見積もり
-	if contextUsage, ok := summary.BreakdownByProbe["context"]; ok {
-		sb.WriteString(fmt.Sprintf("  Context Window Probe : ~%d trials, ~%s\n",
-			contextUsage.Trials,
-			calculator.FormatCost(contextUsage.Cost, 3)))
+	probes := []struct{ key, label string }{
+		{"context", "Context Window Probe"},
+		{"max_output", "Max Output Probe"},
+		{"max_input", "Max Input Probe"},
 	}
-
-	// Max Output Probeの見積もり
-	if outputUsage, ok := summary.BreakdownByProbe["max_output"]; ok {
-		sb.WriteString(fmt.Sprintf("  Max Output Probe     : ~%d trials, ~%s\n",
-			outputUsage.Trials,
-			calculator.FormatCost(outputUsage.Cost, 3)))
+	for _, p := range probes {
+		usage, ok := summary.BreakdownByProbe[p.key]
+		if !ok {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  %-20s : ~%d calls, ~%s tokens, ~%s\n",
+			p.label,
+			usage.Trials,
+			calculator.FormatTokenCount(usage.InputTokens+usage.OutputTokens),
+			calculator.FormatCost(usage.Cost, 3)))
 	}
 
 	sb.WriteString(strings.Repeat("─", 40) + "\n")
+	sb.WriteString(fmt.Sprintf("  Total API Calls      : ~%d\n", getTotalTrials(summary)))
+	sb.WriteString(fmt.Sprintf("  Total Tokens         : ~%s (%s input, %s output)\n",
+		calculator.FormatTokenCount(summary.TotalTokens),
+		calculator.FormatTokenCount(summary.TotalInputTokens),
+		calculator.FormatTokenCount(summary.TotalOutputTokens)))
 	sb.WriteString(fmt.Sprintf("  Total Estimated Cost : ~%s\n",
 		calculator.FormatCost(summary.TotalCost, 3)))
 
@@ -64,9 +64,24 @@ func DisplayEndpoint(urlStr string) error {
 	return nil
 }
 
-// printEndpoint はエンドポイント情報を実際に出力する
+// printEndpoint はエンドポイント情報を標準エラー出力に出力する（--quietでは表示しない）
 func printEndpoint(urlStr string) {
-	println()
-	println("Endpoint:", urlStr)
-	println()
-}
\ No newline at end of file
+	Infof("\nEndpoint: %s\n\n", urlStr)
+}
+
+// FailoverInfo は設定したゲートウェイが停止していて、failoverに指定した別のゲートウェイが応答したことを表す
+type FailoverInfo struct {
+	Primary  string `json:"primary"`   // 設定したゲートウェイ
+	ServedBy string `json:"served_by"` // 実際に応答したゲートウェイ
+	URL      string `json:"url"`       // 応答したゲートウェイのURL
+	Reason   string `json:"reason"`    // 設定したゲートウェイのエラー
+}
+
+// DisplayFailover は別のゲートウェイが応答したことを標準エラー出力に表示する
+// 出力の内容が設定したゲートウェイのものではないため、--quietでも表示する
+func DisplayFailover(info *FailoverInfo) {
+	if info == nil {
+		return
+	}
+	Warnf("⚠️  Served by gateway %s (%s) because %s is unavailable: %s", info.ServedBy, MaskURL(info.URL), info.Primary, info.Reason)
+}
@@ -5,31 +5,64 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/numfmt"
+	"github.com/armaniacs/llm-info/internal/parallel"
 )
 
 // FilterCriteria はフィルタ条件を表す
 type FilterCriteria struct {
-	NamePattern    string   // モデル名のパターン（正規表現）
-	MinTokens      int      // 最小トークン数
-	MaxTokens      int      // 最大トークン数
-	Modes          []string // 許可するモード
-	MinInputCost   float64  // 最小入力コスト
-	MaxInputCost   float64  // 最大入力コスト
-	ExcludePattern string   // 除外するパターン
+	NamePattern    string        // モデル名のパターン（正規表現）
+	NameEquals     []string      // モデル名の完全一致（name=gpt-4o、大文字小文字を区別しない、複数指定はいずれか）
+	NameExact      []string      // 大文字小文字も区別する完全一致（name==GPT-4o、複数指定はname=と合わせていずれか）
+	NameNotEquals  []string      // 除外するモデル名の完全一致（name!=gpt-4o、大文字小文字を区別しない）
+	MinTokens      int           // 最小トークン数
+	MaxTokens      int           // 最大トークン数
+	Modes          []string      // 許可するモード
+	MinInputCost   float64       // 最小入力コスト
+	MaxInputCost   float64       // 最大入力コスト
+	ExcludePattern string        // 除外するパターン
+	MetaFilters    []MetaFilter  // メタデータの条件
+	MeasuredBefore time.Duration // この期間より前に探索したモデル（measured_at<30d）
+	MeasuredWithin time.Duration // この期間内に探索したモデル（measured_at>7d）
+	Incomplete     bool          // max_tokensか入力コストがないモデルのみ（incomplete）
+	Pinned         bool          // llm-info pinでピン留めしたモデルのみ（pinned）
+	Tags           []string      // llm-info noteで付けたタグがすべて付いたモデルのみ（tag:approved）
+}
+
+// MetaFilter はメタデータのキーに対する条件を表す
+type MetaFilter struct {
+	Key      string // メタデータのキー（ドット区切りでネスト可）
+	Operator string // ":"（一致）、">"、">="、"<"、"<="
+	Value    string // 比較する値
 }
 
 // Filter はフィルタ条件に基づいてモデルをフィルタリングする
+// モデル数が多い場合は複数のゴルーチンで判定し、結果は入力順を保つ
 func Filter(models []model.Model, criteria *FilterCriteria) []model.Model {
+	return filterWithWorkers(models, criteria, parallel.Workers())
+}
+
+// filterWithWorkers はゴルーチン数を指定してFilterを実行する
+func filterWithWorkers(models []model.Model, criteria *FilterCriteria, workers int) []model.Model {
 	if criteria == nil {
 		return models
 	}
 
+	matcher := newCriteriaMatcher(criteria)
+	keep := make([]bool, len(models))
+	parallel.ForWorkers(len(models), workers, func(start, end int) {
+		for i := start; i < end; i++ {
+			keep[i] = matcher.match(models[i])
+		}
+	})
+
 	var filtered []model.Model
-	for _, model := range models {
-		if matchesCriteria(model, criteria) {
-			filtered = append(filtered, model)
+	for i, m := range models {
+		if keep[i] {
+			filtered = append(filtered, m)
 		}
 	}
 
@@ -41,19 +74,50 @@ func matchesCriteria(model model.Model, criteria *FilterCriteria) bool {
 	if criteria == nil {
 		return true
 	}
+	return newCriteriaMatcher(criteria).match(model)
+}
 
-	// 名前パターンのチェック
+// criteriaMatcher は正規表現をコンパイル済みのフィルタ条件
+// モデルごとにパターンをコンパイルしないよう、Filterの呼び出しごとに1度だけ作成する
+type criteriaMatcher struct {
+	criteria *FilterCriteria
+	name     *regexp.Regexp
+	exclude  *regexp.Regexp
+	nameErr  bool      // 名前パターンが不正な場合はどのモデルにも一致しない
+	now      time.Time // measured_atの経過時間の基準
+}
+
+// newCriteriaMatcher はフィルタ条件の正規表現をコンパイルする
+func newCriteriaMatcher(criteria *FilterCriteria) *criteriaMatcher {
+	m := &criteriaMatcher{criteria: criteria, now: time.Now()}
 	if criteria.NamePattern != "" {
-		if matched, _ := regexp.MatchString(criteria.NamePattern, model.Name); !matched {
-			return false
-		}
+		re, err := regexp.Compile(criteria.NamePattern)
+		m.name, m.nameErr = re, err != nil
+	}
+	if criteria.ExcludePattern != "" {
+		// 不正な除外パターンは何も除外しない
+		m.exclude, _ = regexp.Compile(criteria.ExcludePattern)
+	}
+	return m
+}
+
+// match はモデルがフィルタ条件に一致するかチェックする
+func (cm *criteriaMatcher) match(model model.Model) bool {
+	criteria := cm.criteria
+
+	// 名前パターンのチェック
+	if cm.nameErr || (cm.name != nil && !cm.name.MatchString(model.Name)) {
+		return false
 	}
 
 	// 除外パターンのチェック
-	if criteria.ExcludePattern != "" {
-		if matched, _ := regexp.MatchString(criteria.ExcludePattern, model.Name); matched {
-			return false
-		}
+	if cm.exclude != nil && cm.exclude.MatchString(model.Name) {
+		return false
+	}
+
+	// モデル名の完全一致のチェック
+	if !matchesNameEquality(model.Name, criteria) {
+		return false
 	}
 
 	// トークン数の範囲チェック
@@ -86,9 +150,105 @@ func matchesCriteria(model model.Model, criteria *FilterCriteria) bool {
 		return false
 	}
 
+	// ピン留めのチェック
+	if criteria.Pinned && !model.IsPinned() {
+		return false
+	}
+
+	// タグのチェック
+	for _, tag := range criteria.Tags {
+		if !model.HasTag(tag) {
+			return false
+		}
+	}
+
+	// メタデータが欠けたモデルのチェック
+	if criteria.Incomplete && !model.IsIncomplete() {
+		return false
+	}
+
+	// メタデータのチェック
+	for _, mf := range criteria.MetaFilters {
+		if !matchesMetaFilter(model, mf) {
+			return false
+		}
+	}
+
+	// 探索結果の経過時間のチェック（探索結果がないモデルは一致しない）
+	if criteria.MeasuredBefore > 0 || criteria.MeasuredWithin > 0 {
+		measuredAt, ok := model.MeasuredAt()
+		if !ok {
+			return false
+		}
+		age := cm.now.Sub(measuredAt)
+		if criteria.MeasuredBefore > 0 && age <= criteria.MeasuredBefore {
+			return false
+		}
+		if criteria.MeasuredWithin > 0 && age > criteria.MeasuredWithin {
+			return false
+		}
+	}
+
 	return true
 }
 
+// matchesNameEquality はモデル名がname=、name==、name!=の条件に一致するかチェックする
+// name=とname==はいずれかに一致すればよく、name!=はどれにも一致してはいけない
+func matchesNameEquality(name string, criteria *FilterCriteria) bool {
+	for _, excluded := range criteria.NameNotEquals {
+		if strings.EqualFold(name, excluded) {
+			return false
+		}
+	}
+	if len(criteria.NameEquals) == 0 && len(criteria.NameExact) == 0 {
+		return true
+	}
+	for _, expected := range criteria.NameEquals {
+		if strings.EqualFold(name, expected) {
+			return true
+		}
+	}
+	for _, expected := range criteria.NameExact {
+		if name == expected {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMetaFilter はモデルのメタデータが条件に一致するかチェックする
+// キーが存在しないモデルは一致しない
+func matchesMetaFilter(m model.Model, mf MetaFilter) bool {
+	value, ok := m.MetaValue(mf.Key)
+	if !ok || value == nil {
+		return false
+	}
+
+	if mf.Operator == ":" {
+		return strings.EqualFold(model.FormatMetaValue(value), mf.Value)
+	}
+
+	number, ok := value.(float64)
+	if !ok {
+		parsed, err := strconv.ParseFloat(model.FormatMetaValue(value), 64)
+		if err != nil {
+			return false
+		}
+		number = parsed
+	}
+	threshold, _ := strconv.ParseFloat(mf.Value, 64)
+
+	switch mf.Operator {
+	case ">":
+		return number > threshold
+	case ">=":
+		return number >= threshold
+	case "<=":
+		return number <= threshold
+	}
+	return number < threshold
+}
+
 // ParseFilterString はフィルタ文字列を解析してFilterCriteriaを返す
 func ParseFilterString(filterStr string) (*FilterCriteria, error) {
 	if filterStr == "" {
@@ -115,6 +275,46 @@ func ParseFilterString(filterStr string) (*FilterCriteria, error) {
 
 // parseFilterPart は個別のフィルタ条件を解析する
 func parseFilterPart(part string, criteria *FilterCriteria) error {
+	// メタデータフィルタ（例: "meta.supports_vision:true", "meta.max_input_tokens>100000"）
+	// キーに"tokens"や"cost"を含むことがあるため、他のフィルタより先に判定する
+	if strings.HasPrefix(part, "meta.") {
+		return parseMetaFilter(part, criteria)
+	}
+
+	// 探索結果フィルタ（例: "measured_context>100000", "measured_at<30d"）
+	// 名前パターンとして扱われないよう、単純な文字列より先に判定する
+	if strings.HasPrefix(part, "measured_") {
+		return parseMeasuredFilter(part, criteria)
+	}
+
+	// max_tokensか入力コストがないモデル（例: "incomplete"）
+	if part == "incomplete" {
+		criteria.Incomplete = true
+		return nil
+	}
+
+	// ピン留めしたモデル（例: "pinned"）
+	if part == "pinned" {
+		criteria.Pinned = true
+		return nil
+	}
+
+	// タグフィルタ（例: "tag:approved"）
+	if strings.HasPrefix(part, "tag:") {
+		tag := strings.TrimPrefix(part, "tag:")
+		if tag == "" {
+			return fmt.Errorf("invalid tag filter format: %s", part)
+		}
+		criteria.Tags = append(criteria.Tags, tag)
+		return nil
+	}
+
+	// モデル名の完全一致（例: "name=gpt-4o", "name==GPT-4o", "name!=gpt-4o"）
+	// "name=="と"name!="を"name="より先に判定する
+	if strings.HasPrefix(part, "name==") || strings.HasPrefix(part, "name!=") || strings.HasPrefix(part, "name=") {
+		return parseNameEquality(part, criteria)
+	}
+
 	// 名前フィルタ（例: "name:gpt"）
 	if strings.HasPrefix(part, "name:") {
 		criteria.NamePattern = strings.TrimPrefix(part, "name:")
@@ -149,33 +349,159 @@ func parseFilterPart(part string, criteria *FilterCriteria) error {
 	return nil
 }
 
-// parseTokenFilter はトークン数フィルタを解析する
-func parseTokenFilter(part string, criteria *FilterCriteria) error {
-	if strings.Contains(part, ">") {
-		parts := strings.Split(part, ">")
-		if len(parts) != 2 || parts[0] != "tokens" {
-			return fmt.Errorf("invalid token filter format: %s", part)
+// parseNameEquality はモデル名の完全一致のフィルタを解析する
+func parseNameEquality(part string, criteria *FilterCriteria) error {
+	for _, op := range []struct {
+		prefix string
+		values *[]string
+	}{
+		{"name==", &criteria.NameExact},
+		{"name!=", &criteria.NameNotEquals},
+		{"name=", &criteria.NameEquals},
+	} {
+		if !strings.HasPrefix(part, op.prefix) {
+			continue
 		}
-		value, err := strconv.Atoi(parts[1])
-		if err != nil {
-			return fmt.Errorf("invalid token value: %s", parts[1])
+		value := strings.TrimSpace(strings.TrimPrefix(part, op.prefix))
+		if value == "" {
+			return fmt.Errorf("invalid name filter format: %s", part)
 		}
-		criteria.MinTokens = value
-	} else if strings.Contains(part, "<") {
-		parts := strings.Split(part, "<")
-		if len(parts) != 2 || parts[0] != "tokens" {
-			return fmt.Errorf("invalid token filter format: %s", part)
+		*op.values = append(*op.values, value)
+		return nil
+	}
+	return fmt.Errorf("invalid name filter format: %s", part)
+}
+
+// parseMetaFilter はメタデータフィルタを解析する
+func parseMetaFilter(part string, criteria *FilterCriteria) error {
+	expr := strings.TrimPrefix(part, "meta.")
+
+	index := strings.IndexAny(expr, ":><")
+	if index <= 0 {
+		return fmt.Errorf("invalid meta filter format: %s", part)
+	}
+	if expr[index] == ':' {
+		criteria.MetaFilters = append(criteria.MetaFilters, MetaFilter{Key: expr[:index], Operator: ":", Value: expr[index+1:]})
+		return nil
+	}
+
+	key, operator, value, _ := splitComparison(expr)
+	threshold, err := parseThreshold(value)
+	if err != nil {
+		return fmt.Errorf("invalid meta value: %s", value)
+	}
+	mf := MetaFilter{Key: key, Operator: operator, Value: threshold}
+
+	criteria.MetaFilters = append(criteria.MetaFilters, mf)
+	return nil
+}
+
+// parseMeasuredFilter は保存済みの探索結果に対するフィルタを解析する
+// measured_contextとmeasured_max_outputはメタデータの数値比較として扱う
+// measured_atは経過時間で比較し、"<30d"は30日より前、">7d"は7日以内に探索したモデルに一致する
+func parseMeasuredFilter(part string, criteria *FilterCriteria) error {
+	key, operator, value, ok := splitComparison(part)
+	if !ok {
+		return fmt.Errorf("invalid measured filter format: %s", part)
+	}
+
+	switch key {
+	case model.MetaMeasuredContext, model.MetaMeasuredMaxOutput:
+		tokens, err := numfmt.ParseTokens(value)
+		if err != nil {
+			return fmt.Errorf("invalid token value: %s", value)
 		}
-		value, err := strconv.Atoi(parts[1])
+		criteria.MetaFilters = append(criteria.MetaFilters, MetaFilter{Key: key, Operator: operator, Value: strconv.Itoa(tokens)})
+	case model.MetaMeasuredAt:
+		age, err := parseAge(value)
 		if err != nil {
-			return fmt.Errorf("invalid token value: %s", parts[1])
+			return err
 		}
-		criteria.MaxTokens = value
+		if strings.HasPrefix(operator, "<") {
+			criteria.MeasuredBefore = age
+		} else {
+			criteria.MeasuredWithin = age
+		}
+	default:
+		return fmt.Errorf("invalid measured filter format: %s", part)
 	}
+	return nil
+}
 
+// parseAge は"30d"、"2w"、"12h"のような経過時間を解析する
+func parseAge(value string) (time.Duration, error) {
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(value, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(value, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit > 0 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid age value: %s", value)
+		}
+		return time.Duration(n) * unit, nil
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("invalid age value: %s", value)
+	}
+	return age, nil
+}
+
+// parseTokenFilter はトークン数フィルタを解析する
+func parseTokenFilter(part string, criteria *FilterCriteria) error {
+	key, operator, value, ok := splitComparison(part)
+	if !ok {
+		return nil
+	}
+	if key != "tokens" || strings.ContainsAny(value, "<>") {
+		return fmt.Errorf("invalid token filter format: %s", part)
+	}
+	tokens, err := numfmt.ParseTokens(value)
+	if err != nil {
+		return fmt.Errorf("invalid token value: %s", value)
+	}
+
+	// tokens>とtokens<は指定値を含むため、tokens>=とtokens<=も同じ範囲として扱う
+	if strings.HasPrefix(operator, ">") {
+		criteria.MinTokens = tokens
+	} else {
+		criteria.MaxTokens = tokens
+	}
 	return nil
 }
 
+// splitComparison は"key>=value"のような比較をキー・演算子（>、>=、<、<=）・値に分ける
+// 演算子がない場合やキーが空の場合はokがfalseになる
+func splitComparison(part string) (key, operator, value string, ok bool) {
+	index := strings.IndexAny(part, "><")
+	if index <= 0 {
+		return "", "", "", false
+	}
+	operator = part[index : index+1]
+	if strings.HasPrefix(part[index+1:], "=") {
+		operator += "="
+	}
+	return part[:index], operator, part[index+len(operator):], true
+}
+
+// parseThreshold はメタデータの数値比較の値を解析する
+// 小数（コストなど）のほか、トークン数と同じk、m、bの接頭辞も使える（meta.max_input_tokens>128k）
+func parseThreshold(value string) (string, error) {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value, nil
+	}
+	tokens, err := numfmt.ParseTokens(value)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(tokens), nil
+}
+
 // parseCostFilter はコストフィルタを解析する
 func parseCostFilter(part string, criteria *FilterCriteria) error {
 	if strings.Contains(part, ">") {
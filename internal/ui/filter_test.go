@@ -1,7 +1,9 @@
 package ui
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/armaniacs/llm-info/internal/model"
 )
@@ -386,3 +388,342 @@ func TestMatchesCriteria(t *testing.T) {
 		})
 	}
 }
+
+func TestFilter_Meta(t *testing.T) {
+	models := []model.Model{
+		{Name: "gpt-4o", Metadata: map[string]interface{}{"supports_vision": true, "litellm_provider": "openai", "max_input_tokens": 128000.0}},
+		{Name: "gpt-3.5-turbo", Metadata: map[string]interface{}{"supports_vision": false, "litellm_provider": "openai", "max_input_tokens": 16385.0}},
+		{Name: "claude-3-haiku", Metadata: map[string]interface{}{"model_info": map[string]interface{}{"supports_vision": true, "litellm_provider": "anthropic"}}},
+		{Name: "no-metadata"},
+	}
+
+	tests := []struct {
+		name      string
+		filterStr string
+		expected  []string
+		wantErr   bool
+	}{
+		{
+			name:      "boolean metadata",
+			filterStr: "meta.supports_vision:true",
+			expected:  []string{"gpt-4o", "claude-3-haiku"},
+		},
+		{
+			name:      "string metadata is case insensitive",
+			filterStr: "meta.litellm_provider:OpenAI",
+			expected:  []string{"gpt-4o", "gpt-3.5-turbo"},
+		},
+		{
+			name:      "numeric comparison",
+			filterStr: "meta.max_input_tokens>100000",
+			expected:  []string{"gpt-4o"},
+		},
+		{
+			name:      "inclusive comparison with unit suffix",
+			filterStr: "meta.max_input_tokens>=128k",
+			expected:  []string{"gpt-4o"},
+		},
+		{
+			name:      "inclusive upper bound",
+			filterStr: "meta.max_input_tokens<=16385",
+			expected:  []string{"gpt-3.5-turbo"},
+		},
+		{
+			name:      "nested key",
+			filterStr: "meta.model_info.litellm_provider:anthropic",
+			expected:  []string{"claude-3-haiku"},
+		},
+		{
+			name:      "combined with name filter",
+			filterStr: "name:gpt,meta.supports_vision:false",
+			expected:  []string{"gpt-3.5-turbo"},
+		},
+		{
+			name:      "missing key",
+			filterStr: "meta.unknown:true",
+			expected:  nil,
+		},
+		{
+			name:      "missing key name",
+			filterStr: "meta.:true",
+			wantErr:   true,
+		},
+		{
+			name:      "non-numeric comparison",
+			filterStr: "meta.max_input_tokens>many",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria, err := ParseFilterString(tt.filterStr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFilterString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []string
+			for _, m := range Filter(models, criteria) {
+				got = append(got, m.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Filter() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilter_Measured(t *testing.T) {
+	now := time.Now()
+	models := []model.Model{{Name: "fresh"}, {Name: "stale"}, {Name: "small"}, {Name: "unmeasured"}}
+	model.ApplyMeasurements(models, map[string]model.Measurement{
+		"fresh": {ContextWindow: 128000, MaxOutput: 16384, MeasuredAt: now.Add(-2 * 24 * time.Hour)},
+		"stale": {ContextWindow: 200000, MaxOutput: 4096, MeasuredAt: now.Add(-45 * 24 * time.Hour)},
+		"small": {ContextWindow: 8192, MeasuredAt: now.Add(-10 * 24 * time.Hour)},
+	})
+
+	tests := []struct {
+		name      string
+		filterStr string
+		expected  []string
+		wantErr   bool
+	}{
+		{name: "measured context", filterStr: "measured_context>100000", expected: []string{"fresh", "stale"}},
+		{name: "measured max output", filterStr: "measured_max_output<8192", expected: []string{"stale"}},
+		{name: "stale measurements", filterStr: "measured_at<30d", expected: []string{"stale"}},
+		{name: "recent measurements", filterStr: "measured_at>1w", expected: []string{"fresh"}},
+		{name: "hours", filterStr: "measured_at>72h", expected: []string{"fresh"}},
+		{name: "combined", filterStr: "measured_context>1000,measured_at>30d", expected: []string{"fresh", "small"}},
+		{name: "invalid age", filterStr: "measured_at<soon", wantErr: true},
+		{name: "invalid value", filterStr: "measured_context>many", wantErr: true},
+		{name: "unknown field", filterStr: "measured_cost>1", wantErr: true},
+		{name: "equality is not supported", filterStr: "measured_context:1000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria, err := ParseFilterString(tt.filterStr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFilterString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []string
+			for _, m := range Filter(models, criteria) {
+				got = append(got, m.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Filter() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilter_Incomplete(t *testing.T) {
+	models := []model.Model{
+		{Name: "gpt-4o", MaxTokens: 128000, InputCost: 0.0000025, Mode: "chat"},
+		{Name: "gpt-4o-mini", Mode: "chat"},
+		{Name: "text-embedding-3-small", MaxTokens: 8191, Mode: "embedding"},
+		{Name: "openai/*"},
+	}
+
+	tests := []struct {
+		filterStr string
+		expected  []string
+	}{
+		{filterStr: "incomplete", expected: []string{"gpt-4o-mini", "text-embedding-3-small"}},
+		{filterStr: "incomplete,mode:chat", expected: []string{"gpt-4o-mini"}},
+		{filterStr: "gpt", expected: []string{"gpt-4o", "gpt-4o-mini"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filterStr, func(t *testing.T) {
+			criteria, err := ParseFilterString(tt.filterStr)
+			if err != nil {
+				t.Fatalf("ParseFilterString() error = %v", err)
+			}
+
+			var got []string
+			for _, m := range Filter(models, criteria) {
+				got = append(got, m.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Filter() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilter_Tag(t *testing.T) {
+	models := []model.Model{
+		{Name: "gpt-4o", Mode: "chat"},
+		{Name: "gpt-4o-mini", Mode: "chat"},
+		{Name: "claude-3-haiku", Mode: "chat"},
+	}
+	model.ApplyAnnotations(models, map[string]model.Annotation{
+		"gpt-4o":         {Tags: []string{"approved", "eu"}},
+		"claude-3-haiku": {Tags: []string{"Approved"}},
+	})
+
+	tests := []struct {
+		filterStr string
+		expected  []string
+	}{
+		{filterStr: "tag:approved", expected: []string{"gpt-4o", "claude-3-haiku"}},
+		{filterStr: "tag:approved,tag:eu", expected: []string{"gpt-4o"}},
+		{filterStr: "tag:approved,name:claude", expected: []string{"claude-3-haiku"}},
+		{filterStr: "tag:missing", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filterStr, func(t *testing.T) {
+			criteria, err := ParseFilterString(tt.filterStr)
+			if err != nil {
+				t.Fatalf("ParseFilterString() error = %v", err)
+			}
+
+			var got []string
+			for _, m := range Filter(models, criteria) {
+				got = append(got, m.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Filter() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := ParseFilterString("tag:"); err == nil {
+		t.Error("ParseFilterString(tag:) error = nil, want error")
+	}
+}
+
+func TestFilter_Pinned(t *testing.T) {
+	models := []model.Model{
+		{Name: "gpt-4o", Mode: "chat"},
+		{Name: "gpt-4o-mini", Mode: "chat"},
+		{Name: "text-embedding-3-small", Mode: "embedding"},
+	}
+	model.ApplyPins(models, []string{"gpt-4o", "text-embedding-3-small"})
+
+	tests := []struct {
+		filterStr string
+		expected  []string
+	}{
+		{filterStr: "pinned", expected: []string{"gpt-4o", "text-embedding-3-small"}},
+		{filterStr: "pinned,mode:chat", expected: []string{"gpt-4o"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filterStr, func(t *testing.T) {
+			criteria, err := ParseFilterString(tt.filterStr)
+			if err != nil {
+				t.Fatalf("ParseFilterString() error = %v", err)
+			}
+
+			var got []string
+			for _, m := range Filter(models, criteria) {
+				got = append(got, m.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Filter() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilter_NameEquality(t *testing.T) {
+	models := []model.Model{
+		{Name: "gpt-4", Mode: "chat"},
+		{Name: "gpt-4o", Mode: "chat"},
+		{Name: "GPT-4o", Mode: "chat"},
+		{Name: "gpt-4o-mini", Mode: "chat"},
+	}
+
+	tests := []struct {
+		filterStr string
+		expected  []string
+	}{
+		{filterStr: "name=gpt-4", expected: []string{"gpt-4"}},
+		{filterStr: "name=GPT-4O", expected: []string{"gpt-4o", "GPT-4o"}},
+		{filterStr: "name==GPT-4o", expected: []string{"GPT-4o"}},
+		{filterStr: "name=gpt-4,name==gpt-4o-mini", expected: []string{"gpt-4", "gpt-4o-mini"}},
+		{filterStr: "name:gpt-4,name!=gpt-4o", expected: []string{"gpt-4", "gpt-4o-mini"}},
+		{filterStr: "name!=gpt-4,name!=gpt-4o-mini", expected: []string{"gpt-4o", "GPT-4o"}},
+		{filterStr: "name=claude", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filterStr, func(t *testing.T) {
+			criteria, err := ParseFilterString(tt.filterStr)
+			if err != nil {
+				t.Fatalf("ParseFilterString() error = %v", err)
+			}
+
+			var got []string
+			for _, m := range Filter(models, criteria) {
+				got = append(got, m.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Filter() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	for _, filterStr := range []string{"name=", "name==", "name!="} {
+		if _, err := ParseFilterString(filterStr); err == nil {
+			t.Errorf("ParseFilterString(%s) error = nil, want error", filterStr)
+		}
+	}
+}
+
+func TestFilter_TokenSuffixes(t *testing.T) {
+	models := []model.Model{
+		{Name: "gpt-4", MaxTokens: 8192},
+		{Name: "gpt-4o", MaxTokens: 128000},
+		{Name: "gemini-1.5-pro", MaxTokens: 1000000},
+	}
+	model.ApplyMeasurements(models, map[string]model.Measurement{
+		"gpt-4o":         {ContextWindow: 128000, MeasuredAt: time.Now()},
+		"gemini-1.5-pro": {ContextWindow: 1048576, MeasuredAt: time.Now()},
+	})
+
+	tests := []struct {
+		filterStr string
+		expected  []string
+	}{
+		{filterStr: "tokens>128k", expected: []string{"gpt-4o", "gemini-1.5-pro"}},
+		{filterStr: "tokens>=1m", expected: []string{"gemini-1.5-pro"}},
+		{filterStr: "tokens<=128K", expected: []string{"gpt-4", "gpt-4o"}},
+		{filterStr: "tokens<8.2k", expected: []string{"gpt-4"}},
+		{filterStr: "measured_context>=1m", expected: []string{"gemini-1.5-pro"}},
+		{filterStr: "measured_context<=128k", expected: []string{"gpt-4o"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filterStr, func(t *testing.T) {
+			criteria, err := ParseFilterString(tt.filterStr)
+			if err != nil {
+				t.Fatalf("ParseFilterString() error = %v", err)
+			}
+
+			var got []string
+			for _, m := range Filter(models, criteria) {
+				got = append(got, m.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Filter() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	for _, filterStr := range []string{"tokens>12x", "tokens>=", "tokens>1k>2k", "measured_context>lots"} {
+		if _, err := ParseFilterString(filterStr); err == nil {
+			t.Errorf("ParseFilterString(%s) error = nil, want error", filterStr)
+		}
+	}
+}
@@ -24,12 +24,18 @@ func NewJSONRenderer(prettyPrint bool) *JSONRenderer {
 func (jr *JSONRenderer) Render(models []model.Model, options *RenderOptions) error {
 	var output interface{}
 
-	if options != nil && options.Filter != "" {
-		// フィルタ条件をメタデータとして含める
-		output = map[string]interface{}{
-			"filter": options.Filter,
+	if options != nil && (options.Filter != "" || options.Failover != nil) {
+		// フィルタ条件と、別のゲートウェイが応答した場合はその情報をメタデータとして含める
+		envelope := map[string]interface{}{
 			"models": models,
 		}
+		if options.Filter != "" {
+			envelope["filter"] = options.Filter
+		}
+		if options.Failover != nil {
+			envelope["failover"] = options.Failover
+		}
+		output = envelope
 	} else {
 		output = models
 	}
@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Verbosity controls which non-data messages are written.
+type Verbosity int
+
+const (
+	// VerbosityQuiet writes warnings only (--quiet).
+	VerbosityQuiet Verbosity = iota
+	// VerbosityNormal writes warnings and informational messages such as
+	// the endpoint banner and hints. This is the default.
+	VerbosityNormal
+	// VerbosityVerbose also writes debug messages (--verbose).
+	VerbosityVerbose
+)
+
+// Messenger writes messages that are not part of the command's data:
+// warnings, banners, hints and debug logs. Stdout is reserved for the data
+// itself (tables, JSON) so that output can be piped into other commands,
+// e.g. llm-info | column -t, without messages mixed in.
+type Messenger struct {
+	mu        sync.Mutex
+	w         io.Writer
+	verbosity Verbosity
+	noHints   bool
+	recent    []string // the last maxRecentMessages messages, written or not
+}
+
+// maxRecentMessages is how many messages a messenger keeps for crash
+// reports.
+const maxRecentMessages = 50
+
+// NewMessenger creates a messenger writing to w at the given verbosity.
+func NewMessenger(w io.Writer, verbosity Verbosity) *Messenger {
+	return &Messenger{w: w, verbosity: verbosity}
+}
+
+// SetVerbosity changes which messages are written.
+func (m *Messenger) SetVerbosity(verbosity Verbosity) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.verbosity = verbosity
+}
+
+// SetHints turns hints on or off (--no-hints).
+func (m *Messenger) SetHints(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.noHints = !enabled
+}
+
+// HintsEnabled reports whether hints are written at all, so that callers
+// can fall back to a terse message when they are not.
+func (m *Messenger) HintsEnabled() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.noHints && m.verbosity >= VerbosityNormal
+}
+
+// Warnf writes a warning. Warnings are written at every verbosity.
+func (m *Messenger) Warnf(format string, args ...interface{}) {
+	m.printf(VerbosityQuiet, format, args...)
+}
+
+// Infof writes an informational message, hidden by --quiet.
+func (m *Messenger) Infof(format string, args ...interface{}) {
+	m.printf(VerbosityNormal, format, args...)
+}
+
+// Hintf writes a suggestion for what to do next, hidden by --quiet and
+// --no-hints.
+func (m *Messenger) Hintf(format string, args ...interface{}) {
+	if !m.HintsEnabled() {
+		return
+	}
+	m.printf(VerbosityNormal, format, args...)
+}
+
+// Debugf writes a debug message, shown only with --verbose.
+func (m *Messenger) Debugf(format string, args ...interface{}) {
+	m.printf(VerbosityVerbose, format, args...)
+}
+
+// printf writes the message if the verbosity allows it, ending it with a
+// newline if the format does not.
+// Every message is also kept for RecentMessages, including debug messages
+// that were not written.
+func (m *Messenger) printf(level Verbosity, format string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	msg := fmt.Sprintf(format, args...)
+	m.recent = append(m.recent, strings.TrimSuffix(msg, "\n"))
+	if len(m.recent) > maxRecentMessages {
+		m.recent = m.recent[len(m.recent)-maxRecentMessages:]
+	}
+	if level > m.verbosity {
+		return
+	}
+	if !strings.HasSuffix(msg, "\n") {
+		msg += "\n"
+	}
+	io.WriteString(m.w, msg)
+}
+
+// RecentMessages returns the last messages, oldest first, whether or not
+// the verbosity allowed writing them.
+func (m *Messenger) RecentMessages() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.recent...)
+}
+
+// messages is the messenger used by the package-level functions.
+var messages = NewMessenger(os.Stderr, VerbosityNormal)
+
+// SetVerbosity changes which messages the package-level functions write.
+func SetVerbosity(verbosity Verbosity) {
+	messages.SetVerbosity(verbosity)
+}
+
+// Warnf writes a warning to stderr.
+func Warnf(format string, args ...interface{}) {
+	messages.Warnf(format, args...)
+}
+
+// Infof writes an informational message to stderr unless --quiet is set.
+func Infof(format string, args ...interface{}) {
+	messages.Infof(format, args...)
+}
+
+// SetHints turns the hints written by the package-level functions on or off.
+func SetHints(enabled bool) {
+	messages.SetHints(enabled)
+}
+
+// HintsEnabled reports whether the package-level functions write hints.
+func HintsEnabled() bool {
+	return messages.HintsEnabled()
+}
+
+// Hintf writes a hint to stderr unless --quiet or --no-hints is set.
+func Hintf(format string, args ...interface{}) {
+	messages.Hintf(format, args...)
+}
+
+// Debugf writes a debug message to stderr when --verbose is set.
+func Debugf(format string, args ...interface{}) {
+	messages.Debugf(format, args...)
+}
+
+// RecentMessages returns the last messages of the package-level functions,
+// for crash reports.
+func RecentMessages() []string {
+	return messages.RecentMessages()
+}
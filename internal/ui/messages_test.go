@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMessenger_Verbosity(t *testing.T) {
+	tests := []struct {
+		name      string
+		verbosity Verbosity
+		want      string
+	}{
+		{"quiet", VerbosityQuiet, "warn 1\n"},
+		{"normal", VerbosityNormal, "warn 1\ninfo 2\n"},
+		{"verbose", VerbosityVerbose, "warn 1\ninfo 2\ndebug 3\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			m := NewMessenger(&buf, tt.verbosity)
+			m.Warnf("warn %d", 1)
+			m.Infof("info %d\n", 2)
+			m.Debugf("debug %d", 3)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessenger_Hints(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewMessenger(&buf, VerbosityNormal)
+	m.Hintf("hint %d", 1)
+	m.SetHints(false)
+	m.Hintf("hint %d", 2)
+	if got := buf.String(); got != "hint 1\n" {
+		t.Errorf("output = %q, want %q", got, "hint 1\n")
+	}
+
+	buf.Reset()
+	quiet := NewMessenger(&buf, VerbosityQuiet)
+	quiet.Hintf("hint")
+	if buf.Len() != 0 || quiet.HintsEnabled() {
+		t.Errorf("hints written with --quiet: %q", buf.String())
+	}
+}
+
+func TestMessenger_RecentMessages(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewMessenger(&buf, VerbosityQuiet)
+	for i := 0; i < maxRecentMessages+5; i++ {
+		m.Debugf("debug %d\n", i)
+	}
+	m.Warnf("warn")
+
+	recent := m.RecentMessages()
+	if len(recent) != maxRecentMessages {
+		t.Fatalf("len(RecentMessages()) = %d, want %d", len(recent), maxRecentMessages)
+	}
+	if recent[0] != "debug 6" || recent[len(recent)-1] != "warn" {
+		t.Errorf("RecentMessages() = [%q ... %q], want [\"debug 6\" ... \"warn\"]", recent[0], recent[len(recent)-1])
+	}
+	if buf.String() != "warn\n" {
+		t.Errorf("output = %q, want only the warning", buf.String())
+	}
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// PluginProtocolVersion は外部フォーマッターに渡す入力の形式のバージョン
+// 互換性のない変更をした場合に上げる
+const PluginProtocolVersion = 1
+
+// 外部フォーマッターに渡す出力内容の種類
+const (
+	PluginKindModels      = "models"       // モデル一覧（dataは--format jsonと同じモデルの配列）
+	PluginKindProbeReport = "probe_report" // 探索レポート（dataは結果スキーマv2のレポート）
+)
+
+// defaultPluginTimeout は外部フォーマッターの実行のデフォルトタイムアウト
+const defaultPluginTimeout = 30 * time.Second
+
+// PluginInput は外部フォーマッターに標準入力で渡すJSON
+type PluginInput struct {
+	ProtocolVersion int         `json:"protocol_version"`
+	Format          string      `json:"format"`
+	Kind            string      `json:"kind"`
+	Gateway         string      `json:"gateway,omitempty"`
+	Data            interface{} `json:"data"`
+}
+
+// PluginFormatter は外部コマンドで出力を整形するフォーマッター
+// コマンドは標準入力でPluginInputを受け取り、整形結果を標準出力に書き出す
+type PluginFormatter struct {
+	Name    string
+	Command string
+	Timeout time.Duration
+}
+
+// NewPluginFormatter は新しいPluginFormatterを作成する
+func NewPluginFormatter(name, command string, timeout time.Duration) *PluginFormatter {
+	if timeout <= 0 {
+		timeout = defaultPluginTimeout
+	}
+	return &PluginFormatter{Name: name, Command: command, Timeout: timeout}
+}
+
+// Render は出力内容をコマンドに渡し、コマンドの標準出力をwに書き出す
+// コマンドが失敗した場合は標準エラー出力の内容を含むエラーを返す
+func (f *PluginFormatter) Render(w io.Writer, kind, gateway string, data interface{}) error {
+	input, err := json.Marshal(PluginInput{
+		ProtocolVersion: PluginProtocolVersion,
+		Format:          f.Name,
+		Kind:            kind,
+		Gateway:         gateway,
+		Data:            data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode input for formatter %s: %w", f.Name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.Timeout)
+	defer cancel()
+
+	// 失敗した場合に途中までの出力を表示しないよう、整形結果は終了後にまとめて書き出す
+	var stdout, stderr bytes.Buffer
+	cmd := pluginCommand(ctx, f.Command)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second
+	cmd.Env = append(os.Environ(),
+		"LLM_INFO_FORMAT="+f.Name,
+		"LLM_INFO_FORMAT_KIND="+kind,
+	)
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("formatter %s timed out after %s", f.Name, f.Timeout)
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("formatter %s failed: %w: %s", f.Name, err, message)
+		}
+		return fmt.Errorf("formatter %s failed: %w", f.Name, err)
+	}
+
+	_, err = w.Write(stdout.Bytes())
+	return err
+}
+
+// pluginCommand はコマンド文字列をシェル経由で実行するコマンドを作成する（引数を書けるようにする）
+func pluginCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/model"
+)
+
+func TestPluginFormatter_Render(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	// 入力のJSONと環境変数をそのまま書き出すフォーマッター
+	formatter := NewPluginFormatter("corp-report", `echo "$LLM_INFO_FORMAT $LLM_INFO_FORMAT_KIND"; cat`, 0)
+	if formatter.Timeout != defaultPluginTimeout {
+		t.Errorf("Timeout = %s, want default %s", formatter.Timeout, defaultPluginTimeout)
+	}
+
+	var out bytes.Buffer
+	models := []model.Model{{Name: "gpt-4o", MaxTokens: 128000}}
+	if err := formatter.Render(&out, PluginKindModels, "production", models); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	lines := strings.SplitN(out.String(), "\n", 2)
+	if lines[0] != "corp-report models" {
+		t.Errorf("env = %q", lines[0])
+	}
+	for _, want := range []string{`"protocol_version":1`, `"format":"corp-report"`, `"kind":"models"`, `"gateway":"production"`, `"gpt-4o"`} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("input %s does not contain %s", lines[1], want)
+		}
+	}
+}
+
+func TestPluginFormatter_RenderErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	// 失敗した場合は途中までの出力を書き出さず、標準エラー出力をエラーに含める
+	var out bytes.Buffer
+	failing := NewPluginFormatter("broken", "echo partial; echo 'unsupported kind' >&2; exit 2", 0)
+	err := failing.Render(&out, PluginKindProbeReport, "", map[string]string{})
+	if err == nil || !strings.Contains(err.Error(), "unsupported kind") {
+		t.Errorf("Render() error = %v, want stderr in error", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("output = %q, want none", out.String())
+	}
+
+	slow := NewPluginFormatter("slow", "sleep 5", 100*time.Millisecond)
+	if err := slow.Render(&out, PluginKindModels, "", nil); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Render() error = %v, want timeout", err)
+	}
+}
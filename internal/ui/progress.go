@@ -0,0 +1,399 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/redact"
+)
+
+// progressWindow is the number of recent durations averaged for the ETA
+const progressWindow = 5
+
+// progressBarWidth is the width of the bar drawn on interactive terminals
+const progressBarWidth = 20
+
+// Progress renders progress for long operations: the current item of a
+// batch (model or gateway), the current phase, trials done and an ETA
+// derived from the moving average of recent trial and item durations.
+//
+// On an interactive terminal a single status line is redrawn in place;
+// in plain mode (--plain or output that is not a terminal) one line is
+// written per finished trial or item so CI logs stay readable.
+//
+// Progress implements probe.VerboseLogger, so it can be passed to
+// SetVerboseLogger when --verbose is not used. A nil *Progress is valid
+// and renders nothing (--quiet).
+type Progress struct {
+	mu    sync.Mutex
+	w     io.Writer
+	plain bool
+	now   func() time.Time
+
+	label     string
+	item      int
+	items     int
+	itemStart time.Time
+
+	phase      string
+	strategy   string
+	trial      int
+	maxTrials  int
+	tokens     int
+	trialsDone int
+	trialStart time.Time
+
+	trialDurations movingAverage
+	itemDurations  movingAverage
+	lastLineLength int
+}
+
+// NewProgress creates a renderer writing to w. With plain set, progress is
+// written as log lines instead of a redrawn status line.
+func NewProgress(w io.Writer, plain bool) *Progress {
+	return &Progress{w: w, plain: plain, now: time.Now}
+}
+
+// StartItem starts the index-th (1-based) of total items, e.g. a model of a
+// batch probe or a gateway of a multi-gateway fetch
+func (p *Progress) StartItem(label string, index, total int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.label, p.item, p.items = label, index, total
+	p.itemStart = p.now()
+	p.phase, p.strategy = "", ""
+	p.resetTrials()
+	if p.plain {
+		p.writeLine(p.prefix() + "started")
+		return
+	}
+	p.render()
+}
+
+// SetPhase sets the current phase of the item, e.g. "context window"
+func (p *Progress) SetPhase(phase string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.phase, p.strategy = phase, ""
+	p.resetTrials()
+	if !p.plain {
+		p.render()
+	}
+}
+
+// FinishItem records the duration of the current item; status (e.g. "ok",
+// "failed") is shown in plain mode
+func (p *Progress) FinishItem(status string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	duration := p.now().Sub(p.itemStart)
+	p.itemDurations.add(duration)
+	p.itemStart = time.Time{}
+	p.resetTrials()
+	p.phase, p.strategy = "", ""
+	if p.plain {
+		msg := fmt.Sprintf("%s%s in %s", p.prefix(), status, formatETA(duration))
+		if p.item < p.items {
+			msg += ", " + p.etaText()
+		}
+		p.writeLine(msg)
+		return
+	}
+	p.render()
+}
+
+// Finish clears the status line; call it before printing results
+func (p *Progress) Finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clearLine()
+}
+
+// LogProgress records the start of a trial
+func (p *Progress) LogProgress(current, total, tokens int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.trial, p.maxTrials, p.tokens = current, total, tokens
+	p.trialStart = p.now()
+	if !p.plain {
+		p.render()
+	}
+}
+
+// LogSuccess records a successful trial
+func (p *Progress) LogSuccess(trial, tokens int, duration time.Duration) {
+	p.finishTrial(trial, tokens, "ok")
+}
+
+// LogFailure records a failed trial
+func (p *Progress) LogFailure(trial, tokens int, reason string) {
+	p.finishTrial(trial, tokens, "failed")
+}
+
+// LogSearchStrategy shows the search strategy next to the phase
+func (p *Progress) LogSearchStrategy(strategy, reason string, details map[string]any) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.strategy = strategy
+	if !p.plain {
+		p.render()
+	}
+}
+
+// LogInfo is ignored; progress shows only the status
+func (p *Progress) LogInfo(message string) {}
+
+// LogAPIRequest is ignored; progress shows only the status
+func (p *Progress) LogAPIRequest(method, url string, tokens int, temperature float64) {}
+
+// LogAPIResponse is ignored; progress shows only the status
+func (p *Progress) LogAPIResponse(status, promptTokens, completionTokens int, duration time.Duration) {
+}
+
+// LogError is ignored; errors are reported by the command
+func (p *Progress) LogError(err error, context string) {}
+
+// LogCompletion is ignored; results are reported by the command
+func (p *Progress) LogCompletion(strategy string, finalEstimate, tolerance int) {}
+
+// finishTrial records the duration of the trial started by LogProgress
+func (p *Progress) finishTrial(trial, tokens int, outcome string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	duration := time.Duration(0)
+	if !p.trialStart.IsZero() {
+		duration = p.now().Sub(p.trialStart)
+		p.trialDurations.add(duration)
+	}
+	p.trialsDone++
+	p.trialStart = time.Time{}
+
+	if p.plain {
+		msg := fmt.Sprintf("%strial %d", p.prefix(), trial)
+		if p.maxTrials > 0 {
+			msg += fmt.Sprintf("/%d", p.maxTrials)
+		}
+		msg += fmt.Sprintf(" %s (%d tokens, %s), %s", outcome, tokens, duration.Round(100*time.Millisecond), p.etaText())
+		p.writeLine(msg)
+		return
+	}
+	p.render()
+}
+
+func (p *Progress) resetTrials() {
+	p.trial, p.maxTrials, p.tokens, p.trialsDone = 0, 0, 0, 0
+	p.trialStart = time.Time{}
+}
+
+// prefix returns "[2/5] gpt-4o context window: " for plain lines
+func (p *Progress) prefix() string {
+	var parts []string
+	if p.items > 1 {
+		parts = append(parts, fmt.Sprintf("[%d/%d]", p.item, p.items))
+	}
+	if p.label != "" {
+		parts = append(parts, p.label)
+	}
+	if p.phase != "" {
+		parts = append(parts, p.phase)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, " ") + ": "
+}
+
+// fraction returns the overall completion between 0 and 1
+func (p *Progress) fraction() float64 {
+	items := p.items
+	if items < 1 {
+		items = 1
+	}
+	done := float64(p.item - 1)
+	if p.itemStart.IsZero() {
+		// The current item has finished
+		done = float64(p.item)
+	}
+	if done < 0 {
+		done = 0
+	}
+	if p.maxTrials > 0 {
+		within := float64(p.trialsDone) / float64(p.maxTrials)
+		if within > 1 {
+			within = 1
+		}
+		done += within
+	}
+	fraction := done / float64(items)
+	if fraction > 1 {
+		fraction = 1
+	}
+	return fraction
+}
+
+// eta estimates the remaining time. Trials left in the current phase are
+// costed at the average trial duration; later items at the average item
+// duration, or at the trial budget when no item has finished yet.
+func (p *Progress) eta() (time.Duration, bool) {
+	trialAvg, hasTrials := p.trialDurations.average()
+	itemAvg, hasItems := p.itemDurations.average()
+
+	if p.itemStart.IsZero() && p.item > 0 && p.item >= p.items {
+		// Everything has finished
+		return 0, true
+	}
+
+	var eta time.Duration
+	known := false
+	if p.maxTrials > 0 && hasTrials {
+		remaining := p.maxTrials - p.trialsDone
+		if remaining < 0 {
+			remaining = 0
+		}
+		eta += time.Duration(remaining) * trialAvg
+		known = true
+	} else if !p.itemStart.IsZero() && hasItems {
+		// Item without trials: assume it takes as long as the others
+		if left := itemAvg - p.now().Sub(p.itemStart); left > 0 {
+			eta += left
+		}
+		known = true
+	}
+
+	if future := p.items - p.item; future > 0 {
+		switch {
+		case hasItems:
+			eta += time.Duration(future) * itemAvg
+		case hasTrials && p.maxTrials > 0:
+			eta += time.Duration(future*p.maxTrials) * trialAvg
+		default:
+			return 0, false
+		}
+		known = true
+	}
+	return eta, known
+}
+
+func (p *Progress) etaText() string {
+	eta, ok := p.eta()
+	if !ok {
+		return "ETA --"
+	}
+	return "ETA " + formatETA(eta)
+}
+
+// render redraws the status line
+func (p *Progress) render() {
+	var sb strings.Builder
+	fraction := p.fraction()
+	filled := int(fraction * progressBarWidth)
+	sb.WriteString("[" + strings.Repeat("#", filled) + strings.Repeat("-", progressBarWidth-filled) + "]")
+	sb.WriteString(fmt.Sprintf(" %3.0f%%", fraction*100))
+
+	if p.items > 1 {
+		sb.WriteString(fmt.Sprintf(" %d/%d", p.item, p.items))
+	}
+	if p.label != "" {
+		sb.WriteString(" " + p.label)
+	}
+	if p.phase != "" {
+		sb.WriteString(" · " + p.phase)
+	}
+	if p.strategy != "" {
+		sb.WriteString(" · " + p.strategy)
+	}
+	if p.trial > 0 {
+		sb.WriteString(fmt.Sprintf(" · trial %d", p.trial))
+		if p.maxTrials > 0 {
+			sb.WriteString(fmt.Sprintf("/%d", p.maxTrials))
+		}
+		if p.tokens > 0 {
+			sb.WriteString(fmt.Sprintf(" (%d tokens)", p.tokens))
+		}
+	}
+	sb.WriteString(" · " + p.etaText())
+
+	line := redact.String(sb.String())
+	p.clearLine()
+	fmt.Fprint(p.w, "\r"+line)
+	p.lastLineLength = len([]rune(line))
+}
+
+// writeLine writes a plain progress line
+func (p *Progress) writeLine(msg string) {
+	fmt.Fprintln(p.w, redact.String(msg))
+}
+
+// clearLine erases the status line
+func (p *Progress) clearLine() {
+	if p.lastLineLength > 0 {
+		fmt.Fprint(p.w, "\r"+strings.Repeat(" ", p.lastLineLength)+"\r")
+		p.lastLineLength = 0
+	}
+}
+
+// formatETA formats a duration as 42s, 3m05s or 1h02m
+func formatETA(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// movingAverage averages the last progressWindow durations
+type movingAverage struct {
+	values []time.Duration
+}
+
+func (m *movingAverage) add(d time.Duration) {
+	m.values = append(m.values, d)
+	if len(m.values) > progressWindow {
+		m.values = m.values[len(m.values)-progressWindow:]
+	}
+}
+
+func (m *movingAverage) average() (time.Duration, bool) {
+	if len(m.values) == 0 {
+		return 0, false
+	}
+	var total time.Duration
+	for _, v := range m.values {
+		total += v
+	}
+	return total / time.Duration(len(m.values)), true
+}
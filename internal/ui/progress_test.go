@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armaniacs/llm-info/internal/probe"
+)
+
+var _ probe.VerboseLogger = (*Progress)(nil)
+
+// fakeClock advances only when told to
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestProgress(plain bool) (*Progress, *bytes.Buffer, *fakeClock) {
+	var buf bytes.Buffer
+	clock := &fakeClock{t: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)}
+	p := NewProgress(&buf, plain)
+	p.now = clock.now
+	return p, &buf, clock
+}
+
+func TestProgress_PlainTrials(t *testing.T) {
+	p, buf, clock := newTestProgress(true)
+
+	p.StartItem("gpt-4o", 1, 2)
+	p.SetPhase("context window")
+	for trial := 1; trial <= 2; trial++ {
+		p.LogProgress(trial, 10, trial*1000)
+		clock.advance(2 * time.Second)
+		p.LogSuccess(trial, trial*1000, 2*time.Second)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	if lines[0] != "[1/2] gpt-4o: started" {
+		t.Errorf("line 0 = %q", lines[0])
+	}
+	// 8 trials left at 2s, plus the second model at 10 trials of 2s
+	want := "[1/2] gpt-4o context window: trial 2/10 ok (2000 tokens, 2s), ETA 36s"
+	if lines[2] != want {
+		t.Errorf("line 2 = %q, want %q", lines[2], want)
+	}
+}
+
+func TestProgress_ItemETA(t *testing.T) {
+	p, buf, clock := newTestProgress(true)
+
+	p.StartItem("production", 1, 3)
+	clock.advance(4 * time.Second)
+	p.FinishItem("ok")
+	p.StartItem("staging", 2, 3)
+	clock.advance(2 * time.Second)
+	p.FinishItem("failed")
+
+	out := buf.String()
+	// The average of 4s and 2s for the one gateway left
+	if !strings.Contains(out, "[2/3] staging: failed in 2s, ETA 3s") {
+		t.Errorf("output =\n%s", out)
+	}
+	if !strings.Contains(out, "[1/3] production: ok in 4s, ETA 8s") {
+		t.Errorf("output =\n%s", out)
+	}
+}
+
+func TestProgress_MovingAverage(t *testing.T) {
+	var m movingAverage
+	if _, ok := m.average(); ok {
+		t.Error("empty average should be unknown")
+	}
+	for i := 1; i <= 10; i++ {
+		m.add(time.Duration(i) * time.Second)
+	}
+	// Only the last five (6s..10s) are averaged
+	if avg, _ := m.average(); avg != 8*time.Second {
+		t.Errorf("average() = %v, want 8s", avg)
+	}
+}
+
+func TestProgress_Interactive(t *testing.T) {
+	p, buf, clock := newTestProgress(false)
+
+	p.StartItem("gpt-4o", 1, 1)
+	p.SetPhase("max output")
+	p.LogSearchStrategy("Binary Search", "Refining bounds", nil)
+	p.LogProgress(1, 4, 8192)
+	clock.advance(time.Second)
+	p.LogFailure(1, 8192, "too long")
+	p.Finish()
+
+	out := buf.String()
+	if strings.Contains(out, "\n") {
+		t.Errorf("interactive progress should not write newlines: %q", out)
+	}
+	if !strings.Contains(out, "[#####---------------]  25% gpt-4o · max output · Binary Search · trial 1/4 (8192 tokens) · ETA 3s") {
+		t.Errorf("output = %q", out)
+	}
+	if !strings.HasSuffix(out, "\r") {
+		t.Errorf("Finish() should clear the status line: %q", out)
+	}
+}
+
+func TestProgress_Nil(t *testing.T) {
+	var p *Progress
+	p.StartItem("gpt-4o", 1, 1)
+	p.LogProgress(1, 10, 1000)
+	p.LogSuccess(1, 1000, time.Second)
+	p.FinishItem("ok")
+	p.Finish()
+}
+
+func TestFormatETA(t *testing.T) {
+	tests := map[time.Duration]string{
+		42 * time.Second:               "42s",
+		185 * time.Second:              "3m05s",
+		time.Hour + 2*time.Minute + 30: "1h02m",
+	}
+	for d, want := range tests {
+		if got := formatETA(d); got != want {
+			t.Errorf("formatETA(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -16,6 +16,11 @@ const (
 	SortByMaxTokens
 	SortByInputCost
 	SortByMode
+	SortByMeasuredContext
+	SortByMeasuredMaxOutput
+	SortByMeasuredAt
+	SortByLatencyP50
+	SortByPinned
 )
 
 // SortOrder はソート順序を表す
@@ -30,6 +35,7 @@ const (
 type SortCriteria struct {
 	Field SortField
 	Order SortOrder
+	Then  *SortCriteria // 値が同じときに使う次のソート条件（"pinned,-max_tokens"の2つ目以降）
 }
 
 // Sort はソート条件に基づいてモデルをソートする
@@ -45,20 +51,22 @@ func Sort(models []model.Model, criteria *SortCriteria) {
 }
 
 // compare は2つのモデルを比較する
+// 値が同じ場合は次のソート条件（Then）で比較する
+// latency_p50は0として扱うと最も速いモデルより前に並ぶため、探索結果がないモデルは昇順・降順とも最後にする
 func compare(a, b model.Model, criteria *SortCriteria) bool {
-	var result bool
+	if criteria.Field == SortByLatencyP50 {
+		_, aOK := a.LatencyP50()
+		_, bOK := b.LatencyP50()
+		if aOK != bOK {
+			return aOK
+		}
+	}
 
-	switch criteria.Field {
-	case SortByName:
-		result = strings.ToLower(a.Name) < strings.ToLower(b.Name)
-	case SortByMaxTokens:
-		result = a.MaxTokens < b.MaxTokens
-	case SortByInputCost:
-		result = a.InputCost < b.InputCost
-	case SortByMode:
-		result = strings.ToLower(a.Mode) < strings.ToLower(b.Mode)
+	if criteria.Then != nil && !less(a, b, criteria.Field) && !less(b, a, criteria.Field) {
+		return compare(a, b, criteria.Then)
 	}
 
+	result := less(a, b, criteria.Field)
 	if criteria.Order == Descending {
 		result = !result
 	}
@@ -66,12 +74,65 @@ func compare(a, b model.Model, criteria *SortCriteria) bool {
 	return result
 }
 
+// less はフィールドの値でaがbより前（昇順）かを返す
+func less(a, b model.Model, field SortField) bool {
+	switch field {
+	case SortByName:
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	case SortByMaxTokens:
+		return a.MaxTokens < b.MaxTokens
+	case SortByInputCost:
+		return a.InputCost < b.InputCost
+	case SortByMode:
+		return strings.ToLower(a.Mode) < strings.ToLower(b.Mode)
+	case SortByMeasuredContext:
+		return measuredValue(a, model.MetaMeasuredContext) < measuredValue(b, model.MetaMeasuredContext)
+	case SortByMeasuredMaxOutput:
+		return measuredValue(a, model.MetaMeasuredMaxOutput) < measuredValue(b, model.MetaMeasuredMaxOutput)
+	case SortByMeasuredAt:
+		aAt, _ := a.MeasuredAt()
+		bAt, _ := b.MeasuredAt()
+		return aAt.Before(bAt)
+	case SortByLatencyP50:
+		// 探索結果がないモデル同士は同じ値として扱う（並び順はcompareで最後にする）
+		aLatency, _ := a.LatencyP50()
+		bLatency, _ := b.LatencyP50()
+		return aLatency < bLatency
+	case SortByPinned:
+		// 昇順でピン留めしたモデルを先にする
+		return a.IsPinned() && !b.IsPinned()
+	}
+	return false
+}
+
+// measuredValue は探索結果の値を返す。探索結果がないモデルは0として扱う
+func measuredValue(m model.Model, key string) float64 {
+	value, _ := m.Metadata[key].(float64)
+	return value
+}
+
 // ParseSortString はソート文字列を解析してSortCriteriaを返す
+// "pinned,-max_tokens"のようにカンマ区切りで複数指定すると、値が同じモデルを次の条件で並べる
 func ParseSortString(sortStr string) (*SortCriteria, error) {
 	if sortStr == "" {
 		return &SortCriteria{Field: SortByName, Order: Ascending}, nil
 	}
 
+	if first, rest, ok := strings.Cut(sortStr, ","); ok {
+		first, rest = strings.TrimSpace(first), strings.TrimSpace(rest)
+		if first == "" || rest == "" {
+			return nil, fmt.Errorf("empty sort field in: %s", sortStr)
+		}
+		criteria, err := ParseSortString(first)
+		if err != nil {
+			return nil, err
+		}
+		if criteria.Then, err = ParseSortString(rest); err != nil {
+			return nil, err
+		}
+		return criteria, nil
+	}
+
 	// 降順の場合はプレフィックスをチェック
 	order := Ascending
 	if strings.HasPrefix(sortStr, "-") {
@@ -90,6 +151,16 @@ func ParseSortString(sortStr string) (*SortCriteria, error) {
 		field = SortByInputCost
 	case "mode":
 		field = SortByMode
+	case "measured_context":
+		field = SortByMeasuredContext
+	case "measured_max_output":
+		field = SortByMeasuredMaxOutput
+	case "measured_at", "probed_at":
+		field = SortByMeasuredAt
+	case "latency_p50":
+		field = SortByLatencyP50
+	case "pinned":
+		field = SortByPinned
 	default:
 		return nil, fmt.Errorf("unknown sort field: %s", sortStr)
 	}
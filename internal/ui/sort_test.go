@@ -2,6 +2,7 @@ package ui
 
 import (
 	"testing"
+	"time"
 
 	"github.com/armaniacs/llm-info/internal/model"
 )
@@ -377,3 +378,86 @@ func TestCompare(t *testing.T) {
 		})
 	}
 }
+
+func TestSort_Measured(t *testing.T) {
+	now := time.Now()
+	models := []model.Model{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	model.ApplyMeasurements(models, map[string]model.Measurement{
+		"a": {ContextWindow: 32000, MaxOutput: 8192, MeasuredAt: now.Add(-time.Hour), LatencyP50: 900 * time.Millisecond},
+		"b": {ContextWindow: 128000, MaxOutput: 4096, MeasuredAt: now.Add(-48 * time.Hour), LatencyP50: 300 * time.Millisecond},
+	})
+
+	tests := []struct {
+		sortStr string
+		want    string
+	}{
+		{"-measured_context", "b,a,c"},
+		{"measured_max_output", "c,b,a"},
+		{"measured_at", "c,b,a"},
+		{"-measured_at", "a,b,c"},
+		{"-probed_at", "a,b,c"},
+		{"latency_p50", "b,a,c"},
+		{"-latency_p50", "a,b,c"},
+	}
+	for _, tt := range tests {
+		criteria, err := ParseSortString(tt.sortStr)
+		if err != nil {
+			t.Fatalf("ParseSortString(%q) error = %v", tt.sortStr, err)
+		}
+		sorted := append([]model.Model(nil), models...)
+		Sort(sorted, criteria)
+		got := ""
+		for i, m := range sorted {
+			if i > 0 {
+				got += ","
+			}
+			got += m.Name
+		}
+		if got != tt.want {
+			t.Errorf("Sort(%s) = %s, want %s", tt.sortStr, got, tt.want)
+		}
+	}
+}
+
+func TestSort_MultipleFields(t *testing.T) {
+	models := []model.Model{
+		{Name: "a", MaxTokens: 8000},
+		{Name: "b", MaxTokens: 128000},
+		{Name: "c", MaxTokens: 32000},
+		{Name: "d", MaxTokens: 200000},
+	}
+	model.ApplyPins(models, []string{"a", "c"})
+
+	tests := []struct {
+		sortStr string
+		want    string
+	}{
+		{"pinned,-max_tokens", "c,a,d,b"},
+		{"pinned, max_tokens", "a,c,b,d"},
+		{"-pinned,name", "b,d,a,c"},
+	}
+	for _, tt := range tests {
+		criteria, err := ParseSortString(tt.sortStr)
+		if err != nil {
+			t.Fatalf("ParseSortString(%q) error = %v", tt.sortStr, err)
+		}
+		sorted := append([]model.Model(nil), models...)
+		Sort(sorted, criteria)
+		got := ""
+		for i, m := range sorted {
+			if i > 0 {
+				got += ","
+			}
+			got += m.Name
+		}
+		if got != tt.want {
+			t.Errorf("Sort(%s) = %s, want %s", tt.sortStr, got, tt.want)
+		}
+	}
+
+	for _, sortStr := range []string{"pinned,", ",name", "pinned,unknown"} {
+		if _, err := ParseSortString(sortStr); err == nil {
+			t.Errorf("ParseSortString(%q) error = nil, want error", sortStr)
+		}
+	}
+}
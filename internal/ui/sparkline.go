@@ -0,0 +1,35 @@
+package ui
+
+// sparkBlocks はスパークラインの高さごとの文字（低い順）
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline は値の推移を1行のブロック文字で表す
+// 値がない位置（okがfalse）は空白にし、すべての値が同じ場合は中間の高さで表す
+func Sparkline(values []int, ok []bool) string {
+	min, max, seen := 0, 0, false
+	for i, value := range values {
+		if !ok[i] {
+			continue
+		}
+		if !seen || value < min {
+			min = value
+		}
+		if !seen || value > max {
+			max = value
+		}
+		seen = true
+	}
+
+	line := make([]rune, len(values))
+	for i, value := range values {
+		switch {
+		case !ok[i]:
+			line[i] = ' '
+		case max == min:
+			line[i] = sparkBlocks[len(sparkBlocks)/2-1]
+		default:
+			line[i] = sparkBlocks[(value-min)*(len(sparkBlocks)-1)/(max-min)]
+		}
+	}
+	return string(line)
+}
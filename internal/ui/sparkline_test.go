@@ -0,0 +1,25 @@
+package ui
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		ok     []bool
+		want   string
+	}{
+		{"rising", []int{0, 50, 100}, []bool{true, true, true}, "▁▄█"},
+		{"drop", []int{128000, 128000, 120000}, []bool{true, true, true}, "██▁"},
+		{"flat", []int{8192, 8192}, []bool{true, true}, "▄▄"},
+		{"failed run", []int{128000, 0, 120000}, []bool{true, false, true}, "█ ▁"},
+		{"empty", nil, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values, tt.ok); got != tt.want {
+				t.Errorf("Sparkline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,12 +5,18 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/armaniacs/llm-info/internal/api"
 	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/internal/numfmt"
+	"github.com/armaniacs/llm-info/internal/parallel"
+	"github.com/armaniacs/llm-info/pkg/config"
 )
 
 // TableRenderer はテーブル表示機能を提供する
 type TableRenderer struct {
 	columnManager *ColumnManager
+	numberFormat  numfmt.Format
+	tableStyle    string
 }
 
 // NewTableRenderer は新しいテーブルレンダラーを作成する
@@ -27,10 +33,20 @@ func (tr *TableRenderer) Render(models []model.Model, options *RenderOptions) er
 		return nil
 	}
 
-	if options != nil && options.Columns != "" {
+	if options != nil && options.Columns == AllColumns {
+		tr.columnManager.ShowAllColumns(models)
+	} else if options != nil && options.Columns != "" {
 		if err := tr.columnManager.ParseColumnsString(options.Columns); err != nil {
 			return fmt.Errorf("failed to parse columns: %w", err)
 		}
+	} else if hasVariants(models) {
+		// --dedupeでまとめたモデルがある場合は元のIDも表示する
+		tr.columnManager.SetColumnVisibility("variants", true)
+	}
+
+	if options != nil {
+		tr.numberFormat = options.NumberFormat
+		tr.tableStyle = options.TableStyle
 	}
 
 	// 表示カラムの取得
@@ -39,7 +55,12 @@ func (tr *TableRenderer) Render(models []model.Model, options *RenderOptions) er
 	// ヘッダーの構成
 	var headers []string
 	for _, col := range visibleColumns {
-		headers = append(headers, col.Header)
+		header := col.Header
+		if isCostColumn(col.Name) && tr.numberFormat.Humanized() {
+			// 100万トークンあたりで表示していることを示す
+			header += " /1M"
+		}
+		headers = append(headers, header)
 	}
 
 	// 列幅を計算
@@ -48,41 +69,129 @@ func (tr *TableRenderer) Render(models []model.Model, options *RenderOptions) er
 		colWidths[i] = len(header)
 	}
 
-	// データ行の準備
-	var rows [][]string
-	for _, model := range models {
-		var row []string
-		for _, col := range visibleColumns {
-			value, err := tr.columnManager.GetColumnValue(model, col.Name)
-			if err != nil {
-				return err
+	// データ行の準備（行ごとの書式化は並列に行い、並び順は入力順を保つ）
+	rows := make([][]string, len(models))
+	errs := make([]error, len(models))
+	parallel.For(len(models), func(start, end int) {
+		for i := start; i < end; i++ {
+			rows[i], errs[i] = tr.formatRow(models[i], visibleColumns)
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	// ワイルドカードのエントリと、欠落・不正なフィールドがあったモデルは名前に印を付ける
+	markNames(models, visibleColumns, rows)
+
+	// 列幅の更新
+	for _, row := range rows {
+		for i, value := range row {
+			if len(value) > colWidths[i] {
+				colWidths[i] = len(value)
 			}
+		}
+	}
 
-			var formattedValue string
-			switch v := value.(type) {
-			case string:
-				formattedValue = v
-			case int:
-				formattedValue = fmt.Sprintf(col.Format, v)
-			case float64:
-				formattedValue = fmt.Sprintf(col.Format, v)
-			default:
-				formattedValue = fmt.Sprintf("%v", v)
+	// テーブルの表示
+	printTable(tr.tableStyle, headers, rows, colWidths)
+	printIssueNotes(models)
+	return nil
+}
+
+// 名前の列に付ける印
+const (
+	wildcardMarker = " (wildcard)" // ワイルドカードのルーティングエントリ
+	issueMarker    = " *"          // 欠落・不正なフィールドがあった行
+)
+
+// markNames はワイルドカードのエントリと、欠落・不正なフィールドがあったモデルの名前の列に印を付ける
+func markNames(models []model.Model, columns []Column, rows [][]string) {
+	for col, column := range columns {
+		if column.Name != "name" {
+			continue
+		}
+		for i, m := range models {
+			if model.IsWildcard(m.Name) {
+				rows[i][col] += wildcardMarker
+			}
+			if len(m.Issues) > 0 {
+				rows[i][col] += issueMarker
 			}
+		}
+	}
+}
+
+// printIssueNotes は欠落・不正なフィールドの内容をモデルごとに表示する
+func printIssueNotes(models []model.Model) {
+	count := model.CountIssues(models)
+	if count == 0 {
+		return
+	}
+	fmt.Printf("\n* %d model(s) had missing or malformed fields in the gateway response; the values shown may be incomplete:\n", count)
+	for _, m := range models {
+		if len(m.Issues) > 0 {
+			fmt.Printf("  %s: %s\n", m.Name, api.FormatIssues(m.Issues))
+		}
+	}
+	fmt.Println("  Use --strict-parse to fail instead.")
+}
 
-			row = append(row, formattedValue)
+// formatRow は1つのモデルを表示用の文字列の行に変換する
+func (tr *TableRenderer) formatRow(model model.Model, columns []Column) ([]string, error) {
+	row := make([]string, 0, len(columns))
+	for _, col := range columns {
+		value, err := tr.columnManager.GetColumnValue(model, col.Name)
+		if err != nil {
+			return nil, err
+		}
 
-			// 列幅の更新
-			if len(formattedValue) > colWidths[len(row)-1] {
-				colWidths[len(row)-1] = len(formattedValue)
+		var formattedValue string
+		switch v := value.(type) {
+		case string:
+			formattedValue = v
+			if _, measured := measuredColumnDefs[col.Name]; measured && col.Name != "measured_at" && col.Name != "latency_p50" && tr.numberFormat.Humanized() {
+				// 探索結果のトークン数はメタデータの文字列で受け取る
+				if n, err := strconv.Atoi(v); err == nil {
+					formattedValue = tr.numberFormat.Tokens(n)
+				}
 			}
+		case int:
+			if col.Name == "max_tokens" {
+				formattedValue = tr.numberFormat.Tokens(v)
+			} else {
+				formattedValue = fmt.Sprintf(col.Format, v)
+			}
+		case float64:
+			if isCostColumn(col.Name) {
+				formattedValue = tr.numberFormat.Cost(v)
+			} else {
+				formattedValue = fmt.Sprintf(col.Format, v)
+			}
+		default:
+			formattedValue = fmt.Sprintf("%v", v)
 		}
-		rows = append(rows, row)
+
+		row = append(row, formattedValue)
 	}
+	return row, nil
+}
 
-	// テーブルの表示
-	printTable(headers, rows, colWidths)
-	return nil
+// isCostColumn は1トークンあたりの料金を表示するカラムかを返す
+func isCostColumn(name string) bool {
+	return name == "input_cost" || name == "cached_input_cost"
+}
+
+// hasVariants はまとめられたモデルが含まれるかを返す
+func hasVariants(models []model.Model) bool {
+	for _, m := range models {
+		if len(m.Variants) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // SetColumnVisibility はカラムの表示/非表示を設定する
@@ -92,9 +201,12 @@ func (tr *TableRenderer) SetColumnVisibility(columnName string, visible bool) er
 
 // RenderOptions は表示オプションを表す
 type RenderOptions struct {
-	Columns string // 表示するカラム（カンマ区切り）
-	Filter  string // フィルタ条件
-	Sort    string // ソート条件
+	Columns      string        // 表示するカラム（カンマ区切り）
+	Filter       string        // フィルタ条件
+	Sort         string        // ソート条件
+	NumberFormat numfmt.Format // トークン数とコストの表記（ゼロ値は従来の表記）
+	TableStyle   string        // テーブルの罫線の種類（空は従来の表示）
+	Failover     *FailoverInfo // 別のゲートウェイが応答した場合（JSONに含める）
 }
 
 // RenderTable はモデル情報をテーブル形式で表示します（互換性のための関数）
@@ -178,7 +290,7 @@ func RenderTable(models []model.Model) {
 	}
 
 	// テーブルを表示
-	printTable(headers, rows, colWidths)
+	printTable(config.TableStyleDefault, headers, rows, colWidths)
 }
 
 // RenderTableWithOptions はオプション付きでモデル情報をテーブル形式で表示します
@@ -187,21 +299,43 @@ func RenderTableWithOptions(models []model.Model, options *RenderOptions) error
 	return renderer.Render(models, options)
 }
 
-// printTable はテーブルを表示します
-func printTable(headers []string, rows [][]string, colWidths []int) {
-	// ヘッダー行を表示
-	printRow(headers, colWidths)
+// printTable はテーブルを指定した罫線の種類で表示します
+// 罫線には罫線素片を使わず、ASCII文字のみを使います
+func printTable(style string, headers []string, rows [][]string, colWidths []int) {
+	switch style {
+	case config.TableStyleGrid:
+		border := gridBorder(colWidths)
+		fmt.Println(border)
+		printGridRow(headers, colWidths)
+		fmt.Println(border)
+		for _, row := range rows {
+			printGridRow(row, colWidths)
+		}
+		fmt.Println(border)
+	case config.TableStylePlain, config.TableStyleCompact:
+		gap := "  "
+		if style == config.TableStyleCompact {
+			gap = " "
+		}
+		printAlignedRow(headers, colWidths, gap)
+		for _, row := range rows {
+			printAlignedRow(row, colWidths, gap)
+		}
+	default:
+		// ヘッダー行を表示
+		printRow(headers, colWidths)
 
-	// 区切り線を表示
-	separators := make([]string, len(colWidths))
-	for i, width := range colWidths {
-		separators[i] = strings.Repeat("-", width)
-	}
-	printRow(separators, colWidths)
+		// 区切り線を表示
+		separators := make([]string, len(colWidths))
+		for i, width := range colWidths {
+			separators[i] = strings.Repeat("-", width)
+		}
+		printRow(separators, colWidths)
 
-	// データ行を表示
-	for _, row := range rows {
-		printRow(row, colWidths)
+		// データ行を表示
+		for _, row := range rows {
+			printRow(row, colWidths)
+		}
 	}
 }
 
@@ -215,3 +349,36 @@ func printRow(row []string, colWidths []int) {
 	}
 	fmt.Println()
 }
+
+// printAlignedRow は列の間をgapで区切って行を表示します（行末の空白は出力しません）
+func printAlignedRow(row []string, colWidths []int, gap string) {
+	var b strings.Builder
+	for i, cell := range row {
+		if i > 0 {
+			b.WriteString(gap)
+		}
+		fmt.Fprintf(&b, "%-*s", colWidths[i], cell)
+	}
+	fmt.Println(strings.TrimRight(b.String(), " "))
+}
+
+// gridBorder はgrid表示の罫線（+------+----+）を返します
+func gridBorder(colWidths []int) string {
+	var b strings.Builder
+	b.WriteString("+")
+	for _, width := range colWidths {
+		b.WriteString(strings.Repeat("-", width+2))
+		b.WriteString("+")
+	}
+	return b.String()
+}
+
+// printGridRow はgrid表示の行（| a | b |）を表示します
+func printGridRow(row []string, colWidths []int) {
+	var b strings.Builder
+	b.WriteString("|")
+	for i, cell := range row {
+		fmt.Fprintf(&b, " %-*s |", colWidths[i], cell)
+	}
+	fmt.Println(b.String())
+}
@@ -33,7 +33,7 @@ func (tf *TableFormatter) FormatIntegratedResult(model string, contextResult *pr
 
 	if contextResult != nil {
 		sb.WriteString(fmt.Sprintf("%-22s %s tokens\n", "Context Window:", formatNumber(contextResult.MaxContextTokens)))
-		sb.WriteString(fmt.Sprintf("%-22s %s\n", "Context Confidence:", contextResult.MethodConfidence))
+		sb.WriteString(fmt.Sprintf("%-22s %s\n", "Context Confidence:", contextResult.Confidence))
 	} else {
 		sb.WriteString(fmt.Sprintf("%-22s %s\n", "Context Window:", "Failed"))
 		sb.WriteString(fmt.Sprintf("%-22s %s\n", "Context Confidence:", "-"))
@@ -41,7 +41,7 @@ func (tf *TableFormatter) FormatIntegratedResult(model string, contextResult *pr
 
 	if outputResult != nil {
 		sb.WriteString(fmt.Sprintf("%-22s %s tokens\n", "Max Output Tokens:", formatNumber(outputResult.MaxOutputTokens)))
-		sb.WriteString(fmt.Sprintf("%-22s %s\n", "Output Confidence:", outputResult.MethodConfidence))
+		sb.WriteString(fmt.Sprintf("%-22s %s\n", "Output Confidence:", outputResult.Confidence))
 	} else {
 		sb.WriteString(fmt.Sprintf("%-22s %s\n", "Max Output Tokens:", "Failed"))
 		sb.WriteString(fmt.Sprintf("%-22s %s\n", "Output Confidence:", "-"))
@@ -83,7 +83,7 @@ func (tf *TableFormatter) FormatContextWindowResult(result *probe.ContextWindowR
 	// データ行
 	sb.WriteString(fmt.Sprintf("%-22s %s\n", "Model:", result.Model))
 	sb.WriteString(fmt.Sprintf("%-22s %s tokens\n", "Estimated Context:", formatNumber(result.MaxContextTokens)))
-	sb.WriteString(fmt.Sprintf("%-22s %s\n", "Method Confidence:", result.MethodConfidence))
+	sb.WriteString(fmt.Sprintf("%-22s %s\n", "Method Confidence:", result.Confidence))
 	sb.WriteString(fmt.Sprintf("%-22s %d\n", "Trials:", result.Trials))
 	sb.WriteString(fmt.Sprintf("%-22s %s\n", "Duration:", formatDuration(result.Duration)))
 
@@ -91,6 +91,14 @@ func (tf *TableFormatter) FormatContextWindowResult(result *probe.ContextWindowR
 		sb.WriteString(fmt.Sprintf("%-22s %s tokens\n", "Max Input at Success:", formatNumber(result.MaxInputAtSuccess)))
 	}
 
+	// 複数ターンの探索では単発の探索結果と比較する
+	if result.TurnTokens > 0 {
+		sb.WriteString(fmt.Sprintf("%-22s multi-turn (%d turns of %s tokens)\n", "Mode:", result.Turns(), formatNumber(result.TurnTokens)))
+		if result.SingleShotLimit > 0 {
+			sb.WriteString(fmt.Sprintf("%-22s %s tokens (multi-turn %+d)\n", "Single-Shot Limit:", formatNumber(result.SingleShotLimit), result.MaxContextTokens-result.SingleShotLimit))
+		}
+	}
+
 	// Needle test information (if available)
 	if result.NeedleComprehension || len(result.NeedleTests) > 0 {
 		sb.WriteString(fmt.Sprintf("%-22s %s\n", "Needle Position:", result.NeedlePosition))
@@ -138,12 +146,21 @@ func (tf *TableFormatter) FormatMaxOutputResult(result *probe.MaxOutputResult) s
 	sb.WriteString(fmt.Sprintf("%-22s %s\n", "Model:", result.Model))
 	sb.WriteString(fmt.Sprintf("%-22s %s\n", "Max Output Tokens:", formatNumber(result.MaxOutputTokens)))
 	sb.WriteString(fmt.Sprintf("%-22s %s\n", "Evidence:", result.Evidence))
+	sb.WriteString(fmt.Sprintf("%-22s %s\n", "Method Confidence:", result.Confidence))
 	sb.WriteString(fmt.Sprintf("%-22s %d\n", "Trials:", result.Trials))
 	sb.WriteString(fmt.Sprintf("%-22s %s\n", "Duration:", formatDuration(result.Duration)))
 
 	if result.MaxSuccessfullyGenerated > 0 {
 		sb.WriteString(fmt.Sprintf("%-22s %s tokens\n", "Max Successfully Gen:", formatNumber(result.MaxSuccessfullyGenerated)))
 	}
+	if len(result.UsageDiscrepancies) == 0 {
+		sb.WriteString(fmt.Sprintf("%-22s %s\n", "Usage Check:", "consistent"))
+	} else {
+		sb.WriteString(fmt.Sprintf("%-22s %d discrepancies (usage may be misreported)\n", "Usage Check:", len(result.UsageDiscrepancies)))
+		for _, d := range result.UsageDiscrepancies {
+			sb.WriteString(fmt.Sprintf("  - %s\n", d.Detail))
+		}
+	}
 
 	sb.WriteString("\n")
 
@@ -160,6 +177,54 @@ func (tf *TableFormatter) FormatMaxOutputResult(result *probe.MaxOutputResult) s
 	return sb.String()
 }
 
+// FormatMaxInputResult は最大入力トークン探索結果を整形
+func (tf *TableFormatter) FormatMaxInputResult(result *probe.MaxInputResult) string {
+	var sb strings.Builder
+
+	// ヘッダー
+	sb.WriteString("Max Input Tokens Probe Results\n")
+	sb.WriteString(strings.Repeat("=", 30) + "\n")
+
+	// データ行
+	sb.WriteString(fmt.Sprintf("%-22s %s\n", "Model:", result.Model))
+	sb.WriteString(fmt.Sprintf("%-22s %s tokens (max_tokens=1)\n", "Max Input Tokens:", formatNumber(result.MaxInputTokens)))
+	switch {
+	case result.CombinedLimit == 0:
+		sb.WriteString(fmt.Sprintf("%-22s %s\n", "Combined Limit:", "unknown"))
+	case result.CombinedSource == "lower_bound":
+		sb.WriteString(fmt.Sprintf("%-22s >= %s tokens (max_tokens=%s)\n", "Combined Limit:", formatNumber(result.CombinedLimit), formatNumber(result.OutputReserve)))
+	default:
+		sb.WriteString(fmt.Sprintf("%-22s %s tokens (max_tokens=%s)\n", "Combined Limit:", formatNumber(result.CombinedLimit), formatNumber(result.OutputReserve)))
+	}
+	if result.CombinedLimit > 0 {
+		enforcement := "combined only"
+		if result.Independent() {
+			enforcement = "input limited separately"
+		}
+		sb.WriteString(fmt.Sprintf("%-22s %s\n", "Enforcement:", enforcement))
+	}
+	sb.WriteString(fmt.Sprintf("%-22s %s\n", "Method Confidence:", result.Confidence))
+	sb.WriteString(fmt.Sprintf("%-22s %d\n", "Trials:", result.Trials))
+	sb.WriteString(fmt.Sprintf("%-22s %s\n", "Duration:", formatDuration(result.Duration)))
+
+	sb.WriteString("\n")
+
+	// ステータス
+	if result.Success {
+		sb.WriteString("Status: ✓ Success\n")
+		if result.ErrorMessage != "" {
+			sb.WriteString(fmt.Sprintf("Note:   combined limit probe failed: %s\n", result.ErrorMessage))
+		}
+	} else {
+		sb.WriteString("Status: ✗ Failed\n")
+		if result.ErrorMessage != "" {
+			sb.WriteString(fmt.Sprintf("Error:  %s\n", result.ErrorMessage))
+		}
+	}
+
+	return sb.String()
+}
+
 // formatNumber は数値を3桁区切りで整形
 func formatNumber(n int) string {
 	if n == 0 {
@@ -201,9 +266,9 @@ func (tf *TableFormatter) FormatVerboseHistory(trials []probe.TrialInfo) string
 	var sb strings.Builder
 
 	sb.WriteString("\nSearch History:\n")
-	sb.WriteString(strings.Repeat("-", 60) + "\n")
-	sb.WriteString(fmt.Sprintf("%-8s %-15s %-12s %s\n", "Trial", "Tokens", "Result", "Message"))
-	sb.WriteString(strings.Repeat("-", 60) + "\n")
+	sb.WriteString(strings.Repeat("-", 70) + "\n")
+	sb.WriteString(fmt.Sprintf("%-8s %-15s %-8s %-10s %s\n", "Trial", "Tokens", "Result", "Latency", "Message"))
+	sb.WriteString(strings.Repeat("-", 70) + "\n")
 
 	for i, trial := range trials {
 		status := "✓"
@@ -216,13 +281,38 @@ func (tf *TableFormatter) FormatVerboseHistory(trials []probe.TrialInfo) string
 			msg = msg[:27] + "..."
 		}
 
-		sb.WriteString(fmt.Sprintf("%-8d %-15s %-12s %s\n",
+		latency := "-"
+		if trial.Latency > 0 {
+			latency = formatDuration(trial.Latency)
+		}
+
+		sb.WriteString(fmt.Sprintf("%-8d %-15s %-8s %-10s %s\n",
 			i+1,
 			formatNumber(trial.TokenCount),
 			status,
+			latency,
 			msg,
 		))
 	}
 
 	return sb.String()
-}
\ No newline at end of file
+}
+
+// FormatConfidenceEvidence は信頼度スコアと根拠リストを整形（verbose用）
+func (tf *TableFormatter) FormatConfidenceEvidence(confidence probe.Confidence) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("\nConfidence: %s\n", confidence))
+	sb.WriteString(strings.Repeat("-", 60) + "\n")
+
+	if len(confidence.Evidence) == 0 {
+		sb.WriteString("  (no evidence collected)\n")
+		return sb.String()
+	}
+
+	for _, evidence := range confidence.Evidence {
+		sb.WriteString(fmt.Sprintf("  - %s\n", evidence))
+	}
+
+	return sb.String()
+}
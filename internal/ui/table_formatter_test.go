@@ -14,7 +14,7 @@ func TestTableFormatter_FormatContextWindowResult(t *testing.T) {
 	result := &probe.ContextWindowResult{
 		Model:              "GLM-4.6",
 		MaxContextTokens:   127000,
-		MethodConfidence:   "high",
+		Confidence:         probe.Confidence{Score: 0.9},
 		Trials:             12,
 		Duration:           45*time.Second + 300*time.Millisecond,
 		MaxInputAtSuccess:  126800,
@@ -40,6 +40,9 @@ func TestTableFormatter_FormatContextWindowResult(t *testing.T) {
 	if !strings.Contains(output, "126,800") {
 		t.Error("Output should contain max input tokens")
 	}
+	if !strings.Contains(output, "0.90 (high)") {
+		t.Error("Output should contain confidence score and level")
+	}
 }
 
 func TestTableFormatter_FormatContextWindowResult_Error(t *testing.T) {
@@ -48,7 +51,7 @@ func TestTableFormatter_FormatContextWindowResult_Error(t *testing.T) {
 	result := &probe.ContextWindowResult{
 		Model:              "invalid-model",
 		MaxContextTokens:   0,
-		MethodConfidence:   "low",
+		Confidence:         probe.Confidence{Score: 0.2},
 		Trials:             5,
 		Duration:           30 * time.Second,
 		MaxInputAtSuccess:  0,
@@ -102,6 +105,40 @@ func TestTableFormatter_FormatMaxOutputResult(t *testing.T) {
 	}
 }
 
+func TestTableFormatter_FormatMaxInputResult(t *testing.T) {
+	formatter := NewTableFormatter()
+
+	result := &probe.MaxInputResult{
+		Model:          "GLM-4.6",
+		MaxInputTokens: 120000,
+		CombinedLimit:  200000,
+		OutputReserve:  4096,
+		CombinedSource: "validation_error",
+		Trials:         9,
+		Duration:       12 * time.Second,
+		Success:        true,
+	}
+
+	output := formatter.FormatMaxInputResult(result)
+
+	if !strings.Contains(output, "120,000") {
+		t.Error("Output should contain formatted max input tokens")
+	}
+	if !strings.Contains(output, "200,000 tokens (max_tokens=4,096)") {
+		t.Error("Output should contain the combined limit and output reserve")
+	}
+	if !strings.Contains(output, "input limited separately") {
+		t.Error("Output should report that the input limit is enforced separately")
+	}
+
+	result.CombinedSource = "lower_bound"
+	result.CombinedLimit = 124096
+	output = formatter.FormatMaxInputResult(result)
+	if !strings.Contains(output, ">= 124,096") {
+		t.Error("Output should mark a combined limit that was not reached as a lower bound")
+	}
+}
+
 func TestFormatNumber(t *testing.T) {
 	tests := []struct {
 		input    int
@@ -146,7 +183,7 @@ func TestTableFormatter_FormatVerboseHistory(t *testing.T) {
 	trials := []probe.TrialInfo{
 		{TokenCount: 1000, Success: true, Message: "Success"},
 		{TokenCount: 10000, Success: false, Message: "Context length exceeded"},
-		{TokenCount: 8000, Success: true, Message: "Success with smaller input"},
+		{TokenCount: 8000, Success: true, Message: "Success with smaller input", Latency: 1500 * time.Millisecond},
 	}
 
 	output := formatter.FormatVerboseHistory(trials)
@@ -166,6 +203,9 @@ func TestTableFormatter_FormatVerboseHistory(t *testing.T) {
 	if !strings.Contains(output, "Context length exceeded") {
 		t.Error("Output should contain trial message")
 	}
+	if !strings.Contains(output, "1.5s") {
+		t.Error("Output should contain trial latency")
+	}
 }
 
 func TestReverse(t *testing.T) {
@@ -184,4 +224,30 @@ func TestReverse(t *testing.T) {
 			t.Errorf("reverse(%s) = %s; want %s", tt.input, result, tt.expected)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestTableFormatter_FormatConfidenceEvidence(t *testing.T) {
+	formatter := NewTableFormatter()
+
+	confidence := probe.Confidence{
+		Score: 0.85,
+		Evidence: []probe.Evidence{
+			{Kind: probe.EvidenceValidationError, TokenCount: 16384},
+			{Kind: probe.EvidenceUsageConfirmed, TokenCount: 16000},
+			{Kind: probe.EvidenceFinishReasonLength, TokenCount: 16384},
+		},
+	}
+
+	output := formatter.FormatConfidenceEvidence(confidence)
+
+	for _, want := range []string{"0.85 (high)", "validation_error match (limit 16384)", "usage-confirmed success at 16000", "finish_reason=length at 16384"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q, got:\n%s", want, output)
+		}
+	}
+
+	empty := formatter.FormatConfidenceEvidence(probe.Confidence{})
+	if !strings.Contains(empty, "no evidence") {
+		t.Error("Output should note missing evidence")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/armaniacs/llm-info/internal/model"
+	"github.com/armaniacs/llm-info/pkg/config"
 )
 
 func TestRenderTable(t *testing.T) {
@@ -218,7 +219,7 @@ func TestPrintTable(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	printTable(headers, rows, colWidths)
+	printTable(config.TableStyleDefault, headers, rows, colWidths)
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -247,6 +248,39 @@ func TestPrintTable(t *testing.T) {
 	}
 }
 
+func TestPrintTable_Styles(t *testing.T) {
+	headers := []string{"Name", "Age"}
+	rows := [][]string{{"Alice", "30"}, {"Bob", "25"}}
+	colWidths := []int{5, 3}
+
+	tests := []struct {
+		style string
+		want  string
+	}{
+		{config.TableStyleGrid, "+-------+-----+\n| Name  | Age |\n+-------+-----+\n| Alice | 30  |\n| Bob   | 25  |\n+-------+-----+\n"},
+		{config.TableStylePlain, "Name   Age\nAlice  30\nBob    25\n"},
+		{config.TableStyleCompact, "Name  Age\nAlice 30\nBob   25\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			printTable(tt.style, headers, rows, colWidths)
+
+			w.Close()
+			os.Stdout = oldStdout
+
+			var buf bytes.Buffer
+			buf.ReadFrom(r)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("printTable(%s) =\n%s\nwant\n%s", tt.style, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPrintRow(t *testing.T) {
 	row := []string{"Alice", "30"}
 	colWidths := []int{5, 3}
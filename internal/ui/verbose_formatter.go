@@ -5,6 +5,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/armaniacs/llm-info/internal/redact"
 )
 
 // VerboseFormatter provides real-time verbose logging for probe operations
@@ -28,7 +30,7 @@ func (vf *VerboseFormatter) LogInfo(message string) {
 	defer vf.mu.Unlock()
 	vf.clearLastLine()
 	msg := fmt.Sprintf("[INFO] %s", message)
-	fmt.Println(msg)
+	vf.println(msg)
 	vf.lastLineLength = 0
 }
 
@@ -53,7 +55,7 @@ func (vf *VerboseFormatter) LogProgress(current, total int, tokens int) {
 		current, total, tokens, eta)
 
 	// Print with carriage return to update same line
-	fmt.Print("\r" + msg)
+	fmt.Print("\r" + redact.String(msg))
 	vf.lastLineLength = len(msg)
 }
 
@@ -64,7 +66,7 @@ func (vf *VerboseFormatter) LogSuccess(trial int, tokens int, duration time.Dura
 	vf.clearLastLine()
 	msg := fmt.Sprintf("[SUCCESS] Trial %d: Success (prompt_tokens=%d, duration=%v)",
 		trial, tokens, duration.Round(time.Millisecond))
-	fmt.Println(msg)
+	vf.println(msg)
 	vf.lastLineLength = 0
 	vf.trialCount++
 }
@@ -76,7 +78,7 @@ func (vf *VerboseFormatter) LogFailure(trial int, tokens int, reason string) {
 	vf.clearLastLine()
 	msg := fmt.Sprintf("[PROGRESS] Trial %d: Failed (%d tokens) - %s",
 		trial, tokens, reason)
-	fmt.Println(msg)
+	vf.println(msg)
 	vf.lastLineLength = 0
 	vf.trialCount++
 }
@@ -88,7 +90,7 @@ func (vf *VerboseFormatter) LogAPIRequest(method, url string, tokens int, temper
 	vf.clearLastLine()
 	msg := fmt.Sprintf("[INFO] API Request: %s %s (tokens=%d, temperature=%.1f)",
 		method, url, tokens, temperature)
-	fmt.Println(msg)
+	vf.println(msg)
 	vf.lastLineLength = 0
 }
 
@@ -99,7 +101,7 @@ func (vf *VerboseFormatter) LogAPIResponse(status int, promptTokens, completionT
 	vf.clearLastLine()
 	msg := fmt.Sprintf("[INFO] API Response: status=%d (prompt=%d, completion=%d, duration=%v)",
 		status, promptTokens, completionTokens, duration.Round(time.Millisecond))
-	fmt.Println(msg)
+	vf.println(msg)
 	vf.lastLineLength = 0
 }
 
@@ -116,7 +118,7 @@ func (vf *VerboseFormatter) LogSearchStrategy(strategy, reason string, details m
 
 	msg := fmt.Sprintf("[INFO] %s: %s (%s)",
 		strategy, reason, strings.Join(detailStrs, ", "))
-	fmt.Println(msg)
+	vf.println(msg)
 	vf.lastLineLength = 0
 }
 
@@ -126,7 +128,7 @@ func (vf *VerboseFormatter) LogError(err error, context string) {
 	defer vf.mu.Unlock()
 	vf.clearLastLine()
 	msg := fmt.Sprintf("[ERROR] %s: %v", context, err)
-	fmt.Println(msg)
+	vf.println(msg)
 	vf.lastLineLength = 0
 }
 
@@ -137,10 +139,15 @@ func (vf *VerboseFormatter) LogCompletion(strategy string, finalEstimate int, to
 	vf.clearLastLine()
 	msg := fmt.Sprintf("[INFO] Converged! Final estimate: %d tokens (±%d) using %s",
 		finalEstimate, tolerance, strategy)
-	fmt.Println(msg)
+	vf.println(msg)
 	vf.lastLineLength = 0
 }
 
+// println prints a line with API keys and other secrets removed
+func (vf *VerboseFormatter) println(msg string) {
+	fmt.Println(redact.String(msg))
+}
+
 // clearLastLine clears the last line if it was a progress update
 func (vf *VerboseFormatter) clearLastLine() {
 	if vf.lastLineLength > 0 {
@@ -0,0 +1,351 @@
+// Package update はGitHubリリースからllm-infoのバイナリを取得し、検証して置き換える
+//
+// リリースには各プラットフォームのバイナリ（AssetNameの形式）と、
+// sha256sum形式のchecksums.txtを添付する。PublicKeyが設定されている場合は
+// checksums.txtのEd25519署名（checksums.txt.sig）も検証する
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAPIURL はGitHub APIのURL
+	DefaultAPIURL = "https://api.github.com"
+	// DefaultRepo はリリースを取得するリポジトリ
+	DefaultRepo = "armaniacs/llm-info"
+	// ChecksumsAsset はチェックサムファイルのアセット名
+	ChecksumsAsset = "checksums.txt"
+	// SignatureAsset はチェックサムファイルの署名のアセット名
+	SignatureAsset = "checksums.txt.sig"
+
+	// maxAssetSize はダウンロードするアセットの最大サイズ
+	maxAssetSize = 200 << 20
+)
+
+// PublicKey はchecksums.txtの署名を検証するEd25519公開鍵（base64）
+// リリースビルドで -ldflags "-X github.com/armaniacs/llm-info/internal/update.PublicKey=..." により設定する
+var PublicKey = ""
+
+// Asset はリリースに添付されたファイル
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+}
+
+// Release はGitHubリリースの情報
+type Release struct {
+	TagName    string    `json:"tag_name"`
+	Name       string    `json:"name"`
+	HTMLURL    string    `json:"html_url"`
+	Prerelease bool      `json:"prerelease"`
+	Published  time.Time `json:"published_at"`
+	Assets     []Asset   `json:"assets"`
+}
+
+// Version はタグ名から先頭のvを除いたバージョンを返す
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// Asset は名前が一致するアセットを返す
+func (r *Release) Asset(name string) (*Asset, bool) {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i], true
+		}
+	}
+	return nil, false
+}
+
+// AssetName はプラットフォームごとのバイナリのアセット名を返す
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("llm-info_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Updater はリリースの確認とバイナリの取得を行う
+type Updater struct {
+	APIURL    string
+	Repo      string
+	Token     string // GitHub APIのレート制限を避けるためのトークン（任意）
+	PublicKey string // 空の場合は署名を検証しない
+	client    *http.Client
+}
+
+// New は新しいUpdaterを作成する
+func New(timeout time.Duration) *Updater {
+	return &Updater{
+		APIURL:    DefaultAPIURL,
+		Repo:      DefaultRepo,
+		PublicKey: PublicKey,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+// Latest は最新のリリースを取得する
+func (u *Updater) Latest() (*Release, error) {
+	return u.fetchRelease(fmt.Sprintf("/repos/%s/releases/latest", u.Repo))
+}
+
+// Tag は指定したタグのリリースを取得する
+// タグにvが付いていない場合はv付きのタグも試す
+func (u *Updater) Tag(tag string) (*Release, error) {
+	release, err := u.fetchRelease(fmt.Sprintf("/repos/%s/releases/tags/%s", u.Repo, tag))
+	if err != nil && !strings.HasPrefix(tag, "v") {
+		if r, vErr := u.fetchRelease(fmt.Sprintf("/repos/%s/releases/tags/v%s", u.Repo, tag)); vErr == nil {
+			return r, nil
+		}
+	}
+	return release, err
+}
+
+// fetchRelease はGitHub APIからリリース情報を取得する
+func (u *Updater) fetchRelease(path string) (*Release, error) {
+	body, err := u.get(strings.TrimSuffix(u.APIURL, "/")+path, "application/vnd.github+json")
+	if err != nil {
+		return nil, err
+	}
+
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, fmt.Errorf("failed to decode release information: %w", err)
+	}
+	if release.TagName == "" {
+		return nil, fmt.Errorf("release information has no tag name")
+	}
+	return &release, nil
+}
+
+// Download はプラットフォームに合ったバイナリを取得し、チェックサムと署名を検証する
+func (u *Updater) Download(release *Release, goos, goarch string) ([]byte, error) {
+	name := AssetName(goos, goarch)
+	asset, ok := release.Asset(name)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no binary for %s/%s (expected asset %s)", release.TagName, goos, goarch, name)
+	}
+	checksumsAsset, ok := release.Asset(ChecksumsAsset)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s; refusing to install an unverified binary", release.TagName, ChecksumsAsset)
+	}
+
+	checksums, err := u.get(checksumsAsset.URL, "application/octet-stream")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", ChecksumsAsset, err)
+	}
+
+	if u.PublicKey != "" {
+		sigAsset, ok := release.Asset(SignatureAsset)
+		if !ok {
+			return nil, fmt.Errorf("release %s has no %s but signature verification is required", release.TagName, SignatureAsset)
+		}
+		sig, err := u.get(sigAsset.URL, "application/octet-stream")
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", SignatureAsset, err)
+		}
+		if err := VerifySignature(u.PublicKey, checksums, sig); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := u.get(asset.URL, "application/octet-stream")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	if err := VerifyChecksum(checksums, name, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// get はURLの内容を取得する
+func (u *Updater) get(url, accept string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", "llm-info-self-update")
+	// 別ホストへのリダイレクト（アセットの保存先）ではnet/httpがAuthorizationを送らない
+	if u.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+u.Token)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request to %s failed with status %d", url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAssetSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(body) > maxAssetSize {
+		return nil, fmt.Errorf("response from %s exceeds %d bytes", url, maxAssetSize)
+	}
+	return body, nil
+}
+
+// VerifyChecksum はsha256sum形式のチェックサム一覧とデータのSHA-256を照合する
+func VerifyChecksum(checksums []byte, name string, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// sha256sumのバイナリモードではファイル名の先頭に*が付く
+		if strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+
+		sum := sha256.Sum256(data)
+		actual := hex.EncodeToString(sum[:])
+		if !strings.EqualFold(fields[0], actual) {
+			return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, fields[0], actual)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s has no checksum for %s", ChecksumsAsset, name)
+}
+
+// VerifySignature はメッセージのEd25519署名を検証する
+// 公開鍵と署名はbase64、署名は64バイトの生データも受け付ける
+func VerifySignature(publicKey string, message, sig []byte) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid update public key")
+	}
+
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil {
+			return fmt.Errorf("invalid signature encoding: %w", err)
+		}
+		sig = decoded
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(key), message, sig) {
+		return fmt.Errorf("signature verification of %s failed", ChecksumsAsset)
+	}
+	return nil
+}
+
+// CompareVersions はセマンティックバージョンを比較し、a<bなら負、a==bなら0、a>bなら正を返す
+// プレリリース（1.2.0-rc1など）は同じバージョンの正式リリースより小さいとみなす
+func CompareVersions(a, b string) int {
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+
+	for i := 0; i < len(aCore) || i < len(bCore); i++ {
+		var x, y int
+		if i < len(aCore) {
+			x = aCore[i]
+		}
+		if i < len(bCore) {
+			y = bCore[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	default:
+		return strings.Compare(aPre, bPre)
+	}
+}
+
+// splitVersion はバージョンを数値部分とプレリリース部分に分ける
+func splitVersion(version string) ([]int, string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexByte(version, '+'); i >= 0 {
+		version = version[:i]
+	}
+	pre := ""
+	if i := strings.IndexByte(version, '-'); i >= 0 {
+		version, pre = version[:i], version[i+1:]
+	}
+
+	var core []int
+	for _, part := range strings.Split(version, ".") {
+		n, _ := strconv.Atoi(part)
+		core = append(core, n)
+	}
+	return core, pre
+}
+
+// Replace は実行ファイルを新しいバイナリに置き換える
+// 同じディレクトリに一時ファイルを書き込んでからリネームするため、途中で失敗しても元のファイルは壊れない
+func Replace(target string, data []byte) error {
+	info, err := os.Stat(target)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", target, err)
+	}
+
+	dir := filepath.Dir(target)
+	tmp, err := os.CreateTemp(dir, ".llm-info-update-*")
+	if err != nil {
+		return fmt.Errorf("cannot write to %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()|0111); err != nil {
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+
+	// Windowsでは実行中のファイルを上書きできないため、先に退避する
+	if runtime.GOOS == "windows" {
+		old := target + ".old"
+		os.Remove(old)
+		if err := os.Rename(target, old); err != nil {
+			return fmt.Errorf("failed to move current binary aside: %w", err)
+		}
+	}
+
+	if err := os.Rename(tmpPath, target); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", target, err)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package update
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// releaseServer はGitHub APIとリリースアセットを模したテストサーバー
+func releaseServer(t *testing.T, tag string, assets map[string][]byte) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	release := Release{TagName: tag, HTMLURL: "https://github.com/armaniacs/llm-info/releases/" + tag}
+	for name, data := range assets {
+		name, data := name, data
+		release.Assets = append(release.Assets, Asset{Name: name, URL: server.URL + "/download/" + name, Size: int64(len(data))})
+		mux.HandleFunc("/download/"+name, func(w http.ResponseWriter, r *http.Request) {
+			w.Write(data)
+		})
+	}
+
+	releaseHandler := func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	}
+	mux.HandleFunc("/repos/armaniacs/llm-info/releases/latest", releaseHandler)
+	mux.HandleFunc("/repos/armaniacs/llm-info/releases/tags/"+tag, releaseHandler)
+	return server
+}
+
+func checksumLine(name string, data []byte) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), name)
+}
+
+func newTestUpdater(server *httptest.Server) *Updater {
+	u := New(5 * time.Second)
+	u.APIURL = server.URL
+	u.PublicKey = ""
+	return u
+}
+
+func TestUpdater_LatestAndTag(t *testing.T) {
+	server := releaseServer(t, "v1.2.0", nil)
+	u := newTestUpdater(server)
+
+	release, err := u.Latest()
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if release.Version() != "1.2.0" {
+		t.Errorf("Version() = %q, want 1.2.0", release.Version())
+	}
+
+	// vなしのタグ指定でもv付きのタグを見つける
+	release, err = u.Tag("1.2.0")
+	if err != nil {
+		t.Fatalf("Tag() error = %v", err)
+	}
+	if release.TagName != "v1.2.0" {
+		t.Errorf("TagName = %q, want v1.2.0", release.TagName)
+	}
+
+	if _, err := u.Tag("v9.9.9"); err == nil {
+		t.Error("Tag() expected error for a missing release")
+	}
+}
+
+func TestUpdater_Download(t *testing.T) {
+	name := AssetName("linux", "amd64")
+	binary := []byte("new llm-info binary")
+	checksums := []byte(checksumLine("llm-info_darwin_arm64", []byte("other")) + checksumLine(name, binary))
+
+	t.Run("valid checksum", func(t *testing.T) {
+		server := releaseServer(t, "v1.2.0", map[string][]byte{name: binary, ChecksumsAsset: checksums})
+		u := newTestUpdater(server)
+		release, _ := u.Latest()
+
+		data, err := u.Download(release, "linux", "amd64")
+		if err != nil {
+			t.Fatalf("Download() error = %v", err)
+		}
+		if string(data) != string(binary) {
+			t.Errorf("Download() = %q, want %q", data, binary)
+		}
+	})
+
+	t.Run("tampered binary", func(t *testing.T) {
+		server := releaseServer(t, "v1.2.0", map[string][]byte{name: []byte("tampered"), ChecksumsAsset: checksums})
+		u := newTestUpdater(server)
+		release, _ := u.Latest()
+
+		_, err := u.Download(release, "linux", "amd64")
+		if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+			t.Errorf("Download() error = %v, want checksum mismatch", err)
+		}
+	})
+
+	t.Run("missing checksums", func(t *testing.T) {
+		server := releaseServer(t, "v1.2.0", map[string][]byte{name: binary})
+		u := newTestUpdater(server)
+		release, _ := u.Latest()
+
+		if _, err := u.Download(release, "linux", "amd64"); err == nil {
+			t.Error("Download() should refuse a release without checksums")
+		}
+	})
+
+	t.Run("missing platform", func(t *testing.T) {
+		server := releaseServer(t, "v1.2.0", map[string][]byte{name: binary, ChecksumsAsset: checksums})
+		u := newTestUpdater(server)
+		release, _ := u.Latest()
+
+		_, err := u.Download(release, "plan9", "386")
+		if err == nil || !strings.Contains(err.Error(), "llm-info_plan9_386") {
+			t.Errorf("Download() error = %v, want missing asset error", err)
+		}
+	})
+}
+
+func TestUpdater_DownloadSignature(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	name := AssetName("linux", "amd64")
+	binary := []byte("new llm-info binary")
+	checksums := []byte(checksumLine(name, binary))
+
+	tests := []struct {
+		name      string
+		signature []byte
+		wantErr   bool
+	}{
+		{"base64 signature", []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, checksums)) + "\n"), false},
+		{"raw signature", ed25519.Sign(privateKey, checksums), false},
+		{"wrong key", []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(otherKey, checksums))), true},
+		{"missing signature", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assets := map[string][]byte{name: binary, ChecksumsAsset: checksums}
+			if tt.signature != nil {
+				assets[SignatureAsset] = tt.signature
+			}
+			server := releaseServer(t, "v1.2.0", assets)
+			u := newTestUpdater(server)
+			u.PublicKey = base64.StdEncoding.EncodeToString(publicKey)
+			release, _ := u.Latest()
+
+			_, err := u.Download(release, "linux", "amd64")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Download() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("binary")
+	checksums := []byte(checksumLine("*llm-info_linux_amd64", data))
+
+	if err := VerifyChecksum(checksums, "llm-info_linux_amd64", data); err != nil {
+		t.Errorf("VerifyChecksum() with binary-mode entry error = %v", err)
+	}
+	if err := VerifyChecksum(checksums, "llm-info_linux_arm64", data); err == nil {
+		t.Error("VerifyChecksum() should fail when the asset is not listed")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"v1.0.0", "1.0.0", 0},
+		{"1.0.1", "1.0.0", 1},
+		{"1.2.0", "1.10.0", -1},
+		{"2.0", "1.9.9", 1},
+		{"1.0.0-rc1", "1.0.0", -1},
+		{"1.0.0", "1.0.0-rc1", 1},
+		{"1.0.0-rc2", "1.0.0-rc1", 1},
+		{"1.0.0+build5", "1.0.0", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); sign(got) != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+func TestReplace(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "llm-info")
+	if err := os.WriteFile(target, []byte("old"), 0750); err != nil {
+		t.Fatalf("failed to write binary: %v", err)
+	}
+
+	if err := Replace(target, []byte("new")); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(target)
+	if string(data) != "new" {
+		t.Errorf("binary content = %q, want new", data)
+	}
+	info, _ := os.Stat(target)
+	if info.Mode().Perm() != 0750|0111 {
+		t.Errorf("mode = %v, want %v", info.Mode().Perm(), os.FileMode(0750|0111))
+	}
+
+	// 一時ファイルが残っていないこと
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the binary in %s, got %d entries", dir, len(entries))
+	}
+
+	if err := Replace(filepath.Join(dir, "missing"), []byte("new")); err == nil {
+		t.Error("Replace() should fail when the target does not exist")
+	}
+}
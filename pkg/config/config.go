@@ -6,25 +6,117 @@ import (
 
 // Config はアプリケーション設定全体を表す
 type Config struct {
-	Gateways       []Gateway `yaml:"gateways"`
-	DefaultGateway string    `yaml:"default_gateway"`
-	Global         Global    `yaml:"global"`
+	ConfigURL      string                     `yaml:"config_url"` // 組織の共有設定のURL（ローカルの設定の下に重ねる）
+	Gateways       []Gateway                  `yaml:"gateways"`
+	DefaultGateway string                     `yaml:"default_gateway"`
+	Global         Global                     `yaml:"global"`
+	Notifications  NotificationConfig         `yaml:"notifications"`
+	Daemon         DaemonConfig               `yaml:"daemon"`
+	Storage        StorageConfig              `yaml:"storage"`
+	Normalization  NormalizationConfig        `yaml:"normalization"`
+	Stats          StatsConfig                `yaml:"stats"`
+	Providers      ProvidersConfig            `yaml:"providers"`
+	Hooks          HooksConfig                `yaml:"hooks"`
+	Formatters     map[string]FormatterConfig `yaml:"formatters"` // --formatで選べる外部フォーマッター（名前 → 設定）
+	Filters        map[string]string          `yaml:"filters"`    // --filter @名前 で使う名前付きのフィルタ（名前 → フィルタ条件）
+	Pinned         []string                   `yaml:"pinned"`     // llm-info pinでピン留めしたモデルID
+	Vault          VaultConfig                `yaml:"vault"`      // api_key_vaultを読み込むHashiCorp Vaultの設定
+	ExitCodes      ExitCodes                  `yaml:"exit_codes"` // エラーの重大度ごとの終了コード（他のツールに組み込む場合の規約に合わせる）
 }
 
 // Gateway は個別のゲートウェイ設定を表す
 type Gateway struct {
-	Name    string        `yaml:"name"`
-	URL     string        `yaml:"url"`
-	APIKey  string        `yaml:"api_key"`
-	Timeout time.Duration `yaml:"timeout"`
+	Name           string        `yaml:"name"`
+	URL            string        `yaml:"url"`
+	APIKey         string        `yaml:"api_key"`
+	APIKeyVault    string        `yaml:"api_key_vault,omitempty"` // APIキーを読み込むVaultのシークレット（例: secret/data/llm/prod#key、api_keyが優先）
+	Timeout        time.Duration `yaml:"timeout"`
+	Timeouts       Timeouts      `yaml:"timeouts"`
+	Type           string        `yaml:"type,omitempty"`            // ゲートウェイの種類（litellm: LiteLLM固有のエンドポイントも利用）
+	DefaultModel   string        `yaml:"default_model,omitempty"`   // probe/chatで--modelを省略したときに使うモデル
+	MaxBodySize    string        `yaml:"max_body_size,omitempty"`   // 探索リクエストのボディの上限（例: 10MB、空は無制限）
+	ProbeEndpoint  string        `yaml:"probe_endpoint,omitempty"`  // 探索リクエストを送るエンドポイント（chat, completions, responses, auto）
+	Proxy          string        `yaml:"proxy,omitempty"`           // 接続に使うプロキシ（例: socks5://localhost:1080、directは使わない、空は環境変数）
+	MaxConcurrency int           `yaml:"max_concurrency,omitempty"` // このゲートウェイに同時に送るリクエストの上限（0は無制限）
+	RateLimit      string        `yaml:"rate_limit,omitempty"`      // このゲートウェイに送るリクエストのレートの上限（例: 60/m、5/s、空は無制限）
+	MaxRetries     int           `yaml:"max_retries,omitempty"`     // 429（Too Many Requests）を返されたリクエストを待ってから再送する回数（0は再送しない）
+	Failover       []string      `yaml:"failover,omitempty"`        // 停止しているときに順に代わりに使うゲートウェイの名前（一覧取得とchat）
 }
 
+// ゲートウェイの種類
+const (
+	GatewayTypeLiteLLM = "litellm"
+)
+
+// ValidGatewayTypes はtypeに指定できる値（空は汎用のOpenAI互換ゲートウェイ）
+var ValidGatewayTypes = []string{GatewayTypeLiteLLM}
+
+// 探索リクエストを送るエンドポイント（probe_endpoint、--endpoint）
+const (
+	ProbeEndpointChat        = "chat"        // /v1/chat/completions（既定）
+	ProbeEndpointCompletions = "completions" // /v1/completions（チャット形式に対応しない旧来のモデル）
+	ProbeEndpointResponses   = "responses"   // /v1/responses（OpenAIのResponses API）
+	ProbeEndpointAuto        = "auto"        // チャット形式に対応していなければ/v1/completionsに切り替える
+)
+
+// ValidProbeEndpoints はprobe_endpointと--endpointに指定できる値
+var ValidProbeEndpoints = []string{ProbeEndpointChat, ProbeEndpointCompletions, ProbeEndpointResponses, ProbeEndpointAuto}
+
+// ProxyDirect はproxyに指定すると環境変数のプロキシも使わずに直接接続する
+const ProxyDirect = "direct"
+
+// ValidProxySchemes はproxyのURLに指定できるスキーム（socks5hはプロキシ側で名前解決する）
+var ValidProxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
 // Global はグローバル設定を表す
 type Global struct {
 	Timeout      time.Duration `yaml:"timeout"`
+	Timeouts     Timeouts      `yaml:"timeouts"`
 	OutputFormat string        `yaml:"output_format"`
 	SortBy       string        `yaml:"sort_by"`
+	NumberFormat string        `yaml:"number_format"` // トークン数とコストの表記（raw, thousands, si）
+	TableStyle   string        `yaml:"table_style"`   // テーブルの罫線の種類（default, grid, plain, compact）
+	ReadOnly     bool          `yaml:"read_only"`     // 探索やchatなど補完を生成するリクエストを送らない
 	Cost         CostConfig    `yaml:"cost"`
+
+	// 見積もりコスト（USD）がこれを超える探索は実行前に確認する（0は確認しない）
+	SpendConfirmThreshold float64 `yaml:"spend_confirm_threshold"`
+}
+
+// Timeouts はHTTP通信の段階ごとのタイムアウトを表す
+// 0の項目は未設定として扱い、Goの標準値（またはtimeout）を使用する
+type Timeouts struct {
+	Dial           time.Duration `yaml:"dial,omitempty"`            // TCP接続の確立
+	TLSHandshake   time.Duration `yaml:"tls_handshake,omitempty"`   // TLSハンドシェイク
+	ResponseHeader time.Duration `yaml:"response_header,omitempty"` // レスポンスヘッダーの受信（一覧取得のみ）
+	Total          time.Duration `yaml:"total,omitempty"`           // リクエスト全体（timeoutと同じ、指定時はこちらを優先）
+	Probe          time.Duration `yaml:"probe,omitempty"`           // 探索リクエスト全体（未設定時はprobeの--timeout）
+}
+
+// Merge は未設定の項目をfallbackの値で補ったTimeoutsを返す
+func (t Timeouts) Merge(fallback Timeouts) Timeouts {
+	if t.Dial == 0 {
+		t.Dial = fallback.Dial
+	}
+	if t.TLSHandshake == 0 {
+		t.TLSHandshake = fallback.TLSHandshake
+	}
+	if t.ResponseHeader == 0 {
+		t.ResponseHeader = fallback.ResponseHeader
+	}
+	if t.Total == 0 {
+		t.Total = fallback.Total
+	}
+	if t.Probe == 0 {
+		t.Probe = fallback.Probe
+	}
+	return t
+}
+
+// Connection は接続段階のタイムアウト（DialとTLSハンドシェイク）のみを返す
+// 応答まで時間のかかる探索リクエストではレスポンスヘッダーのタイムアウトを適用しない
+func (t Timeouts) Connection() Timeouts {
+	return Timeouts{Dial: t.Dial, TLSHandshake: t.TLSHandshake}
 }
 
 // ConfigSource は設定ソースの種類を表す
@@ -39,10 +131,17 @@ const (
 
 // GatewayConfig は実行時に使用するゲートウェイ設定を表す
 type GatewayConfig struct {
-	Name    string        `yaml:"name"`
-	URL     string        `yaml:"url"`
-	APIKey  string        `yaml:"api_key"`
-	Timeout time.Duration `yaml:"timeout"`
+	Name          string        `yaml:"name"`
+	URL           string        `yaml:"url"`
+	APIKey        string        `yaml:"api_key"`
+	Timeout       time.Duration `yaml:"timeout"`
+	Timeouts      Timeouts      `yaml:"timeouts,omitempty"`
+	Type          string        `yaml:"type,omitempty"`
+	DefaultModel  string        `yaml:"default_model,omitempty"`
+	MaxBodySize   string        `yaml:"max_body_size,omitempty"`
+	ProbeEndpoint string        `yaml:"probe_endpoint,omitempty"`
+	Proxy         string        `yaml:"proxy,omitempty"`
+	Failover      []string      `yaml:"failover,omitempty"`
 
 	// ソース追跡（JSON/YAML出力から除外）
 	URLSource     ConfigSource `json:"-" yaml:"-"`
@@ -65,6 +164,20 @@ func (g *GatewayConfig) GetTimeoutSource() ConfigSource {
 	return g.TimeoutSource
 }
 
+// IsLiteLLM はLiteLLM固有のエンドポイントを利用するゲートウェイかを返す
+func (g *GatewayConfig) IsLiteLLM() bool {
+	return g.Type == GatewayTypeLiteLLM
+}
+
+// ProbeTimeout は探索リクエストに使うタイムアウトを返す
+// timeouts.probeが未設定の場合はfallbackを返す
+func (g *GatewayConfig) ProbeTimeout(fallback time.Duration) time.Duration {
+	if g.Timeouts.Probe > 0 {
+		return g.Timeouts.Probe
+	}
+	return fallback
+}
+
 // FileConfig は設定ファイルの構造体です（後方互換性のため）
 type FileConfig struct {
 	Gateways       []GatewayConfig `yaml:"gateways"`
@@ -103,12 +216,200 @@ type Pricing struct {
 	OutputPricePer1K float64 `yaml:"output_price_per_1k"`  // e.g., 0.01 for gpt-4
 }
 
+// NotificationConfig はWebhook通知の設定です
+type NotificationConfig struct {
+	WebhookURL string        `yaml:"webhook_url"`
+	Format     string        `yaml:"format"`  // json または slack（デフォルト: json）
+	Events     []string      `yaml:"events"`  // 空の場合はすべてのイベントを通知
+	Timeout    time.Duration `yaml:"timeout"` // Default: 10s
+}
+
+// VaultConfig はHashiCorp Vaultからシークレットを読み込む設定です
+// トークンやSecret IDはディスクに置かないよう、環境変数から読み込みます
+type VaultConfig struct {
+	Address      string `yaml:"address"`       // Default: $VAULT_ADDR
+	Namespace    string `yaml:"namespace"`     // Vault Enterpriseの名前空間（Default: $VAULT_NAMESPACE）
+	Auth         string `yaml:"auth"`          // token（Default: $VAULT_TOKEN、~/.vault-token）またはapprole
+	RoleID       string `yaml:"role_id"`       // approleのRole ID（Default: $VAULT_ROLE_ID、Secret IDは$VAULT_SECRET_ID）
+	AppRoleMount string `yaml:"approle_mount"` // approleの認証のマウント先（Default: approle）
+}
+
+// 既定の終了コード（エラーの重大度ごと）
+const (
+	ExitCodeWarning = 1 // 引数の誤りなど
+	ExitCodeError   = 2 // 接続やAPIのエラーなど
+	ExitCodeFatal   = 3 // パニックなど
+)
+
+// ExitCodes はエラーの重大度ごとの終了コードを表す（未設定の項目は既定値を使う）
+// llm-infoを他のツールやスクリプトに組み込むとき、呼び出し側の規約に合わせるために使う
+type ExitCodes struct {
+	Warning *int `yaml:"warning,omitempty"` // Default: 1
+	Error   *int `yaml:"error,omitempty"`   // Default: 2
+	Fatal   *int `yaml:"fatal,omitempty"`   // Default: 3
+	Max     *int `yaml:"max,omitempty"`     // 終了コードの上限（例: 1でcronから呼ぶ場合に2以上を返さない）
+}
+
+// Resolve は既定の終了コード（1: warning、2: error、3: fatal）を設定に従って置き換え、上限を適用する
+// 0（成功）と既定の範囲外の終了コードは上限の適用だけを行う
+func (e ExitCodes) Resolve(code int) int {
+	var override *int
+	switch code {
+	case ExitCodeWarning:
+		override = e.Warning
+	case ExitCodeError:
+		override = e.Error
+	case ExitCodeFatal:
+		override = e.Fatal
+	}
+	if override != nil {
+		code = *override
+	}
+	return e.Limit(code)
+}
+
+// Limit は終了コードに上限を適用する（失敗を示す1以上の終了コードは上限を超えない）
+func (e ExitCodes) Limit(code int) int {
+	if e.Max != nil && code > *e.Max {
+		return *e.Max
+	}
+	return code
+}
+
+// テーブルの罫線の種類
+const (
+	TableStyleDefault = "default" // ヘッダーの下に区切り線（従来の表示）
+	TableStyleGrid    = "grid"    // ASCII文字の罫線で囲む
+	TableStylePlain   = "plain"   // 罫線なしで列をそろえる
+	TableStyleCompact = "compact" // 罫線なしで列の間を1文字に詰める
+)
+
+// ValidTableStyles はglobal.table_styleと--format table=STYLEに指定できる値
+var ValidTableStyles = []string{TableStyleDefault, TableStyleGrid, TableStylePlain, TableStyleCompact}
+
+// Vaultの認証方式
+const (
+	VaultAuthToken   = "token"
+	VaultAuthAppRole = "approle"
+)
+
+// ValidVaultAuths はvault.authに指定できる値
+var ValidVaultAuths = []string{VaultAuthToken, VaultAuthAppRole}
+
+// HooksConfig は処理の完了時に実行する外部コマンドの設定です
+// コマンドは標準入力で結果のJSONを受け取ります
+type HooksConfig struct {
+	PostFetch string        `yaml:"post_fetch"` // モデル一覧の取得後に実行するコマンド
+	PostProbe string        `yaml:"post_probe"` // 探索の完了後に実行するコマンド
+	Timeout   time.Duration `yaml:"timeout"`    // Default: 30s
+}
+
+// IsZero はフックが1つも設定されていないかを返します
+func (h HooksConfig) IsZero() bool {
+	return h.PostFetch == "" && h.PostProbe == ""
+}
+
+// FormatterConfig は--formatで選べる外部フォーマッターの設定です
+// コマンドは標準入力で出力内容のJSONを受け取り、標準出力に整形結果を書き出します
+type FormatterConfig struct {
+	Command string        `yaml:"command"`
+	Timeout time.Duration `yaml:"timeout"` // Default: 30s
+}
+
+// StorageConfig は探索結果とログの保存設定です
+type StorageConfig struct {
+	ResultDir string          `yaml:"result_dir"` // Default: ~/.local/state/llm-info/estimates
+	LogDir    string          `yaml:"log_dir"`    // Default: ~/.local/state/llm-info/log
+	LogFormat string          `yaml:"log_format"` // json（Default）またはjsonl
+	CacheDir  string          `yaml:"cache_dir"`  // Default: ~/.cache/llm-info
+	LockDir   string          `yaml:"lock_dir"`   // Default: ~/.local/state/llm-info/locks
+	NotesFile string          `yaml:"notes_file"` // Default: ~/.config/llm-info/notes.json
+	Compress  bool            `yaml:"compress"`   // 探索結果をgzip圧縮して保存
+	Retention RetentionConfig `yaml:"retention"`
+	Remote    RemoteConfig    `yaml:"remote"` // チームで共有するリモート保存先
+}
+
+// RemoteConfig は探索結果を共有するS3互換ストレージの設定です
+// GCSはHMACキーを発行してS3互換APIのエンドポイントを指定します
+type RemoteConfig struct {
+	Endpoint        string `yaml:"endpoint"`          // 例: https://storage.googleapis.com（Default: AWS S3のリージョンエンドポイント）
+	Bucket          string `yaml:"bucket"`            // 指定した場合のみ有効
+	Prefix          string `yaml:"prefix"`            // 例: llm-info/estimates
+	Region          string `yaml:"region"`            // Default: us-east-1
+	AccessKeyID     string `yaml:"access_key_id"`     // 未指定時は AWS_ACCESS_KEY_ID
+	SecretAccessKey string `yaml:"secret_access_key"` // 未指定時は AWS_SECRET_ACCESS_KEY
+	PathStyle       bool   `yaml:"path_style"`        // バケットをパスで指定する（MinIOなど）
+	Lock            bool   `yaml:"lock"`              // 探索中のゲートウェイのロックもリモートに置く
+}
+
+// Enabled はリモート保存先が設定されているかを返す
+func (r RemoteConfig) Enabled() bool {
+	return r.Bucket != ""
+}
+
+// RetentionConfig は探索結果とログの保持ポリシーです（0または空は無制限）
+type RetentionConfig struct {
+	MaxFiles     int           `yaml:"max_files"`      // ディレクトリごとの最大ファイル数
+	MaxAge       time.Duration `yaml:"max_age"`        // 例: 720h
+	MaxTotalSize string        `yaml:"max_total_size"` // 例: 100MB
+}
+
+// StatsConfig はローカルの利用統計の設定です（オプトイン、ネットワーク送信なし）
+type StatsConfig struct {
+	Enabled bool   `yaml:"enabled"` // Default: false
+	File    string `yaml:"file"`    // Default: ~/.local/state/llm-info/stats.json
+}
+
+// ProvidersConfig は探索結果を保存するプロバイダー名の判定設定です
+type ProvidersConfig struct {
+	Hosts   map[string]string `yaml:"hosts"`   // ゲートウェイのホスト（*を使用可）→ プロバイダー名
+	Aliases map[string]string `yaml:"aliases"` // 検出した名前（litellm_providerなど）→ プロバイダー名
+}
+
+// NormalizationConfig はモデルIDの正規化設定です
+type NormalizationConfig struct {
+	Dedupe          bool                `yaml:"dedupe"`           // 正規化後に同一となるモデルをまとめる
+	DisableDefaults bool                `yaml:"disable_defaults"` // 組み込みルールを無効にする
+	Rules           []NormalizationRule `yaml:"rules"`            // 組み込みルールの後に適用
+	Aliases         map[string]string   `yaml:"aliases"`          // モデルID → 正規化後のID
+}
+
+// NormalizationRule は正規表現による置換ルールです
+type NormalizationRule struct {
+	Pattern string `yaml:"pattern"`
+	Replace string `yaml:"replace"`
+}
+
+// DaemonConfig は定期探索デーモンの設定です
+type DaemonConfig struct {
+	Schedule  string        `yaml:"schedule"`   // cron式（例: "0 3 * * *"）
+	ResultDir string        `yaml:"result_dir"` // Default: storage.result_dir
+	Retention time.Duration `yaml:"retention"`  // storage.retention.max_ageを上書き
+	Jobs      []ProbeJob    `yaml:"jobs"`
+}
+
+// ProbeJob はデーモンが実行する探索ジョブです
+type ProbeJob struct {
+	Model    string `yaml:"model"`
+	Gateway  string `yaml:"gateway"`  // 空の場合はデフォルトゲートウェイ
+	Probe    string `yaml:"probe"`    // all, context, max_output（デフォルト: all）
+	Schedule string `yaml:"schedule"` // ジョブ固有のcron式（省略時はdaemon.schedule）
+}
+
 // AppConfig はアプリケーション設定です
 type AppConfig struct {
 	// 現在の設定
-	BaseURL string
-	APIKey  string
-	Timeout time.Duration
+	BaseURL  string
+	APIKey   string
+	Timeout  time.Duration
+	Timeouts Timeouts // 接続段階ごとのタイムアウト
+
+	// 探索リクエストのボディの上限（バイト数、0は無制限）
+	MaxBodySize int64
+	// 探索リクエストのエンドポイント（chat, completions, responses, auto、空はchat）
+	ProbeEndpoint string
+	// 接続に使うプロキシ（空は環境変数のHTTP_PROXYなど、directは使わない）
+	Proxy string
 
 	// 設定ファイル関連
 	ConfigFile string